@@ -16,8 +16,19 @@ func main() {
 		app.UserHandler,
 		*app.SessionService,
 		*app.AuthService,
+		app.APITokenService,
 		app.TargetHandler,
 		app.NotifierHandler,
+		app.IncidentHandler,
+		app.PolicyHandler,
+		app.ScheduleHandler,
+		app.WindowHandler,
+		app.StatusPageHandler,
+		app.BadgeHandler,
+		app.SLAHandler,
+		app.SlackHandler,
+		app.APIHandler,
+		app.MetricsHandler,
 	)
 
 	// Start server
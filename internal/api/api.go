@@ -0,0 +1,141 @@
+// Package api serves the versioned JSON API under /api/v1. It exposes the
+// same services as the HTML dashboard, with JSON bodies instead of forms and
+// error objects instead of flash messages.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
+	monitorService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	notificationService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+)
+
+// maxBodySize bounds the size of a request body
+const maxBodySize = 1 << 20
+
+// Handler serves the JSON API
+type Handler struct {
+	targetService   monitorService.TargetServiceInterface
+	notifierService NotifierService
+	incidentService IncidentService
+}
+
+func NewHandler(targetService monitorService.TargetServiceInterface, notifierService NotifierService, incidentService IncidentService) *Handler {
+	return &Handler{
+		targetService:   targetService,
+		notifierService: notifierService,
+		incidentService: incidentService,
+	}
+}
+
+// Error codes of the error object
+const (
+	CodeBadRequest      = "bad_request"
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeInvalidInput    = "invalid_input"
+	CodeUnsupportedType = "unsupported_media_type"
+	CodeInternal        = "internal_error"
+)
+
+// ErrorResponse is the body of every failed request
+type ErrorResponse struct {
+	Error ErrorObject `json:"error"`
+}
+
+// ErrorObject describes what went wrong. Code is stable and meant for
+// programs, Message is meant for people.
+type ErrorObject struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorStatus maps the errors of the services to a status and an error code
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, monitorService.ErrUnauthorized),
+		errors.Is(err, notificationService.ErrUnauthorized),
+		errors.Is(err, incidentService.ErrUnauthorized):
+		return http.StatusForbidden, CodeForbidden
+	case errors.Is(err, monitorService.ErrTargetNotFound),
+		errors.Is(err, notificationService.ErrTargetNotFound),
+		errors.Is(err, notificationService.ErrNotifierNotFound),
+		errors.Is(err, incidentService.ErrIncidentNotFound):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, monitorService.ErrInvalidInput),
+		errors.Is(err, monitorService.ErrTargetLimitReached),
+		errors.Is(err, notificationService.ErrInvalidInput),
+		errors.Is(err, incidentService.ErrInvalidInput):
+		return http.StatusUnprocessableEntity, CodeInvalidInput
+	default:
+		return http.StatusInternalServerError, CodeInternal
+	}
+}
+
+// writeServiceError answers with the error object matching a service error.
+// Unexpected errors are logged and their details are kept from the client.
+func writeServiceError(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("API request failed", "error", err)
+		writeError(w, status, code, "Internal server error")
+		return
+	}
+	writeError(w, status, code, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, ErrorResponse{Error: ErrorObject{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("Failed to write API response", "error", err)
+	}
+}
+
+// decodeJSON reads a JSON request body into v. Requiring the JSON content
+// type also keeps other sites from posting forms to the API with the
+// session cookie of a signed in user. On failure the error is written and
+// false returned.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, CodeUnsupportedType, "Content-Type must be application/json")
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("Invalid JSON body: %v", err))
+		return false
+	}
+	return true
+}
+
+// NotFound answers requests to unknown API routes
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, CodeNotFound, "No such API route")
+}
+
+// pathID reads a numeric path parameter. On failure the error is written and
+// false returned.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusNotFound, CodeNotFound, fmt.Sprintf("Invalid %s", name))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
+	monitorService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	notificationService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRequest builds an API request made by the given user, with a JSON body when one is given
+func newRequest(method string, target string, body string, userID int) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: userID}))
+}
+
+// decodeError reads the error object of a failed request
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) ErrorObject {
+	t.Helper()
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Error
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{err: fmt.Errorf("%w: user 2", monitorService.ErrUnauthorized), status: http.StatusForbidden, code: CodeForbidden},
+		{err: notificationService.ErrUnauthorized, status: http.StatusForbidden, code: CodeForbidden},
+		{err: fmt.Errorf("%w: id 3", monitorService.ErrTargetNotFound), status: http.StatusNotFound, code: CodeNotFound},
+		{err: notificationService.ErrNotifierNotFound, status: http.StatusNotFound, code: CodeNotFound},
+		{err: incidentService.ErrIncidentNotFound, status: http.StatusNotFound, code: CodeNotFound},
+		{err: monitorService.ErrInvalidInput, status: http.StatusUnprocessableEntity, code: CodeInvalidInput},
+		{err: monitorService.ErrTargetLimitReached, status: http.StatusUnprocessableEntity, code: CodeInvalidInput},
+		{err: fmt.Errorf("connection reset"), status: http.StatusInternalServerError, code: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			status, code := errorStatus(tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func TestWriteServiceError_HidesInternalErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeServiceError(w, fmt.Errorf("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, ErrorObject{Code: CodeInternal, Message: "Internal server error"}, decodeError(t, w))
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var body struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("form body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=x"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		assert.False(t, decodeJSON(w, req, &body))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.False(t, decodeJSON(w, newRequest(http.MethodPost, "/", `{"nme": "x"}`, 1), &body))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, CodeBadRequest, decodeError(t, w).Code)
+	})
+
+	t.Run("valid body", func(t *testing.T) {
+		req := newRequest(http.MethodPost, "/", `{"name": "x"}`, 1)
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		assert.True(t, decodeJSON(httptest.NewRecorder(), req, &body))
+		assert.Equal(t, "x", body.Name)
+	})
+}
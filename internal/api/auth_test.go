@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSessionService struct {
+	validateSessionFunc func(token string) (*authModel.Session, error)
+}
+
+func (m *mockSessionService) CreateSession(ctx context.Context, userID int) (*authModel.Session, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockSessionService) ValidateSession(ctx context.Context, token string) (*authModel.Session, error) {
+	return m.validateSessionFunc(token)
+}
+
+func (m *mockSessionService) DeleteSession(ctx context.Context, sessionID string) error {
+	return nil
+}
+
+type mockAuthService struct {
+	getUserByIDFunc func(id int) (*authModel.User, error)
+}
+
+func (m *mockAuthService) CreateUser(ctx context.Context, user *authModel.User) (*authModel.User, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) Authenticate(ctx context.Context, email string, password string) (*authModel.User, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) GetUserByID(ctx context.Context, id int) (*authModel.User, error) {
+	return m.getUserByIDFunc(id)
+}
+
+func (m *mockAuthService) GetUserByEmail(ctx context.Context, email string) (*authModel.User, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	return nil
+}
+
+func (m *mockAuthService) SendToken(ctx context.Context, userID int, email string, tokenType authModel.TokenType) error {
+	return nil
+}
+
+func (m *mockAuthService) UpdatePassword(ctx context.Context, userID int, newPassword string) error {
+	return nil
+}
+
+func (m *mockAuthService) UpdateDigestFrequency(ctx context.Context, userID int, frequency authModel.DigestFrequency) error {
+	return nil
+}
+
+func (m *mockAuthService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	return nil
+}
+
+func (m *mockAuthService) ValidateToken(ctx context.Context, token string, tokenType authModel.TokenType) (*authModel.Token, error) {
+	return nil, nil
+}
+
+type mockAPITokenService struct {
+	authenticateFunc func(plainToken string) (*authModel.APIToken, error)
+}
+
+func (m *mockAPITokenService) Create(ctx context.Context, userID int, name string, scopes []authModel.Scope, expiresAt time.Time) (*authModel.APIToken, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockAPITokenService) GetByUserID(ctx context.Context, userID int) ([]*authModel.APIToken, error) {
+	return nil, nil
+}
+
+func (m *mockAPITokenService) Revoke(ctx context.Context, id int, userID int) error {
+	return nil
+}
+
+func (m *mockAPITokenService) Authenticate(ctx context.Context, plainToken string) (*authModel.APIToken, error) {
+	return m.authenticateFunc(plainToken)
+}
+
+func TestRequireAuth(t *testing.T) {
+	sessions := &mockSessionService{
+		validateSessionFunc: func(token string) (*authModel.Session, error) {
+			if token != "valid" {
+				return nil, errors.New("session not found")
+			}
+			return &authModel.Session{UserID: 1}, nil
+		},
+	}
+	users := &mockAuthService{
+		getUserByIDFunc: func(id int) (*authModel.User, error) {
+			return &authModel.User{ID: id}, nil
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r)
+		if ok {
+			assert.Equal(t, 1, user.ID)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	tokens := &mockAPITokenService{
+		authenticateFunc: func(plainToken string) (*authModel.APIToken, error) {
+			if plainToken != "ubt_valid" {
+				return nil, authService.ErrInvalidAPIToken
+			}
+			return &authModel.APIToken{ID: 1, UserID: 1}, nil
+		},
+	}
+	handler := RequireAuth(next, sessions, users, tokens)
+
+	tests := []struct {
+		name          string
+		cookie        string
+		authorization string
+		status        int
+	}{
+		{name: "no session", status: http.StatusUnauthorized},
+		{name: "expired session", cookie: "expired", status: http.StatusUnauthorized},
+		{name: "valid session", cookie: "valid", status: http.StatusNoContent},
+		{name: "valid token", authorization: "Bearer ubt_valid", status: http.StatusNoContent},
+		{name: "invalid token", authorization: "Bearer ubt_invalid", status: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized},
+		{name: "invalid token with valid session", cookie: "valid", authorization: "Bearer ubt_invalid", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/targets", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session_token", Value: tt.cookie})
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, CodeUnauthenticated, decodeError(t, w).Code)
+				assert.Empty(t, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	handler := RequireScope(authModel.ScopeTargetsRead, next)
+
+	tests := []struct {
+		name   string
+		token  *authModel.APIToken
+		status int
+	}{
+		{name: "session", status: http.StatusNoContent},
+		{name: "token with scope", token: &authModel.APIToken{Scopes: []authModel.Scope{authModel.ScopeTargetsRead}}, status: http.StatusNoContent},
+		{name: "token with implied scope", token: &authModel.APIToken{Scopes: []authModel.Scope{authModel.ScopeTargetsWrite}}, status: http.StatusNoContent},
+		{name: "token without scope", token: &authModel.APIToken{Scopes: []authModel.Scope{authModel.ScopeNotifiersWrite}}, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/targets", nil)
+			if tt.token != nil {
+				req = req.WithContext(authService.WithAPIToken(req.Context(), tt.token))
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusForbidden {
+				assert.Equal(t, CodeForbidden, decodeError(t, w).Code)
+			}
+		})
+	}
+}
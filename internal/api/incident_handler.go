@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+)
+
+// IncidentService is the part of the incident service the API exposes
+type IncidentService interface {
+	Get(ctx context.Context, id int, userID int) (*model.Incident, error)
+	Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error)
+}
+
+// Incident is the JSON representation of an incident. Events are only
+// included when a single incident is requested.
+type Incident struct {
+	ID             int             `json:"id"`
+	TargetID       int             `json:"target_id"`
+	TargetURL      string          `json:"target_url"`
+	Status         model.Status    `json:"status"`
+	Cause          string          `json:"cause"`
+	StartedAt      time.Time       `json:"started_at"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at"`
+	ResolvedAt     *time.Time      `json:"resolved_at"`
+	Events         []IncidentEvent `json:"events,omitempty"`
+}
+
+// IncidentEvent is the JSON representation of an entry on an incident's timeline
+type IncidentEvent struct {
+	Kind      model.EventKind `json:"kind"`
+	Message   string          `json:"message"`
+	UserEmail string          `json:"user_email,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newIncident(incident *model.Incident) Incident {
+	response := Incident{
+		ID:             incident.ID,
+		TargetID:       incident.TargetID,
+		TargetURL:      incident.TargetURL,
+		Status:         incident.Status,
+		Cause:          incident.Cause,
+		StartedAt:      incident.StartedAt,
+		AcknowledgedAt: incident.AcknowledgedAt,
+		ResolvedAt:     incident.ResolvedAt,
+	}
+	for _, event := range incident.Events {
+		response.Events = append(response.Events, IncidentEvent{
+			Kind:      event.Kind,
+			Message:   event.Message,
+			UserEmail: event.UserEmail,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return response
+}
+
+// parseIncidentFilter reads the status, target, from and to query parameters.
+// Dates are RFC 3339 timestamps.
+func parseIncidentFilter(r *http.Request) (model.Filter, error) {
+	query := r.URL.Query()
+	filter := model.Filter{
+		Status: model.Status(query.Get("status")),
+		Target: query.Get("target"),
+	}
+
+	for name, field := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return model.Filter{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+		}
+		*field = parsed
+	}
+
+	return filter, nil
+}
+
+// ListIncidents lists the incidents of the user's targets, newest first
+func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+	filter, err := parseIncidentFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	stored, err := h.incidentService.Search(r.Context(), user.ID, filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	incidents := make([]Incident, 0, len(stored))
+	for _, incident := range stored {
+		incidents = append(incidents, newIncident(incident))
+	}
+	slices.SortFunc(incidents, func(a, b Incident) int { return b.ID - a.ID })
+
+	writeJSON(w, http.StatusOK, paginate(incidents, func(i Incident) int64 { return int64(i.ID) }, page, true))
+}
+
+// GetIncident returns an incident with its timeline
+func (h *Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newIncident(incident))
+}
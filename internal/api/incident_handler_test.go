@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	"github.com/shuvo-paul/uptimebot/internal/incident/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIncidentService struct {
+	getFunc    func(id int, userID int) (*model.Incident, error)
+	searchFunc func(userID int, filter model.Filter) ([]*model.Incident, error)
+}
+
+func (m *mockIncidentService) Get(ctx context.Context, id int, userID int) (*model.Incident, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *mockIncidentService) Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error) {
+	return m.searchFunc(userID, filter)
+}
+
+func TestHandler_ListIncidents(t *testing.T) {
+	startedAt := time.Date(2025, 4, 29, 9, 0, 0, 0, time.UTC)
+	var searched model.Filter
+	incidentService := &mockIncidentService{
+		searchFunc: func(userID int, filter model.Filter) ([]*model.Incident, error) {
+			searched = filter
+			if err := filter.Validate(); err != nil {
+				return nil, service.ErrInvalidInput
+			}
+			return []*model.Incident{
+				{ID: 1, TargetID: 1, TargetURL: "https://example.org", Status: model.StatusResolved, StartedAt: startedAt.Add(-time.Hour)},
+				{ID: 3, TargetID: 1, TargetURL: "https://example.org", Status: model.StatusOpen, StartedAt: startedAt},
+			}, nil
+		},
+	}
+	handler := NewHandler(nil, nil, incidentService)
+
+	w := httptest.NewRecorder()
+	handler.ListIncidents(w, newRequest(http.MethodGet, "/api/v1/incidents?target=example&from=2025-04-29T00:00:00Z", "", 1))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "example", searched.Target)
+	assert.Equal(t, time.Date(2025, 4, 29, 0, 0, 0, 0, time.UTC), searched.From)
+	var page ListResponse[Incident]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, 3, page.Data[0].ID)
+	assert.Nil(t, page.Data[0].Events)
+
+	w = httptest.NewRecorder()
+	handler.ListIncidents(w, newRequest(http.MethodGet, "/api/v1/incidents?status=sleeping", "", 1))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ListIncidents(w, newRequest(http.MethodGet, "/api/v1/incidents?from=yesterday", "", 1))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_GetIncident(t *testing.T) {
+	incidentService := &mockIncidentService{
+		getFunc: func(id int, userID int) (*model.Incident, error) {
+			if id != 3 {
+				return nil, service.ErrIncidentNotFound
+			}
+			return &model.Incident{
+				ID:     3,
+				Status: model.StatusOpen,
+				Events: []*model.Event{{Kind: model.EventOpened, Message: "Target went down"}},
+			}, nil
+		},
+	}
+	handler := NewHandler(nil, nil, incidentService)
+
+	req := newRequest(http.MethodGet, "/api/v1/incidents/3", "", 1)
+	req.SetPathValue("id", "3")
+	w := httptest.NewRecorder()
+	handler.GetIncident(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var incident Incident
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &incident))
+	assert.Len(t, incident.Events, 1)
+	assert.Equal(t, model.EventOpened, incident.Events[0].Kind)
+
+	req = newRequest(http.MethodGet, "/api/v1/incidents/4", "", 1)
+	req.SetPathValue("id", "4")
+	w = httptest.NewRecorder()
+	handler.GetIncident(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+)
+
+// NotifierService is the part of the notifier service the API exposes
+type NotifierService interface {
+	Create(ctx context.Context, notifier *model.Notifier, userID int) error
+	Get(ctx context.Context, id int, userID int) (*model.Notifier, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error)
+	Update(ctx context.Context, notifier *model.Notifier, userID int) (*model.Notifier, error)
+	Delete(ctx context.Context, id int, userID int) error
+}
+
+// Notifier is the JSON representation of a contact channel
+type Notifier struct {
+	ID         int                    `json:"id"`
+	Name       string                 `json:"name"`
+	Type       model.NotifierType     `json:"type"`
+	Config     json.RawMessage        `json:"config"`
+	Events     []string               `json:"events"`
+	QuietHours *model.QuietHours      `json:"quiet_hours"`
+	Template   *model.MessageTemplate `json:"template"`
+	RateLimit  int                    `json:"rate_limit"`
+}
+
+func newNotifier(notifier *model.Notifier) Notifier {
+	events := notifier.Events
+	if events == nil {
+		events = []string{}
+	}
+	return Notifier{
+		ID:         notifier.ID,
+		Name:       notifier.Name,
+		Type:       notifier.Type,
+		Config:     notifier.Config,
+		Events:     events,
+		QuietHours: notifier.QuietHours,
+		Template:   notifier.Template,
+		RateLimit:  notifier.RateLimit,
+	}
+}
+
+// NotifierRequest is the body of notifier creates and updates. Fields left
+// out of an update keep their value; the type cannot be changed.
+type NotifierRequest struct {
+	Name       *string             `json:"name"`
+	Type       *model.NotifierType `json:"type"`
+	Config     json.RawMessage     `json:"config"`
+	Events     *[]string           `json:"events"`
+	QuietHours json.RawMessage     `json:"quiet_hours"`
+	Template   json.RawMessage     `json:"template"`
+	RateLimit  *int                `json:"rate_limit"`
+}
+
+// apply copies the fields given in the request onto a notifier. A null
+// quiet_hours or template clears it.
+func (req NotifierRequest) apply(notifier *model.Notifier) error {
+	if req.Name != nil {
+		notifier.Name = *req.Name
+	}
+	if req.Type != nil {
+		notifier.Type = *req.Type
+	}
+	if req.Config != nil {
+		notifier.Config = req.Config
+	}
+	if req.Events != nil {
+		notifier.Events = *req.Events
+	}
+	if req.QuietHours != nil {
+		notifier.QuietHours = nil
+		if err := json.Unmarshal(req.QuietHours, &notifier.QuietHours); err != nil {
+			return fmt.Errorf("invalid quiet_hours: %w", err)
+		}
+	}
+	if req.Template != nil {
+		notifier.Template = nil
+		if err := json.Unmarshal(req.Template, &notifier.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	if req.RateLimit != nil {
+		notifier.RateLimit = *req.RateLimit
+	}
+	return nil
+}
+
+// ListNotifiers lists the contact channels of the user, ordered by ID
+func (h *Handler) ListNotifiers(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	stored, err := h.notifierService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	notifiers := make([]Notifier, 0, len(stored))
+	for _, notifier := range stored {
+		notifiers = append(notifiers, newNotifier(notifier))
+	}
+	slices.SortFunc(notifiers, func(a, b Notifier) int { return a.ID - b.ID })
+
+	writeJSON(w, http.StatusOK, paginate(notifiers, func(n Notifier) int64 { return int64(n.ID) }, page, false))
+}
+
+func (h *Handler) CreateNotifier(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	var req NotifierRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	notifier := &model.Notifier{}
+	if err := req.apply(notifier); err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+	if err := h.notifierService.Create(r.Context(), notifier, user.ID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newNotifier(notifier))
+}
+
+func (h *Handler) GetNotifier(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	notifier, err := h.notifierService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newNotifier(notifier))
+}
+
+// UpdateNotifier changes the fields given in the body
+func (h *Handler) UpdateNotifier(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req NotifierRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	notifier, err := h.notifierService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if err := req.apply(notifier); err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+	updated, err := h.notifierService.Update(r.Context(), notifier, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newNotifier(updated))
+}
+
+func (h *Handler) DeleteNotifier(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notifierService.Delete(r.Context(), id, user.ID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
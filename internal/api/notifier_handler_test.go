@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotifierService struct {
+	createFunc      func(notifier *model.Notifier, userID int) error
+	getFunc         func(id int, userID int) (*model.Notifier, error)
+	getByUserIDFunc func(userID int) ([]*model.Notifier, error)
+	updateFunc      func(notifier *model.Notifier, userID int) (*model.Notifier, error)
+	deleteFunc      func(id int, userID int) error
+}
+
+func (m *mockNotifierService) Create(ctx context.Context, notifier *model.Notifier, userID int) error {
+	return m.createFunc(notifier, userID)
+}
+
+func (m *mockNotifierService) Get(ctx context.Context, id int, userID int) (*model.Notifier, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *mockNotifierService) GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockNotifierService) Update(ctx context.Context, notifier *model.Notifier, userID int) (*model.Notifier, error) {
+	return m.updateFunc(notifier, userID)
+}
+
+func (m *mockNotifierService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+// validated mimics the service, which rejects notifiers that fail validation
+func validated(notifier *model.Notifier) error {
+	if err := notifier.Validate(); err != nil {
+		return service.ErrInvalidInput
+	}
+	return nil
+}
+
+func TestHandler_ListNotifiers(t *testing.T) {
+	notifierService := &mockNotifierService{
+		getByUserIDFunc: func(userID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{
+				{ID: 2, UserID: userID, Name: "ops", Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients":["ops@example.com"]}`)},
+				{ID: 1, UserID: userID, Name: "#alerts", Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url":"https://hooks.slack.com/x"}`)},
+			}, nil
+		},
+	}
+	handler := NewHandler(nil, notifierService, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListNotifiers(w, newRequest(http.MethodGet, "/api/v1/notifiers", "", 1))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page ListResponse[Notifier]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, "#alerts", page.Data[0].Name)
+	assert.JSONEq(t, `{"webhook_url":"https://hooks.slack.com/x"}`, string(page.Data[0].Config))
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestHandler_CreateNotifier(t *testing.T) {
+	notifierService := &mockNotifierService{
+		createFunc: func(notifier *model.Notifier, userID int) error {
+			notifier.UserID = userID
+			notifier.ID = 4
+			return validated(notifier)
+		},
+	}
+	handler := NewHandler(nil, notifierService, nil)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "created", body: `{"name": "#ops", "type": "slack", "config": {"webhook_url": "https://hooks.slack.com/ops"}, "events": ["down"], "rate_limit": 10}`, status: http.StatusCreated},
+		{name: "email", body: `{"name": "ops", "type": "email", "config": {"recipients": ["ops@example.com"]}}`, status: http.StatusUnprocessableEntity},
+		{name: "missing config", body: `{"type": "slack"}`, status: http.StatusUnprocessableEntity},
+		{name: "malformed quiet hours", body: `{"type": "slack", "quiet_hours": "nights"}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.CreateNotifier(w, newRequest(http.MethodPost, "/api/v1/notifiers", tt.body, 1))
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusCreated {
+				return
+			}
+			var notifier Notifier
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &notifier))
+			assert.Equal(t, 4, notifier.ID)
+			assert.Equal(t, []string{"down"}, notifier.Events)
+			assert.Equal(t, 10, notifier.RateLimit)
+		})
+	}
+}
+
+func TestHandler_UpdateNotifier(t *testing.T) {
+	notifierService := &mockNotifierService{
+		getFunc: func(id int, userID int) (*model.Notifier, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return &model.Notifier{
+				ID:       id,
+				UserID:   1,
+				Name:     "#alerts",
+				Type:     model.NotifierTypeSlack,
+				Config:   json.RawMessage(`{"webhook_url":"https://hooks.slack.com/x"}`),
+				Template: &model.MessageTemplate{Title: "{{.Name}} is {{.Status}}"},
+			}, nil
+		},
+		updateFunc: func(notifier *model.Notifier, userID int) (*model.Notifier, error) {
+			return notifier, validated(notifier)
+		},
+	}
+	handler := NewHandler(nil, notifierService, nil)
+
+	req := newRequest(http.MethodPatch, "/api/v1/notifiers/1", `{"name": "#ops", "template": null}`, 1)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.UpdateNotifier(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var notifier Notifier
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &notifier))
+	assert.Equal(t, "#ops", notifier.Name)
+	assert.Nil(t, notifier.Template)
+	assert.Equal(t, model.NotifierTypeSlack, notifier.Type)
+
+	req = newRequest(http.MethodPatch, "/api/v1/notifiers/1", `{"name": "#ops"}`, 2)
+	req.SetPathValue("id", "1")
+	w = httptest.NewRecorder()
+	handler.UpdateNotifier(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
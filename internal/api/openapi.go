@@ -0,0 +1,29 @@
+package api
+
+import (
+	_ "embed"
+	"log/slog"
+	"net/http"
+
+	"github.com/shuvo-paul/uptimebot/web/static"
+)
+
+// openAPISpec describes the API for client generators. TestOpenAPISpec keeps
+// it in sync with Routes and the JSON representations.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPI serves the OpenAPI document of the API
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPISpec); err != nil {
+		slog.Error("Failed to write OpenAPI document", "error", err)
+	}
+}
+
+// Docs serves the interactive API documentation, which renders the OpenAPI
+// document in the browser
+func Docs(w http.ResponseWriter, r *http.Request) {
+	http.ServeFileFS(w, r, static.StaticFS, "api-docs.html")
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openAPIDocument is the part of the OpenAPI document the tests look at
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Security []map[string][]string `json:"security"`
+}
+
+func loadOpenAPI(t *testing.T) openAPIDocument {
+	t.Helper()
+	var document openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &document); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return document
+}
+
+func TestOpenAPISpec_Routes(t *testing.T) {
+	document := loadOpenAPI(t)
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+
+	routes := (&Handler{}).Routes()
+	registered := make(map[string]bool)
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+
+		raw, ok := document.Paths[route.Path][strings.ToLower(route.Method)]
+		if !assert.True(t, ok, "route %s is missing from openapi.json", key) {
+			continue
+		}
+		var operation openAPIOperation
+		assert.NoError(t, json.Unmarshal(raw, &operation))
+		assert.Contains(t, operation.Security, map[string][]string{"apiToken": {string(route.Scope)}},
+			"route %s should require the %s scope of API tokens", key, route.Scope)
+	}
+
+	for path, item := range document.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			key := strings.ToUpper(method) + " " + path
+			assert.True(t, registered[key], "openapi.json describes %s, which is not a route", key)
+		}
+	}
+}
+
+func TestOpenAPISpec_Schemas(t *testing.T) {
+	document := loadOpenAPI(t)
+
+	types := map[string]any{
+		"Target":          Target{},
+		"TargetRequest":   TargetRequest{},
+		"TargetList":      ListResponse[Target]{},
+		"CheckResult":     CheckResult{},
+		"CheckResultList": ListResponse[CheckResult]{},
+		"Notifier":        Notifier{},
+		"NotifierRequest": NotifierRequest{},
+		"NotifierList":    ListResponse[Notifier]{},
+		"Incident":        Incident{},
+		"IncidentEvent":   IncidentEvent{},
+		"IncidentList":    ListResponse[Incident]{},
+		"ErrorResponse":   ErrorResponse{},
+		"ErrorObject":     ErrorObject{},
+	}
+
+	for name, value := range types {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := document.Components.Schemas[name]
+			if !assert.True(t, ok, "schema %s is missing from openapi.json", name) {
+				return
+			}
+
+			var fields []string
+			valueType := reflect.TypeOf(value)
+			for i := range valueType.NumField() {
+				tag, _, _ := strings.Cut(valueType.Field(i).Tag.Get("json"), ",")
+				fields = append(fields, tag)
+			}
+			var properties []string
+			for property := range schema.Properties {
+				properties = append(properties, property)
+			}
+			slices.Sort(fields)
+			slices.Sort(properties)
+
+			assert.Equal(t, fields, properties)
+		})
+	}
+}
+
+func TestOpenAPISpec_References(t *testing.T) {
+	var document map[string]any
+	assert.NoError(t, json.Unmarshal(openAPISpec, &document))
+
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok {
+				var target any = document
+				for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					parent, _ := target.(map[string]any)
+					target = parent[part]
+				}
+				assert.NotNil(t, target, "unresolved reference %s", ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(document)
+}
+
+func TestOpenAPI(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	OpenAPI(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(openAPISpec), w.Body.String())
+}
+
+func TestDocs(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Docs(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/static/js/api-docs.js")
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Page sizes of list endpoints
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+)
+
+// ListResponse is the body of list endpoints. NextCursor is passed as the
+// cursor query parameter to get the following page, and is empty on the
+// last page.
+type ListResponse[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// pageRequest is the position and size of the requested page
+type pageRequest struct {
+	after int64 // ID of the last item of the previous page, zero for the first page
+	limit int
+}
+
+// parsePage reads the cursor and limit query parameters
+func parsePage(r *http.Request) (pageRequest, error) {
+	page := pageRequest{limit: defaultLimit}
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return pageRequest{}, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		page.limit = limit
+	}
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return pageRequest{}, err
+		}
+		page.after = after
+	}
+
+	return page, nil
+}
+
+// Cursors are opaque to clients so the way pages are keyed can change
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return id, nil
+}
+
+// paginate returns the page of items following the cursor. Items must be
+// sorted by ID, ascending or descending as told by desc.
+func paginate[T any](items []T, id func(T) int64, page pageRequest, desc bool) ListResponse[T] {
+	start := 0
+	if page.after > 0 {
+		for start < len(items) {
+			current := id(items[start])
+			if (!desc && current > page.after) || (desc && current < page.after) {
+				break
+			}
+			start++
+		}
+	}
+
+	end := min(start+page.limit, len(items))
+	response := ListResponse[T]{Data: append([]T{}, items[start:end]...)}
+	if end < len(items) {
+		response.NextCursor = encodeCursor(id(items[end-1]))
+	}
+	return response
+}
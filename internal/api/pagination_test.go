@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	page, err := parsePage(httptest.NewRequest("GET", "/targets", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, pageRequest{limit: defaultLimit}, page)
+
+	page, err = parsePage(httptest.NewRequest("GET", "/targets?limit=2&cursor="+encodeCursor(7), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, pageRequest{after: 7, limit: 2}, page)
+
+	for _, query := range []string{"limit=0", "limit=101", "limit=x", "cursor=!!", "cursor=" + encodeCursor(-1)} {
+		_, err := parsePage(httptest.NewRequest("GET", "/targets?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	id := func(i int) int64 { return int64(i) }
+	ascending := []int{1, 2, 4, 5, 7}
+
+	first := paginate(ascending, id, pageRequest{limit: 2}, false)
+	assert.Equal(t, []int{1, 2}, first.Data)
+	assert.NotEmpty(t, first.NextCursor)
+
+	after, err := decodeCursor(first.NextCursor)
+	assert.NoError(t, err)
+	second := paginate(ascending, id, pageRequest{after: after, limit: 2}, false)
+	assert.Equal(t, []int{4, 5}, second.Data)
+
+	// The last page has no cursor
+	last := paginate(ascending, id, pageRequest{after: 5, limit: 2}, false)
+	assert.Equal(t, []int{7}, last.Data)
+	assert.Empty(t, last.NextCursor)
+
+	// Items removed since the previous page do not shift the next one
+	descending := []int{9, 6, 3, 1}
+	page := paginate(descending, id, pageRequest{after: 7, limit: 2}, true)
+	assert.Equal(t, []int{6, 3}, page.Data)
+
+	empty := paginate([]int{}, id, pageRequest{after: 3, limit: 2}, false)
+	assert.NotNil(t, empty.Data)
+	assert.Empty(t, empty.NextCursor)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+)
+
+// Route is an endpoint of the API. Paths are relative to /api/v1 and use the
+// patterns of http.ServeMux, which match the path templates of the OpenAPI
+// document.
+type Route struct {
+	Method  string
+	Path    string
+	Scope   model.Scope // required of API tokens; signed in users have every scope
+	Handler http.HandlerFunc
+}
+
+// Routes lists the endpoints of the API. Every route must be described in
+// openapi.json.
+func (h *Handler) Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/targets", model.ScopeTargetsRead, h.ListTargets},
+		{http.MethodPost, "/targets", model.ScopeTargetsWrite, h.CreateTarget},
+		{http.MethodGet, "/targets/{id}", model.ScopeTargetsRead, h.GetTarget},
+		{http.MethodPatch, "/targets/{id}", model.ScopeTargetsWrite, h.UpdateTarget},
+		{http.MethodDelete, "/targets/{id}", model.ScopeTargetsWrite, h.DeleteTarget},
+		{http.MethodGet, "/targets/{id}/results", model.ScopeTargetsRead, h.ListResults},
+		{http.MethodGet, "/notifiers", model.ScopeNotifiersRead, h.ListNotifiers},
+		{http.MethodPost, "/notifiers", model.ScopeNotifiersWrite, h.CreateNotifier},
+		{http.MethodGet, "/notifiers/{id}", model.ScopeNotifiersRead, h.GetNotifier},
+		{http.MethodPatch, "/notifiers/{id}", model.ScopeNotifiersWrite, h.UpdateNotifier},
+		{http.MethodDelete, "/notifiers/{id}", model.ScopeNotifiersWrite, h.DeleteNotifier},
+		{http.MethodGet, "/incidents", model.ScopeTargetsRead, h.ListIncidents},
+		{http.MethodGet, "/incidents/{id}", model.ScopeTargetsRead, h.GetIncident},
+	}
+}
+
+// Mux serves the routes, each limited to its scope
+func (h *Handler) Mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, route := range h.Routes() {
+		mux.HandleFunc(route.Method+" "+route.Path, RequireScope(route.Scope, route.Handler))
+	}
+	mux.HandleFunc("/", NotFound)
+	return mux
+}
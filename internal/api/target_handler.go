@@ -0,0 +1,275 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+// Target is the JSON representation of a monitored target
+type Target struct {
+	ID                int        `json:"id"`
+	URL               string     `json:"url"`
+	Status            string     `json:"status"`
+	Enabled           bool       `json:"enabled"`
+	IntervalSeconds   int        `json:"interval_seconds"`
+	Tags              []string   `json:"tags"`
+	ParentIDs         []int      `json:"parent_ids"`
+	FlapThreshold     int        `json:"flap_threshold"`
+	FlapWindowSeconds int        `json:"flap_window_seconds"`
+	PausedUntil       *time.Time `json:"paused_until"`
+	StatusChangedAt   time.Time  `json:"status_changed_at"`
+}
+
+func newTarget(userTarget model.UserTarget) Target {
+	tags := userTarget.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	parentIDs := userTarget.ParentIDs
+	if parentIDs == nil {
+		parentIDs = []int{}
+	}
+	return Target{
+		ID:                userTarget.ID,
+		URL:               userTarget.URL,
+		Status:            userTarget.Status,
+		Enabled:           userTarget.Enabled,
+		IntervalSeconds:   int(userTarget.Interval / time.Second),
+		Tags:              tags,
+		ParentIDs:         parentIDs,
+		FlapThreshold:     userTarget.FlapThreshold,
+		FlapWindowSeconds: int(userTarget.FlapWindow / time.Second),
+		PausedUntil:       userTarget.PausedUntil,
+		StatusChangedAt:   userTarget.StatusChangedAt,
+	}
+}
+
+// TargetRequest is the body of target creates and updates. Fields left out
+// of an update keep their value.
+type TargetRequest struct {
+	URL               *string   `json:"url"`
+	IntervalSeconds   *int      `json:"interval_seconds"`
+	Tags              *[]string `json:"tags"`
+	ParentIDs         *[]int    `json:"parent_ids"`
+	FlapThreshold     *int      `json:"flap_threshold"`
+	FlapWindowSeconds *int      `json:"flap_window_seconds"`
+	Enabled           *bool     `json:"enabled"`
+}
+
+// apply copies the fields given in the request onto a target
+func (req TargetRequest) apply(userTarget *model.UserTarget) {
+	if req.URL != nil {
+		userTarget.URL = *req.URL
+	}
+	if req.IntervalSeconds != nil {
+		userTarget.Interval = time.Duration(*req.IntervalSeconds) * time.Second
+	}
+	if req.Tags != nil {
+		userTarget.Tags = model.ParseTags(strings.Join(*req.Tags, ","))
+	}
+	if req.ParentIDs != nil {
+		userTarget.ParentIDs = *req.ParentIDs
+	}
+	if req.FlapThreshold != nil {
+		userTarget.FlapThreshold = *req.FlapThreshold
+	}
+	if req.FlapWindowSeconds != nil {
+		userTarget.FlapWindow = time.Duration(*req.FlapWindowSeconds) * time.Second
+	}
+	if req.Enabled != nil {
+		userTarget.Enabled = *req.Enabled
+	}
+}
+
+// CheckResult is the JSON representation of the outcome of a check
+type CheckResult struct {
+	ID         int64     `json:"id"`
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code"`
+	DurationMS int64     `json:"duration_ms"`
+	ErrorClass string    `json:"error_class"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+func newCheckResult(result model.CheckResult) CheckResult {
+	return CheckResult{
+		ID:         result.ID,
+		Status:     result.Status,
+		StatusCode: result.StatusCode,
+		DurationMS: result.Duration.Milliseconds(),
+		ErrorClass: result.ErrorClass,
+		CheckedAt:  result.CheckedAt,
+	}
+}
+
+// ListTargets lists the targets of the user, ordered by ID
+func (h *Handler) ListTargets(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	userTargets, err := h.targetService.GetAllByUserID(r.Context(), user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	targets := make([]Target, 0, len(userTargets))
+	for _, userTarget := range userTargets {
+		targets = append(targets, newTarget(userTarget))
+	}
+	slices.SortFunc(targets, func(a, b Target) int { return a.ID - b.ID })
+
+	writeJSON(w, http.StatusOK, paginate(targets, func(t Target) int64 { return int64(t.ID) }, page, false))
+}
+
+// CreateTarget starts monitoring a new target. The URL and interval are
+// required; tags and enabled are optional.
+func (h *Handler) CreateTarget(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	var req TargetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.URL == nil || req.IntervalSeconds == nil {
+		writeError(w, http.StatusUnprocessableEntity, CodeInvalidInput, "url and interval_seconds are required")
+		return
+	}
+
+	userTarget, err := h.targetService.Create(r.Context(), user.ID, *req.URL, time.Duration(*req.IntervalSeconds)*time.Second)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	// Tags, parents, flap settings and the enabled flag are not part of
+	// creating a target. When they are rejected, the target is removed again
+	// so a failed request leaves nothing behind.
+	if req.Tags != nil || req.ParentIDs != nil || req.FlapThreshold != nil || req.FlapWindowSeconds != nil || req.Enabled != nil {
+		id := userTarget.ID
+		req.apply(&userTarget)
+		userTarget, err = h.targetService.Update(r.Context(), userTarget, user.ID)
+		if err != nil {
+			if deleteErr := h.targetService.Delete(r.Context(), id, user.ID); deleteErr != nil {
+				slog.Error("Failed to remove partly created target", "target", id, "error", deleteErr)
+			}
+			writeServiceError(w, err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, newTarget(userTarget))
+}
+
+func (h *Handler) GetTarget(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	userTarget, err := h.targetService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTarget(userTarget))
+}
+
+// UpdateTarget changes the fields given in the body
+func (h *Handler) UpdateTarget(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req TargetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	userTarget, err := h.targetService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	req.apply(&userTarget)
+	userTarget, err = h.targetService.Update(r.Context(), userTarget, user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTarget(userTarget))
+}
+
+func (h *Handler) DeleteTarget(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.targetService.Delete(r.Context(), id, user.ID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListResults lists the check results of a target, newest first
+func (h *Handler) ListResults(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	// One extra result tells whether there is a next page
+	stored, err := h.targetService.GetResults(r.Context(), id, user.ID, page.after, page.limit+1)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	results := make([]CheckResult, 0, len(stored))
+	for _, result := range stored {
+		results = append(results, newCheckResult(result))
+	}
+
+	writeJSON(w, http.StatusOK, paginate(results, func(c CheckResult) int64 { return c.ID }, pageRequest{limit: page.limit}, true))
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTargetService struct {
+	createFunc         func(userID int, url string, interval time.Duration) (model.UserTarget, error)
+	getByIDFunc        func(id int, userID int) (model.UserTarget, error)
+	getAllByUserIDFunc func(userID int) ([]model.UserTarget, error)
+	updateFunc         func(target model.UserTarget, userID int) (model.UserTarget, error)
+	deleteFunc         func(id int, userID int) error
+	getResultsFunc     func(id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error)
+}
+
+func (m *mockTargetService) Create(ctx context.Context, userID int, url string, interval time.Duration) (model.UserTarget, error) {
+	return m.createFunc(userID, url, interval)
+}
+
+func (m *mockTargetService) GetByID(ctx context.Context, id int, userID int) (model.UserTarget, error) {
+	return m.getByIDFunc(id, userID)
+}
+
+func (m *mockTargetService) GetAll(ctx context.Context) ([]model.UserTarget, error) {
+	return nil, nil
+}
+
+func (m *mockTargetService) GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockTargetService) Update(ctx context.Context, target model.UserTarget, userID int) (model.UserTarget, error) {
+	return m.updateFunc(target, userID)
+}
+
+func (m *mockTargetService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *mockTargetService) InitializeMonitoring(ctx context.Context) error {
+	return nil
+}
+
+func (m *mockTargetService) ToggleEnabled(ctx context.Context, id int, userID int) (model.UserTarget, error) {
+	return model.UserTarget{}, nil
+}
+
+func (m *mockTargetService) PauseFromSlack(ctx context.Context, id int, until time.Time) (model.UserTarget, error) {
+	return model.UserTarget{}, nil
+}
+
+func (m *mockTargetService) Pause(ctx context.Context, id int, userID int, until time.Time) (model.UserTarget, error) {
+	return model.UserTarget{}, nil
+}
+
+func (m *mockTargetService) GetResults(ctx context.Context, id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+	return m.getResultsFunc(id, userID, beforeID, limit)
+}
+
+// ownedTarget returns a target of user 1, or the errors the service returns for other ids and users
+func ownedTarget(id int, userID int) (model.UserTarget, error) {
+	if id != 1 {
+		return model.UserTarget{}, service.ErrTargetNotFound
+	}
+	if userID != 1 {
+		return model.UserTarget{}, service.ErrUnauthorized
+	}
+	return model.UserTarget{
+		UserID: 1,
+		Tags:   []string{"api"},
+		Target: &monitor.Target{ID: 1, URL: "https://example.org", Status: "up", Enabled: true, Interval: time.Minute},
+	}, nil
+}
+
+func TestHandler_ListTargets(t *testing.T) {
+	targetService := &mockTargetService{
+		getAllByUserIDFunc: func(userID int) ([]model.UserTarget, error) {
+			return []model.UserTarget{
+				{UserID: userID, Target: &monitor.Target{ID: 3, URL: "https://c.example.org", Interval: time.Minute}},
+				{UserID: userID, Target: &monitor.Target{ID: 1, URL: "https://a.example.org", Interval: time.Minute}},
+				{UserID: userID, Target: &monitor.Target{ID: 2, URL: "https://b.example.org", Interval: time.Minute}},
+			}, nil
+		},
+	}
+	handler := NewHandler(targetService, nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListTargets(w, newRequest(http.MethodGet, "/api/v1/targets?limit=2", "", 1))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page ListResponse[Target]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, 1, page.Data[0].ID)
+	assert.Equal(t, 60, page.Data[0].IntervalSeconds)
+	assert.Equal(t, []string{}, page.Data[0].Tags)
+	assert.Equal(t, []int{}, page.Data[0].ParentIDs)
+
+	w = httptest.NewRecorder()
+	handler.ListTargets(w, newRequest(http.MethodGet, "/api/v1/targets?limit=2&cursor="+page.NextCursor, "", 1))
+	var last ListResponse[Target]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &last))
+	assert.Len(t, last.Data, 1)
+	assert.Equal(t, 3, last.Data[0].ID)
+	assert.Empty(t, last.NextCursor)
+}
+
+func TestHandler_CreateTarget(t *testing.T) {
+	targetService := &mockTargetService{
+		createFunc: func(userID int, url string, interval time.Duration) (model.UserTarget, error) {
+			if interval < 30*time.Second {
+				return model.UserTarget{}, service.ErrInvalidInput
+			}
+			return model.UserTarget{UserID: userID, Target: &monitor.Target{ID: 5, URL: url, Interval: interval, Enabled: true}}, nil
+		},
+		updateFunc: func(target model.UserTarget, userID int) (model.UserTarget, error) {
+			if len(target.ParentIDs) > 0 {
+				return model.UserTarget{}, service.ErrInvalidInput
+			}
+			return target, nil
+		},
+	}
+	var deleted []int
+	targetService.deleteFunc = func(id int, userID int) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+	handler := NewHandler(targetService, nil, nil)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{name: "created with tags", body: `{"url": "https://example.org", "interval_seconds": 60, "tags": ["API", "web"]}`, status: http.StatusCreated},
+		{name: "invalid parents", body: `{"url": "https://example.org", "interval_seconds": 60, "parent_ids": [9]}`, status: http.StatusUnprocessableEntity, code: CodeInvalidInput},
+		{name: "missing interval", body: `{"url": "https://example.org"}`, status: http.StatusUnprocessableEntity, code: CodeInvalidInput},
+		{name: "interval too short", body: `{"url": "https://example.org", "interval_seconds": 5}`, status: http.StatusUnprocessableEntity, code: CodeInvalidInput},
+		{name: "malformed body", body: `{"url": `, status: http.StatusBadRequest, code: CodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.CreateTarget(w, newRequest(http.MethodPost, "/api/v1/targets", tt.body, 1))
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.code != "" {
+				assert.Equal(t, tt.code, decodeError(t, w).Code)
+				return
+			}
+			var target Target
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &target))
+			assert.Equal(t, 5, target.ID)
+			assert.Equal(t, []string{"api", "web"}, target.Tags)
+		})
+	}
+
+	// Only the target whose parents were rejected is removed again
+	assert.Equal(t, []int{5}, deleted)
+}
+
+func TestHandler_TargetErrors(t *testing.T) {
+	targetService := &mockTargetService{
+		getByIDFunc: ownedTarget,
+		deleteFunc: func(id int, userID int) error {
+			_, err := ownedTarget(id, userID)
+			return err
+		},
+	}
+	handler := NewHandler(targetService, nil, nil)
+
+	tests := []struct {
+		name   string
+		call   func(w http.ResponseWriter, r *http.Request)
+		path   string
+		userID int
+		status int
+		code   string
+	}{
+		{name: "not owned", call: handler.GetTarget, path: "1", userID: 2, status: http.StatusForbidden, code: CodeForbidden},
+		{name: "missing", call: handler.GetTarget, path: "9", userID: 1, status: http.StatusNotFound, code: CodeNotFound},
+		{name: "invalid id", call: handler.GetTarget, path: "abc", userID: 1, status: http.StatusNotFound, code: CodeNotFound},
+		{name: "delete not owned", call: handler.DeleteTarget, path: "1", userID: 2, status: http.StatusForbidden, code: CodeForbidden},
+		{name: "delete", call: handler.DeleteTarget, path: "1", userID: 1, status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(http.MethodGet, "/api/v1/targets/"+tt.path, "", tt.userID)
+			req.SetPathValue("id", tt.path)
+			w := httptest.NewRecorder()
+
+			tt.call(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.code != "" {
+				assert.Equal(t, tt.code, decodeError(t, w).Code)
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateTarget(t *testing.T) {
+	var updated model.UserTarget
+	targetService := &mockTargetService{
+		getByIDFunc: ownedTarget,
+		updateFunc: func(target model.UserTarget, userID int) (model.UserTarget, error) {
+			updated = target
+			return target, nil
+		},
+	}
+	handler := NewHandler(targetService, nil, nil)
+
+	req := newRequest(http.MethodPatch, "/api/v1/targets/1", `{"enabled": false}`, 1)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	handler.UpdateTarget(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, updated.Enabled)
+	// Fields left out keep their value
+	assert.Equal(t, "https://example.org", updated.URL)
+	assert.Equal(t, time.Minute, updated.Interval)
+	assert.Equal(t, []string{"api"}, updated.Tags)
+
+	req = newRequest(http.MethodPatch, "/api/v1/targets/1", `{"parent_ids": [2, 3]}`, 1)
+	req.SetPathValue("id", "1")
+	w = httptest.NewRecorder()
+
+	handler.UpdateTarget(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int{2, 3}, updated.ParentIDs)
+	var target Target
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &target))
+	assert.Equal(t, []int{2, 3}, target.ParentIDs)
+
+	req = newRequest(http.MethodPatch, "/api/v1/targets/1", `{"flap_threshold": 3, "flap_window_seconds": 3600}`, 1)
+	req.SetPathValue("id", "1")
+	w = httptest.NewRecorder()
+
+	handler.UpdateTarget(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, updated.FlapThreshold)
+	assert.Equal(t, time.Hour, updated.FlapWindow)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &target))
+	assert.Equal(t, 3600, target.FlapWindowSeconds)
+}
+
+func TestHandler_ListResults(t *testing.T) {
+	checkedAt := time.Date(2025, 4, 29, 9, 0, 0, 0, time.UTC)
+	targetService := &mockTargetService{
+		getResultsFunc: func(id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+			if _, err := ownedTarget(id, userID); err != nil {
+				return nil, err
+			}
+			assert.Equal(t, 3, limit)
+			var results []model.CheckResult
+			for resultID := int64(10); resultID > 10-int64(limit); resultID-- {
+				results = append(results, model.CheckResult{ID: resultID, TargetID: id, Status: "up", StatusCode: 200, Duration: 120 * time.Millisecond, CheckedAt: checkedAt})
+			}
+			return results, nil
+		},
+	}
+	handler := NewHandler(targetService, nil, nil)
+
+	req := newRequest(http.MethodGet, "/api/v1/targets/1/results?limit=2", "", 1)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	handler.ListResults(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page ListResponse[CheckResult]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, int64(10), page.Data[0].ID)
+	assert.Equal(t, int64(120), page.Data[0].DurationMS)
+	after, err := decodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), after)
+
+	req = newRequest(http.MethodGet, "/api/v1/targets/1/results", "", 2)
+	req.SetPathValue("id", "1")
+	w = httptest.NewRecorder()
+	handler.ListResults(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
 	"github.com/shuvo-paul/uptimebot/internal/auth/service"
@@ -21,17 +24,25 @@ type AuthHandler struct {
 	sessionService service.SessionServiceInterface
 	authService    service.AuthServiceInterface
 	flashStore     flash.FlashStoreInterface
+	tokenService   service.APITokenServiceInterface
+	now            func() time.Time
 }
 
+// apiTokenLifetimes are the expiries offered for new API tokens, in days
+var apiTokenLifetimes = []int{30, 90, 365}
+
 func NewAuthHandler(
 	authService service.AuthServiceInterface,
 	sessionService service.SessionServiceInterface,
 	flashStore flash.FlashStoreInterface,
+	tokenService service.APITokenServiceInterface,
 ) *AuthHandler {
 	return &AuthHandler{
 		authService:    authService,
 		sessionService: sessionService,
 		flashStore:     flashStore,
+		tokenService:   tokenService,
+		now:            time.Now,
 	}
 }
 
@@ -60,7 +71,7 @@ func (c *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Password: r.FormValue("password"),
 	}
 
-	_, err := c.authService.CreateUser(user)
+	_, err := c.authService.CreateUser(r.Context(), user)
 	if err != nil {
 		errors := []string{err.Error()}
 		c.flashStore.SetErrors(ctx, errors)
@@ -94,14 +105,14 @@ func (c *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	email := r.FormValue("email")
 	password := r.FormValue("password")
 
-	user, err := c.authService.Authenticate(email, password)
+	user, err := c.authService.Authenticate(r.Context(), email, password)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{err.Error()})
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
-	session, token, err := c.sessionService.CreateSession(user.ID)
+	session, token, err := c.sessionService.CreateSession(r.Context(), user.ID)
 	if err != nil {
 		http.Error(w, "Failed to create session", http.StatusInternalServerError)
 		return
@@ -129,7 +140,7 @@ func (c *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := c.authService.VerifyEmail(token)
+	err := c.authService.VerifyEmail(r.Context(), token)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Invalid or expired verification token"})
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
@@ -156,14 +167,14 @@ func (c *AuthHandler) SendVerificationEmail(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	user, err := c.authService.GetUserByID(userIDInt)
+	user, err := c.authService.GetUserByID(r.Context(), userIDInt)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Failed to get user"})
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
-	err = c.authService.SendToken(user.ID, user.Email, model.TokenTypeEmailVerification)
+	err = c.authService.SendToken(r.Context(), user.ID, user.Email, model.TokenTypeEmailVerification)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Failed to send verification email"})
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
@@ -178,7 +189,7 @@ func (c *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie("session_token")
 	if err == nil {
 		// Invalidate the session in the backend
-		if err := c.sessionService.DeleteSession(cookie.Value); err != nil {
+		if err := c.sessionService.DeleteSession(r.Context(), cookie.Value); err != nil {
 			http.Error(w, "Failed to logout", http.StatusInternalServerError)
 			return
 		}
@@ -219,7 +230,7 @@ func (c *AuthHandler) SendResetLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := c.authService.GetUserByEmail(email)
+	user, err := c.authService.GetUserByEmail(r.Context(), email)
 	if err != nil {
 		// Don't reveal if email exists or not for security
 		c.flashStore.SetSuccesses(ctx, []string{"If your email exists in our system, you will receive a password reset link shortly."})
@@ -227,7 +238,7 @@ func (c *AuthHandler) SendResetLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = c.authService.SendToken(user.ID, email, model.TokenTypePasswordReset)
+	err = c.authService.SendToken(r.Context(), user.ID, email, model.TokenTypePasswordReset)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Failed to send reset password email"})
 		http.Redirect(w, r, "/request-reset-password", http.StatusSeeOther)
@@ -249,7 +260,7 @@ func (c *AuthHandler) ShowResetPasswordForm(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	_, err := c.authService.ValidateToken(token, model.TokenTypePasswordReset)
+	_, err := c.authService.ValidateToken(r.Context(), token, model.TokenTypePasswordReset)
 	if err != nil {
 		c.flashStore.SetErrors(r.Context(), []string{"Invalid or expired token"})
 		http.Redirect(w, r, "/request-reset-password", http.StatusSeeOther)
@@ -277,7 +288,7 @@ func (c *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/reset-password?token="+token, http.StatusSeeOther)
 		return
 	}
-	if err := c.authService.ResetPassword(token, password); err != nil {
+	if err := c.authService.ResetPassword(r.Context(), token, password); err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Failed to reset password"})
 		http.Redirect(w, r, "/reset-password?token="+token, http.StatusSeeOther)
 		return
@@ -288,12 +299,123 @@ func (c *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *AuthHandler) ShowProfileForm(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := c.tokenService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load API tokens", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]any{
-		"Title": "Profile",
+		"Title":             "Profile",
+		"DigestFrequencies": model.DigestFrequencies,
+		"APITokens":         tokens,
+		"Scopes":            model.Scopes,
+		"TokenLifetimes":    apiTokenLifetimes,
+		"Now":               c.now(),
 	}
 	c.Template.Profile.Render(w, r, data)
 }
 
+// CreateAPIToken issues a personal API token. The token is shown once, in
+// the flash message, and cannot be read back afterwards.
+func (c *AuthHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := service.GetUser(ctx)
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	days, err := strconv.Atoi(r.FormValue("expires_in_days"))
+	if err != nil || !slices.Contains(apiTokenLifetimes, days) {
+		c.flashStore.SetErrors(ctx, []string{"Invalid token expiry"})
+		http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+		return
+	}
+
+	var scopes []model.Scope
+	for _, scope := range r.Form["scopes"] {
+		scopes = append(scopes, model.Scope(scope))
+	}
+
+	expiresAt := c.now().AddDate(0, 0, days)
+	_, plainToken, err := c.tokenService.Create(r.Context(), user.ID, r.FormValue("name"), scopes, expiresAt)
+	if err != nil {
+		c.flashStore.SetErrors(ctx, []string{err.Error()})
+		http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+		return
+	}
+
+	c.flashStore.SetSuccesses(ctx, []string{
+		"API token created. Copy it now, it will not be shown again: " + plainToken,
+	})
+	http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+}
+
+func (c *AuthHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := service.GetUser(ctx)
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.tokenService.Revoke(r.Context(), id, user.ID); err != nil {
+		message := "Failed to revoke API token"
+		if errors.Is(err, service.ErrAPITokenNotFound) {
+			message = "API token not found"
+		}
+		c.flashStore.SetErrors(ctx, []string{message})
+		http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+		return
+	}
+
+	c.flashStore.SetSuccesses(ctx, []string{"API token revoked"})
+	http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+}
+
+func (c *AuthHandler) UpdateDigestSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := service.GetUser(ctx)
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	frequency := model.DigestFrequency(r.FormValue("digest_frequency"))
+	if err := c.authService.UpdateDigestFrequency(r.Context(), user.ID, frequency); err != nil {
+		c.flashStore.SetErrors(ctx, []string{err.Error()})
+		http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+		return
+	}
+
+	c.flashStore.SetSuccesses(ctx, []string{"Digest settings updated successfully"})
+	http.Redirect(w, r, "/app/profile", http.StatusSeeOther)
+}
+
 func (c *AuthHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	if err := r.ParseForm(); err != nil {
@@ -318,7 +440,7 @@ func (c *AuthHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify current password
-	_, err := c.authService.Authenticate(user.Email, currentPassword)
+	_, err := c.authService.Authenticate(r.Context(), user.Email, currentPassword)
 	if err != nil {
 		c.flashStore.SetErrors(ctx, []string{"Current password is incorrect"})
 		http.Redirect(w, r, "/profile", http.StatusSeeOther)
@@ -326,7 +448,7 @@ func (c *AuthHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update password
-	if err := c.authService.UpdatePassword(user.ID, newPassword); err != nil {
+	if err := c.authService.UpdatePassword(r.Context(), user.ID, newPassword); err != nil {
 		c.flashStore.SetErrors(ctx, []string{err.Error()})
 		http.Redirect(w, r, "/profile", http.StatusSeeOther)
 		return
@@ -338,7 +460,7 @@ func (c *AuthHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 
 func (c *AuthHandler) redirectIfAuthenticated(w http.ResponseWriter, r *http.Request) bool {
 	if cookie, err := r.Cookie("session_token"); err == nil {
-		user, err := c.sessionService.ValidateSession(cookie.Value)
+		user, err := c.sessionService.ValidateSession(r.Context(), cookie.Value)
 
 		if err != nil {
 			http.SetCookie(w, &http.Cookie{
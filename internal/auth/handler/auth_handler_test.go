@@ -1,14 +1,17 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/auth/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
 	"github.com/shuvo-paul/uptimebot/internal/templates"
 	"github.com/shuvo-paul/uptimebot/pkg/flash"
@@ -24,47 +27,55 @@ type mockAuthService struct {
 	updatePasswordFunc func(int, string) error
 	resetPasswordFunc  func(string, string) error
 	validateTokenFunc  func(string, model.TokenType) (*model.Token, error)
+	updateDigestFunc   func(int, model.DigestFrequency) error
 }
 
-func (m *mockAuthService) CreateUser(user *model.User) (*model.User, error) {
+func (m *mockAuthService) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
 	return m.createUserFunc(user)
 }
 
-func (m *mockAuthService) Authenticate(email, password string) (*model.User, error) {
+func (m *mockAuthService) Authenticate(ctx context.Context, email, password string) (*model.User, error) {
 	return m.authenticateFunc(email, password)
 }
 
-func (m *mockAuthService) GetUserByID(id int) (*model.User, error) {
+func (m *mockAuthService) GetUserByID(ctx context.Context, id int) (*model.User, error) {
 	return m.getUserByIdFunc(id)
 }
 
-func (m *mockAuthService) GetUserByEmail(email string) (*model.User, error) {
+func (m *mockAuthService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	return m.getUserByEmailFunc(email)
 }
 
-func (m *mockAuthService) ValidateToken(token string, tokenType model.TokenType) (*model.Token, error) {
+func (m *mockAuthService) ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error) {
 	if m.validateTokenFunc != nil {
 		return m.validateTokenFunc(token, tokenType)
 	}
 	return nil, nil
 }
 
-func (m *mockAuthService) VerifyEmail(token string) error {
+func (m *mockAuthService) VerifyEmail(ctx context.Context, token string) error {
 	return nil
 }
 
-func (m *mockAuthService) SendToken(id int, email string, tokenType model.TokenType) error {
+func (m *mockAuthService) SendToken(ctx context.Context, id int, email string, tokenType model.TokenType) error {
 	return m.sendTokenFunc(id, email, tokenType)
 }
 
-func (m *mockAuthService) UpdatePassword(userID int, newPassword string) error {
+func (m *mockAuthService) UpdatePassword(ctx context.Context, userID int, newPassword string) error {
 	if m.updatePasswordFunc != nil {
 		return m.updatePasswordFunc(userID, newPassword)
 	}
 	return nil
 }
 
-func (m *mockAuthService) ResetPassword(token string, newPassword string) error {
+func (m *mockAuthService) UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error {
+	if m.updateDigestFunc != nil {
+		return m.updateDigestFunc(userID, frequency)
+	}
+	return nil
+}
+
+func (m *mockAuthService) ResetPassword(ctx context.Context, token string, newPassword string) error {
 	if m.resetPasswordFunc != nil {
 		return m.resetPasswordFunc(token, newPassword)
 	}
@@ -78,18 +89,40 @@ type mockSessionService struct {
 	validateSessionFunc func(string) (*model.Session, error)
 }
 
-func (m *mockSessionService) CreateSession(userID int) (*model.Session, string, error) {
+func (m *mockSessionService) CreateSession(ctx context.Context, userID int) (*model.Session, string, error) {
 	return m.createSessionFunc(userID)
 }
 
-func (m *mockSessionService) DeleteSession(token string) error {
+func (m *mockSessionService) DeleteSession(ctx context.Context, token string) error {
 	return m.deleteSessionFunc(token)
 }
 
-func (m *mockSessionService) ValidateSession(token string) (*model.Session, error) {
+func (m *mockSessionService) ValidateSession(ctx context.Context, token string) (*model.Session, error) {
 	return m.validateSessionFunc(token)
 }
 
+type mockAPITokenService struct {
+	createFunc func(userID int, name string, scopes []model.Scope, expiresAt time.Time) (*model.APIToken, string, error)
+	revokeFunc func(id int, userID int) error
+	tokens     []*model.APIToken
+}
+
+func (m *mockAPITokenService) Create(ctx context.Context, userID int, name string, scopes []model.Scope, expiresAt time.Time) (*model.APIToken, string, error) {
+	return m.createFunc(userID, name, scopes, expiresAt)
+}
+
+func (m *mockAPITokenService) GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error) {
+	return m.tokens, nil
+}
+
+func (m *mockAPITokenService) Revoke(ctx context.Context, id int, userID int) error {
+	return m.revokeFunc(id, userID)
+}
+
+func (m *mockAPITokenService) Authenticate(ctx context.Context, plainToken string) (*model.APIToken, error) {
+	return nil, service.ErrInvalidAPIToken
+}
+
 func TestRegister(t *testing.T) {
 	mockFlashStore := flash.NewFlashStore()
 	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
@@ -127,7 +160,7 @@ func TestRegister(t *testing.T) {
 
 			mockFlash := flash.NewFlashStore()
 
-			controller := NewAuthHandler(mockUser, mockSession, mockFlash)
+			controller := NewAuthHandler(mockUser, mockSession, mockFlash, &mockAPITokenService{})
 			controller.Template.Register = templateRenderer.GetTemplate("pages:register")
 
 			req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(tt.formData.Encode()))
@@ -187,7 +220,7 @@ func TestLogin(t *testing.T) {
 
 			mockFlash := &flash.MockFlashStore{}
 
-			controller := NewAuthHandler(mockUser, mockSession, mockFlash)
+			controller := NewAuthHandler(mockUser, mockSession, mockFlash, &mockAPITokenService{})
 			controller.Template.Login = templateRenderer.GetTemplate("pages:login")
 
 			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.formData.Encode()))
@@ -265,7 +298,7 @@ func TestSendVerificationMail(t *testing.T) {
 			mockSession := &mockSessionService{}
 			mockFlash := &flash.MockFlashStore{}
 
-			handler := NewAuthHandler(mockUser, mockSession, mockFlash)
+			handler := NewAuthHandler(mockUser, mockSession, mockFlash, &mockAPITokenService{})
 
 			req := httptest.NewRequest(http.MethodGet, "/verify-email?"+tt.queryParams, nil)
 			w := httptest.NewRecorder()
@@ -275,3 +308,178 @@ func TestSendVerificationMail(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateDigestSettings(t *testing.T) {
+	mockFlashStore := flash.NewFlashStore()
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+
+	var updated model.DigestFrequency
+	mockUser := &mockAuthService{
+		updateDigestFunc: func(userID int, frequency model.DigestFrequency) error {
+			if err := frequency.Validate(); err != nil {
+				return err
+			}
+			updated = frequency
+			return nil
+		},
+	}
+	handler := NewAuthHandler(mockUser, &mockSessionService{}, mockFlashStore, &mockAPITokenService{})
+	handler.Template.Profile = templateRenderer.GetTemplate("pages:profile")
+	user := &model.User{ID: 1, Email: "test@example.com", DigestFrequency: model.DigestDaily}
+
+	t.Run("profile shows current frequency", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/profile", nil)
+		req = req.WithContext(service.WithUser(req.Context(), user))
+		w := httptest.NewRecorder()
+
+		handler.ShowProfileForm(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d; got %d", http.StatusOK, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `<option value="daily" selected>`) {
+			t.Errorf("expected daily to be selected")
+		}
+	})
+
+	tests := []struct {
+		name      string
+		frequency string
+		expected  model.DigestFrequency
+	}{
+		{name: "opt out", frequency: "off", expected: model.DigestOff},
+		{name: "unknown frequency", frequency: "hourly", expected: model.DigestOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"digest_frequency": {tt.frequency}}
+			req := httptest.NewRequest(http.MethodPost, "/app/update-digest", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = req.WithContext(service.WithUser(req.Context(), user))
+			w := httptest.NewRecorder()
+
+			handler.UpdateDigestSettings(w, req)
+
+			if w.Code != http.StatusSeeOther {
+				t.Errorf("expected status %d; got %d", http.StatusSeeOther, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != "/app/profile" {
+				t.Errorf("expected redirect to /app/profile; got %s", location)
+			}
+			if updated != tt.expected {
+				t.Errorf("expected frequency %s; got %s", tt.expected, updated)
+			}
+		})
+	}
+}
+
+func TestAPITokens(t *testing.T) {
+	mockFlashStore := flash.NewFlashStore()
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var created *model.APIToken
+	tokens := &mockAPITokenService{
+		tokens: []*model.APIToken{
+			{ID: 7, Name: "CI", Prefix: "ubt_abcd", Scopes: []model.Scope{model.ScopeTargetsRead}, ExpiresAt: now.AddDate(0, 0, 30)},
+		},
+		createFunc: func(userID int, name string, scopes []model.Scope, expiresAt time.Time) (*model.APIToken, string, error) {
+			created = &model.APIToken{UserID: userID, Name: name, Scopes: scopes, ExpiresAt: expiresAt}
+			if err := created.Validate(now); err != nil {
+				return nil, "", err
+			}
+			return created, "ubt_secret", nil
+		},
+		revokeFunc: func(id int, userID int) error {
+			if id != 7 {
+				return service.ErrAPITokenNotFound
+			}
+			return nil
+		},
+	}
+	handler := NewAuthHandler(&mockAuthService{}, &mockSessionService{}, mockFlashStore, tokens)
+	handler.Template.Profile = templateRenderer.GetTemplate("pages:profile")
+	handler.now = func() time.Time { return now }
+	user := &model.User{ID: 1, Email: "test@example.com", DigestFrequency: model.DigestOff}
+
+	t.Run("profile lists tokens", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/profile", nil)
+		req = req.WithContext(service.WithUser(req.Context(), user))
+		w := httptest.NewRecorder()
+
+		handler.ShowProfileForm(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d; got %d", http.StatusOK, w.Code)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "ubt_abcd") || !strings.Contains(body, "/app/api-tokens/7/revoke") {
+			t.Errorf("expected the token to be listed")
+		}
+		if !strings.Contains(body, `value="notifiers:write"`) {
+			t.Errorf("expected the scopes to be offered")
+		}
+	})
+
+	createTests := []struct {
+		name    string
+		form    url.Values
+		created bool
+	}{
+		{
+			name:    "valid token",
+			form:    url.Values{"name": {"deploys"}, "scopes": {"targets:read", "targets:write"}, "expires_in_days": {"90"}},
+			created: true,
+		},
+		{
+			name: "expiry not offered",
+			form: url.Values{"name": {"deploys"}, "scopes": {"targets:read"}, "expires_in_days": {"10000"}},
+		},
+		{
+			name: "no scopes",
+			form: url.Values{"name": {"deploys"}, "expires_in_days": {"30"}},
+		},
+	}
+
+	for _, tt := range createTests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			req := httptest.NewRequest(http.MethodPost, "/app/api-tokens", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = req.WithContext(service.WithUser(req.Context(), user))
+			w := httptest.NewRecorder()
+
+			handler.CreateAPIToken(w, req)
+
+			if w.Code != http.StatusSeeOther {
+				t.Errorf("expected status %d; got %d", http.StatusSeeOther, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != "/app/profile" {
+				t.Errorf("expected redirect to /app/profile; got %s", location)
+			}
+			if tt.created {
+				if created == nil || len(created.Scopes) != 2 || !created.ExpiresAt.Equal(now.AddDate(0, 0, 90)) {
+					t.Errorf("expected a token with two scopes expiring in 90 days; got %+v", created)
+				}
+			} else if created != nil && created.Validate(now) == nil {
+				t.Errorf("expected no token to be created")
+			}
+		})
+	}
+
+	t.Run("revoke", func(t *testing.T) {
+		for _, id := range []string{"7", "8"} {
+			req := httptest.NewRequest(http.MethodPost, "/app/api-tokens/"+id+"/revoke", nil)
+			req.SetPathValue("id", id)
+			req = req.WithContext(service.WithUser(req.Context(), user))
+			w := httptest.NewRecorder()
+
+			handler.RevokeAPIToken(w, req)
+
+			if w.Code != http.StatusSeeOther {
+				t.Errorf("expected status %d; got %d", http.StatusSeeOther, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scope limits what an API token can do
+type Scope string
+
+const (
+	// ScopeTargetsRead reads targets, their check results and incidents
+	ScopeTargetsRead Scope = "targets:read"
+	// ScopeTargetsWrite creates, changes and deletes targets
+	ScopeTargetsWrite Scope = "targets:write"
+	// ScopeNotifiersRead reads notifiers
+	ScopeNotifiersRead Scope = "notifiers:read"
+	// ScopeNotifiersWrite creates, changes and deletes notifiers
+	ScopeNotifiersWrite Scope = "notifiers:write"
+)
+
+// Scopes lists every scope a token can be given
+var Scopes = []Scope{ScopeTargetsRead, ScopeTargetsWrite, ScopeNotifiersRead, ScopeNotifiersWrite}
+
+// impliedScopes maps read scopes to the write scope that includes them
+var impliedScopes = map[Scope]Scope{
+	ScopeTargetsRead:   ScopeTargetsWrite,
+	ScopeNotifiersRead: ScopeNotifiersWrite,
+}
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to spot
+const APITokenPrefix = "ubt_"
+
+// APIToken gives programs access to the API on behalf of a user. Only a hash
+// of the token is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"` // start of the token, to tell tokens apart
+	Hash       string     `db:"token_hash"`
+	Scopes     []Scope    `db:"scopes"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"` // nil until the token is first used
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// Validate checks the name, the scopes and that the token expires in the future
+func (t *APIToken) Validate(now time.Time) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("token name is required")
+	}
+	if len(t.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range t.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("unsupported scope: %s", scope)
+		}
+	}
+	if !t.ExpiresAt.After(now) {
+		return fmt.Errorf("expiry date must be in the future")
+	}
+	return nil
+}
+
+// Expired reports whether the token can no longer be used
+func (t *APIToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Allows reports whether the token grants the scope. Writing targets or
+// notifiers includes reading them.
+func (t *APIToken) Allows(scope Scope) bool {
+	if slices.Contains(t.Scopes, scope) {
+		return true
+	}
+	write, ok := impliedScopes[scope]
+	return ok && slices.Contains(t.Scopes, write)
+}
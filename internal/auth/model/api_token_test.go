@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIToken_Validate(t *testing.T) {
+	now := time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		token   APIToken
+		wantErr bool
+	}{
+		{name: "valid", token: APIToken{Name: "ci", Scopes: []Scope{ScopeTargetsRead}, ExpiresAt: now.Add(time.Hour)}},
+		{name: "missing name", token: APIToken{Name: " ", Scopes: []Scope{ScopeTargetsRead}, ExpiresAt: now.Add(time.Hour)}, wantErr: true},
+		{name: "no scopes", token: APIToken{Name: "ci", ExpiresAt: now.Add(time.Hour)}, wantErr: true},
+		{name: "unknown scope", token: APIToken{Name: "ci", Scopes: []Scope{"admin"}, ExpiresAt: now.Add(time.Hour)}, wantErr: true},
+		{name: "already expired", token: APIToken{Name: "ci", Scopes: []Scope{ScopeTargetsRead}, ExpiresAt: now}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Validate(now)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAPIToken_Allows(t *testing.T) {
+	token := APIToken{Scopes: []Scope{ScopeTargetsWrite}}
+	assert.True(t, token.Allows(ScopeTargetsWrite))
+	assert.True(t, token.Allows(ScopeTargetsRead))
+	assert.False(t, token.Allows(ScopeNotifiersWrite))
+	assert.False(t, token.Allows(ScopeNotifiersRead))
+
+	token = APIToken{Scopes: []Scope{ScopeTargetsRead}}
+	assert.False(t, token.Allows(ScopeTargetsWrite))
+
+	token = APIToken{Scopes: []Scope{ScopeNotifiersWrite}}
+	assert.True(t, token.Allows(ScopeNotifiersRead))
+
+	token = APIToken{Scopes: []Scope{ScopeNotifiersRead}}
+	assert.False(t, token.Allows(ScopeNotifiersWrite))
+}
+
+func TestAPIToken_Expired(t *testing.T) {
+	now := time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC)
+	token := APIToken{ExpiresAt: now}
+	assert.True(t, token.Expired(now))
+	assert.False(t, token.Expired(now.Add(-time.Second)))
+}
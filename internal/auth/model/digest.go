@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DigestFrequency is how often a user receives the uptime digest email
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+	DigestOff    DigestFrequency = "off"
+)
+
+// DigestFrequencies lists every digest frequency a user can choose
+var DigestFrequencies = []DigestFrequency{DigestDaily, DigestWeekly, DigestOff}
+
+// Validate checks that the frequency is known
+func (f DigestFrequency) Validate() error {
+	if !slices.Contains(DigestFrequencies, f) {
+		return fmt.Errorf("unsupported digest frequency: %s", f)
+	}
+	return nil
+}
+
+// Period returns how much time a digest covers, zero when digests are off
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestFrequency(t *testing.T) {
+	assert.NoError(t, DigestWeekly.Validate())
+	assert.ErrorContains(t, DigestFrequency("hourly").Validate(), "unsupported digest frequency")
+
+	assert.Equal(t, 24*time.Hour, DigestDaily.Period())
+	assert.Equal(t, 7*24*time.Hour, DigestWeekly.Period())
+	assert.Zero(t, DigestOff.Period())
+}
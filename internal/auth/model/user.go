@@ -7,10 +7,11 @@ import (
 )
 
 type User struct {
-	ID       int
-	Email    string
-	Password string
-	Verified bool
+	ID              int
+	Email           string
+	Password        string
+	Verified        bool
+	DigestFrequency DigestFrequency
 }
 
 func (u *User) HashPassword() error {
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+)
+
+var ErrAPITokenNotFound = errors.New("api token not found")
+
+type APITokenRepositoryInterface interface {
+	Create(ctx context.Context, token *model.APIToken) (*model.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*model.APIToken, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error)
+	Delete(ctx context.Context, id int, userID int) error
+	UpdateLastUsed(ctx context.Context, id int, at time.Time) error
+}
+
+var _ APITokenRepositoryInterface = (*APITokenRepository)(nil)
+
+type APITokenRepository struct {
+	db database.Querier
+}
+
+func NewAPITokenRepository(db database.Querier) *APITokenRepository {
+	return &APITokenRepository{db: db}
+}
+
+const apiTokenColumns = `id, user_id, name, prefix, token_hash, scopes, expires_at, last_used_at, created_at`
+
+func scanAPIToken(row interface{ Scan(...any) error }) (*model.APIToken, error) {
+	var token model.APIToken
+	var scopes []string
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.Prefix,
+		&token.Hash,
+		pq.Array(&scopes),
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		token.Scopes = append(token.Scopes, model.Scope(scope))
+	}
+	return &token, nil
+}
+
+func (r *APITokenRepository) Create(ctx context.Context, token *model.APIToken) (*model.APIToken, error) {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	query := `
+		INSERT INTO api_token (user_id, name, prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+	err := r.db.QueryRowContext(ctx, query,
+		token.UserID,
+		token.Name,
+		token.Prefix,
+		token.Hash,
+		pq.Array(scopes),
+		token.ExpiresAt.UTC(),
+		token.CreatedAt.UTC(),
+	).Scan(&token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *APITokenRepository) GetByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_token WHERE token_hash = $1`
+	token, err := scanAPIToken(r.db.QueryRowContext(ctx, query, hash))
+	if err == sql.ErrNoRows {
+		return nil, ErrAPITokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+	return token, nil
+}
+
+// GetByUserID lists the tokens of a user, newest first
+func (r *APITokenRepository) GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_token WHERE user_id = $1 ORDER BY id DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Delete revokes a token of the user
+func (r *APITokenRepository) Delete(ctx context.Context, id int, userID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM api_token WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func (r *APITokenRepository) UpdateLastUsed(ctx context.Context, id int, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_token SET last_used_at = $1 WHERE id = $2`, at.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+
+	userRepo := NewUserRepository(tx)
+	user, err := userRepo.SaveUser(context.Background(), &model.User{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	repo := NewAPITokenRepository(tx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	token, err := repo.Create(context.Background(), &model.APIToken{
+		UserID:    user.ID,
+		Name:      "ci",
+		Prefix:    "ubt_abcd",
+		Hash:      "hash",
+		Scopes:    []model.Scope{model.ScopeTargetsRead, model.ScopeNotifiersWrite},
+		ExpiresAt: now.Add(24 * time.Hour),
+		CreatedAt: now,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, token.ID)
+
+	found, err := repo.GetByHash(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", found.Name)
+	assert.Equal(t, []model.Scope{model.ScopeTargetsRead, model.ScopeNotifiersWrite}, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+
+	assert.NoError(t, repo.UpdateLastUsed(context.Background(), token.ID, now))
+	tokens, err := repo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+
+	// Tokens of other users cannot be revoked
+	assert.ErrorIs(t, repo.Delete(context.Background(), token.ID, user.ID+1), ErrAPITokenNotFound)
+	assert.NoError(t, repo.Delete(context.Background(), token.ID, user.ID))
+
+	_, err = repo.GetByHash(context.Background(), "hash")
+	assert.ErrorIs(t, err, ErrAPITokenNotFound)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+)
+
+type APITokenRepositoryMock struct {
+	CreateFunc         func(token *model.APIToken) (*model.APIToken, error)
+	GetByHashFunc      func(hash string) (*model.APIToken, error)
+	GetByUserIDFunc    func(userID int) ([]*model.APIToken, error)
+	DeleteFunc         func(id int, userID int) error
+	UpdateLastUsedFunc func(id int, at time.Time) error
+}
+
+func (m *APITokenRepositoryMock) Create(ctx context.Context, token *model.APIToken) (*model.APIToken, error) {
+	return m.CreateFunc(token)
+}
+
+func (m *APITokenRepositoryMock) GetByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	return m.GetByHashFunc(hash)
+}
+
+func (m *APITokenRepositoryMock) GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error) {
+	return m.GetByUserIDFunc(userID)
+}
+
+func (m *APITokenRepositoryMock) Delete(ctx context.Context, id int, userID int) error {
+	return m.DeleteFunc(id, userID)
+}
+
+func (m *APITokenRepositoryMock) UpdateLastUsed(ctx context.Context, id int, at time.Time) error {
+	return m.UpdateLastUsedFunc(id, at)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
 )
 
@@ -12,22 +13,22 @@ type TokenRepositoryMock struct {
 	InvalidateExistingTokensFunc func(userID int, tokenType model.TokenType) error
 }
 
-func (m *TokenRepositoryMock) SaveToken(token *model.Token) (*model.Token, error) {
+func (m *TokenRepositoryMock) SaveToken(ctx context.Context, token *model.Token) (*model.Token, error) {
 	return m.SaveTokenFunc(token)
 }
 
-func (m *TokenRepositoryMock) GetTokenByValue(token string) (*model.Token, error) {
+func (m *TokenRepositoryMock) GetTokenByValue(ctx context.Context, token string) (*model.Token, error) {
 	return m.GetTokenByValueFunc(token)
 }
 
-func (m *TokenRepositoryMock) MarkTokenUsed(tokenID int) error {
+func (m *TokenRepositoryMock) MarkTokenUsed(ctx context.Context, tokenID int) error {
 	return m.MarkTokenUsedFunc(tokenID)
 }
 
-func (m *TokenRepositoryMock) GetTokensByUserID(userID int) ([]*model.Token, error) {
+func (m *TokenRepositoryMock) GetTokensByUserID(ctx context.Context, userID int) ([]*model.Token, error) {
 	return m.GetTokensByUserIDFunc(userID)
 }
 
-func (m *TokenRepositoryMock) InvalidateExistingTokens(userID int, tokenType model.TokenType) error {
+func (m *TokenRepositoryMock) InvalidateExistingTokens(ctx context.Context, userID int, tokenType model.TokenType) error {
 	return m.InvalidateExistingTokensFunc(userID, tokenType)
 }
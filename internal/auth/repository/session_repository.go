@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -16,10 +17,10 @@ func NewSessionRepository(db database.Querier) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
-func (r *SessionRepository) Create(session *model.Session) error {
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) error {
 	query := `INSERT INTO session (user_id, token, created_at, expires_at) 
 			  VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(query, session.UserID, session.Token,
+	_, err := r.db.ExecContext(ctx, query, session.UserID, session.Token,
 		session.CreatedAt, session.ExpiresAt)
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
@@ -27,11 +28,11 @@ func (r *SessionRepository) Create(session *model.Session) error {
 	return nil
 }
 
-func (r *SessionRepository) GetByToken(token string) (*model.Session, error) {
+func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*model.Session, error) {
 	var session model.Session
 	query := `SELECT user_id, token, created_at, expires_at 
 			  FROM session WHERE token = $1`
-	err := r.db.QueryRow(query, token).Scan(&session.UserID, &session.Token,
+	err := r.db.QueryRowContext(ctx, query, token).Scan(&session.UserID, &session.Token,
 		&session.CreatedAt, &session.ExpiresAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("session not found")
@@ -42,9 +43,9 @@ func (r *SessionRepository) GetByToken(token string) (*model.Session, error) {
 	return &session, nil
 }
 
-func (r *SessionRepository) Delete(token string) error {
+func (r *SessionRepository) Delete(ctx context.Context, token string) error {
 	query := `DELETE FROM session WHERE token = $1`
-	result, err := r.db.Exec(query, token)
+	result, err := r.db.ExecContext(ctx, query, token)
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
@@ -62,9 +63,9 @@ func (r *SessionRepository) Delete(token string) error {
 }
 
 type SessionRepositoryInterface interface {
-	Create(session *model.Session) error
-	GetByToken(token string) (*model.Session, error)
-	Delete(sessionID string) error
+	Create(ctx context.Context, session *model.Session) error
+	GetByToken(ctx context.Context, token string) (*model.Session, error)
+	Delete(ctx context.Context, sessionID string) error
 }
 
 // Ensure SessionRepository implements the interface
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -16,7 +17,7 @@ func createTestUser(t *testing.T, tx database.Querier) *model.User {
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	}
-	savedUser, err := userRepo.SaveUser(user)
+	savedUser, err := userRepo.SaveUser(context.Background(), user)
 	assert.NoError(t, err)
 	return savedUser
 }
@@ -33,7 +34,7 @@ func TestSessionRepository_Create(t *testing.T) {
 		ExpiresAt: time.Now().Add(24 * time.Hour),
 	}
 
-	err := sessionRepo.Create(session)
+	err := sessionRepo.Create(context.Background(), session)
 	assert.NoError(t, err)
 }
 
@@ -50,10 +51,10 @@ func TestSessionRepository_GetByToken(t *testing.T) {
 		CreatedAt: now,
 		ExpiresAt: now.Add(24 * time.Hour),
 	}
-	err := sessionRepo.Create(expectedSession)
+	err := sessionRepo.Create(context.Background(), expectedSession)
 	assert.NoError(t, err)
 
-	session, err := sessionRepo.GetByToken("test-token")
+	session, err := sessionRepo.GetByToken(context.Background(), "test-token")
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSession.UserID, session.UserID)
 	assert.Equal(t, expectedSession.Token, session.Token)
@@ -71,13 +72,13 @@ func TestSessionRepository_Delete(t *testing.T) {
 		CreatedAt: time.Now(),
 		ExpiresAt: time.Now().Add(24 * time.Hour),
 	}
-	err := sessionRepo.Create(session)
+	err := sessionRepo.Create(context.Background(), session)
 	assert.NoError(t, err)
 
-	err = sessionRepo.Delete("token")
+	err = sessionRepo.Delete(context.Background(), "token")
 	assert.NoError(t, err)
 
-	_, err = sessionRepo.GetByToken("token")
+	_, err = sessionRepo.GetByToken(context.Background(), "token")
 	assert.Error(t, err)
 }
 
@@ -86,13 +87,13 @@ func TestSessionRepository_Errors(t *testing.T) {
 	sessionRepo := NewSessionRepository(tx)
 
 	t.Run("GetByToken Error - Non-existent Token", func(t *testing.T) {
-		session, err := sessionRepo.GetByToken("non-existent-token")
+		session, err := sessionRepo.GetByToken(context.Background(), "non-existent-token")
 		assert.Error(t, err)
 		assert.Nil(t, session)
 	})
 
 	t.Run("Delete Error - Non-existent Token", func(t *testing.T) {
-		err := sessionRepo.Delete("non-existent-token")
+		err := sessionRepo.Delete(context.Background(), "non-existent-token")
 		assert.Error(t, err)
 	})
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -17,18 +18,18 @@ func NewTokenRepository(db database.Querier) *TokenRepository {
 }
 
 type TokenRepositoryInterface interface {
-	SaveToken(token *model.Token) (*model.Token, error)
-	GetTokenByValue(token string) (*model.Token, error)
-	MarkTokenUsed(tokenID int) error
-	GetTokensByUserID(userID int) ([]*model.Token, error)
-	InvalidateExistingTokens(userID int, tokenType model.TokenType) error
+	SaveToken(ctx context.Context, token *model.Token) (*model.Token, error)
+	GetTokenByValue(ctx context.Context, token string) (*model.Token, error)
+	MarkTokenUsed(ctx context.Context, tokenID int) error
+	GetTokensByUserID(ctx context.Context, userID int) ([]*model.Token, error)
+	InvalidateExistingTokens(ctx context.Context, userID int, tokenType model.TokenType) error
 }
 
-func (r *TokenRepository) SaveToken(token *model.Token) (*model.Token, error) {
+func (r *TokenRepository) SaveToken(ctx context.Context, token *model.Token) (*model.Token, error) {
 	query := `INSERT INTO token (user_id, token, type, expires_at, used) 
 			  VALUES ($1, $2, $3, $4, $5)
 			  RETURNING id`
-	err := r.db.QueryRow(query, token.UserID, token.Token, token.Type, token.ExpiresAt, token.Used).Scan(&token.ID)
+	err := r.db.QueryRowContext(ctx, query, token.UserID, token.Token, token.Type, token.ExpiresAt, token.Used).Scan(&token.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save token: %w", err)
 	}
@@ -36,11 +37,11 @@ func (r *TokenRepository) SaveToken(token *model.Token) (*model.Token, error) {
 	return token, nil
 }
 
-func (r *TokenRepository) GetTokenByValue(tokenValue string) (*model.Token, error) {
+func (r *TokenRepository) GetTokenByValue(ctx context.Context, tokenValue string) (*model.Token, error) {
 	var token model.Token
 	query := `SELECT id, user_id, token, type, expires_at, used 
 			  FROM token WHERE token = $1`
-	err := r.db.QueryRow(query, tokenValue).Scan(
+	err := r.db.QueryRowContext(ctx, query, tokenValue).Scan(
 		&token.ID,
 		&token.UserID,
 		&token.Token,
@@ -57,9 +58,9 @@ func (r *TokenRepository) GetTokenByValue(tokenValue string) (*model.Token, erro
 	return &token, nil
 }
 
-func (r *TokenRepository) MarkTokenUsed(tokenID int) error {
+func (r *TokenRepository) MarkTokenUsed(ctx context.Context, tokenID int) error {
 	query := `UPDATE token SET used = TRUE WHERE id = $1`
-	result, err := r.db.Exec(query, tokenID)
+	result, err := r.db.ExecContext(ctx, query, tokenID)
 	if err != nil {
 		return fmt.Errorf("failed to mark token as used: %w", err)
 	}
@@ -74,10 +75,10 @@ func (r *TokenRepository) MarkTokenUsed(tokenID int) error {
 	return nil
 }
 
-func (r *TokenRepository) GetTokensByUserID(userID int) ([]*model.Token, error) {
+func (r *TokenRepository) GetTokensByUserID(ctx context.Context, userID int) ([]*model.Token, error) {
 	query := `SELECT id, user_id, token, type, expires_at, used 
 			  FROM token WHERE user_id = $1`
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get verification tokens: %w", err)
 	}
@@ -105,11 +106,11 @@ func (r *TokenRepository) GetTokensByUserID(userID int) ([]*model.Token, error)
 	return tokens, nil
 }
 
-func (r *TokenRepository) InvalidateExistingTokens(userID int, tokenType model.TokenType) error {
+func (r *TokenRepository) InvalidateExistingTokens(ctx context.Context, userID int, tokenType model.TokenType) error {
 	query := `UPDATE token 
 			  SET used = TRUE 
 			  WHERE user_id = $1 AND type = $2 AND used = FALSE AND expires_at > NOW()`
-	_, err := r.db.Exec(query, userID, string(tokenType))
+	_, err := r.db.ExecContext(ctx, query, userID, string(tokenType))
 	if err != nil {
 		return fmt.Errorf("failed to invalidate existing tokens: %w", err)
 	}
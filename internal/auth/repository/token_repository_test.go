@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -13,7 +14,7 @@ func TestVerificationTokenRepository(t *testing.T) {
 	tx := testutil.GetTestTx(t)
 
 	userRepo := NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&model.User{
+	user, err := userRepo.SaveUser(context.Background(), &model.User{
 		Email:    "test@example.com",
 		Password: "password123",
 	})
@@ -31,7 +32,7 @@ func TestVerificationTokenRepository(t *testing.T) {
 			Used:      false,
 		}
 
-		savedToken, err := repo.SaveToken(token)
+		savedToken, err := repo.SaveToken(context.Background(), token)
 		assert.NoError(t, err)
 		assert.NotZero(t, savedToken.ID)
 		assert.Equal(t, token.UserID, savedToken.UserID)
@@ -39,7 +40,7 @@ func TestVerificationTokenRepository(t *testing.T) {
 	})
 
 	t.Run("GetTokenByValue", func(t *testing.T) {
-		token, err := repo.GetTokenByValue("test-token")
+		token, err := repo.GetTokenByValue(context.Background(), "test-token")
 		assert.NoError(t, err)
 		assert.NotNil(t, token)
 		assert.Equal(t, "test-token", token.Token)
@@ -47,16 +48,16 @@ func TestVerificationTokenRepository(t *testing.T) {
 	})
 
 	t.Run("GetTokenByValue_NotFound", func(t *testing.T) {
-		token, err := repo.GetTokenByValue("non-existent-token")
+		token, err := repo.GetTokenByValue(context.Background(), "non-existent-token")
 		assert.NoError(t, err)
 		assert.Nil(t, token)
 	})
 
 	t.Run("MarkTokenUsed", func(t *testing.T) {
-		err := repo.MarkTokenUsed(1)
+		err := repo.MarkTokenUsed(context.Background(), 1)
 		assert.NoError(t, err)
 
-		token, err := repo.GetTokenByValue("test-token")
+		token, err := repo.GetTokenByValue(context.Background(), "test-token")
 		assert.NoError(t, err)
 		assert.True(t, token.Used)
 	})
@@ -70,10 +71,10 @@ func TestVerificationTokenRepository(t *testing.T) {
 			ExpiresAt: time.Now().Add(24 * time.Hour),
 			Used:      false,
 		}
-		_, err := repo.SaveToken(newToken)
+		_, err := repo.SaveToken(context.Background(), newToken)
 		assert.NoError(t, err)
 
-		tokens, err := repo.GetTokensByUserID(newToken.UserID)
+		tokens, err := repo.GetTokensByUserID(context.Background(), newToken.UserID)
 		assert.NoError(t, err)
 		assert.Len(t, tokens, 2)
 	})
@@ -86,13 +87,13 @@ func TestVerificationTokenRepository(t *testing.T) {
 			ExpiresAt: time.Now().Add(24 * time.Hour),
 			Used:      false,
 		}
-		_, err := repo.SaveToken(token)
+		_, err := repo.SaveToken(context.Background(), token)
 		assert.NoError(t, err)
 
-		err = repo.InvalidateExistingTokens(user.ID, model.TokenTypeEmailVerification)
+		err = repo.InvalidateExistingTokens(context.Background(), user.ID, model.TokenTypeEmailVerification)
 		assert.NoError(t, err)
 
-		retrievedToken, err := repo.GetTokenByValue(token.Token)
+		retrievedToken, err := repo.GetTokenByValue(context.Background(), token.Token)
 		assert.NoError(t, err)
 		assert.NotNil(t, retrievedToken)
 		assert.True(t, retrievedToken.Used)
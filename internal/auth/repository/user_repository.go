@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
@@ -15,9 +16,9 @@ func NewUserRepository(db database.Querier) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) SaveUser(user *model.User) (*model.User, error) {
+func (r *UserRepository) SaveUser(ctx context.Context, user *model.User) (*model.User, error) {
 	query := `INSERT INTO usr (email, password) VALUES ($1, $2) RETURNING id`
-	err := r.db.QueryRow(query, user.Email, user.Password).Scan(&user.ID)
+	err := r.db.QueryRowContext(ctx, query, user.Email, user.Password).Scan(&user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
@@ -25,39 +26,39 @@ func (r *UserRepository) SaveUser(user *model.User) (*model.User, error) {
 	return user, nil
 }
 
-func (r *UserRepository) EmailExists(email string) (bool, error) {
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM usr WHERE email = $1)"
-	err := r.db.QueryRow(query, email).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("error checking email existence: %w", err)
 	}
 	return exists, nil
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*model.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	user := &model.User{}
 	query := `SELECT id, email, password FROM usr WHERE email = $1`
-	err := r.db.QueryRow(query, email).Scan(&user.ID, &user.Email, &user.Password)
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	return user, nil
 }
 
-func (r *UserRepository) GetUserByID(id int) (*model.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
 	user := &model.User{}
-	query := `SELECT id, email, verified from usr WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Email, &user.Verified)
+	query := `SELECT id, email, verified, digest_frequency from usr WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Email, &user.Verified, &user.DigestFrequency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	return user, nil
 }
 
-func (r *UserRepository) UpdateUser(user *model.User) (*model.User, error) {
+func (r *UserRepository) UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
 	query := `UPDATE usr SET email = $1, verified = $2 WHERE id = $3`
-	result, err := r.db.Exec(query, user.Email, user.Verified, user.ID)
+	result, err := r.db.ExecContext(ctx, query, user.Email, user.Verified, user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
@@ -74,9 +75,9 @@ func (r *UserRepository) UpdateUser(user *model.User) (*model.User, error) {
 	return user, nil
 }
 
-func (r *UserRepository) UpdatePassword(userID int, hashedPassword string) error {
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, hashedPassword string) error {
 	query := `UPDATE usr SET password = $1 WHERE id = $2`
-	result, err := r.db.Exec(query, hashedPassword, userID)
+	result, err := r.db.ExecContext(ctx, query, hashedPassword, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
@@ -93,13 +94,34 @@ func (r *UserRepository) UpdatePassword(userID int, hashedPassword string) error
 	return nil
 }
 
+// UpdateDigestFrequency sets how often the user receives the uptime digest
+func (r *UserRepository) UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error {
+	query := `UPDATE usr SET digest_frequency = $1 WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, frequency, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update digest frequency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no user found with ID: %d", userID)
+	}
+
+	return nil
+}
+
 type UserRepositoryInterface interface {
-	SaveUser(user *model.User) (*model.User, error)
-	EmailExists(email string) (bool, error)
-	GetUserByEmail(email string) (*model.User, error)
-	GetUserByID(id int) (*model.User, error)
-	UpdateUser(user *model.User) (*model.User, error)
-	UpdatePassword(userID int, hashedPassword string) error
+	SaveUser(ctx context.Context, user *model.User) (*model.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
+	UpdateUser(ctx context.Context, user *model.User) (*model.User, error)
+	UpdatePassword(ctx context.Context, userID int, hashedPassword string) error
+	UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error
 }
 
 var _ UserRepositoryInterface = (*UserRepository)(nil)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
@@ -19,7 +20,7 @@ func TestSaveUser(t *testing.T) {
 		Password: "hashedpassword",
 	}
 
-	savedUser, err := userRepo.SaveUser(user)
+	savedUser, err := userRepo.SaveUser(context.Background(), user)
 
 	assert.NoError(t, err)
 	assert.NotZero(t, savedUser.ID)
@@ -38,17 +39,17 @@ func TestEmailExists(t *testing.T) {
 		Email:    "existing@example.com",
 		Password: "hashedpassword",
 	}
-	_, err := userRepo.SaveUser(user)
+	_, err := userRepo.SaveUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	t.Run("email exists", func(t *testing.T) {
-		exists, err := userRepo.EmailExists("existing@example.com")
+		exists, err := userRepo.EmailExists(context.Background(), "existing@example.com")
 		assert.NoError(t, err)
 		assert.True(t, exists)
 	})
 
 	t.Run("email does not exist", func(t *testing.T) {
-		exists, err := userRepo.EmailExists("nonexistent@example.com")
+		exists, err := userRepo.EmailExists(context.Background(), "nonexistent@example.com")
 		assert.NoError(t, err)
 		assert.False(t, exists)
 	})
@@ -64,12 +65,12 @@ func TestGetUser(t *testing.T) {
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	}
-	savedUser, err := userRepo.SaveUser(expectedUser)
+	savedUser, err := userRepo.SaveUser(context.Background(), expectedUser)
 	assert.NoError(t, err)
 	expectedUser.ID = savedUser.ID
 
 	t.Run("By Email: user found", func(t *testing.T) {
-		user, err := userRepo.GetUserByEmail(expectedUser.Email)
+		user, err := userRepo.GetUserByEmail(context.Background(), expectedUser.Email)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUser.ID, user.ID)
 		assert.Equal(t, expectedUser.Email, user.Email)
@@ -77,7 +78,7 @@ func TestGetUser(t *testing.T) {
 	})
 
 	t.Run("By ID", func(t *testing.T) {
-		user, err := userRepo.GetUserByID(expectedUser.ID)
+		user, err := userRepo.GetUserByID(context.Background(), expectedUser.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUser.ID, user.ID)
 		assert.Equal(t, expectedUser.Email, user.Email)
@@ -95,12 +96,12 @@ func TestUpdateUser(t *testing.T) {
 		Verified: false,
 	}
 
-	savedUser, err := userRepo.SaveUser(expectedUser)
+	savedUser, err := userRepo.SaveUser(context.Background(), expectedUser)
 	assert.NoError(t, err)
 	expectedUser.ID = savedUser.ID
 	expectedUser.Email = "updated@example.org"
 	expectedUser.Verified = true
-	updatedUser, err := userRepo.UpdateUser(expectedUser)
+	updatedUser, err := userRepo.UpdateUser(context.Background(), expectedUser)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.ID, updatedUser.ID)
 	assert.Equal(t, expectedUser.Email, updatedUser.Email)
@@ -116,21 +117,46 @@ func TestUpdatePassword(t *testing.T) {
 		Email:    "test@example.com",
 		Password: "oldpassword",
 	}
-	savedUser, err := userRepo.SaveUser(user)
+	savedUser, err := userRepo.SaveUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	t.Run("successful password update", func(t *testing.T) {
-		err := userRepo.UpdatePassword(savedUser.ID, "newhashedpassword")
+		err := userRepo.UpdatePassword(context.Background(), savedUser.ID, "newhashedpassword")
 		assert.NoError(t, err)
 
 		// Verify password was updated
-		updatedUser, err := userRepo.GetUserByEmail(user.Email)
+		updatedUser, err := userRepo.GetUserByEmail(context.Background(), user.Email)
 		assert.NoError(t, err)
 		assert.Equal(t, "newhashedpassword", updatedUser.Password)
 	})
 
 	t.Run("non-existent user", func(t *testing.T) {
-		err := userRepo.UpdatePassword(9999, "newpassword")
+		err := userRepo.UpdatePassword(context.Background(), 9999, "newpassword")
 		assert.Error(t, err)
 	})
 }
+
+func TestUpdateDigestFrequency(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	userRepo := NewUserRepository(tx)
+	savedUser, err := userRepo.SaveUser(context.Background(), &model.User{Email: "digest@example.com", Password: "password"})
+	assert.NoError(t, err)
+
+	t.Run("defaults to weekly", func(t *testing.T) {
+		user, err := userRepo.GetUserByID(context.Background(), savedUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.DigestWeekly, user.DigestFrequency)
+	})
+
+	t.Run("successful update", func(t *testing.T) {
+		assert.NoError(t, userRepo.UpdateDigestFrequency(context.Background(), savedUser.ID, model.DigestOff))
+
+		user, err := userRepo.GetUserByID(context.Background(), savedUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.DigestOff, user.DigestFrequency)
+	})
+
+	t.Run("non-existent user", func(t *testing.T) {
+		assert.Error(t, userRepo.UpdateDigestFrequency(context.Background(), 9999, model.DigestDaily))
+	})
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/auth/repository"
+)
+
+var (
+	// ErrInvalidAPIToken is returned when a token is unknown, revoked or expired
+	ErrInvalidAPIToken = errors.New("invalid api token")
+	// ErrAPITokenNotFound is returned when revoking a token the user does not have
+	ErrAPITokenNotFound = errors.New("api token not found")
+	// ErrInvalidAPITokenInput is returned when a token cannot be created as requested
+	ErrInvalidAPITokenInput = errors.New("invalid api token input")
+)
+
+// lastUsedPrecision bounds how often using a token is written to the database
+const lastUsedPrecision = time.Minute
+
+// apiTokenPrefixLength is how much of a token is kept to tell tokens apart
+const apiTokenPrefixLength = 8
+
+type APITokenServiceInterface interface {
+	Create(ctx context.Context, userID int, name string, scopes []model.Scope, expiresAt time.Time) (*model.APIToken, string, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error)
+	Revoke(ctx context.Context, id int, userID int) error
+	Authenticate(ctx context.Context, plainToken string) (*model.APIToken, error)
+}
+
+var _ APITokenServiceInterface = (*APITokenService)(nil)
+
+type APITokenService struct {
+	repo repository.APITokenRepositoryInterface
+	now  func() time.Time
+}
+
+func NewAPITokenService(repo repository.APITokenRepositoryInterface) *APITokenService {
+	return &APITokenService{repo: repo, now: time.Now}
+}
+
+// hashAPIToken returns the stored form of a token. Tokens are random and
+// long, so a plain SHA-256 is enough to keep them from being read back.
+func hashAPIToken(plainToken string) string {
+	sum := sha256.Sum256([]byte(plainToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create issues a new token. The plain token is returned only here; the
+// database keeps its hash.
+func (s *APITokenService) Create(ctx context.Context, userID int, name string, scopes []model.Scope, expiresAt time.Time) (*model.APIToken, string, error) {
+	now := s.now()
+	token := &model.APIToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := token.Validate(now); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidAPITokenInput, err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	plainToken := model.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	token.Prefix = plainToken[:apiTokenPrefixLength]
+	token.Hash = hashAPIToken(plainToken)
+
+	created, err := s.repo.Create(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, plainToken, nil
+}
+
+func (s *APITokenService) GetByUserID(ctx context.Context, userID int) ([]*model.APIToken, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, id int, userID int) error {
+	err := s.repo.Delete(ctx, id, userID)
+	if errors.Is(err, repository.ErrAPITokenNotFound) {
+		return ErrAPITokenNotFound
+	}
+	return err
+}
+
+// Authenticate returns the token matching a plain token, if it has not
+// expired, and records that it was used
+func (s *APITokenService) Authenticate(ctx context.Context, plainToken string) (*model.APIToken, error) {
+	if !strings.HasPrefix(plainToken, model.APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	token, err := s.repo.GetByHash(ctx, hashAPIToken(plainToken))
+	if errors.Is(err, repository.ErrAPITokenNotFound) {
+		return nil, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if token.Expired(now) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	// Busy clients would otherwise write on every request
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		if err := s.repo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			slog.Error("Failed to record api token use", "token", token.ID, "error", err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
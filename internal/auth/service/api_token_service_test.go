@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	mockRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenService_Create(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	var stored *model.APIToken
+	repo := &mockRepo.APITokenRepositoryMock{
+		CreateFunc: func(token *model.APIToken) (*model.APIToken, error) {
+			token.ID = 1
+			stored = token
+			return token, nil
+		},
+	}
+	service := NewAPITokenService(repo)
+	service.now = func() time.Time { return now }
+
+	t.Run("stores only the hash", func(t *testing.T) {
+		token, plainToken, err := service.Create(context.Background(), 1, " CI ", []model.Scope{model.ScopeTargetsRead}, now.AddDate(0, 0, 30))
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plainToken, model.APITokenPrefix))
+		assert.Equal(t, "CI", token.Name)
+		assert.Equal(t, plainToken[:8], token.Prefix)
+		assert.Equal(t, hashAPIToken(plainToken), stored.Hash)
+		assert.NotContains(t, stored.Hash, plainToken)
+	})
+
+	t.Run("each token is different", func(t *testing.T) {
+		_, first, err := service.Create(context.Background(), 1, "a", []model.Scope{model.ScopeTargetsRead}, now.AddDate(0, 0, 30))
+		assert.NoError(t, err)
+		_, second, err := service.Create(context.Background(), 1, "b", []model.Scope{model.ScopeTargetsRead}, now.AddDate(0, 0, 30))
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		_, _, err := service.Create(context.Background(), 1, "CI", nil, now.AddDate(0, 0, 30))
+		assert.ErrorIs(t, err, ErrInvalidAPITokenInput)
+
+		_, _, err = service.Create(context.Background(), 1, "CI", []model.Scope{model.ScopeTargetsRead}, now.Add(-time.Hour))
+		assert.ErrorIs(t, err, ErrInvalidAPITokenInput)
+	})
+}
+
+func TestAPITokenService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+	tokens := map[string]*model.APIToken{
+		hashAPIToken("ubt_valid"):   {ID: 1, UserID: 1, ExpiresAt: now.Add(time.Hour)},
+		hashAPIToken("ubt_recent"):  {ID: 2, UserID: 1, ExpiresAt: now.Add(time.Hour), LastUsedAt: &recent},
+		hashAPIToken("ubt_expired"): {ID: 3, UserID: 1, ExpiresAt: now.Add(-time.Hour)},
+	}
+	var touched []int
+	repo := &mockRepo.APITokenRepositoryMock{
+		GetByHashFunc: func(hash string) (*model.APIToken, error) {
+			token, ok := tokens[hash]
+			if !ok {
+				return nil, mockRepo.ErrAPITokenNotFound
+			}
+			copied := *token
+			return &copied, nil
+		},
+		UpdateLastUsedFunc: func(id int, at time.Time) error {
+			touched = append(touched, id)
+			return nil
+		},
+	}
+	service := NewAPITokenService(repo)
+	service.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+		touched bool
+	}{
+		{name: "valid token", token: "ubt_valid", touched: true},
+		{name: "recently used token", token: "ubt_recent"},
+		{name: "expired token", token: "ubt_expired", wantErr: true},
+		{name: "unknown token", token: "ubt_unknown", wantErr: true},
+		{name: "not a token", token: "session", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			touched = nil
+
+			token, err := service.Authenticate(context.Background(), tt.token)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAPIToken)
+				assert.Nil(t, token)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, token.UserID)
+			}
+			assert.Equal(t, tt.touched, len(touched) == 1)
+		})
+	}
+}
+
+func TestAPITokenService_Revoke(t *testing.T) {
+	repo := &mockRepo.APITokenRepositoryMock{
+		DeleteFunc: func(id int, userID int) error {
+			if id != 1 || userID != 1 {
+				return mockRepo.ErrAPITokenNotFound
+			}
+			return nil
+		},
+	}
+	service := NewAPITokenService(repo)
+
+	assert.NoError(t, service.Revoke(context.Background(), 1, 1))
+	assert.ErrorIs(t, service.Revoke(context.Background(), 1, 2), ErrAPITokenNotFound)
+}
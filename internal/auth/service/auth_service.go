@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,15 +10,16 @@ import (
 )
 
 type AuthServiceInterface interface {
-	CreateUser(*model.User) (*model.User, error)
-	Authenticate(string, string) (*model.User, error)
-	GetUserByID(int) (*model.User, error)
-	GetUserByEmail(string) (*model.User, error)
-	VerifyEmail(token string) error
-	SendToken(userID int, email string, tokenType model.TokenType) error
-	UpdatePassword(userID int, newPassword string) error
-	ResetPassword(token string, newPassword string) error
-	ValidateToken(token string, tokenType model.TokenType) (*model.Token, error)
+	CreateUser(context.Context, *model.User) (*model.User, error)
+	Authenticate(context.Context, string, string) (*model.User, error)
+	GetUserByID(context.Context, int) (*model.User, error)
+	GetUserByEmail(context.Context, string) (*model.User, error)
+	VerifyEmail(ctx context.Context, token string) error
+	SendToken(ctx context.Context, userID int, email string, tokenType model.TokenType) error
+	UpdatePassword(ctx context.Context, userID int, newPassword string) error
+	UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error)
 }
 
 var _ AuthServiceInterface = (*AuthService)(nil)
@@ -37,8 +39,8 @@ func NewAuthService(
 	}
 }
 
-func (s *AuthService) CreateUser(user *model.User) (*model.User, error) {
-	if exists, err := s.repo.EmailExists(user.Email); err != nil {
+func (s *AuthService) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	if exists, err := s.repo.EmailExists(ctx, user.Email); err != nil {
 		return nil, fmt.Errorf("error checking email existence: %w", err)
 	} else if exists {
 		return nil, fmt.Errorf("email already exists")
@@ -54,20 +56,20 @@ func (s *AuthService) CreateUser(user *model.User) (*model.User, error) {
 
 	user.Verified = false
 
-	createdUser, err := s.repo.SaveUser(user)
+	createdUser, err := s.repo.SaveUser(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("error saving user: %w", err)
 	}
 
-	if err := s.tokenService.SendToken(createdUser.ID, createdUser.Email, model.TokenTypeEmailVerification, "Verify Your Email Address", "verify-email", 24*time.Hour); err != nil {
+	if err := s.tokenService.SendToken(ctx, createdUser.ID, createdUser.Email, model.TokenTypeEmailVerification, "Verify Your Email Address", "verify-email", 24*time.Hour); err != nil {
 		fmt.Printf("Failed to send verification email: %v\n", err)
 	}
 
 	return createdUser, nil
 }
 
-func (s *AuthService) Authenticate(email, password string) (*model.User, error) {
-	user, err := s.repo.GetUserByEmail(email)
+func (s *AuthService) Authenticate(ctx context.Context, email, password string) (*model.User, error) {
+	user, err := s.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
@@ -79,35 +81,35 @@ func (s *AuthService) Authenticate(email, password string) (*model.User, error)
 	return user, nil
 }
 
-func (s *AuthService) GetUserByID(id int) (*model.User, error) {
-	return s.repo.GetUserByID(id)
+func (s *AuthService) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	return s.repo.GetUserByID(ctx, id)
 }
 
-func (s *AuthService) GetUserByEmail(email string) (*model.User, error) {
-	return s.repo.GetUserByEmail(email)
+func (s *AuthService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return s.repo.GetUserByEmail(ctx, email)
 }
 
-func (s *AuthService) VerifyEmail(token string) error {
-	accountToken, err := s.tokenService.ValidateToken(token, model.TokenTypeEmailVerification)
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	accountToken, err := s.tokenService.ValidateToken(ctx, token, model.TokenTypeEmailVerification)
 	if err != nil {
 		return fmt.Errorf("invalid verification token: %w", err)
 	}
 
-	user, err := s.repo.GetUserByID(accountToken.UserID)
+	user, err := s.repo.GetUserByID(ctx, accountToken.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 
 	user.Verified = true
-	_, err = s.repo.UpdateUser(user)
+	_, err = s.repo.UpdateUser(ctx, user)
 	if err != nil {
 		return fmt.Errorf("failed to update user verification status: %w", err)
 	}
 
-	return s.tokenService.MarkTokenAsUsed(accountToken.ID)
+	return s.tokenService.MarkTokenAsUsed(ctx, accountToken.ID)
 }
 
-func (s *AuthService) SendToken(userID int, email string, tokenType model.TokenType) error {
+func (s *AuthService) SendToken(ctx context.Context, userID int, email string, tokenType model.TokenType) error {
 	var subject string
 	var path string
 
@@ -121,10 +123,10 @@ func (s *AuthService) SendToken(userID int, email string, tokenType model.TokenT
 	default:
 		return fmt.Errorf("unsupported token type")
 	}
-	return s.tokenService.SendToken(userID, email, tokenType, subject, path, 24*time.Hour)
+	return s.tokenService.SendToken(ctx, userID, email, tokenType, subject, path, 24*time.Hour)
 }
 
-func (s *AuthService) UpdatePassword(userID int, newPassword string) error {
+func (s *AuthService) UpdatePassword(ctx context.Context, userID int, newPassword string) error {
 	user := &model.User{Password: newPassword}
 	if err := user.ValidatePassword(); err != nil {
 		return fmt.Errorf("invalid password: %w", err)
@@ -134,28 +136,40 @@ func (s *AuthService) UpdatePassword(userID int, newPassword string) error {
 		return fmt.Errorf("error hashing password: %w", err)
 	}
 
-	if err := s.repo.UpdatePassword(userID, user.Password); err != nil {
+	if err := s.repo.UpdatePassword(ctx, userID, user.Password); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
 	return nil
 }
 
-func (s *AuthService) ResetPassword(token string, newPassword string) error {
-	accountToken, err := s.tokenService.ValidateToken(token, model.TokenTypePasswordReset)
+func (s *AuthService) UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error {
+	if err := frequency.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateDigestFrequency(ctx, userID, frequency); err != nil {
+		return fmt.Errorf("failed to update digest frequency: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	accountToken, err := s.tokenService.ValidateToken(ctx, token, model.TokenTypePasswordReset)
 	if err != nil {
 		return fmt.Errorf("invalid password reset token: %w", err)
 	}
 
-	err = s.UpdatePassword(accountToken.UserID, newPassword)
+	err = s.UpdatePassword(ctx, accountToken.UserID, newPassword)
 	if err != nil {
 		return err
 	}
-	return s.tokenService.MarkTokenAsUsed(accountToken.ID)
+	return s.tokenService.MarkTokenAsUsed(ctx, accountToken.ID)
 }
 
-func (s *AuthService) ValidateToken(token string, tokenType model.TokenType) (*model.Token, error) {
-	accountToken, err := s.tokenService.ValidateToken(token, tokenType)
+func (s *AuthService) ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error) {
+	accountToken, err := s.tokenService.ValidateToken(ctx, token, tokenType)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -17,32 +18,38 @@ type mockUserRepository struct {
 	getUserByIdFunc    func(id int) (*model.User, error)
 	updateUserFunc     func(user *model.User) (*model.User, error)
 	updatePasswordFunc func(userID int, hashedPassword string) error
+
+	updateDigestFrequencyFunc func(userID int, frequency model.DigestFrequency) error
 }
 
-func (m *mockUserRepository) SaveUser(user *model.User) (*model.User, error) {
+func (m *mockUserRepository) SaveUser(ctx context.Context, user *model.User) (*model.User, error) {
 	return m.saveUserFunc(user)
 }
 
-func (m *mockUserRepository) EmailExists(email string) (bool, error) {
+func (m *mockUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	return m.emailExistsFunc(email)
 }
 
-func (m *mockUserRepository) GetUserByEmail(email string) (*model.User, error) {
+func (m *mockUserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	return m.getUserByEmailFunc(email)
 }
 
-func (m *mockUserRepository) GetUserByID(id int) (*model.User, error) {
+func (m *mockUserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
 	return m.getUserByIdFunc(id)
 }
 
-func (m *mockUserRepository) UpdateUser(user *model.User) (*model.User, error) {
+func (m *mockUserRepository) UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
 	return m.updateUserFunc(user)
 }
 
-func (m *mockUserRepository) UpdatePassword(userID int, hashedPassword string) error {
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID int, hashedPassword string) error {
 	return m.updatePasswordFunc(userID, hashedPassword)
 }
 
+func (m *mockUserRepository) UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error {
+	return m.updateDigestFrequencyFunc(userID, frequency)
+}
+
 // MockTokenService is a mock implementation of TokenServiceInterface
 type mockTokenService struct {
 	validateTokenFunc   func(token string, tokenType model.TokenType) (*model.Token, error)
@@ -50,26 +57,26 @@ type mockTokenService struct {
 	markTokenAsUsedFunc func(tokenID int) error
 }
 
-func (m *mockTokenService) createToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
+func (m *mockTokenService) createToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
 	return nil, nil
 }
 
-func (m *mockTokenService) ValidateToken(token string, tokenType model.TokenType) (*model.Token, error) {
+func (m *mockTokenService) ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error) {
 	return m.validateTokenFunc(token, tokenType)
 }
 
-func (m *mockTokenService) invalidateAndCreateNewToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
+func (m *mockTokenService) invalidateAndCreateNewToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
 	return nil, nil
 }
 
-func (m *mockTokenService) SendToken(userID int, email string, tokenType model.TokenType, subject string, path string, expiresIn time.Duration) error {
+func (m *mockTokenService) SendToken(ctx context.Context, userID int, email string, tokenType model.TokenType, subject string, path string, expiresIn time.Duration) error {
 	if m.sendTokenFunc != nil {
 		return m.sendTokenFunc(userID, email, tokenType, subject, path, expiresIn)
 	}
 	return nil
 }
 
-func (m *mockTokenService) MarkTokenAsUsed(tokenID int) error {
+func (m *mockTokenService) MarkTokenAsUsed(ctx context.Context, tokenID int) error {
 	if m.markTokenAsUsedFunc != nil {
 		return m.markTokenAsUsedFunc(tokenID)
 	}
@@ -99,7 +106,7 @@ func TestCreateUser(t *testing.T) {
 			Email:    "test@example.com",
 			Password: "Password123@",
 		}
-		savedUser, err := userService.CreateUser(user)
+		savedUser, err := userService.CreateUser(context.Background(), user)
 		assert.NoError(t, err)
 		assert.Equal(t, savedUser.ID, 1)
 	})
@@ -113,7 +120,7 @@ func TestCreateUser(t *testing.T) {
 			Email:    "test@example.com",
 			Password: "password123",
 		}
-		_, err := userService.CreateUser(user)
+		_, err := userService.CreateUser(context.Background(), user)
 		assert.Error(t, err)
 	})
 
@@ -144,12 +151,12 @@ func TestVerifyEmail(t *testing.T) {
 	userService := NewAuthService(mockRepo, mockTokenService)
 
 	t.Run("Email verified successfully", func(t *testing.T) {
-		err := userService.VerifyEmail("valid_token")
+		err := userService.VerifyEmail(context.Background(), "valid_token")
 		assert.NoError(t, err)
 	})
 
 	t.Run("Invalid token", func(t *testing.T) {
-		err := userService.VerifyEmail("invalid_token")
+		err := userService.VerifyEmail(context.Background(), "invalid_token")
 		assert.Error(t, err)
 	})
 }
@@ -178,13 +185,13 @@ func TestAuthentication(t *testing.T) {
 	userService := NewAuthService(mockRepo, mockTokenService)
 
 	t.Run("Logged in succesfully", func(t *testing.T) {
-		user, err := userService.Authenticate(email, password)
+		user, err := userService.Authenticate(context.Background(), email, password)
 		assert.NoError(t, err)
 		assert.Equal(t, user.Email, email)
 	})
 
 	t.Run("Login failed", func(t *testing.T) {
-		_, err := userService.Authenticate(email, wrongPassword)
+		_, err := userService.Authenticate(context.Background(), email, wrongPassword)
 		assert.Error(t, err)
 	})
 }
@@ -200,7 +207,7 @@ func TestValidateToken(t *testing.T) {
 		}
 		userService := NewAuthService(mockRepo, mockTokenService)
 
-		token, err := userService.ValidateToken("valid_token", model.TokenTypeEmailVerification)
+		token, err := userService.ValidateToken(context.Background(), "valid_token", model.TokenTypeEmailVerification)
 		assert.NoError(t, err)
 		assert.NotNil(t, token)
 		assert.Equal(t, "valid_token", token.Token)
@@ -214,7 +221,7 @@ func TestValidateToken(t *testing.T) {
 		}
 		userService := NewAuthService(mockRepo, mockTokenService)
 
-		token, err := userService.ValidateToken("invalid_token", model.TokenTypeEmailVerification)
+		token, err := userService.ValidateToken(context.Background(), "invalid_token", model.TokenTypeEmailVerification)
 		assert.Error(t, err)
 		assert.Nil(t, token)
 	})
@@ -227,8 +234,26 @@ func TestValidateToken(t *testing.T) {
 		}
 		userService := NewAuthService(mockRepo, mockTokenService)
 
-		token, err := userService.ValidateToken("expired_token", model.TokenTypeEmailVerification)
+		token, err := userService.ValidateToken(context.Background(), "expired_token", model.TokenTypeEmailVerification)
 		assert.Error(t, err)
 		assert.Nil(t, token)
 	})
 }
+
+func TestUpdateDigestFrequency(t *testing.T) {
+	var updated model.DigestFrequency
+	mockRepo := &mockUserRepository{
+		updateDigestFrequencyFunc: func(userID int, frequency model.DigestFrequency) error {
+			updated = frequency
+			return nil
+		},
+	}
+	service := NewAuthService(mockRepo, &mockTokenService{})
+
+	assert.NoError(t, service.UpdateDigestFrequency(context.Background(), 1, model.DigestDaily))
+	assert.Equal(t, model.DigestDaily, updated)
+
+	err := service.UpdateDigestFrequency(context.Background(), 1, "hourly")
+	assert.ErrorContains(t, err, "unsupported digest frequency")
+	assert.Equal(t, model.DigestDaily, updated)
+}
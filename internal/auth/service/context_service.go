@@ -8,7 +8,10 @@ import (
 
 type contextKey string
 
-const userKey contextKey = "user"
+const (
+	userKey     contextKey = "user"
+	apiTokenKey contextKey = "api_token"
+)
 
 func WithUser(ctx context.Context, user *model.User) context.Context {
 	return context.WithValue(ctx, userKey, user)
@@ -18,3 +21,13 @@ func GetUser(ctx context.Context) (*model.User, bool) {
 	user, ok := ctx.Value(userKey).(*model.User)
 	return user, ok
 }
+
+// WithAPIToken records the API token a request was authenticated with
+func WithAPIToken(ctx context.Context, token *model.APIToken) context.Context {
+	return context.WithValue(ctx, apiTokenKey, token)
+}
+
+func GetAPIToken(ctx context.Context) (*model.APIToken, bool) {
+	token, ok := ctx.Value(apiTokenKey).(*model.APIToken)
+	return token, ok
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,9 +11,9 @@ import (
 )
 
 type SessionServiceInterface interface {
-	CreateSession(userID int) (*model.Session, string, error)
-	ValidateSession(token string) (*model.Session, error)
-	DeleteSession(sessionID string) error
+	CreateSession(ctx context.Context, userID int) (*model.Session, string, error)
+	ValidateSession(ctx context.Context, token string) (*model.Session, error)
+	DeleteSession(ctx context.Context, sessionID string) error
 }
 
 var _ SessionServiceInterface = (*SessionService)(nil)
@@ -25,7 +26,7 @@ func NewSessionService(sessionRepo repository.SessionRepositoryInterface) *Sessi
 	return &SessionService{sessionRepo: sessionRepo}
 }
 
-func (s *SessionService) CreateSession(userID int) (*model.Session, string, error) {
+func (s *SessionService) CreateSession(ctx context.Context, userID int) (*model.Session, string, error) {
 	// Generate a unique token
 	plainToken := uuid.New().String()
 
@@ -36,28 +37,28 @@ func (s *SessionService) CreateSession(userID int) (*model.Session, string, erro
 		ExpiresAt: time.Now().Add(24 * time.Hour),
 	}
 
-	if err := s.sessionRepo.Create(session); err != nil {
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
 		return nil, "", err
 	}
 
 	return session, plainToken, nil
 }
 
-func (s *SessionService) ValidateSession(token string) (*model.Session, error) {
+func (s *SessionService) ValidateSession(ctx context.Context, token string) (*model.Session, error) {
 
-	session, err := s.sessionRepo.GetByToken(token)
+	session, err := s.sessionRepo.GetByToken(ctx, token)
 	if err != nil {
 		return nil, err
 	}
 
 	if session.ExpiresAt.Before(time.Now()) {
-		s.sessionRepo.Delete(session.Token)
+		s.sessionRepo.Delete(ctx, session.Token)
 		return nil, fmt.Errorf("session has expired")
 	}
 
 	return session, nil
 }
 
-func (s *SessionService) DeleteSession(token string) error {
-	return s.sessionRepo.Delete(token)
+func (s *SessionService) DeleteSession(ctx context.Context, token string) error {
+	return s.sessionRepo.Delete(ctx, token)
 }
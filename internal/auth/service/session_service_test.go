@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -14,15 +15,15 @@ type mockSessionRepository struct {
 	deleteFunc     func(token string) error
 }
 
-func (m *mockSessionRepository) Create(session *model.Session) error {
+func (m *mockSessionRepository) Create(ctx context.Context, session *model.Session) error {
 	return m.createFunc(session)
 }
 
-func (m *mockSessionRepository) GetByToken(token string) (*model.Session, error) {
+func (m *mockSessionRepository) GetByToken(ctx context.Context, token string) (*model.Session, error) {
 	return m.getByTokenFunc(token)
 }
 
-func (m *mockSessionRepository) Delete(token string) error {
+func (m *mockSessionRepository) Delete(ctx context.Context, token string) error {
 	return m.deleteFunc(token)
 }
 
@@ -35,7 +36,7 @@ func TestCreateSession(t *testing.T) {
 	service := NewSessionService(mockRepo)
 
 	// Test
-	session, plainToken, err := service.CreateSession(1)
+	session, plainToken, err := service.CreateSession(context.Background(), 1)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -64,7 +65,7 @@ func TestValidateSession(t *testing.T) {
 		}
 		service := NewSessionService(mockRepo)
 
-		session, err := service.ValidateSession("token")
+		session, err := service.ValidateSession(context.Background(), "token")
 		assert.NoError(t, err)
 		assert.NotNil(t, session)
 		assert.Equal(t, validSession, session)
@@ -92,7 +93,7 @@ func TestValidateSession(t *testing.T) {
 
 		service := NewSessionService(mockRepo)
 
-		session, err := service.ValidateSession("expired_token")
+		session, err := service.ValidateSession(context.Background(), "expired_token")
 		assert.Error(t, err)
 		assert.Nil(t, session)
 		assert.Contains(t, err.Error(), "session has expired")
@@ -110,7 +111,7 @@ func TestDeleteSession(t *testing.T) {
 	}
 	service := NewSessionService(mockRepo)
 	testToken := "test_token"
-	err := service.DeleteSession(testToken)
+	err := service.DeleteSession(context.Background(), testToken)
 	assert.NoError(t, err)
 	assert.Equal(t, testToken, capturedToken)
 }
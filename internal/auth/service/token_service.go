@@ -2,6 +2,7 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"time"
@@ -34,17 +35,17 @@ func NewTokenService(
 }
 
 type TokenServiceInterface interface {
-	createToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error)
-	ValidateToken(token string, tokenType model.TokenType) (*model.Token, error)
-	invalidateAndCreateNewToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error)
-	SendToken(userID int, email string, tokenType model.TokenType, subject string, path string, expiresIn time.Duration) error
-	MarkTokenAsUsed(tokenID int) error
+	createToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error)
+	ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error)
+	invalidateAndCreateNewToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error)
+	SendToken(ctx context.Context, userID int, email string, tokenType model.TokenType, subject string, path string, expiresIn time.Duration) error
+	MarkTokenAsUsed(ctx context.Context, tokenID int) error
 }
 
 // Ensure TokenService implements TokenServiceInterface
 var _ TokenServiceInterface = (*TokenService)(nil)
 
-func (s *TokenService) createToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
+func (s *TokenService) createToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
 	token := &model.Token{
 		UserID:    userID,
 		Token:     uuid.New().String(),
@@ -53,11 +54,11 @@ func (s *TokenService) createToken(userID int, tokenType model.TokenType, expire
 		Used:      false,
 	}
 
-	return s.tokenRepo.SaveToken(token)
+	return s.tokenRepo.SaveToken(ctx, token)
 }
 
-func (s *TokenService) ValidateToken(token string, tokenType model.TokenType) (*model.Token, error) {
-	vToken, err := s.tokenRepo.GetTokenByValue(token)
+func (s *TokenService) ValidateToken(ctx context.Context, token string, tokenType model.TokenType) (*model.Token, error) {
+	vToken, err := s.tokenRepo.GetTokenByValue(ctx, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
@@ -77,19 +78,19 @@ func (s *TokenService) ValidateToken(token string, tokenType model.TokenType) (*
 }
 
 // MarkTokenAsUsed marks a token as used in the repository
-func (s *TokenService) MarkTokenAsUsed(tokenID int) error {
-	if err := s.tokenRepo.MarkTokenUsed(tokenID); err != nil {
+func (s *TokenService) MarkTokenAsUsed(ctx context.Context, tokenID int) error {
+	if err := s.tokenRepo.MarkTokenUsed(ctx, tokenID); err != nil {
 		return fmt.Errorf("failed to mark token as used: %w", err)
 	}
 	return nil
 }
 
-func (s *TokenService) invalidateAndCreateNewToken(userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
-	if err := s.tokenRepo.InvalidateExistingTokens(userID, tokenType); err != nil {
+func (s *TokenService) invalidateAndCreateNewToken(ctx context.Context, userID int, tokenType model.TokenType, expiresIn time.Duration) (*model.Token, error) {
+	if err := s.tokenRepo.InvalidateExistingTokens(ctx, userID, tokenType); err != nil {
 		return nil, fmt.Errorf("failed to invalidate existing tokens: %w", err)
 	}
 
-	return s.createToken(userID, tokenType, expiresIn)
+	return s.createToken(ctx, userID, tokenType, expiresIn)
 }
 
 // emailParams contains all necessary parameters for sending token-based emails
@@ -136,9 +137,9 @@ func (p *emailParams) Validate() error {
 	return nil
 }
 
-func (s *TokenService) sendTokenEmail(params emailParams) error {
+func (s *TokenService) sendTokenEmail(ctx context.Context, params emailParams) error {
 	// Create a new token
-	token, err := s.invalidateAndCreateNewToken(params.UserID, params.TokenType, params.ExpiresIn)
+	token, err := s.invalidateAndCreateNewToken(ctx, params.UserID, params.TokenType, params.ExpiresIn)
 	if err != nil {
 		return fmt.Errorf("failed to create token: %w", err)
 	}
@@ -178,7 +179,7 @@ func (s *TokenService) sendTokenEmail(params emailParams) error {
 	return nil
 }
 
-func (s *TokenService) SendToken(
+func (s *TokenService) SendToken(ctx context.Context,
 	userID int,
 	email string,
 	tokenType model.TokenType,
@@ -191,7 +192,7 @@ func (s *TokenService) SendToken(
 		return fmt.Errorf("template not found for token type: %s", tokenType)
 	}
 
-	return s.sendTokenEmail(emailParams{
+	return s.sendTokenEmail(ctx, emailParams{
 		UserID:    userID,
 		Email:     email,
 		TokenType: tokenType,
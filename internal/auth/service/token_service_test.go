@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"testing"
@@ -60,7 +61,7 @@ func TestTokenService_CreateToken(t *testing.T) {
 				}
 			}
 
-			token, err := service.createToken(tt.userID, tt.tokenType, tt.expiresIn)
+			token, err := service.createToken(context.Background(), tt.userID, tt.tokenType, tt.expiresIn)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -149,7 +150,7 @@ func TestTokenService_ValidateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			token, err := service.ValidateToken(tt.token, tt.tokenType)
+			token, err := service.ValidateToken(context.Background(), tt.token, tt.tokenType)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -271,7 +272,7 @@ func TestTokenService_SendToken(t *testing.T) {
 				ExpiresIn: tt.expiresIn,
 			}
 
-			err := s.sendTokenEmail(params)
+			err := s.sendTokenEmail(context.Background(), params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -325,7 +326,7 @@ func TestTokenService_MarkTokenAsUsed(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := service.MarkTokenAsUsed(tt.tokenID)
+			err := service.MarkTokenAsUsed(context.Background(), tt.tokenID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/shuvo-paul/uptimebot/internal/badge/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const (
+	// statusMaxAge is how long caches may keep a status badge, in seconds
+	statusMaxAge = 60
+	// statsMaxAge is how long caches may keep uptime and response time
+	// badges, which change slowly, in seconds
+	statsMaxAge = 300
+	// maxLabelLength bounds custom labels, so badges stay badge sized
+	maxLabelLength = 40
+)
+
+// badgePaths are the badges offered on the badges page of a target
+var badgePaths = []string{
+	"status.svg",
+	"uptime.svg?period=24h",
+	"uptime.svg?period=7d",
+	"uptime.svg?period=30d",
+	"uptime.svg?period=90d",
+	"response-time.svg?period=24h",
+}
+
+type BadgeHandler struct {
+	badgeService service.BadgeServiceInterface
+	flash        flash.FlashStoreInterface
+	baseURL      string
+	Template     struct {
+		Show *renderer.Template
+	}
+}
+
+func NewBadgeHandler(badgeService service.BadgeServiceInterface, flash flash.FlashStoreInterface, baseURL string) *BadgeHandler {
+	return &BadgeHandler{
+		badgeService: badgeService,
+		flash:        flash,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// errorStatus maps badge service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTargetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Status serves the current status of a target
+func (h *BadgeHandler) Status(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, statusMaxAge, func() (*model.Badge, error) {
+		return h.badgeService.Status(r.Context(), r.PathValue("token"))
+	})
+}
+
+// Uptime serves the uptime of a target over the period in the query
+func (h *BadgeHandler) Uptime(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, statsMaxAge, func() (*model.Badge, error) {
+		return h.badgeService.Uptime(r.Context(), r.PathValue("token"), r.URL.Query().Get("period"))
+	})
+}
+
+// ResponseTime serves the average response time of a target over the
+// period in the query
+func (h *BadgeHandler) ResponseTime(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, statsMaxAge, func() (*model.Badge, error) {
+		return h.badgeService.ResponseTime(r.Context(), r.PathValue("token"), r.URL.Query().Get("period"))
+	})
+}
+
+// serve renders a badge in the style and with the label asked for in the
+// query. Errors are drawn as badges too, so that embeds never show a broken
+// image. Badges carry an ETag, and caches may keep them for maxAge seconds.
+func (h *BadgeHandler) serve(w http.ResponseWriter, r *http.Request, maxAge int, get func() (*model.Badge, error)) {
+	query := r.URL.Query()
+	status := http.StatusOK
+
+	style, err := model.ParseStyle(query.Get("style"))
+	var badge *model.Badge
+	if err != nil {
+		style = model.StyleFlat
+		status = http.StatusBadRequest
+		badge = &model.Badge{Label: "badge", Message: "invalid style", Color: model.ColorRed}
+	} else if badge, err = get(); err != nil {
+		status = errorStatus(err)
+		badge = &model.Badge{Label: "badge", Message: "unavailable", Color: model.ColorGray}
+		switch status {
+		case http.StatusNotFound:
+			badge.Message = "not found"
+		case http.StatusBadRequest:
+			badge.Message = "invalid request"
+			badge.Color = model.ColorRed
+		case http.StatusInternalServerError:
+			slog.Error("Failed to build badge", "error", err)
+		}
+	} else if label := strings.TrimSpace(query.Get("label")); label != "" {
+		if runes := []rune(label); len(runes) > maxLabelLength {
+			label = string(runes[:maxLabelLength])
+		}
+		badge.Label = label
+	}
+
+	body := renderSVG(badge, style)
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("ETag", etag)
+	if status == http.StatusOK {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	if status == http.StatusOK && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// Show shows the badges of a target with the snippets to embed them
+func (h *BadgeHandler) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	target, err := h.badgeService.GetTarget(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title":  "target badges",
+		"target": target,
+		"badges": badgePaths,
+	}
+	if target.BadgeToken != nil {
+		data["badgeBase"] = h.baseURL + "/badge/" + *target.BadgeToken
+	}
+	h.Template.Show.Render(w, r, data)
+}
+
+// Regenerate gives a target a new badge token, turning its badges on
+func (h *BadgeHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+	badgesURL := fmt.Sprintf("/app/targets/badges/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.badgeService.RegenerateToken(r.Context(), id, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to create badge link: " + err.Error()})
+		http.Redirect(w, r, badgesURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Badge link created successfully"})
+	http.Redirect(w, r, badgesURL, http.StatusSeeOther)
+}
+
+// Disable turns the badges of a target off
+func (h *BadgeHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+	badgesURL := fmt.Sprintf("/app/targets/badges/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.badgeService.DisableToken(r.Context(), id, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to turn off badges: " + err.Error()})
+		http.Redirect(w, r, badgesURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Badges turned off successfully"})
+	http.Redirect(w, r, badgesURL, http.StatusSeeOther)
+}
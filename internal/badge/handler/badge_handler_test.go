@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/shuvo-paul/uptimebot/internal/badge/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBadgeService struct {
+	statusFunc          func(token string) (*model.Badge, error)
+	uptimeFunc          func(token string, period string) (*model.Badge, error)
+	responseTimeFunc    func(token string, period string) (*model.Badge, error)
+	getTargetFunc       func(targetID int, userID int) (*model.Target, error)
+	regenerateTokenFunc func(targetID int, userID int) (string, error)
+	disableTokenFunc    func(targetID int, userID int) error
+}
+
+func (m *mockBadgeService) Status(ctx context.Context, token string) (*model.Badge, error) {
+	return m.statusFunc(token)
+}
+
+func (m *mockBadgeService) Uptime(ctx context.Context, token string, period string) (*model.Badge, error) {
+	return m.uptimeFunc(token, period)
+}
+
+func (m *mockBadgeService) ResponseTime(ctx context.Context, token string, period string) (*model.Badge, error) {
+	return m.responseTimeFunc(token, period)
+}
+
+func (m *mockBadgeService) GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error) {
+	return m.getTargetFunc(targetID, userID)
+}
+
+func (m *mockBadgeService) RegenerateToken(ctx context.Context, targetID int, userID int) (string, error) {
+	return m.regenerateTokenFunc(targetID, userID)
+}
+
+func (m *mockBadgeService) DisableToken(ctx context.Context, targetID int, userID int) error {
+	return m.disableTokenFunc(targetID, userID)
+}
+
+func newTestBadgeHandler(mockService *mockBadgeService) *BadgeHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewBadgeHandler(mockService, mockFlashStore, "https://uptime.example.com/")
+	handler.Template.Show = renderer.New(templates.TemplateFS, mockFlashStore).GetTemplate("pages:targets/badges")
+	return handler
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID, Email: "alice@example.com"})
+	return req.WithContext(ctx)
+}
+
+func TestBadgeHandler_Uptime(t *testing.T) {
+	var periods []string
+	handler := newTestBadgeHandler(&mockBadgeService{
+		uptimeFunc: func(token string, period string) (*model.Badge, error) {
+			periods = append(periods, period)
+			switch {
+			case token != "secret":
+				return nil, service.ErrTargetNotFound
+			case period == "1y":
+				return nil, service.ErrInvalidInput
+			}
+			return &model.Badge{Label: "uptime 7d", Message: "99.98%", Color: model.ColorBrightGreen}, nil
+		},
+	})
+
+	serve := func(token string, query string, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/badge/"+token+"/uptime.svg?"+query, nil)
+		req.SetPathValue("token", token)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rr := httptest.NewRecorder()
+		handler.Uptime(rr, req)
+		return rr
+	}
+
+	rr := serve("secret", "period=7d&label=Acme+API&style=flat-square", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/svg+xml; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Body.String(), "Acme API")
+	assert.Contains(t, rr.Body.String(), "99.98%")
+	assert.Equal(t, []string{"7d"}, periods)
+
+	etag := rr.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	rr = serve("secret", "period=7d&label=Acme+API&style=flat-square", etag)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = serve("guess", "", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Body.String(), "not found")
+
+	rr = serve("secret", "period=1y", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve("secret", "style=plastic", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid style")
+}
+
+func TestBadgeHandler_Status(t *testing.T) {
+	handler := newTestBadgeHandler(&mockBadgeService{
+		statusFunc: func(token string) (*model.Badge, error) {
+			return &model.Badge{Label: "status", Message: "up", Color: model.ColorBrightGreen}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/badge/secret/status.svg?style=for-the-badge", nil)
+	req.SetPathValue("token", "secret")
+	rr := httptest.NewRecorder()
+	handler.Status(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Body.String(), "STATUS")
+}
+
+func TestBadgeHandler_Show(t *testing.T) {
+	token := "secret"
+	handler := newTestBadgeHandler(&mockBadgeService{
+		getTargetFunc: func(targetID int, userID int) (*model.Target, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return &model.Target{ID: targetID, UserID: userID, URL: "https://example.org", BadgeToken: &token}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/app/targets/badges/7", nil)
+	req.SetPathValue("id", "7")
+	rr := httptest.NewRecorder()
+	handler.Show(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `src="https://uptime.example.com/badge/secret/uptime.svg?period=7d"`)
+	assert.Contains(t, rr.Body.String(), `action="/app/targets/badges/7/disable"`)
+
+	rr = httptest.NewRecorder()
+	handler.Show(rr, withUser(req, 2))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestBadgeHandler_Regenerate(t *testing.T) {
+	var regenerated []int
+	handler := newTestBadgeHandler(&mockBadgeService{
+		regenerateTokenFunc: func(targetID int, userID int) (string, error) {
+			regenerated = append(regenerated, targetID)
+			return "secret", nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/app/targets/badges/7", nil)
+	req.SetPathValue("id", "7")
+	rr := httptest.NewRecorder()
+	handler.Regenerate(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/app/targets/badges/7", rr.Header().Get("Location"))
+	assert.Equal(t, []int{7}, regenerated)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+)
+
+// charWidths are the advance widths of printable ASCII characters in 11px
+// Verdana, the font badges are set in, starting with the space
+var charWidths = [...]float64{
+	3.87, 4.33, 5.05, 9.0, 7.0, 11.84, 7.99, 2.95, 4.99, 4.99, 7.0, 9.0, 4.0, 4.99, 4.0, 4.99,
+	7.0, 7.0, 7.0, 7.0, 7.0, 7.0, 7.0, 7.0, 7.0, 7.0, 4.99, 4.99, 9.0, 9.0, 9.0, 6.0,
+	11.0, 7.52, 7.54, 7.68, 8.48, 6.96, 6.32, 8.53, 8.27, 4.63, 5.0, 7.62, 6.12, 9.27, 8.23, 8.66,
+	6.63, 8.66, 7.65, 7.52, 6.78, 8.05, 7.52, 10.88, 7.54, 6.77, 7.54, 4.99, 4.99, 4.99, 9.0, 7.0,
+	7.0, 6.61, 6.85, 5.73, 6.85, 6.55, 3.87, 6.85, 6.96, 3.02, 3.79, 6.51, 3.02, 10.75, 6.96, 6.68,
+	6.85, 6.85, 4.69, 5.73, 4.33, 6.96, 6.51, 8.98, 6.51, 6.51, 5.78, 6.98, 4.99, 6.98, 9.0,
+}
+
+// textWidth estimates how wide text is drawn in 11px Verdana. Characters
+// outside of printable ASCII are taken to be as wide as a digit.
+func textWidth(text string) float64 {
+	var width float64
+	for _, r := range text {
+		if r >= ' ' && int(r-' ') < len(charWidths) {
+			width += charWidths[r-' ']
+		} else {
+			width += 7.0
+		}
+	}
+	return width
+}
+
+// renderSVG draws a badge with its label on the left and message on the
+// right, in the look of shields.io badges
+func renderSVG(badge *model.Badge, style model.Style) []byte {
+	label, message := badge.Label, badge.Message
+	height, padding, fontSize, textY := 20.0, 5.0, 11.0, 14.0
+	var letterSpacing float64
+	if style == model.StyleForTheBadge {
+		label, message = strings.ToUpper(label), strings.ToUpper(message)
+		height, padding, fontSize, textY = 28, 12, 10, 18
+		letterSpacing = 1
+	}
+
+	measure := func(text string) float64 {
+		if text == "" {
+			return 0
+		}
+		width := textWidth(text)*fontSize/11 + letterSpacing*float64(len([]rune(text)))
+		return width + 2*padding
+	}
+	labelWidth, messageWidth := measure(label), measure(message)
+	width := labelWidth + messageWidth
+
+	title := html.EscapeString(badge.Label + ": " + badge.Message)
+	if badge.Label == "" {
+		title = html.EscapeString(badge.Message)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" role="img" aria-label="%s">`, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+
+	if style == model.StyleFlat {
+		b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+		fmt.Fprintf(&b, `<clipPath id="r"><rect width="%.0f" height="%.0f" rx="3" fill="#fff"/></clipPath>`, width, height)
+		b.WriteString(`<g clip-path="url(#r)">`)
+	} else {
+		b.WriteString(`<g shape-rendering="crispEdges">`)
+	}
+	fmt.Fprintf(&b, `<rect width="%.0f" height="%.0f" fill="%s"/>`, labelWidth, height, model.ColorLabel)
+	fmt.Fprintf(&b, `<rect x="%.0f" width="%.0f" height="%.0f" fill="%s"/>`, labelWidth, messageWidth, height, html.EscapeString(badge.Color))
+	if style == model.StyleFlat {
+		fmt.Fprintf(&b, `<rect width="%.0f" height="%.0f" fill="url(#s)"/>`, width, height)
+	}
+	b.WriteString(`</g>`)
+
+	fmt.Fprintf(&b, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="%.0f"`, fontSize)
+	if style == model.StyleForTheBadge {
+		fmt.Fprintf(&b, ` font-weight="bold" letter-spacing="%.0f"`, letterSpacing)
+	}
+	b.WriteString(`>`)
+	for _, part := range []struct {
+		text string
+		x    float64
+	}{
+		{label, labelWidth / 2},
+		{message, labelWidth + messageWidth/2},
+	} {
+		if part.text == "" {
+			continue
+		}
+		text := html.EscapeString(part.text)
+		if style == model.StyleFlat {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" fill="#010101" fill-opacity=".3">%s</text>`, part.x, textY+1, text)
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.0f">%s</text>`, part.x, textY, text)
+	}
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 28.0, textWidth("1234"), 0.01)
+	assert.Greater(t, textWidth("WWW"), textWidth("iii"))
+	assert.InDelta(t, 7.0, textWidth("é"), 0.01)
+}
+
+func TestRenderSVG(t *testing.T) {
+	badge := &model.Badge{Label: "uptime <30d>", Message: "99.98%", Color: model.ColorBrightGreen}
+
+	for _, style := range []model.Style{model.StyleFlat, model.StyleFlatSquare, model.StyleForTheBadge} {
+		t.Run(string(style), func(t *testing.T) {
+			svg := string(renderSVG(badge, style))
+
+			// Labels are escaped, so the badge stays well-formed
+			assert.NoError(t, xml.Unmarshal([]byte(svg), new(struct{})))
+			assert.Contains(t, svg, `fill="#4c1"`)
+			assert.Equal(t, style == model.StyleFlat, strings.Contains(svg, "linearGradient"))
+			if style == model.StyleForTheBadge {
+				assert.Contains(t, svg, "UPTIME &lt;30D&gt;")
+				assert.Contains(t, svg, `height="28"`)
+			} else {
+				assert.Contains(t, svg, "uptime &lt;30d&gt;")
+				assert.Contains(t, svg, `height="20"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Badge colors, as used by shields.io
+const (
+	ColorBrightGreen = "#4c1"
+	ColorGreen       = "#97ca00"
+	ColorYellow      = "#dfb317"
+	ColorOrange      = "#fe7d37"
+	ColorRed         = "#e05d44"
+	ColorBlue        = "#007ec6"
+	ColorGray        = "#9f9f9f"
+	ColorLabel       = "#555"
+)
+
+// DefaultPeriod is the period of uptime and response time badges that do not
+// ask for one
+const DefaultPeriod = "30d"
+
+var periods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// ParsePeriod returns the length of a badge period: 24h, 7d, 30d or 90d
+func ParsePeriod(period string) (time.Duration, error) {
+	if period == "" {
+		period = DefaultPeriod
+	}
+	length, ok := periods[period]
+	if !ok {
+		return 0, fmt.Errorf("unknown period: %s", period)
+	}
+	return length, nil
+}
+
+// Style is the look of a rendered badge
+type Style string
+
+const (
+	StyleFlat        Style = "flat"
+	StyleFlatSquare  Style = "flat-square"
+	StyleForTheBadge Style = "for-the-badge"
+)
+
+// ParseStyle returns the style of the given name, flat when it is empty
+func ParseStyle(style string) (Style, error) {
+	switch s := Style(style); s {
+	case "":
+		return StyleFlat, nil
+	case StyleFlat, StyleFlatSquare, StyleForTheBadge:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown style: %s", style)
+}
+
+// Badge is the text and color of a badge, before it is rendered
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// Target is the part of a monitored target its badges show
+type Target struct {
+	ID          int
+	UserID      int
+	URL         string
+	Status      string
+	Enabled     bool
+	PausedUntil *time.Time
+	BadgeToken  *string // nil while the target has no badges
+}
+
+// Paused reports whether the checks of the target are stopped
+func (t *Target) Paused(now time.Time) bool {
+	return !t.Enabled || (t.PausedUntil != nil && now.Before(*t.PausedUntil))
+}
+
+// Stats summarizes the checks that found a target up over a period
+type Stats struct {
+	UpChecks    int
+	AvgResponse time.Duration // average response time of the checks that found the target up
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	period, err := ParsePeriod("")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, period)
+
+	period, err = ParsePeriod("24h")
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, period)
+
+	_, err = ParsePeriod("1y")
+	assert.ErrorContains(t, err, "unknown period")
+}
+
+func TestParseStyle(t *testing.T) {
+	style, err := ParseStyle("")
+	assert.NoError(t, err)
+	assert.Equal(t, StyleFlat, style)
+
+	style, err = ParseStyle("for-the-badge")
+	assert.NoError(t, err)
+	assert.Equal(t, StyleForTheBadge, style)
+
+	_, err = ParseStyle("plastic")
+	assert.ErrorContains(t, err, "unknown style")
+}
+
+func TestTarget_Paused(t *testing.T) {
+	now := time.Date(2025, 5, 4, 9, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	assert.False(t, (&Target{Enabled: true}).Paused(now))
+	assert.True(t, (&Target{Enabled: false}).Paused(now))
+	assert.True(t, (&Target{Enabled: true, PausedUntil: &later}).Paused(now))
+	assert.False(t, (&Target{Enabled: true, PausedUntil: &earlier}).Paused(now))
+}
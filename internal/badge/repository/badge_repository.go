@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+)
+
+var ErrTargetNotFound = errors.New("target not found")
+
+type BadgeRepositoryInterface interface {
+	GetTarget(ctx context.Context, id int) (*model.Target, error)
+	GetByToken(ctx context.Context, token string) (*model.Target, error)
+	SetToken(ctx context.Context, targetID int, token *string) error
+	GetStats(ctx context.Context, targetID int, since time.Time) (*model.Stats, error)
+}
+
+var _ BadgeRepositoryInterface = (*BadgeRepository)(nil)
+
+// BadgeRepository reads the targets and check results badges are drawn from
+type BadgeRepository struct {
+	db database.Querier
+}
+
+func NewBadgeRepository(db database.Querier) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+func (r *BadgeRepository) getTarget(ctx context.Context, condition string, arg any) (*model.Target, error) {
+	query := `
+		SELECT id, user_id, url, status, enabled, paused_until, badge_token
+		FROM target
+		WHERE ` + condition
+
+	target := &model.Target{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&target.ID,
+		&target.UserID,
+		&target.URL,
+		&target.Status,
+		&target.Enabled,
+		&target.PausedUntil,
+		&target.BadgeToken,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+	return target, nil
+}
+
+func (r *BadgeRepository) GetTarget(ctx context.Context, id int) (*model.Target, error) {
+	return r.getTarget(ctx, `id = $1`, id)
+}
+
+func (r *BadgeRepository) GetByToken(ctx context.Context, token string) (*model.Target, error) {
+	return r.getTarget(ctx, `badge_token = $1`, token)
+}
+
+// SetToken replaces the badge token of a target. A nil token turns its
+// badges off.
+func (r *BadgeRepository) SetToken(ctx context.Context, targetID int, token *string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE target SET badge_token = $1 WHERE id = $2`, token, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to update badge token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+// GetStats summarizes the checks of a target since the given time that
+// found it up
+func (r *BadgeRepository) GetStats(ctx context.Context, targetID int, since time.Time) (*model.Stats, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(AVG(duration_ms), 0)
+		FROM check_result
+		WHERE target_id = $1 AND checked_at >= $2::timestamp AND status = 'up'
+	`
+
+	stats := &model.Stats{}
+	var avgMS float64
+	err := r.db.QueryRowContext(ctx, query, targetID, since.UTC()).Scan(&stats.UpChecks, &avgMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check stats: %w", err)
+	}
+	stats.AvgResponse = time.Duration(avgMS * float64(time.Millisecond))
+	return stats, nil
+}
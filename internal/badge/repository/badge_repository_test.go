@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewBadgeRepository(tx)
+	targets := monitorRepo.NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	target, err := targets.Create(context.Background(), monitorModel.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{
+			URL:             "https://example.org",
+			Status:          "up",
+			Enabled:         true,
+			Interval:        30 * time.Second,
+			StatusChangedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+
+	stored, err := repo.GetTarget(context.Background(), target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Nil(t, stored.BadgeToken)
+
+	token := "badge-token"
+	assert.NoError(t, repo.SetToken(context.Background(), target.ID, &token))
+	byToken, err := repo.GetByToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, byToken.ID)
+	assert.Equal(t, "up", byToken.Status)
+
+	assert.NoError(t, repo.SetToken(context.Background(), target.ID, nil))
+	_, err = repo.GetByToken(context.Background(), token)
+	assert.ErrorIs(t, err, ErrTargetNotFound)
+	assert.ErrorIs(t, repo.SetToken(context.Background(), target.ID+1, nil), ErrTargetNotFound)
+
+	now := time.Now().UTC()
+	for i, duration := range []time.Duration{100, 300, 5000} {
+		status := "up"
+		if i == 2 {
+			status = "down"
+		}
+		assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{
+			Status:    status,
+			Duration:  duration * time.Millisecond,
+			CheckedAt: now.Add(-time.Duration(i+1) * time.Hour),
+		}))
+	}
+	// Outside of the period
+	assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{Status: "down", CheckedAt: now.Add(-48 * time.Hour)}))
+	// Made during a maintenance window
+	assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{Status: "maintenance", Duration: time.Second, CheckedAt: now.Add(-4 * time.Hour)}))
+
+	stats, err := repo.GetStats(context.Background(), target.ID, now.Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.UpChecks)
+	assert.Equal(t, 200*time.Millisecond, stats.AvgResponse)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/shuvo-paul/uptimebot/internal/badge/repository"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+)
+
+var (
+	// ErrUnauthorized is returned when a user manages the badges of a target they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to target")
+	// ErrTargetNotFound is returned when no target has the requested id or badge token.
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
+type BadgeServiceInterface interface {
+	Status(ctx context.Context, token string) (*model.Badge, error)
+	Uptime(ctx context.Context, token string, period string) (*model.Badge, error)
+	ResponseTime(ctx context.Context, token string, period string) (*model.Badge, error)
+	GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error)
+	RegenerateToken(ctx context.Context, targetID int, userID int) (string, error)
+	DisableToken(ctx context.Context, targetID int, userID int) error
+}
+
+var _ BadgeServiceInterface = (*BadgeService)(nil)
+
+// AvailabilityService is the part of the SLA service uptime badges rely on
+type AvailabilityService interface {
+	Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (slaModel.Availability, error)
+}
+
+// BadgeService draws up the badges of targets, which anyone holding the
+// badge token of a target can see
+type BadgeService struct {
+	repo                repository.BadgeRepositoryInterface
+	availabilityService AvailabilityService
+	now                 func() time.Time
+	newToken            func() (string, error)
+}
+
+func NewBadgeService(repo repository.BadgeRepositoryInterface, availabilityService AvailabilityService) *BadgeService {
+	return &BadgeService{
+		repo:                repo,
+		availabilityService: availabilityService,
+		now:                 time.Now,
+		newToken:            newToken,
+	}
+}
+
+// newToken returns a random token that is safe to use in URLs
+func newToken() (string, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate badge token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func (s *BadgeService) byToken(ctx context.Context, token string) (*model.Target, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: missing token", ErrInvalidInput)
+	}
+
+	target, err := s.repo.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			return nil, fmt.Errorf("%w: unknown badge token", ErrTargetNotFound)
+		}
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+	return target, nil
+}
+
+// byPeriod returns the target holding the token, and the length of the period
+// a badge is drawn over
+func (s *BadgeService) byPeriod(ctx context.Context, token string, period string) (*model.Target, time.Duration, error) {
+	length, err := model.ParsePeriod(period)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	target, err := s.byToken(ctx, token)
+	if err != nil {
+		return nil, 0, err
+	}
+	return target, length, nil
+}
+
+// stats summarizes the checks of the target holding the token over a period
+func (s *BadgeService) stats(ctx context.Context, token string, period string) (*model.Stats, error) {
+	target, length, err := s.byPeriod(ctx, token, period)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.GetStats(ctx, target.ID, s.now().Add(-length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Status shows whether the target is up right now
+func (s *BadgeService) Status(ctx context.Context, token string) (*model.Badge, error) {
+	target, err := s.byToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	badge := &model.Badge{Label: "status", Message: target.Status, Color: model.ColorGray}
+	switch {
+	case target.Paused(s.now()):
+		badge.Message = "paused"
+	case target.Status == "up":
+		badge.Color = model.ColorBrightGreen
+	case target.Status == "down", target.Status == "error", target.Status == "unreachable-dependency":
+		badge.Color = model.ColorRed
+	case target.Status == "flapping":
+		badge.Color = model.ColorOrange
+	case target.Status == "maintenance":
+		badge.Color = model.ColorBlue
+	}
+	return badge, nil
+}
+
+// Uptime shows the share of the period the target was up, worked out as
+// the availability of SLAs is
+func (s *BadgeService) Uptime(ctx context.Context, token string, period string) (*model.Badge, error) {
+	if period == "" {
+		period = model.DefaultPeriod
+	}
+	target, length, err := s.byPeriod(ctx, token, period)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	availability, err := s.availabilityService.Availability(ctx, target.ID, target.UserID, now.Add(-length), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability: %w", err)
+	}
+
+	badge := &model.Badge{Label: "uptime " + period, Message: "no data", Color: model.ColorGray}
+	ratio, ok := availability.Ratio()
+	if !ok {
+		return badge, nil
+	}
+
+	uptime := ratio * 100
+	badge.Message = formatUptime(uptime)
+	switch {
+	case uptime >= 99.9:
+		badge.Color = model.ColorBrightGreen
+	case uptime >= 99:
+		badge.Color = model.ColorGreen
+	case uptime >= 97:
+		badge.Color = model.ColorYellow
+	case uptime >= 95:
+		badge.Color = model.ColorOrange
+	default:
+		badge.Color = model.ColorRed
+	}
+	return badge, nil
+}
+
+// formatUptime rounds down, so that only a period without downtime shows 100%
+func formatUptime(uptime float64) string {
+	if uptime >= 100 {
+		return "100%"
+	}
+	return fmt.Sprintf("%.2f%%", math.Floor(uptime*100)/100)
+}
+
+// ResponseTime shows the average response time of the checks over the period
+// that found the target up
+func (s *BadgeService) ResponseTime(ctx context.Context, token string, period string) (*model.Badge, error) {
+	if period == "" {
+		period = model.DefaultPeriod
+	}
+	stats, err := s.stats(ctx, token, period)
+	if err != nil {
+		return nil, err
+	}
+
+	badge := &model.Badge{Label: "response time " + period, Message: "no data", Color: model.ColorGray}
+	if stats.UpChecks == 0 {
+		return badge, nil
+	}
+
+	avg := stats.AvgResponse
+	badge.Message = fmt.Sprintf("%d ms", avg.Milliseconds())
+	if avg >= time.Second {
+		badge.Message = fmt.Sprintf("%.1f s", avg.Seconds())
+	}
+	switch {
+	case avg < 300*time.Millisecond:
+		badge.Color = model.ColorBrightGreen
+	case avg < time.Second:
+		badge.Color = model.ColorGreen
+	case avg < 2*time.Second:
+		badge.Color = model.ColorYellow
+	case avg < 5*time.Second:
+		badge.Color = model.ColorOrange
+	default:
+		badge.Color = model.ColorRed
+	}
+	return badge, nil
+}
+
+// GetTarget retrieves a target with its badge token after verifying the
+// user owns it
+func (s *BadgeService) GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error) {
+	if targetID <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	target, err := s.repo.GetTarget(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			return nil, fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, targetID)
+		}
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	if target.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return target, nil
+}
+
+// RegenerateToken gives a target a new badge token, turning its badges on.
+// Badges embedded with the previous token stop working.
+func (s *BadgeService) RegenerateToken(ctx context.Context, targetID int, userID int) (string, error) {
+	if _, err := s.GetTarget(ctx, targetID, userID); err != nil {
+		return "", err
+	}
+
+	token, err := s.newToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.repo.SetToken(ctx, targetID, &token); err != nil {
+		return "", fmt.Errorf("failed to save badge token: %w", err)
+	}
+	return token, nil
+}
+
+// DisableToken turns the badges of a target off
+func (s *BadgeService) DisableToken(ctx context.Context, targetID int, userID int) error {
+	if _, err := s.GetTarget(ctx, targetID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetToken(ctx, targetID, nil); err != nil {
+		return fmt.Errorf("failed to remove badge token: %w", err)
+	}
+	return nil
+}
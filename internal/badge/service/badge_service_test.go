@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/badge/model"
+	"github.com/shuvo-paul/uptimebot/internal/badge/repository"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBadgeRepository struct {
+	getTargetFunc  func(id int) (*model.Target, error)
+	getByTokenFunc func(token string) (*model.Target, error)
+	setTokenFunc   func(targetID int, token *string) error
+	getStatsFunc   func(targetID int, since time.Time) (*model.Stats, error)
+}
+
+func (m *mockBadgeRepository) GetTarget(ctx context.Context, id int) (*model.Target, error) {
+	return m.getTargetFunc(id)
+}
+
+func (m *mockBadgeRepository) GetByToken(ctx context.Context, token string) (*model.Target, error) {
+	return m.getByTokenFunc(token)
+}
+
+func (m *mockBadgeRepository) SetToken(ctx context.Context, targetID int, token *string) error {
+	return m.setTokenFunc(targetID, token)
+}
+
+func (m *mockBadgeRepository) GetStats(ctx context.Context, targetID int, since time.Time) (*model.Stats, error) {
+	return m.getStatsFunc(targetID, since)
+}
+
+type mockAvailabilityService struct {
+	availabilityFunc func(targetID int, userID int, from, to time.Time) (slaModel.Availability, error)
+}
+
+func (m *mockAvailabilityService) Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (slaModel.Availability, error) {
+	return m.availabilityFunc(targetID, userID, from, to)
+}
+
+func byToken(target *model.Target) func(token string) (*model.Target, error) {
+	return func(token string) (*model.Target, error) {
+		if token != "secret" {
+			return nil, repository.ErrTargetNotFound
+		}
+		return target, nil
+	}
+}
+
+func TestBadgeService_Status(t *testing.T) {
+	now := time.Date(2025, 5, 4, 9, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		target  *model.Target
+		message string
+		color   string
+	}{
+		{name: "up", target: &model.Target{Status: "up", Enabled: true}, message: "up", color: model.ColorBrightGreen},
+		{name: "down", target: &model.Target{Status: "down", Enabled: true}, message: "down", color: model.ColorRed},
+		{name: "flapping", target: &model.Target{Status: "flapping", Enabled: true}, message: "flapping", color: model.ColorOrange},
+		{name: "maintenance", target: &model.Target{Status: "maintenance", Enabled: true}, message: "maintenance", color: model.ColorBlue},
+		{name: "unreachable", target: &model.Target{Status: "unreachable-dependency", Enabled: true}, message: "unreachable-dependency", color: model.ColorRed},
+		{name: "pending", target: &model.Target{Status: "pending", Enabled: true}, message: "pending", color: model.ColorGray},
+		{name: "disabled", target: &model.Target{Status: "up", Enabled: false}, message: "paused", color: model.ColorGray},
+		{name: "paused", target: &model.Target{Status: "down", Enabled: true, PausedUntil: &later}, message: "paused", color: model.ColorGray},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewBadgeService(&mockBadgeRepository{getByTokenFunc: byToken(tt.target)}, nil)
+			service.now = func() time.Time { return now }
+
+			badge, err := service.Status(context.Background(), "secret")
+			assert.NoError(t, err)
+			assert.Equal(t, &model.Badge{Label: "status", Message: tt.message, Color: tt.color}, badge)
+		})
+	}
+
+	service := NewBadgeService(&mockBadgeRepository{getByTokenFunc: byToken(&model.Target{})}, nil)
+	_, err := service.Status(context.Background(), "guess")
+	assert.ErrorIs(t, err, ErrTargetNotFound)
+	_, err = service.Status(context.Background(), "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestBadgeService_Uptime(t *testing.T) {
+	now := time.Date(2025, 5, 4, 9, 0, 0, 0, time.UTC)
+	var since time.Time
+	availability := slaModel.Availability{}
+	repo := &mockBadgeRepository{getByTokenFunc: byToken(&model.Target{ID: 7, UserID: 3})}
+	service := NewBadgeService(repo, &mockAvailabilityService{
+		availabilityFunc: func(targetID int, userID int, from, to time.Time) (slaModel.Availability, error) {
+			assert.Equal(t, 7, targetID)
+			assert.Equal(t, 3, userID)
+			assert.Equal(t, now, to)
+			since = from
+			return availability, nil
+		},
+	})
+	service.now = func() time.Time { return now }
+
+	badge, err := service.Uptime(context.Background(), "secret", "")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Badge{Label: "uptime 30d", Message: "no data", Color: model.ColorGray}, badge)
+	assert.Equal(t, now.Add(-30*24*time.Hour), since)
+
+	availability = slaModel.Availability{Up: 9999 * time.Minute, Down: time.Minute}
+	badge, err = service.Uptime(context.Background(), "secret", "7d")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Badge{Label: "uptime 7d", Message: "99.99%", Color: model.ColorBrightGreen}, badge)
+
+	// Never rounded up to a perfect score
+	availability = slaModel.Availability{Up: 99999 * time.Minute, Down: time.Minute}
+	badge, _ = service.Uptime(context.Background(), "secret", "24h")
+	assert.Equal(t, "99.99%", badge.Message)
+
+	// Maintenance is left out
+	availability = slaModel.Availability{Up: 100 * time.Minute, Excluded: time.Hour}
+	badge, _ = service.Uptime(context.Background(), "secret", "90d")
+	assert.Equal(t, "100%", badge.Message)
+
+	availability = slaModel.Availability{Up: 90 * time.Minute, Down: 10 * time.Minute}
+	badge, _ = service.Uptime(context.Background(), "secret", "90d")
+	assert.Equal(t, model.ColorRed, badge.Color)
+
+	_, err = service.Uptime(context.Background(), "secret", "1y")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestBadgeService_ResponseTime(t *testing.T) {
+	stats := &model.Stats{}
+	repo := &mockBadgeRepository{
+		getByTokenFunc: byToken(&model.Target{ID: 7}),
+		getStatsFunc: func(targetID int, since time.Time) (*model.Stats, error) {
+			return stats, nil
+		},
+	}
+	service := NewBadgeService(repo, nil)
+
+	badge, err := service.ResponseTime(context.Background(), "secret", "24h")
+	assert.NoError(t, err)
+	assert.Equal(t, "no data", badge.Message)
+
+	*stats = model.Stats{UpChecks: 10, AvgResponse: 182 * time.Millisecond}
+	badge, err = service.ResponseTime(context.Background(), "secret", "24h")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Badge{Label: "response time 24h", Message: "182 ms", Color: model.ColorBrightGreen}, badge)
+
+	*stats = model.Stats{UpChecks: 10, AvgResponse: 2500 * time.Millisecond}
+	badge, _ = service.ResponseTime(context.Background(), "secret", "24h")
+	assert.Equal(t, &model.Badge{Label: "response time 24h", Message: "2.5 s", Color: model.ColorOrange}, badge)
+}
+
+func TestBadgeService_RegenerateToken(t *testing.T) {
+	var saved []*string
+	repo := &mockBadgeRepository{
+		getTargetFunc: func(id int) (*model.Target, error) {
+			if id != 7 {
+				return nil, repository.ErrTargetNotFound
+			}
+			return &model.Target{ID: id, UserID: 1}, nil
+		},
+		setTokenFunc: func(targetID int, token *string) error {
+			saved = append(saved, token)
+			return nil
+		},
+	}
+	service := NewBadgeService(repo, nil)
+
+	first, err := service.RegenerateToken(context.Background(), 7, 1)
+	assert.NoError(t, err)
+	assert.Len(t, first, 22)
+	second, err := service.RegenerateToken(context.Background(), 7, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	assert.NoError(t, service.DisableToken(context.Background(), 7, 1))
+	assert.Equal(t, []*string{&first, &second, nil}, saved)
+
+	_, err = service.RegenerateToken(context.Background(), 7, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, service.DisableToken(context.Background(), 8, 1), ErrTargetNotFound)
+}
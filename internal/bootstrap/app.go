@@ -1,39 +1,81 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"html/template"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shuvo-paul/uptimebot/internal/api"
 	authHandler "github.com/shuvo-paul/uptimebot/internal/auth/handler"
 	"github.com/shuvo-paul/uptimebot/internal/auth/model"
 	authRepository "github.com/shuvo-paul/uptimebot/internal/auth/repository"
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	badgeHandler "github.com/shuvo-paul/uptimebot/internal/badge/handler"
+	badgeRepository "github.com/shuvo-paul/uptimebot/internal/badge/repository"
+	badgeService "github.com/shuvo-paul/uptimebot/internal/badge/service"
 	"github.com/shuvo-paul/uptimebot/internal/config"
 	"github.com/shuvo-paul/uptimebot/internal/database"
 	"github.com/shuvo-paul/uptimebot/internal/database/migrations"
 	"github.com/shuvo-paul/uptimebot/internal/email"
+	escalationHandler "github.com/shuvo-paul/uptimebot/internal/escalation/handler"
+	escalationRepository "github.com/shuvo-paul/uptimebot/internal/escalation/repository"
+	escalationService "github.com/shuvo-paul/uptimebot/internal/escalation/service"
+	incidentHandler "github.com/shuvo-paul/uptimebot/internal/incident/handler"
+	incidentRepository "github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
+	maintenanceHandler "github.com/shuvo-paul/uptimebot/internal/maintenance/handler"
+	maintenanceRepository "github.com/shuvo-paul/uptimebot/internal/maintenance/repository"
+	maintenanceService "github.com/shuvo-paul/uptimebot/internal/maintenance/service"
 	uptimeHandler "github.com/shuvo-paul/uptimebot/internal/monitor/handler"
 	uptimeRepository "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	uptimeService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
 	notificationHandler "github.com/shuvo-paul/uptimebot/internal/notification/handler"
 	notificationRepository "github.com/shuvo-paul/uptimebot/internal/notification/repository"
 	notificationService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+	oncallHandler "github.com/shuvo-paul/uptimebot/internal/oncall/handler"
+	oncallRepository "github.com/shuvo-paul/uptimebot/internal/oncall/repository"
+	oncallService "github.com/shuvo-paul/uptimebot/internal/oncall/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	reportRepository "github.com/shuvo-paul/uptimebot/internal/report/repository"
+	reportService "github.com/shuvo-paul/uptimebot/internal/report/service"
+	slaHandler "github.com/shuvo-paul/uptimebot/internal/sla/handler"
+	slaRepository "github.com/shuvo-paul/uptimebot/internal/sla/repository"
+	slaService "github.com/shuvo-paul/uptimebot/internal/sla/service"
+	statusPageHandler "github.com/shuvo-paul/uptimebot/internal/statuspage/handler"
+	statusPageRepository "github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+	statusPageService "github.com/shuvo-paul/uptimebot/internal/statuspage/service"
 	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/tracing"
 	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+	"github.com/shuvo-paul/uptimebot/pkg/netguard"
 )
 
 type App struct {
-	Config          *config.Config
-	AuthService     *authService.AuthService
-	SessionService  *authService.SessionService
-	UserHandler     *authHandler.AuthHandler
-	TargetHandler   *uptimeHandler.TargetHandler
-	NotifierHandler *notificationHandler.NotifierHandler
-	db              *sql.DB
+	Config            *config.Config
+	AuthService       *authService.AuthService
+	SessionService    *authService.SessionService
+	APITokenService   *authService.APITokenService
+	UserHandler       *authHandler.AuthHandler
+	TargetHandler     *uptimeHandler.TargetHandler
+	NotifierHandler   *notificationHandler.NotifierHandler
+	IncidentHandler   *incidentHandler.IncidentHandler
+	PolicyHandler     *escalationHandler.PolicyHandler
+	ScheduleHandler   *oncallHandler.ScheduleHandler
+	WindowHandler     *maintenanceHandler.WindowHandler
+	StatusPageHandler *statusPageHandler.StatusPageHandler
+	BadgeHandler      *badgeHandler.BadgeHandler
+	SLAHandler        *slaHandler.SLAHandler
+	SlackHandler      *notificationHandler.SlackHandler
+	APIHandler        *api.Handler
+	MetricsHandler    http.Handler
+	db                *sql.DB
+	shutdownTracing   func(context.Context) error
 }
 
 func NewApp() *App {
@@ -50,12 +92,20 @@ func NewApp() *App {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	db, err := database.InitDatabase(cfg.Database)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	sqlDB, err := database.InitDatabase(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	migrations.SetupMigration(db)
+	migrations.SetupMigration(sqlDB)
+
+	// Repositories query through a wrapper tracing every query
+	db := database.Traced(sqlDB)
 
 	flashStore := flash.NewFlashStore()
 
@@ -64,6 +114,7 @@ func NewApp() *App {
 	userRepository := authRepository.NewUserRepository(db)
 	sessionRepository := authRepository.NewSessionRepository(db)
 	tokenRepository := authRepository.NewTokenRepository(db)
+	apiTokenRepository := authRepository.NewAPITokenRepository(db)
 
 	emailService, err := email.NewEmailService(&cfg.Email)
 	if err != nil {
@@ -90,7 +141,8 @@ func NewApp() *App {
 	authService2 := authService.NewAuthService(userRepository, tokenService)
 
 	sessionService := authService.NewSessionService(sessionRepository)
-	authHandler := authHandler.NewAuthHandler(authService2, sessionService, flashStore)
+	apiTokenService := authService.NewAPITokenService(apiTokenRepository)
+	authHandler := authHandler.NewAuthHandler(authService2, sessionService, flashStore, apiTokenService)
 	authHandler.Template.Register = templateRenderer.GetTemplate("pages:register")
 	authHandler.Template.Login = templateRenderer.GetTemplate("pages:login")
 	authHandler.Template.RequestPasswordReset = templateRenderer.GetTemplate("pages:request-reset-password")
@@ -98,37 +150,155 @@ func NewApp() *App {
 	authHandler.Template.Profile = templateRenderer.GetTemplate("pages:profile")
 
 	notifierRepository := notificationRepository.NewNotifierRepository(db)
-	notifierService := notificationService.NewNotifierService(notifierRepository, nil)
-	notifierHandler := notificationHandler.NewNotifierHandler(notifierService)
+	notifierService := notificationService.NewNotifierService(notifierRepository)
+	notifierService.StartDigests(time.Minute)
+	notifierHandler := notificationHandler.NewNotifierHandler(notifierService, flashStore)
+	notifierHandler.Template.List = templateRenderer.GetTemplate("pages:notifiers/list")
+	notifierHandler.Template.Create = templateRenderer.GetTemplate("pages:notifiers/create")
+	notifierHandler.Template.Edit = templateRenderer.GetTemplate("pages:notifiers/edit")
+	notifierHandler.Template.Target = templateRenderer.GetTemplate("pages:notifiers/target")
+
+	incidentRepository := incidentRepository.NewIncidentRepository(db)
+	incidentService := incidentService.NewIncidentService(incidentRepository)
+	incidentHandler := incidentHandler.NewIncidentHandler(incidentService, flashStore)
+	incidentHandler.Template.List = templateRenderer.GetTemplate("pages:incidents/list")
+	incidentHandler.Template.Show = templateRenderer.GetTemplate("pages:incidents/show")
+
+	scheduleRepository := oncallRepository.NewScheduleRepository(db)
+	scheduleService := oncallService.NewScheduleService(scheduleRepository, notifierService)
+	scheduleHandler := oncallHandler.NewScheduleHandler(scheduleService, flashStore)
+	scheduleHandler.Template.List = templateRenderer.GetTemplate("pages:oncall/list")
+	scheduleHandler.Template.Create = templateRenderer.GetTemplate("pages:oncall/create")
+	scheduleHandler.Template.Edit = templateRenderer.GetTemplate("pages:oncall/edit")
+	scheduleHandler.Template.Show = templateRenderer.GetTemplate("pages:oncall/show")
+
+	windowRepository := maintenanceRepository.NewWindowRepository(db)
+	windowService := maintenanceService.NewWindowService(windowRepository)
+	windowHandler := maintenanceHandler.NewWindowHandler(windowService, flashStore)
+	windowHandler.Template.List = templateRenderer.GetTemplate("pages:maintenance/list")
+	windowHandler.Template.Create = templateRenderer.GetTemplate("pages:maintenance/create")
+	windowHandler.Template.Edit = templateRenderer.GetTemplate("pages:maintenance/edit")
+
+	policyRepository := escalationRepository.NewPolicyRepository(db)
+	policyService := escalationService.NewPolicyService(policyRepository, notifierService, scheduleService, windowService, cfg.BaseURL)
+	policyService.Start(30 * time.Second)
+	policyHandler := escalationHandler.NewPolicyHandler(policyService, flashStore)
+	policyHandler.Template.List = templateRenderer.GetTemplate("pages:escalation/list")
+	policyHandler.Template.Create = templateRenderer.GetTemplate("pages:escalation/create")
+	policyHandler.Template.Edit = templateRenderer.GetTemplate("pages:escalation/edit")
 
 	targetRepository := uptimeRepository.NewTargetRepository(db)
-	targetService := uptimeService.NewTargetService(targetRepository, notifierService)
+	targetService := uptimeService.NewTargetService(targetRepository, notifierService, incidentService, windowService, cfg.BaseURL)
 
 	// Initialize monitoring for existing targets
-	if err := targetService.InitializeMonitoring(); err != nil {
+	if err := targetService.InitializeMonitoring(context.Background()); err != nil {
 		log.Printf("Failed to initialize target monitoring: %v", err)
 		// Don't fatal here, allow the app to continue even if some monitors fail
 	}
 
+	// Digests, status pages and badges show uptime as the SLA service works
+	// it out
+	slaRepository := slaRepository.NewSLARepository(db)
+	slaService := slaService.NewSLAService(slaRepository, notifierService, cfg.BaseURL)
+	slaService.Start(time.Minute)
+
+	// The digest job runs on its own goroutine, so it gets its own mailer
+	// rather than sharing the message being built by the token service
+	digestMailer, err := email.NewEmailService(&cfg.Email)
+	if err != nil {
+		log.Fatalf("Failed to initialize email service: %v", err)
+	}
+	reportRepository := reportRepository.NewReportRepository(db)
+	reportService := reportService.NewReportService(
+		reportRepository,
+		slaService,
+		digestMailer,
+		templateRenderer.GetTemplate("emails:digest").Raw(),
+		cfg.BaseURL,
+	)
+	reportService.Start(time.Hour)
+
 	// Initialize target controller
 	targetHandler := uptimeHandler.NewTargetHandler(targetService, flashStore)
 	targetHandler.Template.List = templateRenderer.GetTemplate("pages:targets/list")
 	targetHandler.Template.Create = templateRenderer.GetTemplate("pages:targets/create")
 	targetHandler.Template.Edit = templateRenderer.GetTemplate("pages:targets/edit")
 
+	postRepository := statusPageRepository.NewPostRepository(db)
+	subscriberRepository := statusPageRepository.NewSubscriberRepository(db)
+	statusPageRepository := statusPageRepository.NewStatusPageRepository(db)
+	pageService := statusPageService.NewStatusPageService(statusPageRepository, postRepository, targetService, slaService)
+	// Subscribers are emailed from a background goroutine, so they get their
+	// own mailer as well
+	subscriberMailer, err := email.NewEmailService(&cfg.Email)
+	if err != nil {
+		log.Fatalf("Failed to initialize email service: %v", err)
+	}
+	subscriberService := statusPageService.NewSubscriberService(
+		subscriberRepository,
+		pageService,
+		subscriberMailer,
+		templateRenderer.GetTemplate("emails:confirm-subscription").Raw(),
+		templateRenderer.GetTemplate("emails:status-update").Raw(),
+		// Webhook addresses come from visitors, so they may not reach the
+		// server's own network
+		netguard.NewClient(10*time.Second),
+		cfg.BaseURL,
+	)
+	postService := statusPageService.NewPostService(postRepository, pageService, subscriberService)
+	statusPageHandler := statusPageHandler.NewStatusPageHandler(pageService, postService, subscriberService, flashStore, cfg.BaseURL)
+	statusPageHandler.Template.List = templateRenderer.GetTemplate("pages:status-pages/list")
+	statusPageHandler.Template.Create = templateRenderer.GetTemplate("pages:status-pages/create")
+	statusPageHandler.Template.Edit = templateRenderer.GetTemplate("pages:status-pages/edit")
+	statusPageHandler.Template.Posts = templateRenderer.GetTemplate("pages:status-pages/posts")
+	statusPageHandler.Template.Subscribers = templateRenderer.GetTemplate("pages:status-pages/subscribers")
+	statusPageHandler.Template.Public = templateRenderer.GetTemplate("pages:status/show")
+	statusPageHandler.Template.Subscribe = templateRenderer.GetTemplate("pages:status/subscribe")
+	statusPageHandler.Template.Notice = templateRenderer.GetTemplate("pages:status/notice")
+
+	badgeRepository := badgeRepository.NewBadgeRepository(db)
+	badgeService := badgeService.NewBadgeService(badgeRepository, slaService)
+	badgeHandler := badgeHandler.NewBadgeHandler(badgeService, flashStore, cfg.BaseURL)
+	badgeHandler.Template.Show = templateRenderer.GetTemplate("pages:targets/badges")
+
+	slaHandler := slaHandler.NewSLAHandler(slaService, flashStore)
+	slaHandler.Template.Show = templateRenderer.GetTemplate("pages:targets/sla")
+
+	slackHandler := notificationHandler.NewSlackHandler(incidentService, targetService, notifierService, nil)
+	apiHandler := api.NewHandler(targetService, notifierService, incidentService)
+
 	fmt.Println("app initialized")
 
 	return &App{
-		Config:          cfg,
-		AuthService:     authService2,
-		SessionService:  sessionService,
-		UserHandler:     authHandler,
-		TargetHandler:   targetHandler,
-		NotifierHandler: notifierHandler,
-		db:              db,
+		Config:            cfg,
+		AuthService:       authService2,
+		SessionService:    sessionService,
+		APITokenService:   apiTokenService,
+		UserHandler:       authHandler,
+		TargetHandler:     targetHandler,
+		NotifierHandler:   notifierHandler,
+		IncidentHandler:   incidentHandler,
+		PolicyHandler:     policyHandler,
+		ScheduleHandler:   scheduleHandler,
+		WindowHandler:     windowHandler,
+		StatusPageHandler: statusPageHandler,
+		BadgeHandler:      badgeHandler,
+		SLAHandler:        slaHandler,
+		SlackHandler:      slackHandler,
+		APIHandler:        apiHandler,
+		MetricsHandler:    metrics.Handler(cfg.MetricsToken),
+		db:                sqlDB,
+		shutdownTracing:   shutdownTracing,
 	}
 }
 
 func (a *App) Close() {
 	a.db.Close()
+
+	// Flush the spans still waiting to be exported
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to shut down tracing: %v", err)
+	}
 }
@@ -7,10 +7,20 @@ import (
 )
 
 type Config struct {
-	Email    EmailConfig
-	Database DatabaseConfig
-	BaseURL  string
-	Port     int
+	Email        EmailConfig
+	Database     DatabaseConfig
+	BaseURL      string
+	Port         int
+	MetricsToken string // bearer token required to scrape /metrics, empty leaves it open
+	Tracing      TracingConfig
+}
+
+// TracingConfig configures exporting traces over OTLP/HTTP. Tracing is off
+// when no endpoint is set.
+type TracingConfig struct {
+	Endpoint    string  // full URL of the traces endpoint, such as http://collector:4318/v1/traces
+	ServiceName string  // service.name of the spans
+	SampleRatio float64 // share of new traces recorded, from 0 to 1
 }
 
 type DatabaseConfig struct {
@@ -56,11 +66,41 @@ func Load() (*Config, error) {
 		port = portNum
 	}
 
+	tracingConfig, err := loadTracingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracing config: %v", err)
+	}
+
 	return &Config{
-		Email:    emailConfig,
-		Database: dbConfig,
-		BaseURL:  baseURL,
-		Port:     port,
+		Email:        emailConfig,
+		Database:     dbConfig,
+		BaseURL:      baseURL,
+		Port:         port,
+		MetricsToken: os.Getenv("METRICS_TOKEN"),
+		Tracing:      tracingConfig,
+	}, nil
+}
+
+// loadTracingConfig reads the standard OpenTelemetry variables
+func loadTracingConfig() (TracingConfig, error) {
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "uptimebot"
+	}
+
+	sampleRatio := 1.0
+	if ratio := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); ratio != "" {
+		parsed, err := strconv.ParseFloat(ratio, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return TracingConfig{}, fmt.Errorf("invalid sample ratio: %s", ratio)
+		}
+		sampleRatio = parsed
+	}
+
+	return TracingConfig{
+		Endpoint:    os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		ServiceName: serviceName,
+		SampleRatio: sampleRatio,
 	}, nil
 }
 
@@ -48,6 +48,7 @@ func TestLoad(t *testing.T) {
 				},
 				BaseURL: "https://example.com",
 				Port:    8080,
+				Tracing: TracingConfig{ServiceName: "uptimebot", SampleRatio: 1},
 			},
 			wantErr: false,
 		},
@@ -66,7 +67,12 @@ func TestLoad(t *testing.T) {
 				"DB_NAME":         "uptimebot",
 				"DB_SSL_MODE":     "disable",
 				"BASE_URL":        "https://example.com",
+				"METRICS_TOKEN":   "scrape-secret",
 				"PORT":            "3000",
+
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces",
+				"OTEL_SERVICE_NAME":                  "uptimebot-eu",
+				"OTEL_TRACES_SAMPLER_ARG":            "0.25",
 			},
 			want: &Config{
 				Email: EmailConfig{
@@ -84,8 +90,14 @@ func TestLoad(t *testing.T) {
 					DBName:   "uptimebot",
 					SSLMode:  "disable",
 				},
-				BaseURL: "https://example.com",
-				Port:    3000,
+				BaseURL:      "https://example.com",
+				Port:         3000,
+				MetricsToken: "scrape-secret",
+				Tracing: TracingConfig{
+					Endpoint:    "http://collector:4318/v1/traces",
+					ServiceName: "uptimebot-eu",
+					SampleRatio: 0.25,
+				},
 			},
 			wantErr: false,
 		},
@@ -136,6 +148,25 @@ func TestLoad(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "invalid sample ratio",
+			envVars: map[string]string{
+				"SMTP_HOST":               "smtp.example.com",
+				"SMTP_PORT":               "587",
+				"SMTP_USERNAME":           "test@example.com",
+				"SMTP_PASSWORD":           "password123",
+				"SMTP_EMAIL_FROM":         "sender@example.com",
+				"DB_HOST":                 "localhost",
+				"DB_PORT":                 "5432",
+				"DB_USER":                 "postgres",
+				"DB_PASSWORD":             "postgres",
+				"DB_NAME":                 "uptimebot",
+				"BASE_URL":                "https://example.com",
+				"OTEL_TRACES_SAMPLER_ARG": "2",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "invalid port number",
 			envVars: map[string]string{
@@ -1,12 +1,19 @@
 package database
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
-// Querier interface defines the common database operations
+// Querier interface defines the common database operations. The Context
+// variants carry the caller's context, so traced queries join its trace.
 type Querier interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // Ensure both *sql.DB and *sql.Tx implement Querier
@@ -0,0 +1,62 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE notifier ADD COLUMN user_id INTEGER;
+ALTER TABLE notifier ADD COLUMN name TEXT NOT NULL DEFAULT '';
+
+UPDATE notifier n
+SET user_id = t.user_id, name = n.type
+FROM target t
+WHERE t.id = n.target_id;
+
+ALTER TABLE notifier ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE notifier ADD FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE;
+
+CREATE TABLE target_notifier (
+    target_id INTEGER NOT NULL,
+    notifier_id INTEGER NOT NULL,
+    PRIMARY KEY (target_id, notifier_id),
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE,
+    FOREIGN KEY (notifier_id) REFERENCES notifier (id) ON DELETE CASCADE
+);
+
+-- Attach every target to the oldest notifier of its owner with the same configuration
+INSERT INTO target_notifier (target_id, notifier_id)
+SELECT n.target_id, MIN(c.id)
+FROM notifier n
+JOIN notifier c ON c.user_id = n.user_id AND c.type = n.type AND c.config = n.config
+GROUP BY n.id, n.target_id
+ON CONFLICT DO NOTHING;
+
+-- Drop the duplicated copies now that they are shared through target_notifier
+DELETE FROM notifier n
+WHERE EXISTS (
+    SELECT 1 FROM notifier c
+    WHERE c.user_id = n.user_id AND c.type = n.type AND c.config = n.config AND c.id < n.id
+);
+
+ALTER TABLE notifier DROP COLUMN target_id;
+
+CREATE INDEX idx_notifier_user_id ON notifier(user_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_notifier_user_id;
+
+ALTER TABLE notifier ADD COLUMN target_id INTEGER;
+
+-- Restore one notifier copy per attached target
+INSERT INTO notifier (target_id, user_id, name, type, config)
+SELECT tn.target_id, n.user_id, n.name, n.type, n.config
+FROM target_notifier tn
+JOIN notifier n ON n.id = tn.notifier_id;
+
+DELETE FROM notifier WHERE target_id IS NULL;
+
+ALTER TABLE notifier ALTER COLUMN target_id SET NOT NULL;
+ALTER TABLE notifier ADD FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE;
+
+DROP TABLE target_notifier;
+
+ALTER TABLE notifier DROP COLUMN name;
+ALTER TABLE notifier DROP COLUMN user_id;
+ALTER TABLE target DROP COLUMN tags;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE notifier ADD COLUMN events TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE notifier ADD COLUMN quiet_hours JSONB;
+
+CREATE TABLE notification_digest (
+    id SERIAL PRIMARY KEY,
+    notifier_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT NOT NULL,
+    raised_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (notifier_id) REFERENCES notifier (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_digest_notifier_id ON notification_digest(notifier_id);
+
+-- +migrate Down
+DROP INDEX idx_notification_digest_notifier_id;
+DROP TABLE notification_digest;
+
+ALTER TABLE notifier DROP COLUMN quiet_hours;
+ALTER TABLE notifier DROP COLUMN events;
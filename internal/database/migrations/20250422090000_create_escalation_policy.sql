@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE escalation_policy (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE TABLE escalation_step (
+    id SERIAL PRIMARY KEY,
+    policy_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    delay FLOAT NOT NULL,
+    notifier_ids INTEGER[] NOT NULL DEFAULT '{}',
+    UNIQUE (policy_id, position),
+    FOREIGN KEY (policy_id) REFERENCES escalation_policy (id) ON DELETE CASCADE
+);
+
+ALTER TABLE target ADD COLUMN escalation_policy_id INTEGER REFERENCES escalation_policy (id) ON DELETE SET NULL;
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN escalation_policy_id;
+
+DROP TABLE escalation_step;
+DROP TABLE escalation_policy;
@@ -0,0 +1,43 @@
+-- +migrate Up
+CREATE TABLE oncall_schedule (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    rotation TEXT NOT NULL,
+    handoff TEXT NOT NULL DEFAULT '09:00',
+    starts_on DATE NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE TABLE oncall_member (
+    schedule_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (schedule_id, position),
+    FOREIGN KEY (schedule_id) REFERENCES oncall_schedule (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE TABLE oncall_override (
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    CHECK (ends_at > starts_at),
+    FOREIGN KEY (schedule_id) REFERENCES oncall_schedule (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oncall_override_schedule_id ON oncall_override(schedule_id);
+
+ALTER TABLE escalation_step ADD COLUMN schedule_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE escalation_step DROP COLUMN schedule_ids;
+
+DROP INDEX idx_oncall_override_schedule_id;
+DROP TABLE oncall_override;
+DROP TABLE oncall_member;
+DROP TABLE oncall_schedule;
@@ -0,0 +1,39 @@
+-- +migrate Up
+CREATE TABLE incident (
+    id SERIAL PRIMARY KEY,
+    target_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    cause TEXT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    acknowledged_at TIMESTAMP,
+    acknowledged_by INTEGER REFERENCES usr (id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    escalation_step INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incident_target_id ON incident(target_id);
+CREATE INDEX idx_incident_started_at ON incident(started_at);
+-- At most one unresolved incident per target
+CREATE UNIQUE INDEX idx_incident_unresolved ON incident(target_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE incident_event (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    message TEXT NOT NULL,
+    user_id INTEGER REFERENCES usr (id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (incident_id) REFERENCES incident (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incident_event_incident_id ON incident_event(incident_id);
+
+-- +migrate Down
+DROP INDEX idx_incident_event_incident_id;
+DROP TABLE incident_event;
+
+DROP INDEX idx_incident_unresolved;
+DROP INDEX idx_incident_started_at;
+DROP INDEX idx_incident_target_id;
+DROP TABLE incident;
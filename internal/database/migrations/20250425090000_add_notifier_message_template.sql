@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notifier ADD COLUMN message_template JSONB;
+
+-- +migrate Down
+ALTER TABLE notifier DROP COLUMN message_template;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notifier ADD COLUMN rate_limit INTEGER NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE notifier DROP COLUMN rate_limit;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE check_result (
+    id BIGSERIAL PRIMARY KEY,
+    target_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    duration_ms INTEGER NOT NULL,
+    error_class TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_check_result_target_checked_at ON check_result(target_id, checked_at);
+
+ALTER TABLE usr ADD COLUMN digest_frequency TEXT NOT NULL DEFAULT 'weekly';
+ALTER TABLE usr ADD COLUMN digest_sent_at TIMESTAMP;
+
+-- +migrate Down
+ALTER TABLE usr DROP COLUMN digest_sent_at;
+ALTER TABLE usr DROP COLUMN digest_frequency;
+
+DROP INDEX idx_check_result_target_checked_at;
+DROP TABLE check_result;
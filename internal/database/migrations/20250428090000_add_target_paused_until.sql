@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN paused_until TIMESTAMP;
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN paused_until;
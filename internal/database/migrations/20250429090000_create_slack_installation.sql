@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE slack_installation (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    team_id TEXT NOT NULL,
+    slack_user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+-- A Slack account acts as a single uptimebot user
+CREATE UNIQUE INDEX idx_slack_installation_account ON slack_installation(team_id, slack_user_id);
+
+-- +migrate Down
+DROP INDEX idx_slack_installation_account;
+DROP TABLE slack_installation;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE api_token (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE,
+    UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_api_token_user_id ON api_token(user_id);
+
+-- +migrate Down
+DROP INDEX idx_api_token_user_id;
+DROP TABLE api_token;
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE status_page (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    title TEXT NOT NULL,
+    logo_url TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_status_page_domain ON status_page(domain) WHERE domain <> '';
+
+CREATE TABLE status_page_component (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    target_ids INTEGER[] NOT NULL DEFAULT '{}',
+    FOREIGN KEY (page_id) REFERENCES status_page (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_status_page_component_page_id ON status_page_component(page_id);
+
+-- +migrate Down
+DROP INDEX idx_status_page_component_page_id;
+DROP TABLE status_page_component;
+DROP INDEX idx_status_page_domain;
+DROP TABLE status_page;
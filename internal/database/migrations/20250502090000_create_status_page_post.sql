@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE status_page_post (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    status TEXT NOT NULL,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (page_id) REFERENCES status_page (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_status_page_post_page_id ON status_page_post(page_id, updated_at);
+
+CREATE TABLE status_page_post_update (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (post_id) REFERENCES status_page_post (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_status_page_post_update_post_id ON status_page_post_update(post_id);
+
+-- +migrate Down
+DROP INDEX idx_status_page_post_update_post_id;
+DROP TABLE status_page_post_update;
+DROP INDEX idx_status_page_post_page_id;
+DROP TABLE status_page_post;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE status_page_subscriber (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    address TEXT NOT NULL,
+    confirm_token TEXT NOT NULL UNIQUE,
+    confirm_expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    unsubscribe_token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (page_id, kind, address),
+    FOREIGN KEY (page_id) REFERENCES status_page (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE status_page_subscriber;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN badge_token TEXT;
+CREATE UNIQUE INDEX idx_target_badge_token ON target(badge_token);
+
+-- +migrate Down
+DROP INDEX idx_target_badge_token;
+ALTER TABLE target DROP COLUMN badge_token;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE maintenance_window (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    recurrence TEXT NOT NULL DEFAULT '',
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    target_ids INTEGER[] NOT NULL DEFAULT '{}',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_maintenance_window_user_id ON maintenance_window(user_id);
+
+-- +migrate Down
+DROP INDEX idx_maintenance_window_user_id;
+DROP TABLE maintenance_window;
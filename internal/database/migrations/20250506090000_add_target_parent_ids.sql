@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN parent_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN parent_ids;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE slo (
+    id SERIAL PRIMARY KEY,
+    target_id INTEGER NOT NULL,
+    objective DOUBLE PRECISION NOT NULL,
+    window_days INTEGER NOT NULL,
+    alert_level TEXT NOT NULL DEFAULT '',
+    alerted_at TIMESTAMP,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_slo_target_id ON slo(target_id);
+
+-- +migrate Down
+DROP INDEX idx_slo_target_id;
+DROP TABLE slo;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN flap_threshold INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE target ADD COLUMN flap_window FLOAT NOT NULL DEFAULT 900;
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN flap_window;
+ALTER TABLE target DROP COLUMN flap_threshold;
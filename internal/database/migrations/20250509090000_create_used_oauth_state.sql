@@ -0,0 +1,13 @@
+-- +migrate Up
+-- OAuth states are signed and carry their own expiry. The ones already used
+-- are kept until they expire, so that each state is only accepted once.
+CREATE TABLE used_oauth_state (
+    nonce TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_used_oauth_state_expires_at ON used_oauth_state(expires_at);
+
+-- +migrate Down
+DROP INDEX idx_used_oauth_state_expires_at;
+DROP TABLE used_oauth_state;
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE status_page ADD COLUMN domain_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE status_page ADD COLUMN domain_verified_at TIMESTAMP;
+
+-- Domains claimed so far have to prove their owner too
+UPDATE status_page SET domain_token = md5(random()::text || id::text) WHERE domain <> '';
+
+-- Only a verified page holds its domain, so an unproven claim cannot keep the
+-- owner of the domain from using it
+DROP INDEX idx_status_page_domain;
+CREATE UNIQUE INDEX idx_status_page_domain ON status_page(domain) WHERE domain_verified_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX idx_status_page_domain;
+CREATE UNIQUE INDEX idx_status_page_domain ON status_page(domain) WHERE domain <> '';
+ALTER TABLE status_page DROP COLUMN domain_verified_at;
+ALTER TABLE status_page DROP COLUMN domain_token;
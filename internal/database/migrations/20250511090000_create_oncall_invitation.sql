@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE oncall_invitation (
+    owner_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    accepted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (owner_id, user_id),
+    CHECK (owner_id <> user_id),
+    FOREIGN KEY (owner_id) REFERENCES usr (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES usr (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oncall_invitation_user_id ON oncall_invitation(user_id);
+
+-- People already on someone else's rotation are asked to confirm it; until
+-- they accept, they are left out of it
+INSERT INTO oncall_invitation (owner_id, user_id)
+SELECT s.user_id, m.user_id FROM oncall_member m JOIN oncall_schedule s ON s.id = m.schedule_id WHERE m.user_id <> s.user_id
+UNION
+SELECT s.user_id, o.user_id FROM oncall_override o JOIN oncall_schedule s ON s.id = o.schedule_id WHERE o.user_id <> s.user_id;
+
+-- +migrate Down
+DROP INDEX idx_oncall_invitation_user_id;
+DROP TABLE oncall_invitation;
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type querierMock struct {
+	err error
+}
+
+func (m *querierMock) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, m.err
+}
+
+func (m *querierMock) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, m.err
+}
+
+func (m *querierMock) QueryRow(query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (m *querierMock) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, m.err
+}
+
+func (m *querierMock) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, m.err
+}
+
+func (m *querierMock) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestTraced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	failed := errors.New("relation does not exist")
+	db := Traced(&querierMock{err: failed})
+
+	_, err := db.Exec("  update target SET url = $1 WHERE id = $2", "https://example.com", 1)
+	assert.ErrorIs(t, err, failed)
+	db.QueryRow("SELECT id FROM target WHERE id = $1", 1)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "UPDATE", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.DBSystemPostgreSQL)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBQueryText("  update target SET url = $1 WHERE id = $2"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	assert.Equal(t, "SELECT", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestTraced_Context(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "Target.Check")
+	db := Traced(&querierMock{})
+	_, err := db.ExecContext(ctx, "INSERT INTO check_result (target_id) VALUES ($1)", 1)
+	assert.NoError(t, err)
+	db.QueryRowContext(ctx, "SELECT id FROM target WHERE id = $1", 1)
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	for _, span := range spans[:2] {
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WithTx runs fn in a transaction, committing it when fn returns nil and
+// rolling it back otherwise. A traced database hands fn a traced transaction.
+// Inside a transaction already, such as the one repository tests run in, fn
+// works under a savepoint so its failure only undoes its own work. Any other
+// Querier is passed to fn as it is.
+func WithTx(ctx context.Context, q Querier, fn func(tx Querier) error) error {
+	switch db := q.(type) {
+	case *TracedQuerier:
+		return WithTx(ctx, db.q, func(tx Querier) error {
+			return fn(Traced(tx))
+		})
+	case *sql.DB:
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	case *sql.Tx:
+		if _, err := db.ExecContext(ctx, "SAVEPOINT with_tx"); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		if err := fn(db); err != nil {
+			db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT with_tx")
+			return err
+		}
+		if _, err := db.ExecContext(ctx, "RELEASE SAVEPOINT with_tx"); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		return nil
+	default:
+		return fn(q)
+	}
+}
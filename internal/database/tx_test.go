@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTx(t *testing.T) {
+	t.Run("runs in an open transaction as it is", func(t *testing.T) {
+		q := &querierMock{}
+		var got Querier
+		err := WithTx(context.Background(), q, func(tx Querier) error {
+			got = tx
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Same(t, q, got)
+	})
+
+	t.Run("returns the error of fn", func(t *testing.T) {
+		failed := errors.New("cycle")
+		err := WithTx(context.Background(), &querierMock{}, func(tx Querier) error {
+			return failed
+		})
+		assert.ErrorIs(t, err, failed)
+	})
+}
@@ -11,6 +11,7 @@ type Mailer interface {
 	SetTo(string) error
 	SetSubject(string) error
 	SetBody(string) error
+	SetHeader(name string, value string) error
 	SendEmail() error
 }
 
@@ -36,6 +37,7 @@ func NewEmailService(config *config.EmailConfig) (*MailService, error) {
 	return &MailService{
 		server: server,
 		mail:   NewEmail(config.From),
+		from:   config.From,
 	}, nil
 }
 
@@ -52,6 +54,7 @@ func NewEmail(from string) *mail.Email {
 type MailService struct {
 	server *mail.SMTPServer
 	mail   *mail.Email
+	from   string
 }
 
 func (e *MailService) SetTo(to string) error {
@@ -78,7 +81,20 @@ func (e *MailService) SetBody(body string) error {
 	return nil
 }
 
+// SetHeader adds a header to the message, such as List-Unsubscribe
+func (e *MailService) SetHeader(name string, value string) error {
+	if name == "" {
+		return fmt.Errorf("header name cannot be empty")
+	}
+	e.mail.AddHeader(name, value)
+	return nil
+}
+
+// SendEmail sends the message and starts a new one, so recipients of one
+// email never carry over to the next
 func (e *MailService) SendEmail() error {
+	defer func() { e.mail = NewEmail(e.from) }()
+
 	server, err := e.server.Connect()
 	if err != nil {
 		return err
@@ -102,6 +102,19 @@ func TestEmailService_SetBody(t *testing.T) {
 	})
 }
 
+func TestEmailService_SetHeader(t *testing.T) {
+	service := &MailService{
+		mail: NewEmail("sender@example.com"),
+	}
+	service.SetTo("recipient@example.com")
+	service.SetSubject("Test Subject")
+	service.SetBody("<h1>Test Body</h1>")
+
+	assert.NoError(t, service.SetHeader("List-Unsubscribe", "<https://example.com/unsubscribe>"))
+	assert.Error(t, service.SetHeader("", "value"))
+	assert.Contains(t, service.mail.GetMessage(), "List-Unsubscribe: <https://example.com/unsubscribe>")
+}
+
 func TestEmailService_SendEmail(t *testing.T) {
 	// Create test config
 	emailConfig := &config.EmailConfig{
@@ -163,3 +176,14 @@ func TestEmailService_SendEmailWithConnectionError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "dial tcp: lookup invalid.host")
 }
+
+func TestEmailService_SendEmailStartsNewMessage(t *testing.T) {
+	service, err := NewEmailService(&config.EmailConfig{Host: "localhost", Port: 1, From: "sender@example.com"})
+	assert.NoError(t, err)
+
+	service.SetTo("first@example.com")
+	service.SendEmail()
+
+	service.SetTo("second@example.com")
+	assert.Equal(t, []string{"second@example.com"}, service.mail.GetRecipients())
+}
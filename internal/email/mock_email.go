@@ -10,12 +10,14 @@ type MailServiceMock struct {
 	SetToFunc      func(to string) error
 	SetSubjectFunc func(subject string) error
 	SetBodyFunc    func(body string) error
+	SetHeaderFunc  func(name string, value string) error
 	SendEmailFunc  func() error
 
 	calls struct {
 		SetTo      []string
 		SetSubject []string
 		SetBody    []string
+		SetHeader  []string
 		SendEmail  int
 	}
 }
@@ -56,6 +58,17 @@ func (m *MailServiceMock) SetBody(body string) error {
 	return nil
 }
 
+func (m *MailServiceMock) SetHeader(name string, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls.SetHeader = append(m.calls.SetHeader, name+": "+value)
+	if m.SetHeaderFunc != nil {
+		return m.SetHeaderFunc(name, value)
+	}
+	return nil
+}
+
 func (m *MailServiceMock) SendEmail() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -87,6 +100,13 @@ func (m *MailServiceMock) GetSetBodyCalls() []string {
 	return append([]string{}, m.calls.SetBody...)
 }
 
+// GetSetHeaderCalls returns the headers set, as "name: value"
+func (m *MailServiceMock) GetSetHeaderCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.calls.SetHeader...)
+}
+
 func (m *MailServiceMock) GetSendEmailCallCount() int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
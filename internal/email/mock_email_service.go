@@ -4,6 +4,7 @@ type EmailServiceMock struct {
 	SetToFunc      func(to string) error
 	SetSubjectFunc func(subject string) error
 	SetBodyFunc    func(body string) error
+	SetHeaderFunc  func(name string, value string) error
 	SendEmailFunc  func() error
 }
 
@@ -19,6 +20,13 @@ func (m *EmailServiceMock) SetBody(body string) error {
 	return m.SetBodyFunc(body)
 }
 
+func (m *EmailServiceMock) SetHeader(name string, value string) error {
+	if m.SetHeaderFunc == nil {
+		return nil
+	}
+	return m.SetHeaderFunc(name, value)
+}
+
 func (m *EmailServiceMock) SendEmail() error {
 	return m.SendEmailFunc()
 }
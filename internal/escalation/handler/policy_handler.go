@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/service"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	oncallModel "github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+// maxStepRows is the number of step rows a policy form can submit
+const maxStepRows = 10
+
+type PolicyHandler struct {
+	policyService service.PolicyServiceInterface
+	flash         flash.FlashStoreInterface
+	Template      struct {
+		List   *renderer.Template
+		Create *renderer.Template
+		Edit   *renderer.Template
+	}
+}
+
+func NewPolicyHandler(policyService service.PolicyServiceInterface, flash flash.FlashStoreInterface) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+		flash:         flash,
+	}
+}
+
+// stepRow is one row of the step table of the policy form
+type stepRow struct {
+	Index     int
+	Number    int
+	Delay     string
+	Selected  map[int]bool
+	Schedules map[int]bool
+}
+
+// errorStatus maps escalation policy service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrPolicyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// stepRows lays out the policy's steps followed by a few blank rows to add more
+func stepRows(steps []model.Step) []stepRow {
+	count := min(max(len(steps)+2, 3), maxStepRows)
+
+	rows := make([]stepRow, count)
+	for i := range rows {
+		rows[i] = stepRow{Index: i, Number: i + 1, Selected: map[int]bool{}, Schedules: map[int]bool{}}
+		if i < len(steps) {
+			rows[i].Delay = strconv.FormatFloat(steps[i].Delay.Minutes(), 'f', -1, 64)
+			for _, id := range steps[i].NotifierIDs {
+				rows[i].Selected[id] = true
+			}
+			for _, id := range steps[i].ScheduleIDs {
+				rows[i].Schedules[id] = true
+			}
+		}
+	}
+	return rows
+}
+
+// parseIDs converts the submitted values of a multi-value field to IDs
+func parseIDs(values []string) ([]int, error) {
+	ids := make([]int, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parsePolicy reads the policy and the targets it applies to from the submitted
+// form. Rows without a delay, notifiers and schedules are skipped.
+func parsePolicy(r *http.Request) (*model.Policy, []int, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, err
+	}
+
+	policy := &model.Policy{Name: strings.TrimSpace(r.FormValue("name"))}
+	for i := 0; i < maxStepRows; i++ {
+		delay := strings.TrimSpace(r.FormValue(fmt.Sprintf("delay_%d", i)))
+		notifiers := r.Form[fmt.Sprintf("notifiers_%d", i)]
+		schedules := r.Form[fmt.Sprintf("schedules_%d", i)]
+		if delay == "" && len(notifiers) == 0 && len(schedules) == 0 {
+			continue
+		}
+
+		minutes, err := strconv.ParseFloat(delay, 64)
+		if err != nil || minutes < 0 {
+			return nil, nil, fmt.Errorf("step %d: delay must be a number of minutes", i+1)
+		}
+		notifierIDs, err := parseIDs(notifiers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		scheduleIDs, err := parseIDs(schedules)
+		if err != nil {
+			return nil, nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		policy.Steps = append(policy.Steps, model.Step{
+			Delay:       time.Duration(minutes * float64(time.Minute)),
+			NotifierIDs: notifierIDs,
+			ScheduleIDs: scheduleIDs,
+		})
+	}
+
+	targetIDs, err := parseIDs(r.Form["targets"])
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, targetIDs, nil
+}
+
+// formData gathers the notifiers, schedules and targets the policy form offers
+func (ph *PolicyHandler) formData(ctx context.Context, userID int) (map[string]any, error) {
+	notifiers, err := ph.policyService.GetNotifiers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := ph.policyService.GetSchedules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := ph.policyService.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"notifiers": notifiers,
+		"schedules": schedules,
+		"targets":   targets,
+	}, nil
+}
+
+// List shows the user's escalation policies
+func (ph *PolicyHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	policies, err := ph.policyService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	notifiers, err := ph.policyService.GetNotifiers(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	schedules, err := ph.policyService.GetSchedules(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title":     "escalation policies",
+		"policies":  policies,
+		"summaries": summarize(policies, notifiers, schedules),
+	}
+
+	ph.Template.List.Render(w, r, data)
+}
+
+// summarize describes each step of the policies, e.g. "after 10m: #ops, on call for Primary"
+func summarize(policies []*model.Policy, notifiers []*notifModel.Notifier, schedules []*oncallModel.Schedule) map[int][]string {
+	names := make(map[int]string, len(notifiers))
+	for _, notifier := range notifiers {
+		names[notifier.ID] = notifier.Name
+	}
+	scheduleNames := make(map[int]string, len(schedules))
+	for _, schedule := range schedules {
+		scheduleNames[schedule.ID] = schedule.Name
+	}
+
+	summaries := make(map[int][]string, len(policies))
+	for _, policy := range policies {
+		for _, step := range policy.Steps {
+			recipients := make([]string, 0, len(step.NotifierIDs))
+			for _, id := range step.NotifierIDs {
+				if name, ok := names[id]; ok {
+					recipients = append(recipients, name)
+				}
+			}
+			for _, id := range step.ScheduleIDs {
+				if name, ok := scheduleNames[id]; ok {
+					recipients = append(recipients, "on call for "+name)
+				}
+			}
+
+			when := "immediately"
+			if step.Delay > 0 {
+				when = "after " + step.Delay.String()
+			}
+			summaries[policy.ID] = append(summaries[policy.ID], when+": "+strings.Join(recipients, ", "))
+		}
+	}
+	return summaries
+}
+
+func (ph *PolicyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data, err := ph.formData(r.Context(), user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data["title"] = "add an escalation policy"
+		data["steps"] = stepRows(nil)
+		data["attached"] = map[int]bool{}
+
+		ph.Template.Create.Render(w, r, data)
+		return
+	}
+
+	createURL := "/app/escalation-policies/create"
+	policy, targetIDs, err := parsePolicy(r)
+	if err != nil {
+		ph.flash.SetErrors(r.Context(), []string{"Invalid escalation policy: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := ph.policyService.Create(r.Context(), policy, user.ID, targetIDs); err != nil {
+		ph.flash.SetErrors(r.Context(), []string{"Failed to create escalation policy: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	ph.flash.SetSuccesses(r.Context(), []string{"Escalation policy created successfully"})
+	http.Redirect(w, r, "/app/escalation-policies", http.StatusSeeOther)
+}
+
+func (ph *PolicyHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/escalation-policies/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	policy, err := ph.policyService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data, err := ph.formData(r.Context(), user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		attached := map[int]bool{}
+		for _, target := range data["targets"].([]*model.PolicyTarget) {
+			if target.PolicyID != nil && *target.PolicyID == policy.ID {
+				attached[target.ID] = true
+			}
+		}
+
+		data["title"] = "edit escalation policy"
+		data["policy"] = policy
+		data["steps"] = stepRows(policy.Steps)
+		data["attached"] = attached
+
+		ph.Template.Edit.Render(w, r, data)
+		return
+	}
+
+	updated, targetIDs, err := parsePolicy(r)
+	if err != nil {
+		ph.flash.SetErrors(r.Context(), []string{"Invalid escalation policy: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	updated.ID = policy.ID
+
+	if _, err := ph.policyService.Update(r.Context(), updated, user.ID, targetIDs); err != nil {
+		ph.flash.SetErrors(r.Context(), []string{"Failed to update escalation policy: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+
+	ph.flash.SetSuccesses(r.Context(), []string{"Escalation policy updated successfully"})
+	http.Redirect(w, r, "/app/escalation-policies", http.StatusSeeOther)
+}
+
+func (ph *PolicyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := ph.policyService.Delete(r.Context(), id, user.ID); err != nil {
+		ph.flash.SetErrors(r.Context(), []string{"Failed to delete escalation policy: " + err.Error()})
+		http.Redirect(w, r, "/app/escalation-policies", http.StatusSeeOther)
+		return
+	}
+
+	ph.flash.SetSuccesses(r.Context(), []string{"Escalation policy deleted successfully"})
+	http.Redirect(w, r, "/app/escalation-policies", http.StatusSeeOther)
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/service"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	oncallModel "github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockPolicyService struct {
+	createFunc       func(policy *model.Policy, userID int, targetIDs []int) error
+	getFunc          func(id int, userID int) (*model.Policy, error)
+	getByUserIDFunc  func(userID int) ([]*model.Policy, error)
+	updateFunc       func(policy *model.Policy, userID int, targetIDs []int) (*model.Policy, error)
+	deleteFunc       func(id int, userID int) error
+	getTargetsFunc   func(userID int) ([]*model.PolicyTarget, error)
+	getNotifiersFunc func(userID int) ([]*notifModel.Notifier, error)
+	getSchedulesFunc func(userID int) ([]*oncallModel.Schedule, error)
+}
+
+func (m *MockPolicyService) Create(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) error {
+	return m.createFunc(policy, userID, targetIDs)
+}
+
+func (m *MockPolicyService) Get(ctx context.Context, id int, userID int) (*model.Policy, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockPolicyService) GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *MockPolicyService) Update(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) (*model.Policy, error) {
+	return m.updateFunc(policy, userID, targetIDs)
+}
+
+func (m *MockPolicyService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *MockPolicyService) GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error) {
+	return m.getTargetsFunc(userID)
+}
+
+func (m *MockPolicyService) GetNotifiers(ctx context.Context, userID int) ([]*notifModel.Notifier, error) {
+	return m.getNotifiersFunc(userID)
+}
+
+func (m *MockPolicyService) GetSchedules(ctx context.Context, userID int) ([]*oncallModel.Schedule, error) {
+	return m.getSchedulesFunc(userID)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID})
+	return req.WithContext(ctx)
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func newTestPolicyHandler(mockService *MockPolicyService) *PolicyHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewPolicyHandler(mockService, mockFlashStore)
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:escalation/list")
+	handler.Template.Create = templateRenderer.GetTemplate("pages:escalation/create")
+	handler.Template.Edit = templateRenderer.GetTemplate("pages:escalation/edit")
+	return handler
+}
+
+func newMockPolicyService() *MockPolicyService {
+	policyID := 1
+	return &MockPolicyService{
+		getFunc: func(id int, userID int) (*model.Policy, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return &model.Policy{ID: id, UserID: 1, Name: "ops", Steps: []model.Step{
+				{Delay: 0, NotifierIDs: []int{1}},
+				{Position: 1, Delay: 10 * time.Minute, NotifierIDs: []int{2}, ScheduleIDs: []int{3}},
+			}}, nil
+		},
+		getNotifiersFunc: func(userID int) ([]*notifModel.Notifier, error) {
+			return []*notifModel.Notifier{{ID: 1, Name: "#ops"}, {ID: 2, Name: "#leads"}}, nil
+		},
+		getSchedulesFunc: func(userID int) ([]*oncallModel.Schedule, error) {
+			return []*oncallModel.Schedule{{ID: 3, Name: "Primary"}}, nil
+		},
+		getTargetsFunc: func(userID int) ([]*model.PolicyTarget, error) {
+			return []*model.PolicyTarget{
+				{ID: 1, URL: "https://a.example.org", PolicyID: &policyID},
+				{ID: 2, URL: "https://b.example.org"},
+			}, nil
+		},
+	}
+}
+
+func TestPolicyHandler_List(t *testing.T) {
+	mockService := newMockPolicyService()
+	mockService.getByUserIDFunc = func(userID int) ([]*model.Policy, error) {
+		policy, _ := mockService.getFunc(1, userID)
+		return []*model.Policy{policy}, nil
+	}
+	handler := newTestPolicyHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/escalation-policies", nil)
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "immediately: #ops")
+	assert.Contains(t, w.Body.String(), "after 10m0s: #leads, on call for Primary")
+	assert.Contains(t, w.Body.String(), "/app/escalation-policies/edit/1")
+}
+
+func TestPolicyHandler_Create(t *testing.T) {
+	mockService := newMockPolicyService()
+	handler := newTestPolicyHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/escalation-policies/create", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `name="notifiers_2"`)
+		assert.Contains(t, w.Body.String(), "https://b.example.org")
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var created *model.Policy
+		var attached []int
+		mockService.createFunc = func(policy *model.Policy, userID int, targetIDs []int) error {
+			created = policy
+			attached = targetIDs
+			return nil
+		}
+
+		form := url.Values{
+			"name":        {"ops"},
+			"delay_0":     {"0"},
+			"notifiers_0": {"1"},
+			"delay_2":     {"30"},
+			"notifiers_2": {"1", "2"},
+			"schedules_2": {"3"},
+			"targets":     {"2"},
+		}
+		req := postForm("/app/escalation-policies/create", form)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/escalation-policies", w.Header().Get("Location"))
+		assert.Len(t, created.Steps, 2)
+		assert.Equal(t, 30*time.Minute, created.Steps[1].Delay)
+		assert.Equal(t, []int{1, 2}, created.Steps[1].NotifierIDs)
+		assert.Equal(t, []int{3}, created.Steps[1].ScheduleIDs)
+		assert.Equal(t, []int{2}, attached)
+	})
+
+	t.Run("POST request - invalid delay", func(t *testing.T) {
+		form := url.Values{
+			"name":        {"ops"},
+			"delay_0":     {"soon"},
+			"notifiers_0": {"1"},
+		}
+		req := postForm("/app/escalation-policies/create", form)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/escalation-policies/create", w.Header().Get("Location"))
+	})
+}
+
+func TestPolicyHandler_Edit(t *testing.T) {
+	mockService := newMockPolicyService()
+	handler := newTestPolicyHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/escalation-policies/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `value="ops"`)
+		assert.Contains(t, w.Body.String(), `name="delay_1" value="10"`)
+		assert.Contains(t, w.Body.String(), `name="schedules_1" value="3" checked`)
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/escalation-policies/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var updated *model.Policy
+		mockService.updateFunc = func(policy *model.Policy, userID int, targetIDs []int) (*model.Policy, error) {
+			updated = policy
+			return policy, nil
+		}
+
+		form := url.Values{"name": {"renamed"}, "delay_0": {"5"}, "notifiers_0": {"2"}}
+		req := postForm("/app/escalation-policies/edit/1", form)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, 1, updated.ID)
+		assert.Equal(t, "renamed", updated.Name)
+	})
+}
+
+func TestPolicyHandler_Delete(t *testing.T) {
+	deleted := false
+	mockService := &MockPolicyService{
+		deleteFunc: func(id int, userID int) error {
+			if userID != 1 {
+				return service.ErrUnauthorized
+			}
+			deleted = true
+			return nil
+		},
+	}
+	handler := newTestPolicyHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/escalation-policies/delete/1", nil)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 2)
+	w := httptest.NewRecorder()
+	handler.Delete(w, req)
+	assert.False(t, deleted)
+
+	req = httptest.NewRequest(http.MethodPost, "/app/escalation-policies/delete/1", nil)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w = httptest.NewRecorder()
+	handler.Delete(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.True(t, deleted)
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Step notifies a set of notifiers, and whoever is on call on a set of
+// schedules, once an incident has lasted for Delay
+type Step struct {
+	Position    int           `db:"position"`
+	Delay       time.Duration `db:"delay"`
+	NotifierIDs []int         `db:"notifier_ids"`
+	ScheduleIDs []int         `db:"schedule_ids"`
+}
+
+// Policy is an ordered chain of steps run against the open incidents of the
+// targets it is attached to
+type Policy struct {
+	ID     int    `db:"id"`
+	UserID int    `db:"user_id"`
+	Name   string `db:"name"`
+	Steps  []Step
+}
+
+// Validate checks the policy has a name and well ordered steps that notify someone
+func (p *Policy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+
+	for i, step := range p.Steps {
+		if step.Delay < 0 {
+			return fmt.Errorf("step %d: delay cannot be negative", i+1)
+		}
+		if i > 0 && step.Delay < p.Steps[i-1].Delay {
+			return fmt.Errorf("step %d: delay must not be shorter than the previous step", i+1)
+		}
+		if len(step.NotifierIDs) == 0 && len(step.ScheduleIDs) == 0 {
+			return fmt.Errorf("step %d: at least one notifier or schedule is required", i+1)
+		}
+	}
+	return nil
+}
+
+// DueSteps returns the steps to run for an incident that has lasted for
+// elapsed and already ran the first fired steps
+func (p *Policy) DueSteps(fired int, elapsed time.Duration) []Step {
+	var due []Step
+	for i := fired; i < len(p.Steps); i++ {
+		if p.Steps[i].Delay > elapsed {
+			break
+		}
+		due = append(due, p.Steps[i])
+	}
+	return due
+}
+
+// Escalation is an open incident of a target that has an escalation policy
+type Escalation struct {
+	IncidentID  int        `db:"id"`
+	PolicyID    int        `db:"escalation_policy_id"`
+	TargetID    int        `db:"target_id"`
+	TargetURL   string     `db:"url"`
+	PausedUntil *time.Time `db:"paused_until"`
+	Cause       string     `db:"cause"`
+	StartedAt   time.Time  `db:"started_at"`
+	Fired       int        `db:"escalation_step"`
+}
+
+// Paused reports whether the checks of the target are paused at the given time
+func (e *Escalation) Paused(at time.Time) bool {
+	return e.PausedUntil != nil && at.Before(*e.PausedUntil)
+}
+
+// Path returns the dashboard page of the escalated incident, relative to the base URL
+func (e *Escalation) Path() string {
+	return fmt.Sprintf("/app/incidents/%d", e.IncidentID)
+}
+
+// PolicyTarget is a target that an escalation policy can be attached to
+type PolicyTarget struct {
+	ID       int    `db:"id"`
+	URL      string `db:"url"`
+	PolicyID *int   `db:"escalation_policy_id"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{
+			name: "valid policy",
+			policy: Policy{Name: "ops", Steps: []Step{
+				{Delay: 0, NotifierIDs: []int{1}},
+				{Delay: 10 * time.Minute, NotifierIDs: []int{2}},
+				{Delay: 30 * time.Minute, NotifierIDs: []int{3}},
+			}},
+		},
+		{
+			name:    "missing name",
+			policy:  Policy{Steps: []Step{{NotifierIDs: []int{1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "no steps",
+			policy:  Policy{Name: "ops"},
+			wantErr: true,
+		},
+		{
+			name:    "step without notifiers",
+			policy:  Policy{Name: "ops", Steps: []Step{{Delay: time.Minute}}},
+			wantErr: true,
+		},
+		{
+			name:   "on-call schedule only",
+			policy: Policy{Name: "ops", Steps: []Step{{Delay: time.Minute, ScheduleIDs: []int{1}}}},
+		},
+		{
+			name: "decreasing delays",
+			policy: Policy{Name: "ops", Steps: []Step{
+				{Delay: 10 * time.Minute, NotifierIDs: []int{1}},
+				{Delay: 5 * time.Minute, NotifierIDs: []int{2}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_DueSteps(t *testing.T) {
+	policy := Policy{Name: "ops", Steps: []Step{
+		{Position: 0, Delay: 0, NotifierIDs: []int{1}},
+		{Position: 1, Delay: 10 * time.Minute, NotifierIDs: []int{2}},
+		{Position: 2, Delay: 30 * time.Minute, NotifierIDs: []int{3}},
+	}}
+
+	assert.Len(t, policy.DueSteps(0, time.Second), 1)
+	assert.Empty(t, policy.DueSteps(1, 5*time.Minute))
+
+	due := policy.DueSteps(1, 12*time.Minute)
+	assert.Len(t, due, 1)
+	assert.Equal(t, 1, due[0].Position)
+
+	// An evaluator that fell behind catches up on every overdue step
+	due = policy.DueSteps(0, time.Hour)
+	assert.Len(t, due, 3)
+
+	assert.Empty(t, policy.DueSteps(3, time.Hour))
+}
+
+func TestEscalation_Paused(t *testing.T) {
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	assert.False(t, (&Escalation{}).Paused(now))
+	assert.True(t, (&Escalation{PausedUntil: &later}).Paused(now))
+	assert.False(t, (&Escalation{PausedUntil: &earlier}).Paused(now))
+}
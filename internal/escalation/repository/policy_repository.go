@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
+)
+
+var (
+	ErrPolicyNotFound = errors.New("escalation policy not found")
+)
+
+type PolicyRepositoryInterface interface {
+	Create(ctx context.Context, policy *model.Policy) (*model.Policy, error)
+	Get(ctx context.Context, id int) (*model.Policy, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error)
+	Update(ctx context.Context, policy *model.Policy) (*model.Policy, error)
+	Delete(ctx context.Context, id int) error
+	GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error)
+	SetTargets(ctx context.Context, policyID int, userID int, targetIDs []int) error
+	GetOpenIncidents(ctx context.Context) ([]*model.Escalation, error)
+	AdvanceIncident(ctx context.Context, incidentID int, fired int) error
+}
+
+var _ PolicyRepositoryInterface = (*PolicyRepository)(nil)
+
+// PolicyRepository handles database operations for escalation policies
+type PolicyRepository struct {
+	db database.Querier
+}
+
+// NewPolicyRepository creates a new escalation policy repository
+func NewPolicyRepository(db database.Querier) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+// Create inserts a policy along with its steps
+func (r *PolicyRepository) Create(ctx context.Context, policy *model.Policy) (*model.Policy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO escalation_policy (user_id, name) VALUES ($1, $2) RETURNING id`
+
+	newPolicy := *policy
+	if err := r.db.QueryRowContext(ctx, query, policy.UserID, policy.Name).Scan(&newPolicy.ID); err != nil {
+		return nil, fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+
+	if err := r.insertSteps(ctx, newPolicy.ID, newPolicy.Steps); err != nil {
+		return nil, err
+	}
+
+	return &newPolicy, nil
+}
+
+// insertSteps stores the steps of a policy in order
+func (r *PolicyRepository) insertSteps(ctx context.Context, policyID int, steps []model.Step) error {
+	query := `
+		INSERT INTO escalation_step (policy_id, position, delay, notifier_ids, schedule_ids)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for i := range steps {
+		steps[i].Position = i
+		_, err := r.db.ExecContext(ctx,
+			query,
+			policyID,
+			i,
+			steps[i].Delay.Seconds(),
+			pq.Array(toInt64s(steps[i].NotifierIDs)),
+			pq.Array(toInt64s(steps[i].ScheduleIDs)),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create escalation step: %w", err)
+		}
+	}
+	return nil
+}
+
+// getSteps retrieves the steps of a policy in order
+func (r *PolicyRepository) getSteps(ctx context.Context, policyID int) ([]model.Step, error) {
+	query := `
+		SELECT position, delay, notifier_ids, schedule_ids
+		FROM escalation_step
+		WHERE policy_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation steps: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []model.Step
+	for rows.Next() {
+		var step model.Step
+		var delaySeconds float64
+		var notifierIDs, scheduleIDs pq.Int64Array
+		if err := rows.Scan(&step.Position, &delaySeconds, &notifierIDs, &scheduleIDs); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation step: %w", err)
+		}
+		step.Delay = time.Duration(delaySeconds * float64(time.Second))
+		step.NotifierIDs = toInts(notifierIDs)
+		step.ScheduleIDs = toInts(scheduleIDs)
+		steps = append(steps, step)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation steps: %w", err)
+	}
+
+	return steps, nil
+}
+
+// Get retrieves a policy and its steps by ID
+func (r *PolicyRepository) Get(ctx context.Context, id int) (*model.Policy, error) {
+	query := `SELECT id, user_id, name FROM escalation_policy WHERE id = $1`
+
+	policy := &model.Policy{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&policy.ID, &policy.UserID, &policy.Name)
+	if err == sql.ErrNoRows {
+		return nil, ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+
+	if policy.Steps, err = r.getSteps(ctx, policy.ID); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetByUserID retrieves all policies of a user along with their steps
+func (r *PolicyRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error) {
+	query := `SELECT id, user_id, name FROM escalation_policy WHERE user_id = $1 ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*model.Policy
+	for rows.Next() {
+		policy := &model.Policy{}
+		if err := rows.Scan(&policy.ID, &policy.UserID, &policy.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation policies: %w", err)
+	}
+
+	for _, policy := range policies {
+		if policy.Steps, err = r.getSteps(ctx, policy.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return policies, nil
+}
+
+// Update renames a policy and replaces its steps
+func (r *PolicyRepository) Update(ctx context.Context, policy *model.Policy) (*model.Policy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE escalation_policy SET name = $1 WHERE id = $2`, policy.Name, policy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil, ErrPolicyNotFound
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM escalation_step WHERE policy_id = $1`, policy.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete escalation steps: %w", err)
+	}
+
+	updated := *policy
+	if err := r.insertSteps(ctx, updated.ID, updated.Steps); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete removes a policy; its steps are removed and its targets detached by the foreign keys
+func (r *PolicyRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM escalation_policy WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// GetTargets retrieves the user's targets along with the policy attached to each
+func (r *PolicyRepository) GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error) {
+	query := `SELECT id, url, escalation_policy_id FROM target WHERE user_id = $1 ORDER BY url, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []*model.PolicyTarget
+	for rows.Next() {
+		target := &model.PolicyTarget{}
+		if err := rows.Scan(&target.ID, &target.URL, &target.PolicyID); err != nil {
+			return nil, fmt.Errorf("failed to scan target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// SetTargets attaches the policy to exactly the given targets of the user.
+// A target can only follow one policy, so attaching moves it off its previous one.
+func (r *PolicyRepository) SetTargets(ctx context.Context, policyID int, userID int, targetIDs []int) error {
+	query := `
+		UPDATE target
+		SET escalation_policy_id = NULL
+		WHERE escalation_policy_id = $1 AND NOT (id = ANY($2))
+	`
+	if _, err := r.db.ExecContext(ctx, query, policyID, pq.Array(toInt64s(targetIDs))); err != nil {
+		return fmt.Errorf("failed to detach escalation policy: %w", err)
+	}
+
+	query = `
+		UPDATE target
+		SET escalation_policy_id = $1
+		WHERE user_id = $2 AND id = ANY($3)
+	`
+	if _, err := r.db.ExecContext(ctx, query, policyID, userID, pq.Array(toInt64s(targetIDs))); err != nil {
+		return fmt.Errorf("failed to attach escalation policy: %w", err)
+	}
+	return nil
+}
+
+// GetOpenIncidents retrieves the open incidents of enabled targets with an escalation policy.
+// Acknowledged and resolved incidents are left out, which is what stops escalation.
+func (r *PolicyRepository) GetOpenIncidents(ctx context.Context) ([]*model.Escalation, error) {
+	query := `
+		SELECT i.id, t.escalation_policy_id, t.id, t.url, t.paused_until, i.cause, i.started_at, i.escalation_step
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE i.status = $1 AND t.escalation_policy_id IS NOT NULL AND t.enabled
+		ORDER BY i.started_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentModel.StatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []*model.Escalation
+	for rows.Next() {
+		escalation := &model.Escalation{}
+		err := rows.Scan(
+			&escalation.IncidentID,
+			&escalation.PolicyID,
+			&escalation.TargetID,
+			&escalation.TargetURL,
+			&escalation.PausedUntil,
+			&escalation.Cause,
+			&escalation.StartedAt,
+			&escalation.Fired,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan open incident: %w", err)
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating open incidents: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// AdvanceIncident records how many steps have run for an incident that is still open
+func (r *PolicyRepository) AdvanceIncident(ctx context.Context, incidentID int, fired int) error {
+	query := `UPDATE incident SET escalation_step = $1 WHERE id = $2 AND status = $3`
+
+	if _, err := r.db.ExecContext(ctx, query, fired, incidentID, incidentModel.StatusOpen); err != nil {
+		return fmt.Errorf("failed to advance incident: %w", err)
+	}
+	return nil
+}
+
+func toInt64s(ids []int) []int64 {
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+	return values
+}
+
+func toInts(values []int64) []int {
+	ids := make([]int, len(values))
+	for i, value := range values {
+		ids[i] = int(value)
+	}
+	return ids
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	incidentRepo "github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestTarget(t *testing.T, tx *sql.Tx, userID int, url string) int {
+	target, err := monitorRepo.NewTargetRepository(tx).Create(context.Background(), monitorModel.UserTarget{
+		UserID: userID,
+		Target: &core.Target{
+			URL:             url,
+			Status:          "up",
+			Enabled:         true,
+			Interval:        30 * time.Second,
+			StatusChangedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+	return target.ID
+}
+
+func createTestPolicy(t *testing.T, repo *PolicyRepository, userID int) *model.Policy {
+	policy, err := repo.Create(context.Background(), &model.Policy{
+		UserID: userID,
+		Name:   "ops",
+		Steps: []model.Step{
+			{Delay: 0, NotifierIDs: []int{1}},
+			{Delay: 10 * time.Minute, NotifierIDs: []int{2, 3}, ScheduleIDs: []int{4}},
+		},
+	})
+	assert.NoError(t, err)
+	return policy
+}
+
+func TestPolicyRepository_CRUD(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewPolicyRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	policy := createTestPolicy(t, repo, user.ID)
+	assert.NotZero(t, policy.ID)
+
+	fetched, err := repo.Get(context.Background(), policy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", fetched.Name)
+	assert.Len(t, fetched.Steps, 2)
+	assert.Equal(t, 10*time.Minute, fetched.Steps[1].Delay)
+	assert.Equal(t, []int{2, 3}, fetched.Steps[1].NotifierIDs)
+	assert.Equal(t, []int{4}, fetched.Steps[1].ScheduleIDs)
+
+	fetched.Name = "ops lead"
+	fetched.Steps = fetched.Steps[:1]
+	_, err = repo.Update(context.Background(), fetched)
+	assert.NoError(t, err)
+
+	policies, err := repo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, "ops lead", policies[0].Name)
+	assert.Len(t, policies[0].Steps, 1)
+
+	assert.NoError(t, repo.Delete(context.Background(), policy.ID))
+	_, err = repo.Get(context.Background(), policy.ID)
+	assert.ErrorIs(t, err, ErrPolicyNotFound)
+	assert.ErrorIs(t, repo.Delete(context.Background(), policy.ID), ErrPolicyNotFound)
+}
+
+func TestPolicyRepository_Escalation(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewPolicyRepository(tx)
+	incidents := incidentRepo.NewIncidentRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	covered := createTestTarget(t, tx, user.ID, "https://a.example.org")
+	uncovered := createTestTarget(t, tx, user.ID, "https://b.example.org")
+	policy := createTestPolicy(t, repo, user.ID)
+
+	assert.NoError(t, repo.SetTargets(context.Background(), policy.ID, user.ID, []int{covered}))
+
+	targets, err := repo.GetTargets(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, targets, 2)
+	assert.Equal(t, policy.ID, *targets[0].PolicyID)
+	assert.Nil(t, targets[1].PolicyID)
+
+	// Disabled targets are not escalated
+	disabled := createTestTarget(t, tx, user.ID, "https://c.example.org")
+	_, err = tx.Exec(`UPDATE target SET enabled = false WHERE id = $1`, disabled)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.SetTargets(context.Background(), policy.ID, user.ID, []int{covered, disabled}))
+
+	opened, _, err := incidents.Open(context.Background(), covered, "down", time.Now())
+	assert.NoError(t, err)
+	_, _, err = incidents.Open(context.Background(), uncovered, "down", time.Now())
+	assert.NoError(t, err)
+	_, _, err = incidents.Open(context.Background(), disabled, "down", time.Now())
+	assert.NoError(t, err)
+
+	escalations, err := repo.GetOpenIncidents(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, escalations, 1)
+	assert.Equal(t, opened.ID, escalations[0].IncidentID)
+	assert.Equal(t, policy.ID, escalations[0].PolicyID)
+	assert.Equal(t, covered, escalations[0].TargetID)
+	assert.Nil(t, escalations[0].PausedUntil)
+
+	assert.NoError(t, repo.AdvanceIncident(context.Background(), opened.ID, 1))
+	escalations, err = repo.GetOpenIncidents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, escalations[0].Fired)
+
+	// Acknowledged incidents stop escalating
+	assert.NoError(t, incidents.Acknowledge(context.Background(), opened.ID, user.ID, time.Now()))
+	escalations, err = repo.GetOpenIncidents(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, escalations)
+
+	assert.NoError(t, repo.SetTargets(context.Background(), policy.ID, user.ID, nil))
+	targets, err = repo.GetTargets(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, targets[0].PolicyID)
+}
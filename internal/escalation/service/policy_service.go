@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/repository"
+	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	oncallModel "github.com/shuvo-paul/uptimebot/internal/oncall/model"
+)
+
+// Common errors returned by the escalation policy service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a policy they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to escalation policy")
+	// ErrPolicyNotFound is returned when the requested policy does not exist.
+	ErrPolicyNotFound = errors.New("escalation policy not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
+// NotifierService is the part of the notifier service that escalation relies on
+type NotifierService interface {
+	GetByUserID(ctx context.Context, userID int) ([]*notifModel.Notifier, error)
+	NotifyByIDs(ctx context.Context, ids []int, state notifCore.State) error
+}
+
+// ScheduleService is the part of the on-call schedule service that escalation relies on
+type ScheduleService interface {
+	GetByUserID(ctx context.Context, userID int) ([]*oncallModel.Schedule, error)
+	ResolveNotifierIDs(ctx context.Context, scheduleIDs []int, at time.Time) ([]int, error)
+}
+
+// MaintenanceService is the part of the maintenance window service that escalation relies on
+type MaintenanceService interface {
+	InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error)
+}
+
+type PolicyServiceInterface interface {
+	Create(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) error
+	Get(ctx context.Context, id int, userID int) (*model.Policy, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error)
+	Update(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) (*model.Policy, error)
+	Delete(ctx context.Context, id int, userID int) error
+	GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error)
+	GetNotifiers(ctx context.Context, userID int) ([]*notifModel.Notifier, error)
+	GetSchedules(ctx context.Context, userID int) ([]*oncallModel.Schedule, error)
+}
+
+var _ PolicyServiceInterface = (*PolicyService)(nil)
+
+type PolicyService struct {
+	repo               repository.PolicyRepositoryInterface
+	notifierService    NotifierService
+	scheduleService    ScheduleService
+	maintenanceService MaintenanceService
+	baseURL            string // prefixes the dashboard links added to notifications
+	now                func() time.Time
+}
+
+func NewPolicyService(
+	repo repository.PolicyRepositoryInterface,
+	notifierService NotifierService,
+	scheduleService ScheduleService,
+	maintenanceService MaintenanceService,
+	baseURL string,
+) *PolicyService {
+	return &PolicyService{
+		repo:               repo,
+		notifierService:    notifierService,
+		scheduleService:    scheduleService,
+		maintenanceService: maintenanceService,
+		baseURL:            baseURL,
+		now:                time.Now,
+	}
+}
+
+// validate checks the policy and that every step only notifies the user's own
+// notifiers and schedules
+func (s *PolicyService) validate(ctx context.Context, policy *model.Policy, userID int) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	notifiers, err := s.notifierService.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	owned := make(map[int]bool, len(notifiers))
+	for _, notifier := range notifiers {
+		owned[notifier.ID] = true
+	}
+
+	schedules, err := s.scheduleService.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get schedules: %w", err)
+	}
+	ownedSchedules := make(map[int]bool, len(schedules))
+	for _, schedule := range schedules {
+		ownedSchedules[schedule.ID] = true
+	}
+
+	for i, step := range policy.Steps {
+		for _, id := range step.NotifierIDs {
+			if !owned[id] {
+				return fmt.Errorf("%w: step %d uses unknown notifier %d", ErrInvalidInput, i+1, id)
+			}
+		}
+		for _, id := range step.ScheduleIDs {
+			if !ownedSchedules[id] {
+				return fmt.Errorf("%w: step %d uses unknown schedule %d", ErrInvalidInput, i+1, id)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PolicyService) Create(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	policy.UserID = userID
+	if err := s.validate(ctx, policy, userID); err != nil {
+		return err
+	}
+
+	newPolicy, err := s.repo.Create(ctx, policy)
+	if err != nil {
+		return fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+	policy.ID = newPolicy.ID
+
+	if err := s.repo.SetTargets(ctx, policy.ID, userID, targetIDs); err != nil {
+		return fmt.Errorf("failed to attach escalation policy: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a policy after verifying the user owns it
+func (s *PolicyService) Get(ctx context.Context, id int, userID int) (*model.Policy, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	policy, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPolicyNotFound) {
+			return nil, fmt.Errorf("%w: policy with id %d not found", ErrPolicyNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+
+	if policy.UserID != userID {
+		return nil, fmt.Errorf("%w: user %d does not own policy %d", ErrUnauthorized, userID, id)
+	}
+	return policy, nil
+}
+
+func (s *PolicyService) GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	policies, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Update replaces the name, steps and targets of a policy the user owns
+func (s *PolicyService) Update(ctx context.Context, policy *model.Policy, userID int, targetIDs []int) (*model.Policy, error) {
+	existing, err := s.Get(ctx, policy.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.UserID = existing.UserID
+	if err := s.validate(ctx, policy, userID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	if err := s.repo.SetTargets(ctx, policy.ID, userID, targetIDs); err != nil {
+		return nil, fmt.Errorf("failed to attach escalation policy: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *PolicyService) Delete(ctx context.Context, id int, userID int) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+	return nil
+}
+
+// GetTargets retrieves the user's targets along with the policy attached to each
+func (s *PolicyService) GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	targets, err := s.repo.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get targets: %w", err)
+	}
+	return targets, nil
+}
+
+// GetNotifiers retrieves the notifiers the user can use in escalation steps
+func (s *PolicyService) GetNotifiers(ctx context.Context, userID int) ([]*notifModel.Notifier, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	notifiers, err := s.notifierService.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	return notifiers, nil
+}
+
+// GetSchedules retrieves the on-call schedules the user can use in escalation steps
+func (s *PolicyService) GetSchedules(ctx context.Context, userID int) ([]*oncallModel.Schedule, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	schedules, err := s.scheduleService.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// recipients returns the notifiers of a step along with the personal
+// notifiers of whoever is on call on its schedules, without duplicates
+func (s *PolicyService) recipients(ctx context.Context, step model.Step, at time.Time) ([]int, error) {
+	ids := append([]int(nil), step.NotifierIDs...)
+
+	var err error
+	if len(step.ScheduleIDs) > 0 {
+		var onCall []int
+		onCall, err = s.scheduleService.ResolveNotifierIDs(ctx, step.ScheduleIDs, at)
+		ids = append(ids, onCall...)
+	}
+
+	seen := make(map[int]bool, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, err
+}
+
+// inMaintenance reports whether the target is in one of its maintenance
+// windows. A failed lookup counts as no window, so that pages are not lost.
+func (s *PolicyService) inMaintenance(ctx context.Context, targetID int, at time.Time) bool {
+	if s.maintenanceService == nil {
+		return false
+	}
+
+	inMaintenance, err := s.maintenanceService.InMaintenance(ctx, targetID, at)
+	if err != nil {
+		slog.Error("Failed to check maintenance windows", "target", targetID, "error", err)
+		return false
+	}
+	return inMaintenance
+}
+
+// Evaluate runs the due steps of every open incident covered by a policy.
+// An incident stops escalating once it is acknowledged or resolved, and is
+// held back while its target is paused or in a maintenance window.
+func (s *PolicyService) Evaluate(ctx context.Context) error {
+	escalations, err := s.repo.GetOpenIncidents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get open incidents: %w", err)
+	}
+
+	now := s.now()
+	policies := make(map[int]*model.Policy)
+	var errs []error
+	for _, escalation := range escalations {
+		if escalation.Paused(now) || s.inMaintenance(ctx, escalation.TargetID, now) {
+			continue
+		}
+
+		policy, ok := policies[escalation.PolicyID]
+		if !ok {
+			policy, err = s.repo.Get(ctx, escalation.PolicyID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get escalation policy %d: %w", escalation.PolicyID, err))
+				continue
+			}
+			policies[escalation.PolicyID] = policy
+		}
+
+		elapsed := now.Sub(escalation.StartedAt)
+		due := policy.DueSteps(escalation.Fired, elapsed)
+		if len(due) == 0 {
+			continue
+		}
+
+		for _, step := range due {
+			state := notifCore.State{
+				Name:       escalation.TargetURL,
+				URL:        escalation.TargetURL,
+				Status:     escalation.Cause,
+				UpdatedAt:  now,
+				IncidentID: escalation.IncidentID,
+				Duration:   elapsed,
+				Link:       s.baseURL + escalation.Path(),
+				Message: fmt.Sprintf("Target %s has been %s for %s (escalation step %d of %d)",
+					escalation.TargetURL, escalation.Cause, elapsed.Truncate(time.Second), step.Position+1, len(policy.Steps)),
+			}
+			ids, err := s.recipients(ctx, step, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to resolve on-call for incident %d: %w", escalation.IncidentID, err))
+			}
+			if err := s.notifierService.NotifyByIDs(ctx, ids, state); err != nil {
+				errs = append(errs, fmt.Errorf("failed to escalate incident %d: %w", escalation.IncidentID, err))
+			}
+		}
+
+		// Steps are recorded as run even when a delivery failed, so a broken
+		// notifier doesn't page everyone else again on the next evaluation
+		if err := s.repo.AdvanceIncident(ctx, escalation.IncidentID, escalation.Fired+len(due)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start evaluates open incidents every interval in the background
+func (s *PolicyService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.Evaluate(context.Background()); err != nil {
+				slog.Error("Failed to evaluate escalation policies", "error", err)
+			}
+		}
+	}()
+}
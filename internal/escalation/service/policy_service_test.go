@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/escalation/model"
+	"github.com/shuvo-paul/uptimebot/internal/escalation/repository"
+	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	oncallModel "github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPolicyRepository struct {
+	createFunc           func(policy *model.Policy) (*model.Policy, error)
+	getFunc              func(id int) (*model.Policy, error)
+	getByUserIDFunc      func(userID int) ([]*model.Policy, error)
+	updateFunc           func(policy *model.Policy) (*model.Policy, error)
+	deleteFunc           func(id int) error
+	getTargetsFunc       func(userID int) ([]*model.PolicyTarget, error)
+	setTargetsFunc       func(policyID int, userID int, targetIDs []int) error
+	getOpenIncidentsFunc func() ([]*model.Escalation, error)
+	advanceIncidentFunc  func(incidentID int, fired int) error
+}
+
+func (m *mockPolicyRepository) Create(ctx context.Context, policy *model.Policy) (*model.Policy, error) {
+	return m.createFunc(policy)
+}
+
+func (m *mockPolicyRepository) Get(ctx context.Context, id int) (*model.Policy, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockPolicyRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Policy, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockPolicyRepository) Update(ctx context.Context, policy *model.Policy) (*model.Policy, error) {
+	return m.updateFunc(policy)
+}
+
+func (m *mockPolicyRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockPolicyRepository) GetTargets(ctx context.Context, userID int) ([]*model.PolicyTarget, error) {
+	return m.getTargetsFunc(userID)
+}
+
+func (m *mockPolicyRepository) SetTargets(ctx context.Context, policyID int, userID int, targetIDs []int) error {
+	return m.setTargetsFunc(policyID, userID, targetIDs)
+}
+
+func (m *mockPolicyRepository) GetOpenIncidents(ctx context.Context) ([]*model.Escalation, error) {
+	return m.getOpenIncidentsFunc()
+}
+
+func (m *mockPolicyRepository) AdvanceIncident(ctx context.Context, incidentID int, fired int) error {
+	return m.advanceIncidentFunc(incidentID, fired)
+}
+
+type mockNotifierService struct {
+	getByUserIDFunc func(userID int) ([]*notifModel.Notifier, error)
+	notifyByIDsFunc func(ids []int, state notifCore.State) error
+}
+
+func (m *mockNotifierService) GetByUserID(ctx context.Context, userID int) ([]*notifModel.Notifier, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockNotifierService) NotifyByIDs(ctx context.Context, ids []int, state notifCore.State) error {
+	return m.notifyByIDsFunc(ids, state)
+}
+
+type mockScheduleService struct {
+	resolveNotifierIDsFunc func(scheduleIDs []int, at time.Time) ([]int, error)
+}
+
+func (m *mockScheduleService) GetByUserID(ctx context.Context, userID int) ([]*oncallModel.Schedule, error) {
+	if userID != 1 {
+		return nil, nil
+	}
+	return []*oncallModel.Schedule{{ID: 1, UserID: 1}}, nil
+}
+
+func (m *mockScheduleService) ResolveNotifierIDs(ctx context.Context, scheduleIDs []int, at time.Time) ([]int, error) {
+	return m.resolveNotifierIDsFunc(scheduleIDs, at)
+}
+
+type mockMaintenanceService struct {
+	inMaintenanceFunc func(targetID int, at time.Time) (bool, error)
+}
+
+func (m *mockMaintenanceService) InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error) {
+	return m.inMaintenanceFunc(targetID, at)
+}
+
+func ownedNotifiers(userID int) ([]*notifModel.Notifier, error) {
+	if userID != 1 {
+		return nil, nil
+	}
+	return []*notifModel.Notifier{{ID: 1, UserID: 1}, {ID: 2, UserID: 1}}, nil
+}
+
+func testPolicy() *model.Policy {
+	return &model.Policy{
+		ID:     1,
+		UserID: 1,
+		Name:   "ops",
+		Steps: []model.Step{
+			{Position: 0, Delay: 0, NotifierIDs: []int{1}},
+			{Position: 1, Delay: 10 * time.Minute, NotifierIDs: []int{2}},
+		},
+	}
+}
+
+func TestPolicyService_Create(t *testing.T) {
+	var attached []int
+	mockRepo := &mockPolicyRepository{
+		createFunc: func(policy *model.Policy) (*model.Policy, error) {
+			created := *policy
+			created.ID = 5
+			return &created, nil
+		},
+		setTargetsFunc: func(policyID int, userID int, targetIDs []int) error {
+			attached = targetIDs
+			return nil
+		},
+	}
+	service := NewPolicyService(mockRepo, &mockNotifierService{getByUserIDFunc: ownedNotifiers}, &mockScheduleService{}, nil, "")
+
+	t.Run("success", func(t *testing.T) {
+		policy := testPolicy()
+		policy.ID = 0
+		assert.NoError(t, service.Create(context.Background(), policy, 1, []int{3, 4}))
+		assert.Equal(t, 5, policy.ID)
+		assert.Equal(t, []int{3, 4}, attached)
+	})
+
+	t.Run("on-call schedule", func(t *testing.T) {
+		policy := testPolicy()
+		policy.Steps[1].ScheduleIDs = []int{1}
+		assert.NoError(t, service.Create(context.Background(), policy, 1, nil))
+
+		policy.Steps[1].ScheduleIDs = []int{2}
+		assert.ErrorIs(t, service.Create(context.Background(), policy, 1, nil), ErrInvalidInput)
+	})
+
+	t.Run("someone else's notifier", func(t *testing.T) {
+		policy := testPolicy()
+		policy.Steps[1].NotifierIDs = []int{9}
+		assert.ErrorIs(t, service.Create(context.Background(), policy, 1, nil), ErrInvalidInput)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		assert.ErrorIs(t, service.Create(context.Background(), &model.Policy{Name: "empty"}, 1, nil), ErrInvalidInput)
+	})
+}
+
+func TestPolicyService_Get(t *testing.T) {
+	mockRepo := &mockPolicyRepository{
+		getFunc: func(id int) (*model.Policy, error) {
+			if id != 1 {
+				return nil, repository.ErrPolicyNotFound
+			}
+			return testPolicy(), nil
+		},
+	}
+	service := NewPolicyService(mockRepo, &mockNotifierService{}, &mockScheduleService{}, nil, "")
+
+	policy, err := service.Get(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", policy.Name)
+
+	_, err = service.Get(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.Get(context.Background(), 2, 1)
+	assert.ErrorIs(t, err, ErrPolicyNotFound)
+}
+
+func TestPolicyService_UpdateDelete(t *testing.T) {
+	deleted := false
+	mockRepo := &mockPolicyRepository{
+		getFunc: func(id int) (*model.Policy, error) {
+			return testPolicy(), nil
+		},
+		updateFunc: func(policy *model.Policy) (*model.Policy, error) {
+			return policy, nil
+		},
+		setTargetsFunc: func(policyID int, userID int, targetIDs []int) error {
+			return nil
+		},
+		deleteFunc: func(id int) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := NewPolicyService(mockRepo, &mockNotifierService{getByUserIDFunc: ownedNotifiers}, &mockScheduleService{}, nil, "")
+
+	policy := testPolicy()
+	policy.Name = "renamed"
+	updated, err := service.Update(context.Background(), policy, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Name)
+
+	_, err = service.Update(context.Background(), testPolicy(), 2, nil)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	assert.ErrorIs(t, service.Delete(context.Background(), 1, 2), ErrUnauthorized)
+	assert.False(t, deleted)
+	assert.NoError(t, service.Delete(context.Background(), 1, 1))
+	assert.True(t, deleted)
+}
+
+func TestPolicyService_Evaluate(t *testing.T) {
+	now := time.Date(2025, 4, 22, 12, 0, 0, 0, time.UTC)
+	fired := map[int]int{}
+	var notified [][]int
+	mockRepo := &mockPolicyRepository{
+		getFunc: func(id int) (*model.Policy, error) {
+			return testPolicy(), nil
+		},
+		getOpenIncidentsFunc: func() ([]*model.Escalation, error) {
+			return []*model.Escalation{
+				// Just opened: the immediate step is due
+				{IncidentID: 1, PolicyID: 1, TargetID: 1, TargetURL: "https://a.example.org", Cause: "down", StartedAt: now.Add(-time.Second)},
+				// First step ran, second one not due yet
+				{IncidentID: 2, PolicyID: 1, TargetID: 2, TargetURL: "https://b.example.org", Cause: "down", StartedAt: now.Add(-5 * time.Minute), Fired: 1},
+				// First step ran, second one is due
+				{IncidentID: 3, PolicyID: 1, TargetID: 3, TargetURL: "https://c.example.org", Cause: "error", StartedAt: now.Add(-11 * time.Minute), Fired: 1},
+			}, nil
+		},
+		advanceIncidentFunc: func(incidentID int, count int) error {
+			fired[incidentID] = count
+			return nil
+		},
+	}
+	var links []string
+	mockNotifier := &mockNotifierService{
+		notifyByIDsFunc: func(ids []int, state notifCore.State) error {
+			notified = append(notified, ids)
+			links = append(links, state.Link)
+			return nil
+		},
+	}
+	mockSchedule := &mockScheduleService{
+		resolveNotifierIDsFunc: func(scheduleIDs []int, at time.Time) ([]int, error) {
+			return []int{2, 7}, nil
+		},
+	}
+	mockMaintenance := &mockMaintenanceService{
+		inMaintenanceFunc: func(targetID int, at time.Time) (bool, error) {
+			return targetID == 5, nil
+		},
+	}
+	service := NewPolicyService(mockRepo, mockNotifier, mockSchedule, mockMaintenance, "https://uptimebot.example")
+	service.now = func() time.Time { return now }
+
+	assert.NoError(t, service.Evaluate(context.Background()))
+	assert.Equal(t, [][]int{{1}, {2}}, notified)
+	assert.Equal(t, []string{"https://uptimebot.example/app/incidents/1", "https://uptimebot.example/app/incidents/3"}, links)
+
+	t.Run("whoever is on call", func(t *testing.T) {
+		notified = nil
+		policy := testPolicy()
+		policy.Steps[1].ScheduleIDs = []int{1}
+		mockRepo.getFunc = func(id int) (*model.Policy, error) {
+			return policy, nil
+		}
+
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Equal(t, [][]int{{1}, {2, 7}}, notified)
+	})
+	assert.Equal(t, map[int]int{1: 1, 3: 2}, fired)
+
+	t.Run("failed delivery still advances", func(t *testing.T) {
+		fired = map[int]int{}
+		mockNotifier.notifyByIDsFunc = func(ids []int, state notifCore.State) error {
+			return fmt.Errorf("slack is down")
+		}
+
+		assert.ErrorContains(t, service.Evaluate(context.Background()), "slack is down")
+		assert.Equal(t, map[int]int{1: 1, 3: 2}, fired)
+	})
+
+	t.Run("held back while paused or in maintenance", func(t *testing.T) {
+		fired = map[int]int{}
+		notified = nil
+		mockNotifier.notifyByIDsFunc = func(ids []int, state notifCore.State) error {
+			notified = append(notified, ids)
+			return nil
+		}
+		later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+		mockRepo.getOpenIncidentsFunc = func() ([]*model.Escalation, error) {
+			return []*model.Escalation{
+				{IncidentID: 4, PolicyID: 1, TargetID: 4, PausedUntil: &later, Cause: "down", StartedAt: now.Add(-time.Second)},
+				{IncidentID: 5, PolicyID: 1, TargetID: 5, Cause: "down", StartedAt: now.Add(-time.Second)},
+				{IncidentID: 6, PolicyID: 1, TargetID: 6, PausedUntil: &earlier, Cause: "down", StartedAt: now.Add(-time.Second)},
+			}, nil
+		}
+
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Equal(t, [][]int{{1}}, notified)
+		assert.Equal(t, map[int]int{6: 1}, fired)
+	})
+}
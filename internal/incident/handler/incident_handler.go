@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	"github.com/shuvo-paul/uptimebot/internal/incident/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const (
+	dateFormat    = "2006-01-02"
+	displayFormat = "2006-01-02 15:04 MST"
+)
+
+type IncidentHandler struct {
+	incidentService service.IncidentServiceInterface
+	flash           flash.FlashStoreInterface
+	Template        struct {
+		List *renderer.Template
+		Show *renderer.Template
+	}
+}
+
+func NewIncidentHandler(incidentService service.IncidentServiceInterface, flash flash.FlashStoreInterface) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incidentService,
+		flash:           flash,
+	}
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrIncidentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// eventRow is a timeline entry formatted for display
+type eventRow struct {
+	Kind    model.EventKind
+	Message string
+	Author  string
+	At      string
+}
+
+// parseFilter reads the incident filter from the query string. The end date
+// is inclusive, so the range extends to the start of the following day.
+func parseFilter(query url.Values) (model.Filter, error) {
+	filter := model.Filter{
+		Status: model.Status(query.Get("status")),
+		Target: query.Get("target"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		date, err := time.Parse(dateFormat, from)
+		if err != nil {
+			return filter, fmt.Errorf("invalid start date")
+		}
+		filter.From = date
+	}
+	if to := query.Get("to"); to != "" {
+		date, err := time.Parse(dateFormat, to)
+		if err != nil {
+			return filter, fmt.Errorf("invalid end date")
+		}
+		filter.To = date.AddDate(0, 0, 1)
+	}
+
+	return filter, filter.Validate()
+}
+
+// List shows the incidents of the user's targets, narrowed down by the filters
+func (ih *IncidentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	data := map[string]any{
+		"title":    "incidents",
+		"statuses": model.Statuses,
+		"query": map[string]string{
+			"status": query.Get("status"),
+			"target": query.Get("target"),
+			"from":   query.Get("from"),
+			"to":     query.Get("to"),
+		},
+	}
+
+	filter, err := parseFilter(query)
+	if err != nil {
+		data["filterError"] = err.Error()
+		ih.Template.List.Render(w, r, data)
+		return
+	}
+
+	incidents, err := ih.incidentService.Search(r.Context(), user.ID, filter)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	durations := make(map[int]string, len(incidents))
+	now := time.Now()
+	for _, incident := range incidents {
+		durations[incident.ID] = incident.Duration(now).Truncate(time.Second).String()
+	}
+
+	data["incidents"] = incidents
+	data["durations"] = durations
+
+	ih.Template.List.Render(w, r, data)
+}
+
+// Show displays an incident together with its timeline
+func (ih *IncidentHandler) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	incident, err := ih.incidentService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	events := make([]eventRow, 0, len(incident.Events))
+	for _, event := range incident.Events {
+		events = append(events, eventRow{
+			Kind:    event.Kind,
+			Message: event.Message,
+			Author:  event.UserEmail,
+			At:      event.CreatedAt.Format(displayFormat),
+		})
+	}
+
+	data := map[string]any{
+		"title":     "incident",
+		"incident":  incident,
+		"startedAt": incident.StartedAt.Format(displayFormat),
+		"duration":  incident.Duration(time.Now()).Truncate(time.Second).String(),
+		"events":    events,
+	}
+
+	ih.Template.Show.Render(w, r, data)
+}
+
+// Acknowledge marks an incident as being handled, which stops its escalation
+func (ih *IncidentHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := ih.incidentService.Acknowledge(r.Context(), id, user.ID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnauthorized):
+			ih.flash.SetErrors(r.Context(), []string{"You are not allowed to acknowledge this incident"})
+			http.Redirect(w, r, "/app/incidents", http.StatusSeeOther)
+			return
+		case errors.Is(err, service.ErrIncidentNotFound):
+			ih.flash.SetErrors(r.Context(), []string{"Incident is no longer open"})
+		default:
+			ih.flash.SetErrors(r.Context(), []string{"Failed to acknowledge incident: " + err.Error()})
+		}
+		http.Redirect(w, r, fmt.Sprintf("/app/incidents/%d", id), http.StatusSeeOther)
+		return
+	}
+
+	ih.flash.SetSuccesses(r.Context(), []string{"Incident acknowledged, escalation stopped"})
+	http.Redirect(w, r, fmt.Sprintf("/app/incidents/%d", id), http.StatusSeeOther)
+}
+
+// Comment adds a note to the timeline of an incident
+func (ih *IncidentHandler) Comment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	if err := ih.incidentService.Comment(r.Context(), id, user.ID, r.FormValue("message")); err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			ih.flash.SetErrors(r.Context(), []string{"Comment cannot be empty or longer than 2000 characters"})
+			http.Redirect(w, r, fmt.Sprintf("/app/incidents/%d", id), http.StatusSeeOther)
+			return
+		}
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	ih.flash.SetSuccesses(r.Context(), []string{"Comment added"})
+	http.Redirect(w, r, fmt.Sprintf("/app/incidents/%d", id), http.StatusSeeOther)
+}
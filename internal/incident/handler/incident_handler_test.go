@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	"github.com/shuvo-paul/uptimebot/internal/incident/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockIncidentService struct {
+	handleStatusChangeFunc    func(targetID int, status string) (*model.Incident, error)
+	acknowledgeFunc           func(id int, userID int) error
+	acknowledgeFromSlackFunc  func(id int, slackUser string) error
+	commentFunc               func(id int, userID int, message string) error
+	getFunc                   func(id int, userID int) (*model.Incident, error)
+	searchFunc                func(userID int, filter model.Filter) ([]*model.Incident, error)
+	getUnresolvedByUserIDFunc func(userID int) ([]*model.Incident, error)
+}
+
+func (m *MockIncidentService) HandleStatusChange(ctx context.Context, targetID int, status string) (*model.Incident, error) {
+	return m.handleStatusChangeFunc(targetID, status)
+}
+
+func (m *MockIncidentService) RecordCheck(ctx context.Context, targetID int, result monitor.Result) error {
+	return nil
+}
+
+func (m *MockIncidentService) RecordDependent(ctx context.Context, targetID int, dependentURL string) error {
+	return nil
+}
+
+func (m *MockIncidentService) RecordNotification(ctx context.Context, incidentID int, status string, failed int) error {
+	return nil
+}
+
+func (m *MockIncidentService) Acknowledge(ctx context.Context, id int, userID int) error {
+	return m.acknowledgeFunc(id, userID)
+}
+
+func (m *MockIncidentService) AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error {
+	return m.acknowledgeFromSlackFunc(id, slackUser)
+}
+
+func (m *MockIncidentService) Comment(ctx context.Context, id int, userID int, message string) error {
+	return m.commentFunc(id, userID, message)
+}
+
+func (m *MockIncidentService) Get(ctx context.Context, id int, userID int) (*model.Incident, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockIncidentService) Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error) {
+	return m.searchFunc(userID, filter)
+}
+
+func (m *MockIncidentService) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error) {
+	return m.getUnresolvedByUserIDFunc(userID)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID})
+	return req.WithContext(ctx)
+}
+
+func newTestIncidentHandler(mockService *MockIncidentService) *IncidentHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewIncidentHandler(mockService, mockFlashStore)
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:incidents/list")
+	handler.Template.Show = templateRenderer.GetTemplate("pages:incidents/show")
+	return handler
+}
+
+func TestIncidentHandler_List(t *testing.T) {
+	var got model.Filter
+	mockService := &MockIncidentService{
+		searchFunc: func(userID int, filter model.Filter) ([]*model.Incident, error) {
+			got = filter
+			return []*model.Incident{
+				{ID: 7, TargetID: 1, TargetURL: "https://example.com", Status: model.StatusOpen, Cause: "down", StartedAt: time.Now().Add(-time.Minute)},
+			}, nil
+		},
+	}
+	handler := newTestIncidentHandler(mockService)
+
+	t.Run("filtered", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/incidents?status=open&target=example&from=2025-04-01&to=2025-04-20", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.List(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "https://example.com")
+		assert.Contains(t, w.Body.String(), "/app/incidents/7/acknowledge")
+		assert.Equal(t, model.StatusOpen, got.Status)
+		assert.Equal(t, "example", got.Target)
+		assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), got.From)
+		// The end date is inclusive
+		assert.Equal(t, time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC), got.To)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		got = model.Filter{}
+		req := httptest.NewRequest(http.MethodGet, "/app/incidents?from=2025-04-20&to=2025-04-01", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.List(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "end of the date range must be after its start")
+		assert.Empty(t, got.Target)
+	})
+}
+
+func TestIncidentHandler_Show(t *testing.T) {
+	mockService := &MockIncidentService{
+		getFunc: func(id int, userID int) (*model.Incident, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return &model.Incident{
+				ID:        id,
+				TargetURL: "https://example.com",
+				Status:    model.StatusOpen,
+				Cause:     "down",
+				StartedAt: time.Now().Add(-time.Hour),
+				Events: []*model.Event{
+					{Kind: model.EventOpened, Message: "Incident opened, target is down", CreatedAt: time.Now()},
+					{Kind: model.EventComment, Message: "Looking into it", UserEmail: "alice@example.com", CreatedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+	handler := newTestIncidentHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/incidents/7", nil)
+	req.SetPathValue("id", "7")
+	w := httptest.NewRecorder()
+
+	handler.Show(w, withUser(req, 1))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Incident opened, target is down")
+	assert.Contains(t, w.Body.String(), "by alice@example.com")
+	assert.Contains(t, w.Body.String(), "/app/incidents/7/comment")
+
+	w = httptest.NewRecorder()
+	handler.Show(w, withUser(req, 2))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestIncidentHandler_Comment(t *testing.T) {
+	var comments []string
+	mockService := &MockIncidentService{
+		commentFunc: func(id int, userID int, message string) error {
+			if strings.TrimSpace(message) == "" {
+				return service.ErrInvalidInput
+			}
+			comments = append(comments, message)
+			return nil
+		},
+	}
+	handler := newTestIncidentHandler(mockService)
+
+	for _, message := range []string{"Looking into it", ""} {
+		form := url.Values{"message": {message}}
+		req := httptest.NewRequest(http.MethodPost, "/app/incidents/7/comment", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "7")
+		w := httptest.NewRecorder()
+
+		handler.Comment(w, withUser(req, 1))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/incidents/7", w.Header().Get("Location"))
+	}
+	assert.Equal(t, []string{"Looking into it"}, comments)
+}
+
+func TestIncidentHandler_Acknowledge(t *testing.T) {
+	acknowledged := false
+	mockService := &MockIncidentService{
+		acknowledgeFunc: func(id int, userID int) error {
+			if userID != 1 {
+				return service.ErrUnauthorized
+			}
+			acknowledged = true
+			return nil
+		},
+	}
+	handler := newTestIncidentHandler(mockService)
+
+	t.Run("invalid id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/incidents/abc/acknowledge", nil)
+		req.SetPathValue("id", "abc")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Acknowledge(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/incidents/7/acknowledge", nil)
+		req.SetPathValue("id", "7")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Acknowledge(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.False(t, acknowledged)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/incidents/7/acknowledge", nil)
+		req.SetPathValue("id", "7")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Acknowledge(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/incidents/7", w.Header().Get("Location"))
+		assert.True(t, acknowledged)
+	})
+}
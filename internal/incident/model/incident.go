@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Status is the lifecycle state of an incident
+type Status string
+
+const (
+	StatusOpen         Status = "open"
+	StatusAcknowledged Status = "acknowledged"
+	StatusResolved     Status = "resolved"
+)
+
+// Statuses lists every incident status, in lifecycle order
+var Statuses = []Status{StatusOpen, StatusAcknowledged, StatusResolved}
+
+// Incident tracks a period during which a target was failing
+type Incident struct {
+	ID             int        `db:"id"`
+	TargetID       int        `db:"target_id"`
+	TargetURL      string     `db:"url"`
+	Status         Status     `db:"status"`
+	Cause          string     `db:"cause"`
+	StartedAt      time.Time  `db:"started_at"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at"`
+	AcknowledgedBy *int       `db:"acknowledged_by"`
+	ResolvedAt     *time.Time `db:"resolved_at"`
+	EscalationStep int        `db:"escalation_step"` // number of escalation steps already fired
+	Events         []*Event   // timeline, oldest first; only loaded for a single incident
+}
+
+// Duration returns how long the incident lasted, or has lasted so far
+func (i *Incident) Duration(now time.Time) time.Duration {
+	if i.ResolvedAt != nil {
+		return i.ResolvedAt.Sub(i.StartedAt)
+	}
+	return now.Sub(i.StartedAt)
+}
+
+// Path returns the dashboard page of the incident, relative to the base URL
+func (i *Incident) Path() string {
+	return fmt.Sprintf("/app/incidents/%d", i.ID)
+}
+
+// EventKind is the type of an entry on an incident's timeline
+type EventKind string
+
+const (
+	EventOpened       EventKind = "opened"
+	EventCheck        EventKind = "check"
+	EventDependent    EventKind = "dependent" // a target behind this one became unreachable
+	EventNotification EventKind = "notification"
+	EventAcknowledged EventKind = "acknowledged"
+	EventComment      EventKind = "comment"
+	EventResolved     EventKind = "resolved"
+)
+
+// Event is a single entry on an incident's timeline
+type Event struct {
+	ID         int       `db:"id"`
+	IncidentID int       `db:"incident_id"`
+	Kind       EventKind `db:"kind"`
+	Message    string    `db:"message"`
+	UserID     *int      `db:"user_id"` // nil for events raised by the system
+	UserEmail  string    `db:"email"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Filter narrows down the incidents listed for a user. Zero fields match everything.
+type Filter struct {
+	Status Status
+	Target string    // part of the target URL
+	From   time.Time // earliest start, inclusive
+	To     time.Time // latest start, exclusive
+}
+
+// Validate checks that the status is known and that the date range is not reversed
+func (f Filter) Validate() error {
+	if f.Status != "" && !slices.Contains(Statuses, f.Status) {
+		return fmt.Errorf("unsupported status: %s", f.Status)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.To.After(f.From) {
+		return fmt.Errorf("end of the date range must be after its start")
+	}
+	return nil
+}
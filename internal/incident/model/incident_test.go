@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncident_Duration(t *testing.T) {
+	started := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+	now := started.Add(45 * time.Minute)
+
+	incident := &Incident{StartedAt: started}
+	assert.Equal(t, 45*time.Minute, incident.Duration(now))
+
+	resolved := started.Add(10 * time.Minute)
+	incident.ResolvedAt = &resolved
+	assert.Equal(t, 10*time.Minute, incident.Duration(now))
+}
+
+func TestFilter_Validate(t *testing.T) {
+	day := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{name: "empty", filter: Filter{}},
+		{name: "status and range", filter: Filter{Status: StatusResolved, From: day, To: day.AddDate(0, 0, 1)}},
+		{name: "open ended range", filter: Filter{From: day}},
+		{name: "unknown status", filter: Filter{Status: "closed"}, wantErr: true},
+		{name: "reversed range", filter: Filter{From: day, To: day}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+)
+
+var (
+	ErrIncidentNotFound = errors.New("incident not found")
+)
+
+type IncidentRepositoryInterface interface {
+	Open(ctx context.Context, targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error)
+	Resolve(ctx context.Context, targetID int, resolvedAt time.Time) (*model.Incident, error)
+	Acknowledge(ctx context.Context, id int, userID int, acknowledgedAt time.Time) error
+	Get(ctx context.Context, id int) (*model.Incident, error)
+	GetUnresolvedByTargetID(ctx context.Context, targetID int) (*model.Incident, error)
+	GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error)
+	Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error)
+	GetOwnerID(ctx context.Context, id int) (int, error)
+	AddEvent(ctx context.Context, event *model.Event) error
+	GetEvents(ctx context.Context, incidentID int) ([]*model.Event, error)
+}
+
+var _ IncidentRepositoryInterface = (*IncidentRepository)(nil)
+
+// IncidentRepository handles database operations for incidents
+type IncidentRepository struct {
+	db database.Querier
+}
+
+// NewIncidentRepository creates a new incident repository
+func NewIncidentRepository(db database.Querier) *IncidentRepository {
+	return &IncidentRepository{db: db}
+}
+
+const incidentColumns = `i.id, i.target_id, t.url, i.status, i.cause, i.started_at,
+	i.acknowledged_at, i.acknowledged_by, i.resolved_at, i.escalation_step`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIncident(row rowScanner) (*model.Incident, error) {
+	incident := &model.Incident{}
+	err := row.Scan(
+		&incident.ID,
+		&incident.TargetID,
+		&incident.TargetURL,
+		&incident.Status,
+		&incident.Cause,
+		&incident.StartedAt,
+		&incident.AcknowledgedAt,
+		&incident.AcknowledgedBy,
+		&incident.ResolvedAt,
+		&incident.EscalationStep,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// Open starts an incident for the target. When the target already has an
+// unresolved incident, that incident is returned instead and created is false.
+func (r *IncidentRepository) Open(ctx context.Context, targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error) {
+	query := `
+		INSERT INTO incident (target_id, status, cause, started_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (target_id) WHERE resolved_at IS NULL DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, targetID, model.StatusOpen, cause, startedAt.UTC())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	incident, err := r.GetUnresolvedByTargetID(ctx, targetID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get opened incident: %w", err)
+	}
+	return incident, affected > 0, nil
+}
+
+// Resolve closes the unresolved incident of the target, if any
+func (r *IncidentRepository) Resolve(ctx context.Context, targetID int, resolvedAt time.Time) (*model.Incident, error) {
+	query := `
+		UPDATE incident
+		SET status = $1, resolved_at = $2
+		WHERE target_id = $3 AND resolved_at IS NULL
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.QueryRowContext(ctx, query, model.StatusResolved, resolvedAt.UTC(), targetID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	return r.Get(ctx, id)
+}
+
+// Acknowledge marks an open incident as acknowledged by the user
+func (r *IncidentRepository) Acknowledge(ctx context.Context, id int, userID int, acknowledgedAt time.Time) error {
+	query := `
+		UPDATE incident
+		SET status = $1, acknowledged_at = $2, acknowledged_by = $3
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query, model.StatusAcknowledged, acknowledgedAt.UTC(), userID, id, model.StatusOpen)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrIncidentNotFound
+	}
+	return nil
+}
+
+// Get retrieves an incident by ID
+func (r *IncidentRepository) Get(ctx context.Context, id int) (*model.Incident, error) {
+	query := `SELECT ` + incidentColumns + `
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE i.id = $1`
+
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+	return incident, nil
+}
+
+// GetUnresolvedByTargetID retrieves the open or acknowledged incident of a target
+func (r *IncidentRepository) GetUnresolvedByTargetID(ctx context.Context, targetID int) (*model.Incident, error) {
+	query := `SELECT ` + incidentColumns + `
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE i.target_id = $1 AND i.resolved_at IS NULL`
+
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, targetID))
+	if err == sql.ErrNoRows {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unresolved incident: %w", err)
+	}
+	return incident, nil
+}
+
+// GetUnresolvedByUserID retrieves the open and acknowledged incidents of a user's targets
+func (r *IncidentRepository) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error) {
+	query := `SELECT ` + incidentColumns + `
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE t.user_id = $1 AND i.resolved_at IS NULL
+		ORDER BY i.started_at DESC`
+
+	return r.queryIncidents(ctx, query, userID)
+}
+
+// Search retrieves the incidents of a user's targets matching the filter, newest first
+func (r *IncidentRepository) Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error) {
+	conditions := []string{"t.user_id = $1"}
+	args := []any{userID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("i.status = $%d", len(args)))
+	}
+	if filter.Target != "" {
+		args = append(args, "%"+filter.Target+"%")
+		conditions = append(conditions, fmt.Sprintf("t.url ILIKE $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("i.started_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("i.started_at < $%d", len(args)))
+	}
+
+	query := `SELECT ` + incidentColumns + `
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY i.started_at DESC
+		LIMIT 200`
+
+	return r.queryIncidents(ctx, query, args...)
+}
+
+func (r *IncidentRepository) queryIncidents(ctx context.Context, query string, args ...any) ([]*model.Incident, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []*model.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// GetOwnerID returns the ID of the user owning the incident's target
+func (r *IncidentRepository) GetOwnerID(ctx context.Context, id int) (int, error) {
+	query := `
+		SELECT t.user_id
+		FROM incident i
+		JOIN target t ON t.id = i.target_id
+		WHERE i.id = $1
+	`
+
+	var userID int
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrIncidentNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get incident owner: %w", err)
+	}
+	return userID, nil
+}
+
+// AddEvent appends an entry to the timeline of an incident
+func (r *IncidentRepository) AddEvent(ctx context.Context, event *model.Event) error {
+	query := `
+		INSERT INTO incident_event (incident_id, kind, message, user_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query, event.IncidentID, event.Kind, event.Message, event.UserID, event.CreatedAt.UTC()).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to add incident event: %w", err)
+	}
+	return nil
+}
+
+// GetEvents retrieves the timeline of an incident, oldest first
+func (r *IncidentRepository) GetEvents(ctx context.Context, incidentID int) ([]*model.Event, error) {
+	query := `
+		SELECT e.id, e.incident_id, e.kind, e.message, e.user_id, COALESCE(u.email, ''), e.created_at
+		FROM incident_event e
+		LEFT JOIN usr u ON u.id = e.user_id
+		WHERE e.incident_id = $1
+		ORDER BY e.created_at, e.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.Event
+	for rows.Next() {
+		event := &model.Event{}
+		err := rows.Scan(&event.ID, &event.IncidentID, &event.Kind, &event.Message, &event.UserID, &event.UserEmail, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident events: %w", err)
+	}
+
+	return events, nil
+}
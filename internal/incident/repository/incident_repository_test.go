@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestTarget(t *testing.T, tx *sql.Tx) (*authModel.User, int) {
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+	})
+	assert.NoError(t, err)
+
+	target, err := monitorRepo.NewTargetRepository(tx).Create(context.Background(), monitorModel.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{
+			URL:             "https://example.org",
+			Status:          "up",
+			Enabled:         true,
+			Interval:        30 * time.Second,
+			StatusChangedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+
+	return user, target.ID
+}
+
+func TestIncidentRepository_Lifecycle(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewIncidentRepository(tx)
+	user, targetID := createTestTarget(t, tx)
+	started := time.Now().UTC().Truncate(time.Second)
+
+	opened, created, err := repo.Open(context.Background(), targetID, "down", started)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, model.StatusOpen, opened.Status)
+	assert.Equal(t, "https://example.org", opened.TargetURL)
+
+	// A second failure while the incident is unresolved reuses it
+	again, created, err := repo.Open(context.Background(), targetID, "error", started.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, opened.ID, again.ID)
+	assert.Equal(t, "down", again.Cause)
+
+	ownerID, err := repo.GetOwnerID(context.Background(), opened.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, ownerID)
+
+	incidents, err := repo.GetUnresolvedByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+
+	unresolved, err := repo.GetUnresolvedByTargetID(context.Background(), targetID)
+	assert.NoError(t, err)
+	assert.Equal(t, opened.ID, unresolved.ID)
+
+	assert.NoError(t, repo.Acknowledge(context.Background(), opened.ID, user.ID, started.Add(2*time.Minute)))
+	assert.ErrorIs(t, repo.Acknowledge(context.Background(), opened.ID, user.ID, started.Add(3*time.Minute)), ErrIncidentNotFound)
+
+	acknowledged, err := repo.Get(context.Background(), opened.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusAcknowledged, acknowledged.Status)
+	assert.Equal(t, user.ID, *acknowledged.AcknowledgedBy)
+
+	resolved, err := repo.Resolve(context.Background(), targetID, started.Add(10*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusResolved, resolved.Status)
+	assert.Equal(t, 10*time.Minute, resolved.Duration(time.Now()))
+
+	resolved, err = repo.Resolve(context.Background(), targetID, time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, resolved)
+
+	incidents, err = repo.GetUnresolvedByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, incidents)
+
+	_, err = repo.GetUnresolvedByTargetID(context.Background(), targetID)
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestIncidentRepository_Search(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewIncidentRepository(tx)
+	user, targetID := createTestTarget(t, tx)
+	started := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+
+	first, _, err := repo.Open(context.Background(), targetID, "down", started)
+	assert.NoError(t, err)
+	_, err = repo.Resolve(context.Background(), targetID, started.Add(time.Hour))
+	assert.NoError(t, err)
+	second, _, err := repo.Open(context.Background(), targetID, "error", started.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+
+	incidents, err := repo.Search(context.Background(), user.ID, model.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 2)
+	assert.Equal(t, second.ID, incidents[0].ID)
+
+	incidents, err = repo.Search(context.Background(), user.ID, model.Filter{Status: model.StatusResolved})
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+	assert.Equal(t, first.ID, incidents[0].ID)
+
+	incidents, err = repo.Search(context.Background(), user.ID, model.Filter{Target: "EXAMPLE.org", From: started.AddDate(0, 0, 1)})
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+	assert.Equal(t, second.ID, incidents[0].ID)
+
+	incidents, err = repo.Search(context.Background(), user.ID, model.Filter{Target: "other.org"})
+	assert.NoError(t, err)
+	assert.Empty(t, incidents)
+
+	incidents, err = repo.Search(context.Background(), user.ID+1, model.Filter{})
+	assert.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestIncidentRepository_Events(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewIncidentRepository(tx)
+	user, targetID := createTestTarget(t, tx)
+	started := time.Now().UTC().Truncate(time.Second)
+
+	incident, _, err := repo.Open(context.Background(), targetID, "down", started)
+	assert.NoError(t, err)
+
+	opened := &model.Event{IncidentID: incident.ID, Kind: model.EventOpened, Message: "Target is down", CreatedAt: started}
+	assert.NoError(t, repo.AddEvent(context.Background(), opened))
+	assert.NotZero(t, opened.ID)
+
+	comment := &model.Event{IncidentID: incident.ID, Kind: model.EventComment, Message: "Looking into it", UserID: &user.ID, CreatedAt: started.Add(time.Minute)}
+	assert.NoError(t, repo.AddEvent(context.Background(), comment))
+
+	events, err := repo.GetEvents(context.Background(), incident.ID)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, model.EventOpened, events[0].Kind)
+	assert.Nil(t, events[0].UserID)
+	assert.Empty(t, events[0].UserEmail)
+	assert.Equal(t, model.EventComment, events[1].Kind)
+	assert.Equal(t, "test@example.com", events[1].UserEmail)
+}
+
+func TestIncidentRepository_NotFound(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewIncidentRepository(tx)
+
+	_, err := repo.Get(context.Background(), 99999)
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+
+	_, err = repo.GetOwnerID(context.Background(), 99999)
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	"github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+)
+
+// maxCommentLength bounds the size of a comment on the timeline
+const maxCommentLength = 2000
+
+// Common errors returned by the incident service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access an incident of a target they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to incident")
+	// ErrIncidentNotFound is returned when the requested incident does not exist or is no longer open.
+	ErrIncidentNotFound = errors.New("incident not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
+type IncidentServiceInterface interface {
+	HandleStatusChange(ctx context.Context, targetID int, status string) (*model.Incident, error)
+	RecordCheck(ctx context.Context, targetID int, result monitor.Result) error
+	RecordDependent(ctx context.Context, targetID int, dependentURL string) error
+	RecordNotification(ctx context.Context, incidentID int, status string, failed int) error
+	Acknowledge(ctx context.Context, id int, userID int) error
+	AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error
+	Comment(ctx context.Context, id int, userID int, message string) error
+	Get(ctx context.Context, id int, userID int) (*model.Incident, error)
+	Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error)
+	GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error)
+}
+
+var _ IncidentServiceInterface = (*IncidentService)(nil)
+
+type IncidentService struct {
+	repo repository.IncidentRepositoryInterface
+	now  func() time.Time
+}
+
+func NewIncidentService(repo repository.IncidentRepositoryInterface) *IncidentService {
+	return &IncidentService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// HandleStatusChange opens an incident when a target is confirmed to be
+// failing and resolves it when the target is up again. It returns the affected
+// incident, or nil when the status change does not touch any incident. A
+// target that starts flapping counts as failing.
+func (s *IncidentService) HandleStatusChange(ctx context.Context, targetID int, status string) (*model.Incident, error) {
+	if targetID <= 0 {
+		return nil, fmt.Errorf("%w: invalid targetID", ErrInvalidInput)
+	}
+
+	now := s.now()
+	switch status {
+	case "down", "error", "flapping":
+		incident, created, err := s.repo.Open(ctx, targetID, status, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open incident: %w", err)
+		}
+		if created {
+			s.addEvent(ctx, incident.ID, model.EventOpened, fmt.Sprintf("Incident opened, target is %s", status), nil, now)
+		}
+		return incident, nil
+	case "up":
+		incident, err := s.repo.Resolve(ctx, targetID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve incident: %w", err)
+		}
+		if incident != nil {
+			message := fmt.Sprintf("Target recovered after %s", incident.Duration(now).Truncate(time.Second))
+			s.addEvent(ctx, incident.ID, model.EventResolved, message, nil, now)
+		}
+		return incident, nil
+	default:
+		return nil, nil
+	}
+}
+
+// RecordCheck adds the result of a check to the timeline of the target's
+// unresolved incident. Checks of a target without one are not recorded.
+func (s *IncidentService) RecordCheck(ctx context.Context, targetID int, result monitor.Result) error {
+	if targetID <= 0 {
+		return fmt.Errorf("%w: invalid targetID", ErrInvalidInput)
+	}
+
+	incident, err := s.repo.GetUnresolvedByTargetID(ctx, targetID)
+	if errors.Is(err, repository.ErrIncidentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get unresolved incident: %w", err)
+	}
+
+	return s.repo.AddEvent(ctx, &model.Event{
+		IncidentID: incident.ID,
+		Kind:       model.EventCheck,
+		Message:    describeCheck(result),
+		CreatedAt:  result.CheckedAt,
+	})
+}
+
+// RecordDependent adds a target that became unreachable because of this one
+// to the timeline of its unresolved incident, instead of opening an incident
+// of its own. Without an unresolved incident nothing is recorded.
+func (s *IncidentService) RecordDependent(ctx context.Context, targetID int, dependentURL string) error {
+	if targetID <= 0 {
+		return fmt.Errorf("%w: invalid targetID", ErrInvalidInput)
+	}
+
+	incident, err := s.repo.GetUnresolvedByTargetID(ctx, targetID)
+	if errors.Is(err, repository.ErrIncidentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get unresolved incident: %w", err)
+	}
+
+	return s.repo.AddEvent(ctx, &model.Event{
+		IncidentID: incident.ID,
+		Kind:       model.EventDependent,
+		Message:    fmt.Sprintf("Dependent target %s is unreachable", dependentURL),
+		CreatedAt:  s.now(),
+	})
+}
+
+// describeCheck summarizes a check result for the timeline
+func describeCheck(result monitor.Result) string {
+	took := result.Duration.Round(time.Millisecond)
+	switch {
+	case result.Err != nil:
+		return fmt.Sprintf("Check failed after %s: %v", took, result.Err)
+	case result.Status == "up":
+		return fmt.Sprintf("Check passed with HTTP %d in %s", result.StatusCode, took)
+	default:
+		return fmt.Sprintf("Check failed with HTTP %d in %s", result.StatusCode, took)
+	}
+}
+
+// RecordNotification adds a sent status notification to the timeline of an incident
+func (s *IncidentService) RecordNotification(ctx context.Context, incidentID int, status string, failed int) error {
+	if incidentID <= 0 {
+		return fmt.Errorf("%w: invalid incidentID", ErrInvalidInput)
+	}
+
+	message := fmt.Sprintf("Sent %s notification to attached channels", status)
+	if failed > 0 {
+		message += fmt.Sprintf(", %d failed to deliver", failed)
+	}
+
+	return s.repo.AddEvent(ctx, &model.Event{
+		IncidentID: incidentID,
+		Kind:       model.EventNotification,
+		Message:    message,
+		CreatedAt:  s.now(),
+	})
+}
+
+// addEvent records a lifecycle event. The timeline is informational, so a
+// failure is logged rather than failing the transition that raised it.
+func (s *IncidentService) addEvent(ctx context.Context, incidentID int, kind model.EventKind, message string, userID *int, at time.Time) {
+	event := &model.Event{IncidentID: incidentID, Kind: kind, Message: message, UserID: userID, CreatedAt: at}
+	if err := s.repo.AddEvent(ctx, event); err != nil {
+		slog.Error("Failed to record incident event", "incident", incidentID, "kind", kind, "error", err)
+	}
+}
+
+// authorize verifies that the incident exists and that the user owns its target
+func (s *IncidentService) authorize(ctx context.Context, id int, userID int) error {
+	if id <= 0 || userID <= 0 {
+		return fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	ownerID, err := s.repo.GetOwnerID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrIncidentNotFound) {
+			return fmt.Errorf("%w: incident with id %d not found", ErrIncidentNotFound, id)
+		}
+		return fmt.Errorf("failed to get incident owner: %w", err)
+	}
+	if ownerID != userID {
+		return fmt.Errorf("%w: user %d does not own incident %d", ErrUnauthorized, userID, id)
+	}
+	return nil
+}
+
+// Acknowledge marks an open incident as handled by the user, which stops escalation
+func (s *IncidentService) Acknowledge(ctx context.Context, id int, userID int) error {
+	if err := s.authorize(ctx, id, userID); err != nil {
+		return err
+	}
+
+	now := s.now()
+	if err := s.repo.Acknowledge(ctx, id, userID, now); err != nil {
+		if errors.Is(err, repository.ErrIncidentNotFound) {
+			return fmt.Errorf("%w: incident %d is not open", ErrIncidentNotFound, id)
+		}
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	s.addEvent(ctx, id, model.EventAcknowledged, "Acknowledged", &userID, now)
+	return nil
+}
+
+// AcknowledgeFromSlack marks an open incident as handled from a Slack button.
+// Only channels of the target owner receive the button, so the incident is
+// acknowledged on the owner's behalf and the Slack user is kept on the timeline.
+func (s *IncidentService) AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid id", ErrInvalidInput)
+	}
+
+	ownerID, err := s.repo.GetOwnerID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrIncidentNotFound) {
+			return fmt.Errorf("%w: incident with id %d not found", ErrIncidentNotFound, id)
+		}
+		return fmt.Errorf("failed to get incident owner: %w", err)
+	}
+
+	now := s.now()
+	if err := s.repo.Acknowledge(ctx, id, ownerID, now); err != nil {
+		if errors.Is(err, repository.ErrIncidentNotFound) {
+			return fmt.Errorf("%w: incident %d is not open", ErrIncidentNotFound, id)
+		}
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	s.addEvent(ctx, id, model.EventAcknowledged, fmt.Sprintf("Acknowledged from Slack by @%s", slackUser), nil, now)
+	return nil
+}
+
+// Comment adds a note from the user to the timeline of an incident
+func (s *IncidentService) Comment(ctx context.Context, id int, userID int, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" || len(message) > maxCommentLength {
+		return fmt.Errorf("%w: comment must be between 1 and %d characters", ErrInvalidInput, maxCommentLength)
+	}
+
+	if err := s.authorize(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.AddEvent(ctx, &model.Event{
+		IncidentID: id,
+		Kind:       model.EventComment,
+		Message:    message,
+		UserID:     &userID,
+		CreatedAt:  s.now(),
+	})
+}
+
+// Get retrieves an incident of the user's targets together with its timeline
+func (s *IncidentService) Get(ctx context.Context, id int, userID int) (*model.Incident, error) {
+	if err := s.authorize(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	incident, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrIncidentNotFound) {
+			return nil, fmt.Errorf("%w: incident with id %d not found", ErrIncidentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+
+	incident.Events, err = s.repo.GetEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident timeline: %w", err)
+	}
+	return incident, nil
+}
+
+// Search retrieves the incidents of the user's targets matching the filter
+func (s *IncidentService) Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	incidents, err := s.repo.Search(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// GetUnresolvedByUserID retrieves the incidents of the user's targets that are not resolved yet
+func (s *IncidentService) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	incidents, err := s.repo.GetUnresolvedByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+	return incidents, nil
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/incident/model"
+	"github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIncidentRepository struct {
+	openFunc                    func(targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error)
+	resolveFunc                 func(targetID int, resolvedAt time.Time) (*model.Incident, error)
+	acknowledgeFunc             func(id int, userID int, acknowledgedAt time.Time) error
+	getFunc                     func(id int) (*model.Incident, error)
+	getUnresolvedByTargetIDFunc func(targetID int) (*model.Incident, error)
+	getUnresolvedByUserIDFunc   func(userID int) ([]*model.Incident, error)
+	searchFunc                  func(userID int, filter model.Filter) ([]*model.Incident, error)
+	getOwnerIDFunc              func(id int) (int, error)
+	getEventsFunc               func(incidentID int) ([]*model.Event, error)
+	events                      []*model.Event // events added through AddEvent
+}
+
+func (m *mockIncidentRepository) Open(ctx context.Context, targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error) {
+	return m.openFunc(targetID, cause, startedAt)
+}
+
+func (m *mockIncidentRepository) Resolve(ctx context.Context, targetID int, resolvedAt time.Time) (*model.Incident, error) {
+	return m.resolveFunc(targetID, resolvedAt)
+}
+
+func (m *mockIncidentRepository) Acknowledge(ctx context.Context, id int, userID int, acknowledgedAt time.Time) error {
+	return m.acknowledgeFunc(id, userID, acknowledgedAt)
+}
+
+func (m *mockIncidentRepository) Get(ctx context.Context, id int) (*model.Incident, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockIncidentRepository) GetUnresolvedByTargetID(ctx context.Context, targetID int) (*model.Incident, error) {
+	return m.getUnresolvedByTargetIDFunc(targetID)
+}
+
+func (m *mockIncidentRepository) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*model.Incident, error) {
+	return m.getUnresolvedByUserIDFunc(userID)
+}
+
+func (m *mockIncidentRepository) Search(ctx context.Context, userID int, filter model.Filter) ([]*model.Incident, error) {
+	return m.searchFunc(userID, filter)
+}
+
+func (m *mockIncidentRepository) GetOwnerID(ctx context.Context, id int) (int, error) {
+	return m.getOwnerIDFunc(id)
+}
+
+func (m *mockIncidentRepository) AddEvent(ctx context.Context, event *model.Event) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockIncidentRepository) GetEvents(ctx context.Context, incidentID int) ([]*model.Event, error) {
+	return m.getEventsFunc(incidentID)
+}
+
+func TestIncidentService_HandleStatusChange(t *testing.T) {
+	var opened, resolved []int
+	mockRepo := &mockIncidentRepository{
+		openFunc: func(targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error) {
+			opened = append(opened, targetID)
+			// Only the first failure creates the incident, later ones reuse it
+			return &model.Incident{ID: 1, TargetID: targetID, Status: model.StatusOpen, Cause: "down"}, len(opened) == 1, nil
+		},
+		resolveFunc: func(targetID int, resolvedAt time.Time) (*model.Incident, error) {
+			resolved = append(resolved, targetID)
+			return &model.Incident{ID: 1, TargetID: targetID, Status: model.StatusResolved, StartedAt: resolvedAt.Add(-5 * time.Minute), ResolvedAt: &resolvedAt}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	incident, err := service.HandleStatusChange(context.Background(), 3, "down")
+	assert.NoError(t, err)
+	assert.Equal(t, "down", incident.Cause)
+
+	_, err = service.HandleStatusChange(context.Background(), 3, "error")
+	assert.NoError(t, err)
+
+	_, err = service.HandleStatusChange(context.Background(), 3, "flapping")
+	assert.NoError(t, err)
+
+	incident, err = service.HandleStatusChange(context.Background(), 3, "up")
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusResolved, incident.Status)
+
+	incident, err = service.HandleStatusChange(context.Background(), 3, "paused")
+	assert.NoError(t, err)
+	assert.Nil(t, incident)
+
+	assert.Equal(t, []int{3, 3, 3}, opened)
+	assert.Equal(t, []int{3}, resolved)
+
+	// The timeline records the opening once and the recovery with its duration
+	assert.Len(t, mockRepo.events, 2)
+	assert.Equal(t, model.EventOpened, mockRepo.events[0].Kind)
+	assert.Equal(t, model.EventResolved, mockRepo.events[1].Kind)
+	assert.Equal(t, "Target recovered after 5m0s", mockRepo.events[1].Message)
+
+	_, err = service.HandleStatusChange(context.Background(), 0, "down")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	mockRepo.openFunc = func(targetID int, cause string, startedAt time.Time) (*model.Incident, bool, error) {
+		return nil, false, fmt.Errorf("db error")
+	}
+	_, err = service.HandleStatusChange(context.Background(), 3, "down")
+	assert.ErrorContains(t, err, "failed to open incident")
+}
+
+func TestIncidentService_Acknowledge(t *testing.T) {
+	acknowledged := false
+	mockRepo := &mockIncidentRepository{
+		getOwnerIDFunc: func(id int) (int, error) {
+			if id != 1 {
+				return 0, repository.ErrIncidentNotFound
+			}
+			return 1, nil
+		},
+		acknowledgeFunc: func(id int, userID int, acknowledgedAt time.Time) error {
+			acknowledged = true
+			return nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		assert.ErrorIs(t, service.Acknowledge(context.Background(), 1, 2), ErrUnauthorized)
+		assert.False(t, acknowledged)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.ErrorIs(t, service.Acknowledge(context.Background(), 5, 1), ErrIncidentNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, service.Acknowledge(context.Background(), 1, 1))
+		assert.True(t, acknowledged)
+		assert.Len(t, mockRepo.events, 1)
+		assert.Equal(t, model.EventAcknowledged, mockRepo.events[0].Kind)
+		assert.Equal(t, 1, *mockRepo.events[0].UserID)
+	})
+
+	t.Run("already acknowledged", func(t *testing.T) {
+		mockRepo.acknowledgeFunc = func(id int, userID int, acknowledgedAt time.Time) error {
+			return repository.ErrIncidentNotFound
+		}
+		assert.ErrorIs(t, service.Acknowledge(context.Background(), 1, 1), ErrIncidentNotFound)
+	})
+}
+
+func TestIncidentService_GetUnresolvedByUserID(t *testing.T) {
+	mockRepo := &mockIncidentRepository{
+		getUnresolvedByUserIDFunc: func(userID int) ([]*model.Incident, error) {
+			return []*model.Incident{{ID: 1}}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	incidents, err := service.GetUnresolvedByUserID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+
+	_, err = service.GetUnresolvedByUserID(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestIncidentService_AcknowledgeFromSlack(t *testing.T) {
+	var acknowledgedBy int
+	mockRepo := &mockIncidentRepository{
+		getOwnerIDFunc: func(id int) (int, error) {
+			if id != 1 {
+				return 0, repository.ErrIncidentNotFound
+			}
+			return 4, nil
+		},
+		acknowledgeFunc: func(id int, userID int, acknowledgedAt time.Time) error {
+			acknowledgedBy = userID
+			return nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	assert.ErrorIs(t, service.AcknowledgeFromSlack(context.Background(), 2, "alice"), ErrIncidentNotFound)
+
+	assert.NoError(t, service.AcknowledgeFromSlack(context.Background(), 1, "alice"))
+	assert.Equal(t, 4, acknowledgedBy)
+	assert.Len(t, mockRepo.events, 1)
+	assert.Equal(t, "Acknowledged from Slack by @alice", mockRepo.events[0].Message)
+	assert.Nil(t, mockRepo.events[0].UserID)
+}
+
+func TestIncidentService_RecordCheck(t *testing.T) {
+	mockRepo := &mockIncidentRepository{
+		getUnresolvedByTargetIDFunc: func(targetID int) (*model.Incident, error) {
+			if targetID != 3 {
+				return nil, repository.ErrIncidentNotFound
+			}
+			return &model.Incident{ID: 1, TargetID: targetID}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+	checkedAt := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+
+	// Targets without an unresolved incident have no timeline to add to
+	assert.NoError(t, service.RecordCheck(context.Background(), 5, monitor.Result{Status: "down", StatusCode: 503}))
+	assert.Empty(t, mockRepo.events)
+
+	assert.NoError(t, service.RecordCheck(context.Background(), 3, monitor.Result{Status: "down", StatusCode: 503, Duration: 120 * time.Millisecond, CheckedAt: checkedAt}))
+	assert.NoError(t, service.RecordCheck(context.Background(), 3, monitor.Result{Status: "error", Err: fmt.Errorf("connection refused"), Duration: time.Millisecond, CheckedAt: checkedAt}))
+	assert.NoError(t, service.RecordCheck(context.Background(), 3, monitor.Result{Status: "up", StatusCode: 200, Duration: 80 * time.Millisecond, CheckedAt: checkedAt}))
+
+	assert.Len(t, mockRepo.events, 3)
+	assert.Equal(t, model.EventCheck, mockRepo.events[0].Kind)
+	assert.Equal(t, checkedAt, mockRepo.events[0].CreatedAt)
+	assert.Equal(t, "Check failed with HTTP 503 in 120ms", mockRepo.events[0].Message)
+	assert.Equal(t, "Check failed after 1ms: connection refused", mockRepo.events[1].Message)
+	assert.Equal(t, "Check passed with HTTP 200 in 80ms", mockRepo.events[2].Message)
+}
+
+func TestIncidentService_RecordDependent(t *testing.T) {
+	mockRepo := &mockIncidentRepository{
+		getUnresolvedByTargetIDFunc: func(targetID int) (*model.Incident, error) {
+			if targetID != 3 {
+				return nil, repository.ErrIncidentNotFound
+			}
+			return &model.Incident{ID: 1, TargetID: targetID}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	assert.NoError(t, service.RecordDependent(context.Background(), 5, "https://app.example.com"))
+	assert.Empty(t, mockRepo.events)
+
+	assert.NoError(t, service.RecordDependent(context.Background(), 3, "https://app.example.com"))
+	if assert.Len(t, mockRepo.events, 1) {
+		assert.Equal(t, 1, mockRepo.events[0].IncidentID)
+		assert.Equal(t, model.EventDependent, mockRepo.events[0].Kind)
+		assert.Equal(t, "Dependent target https://app.example.com is unreachable", mockRepo.events[0].Message)
+	}
+
+	assert.ErrorIs(t, service.RecordDependent(context.Background(), 0, "https://app.example.com"), ErrInvalidInput)
+}
+
+func TestIncidentService_RecordNotification(t *testing.T) {
+	mockRepo := &mockIncidentRepository{}
+	service := NewIncidentService(mockRepo)
+
+	assert.NoError(t, service.RecordNotification(context.Background(), 1, "down", 0))
+	assert.NoError(t, service.RecordNotification(context.Background(), 1, "up", 2))
+	assert.ErrorIs(t, service.RecordNotification(context.Background(), 0, "down", 0), ErrInvalidInput)
+
+	assert.Len(t, mockRepo.events, 2)
+	assert.Equal(t, "Sent down notification to attached channels", mockRepo.events[0].Message)
+	assert.Equal(t, "Sent up notification to attached channels, 2 failed to deliver", mockRepo.events[1].Message)
+}
+
+func TestIncidentService_Comment(t *testing.T) {
+	mockRepo := &mockIncidentRepository{
+		getOwnerIDFunc: func(id int) (int, error) {
+			return 1, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	assert.ErrorIs(t, service.Comment(context.Background(), 1, 1, "   "), ErrInvalidInput)
+	assert.ErrorIs(t, service.Comment(context.Background(), 1, 2, "Looking into it"), ErrUnauthorized)
+	assert.Empty(t, mockRepo.events)
+
+	assert.NoError(t, service.Comment(context.Background(), 1, 1, "  Looking into it "))
+	assert.Len(t, mockRepo.events, 1)
+	assert.Equal(t, model.EventComment, mockRepo.events[0].Kind)
+	assert.Equal(t, "Looking into it", mockRepo.events[0].Message)
+	assert.Equal(t, 1, *mockRepo.events[0].UserID)
+}
+
+func TestIncidentService_Get(t *testing.T) {
+	mockRepo := &mockIncidentRepository{
+		getOwnerIDFunc: func(id int) (int, error) {
+			return 1, nil
+		},
+		getFunc: func(id int) (*model.Incident, error) {
+			return &model.Incident{ID: id}, nil
+		},
+		getEventsFunc: func(incidentID int) ([]*model.Event, error) {
+			return []*model.Event{{IncidentID: incidentID, Kind: model.EventOpened}}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	incident, err := service.Get(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, incident.Events, 1)
+
+	_, err = service.Get(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestIncidentService_Search(t *testing.T) {
+	var got model.Filter
+	mockRepo := &mockIncidentRepository{
+		searchFunc: func(userID int, filter model.Filter) ([]*model.Incident, error) {
+			got = filter
+			return []*model.Incident{{ID: 1}}, nil
+		},
+	}
+	service := NewIncidentService(mockRepo)
+
+	incidents, err := service.Search(context.Background(), 1, model.Filter{Status: model.StatusOpen})
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+	assert.Equal(t, model.StatusOpen, got.Status)
+
+	_, err = service.Search(context.Background(), 1, model.Filter{Status: "closed"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = service.Search(context.Background(), 0, model.Filter{})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/service"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const (
+	dateTimeFormat = "2006-01-02T15:04"
+	displayFormat  = "Mon Jan 2, 15:04 MST"
+)
+
+type WindowHandler struct {
+	windowService service.WindowServiceInterface
+	flash         flash.FlashStoreInterface
+	now           func() time.Time
+	Template      struct {
+		List   *renderer.Template
+		Create *renderer.Template
+		Edit   *renderer.Template
+	}
+}
+
+func NewWindowHandler(windowService service.WindowServiceInterface, flash flash.FlashStoreInterface) *WindowHandler {
+	return &WindowHandler{
+		windowService: windowService,
+		flash:         flash,
+		now:           time.Now,
+	}
+}
+
+// windowRow is a window as listed, with its times in the window's timezone
+type windowRow struct {
+	ID        int
+	Name      string
+	Schedule  string
+	AppliesTo []string
+	Active    bool
+	Period    string // the open or next period, empty when there is none
+}
+
+// targetOption is a target offered on the form
+type targetOption struct {
+	ID       int
+	URL      string
+	Selected bool
+}
+
+// errorStatus maps maintenance window service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrWindowNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseID reads a positive integer path value
+func parseID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// parseWindow reads a window from the submitted form. Times are given in the
+// window's timezone.
+func parseWindow(r *http.Request) (*model.Window, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("invalid form")
+	}
+
+	window := &model.Window{
+		Name:     r.PostForm.Get("name"),
+		Timezone: strings.TrimSpace(r.PostForm.Get("timezone")),
+		Tags:     monitorModel.ParseTags(r.PostForm.Get("tags")),
+	}
+	loc, err := window.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	window.StartsAt, err = time.ParseInLocation(dateTimeFormat, r.PostForm.Get("starts_at"), loc)
+	if err != nil {
+		return nil, fmt.Errorf("start is required")
+	}
+	if value := r.PostForm.Get("ends_at"); value != "" {
+		endsAt, err := time.ParseInLocation(dateTimeFormat, value, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end")
+		}
+		window.EndsAt = &endsAt
+	}
+
+	if r.PostForm.Get("kind") == "recurring" {
+		window.Recurrence = strings.TrimSpace(r.PostForm.Get("recurrence"))
+		if window.Recurrence == "" {
+			return nil, fmt.Errorf("recurrence is required")
+		}
+		minutes, err := strconv.Atoi(r.PostForm.Get("duration"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration")
+		}
+		window.Duration = time.Duration(minutes) * time.Minute
+	}
+
+	for _, value := range r.PostForm["target_ids"] {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target")
+		}
+		window.TargetIDs = append(window.TargetIDs, id)
+	}
+	return window, nil
+}
+
+// formData prepares a window for the create and edit forms
+func (h *WindowHandler) formData(ctx context.Context, window *model.Window, userID int) (map[string]any, error) {
+	targets, err := h.windowService.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]targetOption, len(targets))
+	for i, target := range targets {
+		options[i] = targetOption{ID: target.ID, URL: target.URL, Selected: slices.Contains(window.TargetIDs, target.ID)}
+	}
+
+	loc, err := window.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+	data := map[string]any{
+		"window":    window,
+		"targets":   options,
+		"tags":      strings.Join(window.Tags, ", "),
+		"startsAt":  window.StartsAt.In(loc).Format(dateTimeFormat),
+		"duration":  int(window.Duration / time.Minute),
+		"recurring": window.Recurring(),
+	}
+	if window.EndsAt != nil {
+		data["endsAt"] = window.EndsAt.In(loc).Format(dateTimeFormat)
+	}
+	return data, nil
+}
+
+// List shows the user's maintenance windows, with the period each is open
+// now or opens next
+func (h *WindowHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	windows, err := h.windowService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	targets, err := h.windowService.GetTargets(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	urls := make(map[int]string, len(targets))
+	for _, target := range targets {
+		urls[target.ID] = target.URL
+	}
+
+	now := h.now()
+	rows := make([]windowRow, 0, len(windows))
+	for _, window := range windows {
+		loc, err := window.Location()
+		if err != nil {
+			loc = time.UTC
+		}
+
+		row := windowRow{ID: window.ID, Name: window.Name, Schedule: "Once"}
+		if window.Recurring() {
+			row.Schedule = fmt.Sprintf("%s in %s, for %s", window.Recurrence, loc, window.Duration)
+		}
+		for _, id := range window.TargetIDs {
+			if url, ok := urls[id]; ok {
+				row.AppliesTo = append(row.AppliesTo, url)
+			}
+		}
+		for _, tag := range window.Tags {
+			row.AppliesTo = append(row.AppliesTo, "#"+tag)
+		}
+		if start, end, ok := window.Next(now); ok {
+			row.Active = !now.Before(start)
+			row.Period = start.In(loc).Format(displayFormat) + " to " + end.In(loc).Format(displayFormat)
+		}
+		rows = append(rows, row)
+	}
+
+	data := map[string]any{
+		"title":   "maintenance windows",
+		"windows": rows,
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+func (h *WindowHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		start := h.now().Truncate(time.Hour).Add(time.Hour)
+		end := start.Add(time.Hour)
+		data, err := h.formData(r.Context(), &model.Window{StartsAt: start, EndsAt: &end, Duration: time.Hour, Timezone: "UTC"}, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data["title"] = "add a maintenance window"
+		h.Template.Create.Render(w, r, data)
+		return
+	}
+
+	createURL := "/app/maintenance/create"
+	window, err := parseWindow(r)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid maintenance window: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := h.windowService.Create(r.Context(), window, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to create maintenance window: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Maintenance window created successfully"})
+	http.Redirect(w, r, "/app/maintenance", http.StatusSeeOther)
+}
+
+func (h *WindowHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/maintenance/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	window, err := h.windowService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data, err := h.formData(r.Context(), window, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data["title"] = "edit maintenance window"
+		h.Template.Edit.Render(w, r, data)
+		return
+	}
+
+	updated, err := parseWindow(r)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid maintenance window: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	updated.ID = window.ID
+
+	if _, err := h.windowService.Update(r.Context(), updated, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to update maintenance window: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Maintenance window updated successfully"})
+	http.Redirect(w, r, "/app/maintenance", http.StatusSeeOther)
+}
+
+func (h *WindowHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.windowService.Delete(r.Context(), id, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to delete maintenance window: " + err.Error()})
+		http.Redirect(w, r, "/app/maintenance", http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Maintenance window deleted successfully"})
+	http.Redirect(w, r, "/app/maintenance", http.StatusSeeOther)
+}
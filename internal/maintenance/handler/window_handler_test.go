@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockWindowService struct {
+	createFunc        func(window *model.Window, userID int) error
+	getFunc           func(id int, userID int) (*model.Window, error)
+	getByUserIDFunc   func(userID int) ([]*model.Window, error)
+	updateFunc        func(window *model.Window, userID int) (*model.Window, error)
+	deleteFunc        func(id int, userID int) error
+	inMaintenanceFunc func(targetID int, at time.Time) (bool, error)
+}
+
+func (m *MockWindowService) Create(ctx context.Context, window *model.Window, userID int) error {
+	return m.createFunc(window, userID)
+}
+
+func (m *MockWindowService) Get(ctx context.Context, id int, userID int) (*model.Window, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockWindowService) GetByUserID(ctx context.Context, userID int) ([]*model.Window, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *MockWindowService) Update(ctx context.Context, window *model.Window, userID int) (*model.Window, error) {
+	return m.updateFunc(window, userID)
+}
+
+func (m *MockWindowService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *MockWindowService) GetTargets(ctx context.Context, userID int) ([]model.Target, error) {
+	return []model.Target{
+		{ID: 1, URL: "https://example.org", Tags: []string{"web"}},
+		{ID: 2, URL: "https://api.example.org", Tags: []string{"api"}},
+	}, nil
+}
+
+func (m *MockWindowService) InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error) {
+	return m.inMaintenanceFunc(targetID, at)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID, Email: "alice@example.com"})
+	return req.WithContext(ctx)
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+var testNow = time.Date(2025, 5, 4, 0, 30, 0, 0, time.UTC)
+
+func newTestWindowHandler(mockService *MockWindowService) *WindowHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewWindowHandler(mockService, mockFlashStore)
+	handler.now = func() time.Time { return testNow }
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:maintenance/list")
+	handler.Template.Create = templateRenderer.GetTemplate("pages:maintenance/create")
+	handler.Template.Edit = templateRenderer.GetTemplate("pages:maintenance/edit")
+	return handler
+}
+
+func ownedWindow(id int, userID int) (*model.Window, error) {
+	if userID != 1 {
+		return nil, service.ErrUnauthorized
+	}
+	return &model.Window{
+		ID:         id,
+		UserID:     1,
+		Name:       "Weekly deploy",
+		StartsAt:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Recurrence: "0 2 * * SUN",
+		Duration:   time.Hour,
+		Timezone:   "Europe/Berlin",
+		TargetIDs:  []int{1},
+		Tags:       []string{"api"},
+	}, nil
+}
+
+func TestWindowHandler_List(t *testing.T) {
+	mockService := &MockWindowService{
+		getByUserIDFunc: func(userID int) ([]*model.Window, error) {
+			window, _ := ownedWindow(1, userID)
+			return []*model.Window{window}, nil
+		},
+	}
+	handler := newTestWindowHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/maintenance", nil)
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	body := w.Body.String()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, body, "Weekly deploy")
+	assert.Contains(t, body, "https://example.org")
+	assert.Contains(t, body, "#api")
+	// 02:00 in Berlin is midnight UTC, so the Sunday window is open
+	assert.Contains(t, body, "in progress")
+	assert.Contains(t, body, "Sun May 4, 02:00 CEST to Sun May 4, 03:00 CEST")
+}
+
+func TestWindowHandler_Create(t *testing.T) {
+	mockService := &MockWindowService{}
+	handler := newTestWindowHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/maintenance/create", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "2025-05-04T01:00")
+		assert.Contains(t, w.Body.String(), "https://api.example.org")
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var created *model.Window
+		mockService.createFunc = func(window *model.Window, userID int) error {
+			created = window
+			return nil
+		}
+
+		form := url.Values{
+			"name":       {"Weekly deploy"},
+			"timezone":   {"Europe/Berlin"},
+			"starts_at":  {"2025-05-01T00:00"},
+			"kind":       {"recurring"},
+			"recurrence": {"FREQ=WEEKLY;BYDAY=SU;BYHOUR=2"},
+			"duration":   {"90"},
+			"target_ids": {"1", "2"},
+			"tags":       {"api, web"},
+		}
+		req := postForm("/app/maintenance/create", form)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/maintenance", w.Header().Get("Location"))
+		assert.Equal(t, 90*time.Minute, created.Duration)
+		assert.Equal(t, []int{1, 2}, created.TargetIDs)
+		// Times are entered in the window's timezone
+		assert.True(t, created.StartsAt.Equal(time.Date(2025, 4, 30, 22, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("POST request - invalid timezone", func(t *testing.T) {
+		req := postForm("/app/maintenance/create", url.Values{"name": {"Deploy"}, "timezone": {"Mars/Olympus"}, "starts_at": {"2025-05-01T00:00"}})
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, "/app/maintenance/create", w.Header().Get("Location"))
+	})
+}
+
+func TestWindowHandler_Edit(t *testing.T) {
+	var updated *model.Window
+	mockService := &MockWindowService{
+		getFunc: ownedWindow,
+		updateFunc: func(window *model.Window, userID int) (*model.Window, error) {
+			updated = window
+			return window, nil
+		},
+	}
+	handler := newTestWindowHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/maintenance/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "0 2 * * SUN")
+		assert.Contains(t, w.Body.String(), "2025-05-01T02:00")
+	})
+
+	t.Run("POST request", func(t *testing.T) {
+		form := url.Values{
+			"name":      {"Database upgrade"},
+			"timezone":  {"UTC"},
+			"starts_at": {"2025-05-04T02:00"},
+			"ends_at":   {"2025-05-04T04:00"},
+		}
+		req := postForm("/app/maintenance/edit/1", form)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, 1, updated.ID)
+		assert.False(t, updated.Recurring())
+		assert.True(t, updated.EndsAt.Equal(time.Date(2025, 5, 4, 4, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/maintenance/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestWindowHandler_Delete(t *testing.T) {
+	deleted := 0
+	mockService := &MockWindowService{
+		deleteFunc: func(id int, userID int) error {
+			deleted = id
+			return nil
+		},
+	}
+	handler := newTestWindowHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/maintenance/delete/3", nil)
+	req.SetPathValue("id", "3")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+	handler.Delete(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/app/maintenance", w.Header().Get("Location"))
+	assert.Equal(t, 3, deleted)
+}
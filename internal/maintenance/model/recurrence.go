@@ -0,0 +1,415 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/pkg/civil"
+)
+
+// schedule tells the minutes at which a recurring window opens, field by
+// field, so occurrences can be found without trying every minute
+type schedule interface {
+	// day reports whether the window opens on the calendar date of t
+	day(t time.Time) bool
+	hour(h int) bool
+	minute(m int) bool
+}
+
+// bits is a set of small non-negative numbers
+type bits uint64
+
+func (b bits) has(n int) bool {
+	return n >= 0 && n < 64 && b&(1<<uint(n)) != 0
+}
+
+func (b *bits) add(n int) {
+	*b |= 1 << uint(n)
+}
+
+// parseRecurrence reads a recurrence rule. Rules containing FREQ= are RRULEs,
+// anything else is a cron expression. RRULEs count their interval and take
+// their defaults from start, given in the window's timezone.
+func parseRecurrence(rule string, start time.Time) (schedule, error) {
+	rule = strings.TrimSpace(rule)
+	if strings.Contains(strings.ToUpper(rule), "FREQ=") {
+		return parseRRule(rule, start)
+	}
+	return parseCron(rule)
+}
+
+// cronMacros are the shorthands cron accepts for common schedules
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var (
+	monthNames   = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	weekdayNames = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// cron is a standard five field cron expression: minute, hour, day of month,
+// month and day of week
+type cron struct {
+	minutes, hours, days, months, weekdays bits
+	// A restricted day of month and day of week match either, as in cron
+	anyDay, anyWeekday bool
+}
+
+func parseCron(expr string) (*cron, error) {
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	c := &cron{}
+	var err error
+	if c.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute: %v", err)
+	}
+	if c.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour: %v", err)
+	}
+	if c.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month: %v", err)
+	}
+	if c.months, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month: %v", err)
+	}
+	// Sunday is both 0 and 7
+	if c.weekdays, err = parseCronField(fields[4], 0, 7, weekdayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week: %v", err)
+	}
+	if c.weekdays.has(7) {
+		c.weekdays.add(0)
+	}
+	c.anyDay = strings.HasPrefix(fields[2], "*")
+	c.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField reads a comma separated list of values, ranges and steps
+func parseCronField(field string, min, max int, names map[string]int) (bits, error) {
+	var set bits
+	for _, part := range strings.Split(field, ",") {
+		span, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		low, high := min, max
+		if span != "*" {
+			from, to, isRange := strings.Cut(span, "-")
+			var err error
+			if low, err = cronValue(from, min, max, names); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = cronValue(to, min, max, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// A single value with a step runs to the end of the range
+				high = max
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range %q", span)
+			}
+		}
+
+		for n := low; n <= high; n += step {
+			set.add(n)
+		}
+	}
+	return set, nil
+}
+
+func cronValue(text string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(text)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", text, min, max)
+	}
+	return n, nil
+}
+
+func (c *cron) day(t time.Time) bool {
+	if !c.months.has(int(t.Month())) {
+		return false
+	}
+	day, weekday := c.days.has(t.Day()), c.weekdays.has(int(t.Weekday()))
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func (c *cron) hour(h int) bool   { return c.hours.has(h) }
+func (c *cron) minute(m int) bool { return c.minutes.has(m) }
+
+// Supported RRULE frequencies
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+)
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// weekdayNum is a BYDAY entry. A non-zero Nth picks a single weekday of the
+// month, counted from its end when negative.
+type weekdayNum struct {
+	Nth     int
+	Weekday time.Weekday
+}
+
+// rrule is the subset of RFC 5545 recurrence rules that maintenance windows
+// need: FREQ of DAILY, WEEKLY or MONTHLY with INTERVAL, BYDAY, BYMONTHDAY,
+// BYHOUR and BYMINUTE. Parts that are left out default to the window's start.
+type rrule struct {
+	freq      string
+	interval  int
+	weekdays  []weekdayNum
+	monthDays []int // negative days count from the end of the month
+	hours     bits
+	minutes   bits
+	start     time.Time // start of the window, in its timezone
+}
+
+func parseRRule(rule string, start time.Time) (*rrule, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(rule), "RRULE:")
+	r := &rrule{interval: 1, start: start}
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case freqDaily, freqWeekly, freqMonthly:
+				r.freq = value
+			default:
+				return nil, fmt.Errorf("unsupported frequency %s", value)
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval <= 0 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+		case "BYDAY":
+			r.weekdays, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.monthDays, err = parseByMonthDay(value)
+		case "BYHOUR":
+			r.hours, err = parseCronField(value, 0, 23, nil)
+		case "BYMINUTE":
+			r.minutes, err = parseCronField(value, 0, 59, nil)
+		case "WKST":
+			// Weeks always start on Monday
+			if value != "MO" {
+				return nil, fmt.Errorf("only WKST=MO is supported")
+			}
+		case "UNTIL", "COUNT":
+			return nil, fmt.Errorf("%s is not supported, set an end date instead", key)
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if r.hours == 0 {
+		r.hours.add(start.Hour())
+	}
+	if r.minutes == 0 {
+		r.minutes.add(start.Minute())
+	}
+	for _, wd := range r.weekdays {
+		if wd.Nth != 0 && r.freq != freqMonthly {
+			return nil, fmt.Errorf("numbered BYDAY is only supported with FREQ=MONTHLY")
+		}
+	}
+	// Without BY parts a rule repeats on the weekday or day of month it starts on
+	if r.freq == freqWeekly && len(r.weekdays) == 0 {
+		r.weekdays = []weekdayNum{{Weekday: start.Weekday()}}
+	}
+	if r.freq == freqMonthly && len(r.weekdays) == 0 && len(r.monthDays) == 0 {
+		r.monthDays = []int{start.Day()}
+	}
+	return r, nil
+}
+
+func parseByDay(value string) ([]weekdayNum, error) {
+	var days []weekdayNum
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		weekday, ok := rruleWeekdays[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		day := weekdayNum{Weekday: weekday}
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid weekday %q", entry)
+			}
+			day.Nth = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(entry)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("invalid day of month %q", entry)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+func (r *rrule) day(t time.Time) bool {
+	if civil.Day(t) < civil.Day(r.start) {
+		return false
+	}
+
+	switch r.freq {
+	case freqDaily:
+		if (civil.Day(t)-civil.Day(r.start))%r.interval != 0 {
+			return false
+		}
+	case freqWeekly:
+		if (weekStart(t)-weekStart(r.start))/7%r.interval != 0 {
+			return false
+		}
+	case freqMonthly:
+		months := (t.Year()-r.start.Year())*12 + int(t.Month()) - int(r.start.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+	}
+
+	if len(r.monthDays) > 0 && !r.matchesMonthDay(t) {
+		return false
+	}
+	if len(r.weekdays) > 0 && !r.matchesWeekday(t) {
+		return false
+	}
+	return true
+}
+
+func (r *rrule) matchesMonthDay(t time.Time) bool {
+	last := daysIn(t)
+	for _, day := range r.monthDays {
+		if day == t.Day() || (day < 0 && last+day+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rrule) matchesWeekday(t time.Time) bool {
+	for _, wd := range r.weekdays {
+		if wd.Weekday != t.Weekday() {
+			continue
+		}
+		switch {
+		case wd.Nth == 0:
+			return true
+		case wd.Nth > 0 && (t.Day()-1)/7+1 == wd.Nth:
+			return true
+		case wd.Nth < 0 && (daysIn(t)-t.Day())/7+1 == -wd.Nth:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rrule) hour(h int) bool   { return r.hours.has(h) }
+func (r *rrule) minute(m int) bool { return r.minutes.has(m) }
+
+// weekStart returns the civil day of the Monday starting t's week
+func weekStart(t time.Time) int {
+	return civil.Day(t) - (int(t.Weekday())+6)%7
+}
+
+// daysIn returns the number of days in t's month
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// lastStart returns the latest minute in (after, t] at which the schedule
+// opens, in loc. Days and hours that do not match are skipped whole.
+func lastStart(s schedule, t, after time.Time, loc *time.Location) (time.Time, bool) {
+	cur := t.In(loc).Truncate(time.Minute)
+	for cur.After(after) {
+		switch {
+		case !s.day(cur):
+			cur = time.Date(cur.Year(), cur.Month(), cur.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.hour(cur.Hour()):
+			cur = cur.Add(-time.Duration(cur.Minute()+1) * time.Minute)
+		case !s.minute(cur.Minute()):
+			cur = cur.Add(-time.Minute)
+		default:
+			return cur, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextStart returns the earliest minute in [from, before) at which the
+// schedule opens, in loc. from must be a whole minute.
+func nextStart(s schedule, from, before time.Time, loc *time.Location) (time.Time, bool) {
+	cur := from.In(loc)
+	for cur.Before(before) {
+		switch {
+		case !s.day(cur):
+			cur = time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour(cur.Hour()):
+			cur = cur.Add(time.Duration(60-cur.Minute()) * time.Minute)
+		case !s.minute(cur.Minute()):
+			cur = cur.Add(time.Minute)
+		default:
+			return cur, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"0 2 * * 0", "*/15 1-3 * * MON-FRI", "30 4 1,15 * *", "0 0 * JAN,JUL 7", "@weekly", "5/10 * * * *"}
+	for _, expr := range valid {
+		_, err := parseCron(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	invalid := []string{"", "0 2 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "x * * * *"}
+	for _, expr := range invalid {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCron_Day(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		day  time.Time
+		want bool
+	}{
+		{"any day", "0 0 * * *", time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC), true},
+		{"weekday matches", "0 0 * * 0", time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), true},
+		{"weekday does not match", "0 0 * * 0", time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), false},
+		{"sunday as 7", "0 0 * * 7", time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), true},
+		{"month does not match", "0 0 * FEB *", time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), false},
+		{"restricted day of month or weekday", "0 0 1 * MON", time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), true},
+		{"restricted day of month or weekday, neither", "0 0 1 * MON", time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseCron(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, c.day(tt.day))
+		})
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	start := time.Date(2025, 5, 4, 2, 30, 0, 0, time.UTC) // a Sunday
+
+	valid := []string{
+		"FREQ=DAILY",
+		"RRULE:FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=1",
+		"FREQ=MONTHLY;BYDAY=1SU",
+		"FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=23;BYMINUTE=0",
+		"freq=weekly;interval=2",
+	}
+	for _, rule := range valid {
+		_, err := parseRRule(rule, start)
+		assert.NoError(t, err, rule)
+	}
+
+	invalid := []string{
+		"BYDAY=MO",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;UNTIL=20250601T000000Z",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYSETPOS=1",
+	}
+	for _, rule := range invalid {
+		_, err := parseRRule(rule, start)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestRRule_Day(t *testing.T) {
+	start := time.Date(2025, 5, 4, 2, 30, 0, 0, time.UTC) // a Sunday
+
+	tests := []struct {
+		name string
+		rule string
+		day  time.Time
+		want bool
+	}{
+		{"before the start", "FREQ=DAILY", time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC), false},
+		{"daily", "FREQ=DAILY", time.Date(2025, 5, 9, 0, 0, 0, 0, time.UTC), true},
+		{"every other day", "FREQ=DAILY;INTERVAL=2", time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), false},
+		{"every other day, on", "FREQ=DAILY;INTERVAL=2", time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC), true},
+		{"weekly defaults to the start weekday", "FREQ=WEEKLY", time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC), true},
+		{"weekly, other weekday", "FREQ=WEEKLY", time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), false},
+		{"every other week, off week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC), false},
+		{"every other week, on week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC), true},
+		{"monthly defaults to the start day", "FREQ=MONTHLY", time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), true},
+		{"first sunday", "FREQ=MONTHLY;BYDAY=1SU", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), true},
+		{"second sunday is not the first", "FREQ=MONTHLY;BYDAY=1SU", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC), false},
+		{"last friday", "FREQ=MONTHLY;BYDAY=-1FR", time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC), true},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), true},
+		{"every other month, off month", "FREQ=MONTHLY;INTERVAL=2", time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parseRRule(tt.rule, start)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, r.day(tt.day))
+		})
+	}
+}
+
+func TestRRule_DefaultsToStartTime(t *testing.T) {
+	start := time.Date(2025, 5, 4, 2, 30, 0, 0, time.UTC)
+
+	r, err := parseRRule("FREQ=DAILY", start)
+	assert.NoError(t, err)
+	assert.True(t, r.hour(2))
+	assert.False(t, r.hour(3))
+	assert.True(t, r.minute(30))
+	assert.False(t, r.minute(0))
+}
+
+func TestLastAndNextStart(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	c, err := parseCron("0 2 * * SUN")
+	assert.NoError(t, err)
+
+	at := time.Date(2025, 5, 7, 12, 0, 0, 0, berlin) // a Wednesday
+	last, ok := lastStart(c, at, at.Add(-7*24*time.Hour), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 5, 4, 2, 0, 0, 0, berlin), last)
+
+	_, ok = lastStart(c, at, at.Add(-24*time.Hour), berlin)
+	assert.False(t, ok)
+
+	next, ok := nextStart(c, at, at.Add(7*24*time.Hour), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 5, 11, 2, 0, 0, 0, berlin), next)
+}
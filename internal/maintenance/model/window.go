@@ -0,0 +1,187 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxDuration bounds how long each occurrence of a recurring window lasts
+const MaxDuration = 7 * 24 * time.Hour
+
+// lookahead is how far ahead Next searches for the next occurrence
+const lookahead = 366 * 24 * time.Hour
+
+// Window is planned downtime for some of a user's targets. Checks go on
+// during a window, but their results raise no alerts and do not count against
+// uptime.
+//
+// A one-off window runs from StartsAt to EndsAt. A recurring window opens at
+// each occurrence of its Recurrence in its Timezone, from StartsAt on, and
+// stays open for Duration. Its EndsAt, when set, is when it stops recurring.
+type Window struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	Name       string    `db:"name"`
+	StartsAt   time.Time `db:"starts_at"`
+	EndsAt     *time.Time
+	Recurrence string        `db:"recurrence"` // cron expression or RRULE, empty for a one-off window
+	Duration   time.Duration // of each occurrence of a recurring window
+	Timezone   string        `db:"timezone"`
+	TargetIDs  []int
+	Tags       []string
+}
+
+// Target is a target that windows can be attached to
+type Target struct {
+	ID   int      `db:"id"`
+	URL  string   `db:"url"`
+	Tags []string `db:"tags"`
+}
+
+// Recurring reports whether the window repeats
+func (w *Window) Recurring() bool {
+	return w.Recurrence != ""
+}
+
+// Validate checks the window's name, timing and the targets it applies to
+func (w *Window) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if w.StartsAt.IsZero() {
+		return fmt.Errorf("start is required")
+	}
+	if _, err := w.Location(); err != nil {
+		return err
+	}
+
+	if !w.Recurring() {
+		if w.EndsAt == nil || !w.EndsAt.After(w.StartsAt) {
+			return fmt.Errorf("end must be after start")
+		}
+	} else {
+		if _, _, err := w.schedule(); err != nil {
+			return fmt.Errorf("invalid recurrence: %v", err)
+		}
+		if w.Duration < time.Minute || w.Duration > MaxDuration {
+			return fmt.Errorf("duration must be between 1 minute and %s", MaxDuration)
+		}
+		if w.EndsAt != nil && !w.EndsAt.After(w.StartsAt) {
+			return fmt.Errorf("end must be after start")
+		}
+	}
+
+	if len(w.TargetIDs) == 0 && len(w.Tags) == 0 {
+		return fmt.Errorf("at least one target or tag is required")
+	}
+	return nil
+}
+
+// Location returns the window's timezone, defaulting to UTC
+func (w *Window) Location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", w.Timezone)
+	}
+	return loc, nil
+}
+
+func (w *Window) schedule() (schedule, *time.Location, error) {
+	loc, err := w.Location()
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := parseRecurrence(w.Recurrence, w.StartsAt.In(loc))
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, loc, nil
+}
+
+// Covers reports whether the window applies to the target with the given tags
+func (w *Window) Covers(targetID int, tags []string) bool {
+	if slices.Contains(w.TargetIDs, targetID) {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(w.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrence returns the period of the window that is open at t
+func (w *Window) Occurrence(t time.Time) (start, end time.Time, ok bool) {
+	if t.Before(w.StartsAt) {
+		return time.Time{}, time.Time{}, false
+	}
+	if !w.Recurring() {
+		if w.EndsAt == nil || !t.Before(*w.EndsAt) {
+			return time.Time{}, time.Time{}, false
+		}
+		return w.StartsAt, *w.EndsAt, true
+	}
+
+	s, loc, err := w.schedule()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	// Only an occurrence that started less than a duration ago is still open
+	start, ok = lastStart(s, t, t.Add(-w.Duration), loc)
+	if !ok || !w.occurs(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.Add(w.Duration), true
+}
+
+// occurs reports whether a recurring window opens at a start of its schedule
+func (w *Window) occurs(start time.Time) bool {
+	return !start.Before(w.StartsAt) && (w.EndsAt == nil || start.Before(*w.EndsAt))
+}
+
+// ActiveAt reports whether the window is open at t
+func (w *Window) ActiveAt(t time.Time) bool {
+	_, _, ok := w.Occurrence(t)
+	return ok
+}
+
+// Next returns the period of the window that is open at t, or else the next
+// one to open, looking up to a year ahead
+func (w *Window) Next(t time.Time) (start, end time.Time, ok bool) {
+	if start, end, ok := w.Occurrence(t); ok {
+		return start, end, true
+	}
+	if !w.Recurring() {
+		if t.Before(w.StartsAt) && w.EndsAt != nil {
+			return w.StartsAt, *w.EndsAt, true
+		}
+		return time.Time{}, time.Time{}, false
+	}
+
+	s, loc, err := w.schedule()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	from := t
+	if from.Before(w.StartsAt) {
+		from = w.StartsAt
+	}
+	// Schedules open on whole minutes, so start from the next one
+	if rounded := from.Truncate(time.Minute); rounded.Equal(from) {
+		from = rounded
+	} else {
+		from = rounded.Add(time.Minute)
+	}
+
+	start, ok = nextStart(s, from, t.Add(lookahead), loc)
+	if !ok || !w.occurs(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.Add(w.Duration), true
+}
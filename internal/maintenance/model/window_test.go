@@ -0,0 +1,131 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func oneOffWindow() *Window {
+	endsAt := time.Date(2025, 5, 4, 4, 0, 0, 0, time.UTC)
+	return &Window{
+		ID:        1,
+		UserID:    1,
+		Name:      "Database upgrade",
+		StartsAt:  time.Date(2025, 5, 4, 2, 0, 0, 0, time.UTC),
+		EndsAt:    &endsAt,
+		TargetIDs: []int{1},
+	}
+}
+
+func recurringWindow() *Window {
+	return &Window{
+		ID:         2,
+		UserID:     1,
+		Name:       "Weekly deploy",
+		StartsAt:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Recurrence: "0 2 * * SUN",
+		Duration:   time.Hour,
+		Timezone:   "Europe/Berlin",
+		Tags:       []string{"api"},
+	}
+}
+
+func TestWindow_Validate(t *testing.T) {
+	assert.NoError(t, oneOffWindow().Validate())
+	assert.NoError(t, recurringWindow().Validate())
+
+	invalid := []func(w *Window){
+		func(w *Window) { w.Name = " " },
+		func(w *Window) { w.StartsAt = time.Time{} },
+		func(w *Window) { w.Timezone = "Mars/Olympus" },
+		func(w *Window) { w.EndsAt = nil },
+		func(w *Window) { endsAt := w.StartsAt; w.EndsAt = &endsAt },
+		func(w *Window) { w.TargetIDs = nil },
+		func(w *Window) { w.Recurrence = "every sunday"; w.Duration = time.Hour },
+		func(w *Window) { w.Recurrence = "0 2 * * SUN" },
+		func(w *Window) { w.Recurrence = "0 2 * * SUN"; w.Duration = 8 * 24 * time.Hour },
+	}
+	for _, mutate := range invalid {
+		w := oneOffWindow()
+		mutate(w)
+		assert.Error(t, w.Validate())
+	}
+}
+
+func TestWindow_Covers(t *testing.T) {
+	w := &Window{TargetIDs: []int{1}, Tags: []string{"api"}}
+
+	assert.True(t, w.Covers(1, nil))
+	assert.True(t, w.Covers(2, []string{"web", "api"}))
+	assert.False(t, w.Covers(2, []string{"web"}))
+}
+
+func TestWindow_OneOffOccurrence(t *testing.T) {
+	w := oneOffWindow()
+
+	assert.False(t, w.ActiveAt(w.StartsAt.Add(-time.Second)))
+	assert.True(t, w.ActiveAt(w.StartsAt))
+	assert.True(t, w.ActiveAt(w.EndsAt.Add(-time.Second)))
+	assert.False(t, w.ActiveAt(*w.EndsAt))
+
+	start, end, ok := w.Next(w.StartsAt.Add(-time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, w.StartsAt, start)
+	assert.Equal(t, *w.EndsAt, end)
+
+	_, _, ok = w.Next(*w.EndsAt)
+	assert.False(t, ok)
+}
+
+func TestWindow_RecurringOccurrence(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	w := recurringWindow()
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"opens on sunday", time.Date(2025, 5, 4, 2, 0, 0, 0, berlin), true},
+		{"still open", time.Date(2025, 5, 4, 2, 59, 59, 0, berlin), true},
+		{"closed after the duration", time.Date(2025, 5, 4, 3, 0, 0, 0, berlin), false},
+		{"closed before it opens", time.Date(2025, 5, 4, 1, 59, 0, 0, berlin), false},
+		{"closed on other days", time.Date(2025, 5, 5, 2, 30, 0, 0, berlin), false},
+		{"timezone is respected", time.Date(2025, 5, 4, 2, 30, 0, 0, time.UTC), false},
+		{"before the window starts", time.Date(2025, 4, 27, 2, 30, 0, 0, berlin), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, w.ActiveAt(tt.at))
+		})
+	}
+}
+
+func TestWindow_RecurringEndsAt(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	w := recurringWindow()
+	endsAt := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	w.EndsAt = &endsAt
+
+	assert.True(t, w.ActiveAt(time.Date(2025, 5, 4, 2, 30, 0, 0, berlin)))
+	assert.False(t, w.ActiveAt(time.Date(2025, 5, 11, 2, 30, 0, 0, berlin)))
+
+	_, _, ok := w.Next(time.Date(2025, 5, 5, 0, 0, 0, 0, berlin))
+	assert.False(t, ok)
+}
+
+func TestWindow_RecurringNext(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	w := recurringWindow()
+
+	start, end, ok := w.Next(time.Date(2025, 5, 4, 2, 30, 0, 0, berlin))
+	assert.True(t, ok, "an open occurrence is the next one")
+	assert.Equal(t, time.Date(2025, 5, 4, 2, 0, 0, 0, berlin), start)
+	assert.Equal(t, time.Date(2025, 5, 4, 3, 0, 0, 0, berlin), end)
+
+	start, _, ok = w.Next(time.Date(2025, 5, 4, 3, 0, 30, 0, berlin))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 5, 11, 2, 0, 0, 0, berlin), start)
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+)
+
+var ErrWindowNotFound = errors.New("maintenance window not found")
+
+type WindowRepositoryInterface interface {
+	Create(ctx context.Context, window *model.Window) (*model.Window, error)
+	Get(ctx context.Context, id int) (*model.Window, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Window, error)
+	GetByTargetID(ctx context.Context, targetID int) ([]*model.Window, error)
+	Update(ctx context.Context, window *model.Window) (*model.Window, error)
+	Delete(ctx context.Context, id int) error
+	GetTargets(ctx context.Context, userID int) ([]model.Target, error)
+}
+
+var _ WindowRepositoryInterface = (*WindowRepository)(nil)
+
+// WindowRepository handles database operations for maintenance windows, and
+// reads the targets they can be attached to
+type WindowRepository struct {
+	db database.Querier
+}
+
+// NewWindowRepository creates a new maintenance window repository
+func NewWindowRepository(db database.Querier) *WindowRepository {
+	return &WindowRepository{db: db}
+}
+
+const windowColumns = `id, user_id, name, starts_at, ends_at, recurrence, duration_seconds, timezone, target_ids, tags`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWindow(row rowScanner) (*model.Window, error) {
+	window := &model.Window{}
+	var endsAt sql.NullTime
+	var seconds int64
+	var targetIDs pq.Int64Array
+	err := row.Scan(
+		&window.ID,
+		&window.UserID,
+		&window.Name,
+		&window.StartsAt,
+		&endsAt,
+		&window.Recurrence,
+		&seconds,
+		&window.Timezone,
+		&targetIDs,
+		pq.Array(&window.Tags),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if endsAt.Valid {
+		window.EndsAt = &endsAt.Time
+	}
+	window.Duration = time.Duration(seconds) * time.Second
+	for _, id := range targetIDs {
+		window.TargetIDs = append(window.TargetIDs, int(id))
+	}
+	return window, nil
+}
+
+// columnValues returns the stored values of a window, times in UTC
+func columnValues(window *model.Window) []any {
+	var endsAt *time.Time
+	if window.EndsAt != nil {
+		utc := window.EndsAt.UTC()
+		endsAt = &utc
+	}
+	tags := window.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	targetIDs := window.TargetIDs
+	if targetIDs == nil {
+		targetIDs = []int{}
+	}
+	return []any{
+		window.Name,
+		window.StartsAt.UTC(),
+		endsAt,
+		window.Recurrence,
+		int64(window.Duration / time.Second),
+		window.Timezone,
+		pq.Array(targetIDs),
+		pq.Array(tags),
+	}
+}
+
+func (r *WindowRepository) Create(ctx context.Context, window *model.Window) (*model.Window, error) {
+	query := `
+		INSERT INTO maintenance_window (user_id, name, starts_at, ends_at, recurrence, duration_seconds, timezone, target_ids, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	newWindow := *window
+	args := append([]any{window.UserID}, columnValues(window)...)
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&newWindow.ID); err != nil {
+		return nil, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	return &newWindow, nil
+}
+
+func (r *WindowRepository) Get(ctx context.Context, id int) (*model.Window, error) {
+	query := `SELECT ` + windowColumns + ` FROM maintenance_window WHERE id = $1`
+
+	window, err := scanWindow(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrWindowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+	return window, nil
+}
+
+func (r *WindowRepository) query(ctx context.Context, query string, args ...any) ([]*model.Window, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*model.Window
+	for rows.Next() {
+		window, err := scanWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+// GetByUserID retrieves all windows of a user, soonest first
+func (r *WindowRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Window, error) {
+	query := `SELECT ` + windowColumns + ` FROM maintenance_window WHERE user_id = $1 ORDER BY starts_at, id`
+	return r.query(ctx, query, userID)
+}
+
+// GetByTargetID retrieves the windows of the target's owner that name the
+// target or one of its tags
+func (r *WindowRepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.Window, error) {
+	query := `
+		SELECT w.id, w.user_id, w.name, w.starts_at, w.ends_at, w.recurrence,
+			w.duration_seconds, w.timezone, w.target_ids, w.tags
+		FROM maintenance_window w
+		JOIN target t ON t.user_id = w.user_id
+		WHERE t.id = $1 AND (t.id = ANY(w.target_ids) OR w.tags && t.tags)
+		ORDER BY w.id
+	`
+	return r.query(ctx, query, targetID)
+}
+
+func (r *WindowRepository) Update(ctx context.Context, window *model.Window) (*model.Window, error) {
+	query := `
+		UPDATE maintenance_window
+		SET name = $1, starts_at = $2, ends_at = $3, recurrence = $4, duration_seconds = $5,
+			timezone = $6, target_ids = $7, tags = $8
+		WHERE id = $9
+	`
+
+	args := append(columnValues(window), window.ID)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil, ErrWindowNotFound
+	}
+
+	updated := *window
+	return &updated, nil
+}
+
+func (r *WindowRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_window WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrWindowNotFound
+	}
+	return nil
+}
+
+// GetTargets returns the targets of a user with their tags
+func (r *WindowRepository) GetTargets(ctx context.Context, userID int) ([]model.Target, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, url, tags FROM target WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []model.Target
+	for rows.Next() {
+		var target model.Target
+		if err := rows.Scan(&target.ID, &target.URL, pq.Array(&target.Tags)); err != nil {
+			return nil, fmt.Errorf("failed to scan target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating targets: %w", err)
+	}
+	return targets, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewWindowRepository(tx)
+	targets := monitorRepo.NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	other, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "other@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	create := func(userID int, url string, tags []string) monitorModel.UserTarget {
+		target, err := targets.Create(context.Background(), monitorModel.UserTarget{
+			UserID: userID,
+			Tags:   tags,
+			Target: &core.Target{URL: url, Status: "up", Enabled: true, Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+		})
+		assert.NoError(t, err)
+		return target
+	}
+	api := create(user.ID, "https://api.example.org", []string{"api"})
+	web := create(user.ID, "https://example.org", []string{"web"})
+	foreign := create(other.ID, "https://other.example.org", []string{"api"})
+
+	endsAt := time.Date(2025, 5, 4, 4, 0, 0, 0, time.UTC)
+	oneOff, err := repo.Create(context.Background(), &model.Window{
+		UserID:    user.ID,
+		Name:      "Database upgrade",
+		StartsAt:  time.Date(2025, 5, 4, 2, 0, 0, 0, time.UTC),
+		EndsAt:    &endsAt,
+		Timezone:  "UTC",
+		TargetIDs: []int{web.ID},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, oneOff.ID)
+
+	recurring, err := repo.Create(context.Background(), &model.Window{
+		UserID:     user.ID,
+		Name:       "Weekly deploy",
+		StartsAt:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Recurrence: "0 2 * * SUN",
+		Duration:   time.Hour,
+		Timezone:   "Europe/Berlin",
+		Tags:       []string{"api"},
+	})
+	assert.NoError(t, err)
+
+	stored, err := repo.Get(context.Background(), oneOff.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Database upgrade", stored.Name)
+	assert.True(t, endsAt.Equal(*stored.EndsAt))
+	assert.Equal(t, []int{web.ID}, stored.TargetIDs)
+
+	stored, err = repo.Get(context.Background(), recurring.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, stored.EndsAt)
+	assert.Equal(t, time.Hour, stored.Duration)
+	assert.Equal(t, []string{"api"}, stored.Tags)
+
+	windows, err := repo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, windows, 2)
+
+	windows, err = repo.GetByTargetID(context.Background(), api.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, windows, 1) {
+		assert.Equal(t, recurring.ID, windows[0].ID)
+	}
+	windows, err = repo.GetByTargetID(context.Background(), web.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, windows, 1) {
+		assert.Equal(t, oneOff.ID, windows[0].ID)
+	}
+	windows, err = repo.GetByTargetID(context.Background(), foreign.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, windows, "windows only apply to their owner's targets")
+
+	stored.Name = "Deploy"
+	stored.TargetIDs = []int{web.ID}
+	stored.Tags = nil
+	_, err = repo.Update(context.Background(), stored)
+	assert.NoError(t, err)
+	windows, err = repo.GetByTargetID(context.Background(), api.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	ownTargets, err := repo.GetTargets(context.Background(), user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, ownTargets, 2) {
+		assert.Equal(t, []string{"api"}, ownTargets[0].Tags)
+	}
+
+	assert.NoError(t, repo.Delete(context.Background(), oneOff.ID))
+	_, err = repo.Get(context.Background(), oneOff.ID)
+	assert.ErrorIs(t, err, ErrWindowNotFound)
+	assert.ErrorIs(t, repo.Delete(context.Background(), oneOff.ID), ErrWindowNotFound)
+	_, err = repo.Update(context.Background(), &model.Window{ID: oneOff.ID, Name: "gone"})
+	assert.ErrorIs(t, err, ErrWindowNotFound)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/repository"
+)
+
+// Common errors returned by the maintenance window service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a window they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to maintenance window")
+	// ErrWindowNotFound is returned when the requested window does not exist.
+	ErrWindowNotFound = errors.New("maintenance window not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
+type WindowServiceInterface interface {
+	Create(ctx context.Context, window *model.Window, userID int) error
+	Get(ctx context.Context, id int, userID int) (*model.Window, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Window, error)
+	Update(ctx context.Context, window *model.Window, userID int) (*model.Window, error)
+	Delete(ctx context.Context, id int, userID int) error
+	GetTargets(ctx context.Context, userID int) ([]model.Target, error)
+	InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error)
+}
+
+var _ WindowServiceInterface = (*WindowService)(nil)
+
+type WindowService struct {
+	repo repository.WindowRepositoryInterface
+}
+
+func NewWindowService(repo repository.WindowRepositoryInterface) *WindowService {
+	return &WindowService{repo: repo}
+}
+
+// prepare normalizes a window and checks it can be saved: it must be valid
+// and may only name the user's own targets
+func (s *WindowService) prepare(ctx context.Context, window *model.Window, userID int) error {
+	window.Name = strings.TrimSpace(window.Name)
+	window.Recurrence = strings.TrimSpace(window.Recurrence)
+	window.Timezone = strings.TrimSpace(window.Timezone)
+	if !window.Recurring() {
+		window.Duration = 0
+	}
+
+	if err := window.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	targets, err := s.repo.GetTargets(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+	owned := make(map[int]bool, len(targets))
+	for _, target := range targets {
+		owned[target.ID] = true
+	}
+	for _, id := range window.TargetIDs {
+		if !owned[id] {
+			return fmt.Errorf("%w: target %d not found", ErrInvalidInput, id)
+		}
+	}
+	return nil
+}
+
+func (s *WindowService) Create(ctx context.Context, window *model.Window, userID int) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+	window.ID = 0
+	window.UserID = userID
+
+	if err := s.prepare(ctx, window, userID); err != nil {
+		return err
+	}
+
+	newWindow, err := s.repo.Create(ctx, window)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	window.ID = newWindow.ID
+	return nil
+}
+
+// Get retrieves a window after verifying the user owns it
+func (s *WindowService) Get(ctx context.Context, id int, userID int) (*model.Window, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	window, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWindowNotFound) {
+			return nil, fmt.Errorf("%w: window with id %d not found", ErrWindowNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch maintenance window: %w", err)
+	}
+
+	if window.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return window, nil
+}
+
+func (s *WindowService) GetByUserID(ctx context.Context, userID int) ([]*model.Window, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	windows, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+func (s *WindowService) Update(ctx context.Context, window *model.Window, userID int) (*model.Window, error) {
+	if _, err := s.Get(ctx, window.ID, userID); err != nil {
+		return nil, err
+	}
+	window.UserID = userID
+
+	if err := s.prepare(ctx, window, userID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, window)
+	if err != nil {
+		if errors.Is(err, repository.ErrWindowNotFound) {
+			return nil, fmt.Errorf("%w: window with id %d not found", ErrWindowNotFound, window.ID)
+		}
+		return nil, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *WindowService) Delete(ctx context.Context, id int, userID int) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrWindowNotFound) {
+			return fmt.Errorf("%w: window with id %d not found", ErrWindowNotFound, id)
+		}
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	return nil
+}
+
+// GetTargets lists the targets a user can attach windows to
+func (s *WindowService) GetTargets(ctx context.Context, userID int) ([]model.Target, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	targets, err := s.repo.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch targets: %w", err)
+	}
+	return targets, nil
+}
+
+// InMaintenance reports whether one of the windows attached to a target, by
+// its ID or one of its tags, is open at the given time
+func (s *WindowService) InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error) {
+	if targetID <= 0 {
+		return false, fmt.Errorf("%w: invalid targetID", ErrInvalidInput)
+	}
+
+	windows, err := s.repo.GetByTargetID(ctx, targetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch maintenance windows: %w", err)
+	}
+	for _, window := range windows {
+		if window.ActiveAt(at) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
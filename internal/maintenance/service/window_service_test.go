@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/model"
+	"github.com/shuvo-paul/uptimebot/internal/maintenance/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWindowRepository struct {
+	createFunc        func(window *model.Window) (*model.Window, error)
+	getFunc           func(id int) (*model.Window, error)
+	getByUserIDFunc   func(userID int) ([]*model.Window, error)
+	getByTargetIDFunc func(targetID int) ([]*model.Window, error)
+	updateFunc        func(window *model.Window) (*model.Window, error)
+	deleteFunc        func(id int) error
+	getTargetsFunc    func(userID int) ([]model.Target, error)
+}
+
+func (m *mockWindowRepository) Create(ctx context.Context, window *model.Window) (*model.Window, error) {
+	return m.createFunc(window)
+}
+
+func (m *mockWindowRepository) Get(ctx context.Context, id int) (*model.Window, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockWindowRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Window, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockWindowRepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.Window, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *mockWindowRepository) Update(ctx context.Context, window *model.Window) (*model.Window, error) {
+	return m.updateFunc(window)
+}
+
+func (m *mockWindowRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockWindowRepository) GetTargets(ctx context.Context, userID int) ([]model.Target, error) {
+	return m.getTargetsFunc(userID)
+}
+
+func ownTargets(userID int) ([]model.Target, error) {
+	return []model.Target{{ID: 1, URL: "https://example.org", Tags: []string{"web"}}}, nil
+}
+
+func testWindow() *model.Window {
+	endsAt := time.Date(2025, 5, 4, 4, 0, 0, 0, time.UTC)
+	return &model.Window{
+		ID:        1,
+		UserID:    1,
+		Name:      "Database upgrade",
+		StartsAt:  time.Date(2025, 5, 4, 2, 0, 0, 0, time.UTC),
+		EndsAt:    &endsAt,
+		TargetIDs: []int{1},
+	}
+}
+
+func TestWindowService_Create(t *testing.T) {
+	var created *model.Window
+	mockRepo := &mockWindowRepository{
+		getTargetsFunc: ownTargets,
+		createFunc: func(window *model.Window) (*model.Window, error) {
+			created = window
+			stored := *window
+			stored.ID = 7
+			return &stored, nil
+		},
+	}
+	service := NewWindowService(mockRepo)
+
+	window := testWindow()
+	window.Name = "  Database upgrade "
+	window.Duration = time.Hour
+	assert.NoError(t, service.Create(context.Background(), window, 1))
+	assert.Equal(t, 7, window.ID)
+	assert.Equal(t, "Database upgrade", created.Name)
+	assert.Zero(t, created.Duration, "one-off windows have no duration")
+
+	t.Run("invalid window", func(t *testing.T) {
+		window := testWindow()
+		window.EndsAt = nil
+		assert.ErrorIs(t, service.Create(context.Background(), window, 1), ErrInvalidInput)
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		window := testWindow()
+		window.TargetIDs = []int{2}
+		assert.ErrorIs(t, service.Create(context.Background(), window, 1), ErrInvalidInput)
+	})
+
+	t.Run("invalid user", func(t *testing.T) {
+		assert.ErrorIs(t, service.Create(context.Background(), testWindow(), 0), ErrInvalidInput)
+	})
+}
+
+func TestWindowService_Get(t *testing.T) {
+	mockRepo := &mockWindowRepository{
+		getFunc: func(id int) (*model.Window, error) {
+			if id != 1 {
+				return nil, repository.ErrWindowNotFound
+			}
+			return testWindow(), nil
+		},
+	}
+	service := NewWindowService(mockRepo)
+
+	window, err := service.Get(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Database upgrade", window.Name)
+
+	_, err = service.Get(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.Get(context.Background(), 2, 1)
+	assert.ErrorIs(t, err, ErrWindowNotFound)
+
+	_, err = service.Get(context.Background(), 0, 1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestWindowService_Update(t *testing.T) {
+	var updated *model.Window
+	mockRepo := &mockWindowRepository{
+		getFunc:        func(id int) (*model.Window, error) { return testWindow(), nil },
+		getTargetsFunc: ownTargets,
+		updateFunc: func(window *model.Window) (*model.Window, error) {
+			updated = window
+			return window, nil
+		},
+	}
+	service := NewWindowService(mockRepo)
+
+	window := testWindow()
+	window.Recurrence = "FREQ=WEEKLY;BYDAY=SU"
+	window.Duration = time.Hour
+	window.EndsAt = nil
+	_, err := service.Update(context.Background(), window, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, updated.Duration)
+
+	_, err = service.Update(context.Background(), testWindow(), 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestWindowService_Delete(t *testing.T) {
+	deleted := 0
+	mockRepo := &mockWindowRepository{
+		getFunc:    func(id int) (*model.Window, error) { return testWindow(), nil },
+		deleteFunc: func(id int) error { deleted = id; return nil },
+	}
+	service := NewWindowService(mockRepo)
+
+	assert.ErrorIs(t, service.Delete(context.Background(), 1, 2), ErrUnauthorized)
+	assert.Zero(t, deleted)
+
+	assert.NoError(t, service.Delete(context.Background(), 1, 1))
+	assert.Equal(t, 1, deleted)
+}
+
+func TestWindowService_InMaintenance(t *testing.T) {
+	mockRepo := &mockWindowRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Window, error) {
+			return []*model.Window{testWindow()}, nil
+		},
+	}
+	service := NewWindowService(mockRepo)
+
+	inMaintenance, err := service.InMaintenance(context.Background(), 1, time.Date(2025, 5, 4, 3, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, inMaintenance)
+
+	inMaintenance, err = service.InMaintenance(context.Background(), 1, time.Date(2025, 5, 4, 4, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, inMaintenance)
+
+	_, err = service.InMaintenance(context.Background(), 0, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
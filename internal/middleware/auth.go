@@ -15,13 +15,13 @@ func RequireAuth(next http.Handler, sessionService service.SessionService, userS
 			return
 		}
 
-		session, err := sessionService.ValidateSession(cookie.Value)
+		session, err := sessionService.ValidateSession(r.Context(), cookie.Value)
 		if err != nil || session == nil {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
 
-		user, err := userService.GetUserByID(session.UserID)
+		user, err := userService.GetUserByID(r.Context(), session.UserID)
 		if err != nil {
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+)
+
+var (
+	httpRequestsTotal = metrics.NewCounter("uptimebot_http_requests_total",
+		"HTTP requests served, by route pattern, method and status code.", "route", "method", "code")
+	httpRequestDuration = metrics.NewHistogram("uptimebot_http_request_duration_seconds",
+		"Time taken to serve HTTP requests, by route pattern.", nil, "route")
+)
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Metrics counts and times requests. Requests are labeled with the pattern
+// of the mux route that served them rather than their path, which keeps the
+// number of series bounded. Routes of a mux served through Mount keep their
+// full path.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		r, rt := withRoute(r)
+
+		next.ServeHTTP(recorder, r)
+
+		route := rt.resolve(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		httpRequestsTotal.Inc(route, r.Method, strconv.Itoa(status))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), route)
+	})
+}
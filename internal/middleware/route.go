@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type routeKey struct{}
+
+// route holds the pattern of the innermost mux that served a request.
+// http.StripPrefix hands a copy of the request to a mounted mux, so the
+// pattern that mux sets never reaches the middlewares around it; Mount
+// records it here instead.
+type route struct {
+	pattern string
+}
+
+// withRoute returns the request with a route holder in its context, reusing
+// the one an outer middleware already attached
+func withRoute(r *http.Request) (*http.Request, *route) {
+	if rt, ok := r.Context().Value(routeKey{}).(*route); ok {
+		return r, rt
+	}
+	rt := &route{}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, rt)), rt
+}
+
+// resolve returns the pattern recorded by a mounted mux, falling back to the
+// pattern of the mux the request itself went through
+func (rt *route) resolve(r *http.Request) string {
+	if rt.pattern != "" {
+		return rt.pattern
+	}
+	return r.Pattern
+}
+
+// Mount serves a mux under a path prefix, like http.StripPrefix, and records
+// the route it matched with the prefix put back, so "GET /targets/{id}"
+// mounted at "/api/v1" is reported as "GET /api/v1/targets/{id}"
+func Mount(prefix string, mux *http.ServeMux) http.Handler {
+	return http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+
+		rt, ok := r.Context().Value(routeKey{}).(*route)
+		if !ok || rt.pattern != "" || r.Pattern == "" {
+			return
+		}
+		rt.pattern = prefixPattern(prefix, r.Pattern)
+	}))
+}
+
+// prefixPattern puts a stripped path prefix back into a route pattern
+func prefixPattern(prefix, pattern string) string {
+	i := strings.Index(pattern, "/")
+	if i < 0 {
+		return pattern
+	}
+	return pattern[:i] + prefix + pattern[i:]
+}
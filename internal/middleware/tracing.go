@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/shuvo-paul/uptimebot/internal/middleware"
+
+// Tracing starts a server span for each request, continuing the trace of the
+// caller when it sent one. It goes first in a stack so the span covers the
+// other middlewares; TraceRoute, last in the stack, names the span after the
+// route that served the request.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// TraceRoute names the request's span after the mux route pattern that
+// served it. The pattern is only set on the request the mux receives, so
+// this has to be the last middleware of a stack; routes of a mux served
+// through Mount are named with their full path.
+func TraceRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, rt := withRoute(r)
+		next.ServeHTTP(w, r)
+
+		pattern := rt.resolve(r)
+		if pattern == "" {
+			return
+		}
+		name := pattern
+		if !strings.HasPrefix(name, r.Method+" ") {
+			name = r.Method + " " + name
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(name)
+		span.SetAttributes(semconv.HTTPRoute(pattern))
+	})
+}
@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+)
+
+var (
+	targetUp = metrics.NewGauge("uptimebot_target_up",
+		"Whether the target is up (1) or not (0), by its confirmed status.", "target_id")
+	checkDuration = metrics.NewHistogram("uptimebot_check_duration_seconds",
+		"Duration of checks that received a response or failed to connect.", nil, "target_id")
+	checkTotal = metrics.NewCounter("uptimebot_check_total",
+		"Checks performed, by result: up, down, error, maintenance, unreachable-dependency or timeout.", "result")
+	schedulerLag = metrics.NewHistogram("uptimebot_scheduler_lag_seconds",
+		"Delay between when a check was due and when it started.",
+		[]float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10})
+)
+
+// observeCheck records the outcome of a check
+func observeCheck(target *Target, result Result) {
+	id := strconv.Itoa(target.ID)
+	checkTotal.Inc(result.Status)
+	checkDuration.Observe(result.Duration.Seconds(), id)
+}
+
+// observeStatus records the confirmed status of a target
+func observeStatus(target *Target) {
+	up := 0.0
+	if target.Status == statusUp {
+		up = 1
+	}
+	targetUp.Set(up, strconv.Itoa(target.ID))
+}
+
+// forgetTarget drops the series of a target that is no longer monitored
+func forgetTarget(targetID int) {
+	id := strconv.Itoa(targetID)
+	targetUp.Delete(id)
+	checkDuration.Delete(id)
+}
+
+// observeTick records how late a scheduled check starts
+func observeTick(due time.Time) {
+	schedulerLag.Observe(time.Since(due).Seconds())
+}
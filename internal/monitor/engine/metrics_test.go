@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+)
+
+func scrape() string {
+	var out strings.Builder
+	metrics.Default.Write(&out)
+	return out.String()
+}
+
+func TestCheckMetrics(t *testing.T) {
+	healthy := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	target := &Target{ID: 4242, URL: ts.URL, Confirmations: 1, Client: DefaultClient}
+
+	target.Check()
+	if !strings.Contains(scrape(), `uptimebot_target_up{target_id="4242"} 1`) {
+		t.Errorf("expected the target to be reported up")
+	}
+
+	healthy = false
+	target.Check()
+	output := scrape()
+	if !strings.Contains(output, `uptimebot_target_up{target_id="4242"} 0`) {
+		t.Errorf("expected the target to be reported down")
+	}
+	if !strings.Contains(output, `uptimebot_check_duration_seconds_count{target_id="4242"} 2`) {
+		t.Errorf("expected both checks to be timed")
+	}
+	if !strings.Contains(output, `uptimebot_check_total{result="down"}`) {
+		t.Errorf("expected the failed check to be counted")
+	}
+
+	forgetTarget(target.ID)
+	if strings.Contains(scrape(), `target_id="4242"`) {
+		t.Errorf("expected the series of the target to be dropped")
+	}
+}
@@ -2,11 +2,20 @@ package monitor
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/tracing"
 )
 
 const (
@@ -14,6 +23,38 @@ const (
 	statusError  = "error"
 	statusDown   = "down"
 	statusPaused = "paused"
+	// statusMaintenance marks checks made, and targets checked, during a maintenance window
+	statusMaintenance = "maintenance"
+	// statusUnreachable marks failed checks, and targets, that cannot be reached
+	// because one of their parent targets is failing
+	statusUnreachable = "unreachable-dependency"
+)
+
+// DefaultConfirmations is the number of failed checks in a row needed
+// before a target is marked down, so a single blip does not raise an alert
+const DefaultConfirmations = 2
+
+// A target whose status changes more than DefaultFlapThreshold times within
+// DefaultFlapWindow is flapping, unless it has settings of its own. It
+// stabilizes once its status holds for a whole window.
+const (
+	DefaultFlapThreshold = 5
+	DefaultFlapWindow    = 15 * time.Minute
+)
+
+// bodySnippetLimit is how much of a failed response body is kept for notifications
+const bodySnippetLimit = 512
+
+// Error classes group failed checks by what went wrong
+const (
+	ErrorClassDNS               = "dns"
+	ErrorClassConnectionRefused = "connection_refused"
+	ErrorClassConnectionReset   = "connection_reset"
+	ErrorClassTLS               = "tls"
+	ErrorClassNetwork           = "network"
+	ErrorClassClient            = "http_4xx"
+	ErrorClassServer            = "http_5xx"
+	ErrorClassUnknown           = "unknown"
 )
 
 // ClientConfig holds HTTP client configuration
@@ -33,69 +74,301 @@ var DefaultClientConfig = ClientConfig{
 // DefaultClient provides a default HTTP client using DefaultClientConfig
 var DefaultClient = &http.Client{
 	Timeout: DefaultClientConfig.Timeout,
-	Transport: &http.Transport{
+	Transport: tracing.Transport(&http.Transport{
 		MaxIdleConns:    DefaultClientConfig.MaxIdleConns,
 		IdleConnTimeout: DefaultClientConfig.IdleConnTimeout,
-	},
+	}),
+}
+
+// StatusUpdateCallback is called when a target moves to another status. Like
+// the other check callbacks, it gets the context of the check, so the work it
+// does is traced as part of the check.
+type StatusUpdateCallback func(context.Context, *Target, string) error
+
+// CheckCallback is called with the result of every completed check
+type CheckCallback func(context.Context, *Target, Result)
+
+// FlapCallback is called when a target starts flapping and when it stabilizes
+type FlapCallback func(ctx context.Context, target *Target, flapping bool) error
+
+// MaintenanceCallback reports whether a target is in a maintenance window at the given time
+type MaintenanceCallback func(ctx context.Context, target *Target, at time.Time) bool
+
+// DependencyCallback returns a parent of the target that is failing, nil when
+// none of them is
+type DependencyCallback func(target *Target) *Target
+
+// Result describes the outcome of a single check
+type Result struct {
+	Status     string
+	StatusCode int    // zero when no response was received
+	Err        error  // nil when a response was received
+	Body       string // start of the response body of a failed HTTP check
+	Duration   time.Duration
+	CheckedAt  time.Time
 }
 
-type StatusUpdateCallback func(*Target, string) error
+// ErrorClass names the kind of failure behind a result, empty when the check passed
+func (r Result) ErrorClass() string {
+	switch {
+	case r.Err != nil:
+		return classifyError(r.Err)
+	case r.StatusCode >= 500:
+		return ErrorClassServer
+	case r.StatusCode >= 400:
+		return ErrorClassClient
+	default:
+		return ""
+	}
+}
+
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+	var opErr *net.OpError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorClassConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorClassConnectionReset
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr), errors.As(err, &recordErr):
+		return ErrorClassTLS
+	case errors.As(err, &opErr):
+		return ErrorClassNetwork
+	default:
+		return ErrorClassUnknown
+	}
+}
 
 type Target struct {
 	ID              int
 	URL             string
 	Status          string
 	Enabled         bool
+	PausedUntil     *time.Time // checks are skipped until then, nil when not paused
+	ParentIDs       []int      // targets this one is reached through, such as its load balancer
 	Interval        time.Duration
 	StatusChangedAt time.Time
+	Confirmations   int           // failed checks in a row before the status changes
+	Failures        int           // failed checks in a row so far
+	FirstFailure    *Result       // first of the failed checks in a row, nil while checks pass
+	LastResult      Result        // outcome of the most recent check
+	FlapThreshold   int           // status changes within FlapWindow before the target is flapping
+	FlapWindow      time.Duration // sliding window status changes are counted over
+	Flapping        bool          // status changes too often for each of them to be notified
+	FlapChanges     int           // status changes since the target started flapping
+	changes         []time.Time   // status changes within the last FlapWindow
 	mu              sync.RWMutex
 	cancelFunc      context.CancelFunc
 	Client          *http.Client
 	OnStatusUpdate  StatusUpdateCallback
+	OnCheck         CheckCallback
+	OnFlap          FlapCallback
+	InMaintenance   MaintenanceCallback
+	FailingParent   DependencyCallback
 }
 
-func (s *Target) Check() error {
+func (s *Target) Check() (err error) {
 	defer func(startStatus string) {
 		slog.Info("Target check completed", "URL", s.URL, "fromStatus", startStatus, "toStatus", s.Status)
 	}(s.Status)
 
-	r, err := s.Client.Get(s.URL)
+	var result Result
+	ctx, span := startCheckSpan(s)
+	defer func() { endCheckSpan(span, result, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid request: %v", err)
+	}
+
+	start := time.Now()
+	r, err := s.Client.Do(req)
+	result = Result{Duration: time.Since(start), CheckedAt: start}
 	if err != nil {
 		// Check if the error is a timeout error
 		if timeoutErr, ok := err.(interface{ Timeout() bool }); ok && timeoutErr.Timeout() {
 			// Log the timeout but don't update status or trigger notification
 			slog.Info("Target check timeout", "URL", s.URL, "error", err)
+			checkTotal.Inc("timeout")
 			return fmt.Errorf("timeout error: %v", err)
 		}
 		// For non-timeout errors, update status and trigger notification
-		s.updateStatus(statusError)
+		result.Status = statusError
+		result.Err = err
+		s.record(ctx, result)
 		return fmt.Errorf("connection error: %v", err)
 	}
 
 	defer r.Body.Close()
 
+	result.StatusCode = r.StatusCode
 	if r.StatusCode >= 400 {
-		s.updateStatus(statusDown)
+		result.Status = statusDown
+		result.Body = readSnippet(r.Body)
+		s.record(ctx, result)
 		return fmt.Errorf("HTTP error: %d", r.StatusCode)
 	}
 
-	s.updateStatus(statusUp)
+	result.Status = statusUp
+	s.record(ctx, result)
 
 	return nil
 }
 
-func (s *Target) updateStatus(status string) {
+// readSnippet reads the start of a response body as text
+func readSnippet(body io.Reader) string {
+	snippet, _ := io.ReadAll(io.LimitReader(body, bodySnippetLimit))
+	return strings.TrimSpace(strings.ToValidUTF8(string(snippet), ""))
+}
+
+// record reports the result of a check and moves the target to its status.
+// A failure only changes the status once enough checks failed in a row. The
+// failures are kept until the target is up again, so the recovery can report them.
+func (s *Target) record(ctx context.Context, result Result) {
+	if s.InMaintenance != nil && s.InMaintenance(ctx, s, result.CheckedAt) {
+		s.recordExplained(ctx, result, statusMaintenance)
+		return
+	}
+	if result.Status != statusUp && s.FailingParent != nil && s.FailingParent(s) != nil {
+		s.recordExplained(ctx, result, statusUnreachable)
+		return
+	}
+
+	s.LastResult = result
+	if result.Status != statusUp {
+		s.Failures++
+		if s.FirstFailure == nil {
+			s.FirstFailure = &result
+		}
+	}
+
+	observeCheck(s, result)
+	if s.OnCheck != nil {
+		s.OnCheck(ctx, s, result)
+	}
+
+	if result.Status != statusUp && s.Failures < s.Confirmations {
+		s.settle(ctx, result.CheckedAt)
+		return
+	}
+	s.updateStatus(ctx, result.Status)
+	observeStatus(s)
+
+	if result.Status == statusUp {
+		s.Failures = 0
+		s.FirstFailure = nil
+	}
+	s.settle(ctx, result.CheckedAt)
+}
+
+// recordExplained reports a check whose outcome is explained by something
+// else: a maintenance window, or a failing parent target. The result keeps its
+// response, but none of it counts as a failure, so it neither confirms an
+// outage nor carries over into one.
+func (s *Target) recordExplained(ctx context.Context, result Result, status string) {
+	result.Status = status
+	s.LastResult = result
+	s.Failures = 0
+	s.FirstFailure = nil
+
+	observeCheck(s, result)
+	if s.OnCheck != nil {
+		s.OnCheck(ctx, s, result)
+	}
+
+	s.updateStatus(ctx, status)
+	observeStatus(s)
+}
+
+func (s *Target) updateStatus(ctx context.Context, status string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.Status != status {
+		// Entering and leaving maintenance, or an unreachable parent, follows
+		// from something else, so it does not count towards flapping
+		explained := explainedStatus(s.Status) || explainedStatus(status)
 		s.Status = status
 		s.StatusChangedAt = time.Now()
+		startedFlapping := !explained && s.countChange(s.StatusChangedAt)
 
 		if s.OnStatusUpdate != nil {
-			if err := s.OnStatusUpdate(s, status); err != nil {
+			if err := s.OnStatusUpdate(ctx, s, status); err != nil {
 				slog.Error("Failed to persist status update", "Target", s.URL, "error", err)
 			}
 		}
+
+		if startedFlapping {
+			s.notifyFlap(ctx, true)
+		}
+	}
+}
+
+func explainedStatus(status string) bool {
+	return status == statusMaintenance || status == statusUnreachable
+}
+
+// Failing reports whether the target's confirmed status is a failure,
+// including being unreachable through a parent of its own
+func (s *Target) Failing() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch s.Status {
+	case statusDown, statusError, statusUnreachable:
+		return true
+	default:
+		return false
+	}
+}
+
+// countChange adds a status change to the sliding window and reports whether
+// it made the target start flapping
+func (s *Target) countChange(at time.Time) bool {
+	s.changes = append(s.changes, at)
+	for len(s.changes) > 0 && at.Sub(s.changes[0]) > s.FlapWindow {
+		s.changes = s.changes[1:]
+	}
+
+	if s.Flapping {
+		s.FlapChanges++
+		return false
+	}
+	if s.FlapThreshold > 0 && len(s.changes) > s.FlapThreshold {
+		s.Flapping = true
+		s.FlapChanges = len(s.changes)
+		return true
+	}
+	return false
+}
+
+// settle ends flapping once the status held for a whole window
+func (s *Target) settle(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.Flapping || now.Sub(s.StatusChangedAt) < s.FlapWindow {
+		return
+	}
+
+	s.notifyFlap(ctx, false)
+	s.Flapping = false
+	s.FlapChanges = 0
+	s.changes = nil
+}
+
+func (s *Target) notifyFlap(ctx context.Context, flapping bool) {
+	if s.OnFlap == nil {
+		return
+	}
+	if err := s.OnFlap(ctx, s, flapping); err != nil {
+		slog.Error("Failed to report flapping", "Target", s.URL, "flapping", flapping, "error", err)
 	}
 }
 
@@ -106,6 +379,21 @@ func (s *Target) Update(updatedTarget *Target) {
 	s.URL = updatedTarget.URL
 	s.Interval = updatedTarget.Interval
 	s.Enabled = updatedTarget.Enabled
+	s.PausedUntil = updatedTarget.PausedUntil
+	s.ParentIDs = updatedTarget.ParentIDs
+	if updatedTarget.FlapThreshold > 0 {
+		s.FlapThreshold = updatedTarget.FlapThreshold
+	}
+	if updatedTarget.FlapWindow > 0 {
+		s.FlapWindow = updatedTarget.FlapWindow
+	}
+}
+
+// Paused reports whether checks are on hold at the given time
+func (s *Target) Paused(now time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.PausedUntil != nil && now.Before(*s.PausedUntil)
 }
 
 type Manager struct {
@@ -130,11 +418,21 @@ func (m *Manager) RegisterTarget(target *Target) error {
 	if target.Client == nil {
 		target.Client = DefaultClient
 	}
+	if target.Confirmations <= 0 {
+		target.Confirmations = DefaultConfirmations
+	}
+	if target.FlapThreshold <= 0 {
+		target.FlapThreshold = DefaultFlapThreshold
+	}
+	if target.FlapWindow <= 0 {
+		target.FlapWindow = DefaultFlapWindow
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	target.cancelFunc = cancel
 
 	m.Targets[target.ID] = target
+	observeStatus(target)
 
 	go func() {
 		ticker := time.NewTicker(target.Interval)
@@ -148,10 +446,11 @@ func (m *Manager) RegisterTarget(target *Target) error {
 				delete(m.Targets, target.ID)
 				m.mu.Unlock()
 				return
-			case <-ticker.C:
-				if !target.Enabled {
+			case due := <-ticker.C:
+				if !target.Enabled || target.Paused(time.Now()) {
 					continue
 				}
+				observeTick(due)
 				if err := target.Check(); err != nil {
 					slog.Error("Target check failed", "Target", target.URL, "error", err)
 				}
@@ -163,12 +462,21 @@ func (m *Manager) RegisterTarget(target *Target) error {
 	return nil
 }
 
+// Get returns the monitored target with the given ID
+func (m *Manager) Get(targetID int) (*Target, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.Targets[targetID]
+	return target, ok
+}
+
 func (m *Manager) RevokeTarget(targetID int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if target, exist := m.Targets[targetID]; exist {
 		target.cancelFunc()
 		delete(m.Targets, targetID)
+		forgetTarget(targetID)
 		slog.Info("Monitoring Stopped", "Target", target.URL)
 	} else {
 		slog.Info("Target removed, but no monitoring was active", "targetID", targetID)
@@ -1,8 +1,16 @@
 package monitor
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -78,7 +86,7 @@ func TestTarget_updateStatus(t *testing.T) {
 	}
 
 	newStatus := statusDown
-	target.updateStatus(newStatus)
+	target.updateStatus(context.Background(), newStatus)
 
 	if target.Status != newStatus {
 		t.Errorf("expected status %q, got %q", newStatus, target.Status)
@@ -88,3 +96,297 @@ func TestTarget_updateStatus(t *testing.T) {
 		t.Errorf("StatusChangedAt was not updated correctly")
 	}
 }
+
+func TestTargetPaused(t *testing.T) {
+	now := time.Now()
+	until := now.Add(time.Hour)
+	target := &Target{}
+
+	if target.Paused(now) {
+		t.Errorf("expected target without PausedUntil not to be paused")
+	}
+
+	target.Update(&Target{PausedUntil: &until})
+	if !target.Paused(now) {
+		t.Errorf("expected target to be paused before %v", until)
+	}
+	if target.Paused(until) {
+		t.Errorf("expected pause to be over at %v", until)
+	}
+}
+
+func TestTargetCheckConfirmations(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var results []Result
+	target := &Target{
+		ID:            1,
+		URL:           ts.URL,
+		Status:        statusUp,
+		Confirmations: 2,
+		Client:        DefaultClient,
+		OnCheck: func(ctx context.Context, target *Target, result Result) {
+			results = append(results, result)
+		},
+	}
+
+	// A single failed check is not enough to mark the target down
+	if err := target.Check(); err == nil {
+		t.Error("Expected HTTP error, got nil")
+	}
+	if target.Status != statusUp {
+		t.Errorf("Status should not change before the failure is confirmed, got %s", target.Status)
+	}
+
+	target.Check()
+	if target.Status != statusDown {
+		t.Errorf("Expected status %s after a confirmed failure, got %s", statusDown, target.Status)
+	}
+	if target.Failures != 2 {
+		t.Errorf("Expected 2 failures, got %d", target.Failures)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected every check to be reported, got %d results", len(results))
+	}
+	if results[1].Status != statusDown || results[1].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected result: %+v", results[1])
+	}
+}
+
+func TestTargetCheckKeepsFailuresUntilRecovery(t *testing.T) {
+	healthy := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("  upstream unavailable\n" + strings.Repeat("x", 1024)))
+	}))
+	defer ts.Close()
+
+	var recovered *Target
+	target := &Target{
+		ID:            1,
+		URL:           ts.URL,
+		Status:        statusUp,
+		Confirmations: 1,
+		Client:        DefaultClient,
+		OnStatusUpdate: func(ctx context.Context, target *Target, status string) error {
+			if status == statusUp {
+				// Copy what the recovery callback sees before it is reset
+				recovered = &Target{Failures: target.Failures, FirstFailure: target.FirstFailure}
+			}
+			return nil
+		},
+	}
+
+	target.Check()
+	target.Check()
+
+	if target.FirstFailure == nil || target.FirstFailure.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected the first failure to be kept, got %+v", target.FirstFailure)
+	}
+	if !strings.HasPrefix(target.LastResult.Body, "upstream unavailable") || len(target.LastResult.Body) > bodySnippetLimit {
+		t.Errorf("Expected a trimmed body snippet, got %q", target.LastResult.Body)
+	}
+	if target.LastResult.ErrorClass() != ErrorClassServer {
+		t.Errorf("Expected error class %s, got %s", ErrorClassServer, target.LastResult.ErrorClass())
+	}
+
+	healthy = true
+	target.Check()
+
+	if recovered == nil || recovered.Failures != 2 || recovered.FirstFailure == nil {
+		t.Fatalf("Expected the recovery to see 2 failures, got %+v", recovered)
+	}
+	if target.Failures != 0 || target.FirstFailure != nil {
+		t.Errorf("Expected failures to be reset after recovery, got %d", target.Failures)
+	}
+}
+
+func TestTargetFlapping(t *testing.T) {
+	var flaps []bool
+	var updates int
+	target := &Target{
+		ID:            1,
+		URL:           "https://example.com",
+		Status:        statusUp,
+		Confirmations: 1,
+		FlapThreshold: 3,
+		FlapWindow:    time.Hour,
+		OnStatusUpdate: func(ctx context.Context, target *Target, status string) error {
+			updates++
+			return nil
+		},
+		OnFlap: func(ctx context.Context, target *Target, flapping bool) error {
+			flaps = append(flaps, flapping)
+			return nil
+		},
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		target.record(context.Background(), Result{Status: statusError, CheckedAt: now})
+		target.record(context.Background(), Result{Status: statusUp, CheckedAt: now})
+	}
+
+	if !target.Flapping {
+		t.Fatal("Expected the target to be flapping after 6 changes")
+	}
+	if len(flaps) != 1 || !flaps[0] {
+		t.Fatalf("Expected a single flapping report, got %v", flaps)
+	}
+	if updates != 6 || target.FlapChanges != 6 {
+		t.Errorf("Expected every change to still be reported and counted, got %d updates and %d changes", updates, target.FlapChanges)
+	}
+
+	// The status held, but not for a whole window yet
+	target.record(context.Background(), Result{Status: statusUp, CheckedAt: now.Add(30 * time.Minute)})
+	if !target.Flapping {
+		t.Error("Target should still be flapping within the window")
+	}
+
+	target.record(context.Background(), Result{Status: statusUp, CheckedAt: now.Add(2 * time.Hour)})
+	if target.Flapping || target.FlapChanges != 0 {
+		t.Error("Expected the target to stabilize once the status held for a window")
+	}
+	if len(flaps) != 2 || flaps[1] {
+		t.Errorf("Expected a stabilization report, got %v", flaps)
+	}
+}
+
+func TestTargetMaintenance(t *testing.T) {
+	inMaintenance := true
+	var statuses []string
+	var results []Result
+	target := &Target{
+		ID:            1,
+		URL:           "https://example.com",
+		Status:        statusDown,
+		Confirmations: 2,
+		FlapThreshold: 1,
+		FlapWindow:    time.Hour,
+		Failures:      3,
+		FirstFailure:  &Result{Status: statusDown},
+		InMaintenance: func(ctx context.Context, target *Target, at time.Time) bool {
+			return inMaintenance
+		},
+		OnStatusUpdate: func(ctx context.Context, target *Target, status string) error {
+			statuses = append(statuses, status)
+			return nil
+		},
+		OnCheck: func(ctx context.Context, target *Target, result Result) {
+			results = append(results, result)
+		},
+	}
+
+	now := time.Now()
+	target.record(context.Background(), Result{Status: statusDown, StatusCode: http.StatusServiceUnavailable, CheckedAt: now})
+	target.record(context.Background(), Result{Status: statusError, CheckedAt: now})
+
+	if target.Status != statusMaintenance {
+		t.Fatalf("Expected status %s during the window, got %s", statusMaintenance, target.Status)
+	}
+	if target.Failures != 0 || target.FirstFailure != nil {
+		t.Errorf("Expected checks in the window not to count as failures, got %d", target.Failures)
+	}
+	if len(results) != 2 || results[0].Status != statusMaintenance || results[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the checks to be reported as maintenance with their response, got %+v", results)
+	}
+
+	// After the window a failure needs confirming again
+	inMaintenance = false
+	target.record(context.Background(), Result{Status: statusDown, CheckedAt: now})
+	if target.Status != statusMaintenance {
+		t.Errorf("Expected a single failure after the window not to change the status, got %s", target.Status)
+	}
+	target.record(context.Background(), Result{Status: statusUp, CheckedAt: now})
+
+	if len(statuses) != 2 || statuses[0] != statusMaintenance || statuses[1] != statusUp {
+		t.Errorf("Expected the target to enter maintenance and come back up, got %v", statuses)
+	}
+	if target.Flapping {
+		t.Error("Expected maintenance not to count towards flapping")
+	}
+}
+
+func TestTargetFailingParent(t *testing.T) {
+	parent := &Target{ID: 1, URL: "https://lb.example.com", Status: statusDown}
+	var statuses []string
+	target := &Target{
+		ID:            2,
+		URL:           "https://app.example.com",
+		Status:        statusUp,
+		ParentIDs:     []int{1},
+		Confirmations: 2,
+		FlapThreshold: 1,
+		FlapWindow:    time.Hour,
+		FailingParent: func(target *Target) *Target {
+			if parent.Failing() {
+				return parent
+			}
+			return nil
+		},
+		OnStatusUpdate: func(ctx context.Context, target *Target, status string) error {
+			statuses = append(statuses, status)
+			return nil
+		},
+	}
+
+	now := time.Now()
+	target.record(context.Background(), Result{Status: statusError, CheckedAt: now})
+	if target.Status != statusUnreachable {
+		t.Fatalf("Expected status %s while the parent is down, got %s", statusUnreachable, target.Status)
+	}
+	if target.Failures != 0 {
+		t.Errorf("Expected failures behind a failing parent not to count, got %d", target.Failures)
+	}
+	if !target.Failing() {
+		t.Error("Expected an unreachable target to count as failing for its own children")
+	}
+
+	// Once the parent is back, failures of the target itself need confirming
+	parent.Status = statusUp
+	target.record(context.Background(), Result{Status: statusDown, CheckedAt: now})
+	if target.Status != statusUnreachable {
+		t.Errorf("Expected a single failure not to change the status, got %s", target.Status)
+	}
+	target.record(context.Background(), Result{Status: statusDown, CheckedAt: now})
+
+	if len(statuses) != 2 || statuses[0] != statusUnreachable || statuses[1] != statusDown {
+		t.Errorf("Expected the target to become unreachable and then down, got %v", statuses)
+	}
+	if target.Flapping {
+		t.Error("Expected an unreachable parent not to count towards flapping")
+	}
+}
+
+func TestResultErrorClass(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{name: "passed", result: Result{StatusCode: http.StatusOK}, want: ""},
+		{name: "client error", result: Result{StatusCode: http.StatusNotFound}, want: ErrorClassClient},
+		{name: "server error", result: Result{StatusCode: http.StatusServiceUnavailable}, want: ErrorClassServer},
+		{name: "dns", result: Result{Err: &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "example.invalid"}}}, want: ErrorClassDNS},
+		{name: "refused", result: Result{Err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}}, want: ErrorClassConnectionRefused},
+		{name: "tls", result: Result{Err: &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}}, want: ErrorClassTLS},
+		{name: "network", result: Result{Err: &net.OpError{Op: "read", Err: errors.New("broken")}}, want: ErrorClassNetwork},
+		{name: "unknown", result: Result{Err: errors.New("boom")}, want: ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.ErrorClass(); got != tt.want {
+				t.Errorf("Expected error class %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
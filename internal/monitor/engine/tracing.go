@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+
+// startCheckSpan starts the span covering a check. The request of the check
+// is made with the returned context, so its client span is a child.
+func startCheckSpan(target *Target) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(context.Background(), "Target.Check",
+		trace.WithAttributes(
+			attribute.Int("target.id", target.ID),
+			semconv.URLFull(target.URL),
+		),
+	)
+}
+
+// endCheckSpan records the outcome of the check on its span and ends it. A
+// check that timed out has no result status.
+func endCheckSpan(span trace.Span, result Result, err error) {
+	if result.Status != "" {
+		span.SetAttributes(attribute.String("check.status", result.Status))
+	}
+	if result.StatusCode != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(result.StatusCode))
+	}
+	if class := result.ErrorClass(); class != "" {
+		span.SetAttributes(attribute.String("check.error_class", class))
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
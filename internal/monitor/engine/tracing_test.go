@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCheckTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var callbackSpan trace.SpanContext
+	target := &Target{ID: 7, URL: ts.URL, Confirmations: 1, Client: &http.Client{Transport: tracing.Transport(nil)}}
+	target.OnCheck = func(ctx context.Context, target *Target, result Result) {
+		callbackSpan = trace.SpanContextFromContext(ctx)
+	}
+	target.Check()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected a check span and a client span, got %d spans", len(spans))
+	}
+	client, check := spans[0], spans[1]
+
+	if check.Name() != "Target.Check" {
+		t.Errorf("expected span Target.Check, got %s", check.Name())
+	}
+	if client.Parent().SpanID() != check.SpanContext().SpanID() {
+		t.Errorf("expected the client span to be a child of the check span")
+	}
+	if callbackSpan.SpanID() != check.SpanContext().SpanID() {
+		t.Errorf("expected the check callback to get the context of the check span")
+	}
+	if check.Status().Code != codes.Error {
+		t.Errorf("expected the failed check to set an error status")
+	}
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range check.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	if attributes["target.id"].AsInt64() != 7 {
+		t.Errorf("expected target.id 7, got %v", attributes["target.id"])
+	}
+	if attributes["check.status"].AsString() != statusDown {
+		t.Errorf("expected check.status %s, got %v", statusDown, attributes["check.status"])
+	}
+	if attributes["check.error_class"].AsString() != ErrorClassServer {
+		t.Errorf("expected check.error_class %s, got %v", ErrorClassServer, attributes["check.error_class"])
+	}
+}
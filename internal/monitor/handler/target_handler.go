@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
 	targetService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
 	"github.com/shuvo-paul/uptimebot/pkg/flash"
@@ -24,6 +26,13 @@ type TargetHandler struct {
 	}
 }
 
+// parentOption is another target offered as a parent on the edit form
+type parentOption struct {
+	ID       int
+	URL      string
+	Selected bool
+}
+
 func NewTargetHandler(targetService targetService.TargetServiceInterface, flash flash.FlashStoreInterface) *TargetHandler {
 	c := &TargetHandler{
 		targetService: targetService,
@@ -40,7 +49,7 @@ func (c *TargetHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	targets, err := c.targetService.GetAllByUserID(user.ID)
+	targets, err := c.targetService.GetAllByUserID(r.Context(), user.ID)
 	if err != nil {
 		http.Error(w, "Failed to fetch targets", http.StatusInternalServerError)
 		return
@@ -82,7 +91,7 @@ func (c *TargetHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userTarget, err := c.targetService.Create(user.ID, url, time.Duration(interval)*time.Second)
+	userTarget, err := c.targetService.Create(r.Context(), user.ID, url, time.Duration(interval)*time.Second)
 	if err != nil {
 		errors := []string{
 			"Failed to create target: " + err.Error(),
@@ -116,22 +125,38 @@ func (c *TargetHandler) Edit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.Method == http.MethodGet {
-		target, err := c.targetService.GetByID(id, user.ID)
+		target, err := c.targetService.GetByID(r.Context(), id, user.ID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
 
+		targets, err := c.targetService.GetAllByUserID(r.Context(), user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var parents []parentOption
+		for _, other := range targets {
+			if other.ID == target.ID {
+				continue
+			}
+			parents = append(parents, parentOption{ID: other.ID, URL: other.URL, Selected: slices.Contains(target.ParentIDs, other.ID)})
+		}
+		slices.SortFunc(parents, func(a, b parentOption) int { return a.ID - b.ID })
+
 		data := map[string]any{
-			"Title":  "Edit Target",
-			"target": target,
+			"Title":   "Edit Target",
+			"target":  target,
+			"tags":    strings.Join(target.Tags, ", "),
+			"parents": parents,
 		}
 
 		c.Template.Edit.Render(w, r, data)
 		return
 	}
 
-	target, err := c.targetService.GetByID(id, user.ID)
+	target, err := c.targetService.GetByID(r.Context(), id, user.ID)
 	if err != nil {
 		code := http.StatusNotFound
 		msg := err.Error()
@@ -154,8 +179,33 @@ func (c *TargetHandler) Edit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	target.Interval = time.Duration(interval) * time.Second
+	flapThreshold, err := strconv.Atoi(r.FormValue("flap_threshold"))
+	if err != nil {
+		c.flash.SetErrors(r.Context(), []string{"Invalid flap threshold value"})
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+	flapWindow, err := strconv.Atoi(r.FormValue("flap_window"))
+	if err != nil {
+		c.flash.SetErrors(r.Context(), []string{"Invalid flap window value"})
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+	target.FlapThreshold = flapThreshold
+	target.FlapWindow = time.Duration(flapWindow) * time.Minute
+	target.Tags = model.ParseTags(r.FormValue("tags"))
+	target.ParentIDs = nil
+	for _, value := range r.Form["parent_ids"] {
+		parentID, err := strconv.Atoi(value)
+		if err != nil {
+			c.flash.SetErrors(r.Context(), []string{"Invalid parent target"})
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+		target.ParentIDs = append(target.ParentIDs, parentID)
+	}
 
-	_, err = c.targetService.Update(target, user.ID)
+	_, err = c.targetService.Update(r.Context(), target, user.ID)
 	if err != nil {
 		errors := []string{
 			"Failed to update target: " + err.Error(),
@@ -184,7 +234,7 @@ func (c *TargetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = c.targetService.Delete(id, user.ID)
+	err = c.targetService.Delete(r.Context(), id, user.ID)
 	if err != nil {
 		c.flash.SetErrors(r.Context(), []string{"Failed to delete target: " + err.Error()})
 	} else {
@@ -208,7 +258,7 @@ func (c *TargetHandler) ToggleEnabled(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userTarget, err := c.targetService.ToggleEnabled(id, user.ID)
+	userTarget, err := c.targetService.ToggleEnabled(r.Context(), id, user.ID)
 
 	var enabledState string
 	if userTarget.Enabled {
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -29,43 +30,58 @@ type mockTargetService struct {
 	getAllByUserIDFunc       func(userID int) ([]model.UserTarget, error)
 	initializeMonitoringFunc func() error
 	toggleEnabledFunc        func(id, userID int) (model.UserTarget, error)
+	pauseFromSlackFunc       func(id int, until time.Time) (model.UserTarget, error)
+	pauseFunc                func(id int, userID int, until time.Time) (model.UserTarget, error)
+	getResultsFunc           func(id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error)
 }
 
-func (m *mockTargetService) GetAll() ([]model.UserTarget, error) {
+func (m *mockTargetService) GetAll(ctx context.Context) ([]model.UserTarget, error) {
 	return m.getAllFunc()
 }
 
-func (m *mockTargetService) GetByID(id, userID int) (model.UserTarget, error) {
+func (m *mockTargetService) GetByID(ctx context.Context, id, userID int) (model.UserTarget, error) {
 	return m.getByIDFunc(id, userID)
 }
 
-func (m *mockTargetService) Create(userID int, url string, interval time.Duration) (model.UserTarget, error) {
+func (m *mockTargetService) Create(ctx context.Context, userID int, url string, interval time.Duration) (model.UserTarget, error) {
 	return m.createFunc(userID, url, interval)
 }
 
-func (m *mockTargetService) Update(target model.UserTarget, userID int) (model.UserTarget, error) {
+func (m *mockTargetService) Update(ctx context.Context, target model.UserTarget, userID int) (model.UserTarget, error) {
 	return m.updateFunc(target, userID)
 }
 
-func (m *mockTargetService) Delete(id, userID int) error {
+func (m *mockTargetService) Delete(ctx context.Context, id, userID int) error {
 	return m.deleteFunc(id, userID)
 }
 
-func (m *mockTargetService) GetAllByUserID(userID int) ([]model.UserTarget, error) {
+func (m *mockTargetService) GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error) {
 	return m.getAllByUserIDFunc(userID)
 }
 
-func (m *mockTargetService) InitializeMonitoring() error {
+func (m *mockTargetService) InitializeMonitoring(ctx context.Context) error {
 	if m.initializeMonitoringFunc != nil {
 		return m.initializeMonitoringFunc()
 	}
 	return nil
 }
 
-func (m *mockTargetService) ToggleEnabled(id, userID int) (model.UserTarget, error) {
+func (m *mockTargetService) ToggleEnabled(ctx context.Context, id, userID int) (model.UserTarget, error) {
 	return m.toggleEnabledFunc(id, userID)
 }
 
+func (m *mockTargetService) PauseFromSlack(ctx context.Context, id int, until time.Time) (model.UserTarget, error) {
+	return m.pauseFromSlackFunc(id, until)
+}
+
+func (m *mockTargetService) GetResults(ctx context.Context, id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+	return m.getResultsFunc(id, userID, beforeID, limit)
+}
+
+func (m *mockTargetService) Pause(ctx context.Context, id int, userID int, until time.Time) (model.UserTarget, error) {
+	return m.pauseFunc(id, userID, until)
+}
+
 func TestTargetHandler_List(t *testing.T) {
 	mockFlashStore := flash.NewMockFlashStore()
 	mockService := &mockTargetService{
@@ -178,7 +194,14 @@ func TestTargetHandler_Edit(t *testing.T) {
 			getByIDFunc: func(id, userID int) (model.UserTarget, error) {
 				return model.UserTarget{
 					UserID: userID,
-					Target: &monitor.Target{ID: id, URL: "http://example.com", Interval: 60 * time.Second},
+					Target: &monitor.Target{ID: id, URL: "http://example.com", Interval: 60 * time.Second, ParentIDs: []int{2},
+						FlapThreshold: 5, FlapWindow: 15 * time.Minute},
+				}, nil
+			},
+			getAllByUserIDFunc: func(userID int) ([]model.UserTarget, error) {
+				return []model.UserTarget{
+					{UserID: userID, Target: &monitor.Target{ID: 1, URL: "http://example.com"}},
+					{UserID: userID, Target: &monitor.Target{ID: 2, URL: "http://lb.example.com"}},
 				}, nil
 			},
 			initializeMonitoringFunc: func() error { return nil },
@@ -201,6 +224,10 @@ func TestTargetHandler_Edit(t *testing.T) {
 		handler.Edit(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `value="2" checked`)
+		assert.Contains(t, w.Body.String(), "http://lb.example.com")
+		assert.Contains(t, w.Body.String(), `name="flap_window" required min="1" max="1440"`)
+		assert.Contains(t, w.Body.String(), `value="15"`)
 	})
 
 	t.Run("POST request - success", func(t *testing.T) {
@@ -213,6 +240,10 @@ func TestTargetHandler_Edit(t *testing.T) {
 				}, nil
 			},
 			updateFunc: func(ut model.UserTarget, userID int) (model.UserTarget, error) {
+				assert.Equal(t, []string{"production", "api"}, ut.Tags)
+				assert.Equal(t, []int{2, 3}, ut.ParentIDs)
+				assert.Equal(t, 8, ut.FlapThreshold)
+				assert.Equal(t, 30*time.Minute, ut.FlapWindow)
 				return ut, nil
 			},
 			initializeMonitoringFunc: func() error { return nil },
@@ -223,6 +254,11 @@ func TestTargetHandler_Edit(t *testing.T) {
 		form := url.Values{}
 		form.Add("url", "http://example.com")
 		form.Add("interval", "60")
+		form.Add("flap_threshold", "8")
+		form.Add("flap_window", "30")
+		form.Add("tags", "Production, api")
+		form.Add("parent_ids", "2")
+		form.Add("parent_ids", "3")
 
 		req := httptest.NewRequest(http.MethodPost, "/app/targets/1/edit", strings.NewReader(form.Encode()))
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
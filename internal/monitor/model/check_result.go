@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// CheckResult is a stored outcome of a single check of a target
+type CheckResult struct {
+	ID         int64
+	TargetID   int
+	Status     string
+	StatusCode int // zero when no response was received
+	Duration   time.Duration
+	ErrorClass string // empty when the check passed
+	CheckedAt  time.Time
+}
@@ -1,8 +1,28 @@
 package model
 
-import monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+import (
+	"strings"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+)
 
 type UserTarget struct {
 	UserID int
+	Tags   []string
 	*monitor.Target
 }
+
+// ParseTags splits a comma separated list into normalized, unique tags
+func ParseTags(input string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(input, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
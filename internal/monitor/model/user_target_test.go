@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty input", input: "", want: []string{}},
+		{name: "single tag", input: "production", want: []string{"production"}},
+		{name: "trims and lowercases", input: " Production , API ", want: []string{"production", "api"}},
+		{name: "drops duplicates and blanks", input: "api,,api, ,web", want: []string{"api", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseTags(tt.input))
+		})
+	}
+}
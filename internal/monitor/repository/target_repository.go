@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"net/url"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/shuvo-paul/uptimebot/internal/database"
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
@@ -17,13 +19,16 @@ var (
 )
 
 type TargetRepositoryInterface interface {
-	Create(model.UserTarget) (model.UserTarget, error)
-	GetByID(int) (model.UserTarget, error)
-	GetAll() ([]model.UserTarget, error)
-	GetAllByUserID(userID int) ([]model.UserTarget, error)
-	Update(model.UserTarget) (model.UserTarget, error)
-	Delete(int) error
-	UpdateStatus(*monitor.Target, string) error
+	Create(context.Context, model.UserTarget) (model.UserTarget, error)
+	GetByID(context.Context, int) (model.UserTarget, error)
+	GetAll(ctx context.Context) ([]model.UserTarget, error)
+	GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error)
+	Update(context.Context, model.UserTarget) (model.UserTarget, error)
+	UpdateChecked(ctx context.Context, userTarget model.UserTarget, check func(targets []model.UserTarget) error) (model.UserTarget, error)
+	Delete(context.Context, int) error
+	UpdateStatus(ctx context.Context, target *monitor.Target, status string) error
+	SaveResult(ctx context.Context, targetID int, result monitor.Result) error
+	GetResults(ctx context.Context, targetID int, beforeID int64, limit int) ([]model.CheckResult, error)
 }
 
 var _ TargetRepositoryInterface = (*TargetRepository)(nil)
@@ -36,7 +41,7 @@ func NewTargetRepository(db database.Querier) *TargetRepository {
 	return &TargetRepository{db: db}
 }
 
-func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget, error) {
+func (r *TargetRepository) Create(ctx context.Context, userTarget model.UserTarget) (model.UserTarget, error) {
 	if userTarget.URL == "" {
 		return model.UserTarget{}, fmt.Errorf("URL cannot be empty")
 	}
@@ -50,12 +55,19 @@ func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget
 	// Ensure time is in UTC before storing
 	userTarget.StatusChangedAt = userTarget.StatusChangedAt.UTC()
 
+	if userTarget.Tags == nil {
+		userTarget.Tags = []string{}
+	}
+	if userTarget.ParentIDs == nil {
+		userTarget.ParentIDs = []int{}
+	}
+
 	query := `
-		INSERT INTO target (url, user_id, status, enabled, interval, changed_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO target (url, user_id, status, enabled, interval, changed_at, tags, parent_ids, flap_threshold, flap_window)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id`
 
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		userTarget.URL,
 		userTarget.UserID,
@@ -63,6 +75,10 @@ func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget
 		userTarget.Enabled,
 		userTarget.Interval.Seconds(),
 		userTarget.StatusChangedAt,
+		pq.Array(userTarget.Tags),
+		pq.Array(userTarget.ParentIDs),
+		userTarget.FlapThreshold,
+		userTarget.FlapWindow.Seconds(),
 	).Scan(&userTarget.ID)
 
 	if err != nil {
@@ -72,16 +88,18 @@ func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget
 	return userTarget, nil
 }
 
-func (r *TargetRepository) GetByID(id int) (model.UserTarget, error) {
+func (r *TargetRepository) GetByID(ctx context.Context, id int) (model.UserTarget, error) {
 	query := `
-        SELECT id, url, status, enabled, interval, changed_at, user_id
+        SELECT id, url, status, enabled, interval, changed_at, user_id, tags, paused_until, parent_ids, flap_threshold, flap_window
         FROM target
         WHERE id = $1`
 
 	userTarget := model.UserTarget{Target: &monitor.Target{}}
 	var intervalSeconds float64
+	var parentIDs pq.Int64Array
+	var flapWindowSeconds float64
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&userTarget.ID,
 		&userTarget.URL,
 		&userTarget.Status,
@@ -89,6 +107,11 @@ func (r *TargetRepository) GetByID(id int) (model.UserTarget, error) {
 		&intervalSeconds,
 		&userTarget.StatusChangedAt, // Direct scan into time.Time
 		&userTarget.UserID,
+		pq.Array(&userTarget.Tags),
+		&userTarget.PausedUntil,
+		&parentIDs,
+		&userTarget.FlapThreshold,
+		&flapWindowSeconds,
 	)
 
 	if err == sql.ErrNoRows {
@@ -100,15 +123,17 @@ func (r *TargetRepository) GetByID(id int) (model.UserTarget, error) {
 
 	userTarget.Interval = time.Duration(intervalSeconds) * time.Second
 	userTarget.StatusChangedAt = userTarget.StatusChangedAt.UTC()
+	userTarget.ParentIDs = intIDs(parentIDs)
+	userTarget.FlapWindow = time.Duration(flapWindowSeconds) * time.Second
 	return userTarget, nil
 }
 
-func (r *TargetRepository) GetAll() ([]model.UserTarget, error) {
+func (r *TargetRepository) GetAll(ctx context.Context) ([]model.UserTarget, error) {
 	query := `
-		SELECT id, url, status, enabled, interval, changed_at, user_id
+		SELECT id, url, status, enabled, interval, changed_at, user_id, tags, paused_until, parent_ids, flap_threshold, flap_window
 		FROM target`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query targets: %w", err)
 	}
@@ -118,6 +143,8 @@ func (r *TargetRepository) GetAll() ([]model.UserTarget, error) {
 	for rows.Next() {
 		userTarget := model.UserTarget{Target: &monitor.Target{}}
 		var intervalSeconds float64
+		var parentIDs pq.Int64Array
+		var flapWindowSeconds float64
 
 		err = rows.Scan(
 			&userTarget.ID,
@@ -127,6 +154,11 @@ func (r *TargetRepository) GetAll() ([]model.UserTarget, error) {
 			&intervalSeconds,
 			&userTarget.StatusChangedAt, // Direct scan into time.Time
 			&userTarget.UserID,
+			pq.Array(&userTarget.Tags),
+			&userTarget.PausedUntil,
+			&parentIDs,
+			&userTarget.FlapThreshold,
+			&flapWindowSeconds,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan target: %w", err)
@@ -134,6 +166,8 @@ func (r *TargetRepository) GetAll() ([]model.UserTarget, error) {
 
 		userTarget.Interval = time.Duration(intervalSeconds) * time.Second
 		userTarget.StatusChangedAt = userTarget.StatusChangedAt.UTC()
+		userTarget.ParentIDs = intIDs(parentIDs)
+		userTarget.FlapWindow = time.Duration(flapWindowSeconds) * time.Second
 		targets = append(targets, userTarget)
 	}
 
@@ -144,13 +178,17 @@ func (r *TargetRepository) GetAll() ([]model.UserTarget, error) {
 	return targets, nil
 }
 
-func (r *TargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error) {
-	query := `
-		SELECT id, url, status, enabled, interval, changed_at, user_id
+const targetsByUserQuery = `
+		SELECT id, url, status, enabled, interval, changed_at, user_id, tags, paused_until, parent_ids, flap_threshold, flap_window
 		FROM target 
 		WHERE user_id = $1`
 
-	rows, err := r.db.Query(query, userID)
+func (r *TargetRepository) GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error) {
+	return r.queryByUserID(ctx, targetsByUserQuery, userID)
+}
+
+func (r *TargetRepository) queryByUserID(ctx context.Context, query string, userID int) ([]model.UserTarget, error) {
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query targets: %w", err)
 	}
@@ -160,6 +198,8 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error
 	for rows.Next() {
 		userTarget := model.UserTarget{Target: &monitor.Target{}}
 		var intervalSeconds float64
+		var parentIDs pq.Int64Array
+		var flapWindowSeconds float64
 
 		err = rows.Scan(
 			&userTarget.ID,
@@ -169,6 +209,11 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error
 			&intervalSeconds,
 			&userTarget.StatusChangedAt, // Direct scan into time.Time
 			&userTarget.UserID,
+			pq.Array(&userTarget.Tags),
+			&userTarget.PausedUntil,
+			&parentIDs,
+			&userTarget.FlapThreshold,
+			&flapWindowSeconds,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan target: %w", err)
@@ -176,6 +221,8 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error
 
 		userTarget.Interval = time.Duration(intervalSeconds) * time.Second
 		userTarget.StatusChangedAt = userTarget.StatusChangedAt.UTC()
+		userTarget.ParentIDs = intIDs(parentIDs)
+		userTarget.FlapWindow = time.Duration(flapWindowSeconds) * time.Second
 		targets = append(targets, userTarget)
 	}
 
@@ -186,22 +233,35 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error
 	return targets, nil
 }
 
-func (r *TargetRepository) Update(userTarget model.UserTarget) (model.UserTarget, error) {
+func (r *TargetRepository) Update(ctx context.Context, userTarget model.UserTarget) (model.UserTarget, error) {
 	// Ensure time is in UTC before updating
 	userTarget.StatusChangedAt = userTarget.StatusChangedAt.UTC()
 
+	if userTarget.Tags == nil {
+		userTarget.Tags = []string{}
+	}
+	if userTarget.ParentIDs == nil {
+		userTarget.ParentIDs = []int{}
+	}
+
 	query := `
 		UPDATE target
-		SET url = $1, status = $2, enabled = $3, interval = $4, changed_at = $5
-		WHERE id = $6`
+		SET url = $1, status = $2, enabled = $3, interval = $4, changed_at = $5, tags = $6, paused_until = $7,
+			parent_ids = $8, flap_threshold = $9, flap_window = $10
+		WHERE id = $11`
 
-	result, err := r.db.Exec(
+	result, err := r.db.ExecContext(ctx,
 		query,
 		userTarget.URL,
 		userTarget.Status,
 		userTarget.Enabled,
 		userTarget.Interval.Seconds(),
 		userTarget.StatusChangedAt,
+		pq.Array(userTarget.Tags),
+		userTarget.PausedUntil,
+		pq.Array(userTarget.ParentIDs),
+		userTarget.FlapThreshold,
+		userTarget.FlapWindow.Seconds(),
 		userTarget.ID,
 	)
 	if err != nil {
@@ -220,7 +280,32 @@ func (r *TargetRepository) Update(userTarget model.UserTarget) (model.UserTarget
 	return userTarget, nil
 }
 
-func (r *TargetRepository) UpdateStatus(target *monitor.Target, status string) error {
+// UpdateChecked updates a target in a transaction that first locks every
+// target of its owner and passes them to check, writing nothing when check
+// fails. Concurrent updates of one owner's targets thus wait for each other,
+// and check sees the targets as they are when the update is written.
+func (r *TargetRepository) UpdateChecked(ctx context.Context, userTarget model.UserTarget, check func(targets []model.UserTarget) error) (model.UserTarget, error) {
+	var updated model.UserTarget
+	err := database.WithTx(ctx, r.db, func(tx database.Querier) error {
+		txRepo := &TargetRepository{db: tx}
+		// Locking in id order keeps concurrent updates from deadlocking
+		targets, err := txRepo.queryByUserID(ctx, targetsByUserQuery+" ORDER BY id FOR UPDATE", userTarget.UserID)
+		if err != nil {
+			return err
+		}
+		if err := check(targets); err != nil {
+			return err
+		}
+		updated, err = txRepo.Update(ctx, userTarget)
+		return err
+	})
+	if err != nil {
+		return model.UserTarget{}, err
+	}
+	return updated, nil
+}
+
+func (r *TargetRepository) UpdateStatus(ctx context.Context, target *monitor.Target, status string) error {
 	// Ensure time is in UTC before updating
 	target.StatusChangedAt = target.StatusChangedAt.UTC()
 
@@ -229,7 +314,7 @@ func (r *TargetRepository) UpdateStatus(target *monitor.Target, status string) e
 		SET status = $1, changed_at = $2
 		WHERE id = $3`
 
-	result, err := r.db.Exec(query, status, target.StatusChangedAt, target.ID)
+	result, err := r.db.ExecContext(ctx, query, status, target.StatusChangedAt, target.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update target status: %w", err)
 	}
@@ -246,10 +331,80 @@ func (r *TargetRepository) UpdateStatus(target *monitor.Target, status string) e
 	return nil
 }
 
-func (r *TargetRepository) Delete(targetId int) error {
+// SaveResult stores the outcome of a check, for uptime and response time reports
+func (r *TargetRepository) SaveResult(ctx context.Context, targetID int, result monitor.Result) error {
+	query := `
+		INSERT INTO check_result (target_id, status, status_code, duration_ms, error_class, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		targetID,
+		result.Status,
+		result.StatusCode,
+		result.Duration.Milliseconds(),
+		result.ErrorClass(),
+		result.CheckedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save check result: %w", err)
+	}
+	return nil
+}
+
+// GetResults returns up to limit results of a target, newest first. A
+// non-zero beforeID only returns results stored before that one.
+func (r *TargetRepository) GetResults(ctx context.Context, targetID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+	query := `
+		SELECT id, target_id, status, status_code, duration_ms, error_class, checked_at
+		FROM check_result
+		WHERE target_id = $1 AND ($2::bigint = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, targetID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []model.CheckResult
+	for rows.Next() {
+		var result model.CheckResult
+		var durationMS int64
+		err := rows.Scan(
+			&result.ID,
+			&result.TargetID,
+			&result.Status,
+			&result.StatusCode,
+			&durationMS,
+			&result.ErrorClass,
+			&result.CheckedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan check result: %w", err)
+		}
+		result.Duration = time.Duration(durationMS) * time.Millisecond
+		result.CheckedAt = result.CheckedAt.UTC()
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating check results: %w", err)
+	}
+
+	return results, nil
+}
+
+// Delete removes a target, and removes it from the parents of the targets
+// that depended on it
+func (r *TargetRepository) Delete(ctx context.Context, targetId int) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE target SET parent_ids = array_remove(parent_ids, $1) WHERE $1 = ANY(parent_ids)`, targetId); err != nil {
+		return fmt.Errorf("failed to remove target from dependents: %w", err)
+	}
+
 	query := `DELETE FROM target WHERE id = $1`
 
-	result, err := r.db.Exec(query, targetId)
+	result, err := r.db.ExecContext(ctx, query, targetId)
 	if err != nil {
 		return err
 	}
@@ -265,3 +420,12 @@ func (r *TargetRepository) Delete(targetId int) error {
 
 	return nil
 }
+
+// intIDs converts IDs scanned from an INTEGER[] column
+func intIDs(ids pq.Int64Array) []int {
+	converted := make([]int, len(ids))
+	for i, id := range ids {
+		converted[i] = int(id)
+	}
+	return converted
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -24,7 +26,7 @@ func TestTargetRepository_Create(t *testing.T) {
 
 	// Create test user
 	userRepo := authRepo.NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&authModel.User{
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	})
@@ -80,7 +82,7 @@ func TestTargetRepository_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			newTarget, err := repo.Create(tt.target)
+			newTarget, err := repo.Create(context.Background(), tt.target)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -103,7 +105,7 @@ func TestTargetRepository_Update(t *testing.T) {
 
 	// Create test user
 	userRepo := authRepo.NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&authModel.User{
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	})
@@ -134,6 +136,42 @@ func TestTargetRepository_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "update tags",
+			setupTarget: model.UserTarget{
+				UserID: user.ID,
+				Tags:   []string{"staging"},
+				Target: &core.Target{
+					URL:             "example.org",
+					Status:          "up",
+					Enabled:         true,
+					Interval:        30 * time.Second,
+					StatusChangedAt: time.Now(),
+				},
+			},
+			updateFunc: func(s *model.UserTarget) {
+				s.Tags = []string{"production", "api"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "pause",
+			setupTarget: model.UserTarget{
+				UserID: user.ID,
+				Target: &core.Target{
+					URL:             "example.org",
+					Status:          "down",
+					Enabled:         true,
+					Interval:        30 * time.Second,
+					StatusChangedAt: time.Now(),
+				},
+			},
+			updateFunc: func(s *model.UserTarget) {
+				until := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+				s.PausedUntil = &until
+			},
+			wantErr: false,
+		},
 		{
 			name: "update non-existent target",
 			setupTarget: model.UserTarget{
@@ -159,7 +197,7 @@ func TestTargetRepository_Update(t *testing.T) {
 			if tt.name == "update non-existent target" {
 				userTarget = tt.setupTarget
 			} else {
-				created, err := repo.Create(tt.setupTarget)
+				created, err := repo.Create(context.Background(), tt.setupTarget)
 				if err != nil {
 					t.Fatalf("Failed to create test target: %v", err)
 				}
@@ -167,7 +205,7 @@ func TestTargetRepository_Update(t *testing.T) {
 			}
 
 			tt.updateFunc(&userTarget)
-			updated, err := repo.Update(userTarget)
+			updated, err := repo.Update(context.Background(), userTarget)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -175,7 +213,7 @@ func TestTargetRepository_Update(t *testing.T) {
 			}
 
 			assert.NoError(t, err)
-			fetched, err := repo.GetByID(userTarget.ID)
+			fetched, err := repo.GetByID(context.Background(), userTarget.ID)
 			assert.NoError(t, err)
 
 			// Replace direct equality check with individual field comparisons
@@ -185,6 +223,8 @@ func TestTargetRepository_Update(t *testing.T) {
 			assert.Equal(t, updated.Enabled, fetched.Enabled)
 			assert.Equal(t, updated.Interval, fetched.Interval)
 			assert.Equal(t, updated.UserID, fetched.UserID)
+			assert.ElementsMatch(t, updated.Tags, fetched.Tags)
+			assert.Equal(t, updated.PausedUntil, fetched.PausedUntil)
 			// Normalize both times to UTC before comparison
 			assert.Equal(t, updated.StatusChangedAt, fetched.StatusChangedAt)
 		})
@@ -198,7 +238,7 @@ func TestTargetRepository_Delete(t *testing.T) {
 
 	// Create test user
 	userRepo := authRepo.NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&authModel.User{
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	})
@@ -235,7 +275,7 @@ func TestTargetRepository_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var targetID int
 			if tt.setupTarget.Target != nil {
-				created, err := repo.Create(tt.setupTarget)
+				created, err := repo.Create(context.Background(), tt.setupTarget)
 				if err != nil {
 					t.Fatalf("Failed to create test target: %v", err)
 				}
@@ -244,20 +284,199 @@ func TestTargetRepository_Delete(t *testing.T) {
 				targetID = tt.targetID
 			}
 
-			err := repo.Delete(targetID)
+			err := repo.Delete(context.Background(), targetID)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 
 			assert.NoError(t, err)
-			_, err = repo.GetByID(targetID)
+			_, err = repo.GetByID(context.Background(), targetID)
 			assert.Error(t, err)
 			assert.ErrorIs(t, err, ErrTargetNotFound)
 		})
 	}
 }
 
+func TestTargetRepository_ParentIDs(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+	})
+	assert.NoError(t, err)
+
+	create := func(url string) model.UserTarget {
+		target, err := repo.Create(context.Background(), model.UserTarget{
+			UserID: user.ID,
+			Target: &core.Target{URL: url, Status: "up", Enabled: true, Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+		})
+		assert.NoError(t, err)
+		return target
+	}
+	balancer := create("https://lb.example.org")
+	gateway := create("https://gateway.example.org")
+	app := create("https://app.example.org")
+
+	stored, err := repo.GetByID(context.Background(), app.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.ParentIDs)
+
+	stored.ParentIDs = []int{balancer.ID, gateway.ID}
+	_, err = repo.Update(context.Background(), stored)
+	assert.NoError(t, err)
+
+	stored, err = repo.GetByID(context.Background(), app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{balancer.ID, gateway.ID}, stored.ParentIDs)
+
+	// Deleting a parent removes it from its dependents
+	assert.NoError(t, repo.Delete(context.Background(), balancer.ID))
+	stored, err = repo.GetByID(context.Background(), app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{gateway.ID}, stored.ParentIDs)
+}
+
+func TestTargetRepository_UpdateChecked(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+	})
+	assert.NoError(t, err)
+
+	create := func(url string) model.UserTarget {
+		target, err := repo.Create(context.Background(), model.UserTarget{
+			UserID: user.ID,
+			Target: &core.Target{URL: url, Status: "up", Enabled: true, Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+		})
+		assert.NoError(t, err)
+		return target
+	}
+	balancer := create("https://lb.example.org")
+	app := create("https://app.example.org")
+
+	var seen []int
+	app.ParentIDs = []int{balancer.ID}
+	_, err = repo.UpdateChecked(context.Background(), app, func(targets []model.UserTarget) error {
+		for _, target := range targets {
+			seen = append(seen, target.ID)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{balancer.ID, app.ID}, seen)
+
+	stored, err := repo.GetByID(context.Background(), app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{balancer.ID}, stored.ParentIDs)
+
+	// Nothing is written when the check fails
+	rejected := errors.New("cycle")
+	app.ParentIDs = nil
+	_, err = repo.UpdateChecked(context.Background(), app, func(targets []model.UserTarget) error { return rejected })
+	assert.ErrorIs(t, err, rejected)
+
+	stored, err = repo.GetByID(context.Background(), app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{balancer.ID}, stored.ParentIDs)
+}
+
+func TestTargetRepository_FlapSettings(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+	})
+	assert.NoError(t, err)
+
+	target, err := repo.Create(context.Background(), model.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{URL: "https://example.org", Status: "up", Enabled: true, Interval: 30 * time.Second,
+			StatusChangedAt: time.Now(), FlapThreshold: 5, FlapWindow: 15 * time.Minute},
+	})
+	assert.NoError(t, err)
+
+	stored, err := repo.GetByID(context.Background(), target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stored.FlapThreshold)
+	assert.Equal(t, 15*time.Minute, stored.FlapWindow)
+
+	stored.FlapThreshold = 8
+	stored.FlapWindow = time.Hour
+	_, err = repo.Update(context.Background(), stored)
+	assert.NoError(t, err)
+
+	stored, err = repo.GetByID(context.Background(), target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, stored.FlapThreshold)
+	assert.Equal(t, time.Hour, stored.FlapWindow)
+}
+
+func TestTargetRepository_SaveResult(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewTargetRepository(tx)
+
+	userRepo := authRepo.NewUserRepository(tx)
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	created, err := repo.Create(context.Background(), model.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{URL: "example.org", Status: "up", Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+	})
+	assert.NoError(t, err)
+
+	result := core.Result{Status: "down", StatusCode: 503, Duration: 120 * time.Millisecond, CheckedAt: time.Now()}
+	assert.NoError(t, repo.SaveResult(context.Background(), created.ID, result))
+
+	var statusCode, durationMs int
+	var errorClass string
+	err = tx.QueryRow(`SELECT status_code, duration_ms, error_class FROM check_result WHERE target_id = $1`, created.ID).
+		Scan(&statusCode, &durationMs, &errorClass)
+	assert.NoError(t, err)
+	assert.Equal(t, 503, statusCode)
+	assert.Equal(t, 120, durationMs)
+	assert.Equal(t, "http_5xx", errorClass)
+}
+
+func TestTargetRepository_GetResults(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewTargetRepository(tx)
+
+	userRepo := authRepo.NewUserRepository(tx)
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	created, err := repo.Create(context.Background(), model.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{URL: "example.org", Status: "up", Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+	})
+	assert.NoError(t, err)
+
+	for _, statusCode := range []int{200, 503, 200} {
+		result := core.Result{Status: "up", StatusCode: statusCode, Duration: 80 * time.Millisecond, CheckedAt: time.Now()}
+		assert.NoError(t, repo.SaveResult(context.Background(), created.ID, result))
+	}
+
+	results, err := repo.GetResults(context.Background(), created.ID, 0, 2)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Greater(t, results[0].ID, results[1].ID)
+	assert.Equal(t, 80*time.Millisecond, results[0].Duration)
+
+	older, err := repo.GetResults(context.Background(), created.ID, results[1].ID, 2)
+	assert.NoError(t, err)
+	assert.Len(t, older, 1)
+	assert.Equal(t, 200, older[0].StatusCode)
+}
+
 // Fix TestTargetRepository_GetAll
 func TestTargetRepository_GetAll(t *testing.T) {
 	tx := testutil.GetTestTx(t)
@@ -265,7 +484,7 @@ func TestTargetRepository_GetAll(t *testing.T) {
 
 	// Create test user
 	userRepo := authRepo.NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&authModel.User{
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	})
@@ -308,13 +527,13 @@ func TestTargetRepository_GetAll(t *testing.T) {
 	// Create targets
 	createdTargets := make(map[string]model.UserTarget)
 	for _, target := range targets {
-		created, err := repo.Create(target)
+		created, err := repo.Create(context.Background(), target)
 		assert.NoError(t, err)
 		createdTargets[created.URL] = created
 	}
 
 	// Remove duplicate setupTestTargets call
-	allTargets, err := repo.GetAll()
+	allTargets, err := repo.GetAll(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, allTargets, len(createdTargets))
 
@@ -342,13 +561,13 @@ func TestTargetRepository_GetAllByUserID(t *testing.T) {
 
 	// Create test user
 	userRepo := authRepo.NewUserRepository(tx)
-	user1, err := userRepo.SaveUser(&authModel.User{
+	user1, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test1@example.com",
 		Password: "hashedpassword",
 	})
 	assert.NoError(t, err)
 
-	user2, err := userRepo.SaveUser(&authModel.User{
+	user2, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test2@example.com",
 		Password: "hashedpassword",
 	})
@@ -390,7 +609,7 @@ func TestTargetRepository_GetAllByUserID(t *testing.T) {
 	}
 
 	for _, target := range targets {
-		_, err := repo.Create(target)
+		_, err := repo.Create(context.Background(), target)
 		assert.NoError(t, err)
 	}
 
@@ -422,7 +641,7 @@ func TestTargetRepository_GetAllByUserID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			targets, err := repo.GetAllByUserID(tt.userID)
+			targets, err := repo.GetAllByUserID(context.Background(), tt.userID)
 			assert.NoError(t, err)
 			assert.Len(t, targets, tt.expectedCount)
 
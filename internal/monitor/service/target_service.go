@@ -3,15 +3,22 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	alertService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/pkg/textutil"
 )
 
 // Common errors returned by the target service.
@@ -26,45 +33,70 @@ var (
 	ErrTargetLimitReached = errors.New("maximum number of targets (5) reached")
 )
 
+// snippetLimit bounds how much of a failed response body is quoted in a notification
+const snippetLimit = 200
+
+// MaxFlapWindow bounds the window status changes are counted over to detect flapping
+const MaxFlapWindow = 24 * time.Hour
+
+// MaintenanceService is the part of the maintenance window service that checks rely on
+type MaintenanceService interface {
+	InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error)
+}
+
 // TargetServiceInterface defines the contract for managing monitoring targets.
 // It provides methods for CRUD operations and monitoring initialization.
 type TargetServiceInterface interface {
 	// Create adds a new monitoring target for a user.
 	// Returns the created target or an error if the operation fails.
 	// Possible errors: ErrInvalidInput if parameters are invalid.
-	Create(userID int, url string, interval time.Duration) (model.UserTarget, error)
+	Create(ctx context.Context, userID int, url string, interval time.Duration) (model.UserTarget, error)
 
 	// GetByID retrieves a target by its ID and verifies user ownership.
 	// Returns the target or an error if not found or unauthorized.
 	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
-	GetByID(id int, userID int) (model.UserTarget, error)
+	GetByID(ctx context.Context, id int, userID int) (model.UserTarget, error)
 
 	// GetAll retrieves all monitoring targets in the system.
 	// Returns a slice of targets or an error if the operation fails.
-	GetAll() ([]model.UserTarget, error)
+	GetAll(ctx context.Context) ([]model.UserTarget, error)
 
 	// GetAllByUserID retrieves all monitoring targets owned by a specific user.
 	// Returns a slice of targets or an error if the operation fails.
-	GetAllByUserID(userID int) ([]model.UserTarget, error)
+	GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error)
 
 	// Update modifies an existing target's properties after verifying ownership.
 	// Returns the updated target or an error if the operation fails.
 	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
-	Update(target model.UserTarget, userID int) (model.UserTarget, error)
+	Update(ctx context.Context, target model.UserTarget, userID int) (model.UserTarget, error)
 
 	// Delete removes a target after verifying ownership.
 	// Returns an error if the operation fails or user is not authorized.
 	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
-	Delete(id int, userID int) error
+	Delete(ctx context.Context, id int, userID int) error
 
 	// InitializeMonitoring starts monitoring for all existing targets.
 	// Returns an error if initialization fails.
-	InitializeMonitoring() error
+	InitializeMonitoring(ctx context.Context) error
 
 	// ToggleEnabled toggles the monitoring state for a target after verifying ownership.
 	// Returns the updated target or an error if the operation fails.
 	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
-	ToggleEnabled(id int, userID int) (model.UserTarget, error)
+	ToggleEnabled(ctx context.Context, id int, userID int) (model.UserTarget, error)
+
+	// PauseFromSlack skips the checks of a target until the given time, on
+	// behalf of its owner. Used by buttons on Slack notifications.
+	// Possible errors: ErrTargetNotFound, ErrInvalidInput.
+	PauseFromSlack(ctx context.Context, id int, until time.Time) (model.UserTarget, error)
+
+	// Pause skips the checks of a target until the given time after verifying ownership.
+	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
+	Pause(ctx context.Context, id int, userID int, until time.Time) (model.UserTarget, error)
+
+	// GetResults returns up to limit check results of a target, newest first,
+	// after verifying ownership. A non-zero beforeID continues an earlier page.
+	// Possible errors: ErrTargetNotFound, ErrUnauthorized, ErrInvalidInput.
+	GetResults(ctx context.Context, id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error)
 }
 
 var _ TargetServiceInterface = (*TargetService)(nil)
@@ -77,14 +109,23 @@ type TargetService struct {
 	manager *monitor.Manager
 	// notifierService handles notifications when target status changes
 	notifierService alertService.NotifierServiceInterface
+	// incidentService opens and resolves incidents when target status changes
+	incidentService incidentService.IncidentServiceInterface
+	// maintenanceService tells which checks fall in a maintenance window, nil when there are none
+	maintenanceService MaintenanceService
+	// baseURL prefixes the dashboard links added to notifications
+	baseURL string
 }
 
 // NewTargetService creates a new instance of TargetService with the provided dependencies.
 // It initializes a new monitor manager and returns the service instance.
-func NewTargetService(repo repository.TargetRepositoryInterface, notifierService alertService.NotifierServiceInterface) *TargetService {
+func NewTargetService(repo repository.TargetRepositoryInterface, notifierService alertService.NotifierServiceInterface, incidentService incidentService.IncidentServiceInterface, maintenanceService MaintenanceService, baseURL string) *TargetService {
 	s := &TargetService{
-		repo:            repo,
-		notifierService: notifierService,
+		repo:               repo,
+		notifierService:    notifierService,
+		incidentService:    incidentService,
+		maintenanceService: maintenanceService,
+		baseURL:            baseURL,
 	}
 	s.initializeManager()
 	return s
@@ -109,44 +150,371 @@ func (s *TargetService) validateTarget(userID int, url string, interval time.Dur
 	return nil
 }
 
+// validateFlapSettings checks how often the status of a target may change
+// before it is flapping. Settings left at zero take the defaults.
+func validateFlapSettings(target *monitor.Target) error {
+	if target.FlapThreshold == 0 {
+		target.FlapThreshold = monitor.DefaultFlapThreshold
+	}
+	if target.FlapWindow == 0 {
+		target.FlapWindow = monitor.DefaultFlapWindow
+	}
+	if target.FlapThreshold < 1 {
+		return fmt.Errorf("%w: flap threshold must be at least 1", ErrInvalidInput)
+	}
+	if target.FlapWindow < time.Minute || target.FlapWindow > MaxFlapWindow {
+		return fmt.Errorf("%w: flap window must be between 1 minute and %s", ErrInvalidInput, MaxFlapWindow)
+	}
+	return nil
+}
+
 // handleStatusUpdate processes status changes for a target.
-// It updates the target's status in the repository and notifies observers of the change.
+// It updates the target's status in the repository, opens or resolves the target's
+// incident and notifies observers of the change.
 // Returns an error if the status update fails or if notification configuration fails.
-func (s *TargetService) handleStatusUpdate(target *monitor.Target, status string) error {
+func (s *TargetService) handleStatusUpdate(ctx context.Context, target *monitor.Target, status string) error {
 	if target == nil || status == "" {
 		return fmt.Errorf("%w: target or status is nil", ErrInvalidInput)
 	}
 
-	if err := s.repo.UpdateStatus(target, status); err != nil {
+	// Until the update below, the stored target still holds the status being left
+	var previous *model.UserTarget
+	stored, err := s.repo.GetByID(ctx, target.ID)
+	if err != nil {
+		slog.Error("Failed to get previous status", "target", target.ID, "error", err)
+	} else {
+		previous = &stored
+	}
+
+	// A flapping target keeps the flapping status until it stabilizes
+	recorded := status
+	if target.Flapping {
+		recorded = "flapping"
+	}
+	if err := s.repo.UpdateStatus(ctx, target, recorded); err != nil {
 		if errors.Is(err, repository.ErrTargetNotFound) {
 			return fmt.Errorf("%w: target %s not found", ErrTargetNotFound, target.URL)
 		}
 		return fmt.Errorf("failed to update target status: %w", err)
 	}
 
-	if err := s.notifierService.ConfigureObservers(target.ID); err != nil {
-		return fmt.Errorf("failed to configure observers: %w", err)
+	// A flapping target is reported once by handleFlap instead of on every change.
+	// Planned downtime is not reported at all, and a target behind a failing
+	// parent is folded into the parent's incident.
+	if target.Flapping || status == "maintenance" {
+		return nil
+	}
+	if status == "unreachable-dependency" {
+		s.recordDependent(ctx, target)
+		return nil
+	}
+
+	// A failure to track the incident must not hold back the notification itself
+	incident, err := s.incidentService.HandleStatusChange(ctx, target.ID, status)
+	if err != nil {
+		slog.Error("Failed to track incident", "target", target.ID, "status", status, "error", err)
+	}
+
+	// Coming back up after a maintenance window, or once a parent recovered,
+	// is only news when it resolves an incident that was open before
+	if previous != nil && (previous.Status == "maintenance" || previous.Status == "unreachable-dependency") &&
+		status == "up" && incident == nil {
+		return nil
 	}
 
 	state := notifCore.State{
 		Name:      target.URL,
+		URL:       target.URL,
+		TargetID:  target.ID,
 		Status:    status,
 		UpdatedAt: time.Now(),
-		Message:   fmt.Sprintf("Target %s is %s", target.URL, status),
+		Message:   statusMessage(target, status, previous),
+		Error:     statusError(target, status),
+	}
+	if previous != nil {
+		state.PreviousStatus = previous.Status
+		state.Duration = target.StatusChangedAt.Sub(previous.StatusChangedAt)
+	}
+	if incident != nil {
+		state.IncidentID = incident.ID
+		state.Link = s.baseURL + incident.Path()
+	}
+
+	errs, err := s.notifierService.NotifyTarget(ctx, target.ID, state)
+	if err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+
+	if incident != nil {
+		if err := s.incidentService.RecordNotification(ctx, incident.ID, status, len(errs)); err != nil {
+			slog.Error("Failed to record notification", "incident", incident.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// statusMessage describes a status change. Failures carry the details of the
+// check that confirmed them, recoveries summarize the downtime they end.
+func statusMessage(target *monitor.Target, status string, previous *model.UserTarget) string {
+	switch status {
+	case "down", "error":
+		return failureMessage(target.URL, status, target.LastResult)
+	case "up":
+		if previous != nil && (previous.Status == "down" || previous.Status == "error") {
+			return recoveryMessage(target, target.StatusChangedAt.Sub(previous.StatusChangedAt))
+		}
+	}
+	return fmt.Sprintf("Target %s is %s", target.URL, status)
+}
+
+// statusError summarizes what went wrong: the failing check for failures and
+// the first failure for recoveries
+func statusError(target *monitor.Target, status string) string {
+	switch status {
+	case "down", "error":
+		return resultError(target.LastResult)
+	case "up":
+		if target.FirstFailure != nil {
+			return resultError(*target.FirstFailure)
+		}
+	}
+	return ""
+}
+
+func resultError(result monitor.Result) string {
+	if result.Err != nil {
+		return result.Err.Error()
+	}
+	if result.StatusCode > 0 {
+		return fmt.Sprintf("HTTP %d %s", result.StatusCode, http.StatusText(result.StatusCode))
+	}
+	return ""
+}
+
+func failureMessage(url string, status string, result monitor.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Target %s is %s", url, status)
+	if result.StatusCode > 0 {
+		fmt.Fprintf(&b, "\nHTTP status: %d %s", result.StatusCode, http.StatusText(result.StatusCode))
+	}
+	if class := result.ErrorClass(); class != "" {
+		fmt.Fprintf(&b, "\nError class: %s", class)
+	}
+	if result.Err != nil {
+		fmt.Fprintf(&b, "\nError: %v", result.Err)
+	}
+	if result.Body != "" {
+		fmt.Fprintf(&b, "\nResponse: %s", textutil.Truncate(result.Body, snippetLimit))
+	}
+	return b.String()
+}
+
+func recoveryMessage(target *monitor.Target, downtime time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Target %s is up", target.URL)
+	if downtime > 0 {
+		fmt.Fprintf(&b, " after being down for %s", downtime.Truncate(time.Second))
+	}
+	if first := target.FirstFailure; first != nil {
+		fmt.Fprintf(&b, "\nFirst error: %s (%s)", resultError(*first), first.ErrorClass())
+	}
+	if target.Failures > 0 {
+		fmt.Fprintf(&b, "\nFailed checks: %d", target.Failures)
+	}
+	return b.String()
+}
+
+// handleFlap sends a single summary when a target starts flapping, keeping its
+// incident open meanwhile, and another one once the target has stabilized.
+// The stored status is flapping until then.
+func (s *TargetService) handleFlap(ctx context.Context, target *monitor.Target, flapping bool) error {
+	status, previous := target.Status, "flapping"
+	message := fmt.Sprintf("Target %s has stabilized and is %s after %d status changes while flapping",
+		target.URL, target.Status, target.FlapChanges)
+	if flapping {
+		status = "flapping"
+		previous = target.Status
+		message = fmt.Sprintf("Target %s is flapping: %d status changes within %s. Notifications are paused until it stabilizes.",
+			target.URL, target.FlapChanges, target.FlapWindow)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, target, status); err != nil {
+		return fmt.Errorf("failed to update target status: %w", err)
+	}
+
+	incident, err := s.incidentService.HandleStatusChange(ctx, target.ID, status)
+	if err != nil {
+		slog.Error("Failed to track incident", "target", target.ID, "status", status, "error", err)
+	}
+
+	state := notifCore.State{
+		Name:           target.URL,
+		URL:            target.URL,
+		TargetID:       target.ID,
+		Status:         status,
+		PreviousStatus: previous,
+		UpdatedAt:      time.Now(),
+		Message:        message,
+		Error:          statusError(target, target.Status),
+	}
+	if incident != nil {
+		state.IncidentID = incident.ID
+		state.Link = s.baseURL + incident.Path()
+	}
+
+	errs, err := s.notifierService.NotifyTarget(ctx, target.ID, state)
+	if err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+
+	if incident != nil {
+		if err := s.incidentService.RecordNotification(ctx, incident.ID, status, len(errs)); err != nil {
+			slog.Error("Failed to record notification", "incident", incident.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// handleCheck stores the result of every check and adds the checks of a
+// failing target to the timeline of its incident
+func (s *TargetService) handleCheck(ctx context.Context, target *monitor.Target, result monitor.Result) {
+	if err := s.repo.SaveResult(ctx, target.ID, result); err != nil {
+		slog.Error("Failed to save check result", "target", target.ID, "error", err)
+	}
+
+	// Healthy targets have no unresolved incident, so there is nothing to
+	// record, and neither planned downtime nor a failing parent is part of one
+	if (result.Status == "up" && target.Status == "up") || result.Status == "maintenance" ||
+		result.Status == "unreachable-dependency" {
+		return
 	}
 
-	s.notifierService.GetSubject().Notify(state)
+	if err := s.incidentService.RecordCheck(ctx, target.ID, result); err != nil {
+		slog.Error("Failed to record check", "target", target.ID, "error", err)
+	}
+}
 
+// inMaintenance reports whether a check falls in one of the target's
+// maintenance windows. A failed lookup counts as no window, so that alerts
+// are not lost.
+func (s *TargetService) inMaintenance(ctx context.Context, target *monitor.Target, at time.Time) bool {
+	if s.maintenanceService == nil {
+		return false
+	}
+
+	inMaintenance, err := s.maintenanceService.InMaintenance(ctx, target.ID, at)
+	if err != nil {
+		slog.Error("Failed to check maintenance windows", "target", target.ID, "error", err)
+		return false
+	}
+	return inMaintenance
+}
+
+// failingParent returns a monitored parent of the target that is failing,
+// nil when the target is reachable through all of its parents
+func (s *TargetService) failingParent(target *monitor.Target) *monitor.Target {
+	return s.findFailingParent(target, map[int]bool{target.ID: true})
+}
+
+// findFailingParent is failingParent leaving out the targets in skip. Status
+// updates run with the target locked, so it must never be asked for its own
+// status, even when its parents were saved into a cycle.
+func (s *TargetService) findFailingParent(target *monitor.Target, skip map[int]bool) *monitor.Target {
+	for _, id := range target.ParentIDs {
+		if skip[id] {
+			continue
+		}
+		if parent, ok := s.manager.Get(id); ok && parent.Failing() {
+			return parent
+		}
+	}
 	return nil
 }
 
-func (s *TargetService) Create(userID int, url string, interval time.Duration) (model.UserTarget, error) {
+// recordDependent adds an unreachable target to the incident of the failing
+// target it depends on. When the parent is itself unreachable, the incident
+// is the one of the ancestor that is actually failing.
+func (s *TargetService) recordDependent(ctx context.Context, target *monitor.Target) {
+	skip := map[int]bool{target.ID: true}
+	parent := s.findFailingParent(target, skip)
+	if parent == nil {
+		return
+	}
+	for {
+		skip[parent.ID] = true
+		ancestor := s.findFailingParent(parent, skip)
+		if ancestor == nil {
+			break
+		}
+		parent = ancestor
+	}
+
+	if err := s.incidentService.RecordDependent(ctx, parent.ID, target.URL); err != nil {
+		slog.Error("Failed to record dependent target", "target", target.ID, "parent", parent.ID, "error", err)
+	}
+}
+
+// validateParents checks that the parents of a target are other targets of
+// its owner, and that depending on them does not close a cycle. The owner's
+// targets are read in the transaction the target is written in.
+func validateParents(userTarget model.UserTarget, targets []model.UserTarget) error {
+	parents := make(map[int][]int, len(targets))
+	urls := make(map[int]string, len(targets))
+	for _, target := range targets {
+		parents[target.ID] = target.ParentIDs
+		urls[target.ID] = target.URL
+	}
+	for _, id := range userTarget.ParentIDs {
+		if _, ok := parents[id]; !ok {
+			return fmt.Errorf("%w: parent target %d not found", ErrInvalidInput, id)
+		}
+	}
+	parents[userTarget.ID] = userTarget.ParentIDs
+	urls[userTarget.ID] = userTarget.URL
+
+	if cycle := dependencyCycle(userTarget.ID, parents); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, id := range cycle {
+			names[i] = urls[id]
+		}
+		return fmt.Errorf("%w: dependencies would form a cycle: %s", ErrInvalidInput, strings.Join(names, " → "))
+	}
+	return nil
+}
+
+// dependencyCycle follows the parents of a target and returns the chain of
+// targets leading back to it, starting and ending with the target, or nil
+// when there is no such chain
+func dependencyCycle(targetID int, parents map[int][]int) []int {
+	visited := make(map[int]bool)
+	var walk func(id int, path []int) []int
+	walk = func(id int, path []int) []int {
+		for _, parent := range parents[id] {
+			if parent == targetID {
+				return append(path, parent)
+			}
+			if visited[parent] {
+				continue
+			}
+			visited[parent] = true
+			if cycle := walk(parent, append(path, parent)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(targetID, []int{targetID})
+}
+
+func (s *TargetService) Create(ctx context.Context, userID int, url string, interval time.Duration) (model.UserTarget, error) {
 	if err := s.validateTarget(userID, url, interval); err != nil {
 		return model.UserTarget{}, err
 	}
 
 	// Check if user has reached the target limit
-	existingTargets, err := s.GetAllByUserID(userID)
+	existingTargets, err := s.GetAllByUserID(ctx, userID)
 	if err != nil {
 		return model.UserTarget{}, fmt.Errorf("failed to check target limit: %w", err)
 	}
@@ -157,16 +525,22 @@ func (s *TargetService) Create(userID int, url string, interval time.Duration) (
 	userTarget := model.UserTarget{
 		UserID: userID,
 		Target: &monitor.Target{
-			URL:      url,
-			Interval: interval,
-			Enabled:  true,
-			Status:   "pending",
+			URL:           url,
+			Interval:      interval,
+			Enabled:       true,
+			Status:        "pending",
+			FlapThreshold: monitor.DefaultFlapThreshold,
+			FlapWindow:    monitor.DefaultFlapWindow,
 		},
 	}
 
 	userTarget.Target.OnStatusUpdate = s.handleStatusUpdate
+	userTarget.Target.OnCheck = s.handleCheck
+	userTarget.Target.OnFlap = s.handleFlap
+	userTarget.Target.InMaintenance = s.inMaintenance
+	userTarget.Target.FailingParent = s.failingParent
 
-	newUserTarget, err := s.repo.Create(userTarget)
+	newUserTarget, err := s.repo.Create(ctx, userTarget)
 	if err != nil {
 		if errors.Is(err, repository.ErrTargetNotFound) {
 			return model.UserTarget{}, fmt.Errorf("%w: %v", ErrTargetNotFound, err)
@@ -182,12 +556,12 @@ func (s *TargetService) Create(userID int, url string, interval time.Duration) (
 	return newUserTarget, nil
 }
 
-func (s *TargetService) GetByID(id int, userID int) (model.UserTarget, error) {
+func (s *TargetService) GetByID(ctx context.Context, id int, userID int) (model.UserTarget, error) {
 	if id <= 0 || userID <= 0 {
 		return model.UserTarget{}, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
 	}
 
-	userTarget, err := s.repo.GetByID(id)
+	userTarget, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrTargetNotFound) {
 			return model.UserTarget{}, fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, id)
@@ -202,22 +576,22 @@ func (s *TargetService) GetByID(id int, userID int) (model.UserTarget, error) {
 	return userTarget, nil
 }
 
-func (s *TargetService) GetAll() ([]model.UserTarget, error) {
-	userTargets, err := s.repo.GetAll()
+func (s *TargetService) GetAll(ctx context.Context) ([]model.UserTarget, error) {
+	userTargets, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch targets: %w", err)
 	}
 	return userTargets, nil
 }
 
-func (s *TargetService) GetAllByUserID(userID int) ([]model.UserTarget, error) {
+func (s *TargetService) GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error) {
 	if userID <= 0 {
 		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
 	}
-	return s.repo.GetAllByUserID(userID)
+	return s.repo.GetAllByUserID(ctx, userID)
 }
 
-func (s *TargetService) Update(userTarget model.UserTarget, userID int) (model.UserTarget, error) {
+func (s *TargetService) Update(ctx context.Context, userTarget model.UserTarget, userID int) (model.UserTarget, error) {
 	if err := s.validateTarget(userID, userTarget.URL, userTarget.Interval); err != nil {
 		return model.UserTarget{}, err
 	}
@@ -226,11 +600,34 @@ func (s *TargetService) Update(userTarget model.UserTarget, userID int) (model.U
 		return model.UserTarget{}, fmt.Errorf("%w: user %d does not own target %d", ErrUnauthorized, userID, userTarget.ID)
 	}
 
-	userTarget.OnStatusUpdate = s.handleStatusUpdate
+	if err := validateFlapSettings(userTarget.Target); err != nil {
+		return model.UserTarget{}, err
+	}
+
+	slices.Sort(userTarget.ParentIDs)
+	userTarget.ParentIDs = slices.Compact(userTarget.ParentIDs)
 
-	// First update the target in the database
-	updatedUserTarget, err := s.repo.Update(userTarget)
+	userTarget.OnStatusUpdate = s.handleStatusUpdate
+	userTarget.OnCheck = s.handleCheck
+	userTarget.OnFlap = s.handleFlap
+	userTarget.InMaintenance = s.inMaintenance
+	userTarget.FailingParent = s.failingParent
+
+	// First update the target in the database. Only adding parents can close
+	// a dependency cycle, which is checked in the same transaction.
+	var updatedUserTarget model.UserTarget
+	var err error
+	if len(userTarget.ParentIDs) == 0 {
+		updatedUserTarget, err = s.repo.Update(ctx, userTarget)
+	} else {
+		updatedUserTarget, err = s.repo.UpdateChecked(ctx, userTarget, func(targets []model.UserTarget) error {
+			return validateParents(userTarget, targets)
+		})
+	}
 	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			return model.UserTarget{}, err
+		}
 		if errors.Is(err, repository.ErrTargetNotFound) {
 			return model.UserTarget{}, fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, userTarget.ID)
 		}
@@ -249,27 +646,31 @@ func (s *TargetService) Update(userTarget model.UserTarget, userID int) (model.U
 	return updatedUserTarget, nil
 }
 
-func (s *TargetService) Delete(id int, userID int) error {
-	userTarget, err := s.GetByID(id, userID)
+func (s *TargetService) Delete(ctx context.Context, id int, userID int) error {
+	userTarget, err := s.GetByID(ctx, id, userID)
 	if err != nil {
 		return err
 	}
 
 	s.manager.RevokeTarget(userTarget.ID)
-	return s.repo.Delete(userTarget.ID)
+	return s.repo.Delete(ctx, userTarget.ID)
 }
 
-func (s *TargetService) ToggleEnabled(id int, userID int) (model.UserTarget, error) {
-	userTarget, err := s.GetByID(id, userID)
+func (s *TargetService) ToggleEnabled(ctx context.Context, id int, userID int) (model.UserTarget, error) {
+	userTarget, err := s.GetByID(ctx, id, userID)
 	if err != nil {
 		return model.UserTarget{}, err
 	}
 
 	userTarget.Enabled = !userTarget.Enabled
 	userTarget.OnStatusUpdate = s.handleStatusUpdate
+	userTarget.OnCheck = s.handleCheck
+	userTarget.OnFlap = s.handleFlap
+	userTarget.InMaintenance = s.inMaintenance
+	userTarget.FailingParent = s.failingParent
 
 	// Update the target in the database
-	updatedUserTarget, err := s.repo.Update(userTarget)
+	updatedUserTarget, err := s.repo.Update(ctx, userTarget)
 	if err != nil {
 		return model.UserTarget{}, fmt.Errorf("failed to update target: %w", err)
 	}
@@ -287,14 +688,79 @@ func (s *TargetService) ToggleEnabled(id int, userID int) (model.UserTarget, err
 	return updatedUserTarget, nil
 }
 
-func (s *TargetService) InitializeMonitoring() error {
-	userTargets, err := s.repo.GetAll()
+// PauseFromSlack skips the checks of a target until the given time. Only
+// channels of the target owner receive the button, so the target is paused
+// on the owner's behalf.
+func (s *TargetService) PauseFromSlack(ctx context.Context, id int, until time.Time) (model.UserTarget, error) {
+	if id <= 0 {
+		return model.UserTarget{}, fmt.Errorf("%w: invalid id", ErrInvalidInput)
+	}
+
+	userTarget, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			return model.UserTarget{}, fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, id)
+		}
+		return model.UserTarget{}, fmt.Errorf("failed to fetch target: %w", err)
+	}
+
+	return s.pause(ctx, userTarget, until)
+}
+
+func (s *TargetService) Pause(ctx context.Context, id int, userID int, until time.Time) (model.UserTarget, error) {
+	userTarget, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return model.UserTarget{}, err
+	}
+
+	return s.pause(ctx, userTarget, until)
+}
+
+func (s *TargetService) GetResults(ctx context.Context, id int, userID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+	if limit <= 0 || beforeID < 0 {
+		return nil, fmt.Errorf("%w: invalid limit or cursor", ErrInvalidInput)
+	}
+
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.GetResults(ctx, id, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check results: %w", err)
+	}
+	return results, nil
+}
+
+// pause stores the end of the pause and hands it to the running monitor
+func (s *TargetService) pause(ctx context.Context, userTarget model.UserTarget, until time.Time) (model.UserTarget, error) {
+	until = until.UTC()
+	userTarget.PausedUntil = &until
+
+	updatedUserTarget, err := s.repo.Update(ctx, userTarget)
+	if err != nil {
+		return model.UserTarget{}, fmt.Errorf("failed to update target: %w", err)
+	}
+
+	if existingTarget, exists := s.manager.Targets[userTarget.ID]; exists {
+		existingTarget.Update(updatedUserTarget.Target)
+	}
+
+	return updatedUserTarget, nil
+}
+
+func (s *TargetService) InitializeMonitoring(ctx context.Context) error {
+	userTargets, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch targets: %w", err)
 	}
 
 	for _, target := range userTargets {
 		target.OnStatusUpdate = s.handleStatusUpdate
+		target.OnCheck = s.handleCheck
+		target.OnFlap = s.handleFlap
+		target.InMaintenance = s.inMaintenance
+		target.FailingParent = s.failingParent
 
 		if err := s.manager.RegisterTarget(target.Target); err != nil {
 			return fmt.Errorf("failed to register target %s: %w", target.URL, err)
@@ -1,13 +1,16 @@
 package service
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	alertModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
 	"github.com/stretchr/testify/assert"
@@ -20,74 +23,639 @@ type mockTargetRepository struct {
 	getAllFunc         func() ([]model.UserTarget, error)
 	updateFunc         func(target model.UserTarget) (model.UserTarget, error)
 	deleteFunc         func(id int) error
-	updateStatusFunc   func(target *monitor.Target, status string) error
+	updateStatusFunc   func(ctx context.Context, target *monitor.Target, status string) error
 	getAllByUserIDFunc func(userID int) ([]model.UserTarget, error)
+	saveResultFunc     func(ctx context.Context, targetID int, result monitor.Result) error
+	getResultsFunc     func(targetID int, beforeID int64, limit int) ([]model.CheckResult, error)
 }
 
-func (m *mockTargetRepository) Create(userTarget model.UserTarget) (model.UserTarget, error) {
+func (m *mockTargetRepository) Create(ctx context.Context, userTarget model.UserTarget) (model.UserTarget, error) {
 	return m.createFunc(userTarget)
 }
 
-func (m *mockTargetRepository) GetByID(id int) (model.UserTarget, error) {
+func (m *mockTargetRepository) GetByID(ctx context.Context, id int) (model.UserTarget, error) {
 	return m.getByIDFunc(id)
 }
 
-func (m *mockTargetRepository) GetAll() ([]model.UserTarget, error) {
+func (m *mockTargetRepository) GetAll(ctx context.Context) ([]model.UserTarget, error) {
 	return m.getAllFunc()
 }
 
-func (m *mockTargetRepository) Update(target model.UserTarget) (model.UserTarget, error) {
+func (m *mockTargetRepository) Update(ctx context.Context, target model.UserTarget) (model.UserTarget, error) {
 	return m.updateFunc(target)
 }
 
-func (m *mockTargetRepository) Delete(id int) error {
+func (m *mockTargetRepository) UpdateChecked(ctx context.Context, target model.UserTarget, check func(targets []model.UserTarget) error) (model.UserTarget, error) {
+	targets, err := m.getAllByUserIDFunc(target.UserID)
+	if err != nil {
+		return model.UserTarget{}, err
+	}
+	if err := check(targets); err != nil {
+		return model.UserTarget{}, err
+	}
+	return m.updateFunc(target)
+}
+
+func (m *mockTargetRepository) Delete(ctx context.Context, id int) error {
 	return m.deleteFunc(id)
 }
 
-func (m *mockTargetRepository) UpdateStatus(target *monitor.Target, status string) error {
-	return m.updateStatusFunc(target, status)
+func (m *mockTargetRepository) UpdateStatus(ctx context.Context, target *monitor.Target, status string) error {
+	return m.updateStatusFunc(ctx, target, status)
+}
+
+func (m *mockTargetRepository) GetResults(ctx context.Context, targetID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+	return m.getResultsFunc(targetID, beforeID, limit)
+}
+
+func (m *mockTargetRepository) SaveResult(ctx context.Context, targetID int, result monitor.Result) error {
+	if m.saveResultFunc != nil {
+		return m.saveResultFunc(ctx, targetID, result)
+	}
+	return nil
 }
 
-func (m *mockTargetRepository) GetAllByUserID(userID int) ([]model.UserTarget, error) {
+func (m *mockTargetRepository) GetAllByUserID(ctx context.Context, userID int) ([]model.UserTarget, error) {
 	return m.getAllByUserIDFunc(userID)
 }
 
 type mockNotifierService struct {
-	configureObserversFunc func(targetID int) error
+	notifyTargetFunc func(targetID int, state notifCore.State) ([]error, error)
 }
 
-func (m *mockNotifierService) ConfigureObservers(targetID int) error {
-	return m.configureObserversFunc(targetID)
+func (m *mockNotifierService) NotifyTarget(ctx context.Context, targetID int, state notifCore.State) ([]error, error) {
+	if m.notifyTargetFunc == nil {
+		return nil, nil
+	}
+	return m.notifyTargetFunc(targetID, state)
 }
 
-func (m *mockNotifierService) Create(notifier *alertModel.Notifier) error {
+func (m *mockNotifierService) Create(ctx context.Context, notifier *alertModel.Notifier, userID int) error {
 	return nil
 }
 
-func (m *mockNotifierService) Get(id int) (*alertModel.Notifier, error) {
+func (m *mockNotifierService) Get(ctx context.Context, id int, userID int) (*alertModel.Notifier, error) {
+	return nil, nil
+}
+
+func (m *mockNotifierService) GetByUserID(ctx context.Context, userID int) ([]*alertModel.Notifier, error) {
+	return nil, nil
+}
+
+func (m *mockNotifierService) GetByTargetID(ctx context.Context, targetID int, userID int) ([]*alertModel.Notifier, error) {
 	return nil, nil
 }
 
-func (m *mockNotifierService) Update(id int, config json.RawMessage) (*alertModel.Notifier, error) {
+func (m *mockNotifierService) Update(ctx context.Context, notifier *alertModel.Notifier, userID int) (*alertModel.Notifier, error) {
 	return nil, nil
 }
 
-func (m *mockNotifierService) Delete(id int) error {
+func (m *mockNotifierService) Attach(ctx context.Context, targetID int, notifierID int, userID int) error {
+	return nil
+}
+
+func (m *mockNotifierService) Detach(ctx context.Context, targetID int, notifierID int, userID int) error {
 	return nil
 }
 
-func (m *mockNotifierService) GetSubject() *notifCore.Subject {
+func (m *mockNotifierService) AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockNotifierService) AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockNotifierService) Delete(ctx context.Context, id int, userID int) error {
 	return nil
 }
 
-func (m *mockNotifierService) HandleSlackCallback(code string, targetID int) (*alertModel.Notifier, error) {
+func (m *mockNotifierService) HandleSlackCallback(ctx context.Context, code string, userID int) (*alertModel.Notifier, error) {
 	return nil, nil
 }
 
-func (m *mockNotifierService) ParseOAuthState(state string) (int, error) {
+func (m *mockNotifierService) ResolveSlackUser(ctx context.Context, teamID string, slackUserID string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockNotifierService) NewOAuthState(ctx context.Context, targetID int, userID int) (string, error) {
+	return "", nil
+}
+
+func (m *mockNotifierService) ParseOAuthState(ctx context.Context, state string, userID int) (int, error) {
 	return 0, nil
 }
 
+type mockIncidentService struct {
+	handleStatusChangeFunc func(targetID int, status string) (*incidentModel.Incident, error)
+	recordCheckFunc        func(targetID int, result monitor.Result) error
+	recordNotificationFunc func(incidentID int, status string, failed int) error
+	recordDependentFunc    func(targetID int, dependentURL string) error
+}
+
+func (m *mockIncidentService) HandleStatusChange(ctx context.Context, targetID int, status string) (*incidentModel.Incident, error) {
+	return m.handleStatusChangeFunc(targetID, status)
+}
+
+func (m *mockIncidentService) RecordCheck(ctx context.Context, targetID int, result monitor.Result) error {
+	return m.recordCheckFunc(targetID, result)
+}
+
+func (m *mockIncidentService) RecordDependent(ctx context.Context, targetID int, dependentURL string) error {
+	return m.recordDependentFunc(targetID, dependentURL)
+}
+
+func (m *mockIncidentService) RecordNotification(ctx context.Context, incidentID int, status string, failed int) error {
+	return m.recordNotificationFunc(incidentID, status, failed)
+}
+
+func (m *mockIncidentService) Acknowledge(ctx context.Context, id int, userID int) error {
+	return nil
+}
+
+func (m *mockIncidentService) AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error {
+	return nil
+}
+
+func (m *mockIncidentService) Comment(ctx context.Context, id int, userID int, message string) error {
+	return nil
+}
+
+func (m *mockIncidentService) Get(ctx context.Context, id int, userID int) (*incidentModel.Incident, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) Search(ctx context.Context, userID int, filter incidentModel.Filter) ([]*incidentModel.Incident, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*incidentModel.Incident, error) {
+	return nil, nil
+}
+
+func TestTargetService_HandleStatusUpdate(t *testing.T) {
+	var updated string
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{}, fmt.Errorf("database error")
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error {
+			updated = status
+			return nil
+		},
+	}
+	subject := notifCore.NewSubject()
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	var incidentStatuses []string
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) {
+			incidentStatuses = append(incidentStatuses, status)
+			return nil, fmt.Errorf("database error")
+		},
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "")
+
+	target := &monitor.Target{ID: 1, URL: "https://example.com"}
+
+	// Incident tracking failures don't stop the status update
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "down"))
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "up"))
+	assert.Equal(t, "up", updated)
+	assert.Equal(t, []string{"down", "up"}, incidentStatuses)
+
+	assert.ErrorIs(t, service.handleStatusUpdate(context.Background(), nil, "down"), ErrInvalidInput)
+}
+
+type stateRecorder struct {
+	states []notifCore.State
+}
+
+func (r *stateRecorder) Notify(state notifCore.State) error {
+	r.states = append(r.states, state)
+	return nil
+}
+
+func TestTargetService_HandleStatusUpdateRecordsNotification(t *testing.T) {
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{Target: &monitor.Target{ID: id, Status: "up"}}, nil
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error { return nil },
+	}
+	recorder := &stateRecorder{}
+	subject := notifCore.NewSubject()
+	subject.Attach(recorder)
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	var recorded []int
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) {
+			return &incidentModel.Incident{ID: 9, TargetID: targetID}, nil
+		},
+		recordNotificationFunc: func(incidentID int, status string, failed int) error {
+			recorded = append(recorded, incidentID)
+			return nil
+		},
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "https://uptimebot.example")
+
+	target := &monitor.Target{ID: 1, URL: "https://example.com", LastResult: monitor.Result{Status: "down", StatusCode: 503}}
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "down"))
+
+	assert.Len(t, recorder.states, 1)
+	state := recorder.states[0]
+	assert.Equal(t, 9, state.IncidentID)
+	assert.Equal(t, "https://example.com", state.URL)
+	assert.Equal(t, "up", state.PreviousStatus)
+	assert.Equal(t, "HTTP 503 Service Unavailable", state.Error)
+	assert.Equal(t, "https://uptimebot.example/app/incidents/9", state.Link)
+	assert.Equal(t, []int{9}, recorded)
+}
+
+func TestTargetService_HandleFlap(t *testing.T) {
+	var updated []string
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{Target: &monitor.Target{ID: id, Status: "up"}}, nil
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error {
+			updated = append(updated, status)
+			return nil
+		},
+	}
+	recorder := &stateRecorder{}
+	subject := notifCore.NewSubject()
+	subject.Attach(recorder)
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	var changes []string
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) {
+			changes = append(changes, status)
+			return &incidentModel.Incident{ID: 4, TargetID: targetID}, nil
+		},
+		recordNotificationFunc: func(incidentID int, status string, failed int) error { return nil },
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "")
+
+	target := &monitor.Target{ID: 1, URL: "https://example.com", Status: "error", Flapping: true, FlapChanges: 6, FlapWindow: 15 * time.Minute}
+
+	// Changes while flapping keep the stored status at flapping, and are
+	// neither notified nor tracked
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "error"))
+	assert.Equal(t, []string{"flapping"}, updated)
+	assert.Empty(t, recorder.states)
+	assert.Empty(t, changes)
+
+	assert.NoError(t, service.handleFlap(context.Background(), target, true))
+	assert.Len(t, recorder.states, 1)
+	assert.Equal(t, "flapping", recorder.states[0].Status)
+	assert.Equal(t, "Target https://example.com is flapping: 6 status changes within 15m0s. "+
+		"Notifications are paused until it stabilizes.", recorder.states[0].Message)
+	assert.Equal(t, 4, recorder.states[0].IncidentID)
+
+	target.Status = "up"
+	target.FlapChanges = 9
+	assert.NoError(t, service.handleFlap(context.Background(), target, false))
+	assert.Len(t, recorder.states, 2)
+	assert.Equal(t, "up", recorder.states[1].Status)
+	assert.Equal(t, "flapping", recorder.states[1].PreviousStatus)
+	assert.Equal(t, "Target https://example.com has stabilized and is up after 9 status changes while flapping", recorder.states[1].Message)
+	assert.Equal(t, []string{"flapping", "up"}, changes)
+	assert.Equal(t, []string{"flapping", "flapping", "up"}, updated, "the stored status is flapping until the target stabilizes")
+}
+
+func TestTargetService_HandleStatusUpdateRecoveryMessage(t *testing.T) {
+	downSince := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{Target: &monitor.Target{ID: id, Status: "down", StatusChangedAt: downSince}}, nil
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error { return nil },
+	}
+	recorder := &stateRecorder{}
+	subject := notifCore.NewSubject()
+	subject.Attach(recorder)
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) { return nil, nil },
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "")
+
+	target := &monitor.Target{
+		ID:              1,
+		URL:             "https://example.com",
+		Status:          "up",
+		StatusChangedAt: downSince.Add(12*time.Minute + 30*time.Second),
+		Failures:        5,
+		FirstFailure:    &monitor.Result{Status: "down", StatusCode: 503},
+	}
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "up"))
+
+	assert.Len(t, recorder.states, 1)
+	assert.Equal(t, "Target https://example.com is up after being down for 12m30s\n"+
+		"First error: HTTP 503 Service Unavailable (http_5xx)\n"+
+		"Failed checks: 5", recorder.states[0].Message)
+	assert.Equal(t, "down", recorder.states[0].PreviousStatus)
+	assert.Equal(t, 12*time.Minute+30*time.Second, recorder.states[0].Duration)
+	assert.Equal(t, "HTTP 503 Service Unavailable", recorder.states[0].Error)
+	assert.Empty(t, recorder.states[0].Link)
+}
+
+func TestStatusMessage(t *testing.T) {
+	t.Run("down with response", func(t *testing.T) {
+		target := &monitor.Target{
+			URL:        "https://example.com",
+			LastResult: monitor.Result{Status: "down", StatusCode: 502, Body: strings.Repeat("a", 250)},
+		}
+		message := statusMessage(target, "down", nil)
+		assert.Equal(t, "Target https://example.com is down\n"+
+			"HTTP status: 502 Bad Gateway\n"+
+			"Error class: http_5xx\n"+
+			"Response: "+strings.Repeat("a", snippetLimit-1)+"…", message)
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		target := &monitor.Target{
+			URL:        "https://example.com",
+			LastResult: monitor.Result{Status: "error", Err: fmt.Errorf("boom")},
+		}
+		message := statusMessage(target, "error", nil)
+		assert.Equal(t, "Target https://example.com is error\nError class: unknown\nError: boom", message)
+	})
+
+	t.Run("first check passing", func(t *testing.T) {
+		target := &monitor.Target{URL: "https://example.com"}
+		previous := &model.UserTarget{Target: &monitor.Target{Status: "pending"}}
+		assert.Equal(t, "Target https://example.com is up", statusMessage(target, "up", previous))
+	})
+
+	t.Run("recovery from connection error", func(t *testing.T) {
+		target := &monitor.Target{
+			URL:          "https://example.com",
+			Failures:     2,
+			FirstFailure: &monitor.Result{Status: "error", Err: fmt.Errorf("dial tcp: connection refused")},
+		}
+		previous := &model.UserTarget{Target: &monitor.Target{Status: "error"}}
+		message := statusMessage(target, "up", previous)
+		assert.Contains(t, message, "First error: dial tcp: connection refused (unknown)")
+		assert.Contains(t, message, "Failed checks: 2")
+	})
+}
+
+func TestTargetService_HandleCheck(t *testing.T) {
+	var checked []string
+	mockIncidentService := &mockIncidentService{
+		recordCheckFunc: func(targetID int, result monitor.Result) error {
+			checked = append(checked, result.Status)
+			return nil
+		},
+	}
+	var saved []string
+	mockRepo := &mockTargetRepository{
+		saveResultFunc: func(ctx context.Context, targetID int, result monitor.Result) error {
+			saved = append(saved, result.Status)
+			return nil
+		},
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, mockIncidentService, nil, "")
+
+	// Every result is stored, but passing checks of a healthy target are
+	// not added to an incident
+	service.handleCheck(context.Background(), &monitor.Target{ID: 1, Status: "up"}, monitor.Result{Status: "up"})
+	service.handleCheck(context.Background(), &monitor.Target{ID: 1, Status: "up"}, monitor.Result{Status: "down"})
+	service.handleCheck(context.Background(), &monitor.Target{ID: 1, Status: "down"}, monitor.Result{Status: "up"})
+	service.handleCheck(context.Background(), &monitor.Target{ID: 1, Status: "maintenance"}, monitor.Result{Status: "maintenance"})
+	service.handleCheck(context.Background(), &monitor.Target{ID: 1, Status: "down"}, monitor.Result{Status: "unreachable-dependency"})
+
+	assert.Equal(t, []string{"down", "up"}, checked)
+	assert.Equal(t, []string{"up", "down", "up", "maintenance", "unreachable-dependency"}, saved)
+}
+
+type mockMaintenanceService struct {
+	inMaintenanceFunc func(targetID int, at time.Time) (bool, error)
+}
+
+func (m *mockMaintenanceService) InMaintenance(ctx context.Context, targetID int, at time.Time) (bool, error) {
+	return m.inMaintenanceFunc(targetID, at)
+}
+
+func TestTargetService_InMaintenance(t *testing.T) {
+	target := &monitor.Target{ID: 1}
+	now := time.Now()
+
+	service := NewTargetService(&mockTargetRepository{}, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+	assert.False(t, service.inMaintenance(context.Background(), target, now))
+
+	service = NewTargetService(&mockTargetRepository{}, &mockNotifierService{}, &mockIncidentService{}, &mockMaintenanceService{
+		inMaintenanceFunc: func(targetID int, at time.Time) (bool, error) { return true, nil },
+	}, "")
+	assert.True(t, service.inMaintenance(context.Background(), target, now))
+
+	// A failed lookup must not swallow alerts
+	service = NewTargetService(&mockTargetRepository{}, &mockNotifierService{}, &mockIncidentService{}, &mockMaintenanceService{
+		inMaintenanceFunc: func(targetID int, at time.Time) (bool, error) { return true, fmt.Errorf("database error") },
+	}, "")
+	assert.False(t, service.inMaintenance(context.Background(), target, now))
+}
+
+func TestTargetService_HandleStatusUpdateMaintenance(t *testing.T) {
+	previous := "down"
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{Target: &monitor.Target{ID: id, Status: previous}}, nil
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error { return nil },
+	}
+	recorder := &stateRecorder{}
+	subject := notifCore.NewSubject()
+	subject.Attach(recorder)
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	var resolved *incidentModel.Incident
+	var incidentStatuses []string
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) {
+			incidentStatuses = append(incidentStatuses, status)
+			return resolved, nil
+		},
+		recordNotificationFunc: func(incidentID int, status string, failed int) error { return nil },
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "")
+	target := &monitor.Target{ID: 1, URL: "https://example.com"}
+
+	// Entering a window is neither notified nor tracked
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "maintenance"))
+	assert.Empty(t, recorder.states)
+	assert.Empty(t, incidentStatuses)
+
+	// Neither is coming back up afterwards
+	previous = "maintenance"
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "up"))
+	assert.Empty(t, recorder.states)
+
+	// Unless that resolves an incident opened before the window
+	resolved = &incidentModel.Incident{ID: 3}
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "up"))
+	assert.Len(t, recorder.states, 1)
+
+	// Failing after the window is an ordinary failure
+	resolved = nil
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "down"))
+	if assert.Len(t, recorder.states, 2) {
+		assert.Equal(t, "maintenance", recorder.states[1].PreviousStatus)
+	}
+	assert.Equal(t, []string{"up", "up", "down"}, incidentStatuses)
+}
+
+func TestTargetService_HandleStatusUpdateDependency(t *testing.T) {
+	previous := "up"
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{Target: &monitor.Target{ID: id, Status: previous}}, nil
+		},
+		updateStatusFunc: func(ctx context.Context, target *monitor.Target, status string) error { return nil },
+	}
+	recorder := &stateRecorder{}
+	subject := notifCore.NewSubject()
+	subject.Attach(recorder)
+	mockNotifierService := &mockNotifierService{
+		notifyTargetFunc: func(targetID int, state notifCore.State) ([]error, error) {
+			return subject.Notify(state), nil
+		},
+	}
+	var folded []int
+	var incidentStatuses []string
+	mockIncidentService := &mockIncidentService{
+		handleStatusChangeFunc: func(targetID int, status string) (*incidentModel.Incident, error) {
+			incidentStatuses = append(incidentStatuses, status)
+			return nil, nil
+		},
+		recordDependentFunc: func(targetID int, dependentURL string) error {
+			folded = append(folded, targetID)
+			return nil
+		},
+	}
+	service := NewTargetService(mockRepo, mockNotifierService, mockIncidentService, nil, "")
+
+	// The load balancer is down, so the gateway behind it is unreachable
+	service.manager.Targets[1] = &monitor.Target{ID: 1, URL: "https://lb.example.com", Status: "down"}
+	service.manager.Targets[2] = &monitor.Target{ID: 2, URL: "https://gateway.example.com", Status: "unreachable-dependency", ParentIDs: []int{1}}
+	target := &monitor.Target{ID: 3, URL: "https://app.example.com", ParentIDs: []int{2}}
+	assert.Same(t, service.manager.Targets[2], service.failingParent(target))
+
+	// The failure is folded into the incident of the load balancer, not notified
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "unreachable-dependency"))
+	assert.Equal(t, []int{1}, folded)
+	assert.Empty(t, recorder.states)
+	assert.Empty(t, incidentStatuses)
+
+	// Nor is coming back up once the load balancer and gateway recovered
+	previous = "unreachable-dependency"
+	service.manager.Targets[1].Status = "up"
+	service.manager.Targets[2].Status = "up"
+	assert.Nil(t, service.failingParent(target))
+	assert.NoError(t, service.handleStatusUpdate(context.Background(), target, "up"))
+	assert.Empty(t, recorder.states)
+	assert.Equal(t, []string{"up"}, incidentStatuses)
+}
+
+func TestTargetService_UpdateParents(t *testing.T) {
+	targets := []model.UserTarget{
+		{UserID: 1, Target: &monitor.Target{ID: 1, URL: "https://lb.example.com"}},
+		{UserID: 1, Target: &monitor.Target{ID: 2, URL: "https://gateway.example.com", ParentIDs: []int{1}}},
+		{UserID: 1, Target: &monitor.Target{ID: 3, URL: "https://app.example.com"}},
+	}
+	mockRepo := &mockTargetRepository{
+		getAllByUserIDFunc: func(userID int) ([]model.UserTarget, error) { return targets, nil },
+		updateFunc:         func(target model.UserTarget) (model.UserTarget, error) { return target, nil },
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+
+	update := func(id int, parentIDs ...int) (model.UserTarget, error) {
+		return service.Update(context.Background(), model.UserTarget{UserID: 1, Target: &monitor.Target{
+			ID:        id,
+			URL:       targets[id-1].URL,
+			Interval:  time.Hour,
+			ParentIDs: parentIDs,
+		}}, 1)
+	}
+
+	t.Run("valid parents", func(t *testing.T) {
+		updated, err := update(3, 2, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, updated.ParentIDs)
+		assert.Equal(t, []int{1, 2}, service.manager.Targets[3].ParentIDs)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := update(1, 2)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.ErrorContains(t, err, "https://lb.example.com → https://gateway.example.com → https://lb.example.com")
+	})
+
+	t.Run("own parent", func(t *testing.T) {
+		_, err := update(1, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		_, err := update(3, 9)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestTargetService_UpdateFlapSettings(t *testing.T) {
+	mockRepo := &mockTargetRepository{
+		updateFunc: func(target model.UserTarget) (model.UserTarget, error) { return target, nil },
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+
+	update := func(threshold int, window time.Duration) (model.UserTarget, error) {
+		return service.Update(context.Background(), model.UserTarget{UserID: 1, Target: &monitor.Target{
+			ID:            1,
+			URL:           "https://example.com",
+			Interval:      time.Minute,
+			FlapThreshold: threshold,
+			FlapWindow:    window,
+		}}, 1)
+	}
+
+	_, err := update(3, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, service.manager.Targets[1].FlapThreshold)
+	assert.Equal(t, time.Hour, service.manager.Targets[1].FlapWindow)
+
+	updated, err := update(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, monitor.DefaultFlapThreshold, updated.FlapThreshold)
+	assert.Equal(t, monitor.DefaultFlapWindow, updated.FlapWindow)
+
+	_, err = update(-1, time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = update(3, time.Second)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = update(3, 2*MaxFlapWindow)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
 func TestTargetService_Create(t *testing.T) {
 	mockRepo := &mockTargetRepository{
 		createFunc: func(userTarget model.UserTarget) (model.UserTarget, error) {
@@ -100,13 +668,13 @@ func TestTargetService_Create(t *testing.T) {
 	}
 	mockNotifierService := &mockNotifierService{}
 
-	service := NewTargetService(mockRepo, mockNotifierService)
+	service := NewTargetService(mockRepo, mockNotifierService, &mockIncidentService{}, nil, "")
 
 	t.Run("Target created successfully", func(t *testing.T) {
 		url := "https://example.com"
 		interval := time.Second * 30
 
-		target, err := service.Create(1, url, interval)
+		target, err := service.Create(context.Background(), 1, url, interval)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, target.ID)
 		assert.Equal(t, url, target.URL)
@@ -123,7 +691,7 @@ func TestTargetService_Create(t *testing.T) {
 			return model.UserTarget{}, fmt.Errorf("database error")
 		}
 
-		_, err := service.Create(1, "https://example.com", time.Second*30)
+		_, err := service.Create(context.Background(), 1, "https://example.com", time.Second*30)
 		assert.Error(t, err)
 	})
 }
@@ -146,7 +714,7 @@ func TestTargetService_Update(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewTargetService(mockRepo, &mockNotifierService{})
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
 
 	t.Run("Update existing target", func(t *testing.T) {
 		// Create and register initial target
@@ -168,7 +736,7 @@ func TestTargetService_Update(t *testing.T) {
 		userTarget.Interval = time.Second * 60
 		userTarget.Enabled = false
 
-		result, err := service.Update(userTarget, userTarget.UserID)
+		result, err := service.Update(context.Background(), userTarget, userTarget.UserID)
 		assert.NoError(t, err)
 		assert.Equal(t, userTarget.URL, result.URL)
 		assert.Equal(t, userTarget.Interval, result.Interval)
@@ -187,7 +755,7 @@ func TestTargetService_Update(t *testing.T) {
 		}
 
 		userTarget := model.UserTarget{UserID: 1, Target: &monitor.Target{ID: 1}}
-		_, err := service.Update(userTarget, userTarget.UserID)
+		_, err := service.Update(context.Background(), userTarget, userTarget.UserID)
 		assert.Error(t, err)
 	})
 
@@ -199,7 +767,7 @@ func TestTargetService_Update(t *testing.T) {
 			Enabled:  true,
 			Status:   "up",
 		}}
-		_, err := service.Update(userTarget, 2)
+		_, err := service.Update(context.Background(), userTarget, 2)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unauthorized")
 	})
@@ -217,7 +785,7 @@ func TestTargetService_Delete(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewTargetService(mockRepo, &mockNotifierService{})
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
 
 	t.Run("Delete existing target", func(t *testing.T) {
 		// Register a target first
@@ -230,7 +798,7 @@ func TestTargetService_Delete(t *testing.T) {
 		err := service.manager.RegisterTarget(target)
 		assert.NoError(t, err)
 
-		err = service.Delete(1, 1) // UserID 1 deleting target 1
+		err = service.Delete(context.Background(), 1, 1) // UserID 1 deleting target 1
 		assert.NoError(t, err)
 		assert.NotContains(t, service.manager.Targets, target.ID)
 	})
@@ -243,7 +811,7 @@ func TestTargetService_Delete(t *testing.T) {
 			}, nil
 		}
 
-		err := service.Delete(1, 1) // UserID 1 trying to delete target owned by UserID 2
+		err := service.Delete(context.Background(), 1, 1) // UserID 1 trying to delete target owned by UserID 2
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unauthorized")
 	})
@@ -260,7 +828,7 @@ func TestTargetService_Delete(t *testing.T) {
 			}, nil
 		}
 
-		err := service.Delete(1, 1)
+		err := service.Delete(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "database error")
 	})
@@ -283,7 +851,7 @@ func TestTargetService_ToggleEnabled(t *testing.T) {
 			return target, nil
 		},
 	}
-	service := NewTargetService(mockRepo, &mockNotifierService{})
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
 
 	t.Run("Toggle target successfully", func(t *testing.T) {
 		// Register initial target
@@ -297,7 +865,7 @@ func TestTargetService_ToggleEnabled(t *testing.T) {
 		assert.NoError(t, err)
 
 		// Toggle the target
-		result, err := service.ToggleEnabled(1, 1)
+		result, err := service.ToggleEnabled(context.Background(), 1, 1)
 		assert.NoError(t, err)
 		assert.False(t, result.Enabled)
 
@@ -314,7 +882,7 @@ func TestTargetService_ToggleEnabled(t *testing.T) {
 			}, nil
 		}
 
-		_, err := service.ToggleEnabled(1, 1) // UserID 1 trying to toggle target owned by UserID 2
+		_, err := service.ToggleEnabled(context.Background(), 1, 1) // UserID 1 trying to toggle target owned by UserID 2
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "unauthorized")
 	})
@@ -330,12 +898,89 @@ func TestTargetService_ToggleEnabled(t *testing.T) {
 			return model.UserTarget{}, fmt.Errorf("database error")
 		}
 
-		_, err := service.ToggleEnabled(1, 1)
+		_, err := service.ToggleEnabled(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "database error")
 	})
 }
 
+func TestTargetService_PauseFromSlack(t *testing.T) {
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			if id != 1 {
+				return model.UserTarget{}, repository.ErrTargetNotFound
+			}
+			return model.UserTarget{
+				UserID: 2,
+				Target: &monitor.Target{ID: id, Enabled: true, URL: "https://example.com", Interval: time.Second * 30},
+			}, nil
+		},
+		updateFunc: func(target model.UserTarget) (model.UserTarget, error) {
+			return target, nil
+		},
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+
+	running := &monitor.Target{ID: 1, Enabled: true, URL: "https://example.com", Interval: time.Second * 30}
+	assert.NoError(t, service.manager.RegisterTarget(running))
+
+	until := time.Now().Add(time.Hour)
+	paused, err := service.PauseFromSlack(context.Background(), 1, until)
+	assert.NoError(t, err)
+	assert.True(t, paused.PausedUntil.Equal(until))
+	assert.True(t, paused.Enabled)
+	assert.True(t, running.Paused(time.Now()))
+
+	_, err = service.PauseFromSlack(context.Background(), 2, until)
+	assert.ErrorIs(t, err, ErrTargetNotFound)
+}
+
+func TestTargetService_Pause(t *testing.T) {
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{
+				UserID: 2,
+				Target: &monitor.Target{ID: id, Enabled: true, URL: "https://example.com", Interval: time.Second * 30},
+			}, nil
+		},
+		updateFunc: func(target model.UserTarget) (model.UserTarget, error) {
+			return target, nil
+		},
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+
+	until := time.Now().Add(30 * time.Minute)
+	paused, err := service.Pause(context.Background(), 1, 2, until)
+	assert.NoError(t, err)
+	assert.True(t, paused.PausedUntil.Equal(until))
+
+	_, err = service.Pause(context.Background(), 1, 3, until)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestTargetService_GetResults(t *testing.T) {
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (model.UserTarget, error) {
+			return model.UserTarget{UserID: 2, Target: &monitor.Target{ID: id}}, nil
+		},
+		getResultsFunc: func(targetID int, beforeID int64, limit int) ([]model.CheckResult, error) {
+			assert.Equal(t, int64(10), beforeID)
+			return []model.CheckResult{{ID: 9, TargetID: targetID, Status: "up"}}, nil
+		},
+	}
+	service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+
+	results, err := service.GetResults(context.Background(), 1, 2, 10, 50)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	_, err = service.GetResults(context.Background(), 1, 3, 10, 50)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.GetResults(context.Background(), 1, 2, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
 func TestTargetService_GetAllByUserID(t *testing.T) {
 	t.Run("successful retrieval", func(t *testing.T) {
 		expectedTargets := []model.UserTarget{
@@ -356,8 +1001,8 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
-		targets, err := service.GetAllByUserID(1)
+		service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+		targets, err := service.GetAllByUserID(context.Background(), 1)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTargets, targets)
@@ -370,8 +1015,8 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
-		targets, err := service.GetAllByUserID(999)
+		service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+		targets, err := service.GetAllByUserID(context.Background(), 999)
 
 		assert.NoError(t, err)
 		assert.Empty(t, targets)
@@ -384,8 +1029,8 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
-		targets, err := service.GetAllByUserID(1)
+		service := NewTargetService(mockRepo, &mockNotifierService{}, &mockIncidentService{}, nil, "")
+		targets, err := service.GetAllByUserID(context.Background(), 1)
 
 		assert.Error(t, err)
 		assert.Nil(t, targets)
@@ -4,10 +4,18 @@ import "time"
 
 // State represents the current state that observers are interested in
 type State struct {
-	Name      string    // Name of what is being observed
-	Status    string    // Current status
-	Message   string    // Additional details
-	UpdatedAt time.Time // When the state was last updated
+	Name           string        // Name of what is being observed
+	Status         string        // Current status
+	Message        string        // Additional details
+	UpdatedAt      time.Time     // When the state was last updated
+	IncidentID     int           // Incident the state belongs to, zero when there is none
+	Title          string        // Headline of the message, empty uses the observer's default
+	URL            string        // Address of what is being observed
+	PreviousStatus string        // Status before this one, empty when unknown
+	Duration       time.Duration // How long the previous status lasted
+	Error          string        // What went wrong, for failures and the recoveries ending them
+	Link           string        // Dashboard page with more details
+	TargetID       int           // Target the state is about, zero when there is none
 }
 
 // Observer defines the interface for objects that should be notified of state changes
@@ -15,38 +23,76 @@ type Observer interface {
 	Notify(State) error
 }
 
+// Decision tells the subject what to do with a state for a single observer
+type Decision int
+
+const (
+	// Deliver passes the state to the observer right away
+	Deliver Decision = iota
+	// Drop discards the state for this observer
+	Drop
+	// Defer hands the state to the subscription's Defer function for later delivery
+	Defer
+)
+
+// Subscription pairs an observer with the rules deciding when it hears about a state
+type Subscription struct {
+	Observer Observer
+	Filter   func(State) Decision // nil delivers every state
+	Defer    func(State) error    // called for deferred states, nil drops them
+}
+
 // Subject maintains a list of observers and notifies them of state changes
 type Subject struct {
-	observers []Observer
+	subscriptions []Subscription
 }
 
 // NewSubject creates a new subject
 func NewSubject() *Subject {
 	return &Subject{
-		observers: make([]Observer, 0),
+		subscriptions: make([]Subscription, 0),
 	}
 }
 
-// Attach adds an observer to the subject
+// Attach adds an observer that receives every state
 func (s *Subject) Attach(observer Observer) {
-	s.observers = append(s.observers, observer)
+	s.Subscribe(Subscription{Observer: observer})
+}
+
+// Subscribe adds an observer together with its filter
+func (s *Subject) Subscribe(subscription Subscription) {
+	s.subscriptions = append(s.subscriptions, subscription)
 }
 
 // Detach removes an observer from the subject
 func (s *Subject) Detach(observer Observer) {
-	for i, obs := range s.observers {
-		if obs == observer {
-			s.observers = append(s.observers[:i], s.observers[i+1:]...)
+	for i, sub := range s.subscriptions {
+		if sub.Observer == observer {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
 			break
 		}
 	}
 }
 
-// Notify sends the state to all observers
+// Notify sends the state to every observer whose filter lets it through
 func (s *Subject) Notify(state State) []error {
 	var errors []error
-	for _, observer := range s.observers {
-		if err := observer.Notify(state); err != nil {
+	for _, sub := range s.subscriptions {
+		decision := Deliver
+		if sub.Filter != nil {
+			decision = sub.Filter(state)
+		}
+
+		var err error
+		switch decision {
+		case Deliver:
+			err = sub.Observer.Notify(state)
+		case Defer:
+			if sub.Defer != nil {
+				err = sub.Defer(state)
+			}
+		}
+		if err != nil {
 			errors = append(errors, err)
 		}
 	}
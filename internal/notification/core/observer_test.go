@@ -117,3 +117,39 @@ func TestSubject_Detach(t *testing.T) {
 	assert.Len(t, observer1.states, 1) // Still 1 from before
 	assert.Len(t, observer2.states, 2) // Got both updates
 }
+
+func TestSubject_Subscribe(t *testing.T) {
+	subject := NewSubject()
+	all := NewMockObserver(nil)
+	downOnly := NewMockObserver(nil)
+	quiet := NewMockObserver(nil)
+	var deferred []State
+
+	subject.Attach(all)
+	subject.Subscribe(Subscription{
+		Observer: downOnly,
+		Filter: func(s State) Decision {
+			if s.Status != "down" {
+				return Drop
+			}
+			return Deliver
+		},
+	})
+	subject.Subscribe(Subscription{
+		Observer: quiet,
+		Filter:   func(State) Decision { return Defer },
+		Defer: func(s State) error {
+			deferred = append(deferred, s)
+			return nil
+		},
+	})
+
+	subject.Notify(State{Name: "test-system", Status: "down"})
+	subject.Notify(State{Name: "test-system", Status: "up"})
+
+	assert.Len(t, all.states, 2)
+	assert.Len(t, downOnly.states, 1)
+	assert.Equal(t, "down", downOnly.states[0].Status)
+	assert.Empty(t, quiet.states)
+	assert.Len(t, deferred, 2)
+}
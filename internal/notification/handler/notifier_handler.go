@@ -1,22 +1,477 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
 	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
 )
 
 type NotifierHandler struct {
 	notifierService service.NotifierServiceInterface
+	flash           flash.FlashStoreInterface
+	Template        struct {
+		List   *renderer.Template
+		Create *renderer.Template
+		Edit   *renderer.Template
+		Target *renderer.Template
+	}
 }
 
-func NewNotifierHandler(notifierService service.NotifierServiceInterface) *NotifierHandler {
+func NewNotifierHandler(notifierService service.NotifierServiceInterface, flash flash.FlashStoreInterface) *NotifierHandler {
 	return &NotifierHandler{
 		notifierService: notifierService,
+		flash:           flash,
+	}
+}
+
+// errorStatus maps notifier service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotifierNotFound), errors.Is(err, service.ErrTargetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// buildConfig builds the notifier configuration from the submitted form
+func buildConfig(notifierType model.NotifierType, r *http.Request) (json.RawMessage, error) {
+	switch notifierType {
+	case model.NotifierTypeSlack:
+		webhookURL := strings.TrimSpace(r.FormValue("webhook_url"))
+		if webhookURL == "" {
+			return nil, fmt.Errorf("webhook URL is required")
+		}
+		return json.Marshal(model.SlackConfig{WebhookURL: webhookURL})
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", notifierType)
+	}
+}
+
+// parseID reads a positive integer path value
+func parseID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// List shows the user's contact channels
+// applyFilters reads the event filter, rate limit and quiet hours from the submitted form
+func applyFilters(notifier *model.Notifier, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	notifier.Events = r.Form["events"]
+
+	notifier.RateLimit = 0
+	if limit := strings.TrimSpace(r.FormValue("rate_limit")); limit != "" {
+		rateLimit, err := strconv.Atoi(limit)
+		if err != nil || rateLimit < 0 || rateLimit > model.MaxRateLimit {
+			return fmt.Errorf("rate limit must be between 0 and %d notifications per hour", model.MaxRateLimit)
+		}
+		notifier.RateLimit = rateLimit
+	}
+
+	windows, err := model.ParseQuietWindows(r.FormValue("quiet_windows"))
+	if err != nil {
+		return err
+	}
+	if len(windows) == 0 {
+		notifier.QuietHours = nil
+		return nil
+	}
+
+	notifier.QuietHours = &model.QuietHours{
+		Timezone: strings.TrimSpace(r.FormValue("quiet_timezone")),
+		Windows:  windows,
+		Action:   model.QuietAction(r.FormValue("quiet_action")),
+	}
+	return notifier.QuietHours.Validate()
+}
+
+// applyTemplate reads and validates the message template from the submitted form
+func applyTemplate(notifier *model.Notifier, r *http.Request) error {
+	notifier.Template = parseTemplate(r)
+	if notifier.Template == nil {
+		return nil
+	}
+	return notifier.Template.Validate()
+}
+
+// parseTemplate reads the message template from the submitted form. Leaving
+// both parts empty keeps the default wording.
+func parseTemplate(r *http.Request) *model.MessageTemplate {
+	tmpl := &model.MessageTemplate{
+		Title: strings.TrimSpace(r.FormValue("title_template")),
+		Body:  strings.TrimSpace(r.FormValue("body_template")),
+	}
+	if tmpl.Title == "" && tmpl.Body == "" {
+		return nil
+	}
+	return tmpl
+}
+
+// defaultTemplate is shown as the placeholder of the message fields
+var defaultTemplate = model.MessageTemplate{Title: model.DefaultTitleTemplate, Body: model.DefaultBodyTemplate}
+
+func (nh *NotifierHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	notifiers, err := nh.notifierService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title":     "contact channels",
+		"notifiers": notifiers,
+	}
+
+	nh.Template.List.Render(w, r, data)
+}
+
+func (nh *NotifierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data := map[string]any{
+			"title":           "add a contact channel",
+			"events":          model.Events,
+			"defaultTemplate": defaultTemplate,
+		}
+		nh.Template.Create.Render(w, r, data)
+		return
+	}
+
+	createURL := "/app/notifiers/create"
+	notifierType := model.NotifierType(r.FormValue("type"))
+	config, err := buildConfig(notifierType, r)
+	if err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	notifier := &model.Notifier{
+		Name:   strings.TrimSpace(r.FormValue("name")),
+		Type:   notifierType,
+		Config: config,
+	}
+	if err := applyFilters(notifier, r); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+	if err := applyTemplate(notifier, r); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := nh.notifierService.Create(r.Context(), notifier, user.ID); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to create notifier: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetSuccesses(r.Context(), []string{"Notifier created successfully"})
+	http.Redirect(w, r, "/app/notifiers", http.StatusSeeOther)
+}
+
+func (nh *NotifierHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/notifiers/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	notifier, err := nh.notifierService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data := map[string]any{
+			"title":           "edit contact channel",
+			"notifier":        notifier,
+			"events":          model.Events,
+			"defaultTemplate": defaultTemplate,
+		}
+
+		if notifier.QuietHours != nil {
+			data["quietWindows"] = model.FormatQuietWindows(notifier.QuietHours.Windows)
+		}
+
+		if config, err := notifier.GetSlackConfig(); err == nil && config != nil {
+			data["slackConfig"] = config
+		}
+
+		nh.Template.Edit.Render(w, r, data)
+		return
+	}
+
+	config, err := buildConfig(notifier.Type, r)
+	if err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+
+	notifier.Name = strings.TrimSpace(r.FormValue("name"))
+	notifier.Config = config
+	if err := applyFilters(notifier, r); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	if err := applyTemplate(notifier, r); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Invalid notifier: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	if _, err := nh.notifierService.Update(r.Context(), notifier, user.ID); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to update notifier: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetSuccesses(r.Context(), []string{"Notifier updated successfully"})
+	http.Redirect(w, r, editURL, http.StatusSeeOther)
+}
+
+// Preview renders the submitted message template against a sample recovery
+func (nh *NotifierHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	tmpl := parseTemplate(r)
+	if tmpl == nil {
+		tmpl = &model.MessageTemplate{}
+	}
+
+	result := map[string]string{}
+	title, body, err := tmpl.Render(model.SampleState)
+	if err != nil {
+		result["error"] = err.Error()
+	} else {
+		result["title"] = title
+		result["body"] = body
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (nh *NotifierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := nh.notifierService.Delete(r.Context(), id, user.ID); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to delete notifier: " + err.Error()})
+	} else {
+		nh.flash.SetSuccesses(r.Context(), []string{"Notifier deleted successfully"})
+	}
+
+	http.Redirect(w, r, "/app/notifiers", http.StatusSeeOther)
+}
+
+// AttachAll attaches a channel to every target of the user
+func (nh *NotifierHandler) AttachAll(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := nh.notifierService.AttachToAllTargets(r.Context(), id, user.ID)
+	if err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to attach notifier: " + err.Error()})
+	} else {
+		nh.flash.SetSuccesses(r.Context(), []string{fmt.Sprintf("Notifier attached to %d targets", count)})
+	}
+
+	http.Redirect(w, r, "/app/notifiers", http.StatusSeeOther)
+}
+
+// AttachTag attaches a channel to every target of the user carrying the submitted tag
+func (nh *NotifierHandler) AttachTag(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	tag := r.FormValue("tag")
+	count, err := nh.notifierService.AttachToTag(r.Context(), id, user.ID, tag)
+	if err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to attach notifier: " + err.Error()})
+	} else {
+		nh.flash.SetSuccesses(r.Context(), []string{fmt.Sprintf("Notifier attached to %d targets tagged %q", count, strings.ToLower(strings.TrimSpace(tag)))})
+	}
+
+	http.Redirect(w, r, "/app/notifiers", http.StatusSeeOther)
+}
+
+// Target shows the channels attached to a target and the ones that can still be attached
+func (nh *NotifierHandler) Target(w http.ResponseWriter, r *http.Request) {
+	targetId, err := parseID(r, "targetId")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	attached, err := nh.notifierService.GetByTargetID(r.Context(), targetId, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	all, err := nh.notifierService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	attachedIDs := make(map[int]bool, len(attached))
+	for _, notifier := range attached {
+		attachedIDs[notifier.ID] = true
 	}
+
+	var available []*model.Notifier
+	for _, notifier := range all {
+		if !attachedIDs[notifier.ID] {
+			available = append(available, notifier)
+		}
+	}
+
+	data := map[string]any{
+		"title":     "target notifiers",
+		"targetId":  targetId,
+		"notifiers": attached,
+		"available": available,
+	}
+
+	nh.Template.Target.Render(w, r, data)
+}
+
+// Attach attaches an existing channel to a target
+func (nh *NotifierHandler) Attach(w http.ResponseWriter, r *http.Request) {
+	targetId, err := parseID(r, "targetId")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+	targetURL := fmt.Sprintf("/app/targets/notifiers/%d", targetId)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	notifierId, err := strconv.Atoi(r.FormValue("notifier_id"))
+	if err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to attach notifier: invalid notifier"})
+	} else if err := nh.notifierService.Attach(r.Context(), targetId, notifierId, user.ID); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to attach notifier: " + err.Error()})
+	} else {
+		nh.flash.SetSuccesses(r.Context(), []string{"Notifier attached successfully"})
+	}
+
+	http.Redirect(w, r, targetURL, http.StatusSeeOther)
+}
+
+// Detach removes a channel from a target without deleting it
+func (nh *NotifierHandler) Detach(w http.ResponseWriter, r *http.Request) {
+	targetId, err := parseID(r, "targetId")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := nh.notifierService.Detach(r.Context(), targetId, id, user.ID); err != nil {
+		nh.flash.SetErrors(r.Context(), []string{"Failed to detach notifier: " + err.Error()})
+	} else {
+		nh.flash.SetSuccesses(r.Context(), []string{"Notifier detached successfully"})
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/app/targets/notifiers/%d", targetId), http.StatusSeeOther)
 }
 
 func (nh *NotifierHandler) AuthSlack(w http.ResponseWriter, r *http.Request) {
@@ -34,31 +489,69 @@ func (nh *NotifierHandler) AuthSlack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	oauthLink := fmt.Sprintf("https://slack.com/oauth/v2/authorize?scope=incoming-webhook&user_scope=&redirect_uri=%s&client_id=%s&state=target_id=%d", redirectUri, clientId, targetId)
-	http.Redirect(w, r, oauthLink, http.StatusSeeOther)
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	// The state carries the target through Slack and back, signed and bound to the user
+	state, err := nh.notifierService.NewOAuthState(r.Context(), targetId, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	query := url.Values{
+		"scope":        {"incoming-webhook"},
+		"user_scope":   {""},
+		"redirect_uri": {redirectUri},
+		"client_id":    {clientId},
+		"state":        {state},
+	}
+	http.Redirect(w, r, "https://slack.com/oauth/v2/authorize?"+query.Encode(), http.StatusSeeOther)
 }
 
 func (nh *NotifierHandler) AuthSlackCallback(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
 	state := r.URL.Query().Get("state")
 
-	targetId, err := nh.notifierService.ParseOAuthState(state)
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	targetId, err := nh.notifierService.ParseOAuthState(r.Context(), state, user.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	notifier, err := nh.notifierService.HandleSlackCallback(code, targetId)
+	// Listing the target's notifiers doubles as the ownership check
+	if _, err := nh.notifierService.GetByTargetID(r.Context(), targetId, user.ID); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	notifier, err := nh.notifierService.HandleSlackCallback(r.Context(), code, user.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	err = nh.notifierService.Create(notifier)
+	err = nh.notifierService.Create(r.Context(), notifier, user.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	// The connection was started from a target page, so the new channel is attached to it
+	if err := nh.notifierService.Attach(r.Context(), targetId, notifier.ID, user.ID); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
-	http.Redirect(w, r, fmt.Sprintf("/targets/%d", targetId), http.StatusSeeOther)
+	nh.flash.SetSuccesses(r.Context(), []string{"Slack connected successfully"})
+	http.Redirect(w, r, fmt.Sprintf("/app/targets/notifiers/%d", targetId), http.StatusSeeOther)
 }
@@ -1,63 +1,118 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
 	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
 	"github.com/stretchr/testify/assert"
 )
 
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID})
+	return req.WithContext(ctx)
+}
+
 type MockNotifierService struct {
-	createFunc              func(notifier *model.Notifier) error
-	getFunc                 func(id int) (*model.Notifier, error)
-	updateFunc              func(id int, config json.RawMessage) (*model.Notifier, error)
-	deleteFunc              func(id int) error
-	configureObserversFunc  func(targetID int) error
-	handleSlackCallbackFunc func(code string, targetId int) (*model.Notifier, error)
-	parseOAuthStateFunc     func(state string) (int, error)
+	createFunc              func(notifier *model.Notifier, userID int) error
+	getFunc                 func(id int, userID int) (*model.Notifier, error)
+	getByUserIDFunc         func(userID int) ([]*model.Notifier, error)
+	getByTargetIDFunc       func(targetID int, userID int) ([]*model.Notifier, error)
+	updateFunc              func(notifier *model.Notifier, userID int) (*model.Notifier, error)
+	deleteFunc              func(id int, userID int) error
+	attachFunc              func(targetID int, notifierID int, userID int) error
+	detachFunc              func(targetID int, notifierID int, userID int) error
+	attachToAllTargetsFunc  func(notifierID int, userID int) (int, error)
+	attachToTagFunc         func(notifierID int, userID int, tag string) (int, error)
+	handleSlackCallbackFunc func(code string, userID int) (*model.Notifier, error)
+	resolveSlackUserFunc    func(teamID string, slackUserID string) (int, error)
+	newOAuthStateFunc       func(targetID int, userID int) (string, error)
+	parseOAuthStateFunc     func(state string, userID int) (int, error)
+}
+
+func (m *MockNotifierService) Create(ctx context.Context, notifier *model.Notifier, userID int) error {
+	return m.createFunc(notifier, userID)
+}
+
+func (m *MockNotifierService) Get(ctx context.Context, id int, userID int) (*model.Notifier, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockNotifierService) GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *MockNotifierService) GetByTargetID(ctx context.Context, targetID int, userID int) ([]*model.Notifier, error) {
+	return m.getByTargetIDFunc(targetID, userID)
+}
+
+func (m *MockNotifierService) Update(ctx context.Context, notifier *model.Notifier, userID int) (*model.Notifier, error) {
+	return m.updateFunc(notifier, userID)
+}
+
+func (m *MockNotifierService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
 }
 
-func (m *MockNotifierService) Create(notifier *model.Notifier) error {
-	return m.createFunc(notifier)
+func (m *MockNotifierService) Attach(ctx context.Context, targetID int, notifierID int, userID int) error {
+	return m.attachFunc(targetID, notifierID, userID)
 }
 
-func (m *MockNotifierService) Get(id int) (*model.Notifier, error) {
-	return m.getFunc(id)
+func (m *MockNotifierService) Detach(ctx context.Context, targetID int, notifierID int, userID int) error {
+	return m.detachFunc(targetID, notifierID, userID)
 }
 
-func (m *MockNotifierService) Update(id int, config json.RawMessage) (*model.Notifier, error) {
-	return m.updateFunc(id, config)
+func (m *MockNotifierService) AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error) {
+	return m.attachToAllTargetsFunc(notifierID, userID)
 }
 
-func (m *MockNotifierService) Delete(id int) error {
-	return m.deleteFunc(id)
+func (m *MockNotifierService) AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error) {
+	return m.attachToTagFunc(notifierID, userID, tag)
 }
 
-func (m *MockNotifierService) ConfigureObservers(targetID int) error {
-	return m.configureObserversFunc(targetID)
+func (m *MockNotifierService) NotifyTarget(ctx context.Context, targetID int, state notification.State) ([]error, error) {
+	return nil, nil
 }
 
-func (m *MockNotifierService) HandleSlackCallback(code string, targetId int) (*model.Notifier, error) {
-	return m.handleSlackCallbackFunc(code, targetId)
+func (m *MockNotifierService) HandleSlackCallback(ctx context.Context, code string, userID int) (*model.Notifier, error) {
+	return m.handleSlackCallbackFunc(code, userID)
 }
 
-func (m *MockNotifierService) ParseOAuthState(state string) (int, error) {
-	return m.parseOAuthStateFunc(state)
+func (m *MockNotifierService) ResolveSlackUser(ctx context.Context, teamID string, slackUserID string) (int, error) {
+	return m.resolveSlackUserFunc(teamID, slackUserID)
 }
 
-func (m *MockNotifierService) GetSubject() *notification.Subject {
-	return nil
+func (m *MockNotifierService) NewOAuthState(ctx context.Context, targetID int, userID int) (string, error) {
+	return m.newOAuthStateFunc(targetID, userID)
+}
+
+func (m *MockNotifierService) ParseOAuthState(ctx context.Context, state string, userID int) (int, error) {
+	return m.parseOAuthStateFunc(state, userID)
 }
 
 func TestNotifierHandler_AuthSlack(t *testing.T) {
 	mockService := new(MockNotifierService)
-	handler := NewNotifierHandler(mockService)
+	mockService.newOAuthStateFunc = func(targetID int, userID int) (string, error) {
+		if userID != 1 {
+			return "", service.ErrUnauthorized
+		}
+		return fmt.Sprintf("%d.%d.signed", userID, targetID), nil
+	}
+	handler := NewNotifierHandler(mockService, flash.NewMockFlashStore())
 
 	t.Run("successful redirect", func(t *testing.T) {
 		os.Setenv("SLACK_REDIRECT_URI", "http://example.com/callback")
@@ -69,24 +124,37 @@ func TestNotifierHandler_AuthSlack(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil)
 		req.SetPathValue("targetId", "1")
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		handler.AuthSlack(w, req)
 
 		assert.Equal(t, http.StatusSeeOther, w.Code)
 
-		expectedLocation := fmt.Sprintf(
-			"https://slack.com/oauth/v2/authorize?" +
-				"scope=incoming-webhook&" +
-				"user_scope=&" +
-				"redirect_uri=http://example.com/callback&" +
-				"client_id=test_client_id&" +
-				"state=target_id=1",
-		)
+		expectedLocation := "https://slack.com/oauth/v2/authorize?" +
+			"client_id=test_client_id&" +
+			"redirect_uri=http%3A%2F%2Fexample.com%2Fcallback&" +
+			"scope=incoming-webhook&" +
+			"state=1.1.signed&" +
+			"user_scope="
 
 		assert.Equal(t, expectedLocation, w.Header().Get("Location"))
 	})
 
+	t.Run("target owned by another user", func(t *testing.T) {
+		t.Setenv("SLACK_REDIRECT_URI", "http://example.com/callback")
+		t.Setenv("SLACK_CLIENT_ID", "test_client_id")
+
+		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil)
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.AuthSlack(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
 	t.Run("missing environment variables", func(t *testing.T) {
 		// Setup - ensure env vars are not set
 		os.Unsetenv("SLACK_REDIRECT_URI")
@@ -104,33 +172,64 @@ func TestNotifierHandler_AuthSlack(t *testing.T) {
 }
 
 func TestNotifierController_AuthSlackCallback(t *testing.T) {
+	var attachedTarget int
 	mockService := new(MockNotifierService)
-	mockService.handleSlackCallbackFunc = func(code string, targetId int) (*model.Notifier, error) {
-		return &model.Notifier{ID: 1, TargetId: targetId}, nil
+	mockService.handleSlackCallbackFunc = func(code string, userID int) (*model.Notifier, error) {
+		return &model.Notifier{Name: "#alerts", Type: model.NotifierTypeSlack}, nil
 	}
-	mockService.parseOAuthStateFunc = func(state string) (int, error) {
+	mockService.parseOAuthStateFunc = func(state string, userID int) (int, error) {
 		return 1, nil
 	}
-	mockService.createFunc = func(notifier *model.Notifier) error {
+	mockService.getByTargetIDFunc = func(targetID int, userID int) ([]*model.Notifier, error) {
+		if userID != 1 {
+			return nil, service.ErrUnauthorized
+		}
+		return nil, nil
+	}
+	mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+		notifier.ID = 5
+		return nil
+	}
+	mockService.attachFunc = func(targetID int, notifierID int, userID int) error {
+		assert.Equal(t, 5, notifierID)
+		attachedTarget = targetID
 		return nil
 	}
-	controller := NewNotifierHandler(mockService)
+	controller := NewNotifierHandler(mockService, flash.NewMockFlashStore())
 
 	t.Run("successful callback", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=target_id=1", nil)
+		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=1.1.signed", nil)
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		controller.AuthSlackCallback(w, req)
 
 		assert.Equal(t, http.StatusSeeOther, w.Code)
-		assert.Equal(t, "/targets/1", w.Header().Get("Location"))
+		assert.Equal(t, "/app/targets/notifiers/1", w.Header().Get("Location"))
+		assert.Equal(t, 1, attachedTarget)
+	})
+
+	t.Run("target owned by another user", func(t *testing.T) {
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			t.Fatal("create must not be called for a target the user does not own")
+			return nil
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=1.1.signed", nil)
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		controller.AuthSlackCallback(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("invalid code", func(t *testing.T) {
-		mockService.handleSlackCallbackFunc = func(code string, targetId int) (*model.Notifier, error) {
+		mockService.handleSlackCallbackFunc = func(code string, userID int) (*model.Notifier, error) {
 			return nil, fmt.Errorf("invalid code")
 		}
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=&state=target_id=1", nil)
+		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=&state=1.1.signed", nil)
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		controller.AuthSlackCallback(w, req)
@@ -140,15 +239,477 @@ func TestNotifierController_AuthSlackCallback(t *testing.T) {
 	})
 
 	t.Run("invalid state", func(t *testing.T) {
-		mockService.parseOAuthStateFunc = func(state string) (int, error) {
-			return 0, fmt.Errorf("invalid state")
+		mockService.parseOAuthStateFunc = func(state string, userID int) (int, error) {
+			return 0, service.ErrInvalidState
 		}
 		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=invalid_state", nil)
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		controller.AuthSlackCallback(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "invalid state")
+		assert.Contains(t, w.Body.String(), "invalid oauth state")
+	})
+}
+
+func newTestNotifierHandler(mockService *MockNotifierService) *NotifierHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewNotifierHandler(mockService, mockFlashStore)
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:notifiers/list")
+	handler.Template.Create = templateRenderer.GetTemplate("pages:notifiers/create")
+	handler.Template.Edit = templateRenderer.GetTemplate("pages:notifiers/edit")
+	handler.Template.Target = templateRenderer.GetTemplate("pages:notifiers/target")
+	return handler
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func ownedNotifier(id int, userID int) (*model.Notifier, error) {
+	if userID != 1 {
+		return nil, service.ErrUnauthorized
+	}
+	return &model.Notifier{
+		ID:     id,
+		UserID: 1,
+		Name:   "#alerts",
+		Type:   model.NotifierTypeSlack,
+		Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+	}, nil
+}
+
+func TestNotifierHandler_List(t *testing.T) {
+	mockService := &MockNotifierService{
+		getByUserIDFunc: func(userID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{{ID: 1, UserID: userID, Name: "#alerts", Type: model.NotifierTypeSlack}}, nil
+		},
+	}
+	handler := newTestNotifierHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/notifiers", nil)
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "#alerts")
+	assert.Contains(t, w.Body.String(), "/app/notifiers/edit/1")
+	assert.Contains(t, w.Body.String(), "/app/notifiers/attach-tag/1")
+}
+
+func TestNotifierHandler_Create(t *testing.T) {
+	mockService := &MockNotifierService{}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/notifiers/create", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var created *model.Notifier
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			created = notifier
+			return nil
+		}
+
+		form := url.Values{}
+		form.Add("name", "#alerts")
+		form.Add("type", "slack")
+		form.Add("webhook_url", "https://hooks.slack.com/test")
+		form.Add("events", "down")
+		form.Add("events", "up")
+		form.Add("quiet_windows", "22:00-07:00")
+		form.Add("quiet_timezone", "Europe/Berlin")
+		form.Add("quiet_action", "digest")
+		form.Add("title_template", "{{upper .Status}}: {{.Name}}")
+		form.Add("rate_limit", "10")
+
+		req := withUser(postForm("/app/notifiers/create", form), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers", w.Header().Get("Location"))
+		assert.Equal(t, "#alerts", created.Name)
+		assert.JSONEq(t, `{"webhook_url": "https://hooks.slack.com/test"}`, string(created.Config))
+		assert.Equal(t, []string{"down", "up"}, created.Events)
+		assert.Equal(t, &model.QuietHours{
+			Timezone: "Europe/Berlin",
+			Windows:  []model.QuietWindow{{Start: "22:00", End: "07:00"}},
+			Action:   model.QuietActionDigest,
+		}, created.QuietHours)
+		assert.Equal(t, &model.MessageTemplate{Title: "{{upper .Status}}: {{.Name}}"}, created.Template)
+		assert.Equal(t, 10, created.RateLimit)
+	})
+
+	t.Run("POST request - invalid rate limit", func(t *testing.T) {
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			t.Fatal("create must not be called for an invalid form")
+			return nil
+		}
+
+		form := url.Values{}
+		form.Add("type", "slack")
+		form.Add("webhook_url", "https://hooks.slack.com/test")
+		form.Add("rate_limit", "-5")
+
+		req := withUser(postForm("/app/notifiers/create", form), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers/create", w.Header().Get("Location"))
+	})
+
+	t.Run("POST request - invalid template", func(t *testing.T) {
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			t.Fatal("create must not be called for an invalid form")
+			return nil
+		}
+
+		form := url.Values{}
+		form.Add("type", "slack")
+		form.Add("webhook_url", "https://hooks.slack.com/test")
+		form.Add("body_template", `{{printf "%s" .Name}}`)
+
+		req := withUser(postForm("/app/notifiers/create", form), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers/create", w.Header().Get("Location"))
+	})
+
+	t.Run("POST request - invalid quiet hours", func(t *testing.T) {
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			t.Fatal("create must not be called for an invalid form")
+			return nil
+		}
+
+		form := url.Values{}
+		form.Add("type", "slack")
+		form.Add("webhook_url", "https://hooks.slack.com/test")
+		form.Add("quiet_windows", "late-early")
+		form.Add("quiet_action", "drop")
+
+		req := withUser(postForm("/app/notifiers/create", form), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers/create", w.Header().Get("Location"))
+	})
+
+	t.Run("POST request - missing webhook", func(t *testing.T) {
+		mockService.createFunc = func(notifier *model.Notifier, userID int) error {
+			t.Fatal("create must not be called for an invalid form")
+			return nil
+		}
+
+		form := url.Values{}
+		form.Add("type", "slack")
+
+		req := withUser(postForm("/app/notifiers/create", form), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers/create", w.Header().Get("Location"))
+	})
+}
+
+func TestNotifierHandler_Edit(t *testing.T) {
+	mockService := &MockNotifierService{getFunc: ownedNotifier}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/notifiers/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "https://hooks.slack.com/test")
+		assert.Contains(t, w.Body.String(), `value="down" checked`)
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/notifiers/edit/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		mockService.updateFunc = func(notifier *model.Notifier, userID int) (*model.Notifier, error) {
+			assert.Equal(t, "#incidents", notifier.Name)
+			assert.Empty(t, notifier.Events)
+			assert.Nil(t, notifier.QuietHours)
+			assert.Nil(t, notifier.Template)
+			assert.JSONEq(t, `{"webhook_url": "https://hooks.slack.com/new"}`, string(notifier.Config))
+			return notifier, nil
+		}
+
+		form := url.Values{}
+		form.Add("name", "#incidents")
+		form.Add("webhook_url", "https://hooks.slack.com/new")
+
+		req := postForm("/app/notifiers/edit/1", form)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers/edit/1", w.Header().Get("Location"))
+	})
+}
+
+func TestNotifierHandler_Preview(t *testing.T) {
+	handler := newTestNotifierHandler(&MockNotifierService{})
+
+	preview := func(form url.Values) map[string]string {
+		w := httptest.NewRecorder()
+		handler.Preview(w, withUser(postForm("/app/notifiers/preview", form), 1))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var result map[string]string
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+		return result
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		result := preview(url.Values{})
+		assert.Equal(t, "Status Update for https://example.com", result["title"])
+		assert.Equal(t, model.SampleState.Message, result["body"])
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		result := preview(url.Values{"body_template": {"Back after {{.Duration}}"}})
+		assert.Equal(t, "Back after 12m30s", result["body"])
+		assert.Empty(t, result["error"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		result := preview(url.Values{"title_template": {"{{.Name"}})
+		assert.Contains(t, result["error"], "invalid title template")
+		assert.Empty(t, result["title"])
+	})
+}
+
+func TestNotifierHandler_Delete(t *testing.T) {
+	deleted := false
+	mockService := &MockNotifierService{
+		deleteFunc: func(id int, userID int) error {
+			if userID != 1 {
+				return service.ErrUnauthorized
+			}
+			deleted = true
+			return nil
+		},
+	}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/notifiers/delete/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Delete(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.False(t, deleted)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/notifiers/delete/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Delete(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers", w.Header().Get("Location"))
+		assert.True(t, deleted)
+	})
+}
+
+func TestNotifierHandler_BulkAttach(t *testing.T) {
+	var tag string
+	mockService := &MockNotifierService{
+		attachToAllTargetsFunc: func(notifierID int, userID int) (int, error) {
+			return 3, nil
+		},
+		attachToTagFunc: func(notifierID int, userID int, t string) (int, error) {
+			tag = t
+			return 2, nil
+		},
+	}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("attach to all targets", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/notifiers/attach-all/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.AttachAll(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/notifiers", w.Header().Get("Location"))
+	})
+
+	t.Run("attach to tag", func(t *testing.T) {
+		form := url.Values{}
+		form.Add("tag", "production")
+
+		req := postForm("/app/notifiers/attach-tag/1", form)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.AttachTag(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "production", tag)
+	})
+}
+
+func TestNotifierHandler_Target(t *testing.T) {
+	mockService := &MockNotifierService{
+		getByTargetIDFunc: func(targetID int, userID int) ([]*model.Notifier, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return []*model.Notifier{{ID: 1, Name: "#alerts", Type: model.NotifierTypeSlack}}, nil
+		},
+		getByUserIDFunc: func(userID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{
+				{ID: 1, Name: "#alerts", Type: model.NotifierTypeSlack},
+				{ID: 2, Name: "#ops", Type: model.NotifierTypeSlack},
+			}, nil
+		},
+	}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("lists attached and available channels", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/notifiers/1", nil)
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Target(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "/app/targets/notifiers/1/detach/1")
+		assert.Contains(t, w.Body.String(), `<option value="2">#ops (slack)</option>`)
+		assert.NotContains(t, w.Body.String(), `<option value="1">`)
+	})
+
+	t.Run("target owned by another user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/notifiers/1", nil)
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Target(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid target ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/notifiers/abc", nil)
+		req.SetPathValue("targetId", "abc")
+		w := httptest.NewRecorder()
+
+		handler.Target(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotifierHandler_AttachDetach(t *testing.T) {
+	var attached, detached int
+	mockService := &MockNotifierService{
+		attachFunc: func(targetID int, notifierID int, userID int) error {
+			attached = notifierID
+			return nil
+		},
+		detachFunc: func(targetID int, notifierID int, userID int) error {
+			if userID != 1 {
+				return service.ErrUnauthorized
+			}
+			detached = notifierID
+			return nil
+		},
+	}
+	handler := newTestNotifierHandler(mockService)
+
+	t.Run("attach", func(t *testing.T) {
+		form := url.Values{}
+		form.Add("notifier_id", "2")
+
+		req := postForm("/app/targets/notifiers/1/attach", form)
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Attach(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/targets/notifiers/1", w.Header().Get("Location"))
+		assert.Equal(t, 2, attached)
+	})
+
+	t.Run("detach by another user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/targets/notifiers/1/detach/2", nil)
+		req.SetPathValue("targetId", "1")
+		req.SetPathValue("id", "2")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Detach(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Zero(t, detached)
+	})
+
+	t.Run("detach", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/app/targets/notifiers/1/detach/2", nil)
+		req.SetPathValue("targetId", "1")
+		req.SetPathValue("id", "2")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Detach(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, 2, detached)
 	})
 }
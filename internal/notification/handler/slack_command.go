@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	notifierService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+)
+
+// slackMaxPause is the longest a target can be paused with a slash command
+const slackMaxPause = 7 * 24 * time.Hour
+
+// slackTimeFormat is how times are shown in Slack messages
+const slackTimeFormat = "Jan 2 15:04 MST"
+
+var slackCommandUsage = []string{
+	"`/uptime status` lists your targets and their status",
+	"`/uptime pause <target> <duration>` pauses the checks of a target, e.g. `/uptime pause example.com 30m`",
+	"`/uptime incidents` lists the incidents that are not resolved yet",
+	"A target is given by its ID or its URL, or a unique part of it",
+}
+
+// Command handles the /uptime slash command. Slack signs every request with
+// the app's signing secret, which is verified before acting. The Slack
+// account is mapped to the user that connected Slack from it, and the
+// command only sees and acts on that user's targets.
+func (sh *SlackHandler) Command(w http.ResponseWriter, r *http.Request) {
+	form, valid := sh.verifiedForm(w, r, os.Getenv("SLACK_SIGNING_SECRET"))
+	if !valid {
+		return
+	}
+
+	userID, err := sh.slackUsers.ResolveSlackUser(r.Context(), form.Get("team_id"), form.Get("user_id"))
+	if err != nil {
+		if errors.Is(err, notifierService.ErrUnauthorized) {
+			writeSlack(w, provider.SlackCommandResponse("Slack is not connected", []string{
+				"Your Slack account is not linked to an uptimebot user yet. Connect Slack from the notifiers page of one of your targets, then try again.",
+			}))
+			return
+		}
+		slog.Error("Failed to resolve Slack user", "team", form.Get("team_id"), "user", form.Get("user_id"), "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	var response provider.SlackResponse
+	switch {
+	case len(args) == 0 || args[0] == "help":
+		response = provider.SlackCommandResponse("Uptimebot commands", slackCommandUsage)
+	case args[0] == "status":
+		response = sh.status(r.Context(), userID)
+	case args[0] == "pause":
+		response = sh.pauseCommand(r.Context(), userID, args[1:])
+	case args[0] == "incidents":
+		response = sh.incidents(r.Context(), userID)
+	default:
+		lines := append([]string{fmt.Sprintf("Unknown command `%s`", provider.EscapeSlackText(args[0]))}, slackCommandUsage...)
+		response = provider.SlackCommandResponse("Uptimebot commands", lines)
+	}
+
+	writeSlack(w, response)
+}
+
+// status lists the targets of the user
+func (sh *SlackHandler) status(ctx context.Context, userID int) provider.SlackResponse {
+	targets, err := sh.targetService.GetAllByUserID(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to list targets for Slack", "user", userID, "error", err)
+		return provider.SlackCommandResponse("Target status", []string{"Failed to load your targets, please try again"})
+	}
+	if len(targets) == 0 {
+		return provider.SlackCommandResponse("Target status", []string{"You are not monitoring any targets yet"})
+	}
+
+	now := sh.now()
+	lines := make([]string, 0, len(targets))
+	for _, target := range targets {
+		line := fmt.Sprintf("%s *%s* (#%d) is %s", provider.SlackStatusEmoji(target.Status),
+			provider.EscapeSlackText(target.URL), target.ID, provider.EscapeSlackText(target.Status))
+		switch {
+		case !target.Enabled:
+			line += ", monitoring disabled"
+		case target.Paused(now):
+			line += ", paused until " + target.PausedUntil.UTC().Format(slackTimeFormat)
+		}
+		lines = append(lines, line)
+	}
+	return provider.SlackCommandResponse("Target status", lines)
+}
+
+// pauseCommand pauses the checks of a target for the given duration
+func (sh *SlackHandler) pauseCommand(ctx context.Context, userID int, args []string) provider.SlackResponse {
+	if len(args) != 2 {
+		return provider.SlackCommandResponse("Pause monitoring", []string{"Usage: `/uptime pause <target> <duration>`, e.g. `/uptime pause example.com 30m`"})
+	}
+
+	duration, err := time.ParseDuration(args[1])
+	if err != nil || duration <= 0 || duration > slackMaxPause {
+		return provider.SlackCommandResponse("Pause monitoring", []string{"The duration must be between 1s and 168h, e.g. `30m` or `2h`"})
+	}
+
+	targets, err := sh.targetService.GetAllByUserID(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to list targets for Slack", "user", userID, "error", err)
+		return provider.SlackCommandResponse("Pause monitoring", []string{"Failed to load your targets, please try again"})
+	}
+	target, problem := findTarget(targets, args[0])
+	if problem != "" {
+		return provider.SlackCommandResponse("Pause monitoring", []string{problem})
+	}
+
+	until := sh.now().Add(duration)
+	if _, err := sh.targetService.Pause(ctx, target.ID, userID, until); err != nil {
+		slog.Error("Failed to pause target from Slack", "target", target.ID, "error", err)
+		return provider.SlackCommandResponse("Pause monitoring", []string{"Failed to pause monitoring, please try again"})
+	}
+
+	return provider.SlackCommandResponse("Pause monitoring", []string{
+		fmt.Sprintf(":double_vertical_bar: Monitoring of *%s* paused until %s",
+			provider.EscapeSlackText(target.URL), until.UTC().Format(slackTimeFormat)),
+	})
+}
+
+// findTarget picks the target a command refers to by ID, URL or a unique
+// part of the URL. When there is no single match it returns why.
+func findTarget(targets []monitorModel.UserTarget, ref string) (monitorModel.UserTarget, string) {
+	if id, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		for _, target := range targets {
+			if target.ID == id {
+				return target, ""
+			}
+		}
+	}
+
+	var matches []monitorModel.UserTarget
+	for _, target := range targets {
+		if target.URL == ref {
+			return target, ""
+		}
+		if strings.Contains(target.URL, ref) {
+			matches = append(matches, target)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return monitorModel.UserTarget{}, fmt.Sprintf("No target matches `%s`", provider.EscapeSlackText(ref))
+	case 1:
+		return matches[0], ""
+	default:
+		return monitorModel.UserTarget{}, fmt.Sprintf("`%s` matches %d targets, use the target ID or full URL instead",
+			provider.EscapeSlackText(ref), len(matches))
+	}
+}
+
+// incidents lists the incidents of the user that are not resolved yet
+func (sh *SlackHandler) incidents(ctx context.Context, userID int) provider.SlackResponse {
+	incidents, err := sh.incidentService.GetUnresolvedByUserID(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to list incidents for Slack", "user", userID, "error", err)
+		return provider.SlackCommandResponse("Open incidents", []string{"Failed to load your incidents, please try again"})
+	}
+	if len(incidents) == 0 {
+		return provider.SlackCommandResponse("Open incidents", []string{":large_green_circle: No open incidents"})
+	}
+
+	now := sh.now()
+	lines := make([]string, 0, len(incidents))
+	for _, incident := range incidents {
+		emoji := ":red_circle:"
+		if incident.Status == incidentModel.StatusAcknowledged {
+			emoji = ":large_yellow_circle:"
+		}
+		line := fmt.Sprintf("%s *%s* %s for %s", emoji, provider.EscapeSlackText(incident.TargetURL),
+			incident.Status, incident.Duration(now).Round(time.Minute))
+		if incident.Cause != "" {
+			line += ": " + provider.EscapeSlackText(incident.Cause)
+		}
+		lines = append(lines, line)
+	}
+	return provider.SlackCommandResponse("Open incidents", lines)
+}
+
+// writeSlack answers a Slack request with a message
+func writeSlack(w http.ResponseWriter, response provider.SlackResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to write Slack response", "error", err)
+	}
+}
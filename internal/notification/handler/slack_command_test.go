@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackHandler_Command(t *testing.T) {
+	t.Setenv("SLACK_SIGNING_SECRET", "secret")
+	now := time.Date(2025, 4, 29, 9, 0, 0, 0, time.UTC)
+	pausedUntil := now.Add(time.Hour)
+
+	var paused []int
+	targets := &mockSlackTargetService{
+		getAllByUserIDFunc: func(userID int) ([]monitorModel.UserTarget, error) {
+			assert.Equal(t, 2, userID)
+			return []monitorModel.UserTarget{
+				{UserID: 2, Target: &monitor.Target{ID: 1, URL: "https://example.org", Status: "up", Enabled: true}},
+				{UserID: 2, Target: &monitor.Target{ID: 2, URL: "https://api.example.org", Status: "down", Enabled: true, PausedUntil: &pausedUntil}},
+			}, nil
+		},
+		pauseFunc: func(id int, userID int, until time.Time) (monitorModel.UserTarget, error) {
+			assert.Equal(t, now.Add(30*time.Minute), until)
+			paused = append(paused, id)
+			return monitorModel.UserTarget{}, nil
+		},
+	}
+	incidents := &mockSlackIncidentService{
+		getUnresolvedByUserIDFunc: func(userID int) ([]*incidentModel.Incident, error) {
+			return []*incidentModel.Incident{{
+				ID:        3,
+				TargetURL: "https://api.example.org",
+				Status:    incidentModel.StatusOpen,
+				Cause:     "connection refused",
+				StartedAt: now.Add(-15 * time.Minute),
+			}}, nil
+		},
+	}
+	slackUsers := &MockNotifierService{
+		resolveSlackUserFunc: func(teamID string, slackUserID string) (int, error) {
+			if teamID == "T1" && slackUserID == "U1" {
+				return 2, nil
+			}
+			return 0, fmt.Errorf("%w: slack account is not connected", service.ErrUnauthorized)
+		},
+	}
+	handler := NewSlackHandler(incidents, targets, slackUsers, nil)
+	handler.now = func() time.Time { return now }
+
+	request := func(slackUserID string, text string, signingSecret string) (*httptest.ResponseRecorder, string) {
+		body := url.Values{"team_id": {"T1"}, "user_id": {slackUserID}, "command": {"/uptime"}, "text": {text}}.Encode()
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+
+		req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", provider.SignSlackRequest(signingSecret, timestamp, []byte(body)))
+		w := httptest.NewRecorder()
+
+		handler.Command(w, req)
+
+		var response provider.SlackResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		var blocks []string
+		for _, block := range response.Blocks {
+			blocks = append(blocks, string(block))
+		}
+		return w, strings.Join(blocks, "\n")
+	}
+
+	t.Run("forged request", func(t *testing.T) {
+		w, _ := request("U1", "status", "other")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("unknown slack user", func(t *testing.T) {
+		w, blocks := request("U9", "status", "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, blocks, "not linked to an uptimebot user")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		w, blocks := request("U1", "status", "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Contains(t, blocks, ":large_green_circle: *https://example.org* (#1) is up")
+		assert.Contains(t, blocks, "paused until Apr 29 10:00 UTC")
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		_, blocks := request("U1", "pause api 30m", "secret")
+		assert.Equal(t, []int{2}, paused)
+		assert.Contains(t, blocks, "Monitoring of *https://api.example.org* paused until Apr 29 09:30 UTC")
+	})
+
+	t.Run("pause with ambiguous target", func(t *testing.T) {
+		_, blocks := request("U1", "pause example 30m", "secret")
+		assert.Contains(t, blocks, "matches 2 targets")
+	})
+
+	t.Run("pause with invalid duration", func(t *testing.T) {
+		_, blocks := request("U1", "pause #1 forever", "secret")
+		assert.Contains(t, blocks, "The duration must be between")
+	})
+
+	t.Run("incidents", func(t *testing.T) {
+		_, blocks := request("U1", "incidents", "secret")
+		assert.Contains(t, blocks, ":red_circle: *https://api.example.org* open for 15m0s: connection refused")
+	})
+
+	t.Run("help", func(t *testing.T) {
+		_, blocks := request("U1", "", "secret")
+		assert.Contains(t, blocks, "/uptime pause")
+	})
+}
+
+func TestFindTarget(t *testing.T) {
+	targets := []monitorModel.UserTarget{
+		{Target: &monitor.Target{ID: 1, URL: "https://example.org"}},
+		{Target: &monitor.Target{ID: 2, URL: "https://example.org/health"}},
+	}
+
+	target, problem := findTarget(targets, "#2")
+	assert.Empty(t, problem)
+	assert.Equal(t, 2, target.ID)
+
+	// An exact URL wins over the targets it is part of
+	target, problem = findTarget(targets, "https://example.org")
+	assert.Empty(t, problem)
+	assert.Equal(t, 1, target.ID)
+
+	target, problem = findTarget(targets, "health")
+	assert.Empty(t, problem)
+	assert.Equal(t, 2, target.ID)
+
+	_, problem = findTarget(targets, "missing")
+	assert.Contains(t, problem, "No target matches")
+}
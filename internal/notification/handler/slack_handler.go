@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+)
+
+// slackPauseDuration is how long the Pause button holds the checks of a target
+const slackPauseDuration = time.Hour
+
+// IncidentService is the part of the incident service the Slack app acts on
+type IncidentService interface {
+	AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error
+	GetUnresolvedByUserID(ctx context.Context, userID int) ([]*incidentModel.Incident, error)
+}
+
+// TargetService is the part of the target service the Slack app acts on
+type TargetService interface {
+	PauseFromSlack(ctx context.Context, id int, until time.Time) (monitorModel.UserTarget, error)
+	Pause(ctx context.Context, id int, userID int, until time.Time) (monitorModel.UserTarget, error)
+	GetAllByUserID(ctx context.Context, userID int) ([]monitorModel.UserTarget, error)
+}
+
+// SlackUserResolver maps Slack accounts to the users that connected Slack
+type SlackUserResolver interface {
+	ResolveSlackUser(ctx context.Context, teamID string, slackUserID string) (int, error)
+}
+
+// SlackHandler handles requests sent by the Slack app
+type SlackHandler struct {
+	incidentService IncidentService
+	targetService   TargetService
+	slackUsers      SlackUserResolver
+	client          provider.HTTPClient
+	now             func() time.Time
+}
+
+func NewSlackHandler(incidentService IncidentService, targetService TargetService, slackUsers SlackUserResolver, client provider.HTTPClient) *SlackHandler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SlackHandler{
+		incidentService: incidentService,
+		targetService:   targetService,
+		slackUsers:      slackUsers,
+		client:          client,
+		now:             time.Now,
+	}
+}
+
+// slackInteraction is the part of a Slack block_actions payload we act on
+type slackInteraction struct {
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Message struct {
+		Text   string            `json:"text"`
+		Blocks []json.RawMessage `json:"blocks"`
+	} `json:"message"`
+}
+
+func (i *slackInteraction) userName() string {
+	if i.User.Username != "" {
+		return i.User.Username
+	}
+	return i.User.Name
+}
+
+// Interact handles the buttons of Slack notifications. Slack signs every
+// request with the app's signing secret, which is verified before acting. The
+// outcome replaces the original message, so the whole channel sees it.
+func (sh *SlackHandler) Interact(w http.ResponseWriter, r *http.Request) {
+	secret := os.Getenv("SLACK_SIGNING_SECRET")
+
+	form, valid := sh.verifiedForm(w, r, secret)
+	if !valid {
+		return
+	}
+
+	var interaction slackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &interaction); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Slack expects every interaction to be acknowledged, including link
+	// buttons which have nothing left to do
+	w.WriteHeader(http.StatusOK)
+	if len(interaction.Actions) == 0 {
+		return
+	}
+
+	action := interaction.Actions[0]
+	var note string
+	var ok bool
+	switch action.ActionID {
+	case provider.SlackActionOpenDashboard:
+		return
+	case provider.SlackActionAcknowledge:
+		note, ok = sh.acknowledge(r.Context(), secret, action.Value, interaction.userName())
+	case provider.SlackActionPause:
+		note, ok = sh.pause(r.Context(), secret, action.Value, interaction.userName())
+	default:
+		note = "This action is not supported"
+	}
+	if !ok {
+		sh.reply(interaction.ResponseURL, note)
+		return
+	}
+
+	blocks, err := provider.CompleteSlackAction(interaction.Message.Blocks, action.ActionID, note)
+	if err != nil {
+		slog.Error("Failed to update Slack message", "error", err)
+		sh.reply(interaction.ResponseURL, note)
+		return
+	}
+	err = provider.RespondSlack(sh.client, interaction.ResponseURL, provider.SlackResponse{
+		ReplaceOriginal: true,
+		Text:            interaction.Message.Text,
+		Blocks:          blocks,
+	})
+	if err != nil {
+		slog.Error("Failed to update Slack message", "error", err)
+	}
+}
+
+// verifiedForm reads a form Slack posted, after checking it is signed with
+// the app's signing secret. On failure the error is written and false returned.
+func (sh *SlackHandler) verifiedForm(w http.ResponseWriter, r *http.Request, secret string) (url.Values, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if err := provider.VerifySlackSignature(secret, r.Header, body, sh.now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return nil, false
+	}
+	return form, true
+}
+
+// acknowledge stops the escalation of an incident. It returns the note added
+// to the message, or why nothing was done and false.
+func (sh *SlackHandler) acknowledge(ctx context.Context, secret string, value string, slackUser string) (string, bool) {
+	id, err := provider.VerifyActionID(secret, provider.SlackActionAcknowledge, value)
+	if err != nil {
+		return "This button is not valid", false
+	}
+
+	if err := sh.incidentService.AcknowledgeFromSlack(ctx, id, slackUser); err != nil {
+		if errors.Is(err, incidentService.ErrIncidentNotFound) {
+			return "This incident is already acknowledged or resolved", false
+		}
+		slog.Error("Failed to acknowledge incident from Slack", "incident", id, "error", err)
+		return "Failed to acknowledge the incident, please try again from the dashboard", false
+	}
+
+	return fmt.Sprintf(":white_check_mark: Acknowledged by @%s, escalation stopped", slackUser), true
+}
+
+// pause holds the checks of a target for slackPauseDuration
+func (sh *SlackHandler) pause(ctx context.Context, secret string, value string, slackUser string) (string, bool) {
+	id, err := provider.VerifyActionID(secret, provider.SlackActionPause, value)
+	if err != nil {
+		return "This button is not valid", false
+	}
+
+	until := sh.now().Add(slackPauseDuration)
+	if _, err := sh.targetService.PauseFromSlack(ctx, id, until); err != nil {
+		if errors.Is(err, monitorService.ErrTargetNotFound) {
+			return "This target no longer exists", false
+		}
+		slog.Error("Failed to pause target from Slack", "target", id, "error", err)
+		return "Failed to pause monitoring, please try again from the dashboard", false
+	}
+
+	return fmt.Sprintf(":double_vertical_bar: Monitoring paused until %s by @%s",
+		until.UTC().Format("Jan 2 15:04 MST"), slackUser), true
+}
+
+// reply sends a message only the Slack user who clicked the button sees
+func (sh *SlackHandler) reply(responseURL string, text string) {
+	err := provider.RespondSlack(sh.client, responseURL, provider.SlackResponse{
+		ResponseType:    "ephemeral",
+		ReplaceOriginal: false,
+		Text:            text,
+	})
+	if err != nil {
+		slog.Error("Failed to reply to Slack", "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	incidentModel "github.com/shuvo-paul/uptimebot/internal/incident/model"
+	incidentService "github.com/shuvo-paul/uptimebot/internal/incident/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSlackIncidentService struct {
+	acknowledgeFromSlackFunc  func(id int, slackUser string) error
+	getUnresolvedByUserIDFunc func(userID int) ([]*incidentModel.Incident, error)
+}
+
+func (m *mockSlackIncidentService) AcknowledgeFromSlack(ctx context.Context, id int, slackUser string) error {
+	return m.acknowledgeFromSlackFunc(id, slackUser)
+}
+
+func (m *mockSlackIncidentService) GetUnresolvedByUserID(ctx context.Context, userID int) ([]*incidentModel.Incident, error) {
+	return m.getUnresolvedByUserIDFunc(userID)
+}
+
+type mockSlackTargetService struct {
+	pauseFromSlackFunc func(id int, until time.Time) (monitorModel.UserTarget, error)
+	pauseFunc          func(id int, userID int, until time.Time) (monitorModel.UserTarget, error)
+	getAllByUserIDFunc func(userID int) ([]monitorModel.UserTarget, error)
+}
+
+func (m *mockSlackTargetService) PauseFromSlack(ctx context.Context, id int, until time.Time) (monitorModel.UserTarget, error) {
+	return m.pauseFromSlackFunc(id, until)
+}
+
+func (m *mockSlackTargetService) Pause(ctx context.Context, id int, userID int, until time.Time) (monitorModel.UserTarget, error) {
+	return m.pauseFunc(id, userID, until)
+}
+
+func (m *mockSlackTargetService) GetAllByUserID(ctx context.Context, userID int) ([]monitorModel.UserTarget, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+// recordingClient keeps the bodies posted to Slack response URLs
+type recordingClient struct {
+	bodies []map[string]any
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	var body map[string]any
+	json.NewDecoder(req.Body).Decode(&body)
+	c.bodies = append(c.bodies, body)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestSlackHandler_Interact(t *testing.T) {
+	t.Setenv("SLACK_SIGNING_SECRET", "secret")
+	now := time.Date(2025, 4, 28, 9, 0, 0, 0, time.UTC)
+
+	var acknowledged []int
+	var paused []time.Time
+	incidents := &mockSlackIncidentService{
+		acknowledgeFromSlackFunc: func(id int, slackUser string) error {
+			if id != 7 {
+				return incidentService.ErrIncidentNotFound
+			}
+			acknowledged = append(acknowledged, id)
+			return nil
+		},
+	}
+	targets := &mockSlackTargetService{
+		pauseFromSlackFunc: func(id int, until time.Time) (monitorModel.UserTarget, error) {
+			paused = append(paused, until)
+			return monitorModel.UserTarget{Target: &monitor.Target{ID: id, PausedUntil: &until}}, nil
+		},
+	}
+	client := &recordingClient{}
+	handler := NewSlackHandler(incidents, targets, nil, client)
+	handler.now = func() time.Time { return now }
+
+	request := func(actionID string, value string, signingSecret string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(map[string]any{
+			"type":         "block_actions",
+			"response_url": "https://hooks.slack.com/actions/T1/1/abc",
+			"actions":      []map[string]string{{"action_id": actionID, "value": value}},
+			"user":         map[string]string{"id": "U1", "username": "alice"},
+			"message": map[string]any{
+				"text": "Status Update for https://example.org",
+				"blocks": []map[string]any{
+					{"type": "header", "text": map[string]string{"type": "plain_text", "text": "Status Update"}},
+					{"type": "actions", "block_id": "actions", "elements": []map[string]string{
+						{"type": "button", "action_id": provider.SlackActionAcknowledge, "value": "a"},
+						{"type": "button", "action_id": provider.SlackActionPause, "value": "p"},
+					}},
+				},
+			},
+		})
+		body := url.Values{"payload": {string(payload)}}.Encode()
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+
+		req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", provider.SignSlackRequest(signingSecret, timestamp, []byte(body)))
+		w := httptest.NewRecorder()
+
+		handler.Interact(w, req)
+		return w
+	}
+	last := func() map[string]any {
+		return client.bodies[len(client.bodies)-1]
+	}
+
+	t.Run("forged request", func(t *testing.T) {
+		w := request(provider.SlackActionAcknowledge, provider.SignActionValue("secret", "acknowledge:7"), "other")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, client.bodies)
+	})
+
+	t.Run("forged button", func(t *testing.T) {
+		w := request(provider.SlackActionAcknowledge, provider.SignActionValue("other", "acknowledge:7"), "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ephemeral", last()["response_type"])
+		assert.Equal(t, "This button is not valid", last()["text"])
+	})
+
+	t.Run("acknowledge", func(t *testing.T) {
+		w := request(provider.SlackActionAcknowledge, provider.SignActionValue("secret", "acknowledge:7"), "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		response := last()
+		assert.Equal(t, true, response["replace_original"])
+		blocks, _ := json.Marshal(response["blocks"])
+		assert.NotContains(t, string(blocks), provider.SlackActionAcknowledge)
+		assert.Contains(t, string(blocks), provider.SlackActionPause)
+		assert.Contains(t, string(blocks), "Acknowledged by @alice")
+	})
+
+	t.Run("already acknowledged", func(t *testing.T) {
+		request(provider.SlackActionAcknowledge, provider.SignActionValue("secret", "acknowledge:8"), "secret")
+		assert.Equal(t, "This incident is already acknowledged or resolved", last()["text"])
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		w := request(provider.SlackActionPause, provider.SignActionValue("secret", "pause:3"), "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		blocks, _ := json.Marshal(last()["blocks"])
+		assert.Contains(t, string(blocks), "Monitoring paused until Apr 28 10:00 UTC by @alice")
+	})
+
+	t.Run("button replayed for another action", func(t *testing.T) {
+		request(provider.SlackActionPause, provider.SignActionValue("secret", "acknowledge:7"), "secret")
+		assert.Equal(t, "This button is not valid", last()["text"])
+	})
+
+	t.Run("link button", func(t *testing.T) {
+		sent := len(client.bodies)
+		w := request(provider.SlackActionOpenDashboard, "", "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, client.bodies, sent)
+	})
+
+	assert.Equal(t, []int{7}, acknowledged)
+	assert.Equal(t, []time.Time{now.Add(time.Hour)}, paused)
+}
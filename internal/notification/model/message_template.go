@@ -0,0 +1,238 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/pkg/textutil"
+)
+
+// Default templates reproduce the built-in wording of notifications
+const (
+	DefaultTitleTemplate = "Status Update for {{.Name}}"
+	DefaultBodyTemplate  = "{{.Message}}"
+)
+
+const (
+	// maxTemplateLength bounds the size of a template a user can save
+	maxTemplateLength = 2000
+	// maxRenderedLength bounds the size of a rendered title or body
+	maxRenderedLength = 4000
+)
+
+var errRenderedTooLong = errors.New("rendered message is too long")
+
+// templateFuncs are the only functions a message template can call, on top
+// of the comparison and logic builtins allowed by checkPipe
+var templateFuncs = template.FuncMap{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"truncate": truncate,
+}
+
+// allowedIdentifiers lists the functions a message template may call
+var allowedIdentifiers = map[string]bool{
+	"upper": true, "lower": true, "truncate": true,
+	"eq": true, "ne": true, "and": true, "or": true, "not": true, "len": true,
+}
+
+// MessageTemplate overrides the wording of a notifier's messages.
+// An empty title or body keeps the default wording for that part.
+type MessageTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// MessageData holds the values a message template can refer to
+type MessageData struct {
+	Name           string
+	URL            string
+	Status         string
+	PreviousStatus string
+	Duration       string
+	Error          string
+	Link           string
+	Message        string
+	Time           string
+}
+
+// NewMessageData exposes a state to message templates
+func NewMessageData(state notification.State) MessageData {
+	data := MessageData{
+		Name:           state.Name,
+		URL:            state.URL,
+		Status:         state.Status,
+		PreviousStatus: state.PreviousStatus,
+		Error:          state.Error,
+		Link:           state.Link,
+		Message:        state.Message,
+		Time:           state.UpdatedAt.Format(time.RFC1123),
+	}
+	if state.Duration > 0 {
+		data.Duration = state.Duration.Truncate(time.Second).String()
+	}
+	return data
+}
+
+// SampleState is a recovery used to preview and validate templates
+var SampleState = notification.State{
+	Name:           "https://example.com",
+	URL:            "https://example.com",
+	Status:         "up",
+	PreviousStatus: "down",
+	Duration:       12*time.Minute + 30*time.Second,
+	Error:          "HTTP 503 Service Unavailable",
+	Link:           "https://uptimebot.example/app/incidents/1",
+	Message:        "Target https://example.com is up after being down for 12m30s",
+	UpdatedAt:      time.Date(2025, 4, 20, 10, 12, 30, 0, time.UTC),
+}
+
+// Validate checks that both parts parse, only use the allowed actions and
+// functions, and render the sample state
+func (t *MessageTemplate) Validate() error {
+	_, _, err := t.Render(SampleState)
+	return err
+}
+
+// Render produces the title and body of the message for a state
+func (t *MessageTemplate) Render(state notification.State) (string, string, error) {
+	data := NewMessageData(state)
+
+	title, err := renderTemplate("title", t.Title, DefaultTitleTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := renderTemplate("body", t.Body, DefaultBodyTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+// Apply returns the state reworded by the template
+func (t *MessageTemplate) Apply(state notification.State) (notification.State, error) {
+	title, body, err := t.Render(state)
+	if err != nil {
+		return state, err
+	}
+	state.Title = title
+	state.Message = body
+	return state, nil
+}
+
+func renderTemplate(name string, text string, fallback string, data MessageData) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	if len(text) > maxTemplateLength {
+		return "", fmt.Errorf("%s template is longer than %d characters", name, maxTemplateLength)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	if len(tmpl.Templates()) > 1 {
+		return "", fmt.Errorf("invalid %s template: defining templates is not allowed", name)
+	}
+	if err := checkNode(tmpl.Tree.Root); err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	out := &limitedBuilder{limit: maxRenderedLength}
+	if err := tmpl.Execute(out, data); err != nil {
+		if errors.Is(err, errRenderedTooLong) {
+			return "", fmt.Errorf("%s is longer than %d characters once rendered", name, maxRenderedLength)
+		}
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// checkNode keeps templates to plain values, if and with. Loops and template
+// calls are rejected, so rendering always finishes quickly.
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			if err := checkNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.TextNode, *parse.CommentNode:
+		return nil
+	case *parse.ActionNode:
+		return checkPipe(n.Pipe)
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	default:
+		return fmt.Errorf("only values, if and with are allowed, found %s", node)
+	}
+}
+
+func checkBranch(branch *parse.BranchNode) error {
+	if err := checkPipe(branch.Pipe); err != nil {
+		return err
+	}
+	if err := checkNode(branch.List); err != nil {
+		return err
+	}
+	if branch.ElseList != nil {
+		return checkNode(branch.ElseList)
+	}
+	return nil
+}
+
+func checkPipe(pipe *parse.PipeNode) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.IdentifierNode:
+				if !allowedIdentifiers[a.Ident] {
+					return fmt.Errorf("function %q is not allowed", a.Ident)
+				}
+			case *parse.PipeNode:
+				if err := checkPipe(a); err != nil {
+					return err
+				}
+			case *parse.ChainNode:
+				if inner, ok := a.Node.(*parse.PipeNode); ok {
+					if err := checkPipe(inner); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// truncate is textutil.Truncate with the text last, so that templates can
+// pipe it in
+func truncate(limit int, text string) string {
+	return textutil.Truncate(text, limit)
+}
+
+// limitedBuilder collects rendered output and fails once it grows past the limit
+type limitedBuilder struct {
+	strings.Builder
+	limit int
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errRenderedTooLong
+	}
+	return b.Builder.Write(p)
+}
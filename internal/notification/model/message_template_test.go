@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplate_DefaultsMatchBuiltInWording(t *testing.T) {
+	state := notification.State{Name: "https://example.com", Status: "down", Message: "Target https://example.com is down"}
+
+	title, body, err := (&MessageTemplate{}).Render(state)
+	assert.NoError(t, err)
+	assert.Equal(t, "Status Update for https://example.com", title)
+	assert.Equal(t, "Target https://example.com is down", body)
+}
+
+func TestMessageTemplate_Render(t *testing.T) {
+	tmpl := &MessageTemplate{
+		Title: "{{upper .Status}}: {{.Name}}",
+		Body:  "{{if eq .Status \"up\"}}Back after {{.Duration}}{{else}}{{.Error}}{{end}} - {{.Link}}",
+	}
+
+	state := notification.State{
+		Name:      "api",
+		Status:    "up",
+		Duration:  90*time.Second + 400*time.Millisecond,
+		Link:      "https://uptimebot.example/app/incidents/3",
+		UpdatedAt: time.Now(),
+	}
+
+	title, body, err := tmpl.Render(state)
+	assert.NoError(t, err)
+	assert.Equal(t, "UP: api", title)
+	assert.Equal(t, "Back after 1m30s - https://uptimebot.example/app/incidents/3", body)
+
+	applied, err := tmpl.Apply(state)
+	assert.NoError(t, err)
+	assert.Equal(t, "UP: api", applied.Title)
+	assert.Equal(t, body, applied.Message)
+}
+
+func TestMessageTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    MessageTemplate
+		wantErr string
+	}{
+		{"empty uses defaults", MessageTemplate{}, ""},
+		{"allowed functions", MessageTemplate{Body: "{{truncate 10 .Error | lower}}"}, ""},
+		{"syntax error", MessageTemplate{Title: "{{.Name"}, "invalid title template"},
+		{"unknown field", MessageTemplate{Body: "{{.Password}}"}, "failed to render body template"},
+		{"printf", MessageTemplate{Body: "{{printf \"%s\" .Name}}"}, `function "printf" is not allowed`},
+		{"call", MessageTemplate{Body: "{{call .Name}}"}, `function "call" is not allowed`},
+		{"nested", MessageTemplate{Body: "{{if (print .Name)}}x{{end}}"}, `function "print" is not allowed`},
+		{"range", MessageTemplate{Body: "{{range .Name}}{{end}}"}, "only values, if and with are allowed"},
+		{"define", MessageTemplate{Body: `{{define "x"}}y{{end}}`}, "defining templates is not allowed"},
+		{"too long", MessageTemplate{Body: strings.Repeat("a", maxTemplateLength+1)}, "longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tmpl.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMessageTemplate_RenderedLengthIsLimited(t *testing.T) {
+	tmpl := &MessageTemplate{Body: strings.Repeat("{{.Message}}", 10)}
+	state := notification.State{Message: strings.Repeat("a", maxRenderedLength/5)}
+
+	_, _, err := tmpl.Render(state)
+	assert.ErrorContains(t, err, "longer than")
+}
@@ -2,6 +2,12 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 )
 
 // NotifierType represents the type of notifier
@@ -12,14 +18,60 @@ const (
 	NotifierTypeEmail NotifierType = "email"
 )
 
-// Notifier represents a notification channel configuration
+// Events a notifier can subscribe to. They match the status carried by a state.
+const (
+	EventUp       = "up"
+	EventDown     = "down"
+	EventError    = "error"
+	EventPaused   = "paused"
+	EventFlapping = "flapping"
+	EventBurnRate = "burn-rate"
+)
+
+// Events lists every event a notifier can subscribe to
+var Events = []string{EventDown, EventError, EventUp, EventPaused, EventFlapping, EventBurnRate}
+
+// Notifier represents a user-level contact channel that can be attached to many targets
 type Notifier struct {
-	ID       int             `db:"id"`
-	TargetId int             `db:"target_id"`
-	Type     NotifierType    `db:"type"`
-	Config   json.RawMessage `db:"config"`
+	ID         int              `db:"id"`
+	UserID     int              `db:"user_id"`
+	Name       string           `db:"name"`
+	Type       NotifierType     `db:"type"`
+	Config     json.RawMessage  `db:"config"`
+	Events     []string         `db:"events"`           // empty subscribes to every event
+	QuietHours *QuietHours      `db:"quiet_hours"`      // nil never silences the notifier
+	Template   *MessageTemplate `db:"message_template"` // nil keeps the default wording
+	RateLimit  int              `db:"rate_limit"`       // most notifications delivered per hour, zero is unlimited
+}
+
+// MaxRateLimit bounds the hourly rate limit of a notifier
+const MaxRateLimit = 1000
+
+// Subscribes reports whether the notifier wants to hear about the event
+func (n *Notifier) Subscribes(event string) bool {
+	return len(n.Events) == 0 || slices.Contains(n.Events, event)
+}
+
+// Decide applies the event filter and quiet hours to a state raised at the given time
+func (n *Notifier) Decide(state notification.State, now time.Time) notification.Decision {
+	if !n.Subscribes(state.Status) {
+		return notification.Drop
+	}
+
+	if n.QuietHours != nil && n.QuietHours.Active(now) {
+		if n.QuietHours.Action == QuietActionDigest {
+			return notification.Defer
+		}
+		return notification.Drop
+	}
+
+	return notification.Deliver
 }
 
+// SlackWebhookPrefix starts every Slack incoming webhook. Other URLs are
+// refused so a notifier cannot make the server post to arbitrary hosts.
+const SlackWebhookPrefix = "https://hooks.slack.com/"
+
 // SlackConfig represents Slack notifier configuration
 type SlackConfig struct {
 	WebhookURL string `json:"webhook_url"`
@@ -53,3 +105,49 @@ func (n *Notifier) GetEmailConfig() (*EmailConfig, error) {
 	}
 	return &config, nil
 }
+
+// Validate checks that the configuration matches the notifier type and that
+// the event filter, quiet hours, message template and rate limit are well formed
+func (n *Notifier) Validate() error {
+	if n.RateLimit < 0 || n.RateLimit > MaxRateLimit {
+		return fmt.Errorf("rate limit must be between 0 and %d notifications per hour", MaxRateLimit)
+	}
+
+	for _, event := range n.Events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("unsupported event: %s", event)
+		}
+	}
+
+	if n.QuietHours != nil {
+		if err := n.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if n.Template != nil {
+		if err := n.Template.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch n.Type {
+	case NotifierTypeSlack:
+		config, err := n.GetSlackConfig()
+		if err != nil {
+			return fmt.Errorf("invalid slack config: %w", err)
+		}
+		if config.WebhookURL == "" {
+			return fmt.Errorf("webhook URL is required for slack notifier")
+		}
+		if !strings.HasPrefix(config.WebhookURL, SlackWebhookPrefix) {
+			return fmt.Errorf("webhook URL must start with %s", SlackWebhookPrefix)
+		}
+	case NotifierTypeEmail:
+		// No provider delivers email notifications yet
+		return fmt.Errorf("email notifiers are not supported yet")
+	default:
+		return fmt.Errorf("unsupported notifier type: %s", n.Type)
+	}
+	return nil
+}
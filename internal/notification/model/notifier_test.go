@@ -3,7 +3,9 @@ package model
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -115,3 +117,184 @@ func TestNotifier_GetEmailConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifier_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier *Notifier
+		wantErr  string
+	}{
+		{
+			name: "valid slack notifier",
+			notifier: &Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+			},
+		},
+		{
+			name: "slack notifier without webhook",
+			notifier: &Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`{}`),
+			},
+			wantErr: "webhook URL is required",
+		},
+		{
+			name: "slack notifier posting elsewhere",
+			notifier: &Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "http://169.254.169.254/latest/meta-data"}`),
+			},
+			wantErr: "webhook URL must start with https://hooks.slack.com/",
+		},
+		{
+			name: "email notifier",
+			notifier: &Notifier{
+				Type:   NotifierTypeEmail,
+				Config: json.RawMessage(`{"recipients": ["ops@example.com"]}`),
+			},
+			wantErr: "email notifiers are not supported yet",
+		},
+		{
+			name: "unknown event",
+			notifier: &Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+				Events: []string{EventDown, "exploded"},
+			},
+			wantErr: "unsupported event",
+		},
+		{
+			name: "invalid quiet hours",
+			notifier: &Notifier{
+				Type:       NotifierTypeSlack,
+				Config:     json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+				QuietHours: &QuietHours{Timezone: "Mars/Base", Windows: []QuietWindow{{Start: "22:00", End: "07:00"}}, Action: QuietActionDrop},
+			},
+			wantErr: "invalid timezone",
+		},
+		{
+			name: "negative rate limit",
+			notifier: &Notifier{
+				Type:      NotifierTypeSlack,
+				Config:    json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+				RateLimit: -1,
+			},
+			wantErr: "rate limit must be between",
+		},
+		{
+			name: "invalid json",
+			notifier: &Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`invalid json`),
+			},
+			wantErr: "invalid slack config",
+		},
+		{
+			name: "unsupported type",
+			notifier: &Notifier{
+				Type:   "sms",
+				Config: json.RawMessage(`{}`),
+			},
+			wantErr: "unsupported notifier type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.notifier.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNotifier_Decide(t *testing.T) {
+	utc := func(hour int) time.Time {
+		return time.Date(2025, 4, 20, hour, 0, 0, 0, time.UTC)
+	}
+	down := notification.State{Name: "example.org", Status: EventDown}
+	up := notification.State{Name: "example.org", Status: EventUp}
+
+	tests := []struct {
+		name     string
+		notifier *Notifier
+		state    notification.State
+		at       time.Time
+		want     notification.Decision
+	}{
+		{
+			name:     "no filter delivers everything",
+			notifier: &Notifier{},
+			state:    up,
+			at:       utc(12),
+			want:     notification.Deliver,
+		},
+		{
+			name:     "unsubscribed event is dropped",
+			notifier: &Notifier{Events: []string{EventDown}},
+			state:    up,
+			at:       utc(12),
+			want:     notification.Drop,
+		},
+		{
+			name:     "subscribed event is delivered",
+			notifier: &Notifier{Events: []string{EventDown}},
+			state:    down,
+			at:       utc(12),
+			want:     notification.Deliver,
+		},
+		{
+			name: "quiet hours drop",
+			notifier: &Notifier{QuietHours: &QuietHours{
+				Windows: []QuietWindow{{Start: "22:00", End: "07:00"}},
+				Action:  QuietActionDrop,
+			}},
+			state: down,
+			at:    utc(3),
+			want:  notification.Drop,
+		},
+		{
+			name: "quiet hours digest",
+			notifier: &Notifier{QuietHours: &QuietHours{
+				Windows: []QuietWindow{{Start: "22:00", End: "07:00"}},
+				Action:  QuietActionDigest,
+			}},
+			state: down,
+			at:    utc(3),
+			want:  notification.Defer,
+		},
+		{
+			name: "outside quiet hours",
+			notifier: &Notifier{QuietHours: &QuietHours{
+				Windows: []QuietWindow{{Start: "22:00", End: "07:00"}},
+				Action:  QuietActionDigest,
+			}},
+			state: down,
+			at:    utc(9),
+			want:  notification.Deliver,
+		},
+		{
+			name: "filter applies before quiet hours",
+			notifier: &Notifier{
+				Events: []string{EventDown},
+				QuietHours: &QuietHours{
+					Windows: []QuietWindow{{Start: "22:00", End: "07:00"}},
+					Action:  QuietActionDigest,
+				},
+			},
+			state: up,
+			at:    utc(3),
+			want:  notification.Drop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.notifier.Decide(tt.state, tt.at))
+		})
+	}
+}
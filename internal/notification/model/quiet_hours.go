@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietAction decides what happens to events raised during quiet hours
+type QuietAction string
+
+const (
+	QuietActionDrop   QuietAction = "drop"
+	QuietActionDigest QuietAction = "digest"
+)
+
+// QuietWindow is a daily time range in "15:04" format. A window whose end is
+// before its start wraps around midnight.
+type QuietWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// QuietHours silences a notifier during daily windows in the given timezone
+type QuietHours struct {
+	Timezone string        `json:"timezone"`
+	Windows  []QuietWindow `json:"windows"`
+	Action   QuietAction   `json:"action"`
+}
+
+// Validate checks the timezone, windows and action
+func (q *QuietHours) Validate() error {
+	if _, err := q.location(); err != nil {
+		return err
+	}
+	if len(q.Windows) == 0 {
+		return fmt.Errorf("at least one quiet window is required")
+	}
+	for _, w := range q.Windows {
+		if _, _, err := w.minutes(); err != nil {
+			return err
+		}
+	}
+	switch q.Action {
+	case QuietActionDrop, QuietActionDigest:
+	default:
+		return fmt.Errorf("unsupported quiet hours action: %s", q.Action)
+	}
+	return nil
+}
+
+// Active reports whether t falls inside one of the windows
+func (q *QuietHours) Active(t time.Time) bool {
+	loc, err := q.location()
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	for _, w := range q.Windows {
+		start, end, err := w.minutes()
+		if err != nil {
+			continue
+		}
+		if start <= end {
+			if now >= start && now < end {
+				return true
+			}
+		} else if now >= start || now < end {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *QuietHours) location() (*time.Location, error) {
+	if q.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", q.Timezone)
+	}
+	return loc, nil
+}
+
+func (w QuietWindow) minutes() (int, int, error) {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet window start %q", w.Start)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet window end %q", w.End)
+	}
+	if start.Equal(end) {
+		return 0, 0, fmt.Errorf("quiet window %s-%s is empty", w.Start, w.End)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// ParseQuietWindows parses a comma separated list such as "22:00-07:00, 12:00-13:00"
+func ParseQuietWindows(input string) ([]QuietWindow, error) {
+	var windows []QuietWindow
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		start, end, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet window %q", part)
+		}
+		window := QuietWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+		if _, _, err := window.minutes(); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// FormatQuietWindows is the inverse of ParseQuietWindows
+func FormatQuietWindows(windows []QuietWindow) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.Start + "-" + w.End
+	}
+	return strings.Join(parts, ", ")
+}
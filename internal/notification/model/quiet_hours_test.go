@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHours_Active(t *testing.T) {
+	quiet := &QuietHours{
+		Timezone: "America/New_York",
+		Windows:  []QuietWindow{{Start: "22:00", End: "07:00"}, {Start: "12:00", End: "13:00"}},
+		Action:   QuietActionDrop,
+	}
+	assert.NoError(t, quiet.Validate())
+
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"late evening", time.Date(2025, 4, 20, 23, 30, 0, 0, ny), true},
+		{"early morning", time.Date(2025, 4, 20, 6, 59, 0, 0, ny), true},
+		{"end is exclusive", time.Date(2025, 4, 20, 7, 0, 0, 0, ny), false},
+		{"lunch", time.Date(2025, 4, 20, 12, 15, 0, 0, ny), true},
+		{"afternoon", time.Date(2025, 4, 20, 15, 0, 0, 0, ny), false},
+		{"converted from UTC", time.Date(2025, 4, 20, 3, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quiet.Active(tt.at))
+		})
+	}
+}
+
+func TestQuietHours_Validate(t *testing.T) {
+	window := []QuietWindow{{Start: "22:00", End: "07:00"}}
+
+	assert.Error(t, (&QuietHours{Timezone: "Mars/Base", Windows: window, Action: QuietActionDrop}).Validate())
+	assert.Error(t, (&QuietHours{Windows: window, Action: "snooze"}).Validate())
+	assert.Error(t, (&QuietHours{Action: QuietActionDigest}).Validate())
+	assert.Error(t, (&QuietHours{Windows: []QuietWindow{{Start: "25:00", End: "07:00"}}, Action: QuietActionDrop}).Validate())
+	assert.NoError(t, (&QuietHours{Windows: window, Action: QuietActionDigest}).Validate())
+}
+
+func TestParseQuietWindows(t *testing.T) {
+	windows, err := ParseQuietWindows("22:00-07:00, 12:00 - 13:00,")
+	assert.NoError(t, err)
+	assert.Equal(t, []QuietWindow{{Start: "22:00", End: "07:00"}, {Start: "12:00", End: "13:00"}}, windows)
+	assert.Equal(t, "22:00-07:00, 12:00-13:00", FormatQuietWindows(windows))
+
+	windows, err = ParseQuietWindows("")
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	_, err = ParseQuietWindows("22:00")
+	assert.Error(t, err)
+
+	_, err = ParseQuietWindows("08:00-08:00")
+	assert.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// SlackInstallation records which uptimebot user connected Slack from which
+// Slack account, so requests sent by that account act as the user
+type SlackInstallation struct {
+	UserID      int       `db:"user_id"`
+	TeamID      string    `db:"team_id"`       // Slack workspace
+	SlackUserID string    `db:"slack_user_id"` // Slack account that authorized the app
+	CreatedAt   time.Time `db:"created_at"`
+}
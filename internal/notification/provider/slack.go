@@ -5,14 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/pkg/textutil"
 )
 
 // SlackObserver implements the Observer interface for Slack notifications
 type SlackObserver struct {
-	webhookURL string
-	client     HTTPClient
+	webhookURL    string
+	client        HTTPClient
+	signingSecret string // empty leaves the Acknowledge and Pause buttons out of messages
 }
 
 // HTTPClient interface for making HTTP requests
@@ -20,69 +24,146 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-// NewSlackObserver creates a new Slack observer
-func NewSlackObserver(webhookURL string, client HTTPClient) *SlackObserver {
+// NewSlackObserver creates a new Slack observer. The signing secret of the
+// Slack app signs the values of interactive buttons.
+func NewSlackObserver(webhookURL string, client HTTPClient, signingSecret string) *SlackObserver {
 	if client == nil {
 		client = http.DefaultClient
 	}
 	return &SlackObserver{
-		webhookURL: webhookURL,
-		client:     client,
+		webhookURL:    webhookURL,
+		client:        client,
+		signingSecret: signingSecret,
 	}
 }
 
+// slackMessage is a Block Kit message. Text is shown in notifications and
+// by clients that cannot display blocks.
 type slackMessage struct {
-	Text        string       `json:"text"`
-	Attachments []attachment `json:"attachments"`
+	Text   string  `json:"text"`
+	Blocks []block `json:"blocks"`
 }
 
-type attachment struct {
-	Color  string  `json:"color"`
-	Fields []field `json:"fields"`
+type block struct {
+	Type     string       `json:"type"`
+	BlockID  string       `json:"block_id,omitempty"`
+	Text     *textObject  `json:"text,omitempty"`
+	Fields   []textObject `json:"fields,omitempty"`
+	Elements []button     `json:"elements,omitempty"`
 }
 
-type field struct {
-	Title string `json:"title"`
-	Value string `json:"value"`
-	Short bool   `json:"short"`
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
 }
 
+type button struct {
+	Type     string     `json:"type"`
+	Text     textObject `json:"text"`
+	ActionID string     `json:"action_id"`
+	Value    string     `json:"value,omitempty"`
+	URL      string     `json:"url,omitempty"`
+	Style    string     `json:"style,omitempty"`
+}
+
+// Action identifiers of message buttons, echoed back by Slack when a button is clicked
+const (
+	SlackActionAcknowledge   = "acknowledge"
+	SlackActionPause         = "pause"
+	SlackActionOpenDashboard = "open_dashboard"
+)
+
+// slackActionsBlock identifies the block holding the buttons of a message
+const slackActionsBlock = "actions"
+
+// Block Kit limits on the length of texts
+const (
+	headerLimit  = 150
+	sectionLimit = 3000
+)
+
 // Notify implements the Observer interface
 func (s *SlackObserver) Notify(state notification.State) error {
-	color := "warning"
-	if state.Status == "up" {
-		color = "good"
-	} else if state.Status == "down" {
-		color = "danger"
+	title := state.Title
+	if title == "" {
+		title = fmt.Sprintf("Status Update for %s", state.Name)
 	}
 
 	msg := slackMessage{
-		Text: fmt.Sprintf("Status Update for %s", state.Name),
-		Attachments: []attachment{
+		Text: title,
+		Blocks: []block{
+			{Type: "header", Text: &textObject{Type: "plain_text", Text: textutil.Truncate(title, headerLimit)}},
 			{
-				Color: color,
-				Fields: []field{
-					{Title: "Name", Value: state.Name, Short: true},
-					{Title: "Status", Value: state.Status, Short: true},
-					{Title: "Time", Value: state.UpdatedAt.String(), Short: true},
-					{Title: "Message", Value: state.Message, Short: false},
+				Type: "section",
+				Fields: []textObject{
+					{Type: "mrkdwn", Text: "*Name*\n" + EscapeSlackText(state.Name)},
+					{Type: "mrkdwn", Text: "*Status*\n" + SlackStatusEmoji(state.Status) + " " + EscapeSlackText(state.Status)},
+					{Type: "mrkdwn", Text: "*Time*\n" + state.UpdatedAt.String()},
 				},
 			},
 		},
 	}
+	if state.Message != "" {
+		msg.Blocks = append(msg.Blocks, block{
+			Type: "section",
+			Text: &textObject{Type: "mrkdwn", Text: textutil.Truncate(EscapeSlackText(state.Message), sectionLimit)},
+		})
+	}
+	if buttons := s.buttons(state); len(buttons) > 0 {
+		msg.Blocks = append(msg.Blocks, block{Type: "actions", BlockID: slackActionsBlock, Elements: buttons})
+	}
 
 	payload, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal slack message: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewBuffer(payload))
+	return postSlack(s.client, s.webhookURL, payload)
+}
+
+// buttons lists the actions available for a state. Failures can be
+// acknowledged and paused right from Slack; their values are signed so only
+// buttons sent by us are acted on.
+func (s *SlackObserver) buttons(state notification.State) []button {
+	var buttons []button
+	failing := state.Status != "up"
+
+	if failing && state.IncidentID > 0 && s.signingSecret != "" {
+		buttons = append(buttons, button{
+			Type:     "button",
+			Text:     textObject{Type: "plain_text", Text: "Acknowledge"},
+			ActionID: SlackActionAcknowledge,
+			Value:    SignActionValue(s.signingSecret, SlackActionAcknowledge+":"+strconv.Itoa(state.IncidentID)),
+			Style:    "primary",
+		})
+	}
+	if failing && state.TargetID > 0 && s.signingSecret != "" {
+		buttons = append(buttons, button{
+			Type:     "button",
+			Text:     textObject{Type: "plain_text", Text: "Pause monitoring 1h"},
+			ActionID: SlackActionPause,
+			Value:    SignActionValue(s.signingSecret, SlackActionPause+":"+strconv.Itoa(state.TargetID)),
+		})
+	}
+	if state.Link != "" {
+		buttons = append(buttons, button{
+			Type:     "button",
+			Text:     textObject{Type: "plain_text", Text: "Open dashboard"},
+			ActionID: SlackActionOpenDashboard,
+			URL:      state.Link,
+		})
+	}
+	return buttons
+}
+
+func postSlack(client HTTPClient, url string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send slack message: %w", err)
 	}
@@ -94,3 +175,20 @@ func (s *SlackObserver) Notify(state notification.State) error {
 
 	return nil
 }
+
+// SlackStatusEmoji returns the emoji shown next to a target status
+func SlackStatusEmoji(status string) string {
+	switch status {
+	case "up":
+		return ":large_green_circle:"
+	case "down":
+		return ":red_circle:"
+	default:
+		return ":warning:"
+	}
+}
+
+// EscapeSlackText escapes the characters Slack reads as markup
+func EscapeSlackText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/shuvo-paul/uptimebot/pkg/textutil"
+)
+
+// blockLimit is the most blocks Slack accepts in a message
+const blockLimit = 50
+
+// SlackCommandResponse answers a slash command with a message only the
+// requesting user sees. The lines are mrkdwn and are packed into as few
+// sections as the Block Kit limits allow; lines past the limits are dropped.
+func SlackCommandResponse(title string, lines []string) SlackResponse {
+	blocks := []block{{Type: "header", Text: &textObject{Type: "plain_text", Text: textutil.Truncate(title, headerLimit)}}}
+
+	var section strings.Builder
+	flush := func() {
+		if section.Len() > 0 {
+			blocks = append(blocks, block{Type: "section", Text: &textObject{Type: "mrkdwn", Text: section.String()}})
+			section.Reset()
+		}
+	}
+	for _, line := range lines {
+		line = textutil.Truncate(line, sectionLimit)
+		if section.Len()+len(line)+1 > sectionLimit {
+			flush()
+			if len(blocks) == blockLimit {
+				break
+			}
+		}
+		if section.Len() > 0 {
+			section.WriteString("\n")
+		}
+		section.WriteString(line)
+	}
+	flush()
+
+	response := SlackResponse{ResponseType: "ephemeral", Text: title}
+	for _, b := range blocks {
+		raw, err := json.Marshal(b)
+		if err != nil {
+			continue
+		}
+		response.Blocks = append(response.Blocks, raw)
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackCommandResponse(t *testing.T) {
+	response := SlackCommandResponse("Target status", []string{"first", "second"})
+	assert.Equal(t, "ephemeral", response.ResponseType)
+	assert.Equal(t, "Target status", response.Text)
+	assert.Len(t, response.Blocks, 2)
+
+	var section block
+	assert.NoError(t, json.Unmarshal(response.Blocks[1], &section))
+	assert.Equal(t, "first\nsecond", section.Text.Text)
+
+	// Long lists are split over sections and cut at the block limit
+	lines := make([]string, 200)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 1000)
+	}
+	response = SlackCommandResponse("Target status", lines)
+	assert.Len(t, response.Blocks, blockLimit)
+	for _, raw := range response.Blocks[1:] {
+		assert.NoError(t, json.Unmarshal(raw, &section))
+		assert.LessOrEqual(t, len(section.Text.Text), sectionLimit)
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// slackResponseHost is where Slack hosts the response URLs of interactions
+const slackResponseHost = "https://hooks.slack.com/"
+
+// SlackResponse answers an interaction through its response URL, either by
+// replacing the original message or with a message only the clicking user sees
+type SlackResponse struct {
+	ResponseType    string            `json:"response_type,omitempty"`
+	ReplaceOriginal bool              `json:"replace_original"`
+	Text            string            `json:"text"`
+	Blocks          []json.RawMessage `json:"blocks,omitempty"`
+}
+
+// RespondSlack posts a response to the response URL of an interaction
+func RespondSlack(client HTTPClient, responseURL string, response SlackResponse) error {
+	if !strings.HasPrefix(responseURL, slackResponseHost) {
+		return fmt.Errorf("invalid slack response URL: %s", responseURL)
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack response: %w", err)
+	}
+	return postSlack(client, responseURL, payload)
+}
+
+// CompleteSlackAction returns the blocks of a message once one of its buttons
+// did its job: the button is removed and a note about what happened is added
+// below the message.
+func CompleteSlackAction(blocks []json.RawMessage, actionID string, note string) ([]json.RawMessage, error) {
+	completed := make([]json.RawMessage, 0, len(blocks)+1)
+	for _, raw := range blocks {
+		var b struct {
+			Type     string            `json:"type"`
+			BlockID  string            `json:"block_id"`
+			Elements []json.RawMessage `json:"elements"`
+		}
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, fmt.Errorf("invalid slack block: %w", err)
+		}
+		if b.Type != "actions" || b.BlockID != slackActionsBlock {
+			completed = append(completed, raw)
+			continue
+		}
+
+		var remaining []json.RawMessage
+		for _, element := range b.Elements {
+			var e struct {
+				ActionID string `json:"action_id"`
+			}
+			if err := json.Unmarshal(element, &e); err != nil {
+				return nil, fmt.Errorf("invalid slack block element: %w", err)
+			}
+			if e.ActionID != actionID {
+				remaining = append(remaining, element)
+			}
+		}
+		if len(remaining) == 0 {
+			continue
+		}
+		actions, err := json.Marshal(map[string]any{"type": "actions", "block_id": slackActionsBlock, "elements": remaining})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal slack block: %w", err)
+		}
+		completed = append(completed, actions)
+	}
+
+	context, err := json.Marshal(map[string]any{
+		"type":     "context",
+		"elements": []textObject{{Type: "mrkdwn", Text: EscapeSlackText(note)}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal slack block: %w", err)
+	}
+	return append(completed, context), nil
+}
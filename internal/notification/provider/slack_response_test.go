@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespondSlack(t *testing.T) {
+	t.Run("posts to the response URL", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		err := RespondSlack(mockClient, "https://hooks.slack.com/actions/T1/1/abc", SlackResponse{ReplaceOriginal: true, Text: "done"})
+		assert.NoError(t, err)
+		assert.Len(t, mockClient.requests, 1)
+
+		var response SlackResponse
+		assert.NoError(t, json.NewDecoder(mockClient.requests[0].Body).Decode(&response))
+		assert.True(t, response.ReplaceOriginal)
+		assert.Equal(t, "done", response.Text)
+	})
+
+	t.Run("refuses other hosts", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		err := RespondSlack(mockClient, "https://internal.example/admin", SlackResponse{Text: "done"})
+		assert.Error(t, err)
+		assert.Empty(t, mockClient.requests)
+	})
+}
+
+func TestCompleteSlackAction(t *testing.T) {
+	blocks := []json.RawMessage{
+		json.RawMessage(`{"type":"header","text":{"type":"plain_text","text":"Status Update"}}`),
+		json.RawMessage(`{"type":"actions","block_id":"actions","elements":[` +
+			`{"type":"button","action_id":"acknowledge","value":"v"},` +
+			`{"type":"button","action_id":"open_dashboard","url":"https://uptimebot.example"}]}`),
+	}
+
+	completed, err := CompleteSlackAction(blocks, SlackActionAcknowledge, "Acknowledged by @alice")
+	assert.NoError(t, err)
+	assert.Len(t, completed, 3)
+	assert.JSONEq(t, string(blocks[0]), string(completed[0]))
+	assert.JSONEq(t, `{"type":"actions","block_id":"actions","elements":[`+
+		`{"type":"button","action_id":"open_dashboard","url":"https://uptimebot.example"}]}`, string(completed[1]))
+	assert.JSONEq(t, `{"type":"context","elements":[{"type":"mrkdwn","text":"Acknowledged by @alice"}]}`, string(completed[2]))
+
+	t.Run("drops the actions once no button is left", func(t *testing.T) {
+		again, err := CompleteSlackAction(completed, SlackActionOpenDashboard, "Done")
+		assert.NoError(t, err)
+		assert.Len(t, again, 3)
+		assert.Contains(t, string(again[1]), "context")
+	})
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackSignatureMaxAge bounds how old a signed Slack request may be, which stops replays
+const slackSignatureMaxAge = 5 * time.Minute
+
+// ErrInvalidSignature is returned when a Slack request or action value was not signed by us
+var ErrInvalidSignature = errors.New("invalid slack signature")
+
+// SignSlackRequest computes the signature Slack sends along with a request body
+func SignSlackRequest(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySlackSignature checks the X-Slack-Signature header of a request
+// against its body, following Slack's request signing scheme
+func VerifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("%w: signing secret is not configured", ErrInvalidSignature)
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("%w: request is too old", ErrInvalidSignature)
+	}
+
+	expected := SignSlackRequest(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// SignActionValue appends a signature to the value of a message button. Anyone
+// can post a button through an incoming webhook, so the signature proves the
+// button was sent by us.
+func SignActionValue(secret string, value string) string {
+	return value + "." + actionSignature(secret, value)
+}
+
+// VerifyActionValue checks a signed button value and returns the original value
+func VerifyActionValue(secret string, signed string) (string, error) {
+	value, signature, ok := strings.Cut(signed, ".")
+	if !ok || secret == "" {
+		return "", fmt.Errorf("%w: unsigned action value", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(actionSignature(secret, value))) {
+		return "", fmt.Errorf("%w: action value mismatch", ErrInvalidSignature)
+	}
+	return value, nil
+}
+
+func actionSignature(secret string, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("action:" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyActionID checks the signed value of a button sent for an action and
+// returns the ID it carries. Values name their action, so the value of one
+// button cannot be replayed through another.
+func VerifyActionID(secret string, action string, signed string) (int, error) {
+	value, err := VerifyActionValue(secret, signed)
+	if err != nil {
+		return 0, err
+	}
+	rawID, ok := strings.CutPrefix(value, action+":")
+	if !ok {
+		return 0, fmt.Errorf("%w: value is not for action %s", ErrInvalidSignature, action)
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid action ID %q", rawID)
+	}
+	return id, nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1745143200, 0)
+	body := []byte("payload=%7B%7D")
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	signed := func(secret string, timestamp string) http.Header {
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", timestamp)
+		header.Set("X-Slack-Signature", SignSlackRequest(secret, timestamp, body))
+		return header
+	}
+
+	assert.NoError(t, VerifySlackSignature("secret", signed("secret", timestamp), body, now))
+
+	err := VerifySlackSignature("secret", signed("other", timestamp), body, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	err = VerifySlackSignature("secret", signed("secret", timestamp), []byte("payload=tampered"), now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	err = VerifySlackSignature("secret", signed("secret", stale), body, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	err = VerifySlackSignature("", signed("", timestamp), body, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestActionValue(t *testing.T) {
+	signed := SignActionValue("secret", "7")
+
+	value, err := VerifyActionValue("secret", signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", value)
+
+	_, err = VerifyActionValue("other", signed)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyActionValue("secret", "8"+signed[1:])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyActionValue("secret", "7")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifyActionID(t *testing.T) {
+	signed := SignActionValue("secret", SlackActionPause+":7")
+
+	id, err := VerifyActionID("secret", SlackActionPause, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+
+	// The value of a pause button cannot acknowledge incident 7
+	_, err = VerifyActionID("secret", SlackActionAcknowledge, signed)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyActionID("other", SlackActionPause, signed)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyActionID("secret", SlackActionPause, SignActionValue("secret", SlackActionPause+":abc"))
+	assert.Error(t, err)
+}
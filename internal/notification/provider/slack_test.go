@@ -87,7 +87,7 @@ func TestSlackObserver_Notify(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := NewMockHTTPClient(tt.statusCode, tt.err)
-			observer := NewSlackObserver("https://hooks.slack.com/test", mockClient)
+			observer := NewSlackObserver("https://hooks.slack.com/test", mockClient, "")
 
 			err := observer.Notify(tt.state)
 
@@ -109,28 +109,113 @@ func TestSlackObserver_Notify(t *testing.T) {
 				assert.NoError(t, err)
 
 				assert.Contains(t, msg.Text, tt.state.Name)
-				assert.Len(t, msg.Attachments, 1)
-
-				attachment := msg.Attachments[0]
-				expectedColor := "warning"
-				if tt.state.Status == "up" {
-					expectedColor = "good"
-				} else if tt.state.Status == "down" {
-					expectedColor = "danger"
-				}
-				assert.Equal(t, expectedColor, attachment.Color)
-
-				assert.Len(t, attachment.Fields, 4)
-				fields := make(map[string]string)
-				for _, f := range attachment.Fields {
-					fields[f.Title] = f.Value
-				}
-
-				assert.Equal(t, tt.state.Name, fields["Name"])
-				assert.Equal(t, tt.state.Status, fields["Status"])
-				assert.Equal(t, tt.state.Message, fields["Message"])
-				assert.Contains(t, fields["Time"], tt.state.UpdatedAt.String())
+				assert.Equal(t, "header", msg.Blocks[0].Type)
+				assert.Equal(t, msg.Text, msg.Blocks[0].Text.Text)
+
+				fields := msg.Blocks[1].Fields
+				assert.Len(t, fields, 3)
+				assert.Equal(t, "*Name*\n"+tt.state.Name, fields[0].Text)
+				assert.Contains(t, fields[1].Text, tt.state.Status)
+				assert.Contains(t, fields[2].Text, tt.state.UpdatedAt.String())
+				assert.Equal(t, tt.state.Message, msg.Blocks[2].Text.Text)
 			}
 		})
 	}
 }
+
+func TestSlackObserver_NotifyButtons(t *testing.T) {
+	state := notification.State{
+		Name:       "test-system",
+		Status:     "down",
+		UpdatedAt:  time.Now(),
+		IncidentID: 7,
+		TargetID:   3,
+		Link:       "https://uptimebot.example/app/incidents/7",
+	}
+
+	buttons := func(t *testing.T, client *MockHTTPClient) map[string]button {
+		var msg slackMessage
+		assert.NoError(t, json.NewDecoder(client.requests[0].Body).Decode(&msg))
+		found := map[string]button{}
+		for _, b := range msg.Blocks {
+			if b.Type != "actions" {
+				continue
+			}
+			assert.Equal(t, slackActionsBlock, b.BlockID)
+			for _, e := range b.Elements {
+				found[e.ActionID] = e
+			}
+		}
+		return found
+	}
+
+	t.Run("signed buttons for a failure", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		assert.NoError(t, NewSlackObserver("https://hooks.slack.com/test", mockClient, "secret").Notify(state))
+
+		found := buttons(t, mockClient)
+		assert.Len(t, found, 3)
+
+		id, err := VerifyActionID("secret", SlackActionAcknowledge, found[SlackActionAcknowledge].Value)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+
+		id, err = VerifyActionID("secret", SlackActionPause, found[SlackActionPause].Value)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+
+		assert.Equal(t, state.Link, found[SlackActionOpenDashboard].URL)
+	})
+
+	t.Run("only the link without a signing secret", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		assert.NoError(t, NewSlackObserver("https://hooks.slack.com/test", mockClient, "").Notify(state))
+
+		found := buttons(t, mockClient)
+		assert.Len(t, found, 1)
+		assert.Contains(t, found, SlackActionOpenDashboard)
+	})
+
+	t.Run("only the link on recovery", func(t *testing.T) {
+		recovered := state
+		recovered.Status = "up"
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		assert.NoError(t, NewSlackObserver("https://hooks.slack.com/test", mockClient, "secret").Notify(recovered))
+
+		found := buttons(t, mockClient)
+		assert.Len(t, found, 1)
+		assert.Contains(t, found, SlackActionOpenDashboard)
+	})
+
+	t.Run("no buttons without anything to act on", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(http.StatusOK, nil)
+		err := NewSlackObserver("https://hooks.slack.com/test", mockClient, "secret").
+			Notify(notification.State{Name: "test-system", Status: "down"})
+		assert.NoError(t, err)
+		assert.Empty(t, buttons(t, mockClient))
+	})
+}
+
+func TestSlackObserver_NotifyEscapesMarkup(t *testing.T) {
+	mockClient := NewMockHTTPClient(http.StatusOK, nil)
+	observer := NewSlackObserver("https://hooks.slack.com/test", mockClient, "")
+
+	err := observer.Notify(notification.State{Name: "test-system", Status: "down", Message: "<!channel> 5 > 4 & 3"})
+	assert.NoError(t, err)
+
+	var msg slackMessage
+	assert.NoError(t, json.NewDecoder(mockClient.requests[0].Body).Decode(&msg))
+	assert.Equal(t, "&lt;!channel&gt; 5 &gt; 4 &amp; 3", msg.Blocks[2].Text.Text)
+}
+
+func TestSlackObserver_NotifyTitle(t *testing.T) {
+	mockClient := NewMockHTTPClient(http.StatusOK, nil)
+	observer := NewSlackObserver("https://hooks.slack.com/test", mockClient, "")
+
+	err := observer.Notify(notification.State{Name: "test-system", Status: "down", Title: "test-system needs attention"})
+	assert.NoError(t, err)
+
+	var msg slackMessage
+	assert.NoError(t, json.NewDecoder(mockClient.requests[0].Body).Decode(&msg))
+	assert.Equal(t, "test-system needs attention", msg.Text)
+}
@@ -1,20 +1,43 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/shuvo-paul/uptimebot/internal/database"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
 )
 
+var (
+	ErrTargetNotFound    = errors.New("target not found")
+	ErrSlackUserNotFound = errors.New("slack user not found")
+)
+
 type NotifierRepositoryInterface interface {
-	Create(*model.Notifier) (*model.Notifier, error)
-	Get(int) (*model.Notifier, error)
-	Update(int, json.RawMessage) (*model.Notifier, error)
-	Delete(int) error
-	GetByTargetID(int) ([]*model.Notifier, error)
+	Create(context.Context, *model.Notifier) (*model.Notifier, error)
+	Get(context.Context, int) (*model.Notifier, error)
+	Update(context.Context, *model.Notifier) (*model.Notifier, error)
+	Delete(context.Context, int) error
+	GetByUserID(context.Context, int) ([]*model.Notifier, error)
+	GetByTargetID(context.Context, int) ([]*model.Notifier, error)
+	GetTargetOwnerID(context.Context, int) (int, error)
+	Attach(ctx context.Context, targetID int, notifierID int) error
+	Detach(ctx context.Context, targetID int, notifierID int) error
+	AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error)
+	AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error)
+	QueueDigest(ctx context.Context, notifierID int, state notification.State) error
+	GetQueuedNotifiers(ctx context.Context) ([]*model.Notifier, error)
+	TakeDigest(ctx context.Context, notifierID int, send func(states []notification.State) error) error
+	SaveSlackInstallation(ctx context.Context, installation *model.SlackInstallation) error
+	GetUserIDBySlackUser(ctx context.Context, teamID string, slackUserID string) (int, error)
+	UseOAuthState(ctx context.Context, nonce string, expiresAt time.Time, now time.Time) (bool, error)
 }
 
 var _ NotifierRepositoryInterface = (*NotifierRepository)(nil)
@@ -30,39 +53,36 @@ func NewNotifierRepository(db database.Querier) *NotifierRepository {
 }
 
 // Create inserts a new notifier into the database
-func (r *NotifierRepository) Create(notifier *model.Notifier) (*model.Notifier, error) {
-	// Validate config based on notifier type
-	if notifier.Type == model.NotifierTypeSlack {
-		config, err := notifier.GetSlackConfig()
-		if err != nil {
-			return nil, fmt.Errorf("invalid slack config: %w", err)
-		}
-		if config == nil || config.WebhookURL == "" {
-			return nil, fmt.Errorf("webhook URL is required for slack notifier")
-		}
-	} else if notifier.Type == model.NotifierTypeEmail {
-		config, err := notifier.GetEmailConfig()
-		if err != nil {
-			return nil, fmt.Errorf("invalid email config: %w", err)
-		}
-		if config == nil {
-			return nil, fmt.Errorf("email configuration is required")
-		}
+func (r *NotifierRepository) Create(ctx context.Context, notifier *model.Notifier) (*model.Notifier, error) {
+	if err := notifier.Validate(); err != nil {
+		return nil, err
+	}
+
+	quietHours, err := encodeQuietHours(notifier.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	messageTemplate, err := encodeMessageTemplate(notifier.Template)
+	if err != nil {
+		return nil, err
 	}
 
 	query := `
-		INSERT INTO notifier (target_id, type, config)
-		VALUES ($1, $2, $3)
-		RETURNING id, target_id, type, config
-	`
+		INSERT INTO notifier (user_id, name, type, config, events, quiet_hours, message_template, rate_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + notifierColumns
 
-	newNotifier := &model.Notifier{}
-	err := r.db.QueryRow(query, notifier.TargetId, notifier.Type, notifier.Config).Scan(
-		&newNotifier.ID,
-		&newNotifier.TargetId,
-		&newNotifier.Type,
-		&newNotifier.Config,
-	)
+	newNotifier, err := scanNotifier(r.db.QueryRowContext(ctx, query,
+		notifier.UserID,
+		notifier.Name,
+		notifier.Type,
+		notifier.Config,
+		pq.Array(eventsOrEmpty(notifier.Events)),
+		quietHours,
+		messageTemplate,
+		notifier.RateLimit,
+	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create notifier: %w", err)
 	}
@@ -71,21 +91,10 @@ func (r *NotifierRepository) Create(notifier *model.Notifier) (*model.Notifier,
 }
 
 // Get retrieves a notifier by ID
-func (r *NotifierRepository) Get(id int) (*model.Notifier, error) {
-	query := `
-		SELECT id, target_id, type, config
-		FROM notifier
-		WHERE id = $1
-	`
-
-	notifier := &model.Notifier{}
-	err := r.db.QueryRow(query, id).Scan(
-		&notifier.ID,
-		&notifier.TargetId,
-		&notifier.Type,
-		&notifier.Config,
-	)
+func (r *NotifierRepository) Get(ctx context.Context, id int) (*model.Notifier, error) {
+	query := `SELECT ` + notifierColumns + ` FROM notifier WHERE id = $1`
 
+	notifier, err := scanNotifier(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -96,33 +105,120 @@ func (r *NotifierRepository) Get(id int) (*model.Notifier, error) {
 	return notifier, nil
 }
 
-// Update updates a notifier's configuration
-func (r *NotifierRepository) Update(id int, config json.RawMessage) (*model.Notifier, error) {
+// Update updates a notifier's name, configuration, event filter, quiet hours,
+// message template and rate limit
+func (r *NotifierRepository) Update(ctx context.Context, notifier *model.Notifier) (*model.Notifier, error) {
+	if err := notifier.Validate(); err != nil {
+		return nil, err
+	}
+
+	quietHours, err := encodeQuietHours(notifier.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	messageTemplate, err := encodeMessageTemplate(notifier.Template)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE notifier
-		SET config = $1
-		WHERE id = $2
-		RETURNING id, target_id, type, config
-	`
+		SET name = $1, config = $2, events = $3, quiet_hours = $4, message_template = $5, rate_limit = $6
+		WHERE id = $7
+		RETURNING ` + notifierColumns
+
+	updated, err := scanNotifier(r.db.QueryRowContext(ctx, query,
+		notifier.Name,
+		notifier.Config,
+		pq.Array(eventsOrEmpty(notifier.Events)),
+		quietHours,
+		messageTemplate,
+		notifier.RateLimit,
+		notifier.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update: %w", err)
+	}
+
+	return updated, nil
+}
+
+const notifierColumns = "id, user_id, name, type, config, events, quiet_hours, message_template, rate_limit"
 
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanNotifier reads a row selected with notifierColumns
+func scanNotifier(row rowScanner) (*model.Notifier, error) {
 	notifier := &model.Notifier{}
-	err := r.db.QueryRow(query, config, id).Scan(
+	var quietHours, messageTemplate []byte
+	err := row.Scan(
 		&notifier.ID,
-		&notifier.TargetId,
+		&notifier.UserID,
+		&notifier.Name,
 		&notifier.Type,
 		&notifier.Config,
+		pq.Array(&notifier.Events),
+		&quietHours,
+		&messageTemplate,
+		&notifier.RateLimit,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update: %w", err)
+		return nil, err
+	}
+
+	if quietHours != nil {
+		notifier.QuietHours = &model.QuietHours{}
+		if err := json.Unmarshal(quietHours, notifier.QuietHours); err != nil {
+			return nil, fmt.Errorf("failed to decode quiet hours: %w", err)
+		}
+	}
+
+	if messageTemplate != nil {
+		notifier.Template = &model.MessageTemplate{}
+		if err := json.Unmarshal(messageTemplate, notifier.Template); err != nil {
+			return nil, fmt.Errorf("failed to decode message template: %w", err)
+		}
 	}
 
 	return notifier, nil
 }
 
+func encodeQuietHours(quietHours *model.QuietHours) ([]byte, error) {
+	if quietHours == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(quietHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quiet hours: %w", err)
+	}
+	return encoded, nil
+}
+
+func encodeMessageTemplate(template *model.MessageTemplate) ([]byte, error) {
+	if template == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message template: %w", err)
+	}
+	return encoded, nil
+}
+
+func eventsOrEmpty(events []string) []string {
+	if events == nil {
+		return []string{}
+	}
+	return events
+}
+
 // Delete removes a notifier from the database
-func (r *NotifierRepository) Delete(id int) error {
+func (r *NotifierRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM notifier WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete notifier: %w", err)
 	}
@@ -139,15 +235,28 @@ func (r *NotifierRepository) Delete(id int) error {
 	return nil
 }
 
-// GetByTargetID retrieves all notifiers for a specific target
-func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, error) {
+// GetByUserID retrieves all notifiers owned by a user
+func (r *NotifierRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error) {
+	query := `SELECT ` + notifierColumns + ` FROM notifier WHERE user_id = $1 ORDER BY id`
+
+	return r.queryNotifiers(ctx, query, userID)
+}
+
+// GetByTargetID retrieves all notifiers attached to a specific target
+func (r *NotifierRepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.Notifier, error) {
 	query := `
-		SELECT id, target_id, type, config
-		FROM notifier
-		WHERE target_id = $1
+		SELECT n.id, n.user_id, n.name, n.type, n.config, n.events, n.quiet_hours, n.message_template, n.rate_limit
+		FROM notifier n
+		JOIN target_notifier tn ON tn.notifier_id = n.id
+		WHERE tn.target_id = $1
+		ORDER BY n.id
 	`
 
-	rows, err := r.db.Query(query, targetID)
+	return r.queryNotifiers(ctx, query, targetID)
+}
+
+func (r *NotifierRepository) queryNotifiers(ctx context.Context, query string, args ...any) ([]*model.Notifier, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query notifiers: %w", err)
 	}
@@ -155,13 +264,7 @@ func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, err
 
 	var notifiers []*model.Notifier
 	for rows.Next() {
-		notifier := &model.Notifier{}
-		err := rows.Scan(
-			&notifier.ID,
-			&notifier.TargetId,
-			&notifier.Type,
-			&notifier.Config,
-		)
+		notifier, err := scanNotifier(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan notifier: %w", err)
 		}
@@ -175,3 +278,204 @@ func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, err
 
 	return notifiers, nil
 }
+
+// GetTargetOwnerID returns the ID of the user who owns the given target
+func (r *NotifierRepository) GetTargetOwnerID(ctx context.Context, targetID int) (int, error) {
+	query := `SELECT user_id FROM target WHERE id = $1`
+
+	var userID int
+	err := r.db.QueryRowContext(ctx, query, targetID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrTargetNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get target owner: %w", err)
+	}
+
+	return userID, nil
+}
+
+// Attach links a notifier to a target, ignoring links that already exist
+func (r *NotifierRepository) Attach(ctx context.Context, targetID int, notifierID int) error {
+	query := `
+		INSERT INTO target_notifier (target_id, notifier_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, targetID, notifierID); err != nil {
+		return fmt.Errorf("failed to attach notifier: %w", err)
+	}
+	return nil
+}
+
+// Detach removes the link between a notifier and a target
+func (r *NotifierRepository) Detach(ctx context.Context, targetID int, notifierID int) error {
+	query := `DELETE FROM target_notifier WHERE target_id = $1 AND notifier_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, targetID, notifierID); err != nil {
+		return fmt.Errorf("failed to detach notifier: %w", err)
+	}
+	return nil
+}
+
+// AttachToAllTargets links a notifier to every target of the user and
+// returns the number of newly created links
+func (r *NotifierRepository) AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error) {
+	query := `
+		INSERT INTO target_notifier (target_id, notifier_id)
+		SELECT id, $1 FROM target WHERE user_id = $2
+		ON CONFLICT DO NOTHING
+	`
+
+	return r.execCount(ctx, query, notifierID, userID)
+}
+
+// AttachToTag links a notifier to every target of the user carrying the tag
+// and returns the number of newly created links
+func (r *NotifierRepository) AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error) {
+	query := `
+		INSERT INTO target_notifier (target_id, notifier_id)
+		SELECT id, $1 FROM target WHERE user_id = $2 AND $3 = ANY(tags)
+		ON CONFLICT DO NOTHING
+	`
+
+	return r.execCount(ctx, query, notifierID, userID, tag)
+}
+
+func (r *NotifierRepository) execCount(ctx context.Context, query string, args ...any) (int, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach notifier: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+// QueueDigest stores a state silenced by quiet hours for the notifier's next digest
+func (r *NotifierRepository) QueueDigest(ctx context.Context, notifierID int, state notification.State) error {
+	query := `
+		INSERT INTO notification_digest (notifier_id, name, status, message, raised_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, notifierID, state.Name, state.Status, state.Message, state.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to queue digest entry: %w", err)
+	}
+	return nil
+}
+
+// GetQueuedNotifiers retrieves every notifier with at least one queued digest entry
+func (r *NotifierRepository) GetQueuedNotifiers(ctx context.Context) ([]*model.Notifier, error) {
+	query := `
+		SELECT n.id, n.user_id, n.name, n.type, n.config, n.events, n.quiet_hours, n.message_template, n.rate_limit
+		FROM notifier n
+		WHERE EXISTS (SELECT 1 FROM notification_digest d WHERE d.notifier_id = n.id)
+		ORDER BY n.id
+	`
+
+	return r.queryNotifiers(ctx, query)
+}
+
+// TakeDigest hands the queued states of a notifier, oldest first, to send and
+// removes them once send succeeds. When send fails they stay queued for the
+// next flush. The entries are locked meanwhile so a concurrent flush cannot
+// send them twice.
+func (r *NotifierRepository) TakeDigest(ctx context.Context, notifierID int, send func(states []notification.State) error) error {
+	query := `
+		DELETE FROM notification_digest
+		WHERE notifier_id = $1
+		RETURNING name, status, message, raised_at
+	`
+
+	return database.WithTx(ctx, r.db, func(tx database.Querier) error {
+		rows, err := tx.QueryContext(ctx, query, notifierID)
+		if err != nil {
+			return fmt.Errorf("failed to take digest: %w", err)
+		}
+		defer rows.Close()
+
+		var states []notification.State
+		for rows.Next() {
+			var state notification.State
+			if err := rows.Scan(&state.Name, &state.Status, &state.Message, &state.UpdatedAt); err != nil {
+				return fmt.Errorf("failed to scan digest entry: %w", err)
+			}
+			states = append(states, state)
+		}
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("error iterating digest entries: %w", err)
+		}
+		if len(states) == 0 {
+			return nil
+		}
+
+		sort.SliceStable(states, func(i, j int) bool {
+			return states[i].UpdatedAt.Before(states[j].UpdatedAt)
+		})
+
+		return send(states)
+	})
+}
+
+// SaveSlackInstallation records the Slack account a user connected Slack
+// from. Connecting the same account again moves it to the latest user.
+func (r *NotifierRepository) SaveSlackInstallation(ctx context.Context, installation *model.SlackInstallation) error {
+	query := `
+		INSERT INTO slack_installation (user_id, team_id, slack_user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id, slack_user_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, installation.UserID, installation.TeamID, installation.SlackUserID, installation.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save slack installation: %w", err)
+	}
+	return nil
+}
+
+// GetUserIDBySlackUser returns the user a Slack account acts as
+func (r *NotifierRepository) GetUserIDBySlackUser(ctx context.Context, teamID string, slackUserID string) (int, error) {
+	var userID int
+	query := `SELECT user_id FROM slack_installation WHERE team_id = $1 AND slack_user_id = $2`
+	err := r.db.QueryRowContext(ctx, query, teamID, slackUserID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrSlackUserNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slack user: %w", err)
+	}
+	return userID, nil
+}
+
+// UseOAuthState records that the OAuth state with the nonce was used and
+// reports whether it was still unused. Used states are kept until they
+// expire, and expired ones are dropped along the way.
+func (r *NotifierRepository) UseOAuthState(ctx context.Context, nonce string, expiresAt time.Time, now time.Time) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM used_oauth_state WHERE expires_at <= $1`, now.UTC()); err != nil {
+		return false, fmt.Errorf("failed to drop expired oauth states: %w", err)
+	}
+
+	query := `
+		INSERT INTO used_oauth_state (nonce, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (nonce) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, nonce, expiresAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to use oauth state: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
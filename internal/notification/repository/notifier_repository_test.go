@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
 	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
 	"github.com/shuvo-paul/uptimebot/internal/testutil"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +21,7 @@ import (
 
 func createTestUser(t *testing.T, tx *sql.Tx) *authModel.User {
 	userRepo := authRepo.NewUserRepository(tx)
-	user, err := userRepo.SaveUser(&authModel.User{
+	user, err := userRepo.SaveUser(context.Background(), &authModel.User{
 		Email:    "test@example.com",
 		Password: "hashedpassword",
 	})
@@ -30,11 +33,12 @@ func createTestUser(t *testing.T, tx *sql.Tx) *authModel.User {
 	return user
 }
 
-func createTestTarget(t *testing.T, tx *sql.Tx, user *authModel.User) int {
+func createTestTarget(t *testing.T, tx *sql.Tx, user *authModel.User, tags ...string) int {
 	// Create target
 	targetRepo := monitorRepo.NewTargetRepository(tx)
-	target, err := targetRepo.Create(monitorModel.UserTarget{
+	target, err := targetRepo.Create(context.Background(), monitorModel.UserTarget{
 		UserID: user.ID,
+		Tags:   tags,
 		Target: &core.Target{
 			URL:             "example.org",
 			Status:          "up",
@@ -48,23 +52,36 @@ func createTestTarget(t *testing.T, tx *sql.Tx, user *authModel.User) int {
 	return target.ID
 }
 
+func createTestNotifier(t *testing.T, repo *NotifierRepository, user *authModel.User, webhookURL string) *model.Notifier {
+	notifier, err := repo.Create(context.Background(), &model.Notifier{
+		UserID: user.ID,
+		Name:   "#alerts",
+		Type:   model.NotifierTypeSlack,
+		Config: json.RawMessage(`{"webhook_url": "` + webhookURL + `"}`),
+	})
+	assert.NoError(t, err)
+
+	return notifier
+}
+
 func TestNotifierRepository_Create(t *testing.T) {
 	tx := testutil.GetTestTx(t)
 	notifierRepo := NewNotifierRepository(tx)
 
 	user := createTestUser(t, tx)
-	targetID := createTestTarget(t, tx, user)
 
 	slackNotifier := &model.Notifier{
-		TargetId: targetID,
-		Type:     model.NotifierTypeSlack,
-		Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		UserID: user.ID,
+		Name:   "#alerts",
+		Type:   model.NotifierTypeSlack,
+		Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
 	}
 
 	emailNotifier := &model.Notifier{
-		TargetId: targetID,
-		Type:     model.NotifierTypeEmail,
-		Config:   json.RawMessage(`{"recipients": ["test@example.com"]}`),
+		UserID: user.ID,
+		Name:   "ops team",
+		Type:   model.NotifierTypeEmail,
+		Config: json.RawMessage(`{"recipients": ["test@example.com"]}`),
 	}
 
 	tests := []struct {
@@ -82,25 +99,25 @@ func TestNotifierRepository_Create(t *testing.T) {
 		{
 			name: "invalid json",
 			notifier: &model.Notifier{
-				TargetId: targetID,
-				Type:     model.NotifierTypeSlack,
-				Config:   json.RawMessage(`invalid json`),
+				UserID: user.ID,
+				Type:   model.NotifierTypeSlack,
+				Config: json.RawMessage(`invalid json`),
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name:     "valid email config",
+			name:     "unsupported email notifier",
 			notifier: emailNotifier,
-			want:     emailNotifier,
-			wantErr:  false,
+			want:     nil,
+			wantErr:  true,
 		},
 		{
-			name: "invalid target id",
+			name: "invalid user id",
 			notifier: &model.Notifier{
-				TargetId: 999,
-				Type:     model.NotifierTypeSlack,
-				Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+				UserID: 999,
+				Type:   model.NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
 			},
 			want:    nil,
 			wantErr: true,
@@ -109,7 +126,7 @@ func TestNotifierRepository_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			newNotifier, err := notifierRepo.Create(tt.notifier)
+			newNotifier, err := notifierRepo.Create(context.Background(), tt.notifier)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -118,7 +135,8 @@ func TestNotifierRepository_Create(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.NotEmpty(t, newNotifier.ID)
-			assert.Equal(t, tt.want.TargetId, newNotifier.TargetId)
+			assert.Equal(t, tt.want.UserID, newNotifier.UserID)
+			assert.Equal(t, tt.want.Name, newNotifier.Name)
 			assert.Equal(t, tt.want.Type, newNotifier.Type)
 			assert.JSONEq(t, string(tt.want.Config), string(newNotifier.Config))
 		})
@@ -130,31 +148,23 @@ func TestNotifierRepository_Get(t *testing.T) {
 	tx := testutil.GetTestTx(t)
 	notifierRepo := NewNotifierRepository(tx)
 	user := createTestUser(t, tx)
-	targetID := createTestTarget(t, tx, user)
 
 	t.Run("NotFound", func(t *testing.T) {
-		notifier, err := notifierRepo.Get(1)
+		notifier, err := notifierRepo.Get(context.Background(), 99999)
 		assert.NoError(t, err)
 		assert.Nil(t, notifier)
 	})
 
 	t.Run("Found", func(t *testing.T) {
-		config := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)
-		notifier := &model.Notifier{
-			TargetId: targetID,
-			Type:     model.NotifierTypeSlack,
-			Config:   config,
-		}
-		created, err := notifierRepo.Create(notifier)
-		assert.NoError(t, err)
-		assert.NotEmpty(t, created.ID)
+		created := createTestNotifier(t, notifierRepo, user, "https://hooks.slack.com/test")
 
-		savedNotifier, err := notifierRepo.Get(created.ID)
+		savedNotifier, err := notifierRepo.Get(context.Background(), created.ID)
 		assert.NoError(t, err)
 		assert.NotNil(t, savedNotifier)
 		assert.Equal(t, created.ID, savedNotifier.ID)
+		assert.Equal(t, user.ID, savedNotifier.UserID)
 		assert.Equal(t, created.Type, savedNotifier.Type)
-		assert.JSONEq(t, string(config), string(savedNotifier.Config))
+		assert.JSONEq(t, string(created.Config), string(savedNotifier.Config))
 	})
 }
 
@@ -162,36 +172,48 @@ func TestNotifierRepository_Update(t *testing.T) {
 	tx := testutil.GetTestTx(t)
 	notifierRepo := NewNotifierRepository(tx)
 	user := createTestUser(t, tx)
-	targetID := createTestTarget(t, tx, user)
 
 	t.Run("NotFound", func(t *testing.T) {
-		config := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test2"}`)
-		notifier, err := notifierRepo.Update(99, config)
+		notifier, err := notifierRepo.Update(context.Background(), &model.Notifier{
+			ID:     99999,
+			Type:   model.NotifierTypeSlack,
+			Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test2"}`),
+		})
 		assert.Error(t, err)
 		assert.Nil(t, notifier)
 	})
 
 	t.Run("Success", func(t *testing.T) {
-		initialConfig := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)
-		notifier := &model.Notifier{
-			TargetId: targetID,
-			Type:     model.NotifierTypeSlack,
-			Config:   initialConfig,
-		}
-		created, err := notifierRepo.Create(notifier)
-		assert.NoError(t, err)
-		assert.NotEmpty(t, created.ID)
+		created := createTestNotifier(t, notifierRepo, user, "https://hooks.slack.com/test")
 
-		newConfig := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test2"}`)
-		updated, err := notifierRepo.Update(created.ID, newConfig)
+		created.Name = "#incidents"
+		created.Config = json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test2"}`)
+		updated, err := notifierRepo.Update(context.Background(), created)
 		assert.NoError(t, err)
 		assert.NotNil(t, updated)
 		assert.Equal(t, created.ID, updated.ID)
-		assert.Equal(t, created.Type, updated.Type)
-		assert.JSONEq(t, string(newConfig), string(updated.Config))
+		assert.Equal(t, "#incidents", updated.Name)
+		assert.JSONEq(t, string(created.Config), string(updated.Config))
 	})
 }
 
+func TestNotifierRepository_GetByUserID(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+
+	createTestNotifier(t, repo, user, "https://hooks.slack.com/test1")
+	createTestNotifier(t, repo, user, "https://hooks.slack.com/test2")
+
+	notifiers, err := repo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, notifiers, 2)
+
+	notifiers, err = repo.GetByUserID(context.Background(), 99999)
+	assert.NoError(t, err)
+	assert.Empty(t, notifiers)
+}
+
 func TestNotifierRepository_GetByTargetID(t *testing.T) {
 	tx := testutil.GetTestTx(t)
 	repo := NewNotifierRepository(tx)
@@ -200,58 +222,205 @@ func TestNotifierRepository_GetByTargetID(t *testing.T) {
 	targetID2 := createTestTarget(t, tx, user)
 
 	t.Run("NoNotifiers", func(t *testing.T) {
-		notifiers, err := repo.GetByTargetID(999)
+		notifiers, err := repo.GetByTargetID(context.Background(), 99999)
 		assert.NoError(t, err)
 		assert.Empty(t, notifiers)
 	})
 
-	t.Run("MultipleNotifiers", func(t *testing.T) {
-		config1 := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test1"}`)
-		config2 := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test2"}`)
-		otherConfig := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/other"}`)
-
-		notifier1 := &model.Notifier{
-			TargetId: targetID,
-			Type:     model.NotifierTypeSlack,
-			Config:   config1,
-		}
-		notifier2 := &model.Notifier{
-			TargetId: targetID,
-			Type:     model.NotifierTypeSlack,
-			Config:   config2,
-		}
-		otherNotifier := &model.Notifier{
-			TargetId: targetID2,
-			Type:     model.NotifierTypeSlack,
-			Config:   otherConfig,
-		}
-
-		// Save all notifiers
-		_, err := repo.Create(notifier1)
+	t.Run("SharedNotifier", func(t *testing.T) {
+		shared := createTestNotifier(t, repo, user, "https://hooks.slack.com/shared")
+		other := createTestNotifier(t, repo, user, "https://hooks.slack.com/other")
+
+		assert.NoError(t, repo.Attach(context.Background(), targetID, shared.ID))
+		assert.NoError(t, repo.Attach(context.Background(), targetID2, shared.ID))
+		assert.NoError(t, repo.Attach(context.Background(), targetID2, other.ID))
+		// Attaching twice is a no-op
+		assert.NoError(t, repo.Attach(context.Background(), targetID, shared.ID))
+
+		notifiers, err := repo.GetByTargetID(context.Background(), targetID)
 		assert.NoError(t, err)
-		_, err = repo.Create(notifier2)
+		assert.Len(t, notifiers, 1)
+		assert.Equal(t, shared.ID, notifiers[0].ID)
+
+		notifiers, err = repo.GetByTargetID(context.Background(), targetID2)
 		assert.NoError(t, err)
-		_, err = repo.Create(otherNotifier)
+		assert.Len(t, notifiers, 2)
+
+		assert.NoError(t, repo.Detach(context.Background(), targetID2, shared.ID))
+		notifiers, err = repo.GetByTargetID(context.Background(), targetID2)
 		assert.NoError(t, err)
+		assert.Len(t, notifiers, 1)
+		assert.Equal(t, other.ID, notifiers[0].ID)
+	})
+}
 
-		// Fetch notifiers for the target
-		notifiers, err := repo.GetByTargetID(targetID)
+func TestNotifierRepository_BulkAttach(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+	prodID := createTestTarget(t, tx, user, "production")
+	createTestTarget(t, tx, user, "staging")
+	createTestTarget(t, tx, user, "production", "api")
+
+	t.Run("AttachToTag", func(t *testing.T) {
+		notifier := createTestNotifier(t, repo, user, "https://hooks.slack.com/prod")
+
+		count, err := repo.AttachToTag(context.Background(), notifier.ID, user.ID, "production")
 		assert.NoError(t, err)
-		assert.Len(t, notifiers, 2)
+		assert.Equal(t, 2, count)
 
-		// Verify notifier details
-		for _, n := range notifiers {
-			assert.Equal(t, targetID, n.TargetId)
-			assert.Equal(t, model.NotifierTypeSlack, n.Type)
-			config, err := n.GetSlackConfig()
-			assert.NoError(t, err)
-			assert.Contains(t, config.WebhookURL, "https://hooks.slack.com/test")
-		}
+		notifiers, err := repo.GetByTargetID(context.Background(), prodID)
+		assert.NoError(t, err)
+		assert.Len(t, notifiers, 1)
+	})
+
+	t.Run("AttachToAllTargets", func(t *testing.T) {
+		notifier := createTestNotifier(t, repo, user, "https://hooks.slack.com/all")
+
+		assert.NoError(t, repo.Attach(context.Background(), prodID, notifier.ID))
+		count, err := repo.AttachToAllTargets(context.Background(), notifier.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
 
-		// Verify other target's notifier is not included
-		otherNotifiers, err := repo.GetByTargetID(targetID2)
+func TestNotifierRepository_GetTargetOwnerID(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+	targetID := createTestTarget(t, tx, user)
+
+	t.Run("Found", func(t *testing.T) {
+		ownerID, err := repo.GetTargetOwnerID(context.Background(), targetID)
 		assert.NoError(t, err)
-		assert.Len(t, otherNotifiers, 1)
-		assert.Equal(t, targetID2, otherNotifiers[0].TargetId)
+		assert.Equal(t, user.ID, ownerID)
 	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.GetTargetOwnerID(context.Background(), 99999)
+		assert.ErrorIs(t, err, ErrTargetNotFound)
+	})
+}
+
+func TestNotifierRepository_Filters(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+
+	quietHours := &model.QuietHours{
+		Timezone: "Europe/Berlin",
+		Windows:  []model.QuietWindow{{Start: "22:00", End: "07:00"}},
+		Action:   model.QuietActionDigest,
+	}
+	messageTemplate := &model.MessageTemplate{Title: "{{upper .Status}}: {{.Name}}"}
+
+	created, err := repo.Create(context.Background(), &model.Notifier{
+		UserID:     user.ID,
+		Name:       "#alerts",
+		Type:       model.NotifierTypeSlack,
+		Config:     json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		Events:     []string{model.EventDown},
+		QuietHours: quietHours,
+		Template:   messageTemplate,
+		RateLimit:  10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.EventDown}, created.Events)
+	assert.Equal(t, quietHours, created.QuietHours)
+	assert.Equal(t, messageTemplate, created.Template)
+	assert.Equal(t, 10, created.RateLimit)
+
+	created.Events = nil
+	created.QuietHours = nil
+	created.Template = nil
+	updated, err := repo.Update(context.Background(), created)
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Events)
+	assert.Nil(t, updated.QuietHours)
+	assert.Nil(t, updated.Template)
+}
+
+func TestNotifierRepository_Digest(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+	notifier := createTestNotifier(t, repo, user, "https://hooks.slack.com/test")
+	createTestNotifier(t, repo, user, "https://hooks.slack.com/other")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, repo.QueueDigest(context.Background(), notifier.ID, notification.State{Name: "example.org", Status: "up", Message: "recovered", UpdatedAt: now}))
+	assert.NoError(t, repo.QueueDigest(context.Background(), notifier.ID, notification.State{Name: "example.org", Status: "down", Message: "went down", UpdatedAt: now.Add(-time.Hour)}))
+
+	queued, err := repo.GetQueuedNotifiers(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, queued, 1)
+	assert.Equal(t, notifier.ID, queued[0].ID)
+
+	// A failed delivery leaves the entries queued
+	sendErr := errors.New("slack is down")
+	err = repo.TakeDigest(context.Background(), notifier.ID, func(states []notification.State) error {
+		return sendErr
+	})
+	assert.ErrorIs(t, err, sendErr)
+
+	var states []notification.State
+	err = repo.TakeDigest(context.Background(), notifier.ID, func(taken []notification.State) error {
+		states = taken
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, states, 2)
+	assert.Equal(t, "down", states[0].Status)
+	assert.Equal(t, "up", states[1].Status)
+
+	called := false
+	err = repo.TakeDigest(context.Background(), notifier.ID, func(taken []notification.State) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, called)
+}
+
+func TestNotifierRepository_SlackInstallation(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	user := createTestUser(t, tx)
+
+	_, err := repo.GetUserIDBySlackUser(context.Background(), "T123", "U123")
+	assert.ErrorIs(t, err, ErrSlackUserNotFound)
+
+	installation := &model.SlackInstallation{UserID: user.ID, TeamID: "T123", SlackUserID: "U123", CreatedAt: time.Now()}
+	assert.NoError(t, repo.SaveSlackInstallation(context.Background(), installation))
+	// Connecting again from the same account is not an error
+	assert.NoError(t, repo.SaveSlackInstallation(context.Background(), installation))
+
+	userID, err := repo.GetUserIDBySlackUser(context.Background(), "T123", "U123")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, userID)
+
+	_, err = repo.GetUserIDBySlackUser(context.Background(), "T999", "U123")
+	assert.ErrorIs(t, err, ErrSlackUserNotFound)
+}
+
+func TestNotifierRepository_UseOAuthState(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewNotifierRepository(tx)
+	now := time.Date(2025, 4, 28, 9, 0, 0, 0, time.UTC)
+
+	used, err := repo.UseOAuthState(context.Background(), "abc", now.Add(10*time.Minute), now)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.UseOAuthState(context.Background(), "abc", now.Add(10*time.Minute), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, used, "a state is only used once")
+
+	// Once expired, the state is forgotten
+	used, err = repo.UseOAuthState(context.Background(), "def", now.Add(time.Hour), now.Add(10*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, used)
+	var count int
+	assert.NoError(t, tx.QueryRow(`SELECT COUNT(*) FROM used_oauth_state`).Scan(&count))
+	assert.Equal(t, 1, count)
 }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	notifCoer "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+)
+
+// Results of a notification
+const (
+	resultSent     = "sent"
+	resultFailed   = "failed"
+	resultDeferred = "deferred"
+	resultDropped  = "dropped"
+)
+
+var notificationsTotal = metrics.NewCounter("uptimebot_notifications_total",
+	"Notifications for contact channels, by channel type and result: sent, failed, deferred to a digest or dropped.",
+	"type", "result")
+
+// countingObserver counts the deliveries of a notifier's observer
+type countingObserver struct {
+	observer     notifCoer.Observer
+	notifierType model.NotifierType
+}
+
+func (o *countingObserver) Notify(state notifCoer.State) error {
+	err := o.observer.Notify(state)
+	result := resultSent
+	if err != nil {
+		result = resultFailed
+	}
+	notificationsTotal.Inc(string(o.notifierType), result)
+	return err
+}
+
+// countDecision counts the notifications held back by a notifier's filter
+func countDecision(notifier *model.Notifier, decision notifCoer.Decision) {
+	switch decision {
+	case notifCoer.Defer:
+		notificationsTotal.Inc(string(notifier.Type), resultDeferred)
+	case notifCoer.Drop:
+		notificationsTotal.Inc(string(notifier.Type), resultDropped)
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingObserver(t *testing.T) {
+	const notifierType = model.NotifierType("test")
+
+	sent := &countingObserver{observer: newMockObserver(nil), notifierType: notifierType}
+	failed := &countingObserver{observer: newMockObserver(errors.New("webhook failed")), notifierType: notifierType}
+
+	assert.NoError(t, sent.Notify(notification.State{}))
+	assert.NoError(t, sent.Notify(notification.State{}))
+	assert.Error(t, failed.Notify(notification.State{}))
+	countDecision(&model.Notifier{Type: notifierType}, notification.Defer)
+
+	var out strings.Builder
+	metrics.Default.Write(&out)
+	assert.Contains(t, out.String(), `uptimebot_notifications_total{type="test",result="sent"} 2`)
+	assert.Contains(t, out.String(), `uptimebot_notifications_total{type="test",result="failed"} 1`)
+	assert.Contains(t, out.String(), `uptimebot_notifications_total{type="test",result="deferred"} 1`)
+}
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
-	"strconv"
+	"strings"
+	"time"
 
 	notifCoer "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
@@ -14,20 +18,40 @@ import (
 	"github.com/shuvo-paul/uptimebot/internal/notification/repository"
 )
 
+// Common errors returned by the notifier service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a notifier or target they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to notifier")
+	// ErrNotifierNotFound is returned when the requested notifier does not exist.
+	ErrNotifierNotFound = errors.New("notifier not found")
+	// ErrTargetNotFound is returned when the target a notifier is attached to does not exist.
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
 type NotifierServiceInterface interface {
-	Create(notifier *model.Notifier) error
-	Get(id int) (*model.Notifier, error)
-	Update(id int, config json.RawMessage) (*model.Notifier, error)
-	Delete(id int) error
-	ConfigureObservers(targetID int) error
-	HandleSlackCallback(code string, targetID int) (*model.Notifier, error)
-	ParseOAuthState(state string) (int, error)
-	GetSubject() *notifCoer.Subject
+	Create(ctx context.Context, notifier *model.Notifier, userID int) error
+	Get(ctx context.Context, id int, userID int) (*model.Notifier, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error)
+	GetByTargetID(ctx context.Context, targetID int, userID int) ([]*model.Notifier, error)
+	Update(ctx context.Context, notifier *model.Notifier, userID int) (*model.Notifier, error)
+	Delete(ctx context.Context, id int, userID int) error
+	Attach(ctx context.Context, targetID int, notifierID int, userID int) error
+	Detach(ctx context.Context, targetID int, notifierID int, userID int) error
+	AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error)
+	AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error)
+	NotifyTarget(ctx context.Context, targetID int, state notifCoer.State) ([]error, error)
+	HandleSlackCallback(ctx context.Context, code string, userID int) (*model.Notifier, error)
+	ResolveSlackUser(ctx context.Context, teamID string, slackUserID string) (int, error)
+	NewOAuthState(ctx context.Context, targetID int, userID int) (string, error)
+	ParseOAuthState(ctx context.Context, state string, userID int) (int, error)
 }
 
 type NotifierService struct {
 	notifierRepo repository.NotifierRepositoryInterface
-	subject      *notifCoer.Subject
+	limiter      *rateLimiter
+	now          func() time.Time
 }
 
 var (
@@ -35,82 +59,407 @@ var (
 	SlackTokenURL = "https://slack.com/api/oauth.v2.access"
 )
 
-func NewNotifierService(
-	notifierRepo repository.NotifierRepositoryInterface,
-	subject *notifCoer.Subject,
-) *NotifierService {
-	if subject == nil {
-		subject = notifCoer.NewSubject()
-	}
+func NewNotifierService(notifierRepo repository.NotifierRepositoryInterface) *NotifierService {
 	return &NotifierService{
 		notifierRepo: notifierRepo,
-		subject:      subject,
+		limiter:      newRateLimiter(),
+		now:          time.Now,
 	}
 }
 
-// Create adds a new notifier
-func (s *NotifierService) Create(notifier *model.Notifier) error {
-	if _, err := s.notifierRepo.Create(notifier); err != nil {
+// authorizeTarget verifies that the target exists and is owned by the user.
+func (s *NotifierService) authorizeTarget(ctx context.Context, targetID int, userID int) error {
+	if targetID <= 0 || userID <= 0 {
+		return fmt.Errorf("%w: invalid targetID or userID", ErrInvalidInput)
+	}
+
+	ownerID, err := s.notifierRepo.GetTargetOwnerID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			return fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, targetID)
+		}
+		return fmt.Errorf("failed to get target owner: %w", err)
+	}
+
+	if ownerID != userID {
+		return fmt.Errorf("%w: user %d does not own target %d", ErrUnauthorized, userID, targetID)
+	}
+
+	return nil
+}
+
+// Create adds a new contact channel owned by the user
+func (s *NotifierService) Create(ctx context.Context, notifier *model.Notifier, userID int) error {
+	if notifier == nil {
+		return fmt.Errorf("%w: notifier is nil", ErrInvalidInput)
+	}
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	notifier.UserID = userID
+	if notifier.Name == "" {
+		notifier.Name = string(notifier.Type)
+	}
+	if err := notifier.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	created, err := s.notifierRepo.Create(ctx, notifier)
+	if err != nil {
 		return fmt.Errorf("failed to create notifier: %w", err)
 	}
+	notifier.ID = created.ID
 	return nil
 }
 
-// Get retrieves a notifier by ID
-func (s *NotifierService) Get(id int) (*model.Notifier, error) {
-	notifier, err := s.notifierRepo.Get(id)
+// Get retrieves a notifier by ID after verifying that the user owns it
+func (s *NotifierService) Get(ctx context.Context, id int, userID int) (*model.Notifier, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid id", ErrInvalidInput)
+	}
+
+	notifier, err := s.notifierRepo.Get(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notifier: %w", err)
 	}
+	if notifier == nil {
+		return nil, fmt.Errorf("%w: notifier with id %d not found", ErrNotifierNotFound, id)
+	}
+
+	if notifier.UserID != userID {
+		return nil, fmt.Errorf("%w: user %d does not own notifier %d", ErrUnauthorized, userID, id)
+	}
+
 	return notifier, nil
 }
 
-// Update modifies an existing notifier's configuration
-func (s *NotifierService) Update(id int, config json.RawMessage) (*model.Notifier, error) {
-	notifier, err := s.notifierRepo.Update(id, config)
+// GetByUserID retrieves all contact channels of a user
+func (s *NotifierService) GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	notifiers, err := s.notifierRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	return notifiers, nil
+}
+
+// GetByTargetID retrieves all notifiers attached to a target after verifying that the user owns it
+func (s *NotifierService) GetByTargetID(ctx context.Context, targetID int, userID int) ([]*model.Notifier, error) {
+	if err := s.authorizeTarget(ctx, targetID, userID); err != nil {
+		return nil, err
+	}
+
+	notifiers, err := s.notifierRepo.GetByTargetID(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	return notifiers, nil
+}
+
+// Update modifies an existing notifier's name and configuration after verifying ownership
+func (s *NotifierService) Update(ctx context.Context, notifier *model.Notifier, userID int) (*model.Notifier, error) {
+	if notifier == nil {
+		return nil, fmt.Errorf("%w: notifier is nil", ErrInvalidInput)
+	}
+
+	existing, err := s.Get(ctx, notifier.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner and type of a channel never change
+	notifier.UserID = existing.UserID
+	notifier.Type = existing.Type
+	if notifier.Name == "" {
+		notifier.Name = existing.Name
+	}
+	if err := notifier.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	updated, err := s.notifierRepo.Update(ctx, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update notifier: %w", err)
 	}
-	return notifier, nil
+	return updated, nil
 }
 
-// Delete removes a notifier
-func (s *NotifierService) Delete(id int) error {
-	if err := s.notifierRepo.Delete(id); err != nil {
+// Delete removes a notifier, and with it every target attachment, after verifying ownership
+func (s *NotifierService) Delete(ctx context.Context, id int, userID int) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.notifierRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete notifier: %w", err)
 	}
 	return nil
 }
 
-// ConfigureObservers configures observers for a specific target
-func (s *NotifierService) ConfigureObservers(targetID int) error {
-	// First detach any existing observers
-	// This ensures we don't have duplicate observers if called multiple times
-	s.subject = notifCoer.NewSubject()
+// Attach links a notifier to a target when the user owns both
+func (s *NotifierService) Attach(ctx context.Context, targetID int, notifierID int, userID int) error {
+	if err := s.authorizeTarget(ctx, targetID, userID); err != nil {
+		return err
+	}
+	if _, err := s.Get(ctx, notifierID, userID); err != nil {
+		return err
+	}
+
+	if err := s.notifierRepo.Attach(ctx, targetID, notifierID); err != nil {
+		return fmt.Errorf("failed to attach notifier: %w", err)
+	}
+	return nil
+}
+
+// Detach unlinks a notifier from a target when the user owns both
+func (s *NotifierService) Detach(ctx context.Context, targetID int, notifierID int, userID int) error {
+	if err := s.authorizeTarget(ctx, targetID, userID); err != nil {
+		return err
+	}
+	if _, err := s.Get(ctx, notifierID, userID); err != nil {
+		return err
+	}
+
+	if err := s.notifierRepo.Detach(ctx, targetID, notifierID); err != nil {
+		return fmt.Errorf("failed to detach notifier: %w", err)
+	}
+	return nil
+}
+
+// AttachToAllTargets links a notifier to every target of the user and returns the number of new links
+func (s *NotifierService) AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error) {
+	if _, err := s.Get(ctx, notifierID, userID); err != nil {
+		return 0, err
+	}
+
+	count, err := s.notifierRepo.AttachToAllTargets(ctx, notifierID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach notifier: %w", err)
+	}
+	return count, nil
+}
+
+// AttachToTag links a notifier to every target of the user carrying the tag and returns the number of new links
+func (s *NotifierService) AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return 0, fmt.Errorf("%w: tag is required", ErrInvalidInput)
+	}
+	if _, err := s.Get(ctx, notifierID, userID); err != nil {
+		return 0, err
+	}
+
+	count, err := s.notifierRepo.AttachToTag(ctx, notifierID, userID, tag)
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach notifier: %w", err)
+	}
+	return count, nil
+}
+
+// NotifyTarget delivers a state to the notifiers attached to a target. Every
+// call subscribes them to a subject of its own, so status changes of
+// different targets never reach each other's notifiers. It returns the
+// deliveries that failed.
+func (s *NotifierService) NotifyTarget(ctx context.Context, targetID int, state notifCoer.State) ([]error, error) {
+	subject, err := s.targetSubject(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return subject.Notify(state), nil
+}
 
-	notifiers, err := s.notifierRepo.GetByTargetID(targetID)
+// targetSubject subscribes the notifiers attached to a target to a new subject.
+// A notifier that cannot be delivered to is logged and skipped, so it does not
+// keep the target's other notifiers from hearing about it.
+func (s *NotifierService) targetSubject(ctx context.Context, targetID int) (*notifCoer.Subject, error) {
+	notifiers, err := s.notifierRepo.GetByTargetID(ctx, targetID)
 	if err != nil {
-		return fmt.Errorf("failed to get notifiers: %w", err)
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
 	}
 
+	subject := notifCoer.NewSubject()
 	for _, notifier := range notifiers {
-		switch notifier.Type {
-		case model.NotifierTypeSlack:
-			config, err := notifier.GetSlackConfig()
-			if err != nil {
-				return fmt.Errorf("failed to get slack config: %w", err)
+		subscription, err := s.subscription(ctx, notifier)
+		if err != nil {
+			slog.Error("Skipping notifier", "notifier", notifier.ID, "target", targetID, "error", err)
+			continue
+		}
+		subject.Subscribe(subscription)
+	}
+	return subject, nil
+}
+
+// subscription subscribes a notifier's observer with its event filter, quiet
+// hours and rate limit. States over the rate limit are held back for the digest.
+func (s *NotifierService) subscription(ctx context.Context, notifier *model.Notifier) (notifCoer.Subscription, error) {
+	observer, err := newObserver(notifier)
+	if err != nil {
+		return notifCoer.Subscription{}, err
+	}
+
+	return notifCoer.Subscription{
+		Observer: observer,
+		Filter: func(state notifCoer.State) notifCoer.Decision {
+			now := s.now()
+			decision := notifier.Decide(state, now)
+			if decision == notifCoer.Deliver && !s.limiter.allow(notifier.ID, notifier.RateLimit, now) {
+				decision = notifCoer.Defer
 			}
-			observer := provider.NewSlackObserver(config.WebhookURL, http.DefaultClient)
-			s.subject.Attach(observer)
-		default:
-			return fmt.Errorf("unsupported notifier type: %s", notifier.Type)
+			countDecision(notifier, decision)
+			return decision
+		},
+		Defer: func(state notifCoer.State) error {
+			return s.notifierRepo.QueueDigest(ctx, notifier.ID, state)
+		},
+	}, nil
+}
+
+// NotifyByIDs delivers a state to the given notifiers, independently of the
+// targets they are attached to. Notifiers that no longer exist are skipped.
+func (s *NotifierService) NotifyByIDs(ctx context.Context, ids []int, state notifCoer.State) error {
+	subject := notifCoer.NewSubject()
+
+	var errs []error
+	for _, id := range ids {
+		notifier, err := s.notifierRepo.Get(ctx, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get notifier %d: %w", id, err))
+			continue
+		}
+		if notifier == nil {
+			continue
 		}
+
+		subscription, err := s.subscription(ctx, notifier)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		subject.Subscribe(subscription)
 	}
 
-	return nil
+	errs = append(errs, subject.Notify(state)...)
+	return errors.Join(errs...)
+}
+
+// newObserver builds the observer delivering to a notifier, worded by its
+// message template when it has one
+func newObserver(notifier *model.Notifier) (notifCoer.Observer, error) {
+	var observer notifCoer.Observer
+	switch notifier.Type {
+	case model.NotifierTypeSlack:
+		config, err := notifier.GetSlackConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slack config: %w", err)
+		}
+		observer = provider.NewSlackObserver(config.WebhookURL, http.DefaultClient, os.Getenv("SLACK_SIGNING_SECRET"))
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", notifier.Type)
+	}
+
+	if notifier.Template != nil {
+		observer = &templatedObserver{observer: observer, template: notifier.Template, notifierID: notifier.ID}
+	}
+	observer = &countingObserver{observer: observer, notifierType: notifier.Type}
+	return &tracingObserver{observer: observer, notifierID: notifier.ID, notifierType: notifier.Type}, nil
 }
 
-func (s *NotifierService) HandleSlackCallback(code string, targetID int) (*model.Notifier, error) {
+// templatedObserver rewords states with a notifier's message template before
+// handing them to the provider
+type templatedObserver struct {
+	observer   notifCoer.Observer
+	template   *model.MessageTemplate
+	notifierID int
+}
+
+// Notify renders the template, falling back to the default wording when it
+// fails so a broken template never swallows an alert
+func (o *templatedObserver) Notify(state notifCoer.State) error {
+	rendered, err := o.template.Apply(state)
+	if err != nil {
+		slog.Error("Failed to render message template", "notifier", o.notifierID, "error", err)
+		return o.observer.Notify(state)
+	}
+	return o.observer.Notify(rendered)
+}
+
+// FlushDigests sends one summary per notifier that has queued states, once its
+// quiet hours are over and its rate limit leaves room for another message
+func (s *NotifierService) FlushDigests(ctx context.Context) error {
+	notifiers, err := s.notifierRepo.GetQueuedNotifiers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get queued notifiers: %w", err)
+	}
+
+	now := s.now()
+	var errs []error
+	for _, notifier := range notifiers {
+		if notifier.QuietHours != nil && notifier.QuietHours.Active(now) {
+			continue
+		}
+		if s.limiter.full(notifier.ID, notifier.RateLimit, now) {
+			continue
+		}
+
+		observer, err := newObserver(notifier)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = s.notifierRepo.TakeDigest(ctx, notifier.ID, func(states []notifCoer.State) error {
+			s.limiter.allow(notifier.ID, notifier.RateLimit, now)
+			if err := observer.Notify(digestState(states, now)); err != nil {
+				return fmt.Errorf("failed to send digest to notifier %d: %w", notifier.ID, err)
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// StartDigests flushes queued digests every interval in the background
+func (s *NotifierService) StartDigests(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.FlushDigests(context.Background()); err != nil {
+				slog.Error("Failed to flush notification digests", "error", err)
+			}
+		}
+	}()
+}
+
+// digestState summarizes the states held back by quiet hours or the rate limit
+// into a single state
+func digestState(states []notifCoer.State, now time.Time) notifCoer.State {
+	lines := make([]string, len(states))
+	for i, state := range states {
+		lines[i] = fmt.Sprintf("%s %s: %s", state.UpdatedAt.Format(time.RFC3339), state.Name, state.Message)
+	}
+
+	return notifCoer.State{
+		Name:      fmt.Sprintf("Notification digest (%d events)", len(states)),
+		Status:    states[len(states)-1].Status,
+		Message:   strings.Join(lines, "\n"),
+		UpdatedAt: now,
+	}
+}
+
+// HandleSlackCallback exchanges the OAuth code for an incoming webhook and
+// returns an unsaved Slack notifier named after the chosen channel. The Slack
+// account that authorized the app is recorded so it can act as the user.
+func (s *NotifierService) HandleSlackCallback(ctx context.Context, code string, userID int) (*model.Notifier, error) {
 	clientId := os.Getenv("SLACK_CLIENT_ID")
 	clientSecret := os.Getenv("SLACK_CLIENT_SECRET")
 
@@ -145,36 +494,53 @@ func (s *NotifierService) HandleSlackCallback(code string, targetID int) (*model
 		return nil, fmt.Errorf("failed to get incoming webhook url")
 	}
 
-	notifier := &model.Notifier{
-		TargetId: targetID,
-		Type:     model.NotifierTypeSlack,
-		Config:   json.RawMessage(`{"webhook_url": "` + webhookUrl + `"}`),
+	config, err := json.Marshal(model.SlackConfig{WebhookURL: webhookUrl})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode slack config: %w", err)
 	}
 
-	return notifier, nil
-}
-
-func (s *NotifierService) ParseOAuthState(state string) (int, error) {
-	parsedState, err := url.ParseQuery(state)
-
-	if err != nil {
-		return 0, fmt.Errorf("invalid state format: %w", err)
+	channel, _ := incomingWebhook["channel"].(string)
+	if channel == "" {
+		channel = string(model.NotifierTypeSlack)
 	}
 
-	targetId, ok := parsedState["target_id"]
+	if err := s.saveSlackInstallation(ctx, result, userID); err != nil {
+		return nil, err
+	}
 
-	if !ok || len(targetId) <= 0 || targetId[0] == "" {
-		return 0, fmt.Errorf("missing target id in state")
+	notifier := &model.Notifier{
+		Name:   channel,
+		Type:   model.NotifierTypeSlack,
+		Config: config,
 	}
 
-	targetIdInt, err := strconv.Atoi(targetId[0])
-	if err != nil {
-		return 0, fmt.Errorf("invalid target id format: %w", err)
+	return notifier, nil
+}
+
+// saveSlackInstallation maps the authorizing Slack account to the user.
+// Responses without an authed user predate user scopes and are skipped.
+func (s *NotifierService) saveSlackInstallation(ctx context.Context, result map[string]any, userID int) error {
+	team, _ := result["team"].(map[string]any)
+	authedUser, _ := result["authed_user"].(map[string]any)
+	teamID, _ := team["id"].(string)
+	slackUserID, _ := authedUser["id"].(string)
+	if teamID == "" || slackUserID == "" {
+		return nil
 	}
 
-	return targetIdInt, nil
+	return s.notifierRepo.SaveSlackInstallation(ctx, &model.SlackInstallation{
+		UserID:      userID,
+		TeamID:      teamID,
+		SlackUserID: slackUserID,
+		CreatedAt:   s.now(),
+	})
 }
 
-func (s *NotifierService) GetSubject() *notifCoer.Subject {
-	return s.subject
+// ResolveSlackUser returns the user a Slack account connected Slack for
+func (s *NotifierService) ResolveSlackUser(ctx context.Context, teamID string, slackUserID string) (int, error) {
+	userID, err := s.notifierRepo.GetUserIDBySlackUser(ctx, teamID, slackUserID)
+	if errors.Is(err, repository.ErrSlackUserNotFound) {
+		return 0, fmt.Errorf("%w: slack account is not connected", ErrUnauthorized)
+	}
+	return userID, err
 }
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"testing"
@@ -12,38 +14,129 @@ import (
 
 	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/repository"
 	"github.com/stretchr/testify/assert"
 )
 
 // mockNotifierRepository is a mock implementation of NotifierRepositoryInterface
 type mockNotifierRepository struct {
-	getByTargetIDFunc func(targetID int) ([]*model.Notifier, error)
-	createFunc        func(notifier *model.Notifier) (*model.Notifier, error)
-	getFunc           func(id int) (*model.Notifier, error)
-	updateFunc        func(id int, config json.RawMessage) (*model.Notifier, error)
-	deleteFunc        func(id int) error
+	getByTargetIDFunc      func(targetID int) ([]*model.Notifier, error)
+	getByUserIDFunc        func(userID int) ([]*model.Notifier, error)
+	createFunc             func(notifier *model.Notifier) (*model.Notifier, error)
+	getFunc                func(id int) (*model.Notifier, error)
+	updateFunc             func(notifier *model.Notifier) (*model.Notifier, error)
+	deleteFunc             func(id int) error
+	getTargetOwnerIDFunc   func(targetID int) (int, error)
+	attachFunc             func(targetID int, notifierID int) error
+	detachFunc             func(targetID int, notifierID int) error
+	attachToAllTargetsFunc func(notifierID int, userID int) (int, error)
+	attachToTagFunc        func(notifierID int, userID int, tag string) (int, error)
+	queueDigestFunc        func(notifierID int, state notification.State) error
+	getQueuedNotifiersFunc func() ([]*model.Notifier, error)
+	takeDigestFunc         func(notifierID int) ([]notification.State, error)
+	installations          []*model.SlackInstallation
+	usedStates             map[string]bool
 }
 
-func (m *mockNotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, error) {
+func (m *mockNotifierRepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.Notifier, error) {
 	return m.getByTargetIDFunc(targetID)
 }
 
-func (m *mockNotifierRepository) Create(notifier *model.Notifier) (*model.Notifier, error) {
+func (m *mockNotifierRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Notifier, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockNotifierRepository) Create(ctx context.Context, notifier *model.Notifier) (*model.Notifier, error) {
 	return m.createFunc(notifier)
 }
 
-func (m *mockNotifierRepository) Get(id int) (*model.Notifier, error) {
+func (m *mockNotifierRepository) Get(ctx context.Context, id int) (*model.Notifier, error) {
 	return m.getFunc(id)
 }
 
-func (m *mockNotifierRepository) Update(id int, config json.RawMessage) (*model.Notifier, error) {
-	return m.updateFunc(id, config)
+func (m *mockNotifierRepository) Update(ctx context.Context, notifier *model.Notifier) (*model.Notifier, error) {
+	return m.updateFunc(notifier)
 }
 
-func (m *mockNotifierRepository) Delete(id int) error {
+func (m *mockNotifierRepository) Delete(ctx context.Context, id int) error {
 	return m.deleteFunc(id)
 }
 
+func (m *mockNotifierRepository) GetTargetOwnerID(ctx context.Context, targetID int) (int, error) {
+	if m.getTargetOwnerIDFunc != nil {
+		return m.getTargetOwnerIDFunc(targetID)
+	}
+	return 1, nil
+}
+
+func (m *mockNotifierRepository) Attach(ctx context.Context, targetID int, notifierID int) error {
+	return m.attachFunc(targetID, notifierID)
+}
+
+func (m *mockNotifierRepository) Detach(ctx context.Context, targetID int, notifierID int) error {
+	return m.detachFunc(targetID, notifierID)
+}
+
+func (m *mockNotifierRepository) AttachToAllTargets(ctx context.Context, notifierID int, userID int) (int, error) {
+	return m.attachToAllTargetsFunc(notifierID, userID)
+}
+
+func (m *mockNotifierRepository) AttachToTag(ctx context.Context, notifierID int, userID int, tag string) (int, error) {
+	return m.attachToTagFunc(notifierID, userID, tag)
+}
+
+func (m *mockNotifierRepository) QueueDigest(ctx context.Context, notifierID int, state notification.State) error {
+	return m.queueDigestFunc(notifierID, state)
+}
+
+func (m *mockNotifierRepository) GetQueuedNotifiers(ctx context.Context) ([]*model.Notifier, error) {
+	return m.getQueuedNotifiersFunc()
+}
+
+func (m *mockNotifierRepository) TakeDigest(ctx context.Context, notifierID int, send func(states []notification.State) error) error {
+	states, err := m.takeDigestFunc(notifierID)
+	if err != nil || len(states) == 0 {
+		return err
+	}
+	return send(states)
+}
+
+func (m *mockNotifierRepository) SaveSlackInstallation(ctx context.Context, installation *model.SlackInstallation) error {
+	m.installations = append(m.installations, installation)
+	return nil
+}
+
+func (m *mockNotifierRepository) GetUserIDBySlackUser(ctx context.Context, teamID string, slackUserID string) (int, error) {
+	for _, installation := range m.installations {
+		if installation.TeamID == teamID && installation.SlackUserID == slackUserID {
+			return installation.UserID, nil
+		}
+	}
+	return 0, repository.ErrSlackUserNotFound
+}
+
+func (m *mockNotifierRepository) UseOAuthState(ctx context.Context, nonce string, expiresAt time.Time, now time.Time) (bool, error) {
+	if m.usedStates == nil {
+		m.usedStates = make(map[string]bool)
+	}
+	if m.usedStates[nonce] {
+		return false, nil
+	}
+	m.usedStates[nonce] = true
+	return true, nil
+}
+
+// ownedNotifier returns a slack notifier belonging to user 1
+func ownedNotifier(id int) (*model.Notifier, error) {
+	return &model.Notifier{
+		ID:     id,
+		UserID: 1,
+		Name:   "#alerts",
+		Type:   model.NotifierTypeSlack,
+		Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+	}, nil
+}
+
 // mockObserver is a mock implementation of the Observer interface
 type mockObserver struct {
 	state notification.State
@@ -64,26 +157,25 @@ func (m *mockObserver) Notify(state notification.State) error {
 
 func TestNotifierService_Create(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo)
 
 	t.Run("successful creation", func(t *testing.T) {
 		mockRepo.createFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
-			return &model.Notifier{
-				ID:       1,
-				TargetId: 1,
-				Type:     model.NotifierTypeSlack,
-				Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
-			}, nil
+			created := *notifier
+			created.ID = 7
+			return &created, nil
 		}
 
 		notifier := &model.Notifier{
-			TargetId: 1,
-			Type:     model.NotifierTypeSlack,
-			Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+			Type:   model.NotifierTypeSlack,
+			Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
 		}
 
-		err := service.Create(notifier)
+		err := service.Create(context.Background(), notifier, 1)
 		assert.NoError(t, err)
+		assert.Equal(t, 7, notifier.ID)
+		assert.Equal(t, 1, notifier.UserID)
+		assert.Equal(t, "slack", notifier.Name)
 	})
 
 	t.Run("creation fails", func(t *testing.T) {
@@ -91,31 +183,37 @@ func TestNotifierService_Create(t *testing.T) {
 			return nil, fmt.Errorf("db error")
 		}
 
-		err := service.Create(&model.Notifier{})
+		notifier := &model.Notifier{
+			Type:   model.NotifierTypeSlack,
+			Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		}
+
+		err := service.Create(context.Background(), notifier, 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create notifier")
 	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		err := service.Create(context.Background(), &model.Notifier{Type: model.NotifierTypeSlack, Config: json.RawMessage(`{}`)}, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("invalid user", func(t *testing.T) {
+		err := service.Create(context.Background(), &model.Notifier{}, 0)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
 }
 
 func TestNotifierService_Get(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo)
 
 	t.Run("successful retrieval", func(t *testing.T) {
-		expected := &model.Notifier{
-			ID:       1,
-			TargetId: 1,
-			Type:     model.NotifierTypeSlack,
-			Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
-		}
+		mockRepo.getFunc = ownedNotifier
 
-		mockRepo.getFunc = func(id int) (*model.Notifier, error) {
-			return expected, nil
-		}
-
-		notifier, err := service.Get(1)
+		notifier, err := service.Get(context.Background(), 1, 1)
 		assert.NoError(t, err)
-		assert.Equal(t, expected, notifier)
+		assert.Equal(t, 1, notifier.ID)
 	})
 
 	t.Run("retrieval fails", func(t *testing.T) {
@@ -123,58 +221,145 @@ func TestNotifierService_Get(t *testing.T) {
 			return nil, fmt.Errorf("db error")
 		}
 
-		notifier, err := service.Get(1)
+		notifier, err := service.Get(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.Nil(t, notifier)
 		assert.Contains(t, err.Error(), "failed to get notifier")
 	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.getFunc = func(id int) (*model.Notifier, error) {
+			return nil, nil
+		}
+
+		notifier, err := service.Get(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrNotifierNotFound)
+		assert.Nil(t, notifier)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockRepo.getFunc = ownedNotifier
+
+		notifier, err := service.Get(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		assert.Nil(t, notifier)
+	})
 }
 
-func TestNotifierService_Update(t *testing.T) {
+func TestNotifierService_GetByUserID(t *testing.T) {
+	mockRepo := &mockNotifierRepository{
+		getByUserIDFunc: func(userID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{{ID: 1, UserID: userID}, {ID: 2, UserID: userID}}, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+
+	notifiers, err := service.GetByUserID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, notifiers, 2)
+
+	_, err = service.GetByUserID(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestNotifierService_GetByTargetID(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo)
 
-	t.Run("successful update", func(t *testing.T) {
-		config := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/new"}`)
+	t.Run("successful retrieval", func(t *testing.T) {
+		mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{{ID: 1, UserID: 1}}, nil
+		}
 
-		expected := &model.Notifier{
-			ID:       1,
-			TargetId: 1,
-			Type:     model.NotifierTypeSlack,
-			Config:   config,
+		notifiers, err := service.GetByTargetID(context.Background(), 1, 1)
+		assert.NoError(t, err)
+		assert.Len(t, notifiers, 1)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockRepo.getTargetOwnerIDFunc = func(targetID int) (int, error) {
+			return 2, nil
 		}
+		defer func() { mockRepo.getTargetOwnerIDFunc = nil }()
 
-		mockRepo.updateFunc = func(id int, cfg json.RawMessage) (*model.Notifier, error) {
-			return expected, nil
+		notifiers, err := service.GetByTargetID(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		assert.Nil(t, notifiers)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		mockRepo.getTargetOwnerIDFunc = func(targetID int) (int, error) {
+			return 0, repository.ErrTargetNotFound
 		}
+		defer func() { mockRepo.getTargetOwnerIDFunc = nil }()
+
+		_, err := service.GetByTargetID(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrTargetNotFound)
+	})
+}
+
+func TestNotifierService_Update(t *testing.T) {
+	mockRepo := &mockNotifierRepository{getFunc: ownedNotifier}
+	service := NewNotifierService(mockRepo)
 
-		notifier, err := service.Update(1, config)
+	t.Run("successful update", func(t *testing.T) {
+		mockRepo.updateFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
+			return notifier, nil
+		}
+
+		notifier, err := service.Update(context.Background(), &model.Notifier{
+			ID:     1,
+			Name:   "#incidents",
+			Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/new"}`),
+		}, 1)
 		assert.NoError(t, err)
-		assert.Equal(t, expected, notifier)
+		assert.Equal(t, "#incidents", notifier.Name)
+		assert.Equal(t, model.NotifierTypeSlack, notifier.Type)
+		assert.Equal(t, 1, notifier.UserID)
 	})
 
 	t.Run("update fails", func(t *testing.T) {
-		mockRepo.updateFunc = func(id int, cfg json.RawMessage) (*model.Notifier, error) {
+		mockRepo.updateFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
 			return nil, fmt.Errorf("db error")
 		}
 
-		notifier, err := service.Update(1, json.RawMessage{})
+		notifier, err := service.Update(context.Background(), &model.Notifier{
+			ID:     1,
+			Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/new"}`),
+		}, 1)
 		assert.Error(t, err)
 		assert.Nil(t, notifier)
 		assert.Contains(t, err.Error(), "failed to update notifier")
 	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		notifier, err := service.Update(context.Background(), &model.Notifier{ID: 1, Config: json.RawMessage(`{}`)}, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.Nil(t, notifier)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockRepo.updateFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
+			t.Fatal("update must not be called for an unauthorized user")
+			return nil, nil
+		}
+
+		notifier, err := service.Update(context.Background(), &model.Notifier{ID: 1}, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		assert.Nil(t, notifier)
+	})
 }
 
 func TestNotifierService_Delete(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	mockRepo := &mockNotifierRepository{getFunc: ownedNotifier}
+	service := NewNotifierService(mockRepo)
 
 	t.Run("successful deletion", func(t *testing.T) {
 		mockRepo.deleteFunc = func(id int) error {
 			return nil
 		}
 
-		err := service.Delete(1)
+		err := service.Delete(context.Background(), 1, 1)
 		assert.NoError(t, err)
 	})
 
@@ -183,103 +368,374 @@ func TestNotifierService_Delete(t *testing.T) {
 			return fmt.Errorf("db error")
 		}
 
-		err := service.Delete(1)
+		err := service.Delete(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete notifier")
 	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockRepo.deleteFunc = func(id int) error {
+			t.Fatal("delete must not be called for an unauthorized user")
+			return nil
+		}
+
+		err := service.Delete(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
 }
 
-func TestNotifierService_ConfigureObservers(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	subject := notification.NewSubject()
-	service := NewNotifierService(mockRepo, subject)
+func TestNotifierService_AttachDetach(t *testing.T) {
+	var attached, detached []int
+	mockRepo := &mockNotifierRepository{
+		getFunc: ownedNotifier,
+		attachFunc: func(targetID int, notifierID int) error {
+			attached = append(attached, targetID)
+			return nil
+		},
+		detachFunc: func(targetID int, notifierID int) error {
+			detached = append(detached, targetID)
+			return nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
 
-	t.Run("successful configuration with slack observer", func(t *testing.T) {
-		mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
-			return []*model.Notifier{
-				{
-					ID:       1,
-					TargetId: 1,
-					Type:     model.NotifierTypeSlack,
-					Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
-				},
-			}, nil
+	t.Run("successful attach and detach", func(t *testing.T) {
+		assert.NoError(t, service.Attach(context.Background(), 3, 1, 1))
+		assert.NoError(t, service.Detach(context.Background(), 3, 1, 1))
+		assert.Equal(t, []int{3}, attached)
+		assert.Equal(t, []int{3}, detached)
+	})
+
+	t.Run("notifier owned by another user", func(t *testing.T) {
+		err := service.Attach(context.Background(), 3, 1, 2)
+		assert.Error(t, err)
+		assert.Len(t, attached, 1)
+	})
+
+	t.Run("target owned by another user", func(t *testing.T) {
+		mockRepo.getTargetOwnerIDFunc = func(targetID int) (int, error) {
+			return 2, nil
 		}
+		defer func() { mockRepo.getTargetOwnerIDFunc = nil }()
+
+		assert.ErrorIs(t, service.Attach(context.Background(), 3, 1, 1), ErrUnauthorized)
+		assert.ErrorIs(t, service.Detach(context.Background(), 3, 1, 1), ErrUnauthorized)
+		assert.Len(t, attached, 1)
+		assert.Len(t, detached, 1)
+	})
+}
+
+func TestNotifierService_BulkAttach(t *testing.T) {
+	mockRepo := &mockNotifierRepository{
+		getFunc: ownedNotifier,
+		attachToAllTargetsFunc: func(notifierID int, userID int) (int, error) {
+			return 5, nil
+		},
+		attachToTagFunc: func(notifierID int, userID int, tag string) (int, error) {
+			assert.Equal(t, "production", tag)
+			return 2, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
 
-		err := service.ConfigureObservers(1)
+	t.Run("attach to all targets", func(t *testing.T) {
+		count, err := service.AttachToAllTargets(context.Background(), 1, 1)
 		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
 	})
 
-	t.Run("repository error", func(t *testing.T) {
-		mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
-			return nil, fmt.Errorf("db error")
-		}
+	t.Run("attach to tag", func(t *testing.T) {
+		count, err := service.AttachToTag(context.Background(), 1, 1, " Production ")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
 
-		err := service.ConfigureObservers(1)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to get notifiers")
+	t.Run("empty tag", func(t *testing.T) {
+		_, err := service.AttachToTag(context.Background(), 1, 1, " ")
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		_, err := service.AttachToAllTargets(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
 	})
 }
 
-func TestNotifierService_Subject(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	subject := notification.NewSubject()
-	service := NewNotifierService(mockRepo, subject)
-
-	// Create and attach mock observers
-	observer1 := newMockObserver(nil)
-	observer2 := newMockObserver(fmt.Errorf("notification error"))
-	service.subject.Attach(observer1)
-	service.subject.Attach(observer2)
-
-	// Test notification using Subject directly
-	state := notification.State{
-		Name:      "test-system",
-		Status:    "up",
-		Message:   "System is up",
-		UpdatedAt: time.Now(),
+func TestNotifierService_NotifyTarget_Filters(t *testing.T) {
+	var received []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	var queued []notification.State
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{
+				{
+					ID:     1,
+					Type:   model.NotifierTypeSlack,
+					Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/down-only"}`),
+					Events: []string{model.EventDown},
+				},
+				{
+					ID:     2,
+					Type:   model.NotifierTypeSlack,
+					Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/night-digest"}`),
+					QuietHours: &model.QuietHours{
+						Windows: []model.QuietWindow{{Start: "22:00", End: "07:00"}},
+						Action:  model.QuietActionDigest,
+					},
+				},
+			}, nil
+		},
+		queueDigestFunc: func(notifierID int, state notification.State) error {
+			assert.Equal(t, 2, notifierID)
+			queued = append(queued, state)
+			return nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+	service.now = func() time.Time { return time.Date(2025, 4, 20, 23, 0, 0, 0, time.UTC) }
+
+	errs, err := service.NotifyTarget(context.Background(), 1, notification.State{Name: "example.org", Status: model.EventUp})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	errs, err = service.NotifyTarget(context.Background(), 1, notification.State{Name: "example.org", Status: model.EventDown})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, []string{"/down-only"}, received)
+	assert.Len(t, queued, 2)
+}
+
+func TestNotifierService_NotifyTarget_Template(t *testing.T) {
+	var payloads []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{{
+				ID:       1,
+				Type:     model.NotifierTypeSlack,
+				Config:   json.RawMessage(`{"webhook_url": "` + webhook.URL + `"}`),
+				Template: &model.MessageTemplate{Title: "{{upper .Status}}: {{.Name}}", Body: "{{.Name}} was {{.PreviousStatus}} for {{.Duration}}"},
+			}}, nil
+		},
 	}
-	errors := service.subject.Notify(state)
+	service := NewNotifierService(mockRepo)
+
+	errs, err := service.NotifyTarget(context.Background(), 1, notification.State{Name: "example.org", Status: model.EventUp, PreviousStatus: model.EventDown, Duration: 5 * time.Minute})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
 
-	// Verify results
-	assert.Len(t, errors, 1) // One observer should fail
-	assert.Equal(t, state, observer1.state)
-	assert.Empty(t, observer2.state) // Failed observer shouldn't have state
+	assert.Len(t, payloads, 1)
+	assert.Contains(t, payloads[0], "UP: example.org")
+	assert.Contains(t, payloads[0], "example.org was down for 5m0s")
 }
 
-func TestNotifierService_ParseOAuthState(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+func TestTemplatedObserver_FallsBackOnRenderError(t *testing.T) {
+	observer := newMockObserver(nil)
+	templated := &templatedObserver{observer: observer, template: &model.MessageTemplate{Body: "{{.Missing}}"}}
 
-	t.Run("successful parsing", func(t *testing.T) {
-		state := "target_id=1"
-		targetId, err := service.ParseOAuthState(state)
+	state := notification.State{Name: "example.org", Status: model.EventDown, Message: "Target example.org is down"}
+	assert.NoError(t, templated.Notify(state))
+	assert.Equal(t, state, observer.state)
+}
+
+func TestNotifierService_NotifyTarget_RateLimit(t *testing.T) {
+	var received int
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	var queued []notification.State
+	notifier := &model.Notifier{
+		ID:        1,
+		Type:      model.NotifierTypeSlack,
+		Config:    json.RawMessage(`{"webhook_url": "` + webhook.URL + `"}`),
+		RateLimit: 2,
+	}
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{notifier}, nil
+		},
+		queueDigestFunc: func(notifierID int, state notification.State) error {
+			queued = append(queued, state)
+			return nil
+		},
+		getQueuedNotifiersFunc: func() ([]*model.Notifier, error) {
+			return []*model.Notifier{notifier}, nil
+		},
+		takeDigestFunc: func(notifierID int) ([]notification.State, error) {
+			return queued, nil
+		},
+	}
+	now := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+	service := NewNotifierService(mockRepo)
+	service.now = func() time.Time { return now }
+
+	for _, status := range []string{model.EventDown, model.EventUp, model.EventDown, model.EventUp} {
+		errs, err := service.NotifyTarget(context.Background(), 1, notification.State{Name: "example.org", Status: status})
 		assert.NoError(t, err)
-		assert.Equal(t, 1, targetId)
-	})
+		assert.Empty(t, errs)
+	}
+	assert.Equal(t, 2, received)
+	assert.Len(t, queued, 2)
 
-	t.Run("invalid state", func(t *testing.T) {
-		state := "%invalid_state"
-		_, err := service.ParseOAuthState(state)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid state format")
-	})
+	// The held back states wait until the limit leaves room again
+	assert.NoError(t, service.FlushDigests(context.Background()))
+	assert.Equal(t, 2, received)
 
-	t.Run("missing target id", func(t *testing.T) {
-		state := "target_id="
-		_, err := service.ParseOAuthState(state)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "missing target id in state")
+	now = now.Add(time.Hour)
+	assert.NoError(t, service.FlushDigests(context.Background()))
+	assert.Equal(t, 3, received)
+}
+
+func TestNotifierService_FlushDigests(t *testing.T) {
+	var payloads []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	quietHours := &model.QuietHours{
+		Windows: []model.QuietWindow{{Start: "22:00", End: "07:00"}},
+		Action:  model.QuietActionDigest,
+	}
+	taken := map[int]bool{}
+	mockRepo := &mockNotifierRepository{
+		getQueuedNotifiersFunc: func() ([]*model.Notifier, error) {
+			return []*model.Notifier{{
+				ID:         1,
+				Type:       model.NotifierTypeSlack,
+				Config:     json.RawMessage(`{"webhook_url": "` + webhook.URL + `"}`),
+				QuietHours: quietHours,
+			}}, nil
+		},
+		takeDigestFunc: func(notifierID int) ([]notification.State, error) {
+			taken[notifierID] = true
+			return []notification.State{
+				{Name: "example.org", Status: "down", Message: "Target example.org is down", UpdatedAt: time.Date(2025, 4, 20, 23, 0, 0, 0, time.UTC)},
+				{Name: "example.org", Status: "up", Message: "Target example.org is up", UpdatedAt: time.Date(2025, 4, 21, 1, 0, 0, 0, time.UTC)},
+			}, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+
+	t.Run("still quiet", func(t *testing.T) {
+		service.now = func() time.Time { return time.Date(2025, 4, 21, 3, 0, 0, 0, time.UTC) }
+
+		assert.NoError(t, service.FlushDigests(context.Background()))
+		assert.Empty(t, taken)
+		assert.Empty(t, payloads)
 	})
 
-	t.Run("invalid target id", func(t *testing.T) {
-		state := "target_id=invalid"
-		_, err := service.ParseOAuthState(state)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid target id format")
+	t.Run("morning", func(t *testing.T) {
+		service.now = func() time.Time { return time.Date(2025, 4, 21, 7, 30, 0, 0, time.UTC) }
+
+		assert.NoError(t, service.FlushDigests(context.Background()))
+		assert.True(t, taken[1])
+		assert.Len(t, payloads, 1)
+		assert.Contains(t, payloads[0], "Notification digest (2 events)")
+		assert.Contains(t, payloads[0], "Target example.org is down")
 	})
 }
 
+func TestNotifierService_FlushDigests_Failed(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+
+	mockRepo := &mockNotifierRepository{
+		getQueuedNotifiersFunc: func() ([]*model.Notifier, error) {
+			return []*model.Notifier{{
+				ID:     1,
+				Type:   model.NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `"}`),
+			}}, nil
+		},
+		takeDigestFunc: func(notifierID int) ([]notification.State, error) {
+			return []notification.State{{Name: "example.org", Status: "down", Message: "Target example.org is down"}}, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+
+	// The failure reaches TakeDigest, which keeps the entries queued
+	err := service.FlushDigests(context.Background())
+	assert.ErrorContains(t, err, "failed to send digest to notifier 1")
+}
+
+func TestNotifierService_NotifyTarget(t *testing.T) {
+	var received []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			if targetID != 1 {
+				return nil, fmt.Errorf("db error")
+			}
+			return []*model.Notifier{
+				{ID: 1, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/all"}`)},
+				{ID: 2, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/up-only"}`), Events: []string{model.EventUp}},
+				{ID: 3, Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com"]}`)},
+			}, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+
+	// The email notifier has no provider, which does not hold back the others
+	errs, err := service.NotifyTarget(context.Background(), 1, notification.State{Name: "example.org", Status: model.EventDown})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"/all"}, received)
+
+	_, err = service.NotifyTarget(context.Background(), 2, notification.State{Name: "example.org", Status: model.EventDown})
+	assert.ErrorContains(t, err, "failed to get notifiers")
+}
+
+func TestNotifierService_NotifyByIDs(t *testing.T) {
+	var received []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	mockRepo := &mockNotifierRepository{
+		getFunc: func(id int) (*model.Notifier, error) {
+			switch id {
+			case 1:
+				return &model.Notifier{ID: 1, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/lead"}`)}, nil
+			case 2:
+				return &model.Notifier{ID: 2, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "` + webhook.URL + `/up-only"}`), Events: []string{model.EventUp}}, nil
+			default:
+				return nil, nil
+			}
+		},
+	}
+	service := NewNotifierService(mockRepo)
+
+	err := service.NotifyByIDs(context.Background(), []int{1, 2, 3}, notification.State{Name: "example.org", Status: model.EventDown})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/lead"}, received)
+}
+
 func TestNotifierService_HandleSlackCallback(t *testing.T) {
 	// Create a mock HTTP server to simulate Slack's OAuth API
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -309,8 +765,11 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"ok": true,
 			"incoming_webhook": map[string]interface{}{
-				"url": "https://hooks.slack.com/services/TEST/WEBHOOK/URL",
+				"url":     "https://hooks.slack.com/services/TEST/WEBHOOK/URL",
+				"channel": "#alerts",
 			},
+			"team":        map[string]interface{}{"id": "T123"},
+			"authed_user": map[string]interface{}{"id": "U123"},
 		})
 	}))
 	defer mockServer.Close()
@@ -322,20 +781,17 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 	tests := []struct {
 		name      string
 		code      string
-		targetID  int
 		wantErr   bool
 		errString string
 	}{
 		{
-			name:     "successful callback",
-			code:     "test_code",
-			targetID: 123,
-			wantErr:  false,
+			name:    "successful callback",
+			code:    "test_code",
+			wantErr: false,
 		},
 		{
 			name:      "empty code",
 			code:      "",
-			targetID:  123,
 			wantErr:   true,
 			errString: "missing code or client credentials",
 		},
@@ -345,14 +801,14 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create service with mock repository
 			mockRepo := &mockNotifierRepository{}
-			service := NewNotifierService(mockRepo, nil)
+			service := NewNotifierService(mockRepo)
 
 			// Override the Slack API URL to point to our mock server
 			originalURL := SlackTokenURL
 			SlackTokenURL = mockServer.URL + "/api/oauth.v2.access"
 			defer func() { SlackTokenURL = originalURL }()
 
-			notifier, err := service.HandleSlackCallback(tt.code, tt.targetID)
+			notifier, err := service.HandleSlackCallback(context.Background(), tt.code, 1)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -365,9 +821,16 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.NotNil(t, notifier)
-			assert.Equal(t, tt.targetID, notifier.TargetId)
+			assert.Equal(t, "#alerts", notifier.Name)
 			assert.Equal(t, model.NotifierTypeSlack, notifier.Type)
 			assert.Contains(t, string(notifier.Config), "hooks.slack.com")
+
+			userID, err := service.ResolveSlackUser(context.Background(), "T123", "U123")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, userID)
+
+			_, err = service.ResolveSlackUser(context.Background(), "T123", "U999")
+			assert.ErrorIs(t, err, ErrUnauthorized)
 		})
 	}
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// oauthStateTTL bounds how long a user can take to connect Slack
+const oauthStateTTL = 10 * time.Minute
+
+// ErrInvalidState is returned when an OAuth state was not issued to the user,
+// was already used or has expired
+var ErrInvalidState = errors.New("invalid oauth state")
+
+// NewOAuthState returns the state to send along with a Slack authorization
+// request started by the user from one of their targets. The state names the
+// user and the target, expires, and is signed with the Slack client secret,
+// so nothing needs to be stored until it is used.
+func (s *NotifierService) NewOAuthState(ctx context.Context, targetID int, userID int) (string, error) {
+	if err := s.authorizeTarget(ctx, targetID, userID); err != nil {
+		return "", err
+	}
+
+	secret := os.Getenv("SLACK_CLIENT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("missing client credentials")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate oauth state: %w", err)
+	}
+
+	expiresAt := s.now().Add(oauthStateTTL)
+	payload := fmt.Sprintf("%d.%d.%d.%s", userID, targetID, expiresAt.Unix(), hex.EncodeToString(nonce))
+
+	return payload + "." + oauthStateSignature(secret, payload), nil
+}
+
+// ParseOAuthState checks a state returned by Slack and returns the target the
+// connection was started from. The state must have been issued to the same
+// user, and can only be used once, which holds across restarts.
+func (s *NotifierService) ParseOAuthState(ctx context.Context, state string, userID int) (int, error) {
+	secret := os.Getenv("SLACK_CLIENT_SECRET")
+	if secret == "" {
+		return 0, fmt.Errorf("missing client credentials")
+	}
+
+	payload, signature, ok := cutLast(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(oauthStateSignature(secret, payload))) {
+		return 0, fmt.Errorf("%w: signature mismatch", ErrInvalidState)
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("%w: malformed state", ErrInvalidState)
+	}
+	stateUserID, errUser := strconv.Atoi(parts[0])
+	targetID, errTarget := strconv.Atoi(parts[1])
+	expiresAt, errExpiry := strconv.ParseInt(parts[2], 10, 64)
+	if err := errors.Join(errUser, errTarget, errExpiry); err != nil {
+		return 0, fmt.Errorf("%w: malformed state", ErrInvalidState)
+	}
+
+	if stateUserID != userID {
+		return 0, fmt.Errorf("%w: state was issued to another user", ErrInvalidState)
+	}
+	now := s.now()
+	expiry := time.Unix(expiresAt, 0)
+	if !now.Before(expiry) {
+		return 0, fmt.Errorf("%w: state has expired", ErrInvalidState)
+	}
+	unused, err := s.notifierRepo.UseOAuthState(ctx, parts[3], expiry, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to use oauth state: %w", err)
+	}
+	if !unused {
+		return 0, fmt.Errorf("%w: state was already used", ErrInvalidState)
+	}
+
+	return targetID, nil
+}
+
+func oauthStateSignature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("oauth-state:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s string, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
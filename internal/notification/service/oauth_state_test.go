@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierService_OAuthState(t *testing.T) {
+	t.Setenv("SLACK_CLIENT_SECRET", "secret")
+	now := time.Date(2025, 4, 28, 9, 0, 0, 0, time.UTC)
+	mockRepo := &mockNotifierRepository{
+		getTargetOwnerIDFunc: func(targetID int) (int, error) {
+			return 1, nil
+		},
+	}
+	service := NewNotifierService(mockRepo)
+	service.now = func() time.Time { return now }
+
+	t.Run("round trip", func(t *testing.T) {
+		state, err := service.NewOAuthState(context.Background(), 3, 1)
+		assert.NoError(t, err)
+
+		targetID, err := service.ParseOAuthState(context.Background(), state, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, targetID)
+
+		// A state can only be used once
+		_, err = service.ParseOAuthState(context.Background(), state, 1)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("someone else's target", func(t *testing.T) {
+		_, err := service.NewOAuthState(context.Background(), 3, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+
+	t.Run("issued to another user", func(t *testing.T) {
+		state, err := service.NewOAuthState(context.Background(), 3, 1)
+		assert.NoError(t, err)
+
+		_, err = service.ParseOAuthState(context.Background(), state, 2)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		state, err := service.NewOAuthState(context.Background(), 3, 1)
+		assert.NoError(t, err)
+
+		_, err = service.ParseOAuthState(context.Background(), strings.Replace(state, "1.3.", "1.4.", 1), 1)
+		assert.ErrorIs(t, err, ErrInvalidState)
+		_, err = service.ParseOAuthState(context.Background(), "target_id=3", 1)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		state, err := service.NewOAuthState(context.Background(), 3, 1)
+		assert.NoError(t, err)
+
+		service.now = func() time.Time { return now.Add(oauthStateTTL) }
+		defer func() { service.now = func() time.Time { return now } }()
+
+		_, err = service.ParseOAuthState(context.Background(), state, 1)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+}
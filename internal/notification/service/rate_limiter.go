@@ -0,0 +1,58 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// rateWindow is the period a notifier's rate limit applies to
+const rateWindow = time.Hour
+
+// rateLimiter counts the notifications delivered to each notifier over a
+// sliding window
+type rateLimiter struct {
+	mu   sync.Mutex
+	sent map[int][]time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{sent: make(map[int][]time.Time)}
+}
+
+// allow records a delivery to the notifier unless it already reached its
+// limit within the window. A limit of zero allows every delivery.
+func (l *rateLimiter) allow(notifierID int, limit int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 {
+		return true
+	}
+	if len(l.prune(notifierID, now)) >= limit {
+		return false
+	}
+	l.sent[notifierID] = append(l.sent[notifierID], now)
+	return true
+}
+
+// full reports whether the notifier reached its limit within the window
+func (l *rateLimiter) full(notifierID int, limit int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return limit > 0 && len(l.prune(notifierID, now)) >= limit
+}
+
+// prune drops the deliveries that left the window and returns the rest
+func (l *rateLimiter) prune(notifierID int, now time.Time) []time.Time {
+	sent := l.sent[notifierID]
+	for len(sent) > 0 && now.Sub(sent[0]) >= rateWindow {
+		sent = sent[1:]
+	}
+	if len(sent) == 0 {
+		delete(l.sent, notifierID)
+		return nil
+	}
+	l.sent[notifierID] = sent
+	return sent
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2025, 4, 20, 10, 0, 0, 0, time.UTC)
+
+	assert.True(t, limiter.allow(1, 2, now))
+	assert.True(t, limiter.allow(1, 2, now.Add(10*time.Minute)))
+	assert.False(t, limiter.allow(1, 2, now.Add(20*time.Minute)))
+	assert.True(t, limiter.full(1, 2, now.Add(20*time.Minute)))
+
+	// Other notifiers and unlimited ones are not affected
+	assert.True(t, limiter.allow(2, 2, now.Add(20*time.Minute)))
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.allow(3, 0, now))
+	}
+
+	// The first delivery leaves the window after an hour
+	assert.False(t, limiter.full(1, 2, now.Add(time.Hour)))
+	assert.True(t, limiter.allow(1, 2, now.Add(time.Hour)))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	notifCoer "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const tracerName = "github.com/shuvo-paul/uptimebot/internal/notification/service"
+
+// tracingObserver creates a span for each delivery of a notifier's observer
+type tracingObserver struct {
+	observer     notifCoer.Observer
+	notifierID   int
+	notifierType model.NotifierType
+}
+
+func (o *tracingObserver) Notify(state notifCoer.State) error {
+	_, span := otel.Tracer(tracerName).Start(context.Background(), "Observer.Notify")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("notifier.id", o.notifierID),
+		attribute.String("notifier.type", string(o.notifierType)),
+		attribute.String("notification.status", state.Status),
+	)
+	if state.TargetID != 0 {
+		span.SetAttributes(attribute.Int("target.id", state.TargetID))
+	}
+	if state.IncidentID != 0 {
+		span.SetAttributes(attribute.Int("incident.id", state.IncidentID))
+	}
+
+	err := o.observer.Notify(state)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
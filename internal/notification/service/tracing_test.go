@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingObserver(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	sent := &tracingObserver{observer: newMockObserver(nil), notifierID: 3, notifierType: model.NotifierTypeSlack}
+	failed := &tracingObserver{observer: newMockObserver(errors.New("webhook failed")), notifierID: 4, notifierType: model.NotifierTypeSlack}
+
+	assert.NoError(t, sent.Notify(notification.State{Status: "down", TargetID: 9}))
+	assert.Error(t, failed.Notify(notification.State{Status: "up"}))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "Observer.Notify", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("notifier.id", 3))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("notifier.type", "slack"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("target.id", 9))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,525 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const (
+	// upcomingPeriod is how far ahead the schedule page shows shifts
+	upcomingPeriod = 14 * 24 * time.Hour
+	// calendarPeriod is how far ahead the iCal export lists shifts
+	calendarPeriod = 12 * 7 * 24 * time.Hour
+
+	dateFormat     = "2006-01-02"
+	dateTimeFormat = "2006-01-02T15:04"
+	displayFormat  = "Mon Jan 2, 15:04"
+)
+
+type ScheduleHandler struct {
+	scheduleService service.ScheduleServiceInterface
+	flash           flash.FlashStoreInterface
+	now             func() time.Time
+	Template        struct {
+		List   *renderer.Template
+		Create *renderer.Template
+		Edit   *renderer.Template
+		Show   *renderer.Template
+	}
+}
+
+func NewScheduleHandler(scheduleService service.ScheduleServiceInterface, flash flash.FlashStoreInterface) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		flash:           flash,
+		now:             time.Now,
+	}
+}
+
+// shiftRow is a shift formatted in the schedule's timezone
+type shiftRow struct {
+	Email    string
+	Start    string
+	End      string
+	Override bool
+}
+
+// overrideRow is an override formatted in the schedule's timezone
+type overrideRow struct {
+	ID    int
+	Email string
+	Start string
+	End   string
+}
+
+// errorStatus maps on-call schedule service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrScheduleNotFound), errors.Is(err, service.ErrInvitationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseID reads a positive integer path value
+func parseID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// parseSchedule reads the schedule settings and member emails from the submitted form
+func parseSchedule(r *http.Request) (*model.Schedule, []string, error) {
+	startsOn, err := time.Parse(dateFormat, r.FormValue("starts_on"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid start date")
+	}
+
+	schedule := &model.Schedule{
+		Name:     strings.TrimSpace(r.FormValue("name")),
+		Timezone: strings.TrimSpace(r.FormValue("timezone")),
+		Rotation: model.Rotation(r.FormValue("rotation")),
+		Handoff:  strings.TrimSpace(r.FormValue("handoff")),
+		StartsOn: startsOn,
+	}
+
+	emails := strings.FieldsFunc(r.FormValue("members"), func(c rune) bool {
+		return c == ',' || c == '\n' || c == '\r' || c == ' '
+	})
+	return schedule, emails, nil
+}
+
+// memberEmails formats the rotation members for the members field, one per line
+func memberEmails(schedule *model.Schedule) string {
+	emails := make([]string, len(schedule.Members))
+	for i, member := range schedule.Members {
+		emails[i] = member.Email
+	}
+	return strings.Join(emails, "\n")
+}
+
+// List shows the user's on-call schedules and who is on call on each right
+// now, along with their on-call team and the invitations they received
+func (sh *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	schedules, err := sh.scheduleService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	now := sh.now()
+	onCall := make(map[int]string, len(schedules))
+	for _, schedule := range schedules {
+		if member, ok := schedule.OnCallAt(now); ok {
+			onCall[schedule.ID] = member.Email
+		}
+	}
+
+	invitations, err := sh.scheduleService.GetInvitations(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	invitees, err := sh.scheduleService.GetInvitees(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title":       "on-call schedules",
+		"schedules":   schedules,
+		"onCall":      onCall,
+		"invitations": invitations,
+		"invitees":    invitees,
+	}
+
+	sh.Template.List.Render(w, r, data)
+}
+
+// Show displays who is on call now and over the next two weeks, along with the overrides
+func (sh *ScheduleHandler) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	schedule, err := sh.scheduleService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	loc, err := schedule.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := sh.now()
+	var shifts []shiftRow
+	for _, shift := range schedule.Shifts(now, now.Add(upcomingPeriod)) {
+		shifts = append(shifts, shiftRow{
+			Email:    shift.Email,
+			Start:    shift.Start.In(loc).Format(displayFormat),
+			End:      shift.End.In(loc).Format(displayFormat),
+			Override: shift.Override,
+		})
+	}
+
+	var overrides []overrideRow
+	for _, override := range schedule.Overrides {
+		if override.EndsAt.Before(now) {
+			continue
+		}
+		overrides = append(overrides, overrideRow{
+			ID:    override.ID,
+			Email: override.Email,
+			Start: override.StartsAt.In(loc).Format(displayFormat),
+			End:   override.EndsAt.In(loc).Format(displayFormat),
+		})
+	}
+
+	data := map[string]any{
+		"title":     "on-call schedule",
+		"schedule":  schedule,
+		"timezone":  loc.String(),
+		"shifts":    shifts,
+		"overrides": overrides,
+	}
+	if member, ok := schedule.OnCallAt(now); ok {
+		data["onCall"] = member.Email
+	}
+
+	sh.Template.Show.Render(w, r, data)
+}
+
+func (sh *ScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data := map[string]any{
+			"title":     "add an on-call schedule",
+			"rotations": model.Rotations,
+			"startsOn":  sh.now().Format(dateFormat),
+			"members":   user.Email,
+		}
+		sh.Template.Create.Render(w, r, data)
+		return
+	}
+
+	createURL := "/app/oncall/create"
+	schedule, emails, err := parseSchedule(r)
+	if err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Invalid schedule: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := sh.scheduleService.Create(r.Context(), schedule, user.ID, emails); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to create schedule: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Schedule created successfully"})
+	http.Redirect(w, r, fmt.Sprintf("/app/oncall/%d", schedule.ID), http.StatusSeeOther)
+}
+
+func (sh *ScheduleHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/oncall/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	schedule, err := sh.scheduleService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		data := map[string]any{
+			"title":     "edit on-call schedule",
+			"schedule":  schedule,
+			"rotations": model.Rotations,
+			"startsOn":  schedule.StartsOn.Format(dateFormat),
+			"members":   memberEmails(schedule),
+		}
+		sh.Template.Edit.Render(w, r, data)
+		return
+	}
+
+	updated, emails, err := parseSchedule(r)
+	if err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Invalid schedule: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	updated.ID = schedule.ID
+
+	if _, err := sh.scheduleService.Update(r.Context(), updated, user.ID, emails); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to update schedule: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Schedule updated successfully"})
+	http.Redirect(w, r, fmt.Sprintf("/app/oncall/%d", id), http.StatusSeeOther)
+}
+
+func (sh *ScheduleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.Delete(r.Context(), id, user.ID); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to delete schedule: " + err.Error()})
+		http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Schedule deleted successfully"})
+	http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+}
+
+// AddOverride puts someone on call for a one-off period, given in the schedule's timezone
+func (sh *ScheduleHandler) AddOverride(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+	scheduleURL := fmt.Sprintf("/app/oncall/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	schedule, err := sh.scheduleService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	startsAt, startErr := time.ParseInLocation(dateTimeFormat, r.FormValue("starts_at"), loc)
+	endsAt, endErr := time.ParseInLocation(dateTimeFormat, r.FormValue("ends_at"), loc)
+	if startErr != nil || endErr != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Invalid override: start and end are required"})
+		http.Redirect(w, r, scheduleURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := sh.scheduleService.AddOverride(r.Context(), id, user.ID, r.FormValue("email"), startsAt, endsAt); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to add override: " + err.Error()})
+		http.Redirect(w, r, scheduleURL, http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Override added successfully"})
+	http.Redirect(w, r, scheduleURL, http.StatusSeeOther)
+}
+
+func (sh *ScheduleHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+	overrideID, err := parseID(r, "overrideId")
+	if err != nil {
+		http.Error(w, "Invalid override ID", http.StatusBadRequest)
+		return
+	}
+	scheduleURL := fmt.Sprintf("/app/oncall/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.DeleteOverride(r.Context(), id, overrideID, user.ID); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to delete override: " + err.Error()})
+		http.Redirect(w, r, scheduleURL, http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Override deleted successfully"})
+	http.Redirect(w, r, scheduleURL, http.StatusSeeOther)
+}
+
+// Invite asks someone to join the user's on-call team
+func (sh *ScheduleHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.Invite(r.Context(), user.ID, r.FormValue("email")); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to send invitation: " + err.Error()})
+		http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"If an account exists for that address, it can now accept your invitation from its on-call page"})
+	http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+}
+
+// AcceptInvitation joins the on-call team of the user who sent the invitation
+func (sh *ScheduleHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := parseID(r, "ownerId")
+	if err != nil {
+		http.Error(w, "Invalid owner ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.AcceptInvitation(r.Context(), ownerID, user.ID); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to accept invitation: " + err.Error()})
+		http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Invitation accepted"})
+	http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+}
+
+// DeclineInvitation declines an invitation or leaves an on-call team joined earlier
+func (sh *ScheduleHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := parseID(r, "ownerId")
+	if err != nil {
+		http.Error(w, "Invalid owner ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.DeleteInvitation(r.Context(), ownerID, user.ID); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to decline invitation: " + err.Error()})
+		http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"You are no longer on that on-call team"})
+	http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+}
+
+// RemoveInvitee takes someone off the user's on-call team
+func (sh *ScheduleHandler) RemoveInvitee(w http.ResponseWriter, r *http.Request) {
+	inviteeID, err := parseID(r, "userId")
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sh.scheduleService.DeleteInvitation(r.Context(), user.ID, inviteeID); err != nil {
+		sh.flash.SetErrors(r.Context(), []string{"Failed to remove team member: " + err.Error()})
+		http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+		return
+	}
+
+	sh.flash.SetSuccesses(r.Context(), []string{"Team member removed"})
+	http.Redirect(w, r, "/app/oncall", http.StatusSeeOther)
+}
+
+// ICal exports the upcoming shifts of a schedule as an iCalendar file
+func (sh *ScheduleHandler) ICal(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	schedule, err := sh.scheduleService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	now := sh.now()
+	shifts := schedule.Shifts(now, now.Add(calendarPeriod))
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="oncall-%d.ics"`, schedule.ID))
+	if err := model.WriteICal(w, schedule, shifts, now); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
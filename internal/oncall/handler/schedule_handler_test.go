@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockScheduleService struct {
+	createFunc             func(schedule *model.Schedule, userID int, emails []string) error
+	getFunc                func(id int, userID int) (*model.Schedule, error)
+	getByUserIDFunc        func(userID int) ([]*model.Schedule, error)
+	updateFunc             func(schedule *model.Schedule, userID int, emails []string) (*model.Schedule, error)
+	deleteFunc             func(id int, userID int) error
+	addOverrideFunc        func(scheduleID int, userID int, email string, startsAt, endsAt time.Time) error
+	deleteOverrideFunc     func(scheduleID int, overrideID int, userID int) error
+	resolveNotifierIDsFunc func(scheduleIDs []int, at time.Time) ([]int, error)
+	inviteFunc             func(ownerID int, email string) error
+	getInvitationsFunc     func(userID int) ([]model.Invitation, error)
+	getInviteesFunc        func(ownerID int) ([]model.Invitation, error)
+	acceptInvitationFunc   func(ownerID int, userID int) error
+	deleteInvitationFunc   func(ownerID int, userID int) error
+}
+
+func (m *MockScheduleService) Create(ctx context.Context, schedule *model.Schedule, userID int, emails []string) error {
+	return m.createFunc(schedule, userID, emails)
+}
+
+func (m *MockScheduleService) Get(ctx context.Context, id int, userID int) (*model.Schedule, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockScheduleService) GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *MockScheduleService) Update(ctx context.Context, schedule *model.Schedule, userID int, emails []string) (*model.Schedule, error) {
+	return m.updateFunc(schedule, userID, emails)
+}
+
+func (m *MockScheduleService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *MockScheduleService) AddOverride(ctx context.Context, scheduleID int, userID int, email string, startsAt, endsAt time.Time) error {
+	return m.addOverrideFunc(scheduleID, userID, email, startsAt, endsAt)
+}
+
+func (m *MockScheduleService) DeleteOverride(ctx context.Context, scheduleID int, overrideID int, userID int) error {
+	return m.deleteOverrideFunc(scheduleID, overrideID, userID)
+}
+
+func (m *MockScheduleService) ResolveNotifierIDs(ctx context.Context, scheduleIDs []int, at time.Time) ([]int, error) {
+	return m.resolveNotifierIDsFunc(scheduleIDs, at)
+}
+
+func (m *MockScheduleService) Invite(ctx context.Context, ownerID int, email string) error {
+	return m.inviteFunc(ownerID, email)
+}
+
+func (m *MockScheduleService) GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error) {
+	return m.getInvitationsFunc(userID)
+}
+
+func (m *MockScheduleService) GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error) {
+	return m.getInviteesFunc(ownerID)
+}
+
+func (m *MockScheduleService) AcceptInvitation(ctx context.Context, ownerID int, userID int) error {
+	return m.acceptInvitationFunc(ownerID, userID)
+}
+
+func (m *MockScheduleService) DeleteInvitation(ctx context.Context, ownerID int, userID int) error {
+	return m.deleteInvitationFunc(ownerID, userID)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID, Email: "alice@example.com"})
+	return req.WithContext(ctx)
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+var testNow = time.Date(2025, 4, 22, 12, 0, 0, 0, time.UTC)
+
+func newTestScheduleHandler(mockService *MockScheduleService) *ScheduleHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewScheduleHandler(mockService, mockFlashStore)
+	handler.now = func() time.Time { return testNow }
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:oncall/list")
+	handler.Template.Create = templateRenderer.GetTemplate("pages:oncall/create")
+	handler.Template.Edit = templateRenderer.GetTemplate("pages:oncall/edit")
+	handler.Template.Show = templateRenderer.GetTemplate("pages:oncall/show")
+	return handler
+}
+
+func ownedSchedule(id int, userID int) (*model.Schedule, error) {
+	if userID != 1 {
+		return nil, service.ErrUnauthorized
+	}
+	return &model.Schedule{
+		ID:       id,
+		UserID:   1,
+		Name:     "Primary",
+		Timezone: "Europe/Berlin",
+		Rotation: model.RotationWeekly,
+		Handoff:  "09:00",
+		StartsOn: time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC),
+		Members:  []model.Member{{UserID: 1, Email: "alice@example.com"}, {UserID: 2, Email: "bob@example.com"}},
+		Overrides: []model.Override{{
+			ID:       4,
+			UserID:   3,
+			Email:    "carol@example.com",
+			StartsAt: testNow.Add(time.Hour),
+			EndsAt:   testNow.Add(3 * time.Hour),
+		}},
+	}, nil
+}
+
+func TestScheduleHandler_List(t *testing.T) {
+	mockService := &MockScheduleService{
+		getByUserIDFunc: func(userID int) ([]*model.Schedule, error) {
+			schedule, _ := ownedSchedule(1, userID)
+			return []*model.Schedule{schedule}, nil
+		},
+		getInvitationsFunc: func(userID int) ([]model.Invitation, error) {
+			return []model.Invitation{{OwnerID: 5, OwnerEmail: "dave@example.com", UserID: userID, Email: "alice@example.com"}}, nil
+		},
+		getInviteesFunc: func(ownerID int) ([]model.Invitation, error) {
+			return []model.Invitation{{OwnerID: ownerID, UserID: 2, Email: "bob@example.com", Accepted: true}}, nil
+		},
+	}
+	handler := newTestScheduleHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/oncall", nil)
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	body := w.Body.String()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, body, "Primary")
+	assert.Contains(t, body, "alice@example.com")
+	assert.Contains(t, body, "dave@example.com")
+	assert.Contains(t, body, "/app/oncall/invitations/5/accept")
+	assert.Contains(t, body, "/app/oncall/team/2/remove")
+}
+
+func TestScheduleHandler_Show(t *testing.T) {
+	handler := newTestScheduleHandler(&MockScheduleService{getFunc: ownedSchedule})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/oncall/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Show(w, req)
+
+		body := w.Body.String()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, body, "carol@example.com")
+		assert.Contains(t, body, "bob@example.com")
+		assert.Contains(t, body, "/app/oncall/overrides/1/delete/4")
+		assert.Contains(t, body, "Europe/Berlin")
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/oncall/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Show(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestScheduleHandler_Create(t *testing.T) {
+	mockService := &MockScheduleService{}
+	handler := newTestScheduleHandler(mockService)
+
+	t.Run("GET request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/oncall/create", nil)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "2025-04-22")
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var created *model.Schedule
+		var members []string
+		mockService.createFunc = func(schedule *model.Schedule, userID int, emails []string) error {
+			created = schedule
+			members = emails
+			schedule.ID = 7
+			return nil
+		}
+
+		form := url.Values{
+			"name":      {"Primary"},
+			"members":   {"alice@example.com\r\nbob@example.com"},
+			"rotation":  {"daily"},
+			"starts_on": {"2025-04-21"},
+			"handoff":   {"09:00"},
+			"timezone":  {"Europe/Berlin"},
+		}
+		req := postForm("/app/oncall/create", form)
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/oncall/7", w.Header().Get("Location"))
+		assert.Equal(t, model.RotationDaily, created.Rotation)
+		assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, members)
+	})
+
+	t.Run("POST request - invalid date", func(t *testing.T) {
+		req := postForm("/app/oncall/create", url.Values{"name": {"Primary"}, "starts_on": {"soon"}})
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, "/app/oncall/create", w.Header().Get("Location"))
+	})
+}
+
+func TestScheduleHandler_Edit(t *testing.T) {
+	var updated *model.Schedule
+	mockService := &MockScheduleService{
+		getFunc: ownedSchedule,
+		updateFunc: func(schedule *model.Schedule, userID int, emails []string) (*model.Schedule, error) {
+			updated = schedule
+			return schedule, nil
+		},
+	}
+	handler := newTestScheduleHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/oncall/edit/1", nil)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+	handler.Edit(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "alice@example.com\nbob@example.com")
+
+	form := url.Values{
+		"name":      {"Secondary"},
+		"members":   {"bob@example.com"},
+		"rotation":  {"weekly"},
+		"starts_on": {"2025-04-21"},
+		"handoff":   {"10:00"},
+	}
+	req = postForm("/app/oncall/edit/1", form)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w = httptest.NewRecorder()
+	handler.Edit(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, 1, updated.ID)
+	assert.Equal(t, "Secondary", updated.Name)
+}
+
+func TestScheduleHandler_Overrides(t *testing.T) {
+	var startsAt time.Time
+	deleted := 0
+	mockService := &MockScheduleService{
+		getFunc: ownedSchedule,
+		addOverrideFunc: func(scheduleID int, userID int, email string, start, end time.Time) error {
+			startsAt = start
+			return nil
+		},
+		deleteOverrideFunc: func(scheduleID int, overrideID int, userID int) error {
+			deleted = overrideID
+			return nil
+		},
+	}
+	handler := newTestScheduleHandler(mockService)
+
+	form := url.Values{"email": {"carol@example.com"}, "starts_at": {"2025-04-22T18:00"}, "ends_at": {"2025-04-23T08:00"}}
+	req := postForm("/app/oncall/overrides/1", form)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+	handler.AddOverride(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	// Override times are entered in the schedule's timezone
+	assert.True(t, startsAt.Equal(time.Date(2025, 4, 22, 16, 0, 0, 0, time.UTC)))
+
+	req = httptest.NewRequest(http.MethodPost, "/app/oncall/overrides/1/delete/4", nil)
+	req.SetPathValue("id", "1")
+	req.SetPathValue("overrideId", "4")
+	req = withUser(req, 1)
+	w = httptest.NewRecorder()
+	handler.DeleteOverride(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, 4, deleted)
+}
+
+func TestScheduleHandler_ICal(t *testing.T) {
+	handler := newTestScheduleHandler(&MockScheduleService{getFunc: ownedSchedule})
+
+	req := httptest.NewRequest(http.MethodGet, "/app/oncall/calendar/1", nil)
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.ICal(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "BEGIN:VEVENT")
+	assert.Contains(t, w.Body.String(), "SUMMARY:On call: carol@example.com (override)")
+}
+
+func TestScheduleHandler_Invitations(t *testing.T) {
+	mockService := &MockScheduleService{}
+	handler := newTestScheduleHandler(mockService)
+
+	t.Run("invite", func(t *testing.T) {
+		var invited string
+		mockService.inviteFunc = func(ownerID int, email string) error {
+			invited = email
+			return nil
+		}
+
+		req := postForm("/app/oncall/invitations", url.Values{"email": {"bob@example.com"}})
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Invite(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/app/oncall", w.Header().Get("Location"))
+		assert.Equal(t, "bob@example.com", invited)
+	})
+
+	t.Run("accept", func(t *testing.T) {
+		var accepted [2]int
+		mockService.acceptInvitationFunc = func(ownerID int, userID int) error {
+			accepted = [2]int{ownerID, userID}
+			return nil
+		}
+
+		req := postForm("/app/oncall/invitations/5/accept", nil)
+		req.SetPathValue("ownerId", "5")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.AcceptInvitation(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, [2]int{5, 1}, accepted)
+	})
+
+	t.Run("decline and remove", func(t *testing.T) {
+		var deleted [][2]int
+		mockService.deleteInvitationFunc = func(ownerID int, userID int) error {
+			deleted = append(deleted, [2]int{ownerID, userID})
+			return nil
+		}
+
+		req := postForm("/app/oncall/invitations/5/decline", nil)
+		req.SetPathValue("ownerId", "5")
+		req = withUser(req, 1)
+		handler.DeclineInvitation(httptest.NewRecorder(), req)
+
+		req = postForm("/app/oncall/team/2/remove", nil)
+		req.SetPathValue("userId", "2")
+		req = withUser(req, 1)
+		handler.RemoveInvitee(httptest.NewRecorder(), req)
+
+		assert.Equal(t, [][2]int{{5, 1}, {1, 2}}, deleted)
+	})
+
+	t.Run("invalid owner ID", func(t *testing.T) {
+		req := postForm("/app/oncall/invitations/x/accept", nil)
+		req.SetPathValue("ownerId", "x")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.AcceptInvitation(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
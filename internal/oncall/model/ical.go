@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const icalTimeFormat = "20060102T150405Z"
+
+// icalEscaper escapes TEXT values as required by RFC 5545
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// WriteICal writes the shifts of a schedule as an iCalendar feed
+func WriteICal(w io.Writer, schedule *Schedule, shifts []Shift, stamp time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Uptime Bot//On-call//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icalEscaper.Replace(schedule.Name),
+	}
+
+	for _, shift := range shifts {
+		summary := "On call: " + shift.Email
+		if shift.Override {
+			summary += " (override)"
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:oncall-%d-%d-%d@uptimebot", schedule.ID, shift.UserID, shift.Start.Unix()),
+			"DTSTAMP:"+stamp.UTC().Format(icalTimeFormat),
+			"DTSTART:"+shift.Start.UTC().Format(icalTimeFormat),
+			"DTEND:"+shift.End.UTC().Format(icalTimeFormat),
+			"SUMMARY:"+icalEscaper.Replace(summary),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
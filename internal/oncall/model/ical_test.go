@@ -0,0 +1,27 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteICal(t *testing.T) {
+	s := testSchedule()
+	s.Name = "Primary, EU"
+	start := time.Date(2025, 4, 21, 7, 0, 0, 0, time.UTC)
+	shifts := []Shift{{UserID: 1, Email: "alice@example.com", Start: start, End: start.Add(7 * 24 * time.Hour)}}
+
+	var b strings.Builder
+	assert.NoError(t, WriteICal(&b, s, shifts, start))
+
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, out, `X-WR-CALNAME:Primary\, EU`)
+	assert.Contains(t, out, "DTSTART:20250421T070000Z")
+	assert.Contains(t, out, "DTEND:20250428T070000Z")
+	assert.Contains(t, out, "SUMMARY:On call: alice@example.com")
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+}
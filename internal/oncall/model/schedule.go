@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/pkg/civil"
+)
+
+// Rotation is how often the on-call duty passes to the next member
+type Rotation string
+
+const (
+	RotationDaily  Rotation = "daily"
+	RotationWeekly Rotation = "weekly"
+)
+
+// Rotations lists the supported rotations, in the order forms offer them
+var Rotations = []Rotation{RotationWeekly, RotationDaily}
+
+// Member is a user taking part in a rotation
+type Member struct {
+	UserID int    `db:"user_id"`
+	Email  string `db:"email"`
+}
+
+// Override puts a user on call for a one-off period, regardless of the rotation
+type Override struct {
+	ID       int       `db:"id"`
+	UserID   int       `db:"user_id"`
+	Email    string    `db:"email"`
+	StartsAt time.Time `db:"starts_at"`
+	EndsAt   time.Time `db:"ends_at"`
+}
+
+// Invitation asks a user to join the on-call team of a schedule owner. Once
+// accepted, the owner can put them on rotations and overrides.
+type Invitation struct {
+	OwnerID    int    `db:"owner_id"`
+	OwnerEmail string `db:"owner_email"`
+	UserID     int    `db:"user_id"`
+	Email      string `db:"email"`
+	Accepted   bool   `db:"accepted"`
+}
+
+// Schedule rotates on-call duty across its members. The rotation starts with
+// the first member on StartsOn at the Handoff time, in the schedule's timezone.
+type Schedule struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	Name      string    `db:"name"`
+	Timezone  string    `db:"timezone"`
+	Rotation  Rotation  `db:"rotation"`
+	Handoff   string    `db:"handoff"` // "15:04"
+	StartsOn  time.Time `db:"starts_on"`
+	Members   []Member
+	Overrides []Override
+}
+
+// Shift is a period during which a single user is on call
+type Shift struct {
+	UserID   int
+	Email    string
+	Start    time.Time
+	End      time.Time
+	Override bool
+}
+
+// Validate checks the schedule's name, timezone, rotation, handoff time and members
+func (s *Schedule) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	switch s.Rotation {
+	case RotationDaily, RotationWeekly:
+	default:
+		return fmt.Errorf("unsupported rotation: %s", s.Rotation)
+	}
+	if _, err := s.handoff(); err != nil {
+		return err
+	}
+	if s.StartsOn.IsZero() {
+		return fmt.Errorf("start date is required")
+	}
+	if len(s.Members) == 0 {
+		return fmt.Errorf("at least one member is required")
+	}
+	return nil
+}
+
+// Location returns the schedule's timezone, defaulting to UTC
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", s.Timezone)
+	}
+	return loc, nil
+}
+
+// handoff returns the handoff time as minutes after midnight
+func (s *Schedule) handoff() (int, error) {
+	t, err := time.Parse("15:04", s.Handoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid handoff time %q, expected HH:MM", s.Handoff)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// OnCallAt returns the member on call at t. Overrides take precedence over the rotation.
+func (s *Schedule) OnCallAt(t time.Time) (Member, bool) {
+	for _, o := range s.Overrides {
+		if !t.Before(o.StartsAt) && t.Before(o.EndsAt) {
+			return Member{UserID: o.UserID, Email: o.Email}, true
+		}
+	}
+
+	if len(s.Members) == 0 {
+		return Member{}, false
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return Member{}, false
+	}
+	handoff, err := s.handoff()
+	if err != nil {
+		return Member{}, false
+	}
+
+	// Count the handoffs since the rotation started on calendar days, so
+	// DST changes don't shift the handoff time
+	local := t.In(loc)
+	day := civil.Day(local)
+	if local.Hour()*60+local.Minute() < handoff {
+		day--
+	}
+	days := day - civil.Day(s.StartsOn)
+
+	period := 1
+	if s.Rotation == RotationWeekly {
+		period = 7
+	}
+	index := floorDiv(days, period) % len(s.Members)
+	if index < 0 {
+		index += len(s.Members)
+	}
+	return s.Members[index], true
+}
+
+// Shifts returns who is on call between from and to, as consecutive shifts
+func (s *Schedule) Shifts(from, to time.Time) []Shift {
+	if !from.Before(to) {
+		return nil
+	}
+
+	// Duty can only change at a handoff or at the edge of an override, so
+	// the timeline is resolved at each of those instants and then merged
+	boundaries := []time.Time{from}
+	if loc, err := s.Location(); err == nil {
+		if handoff, err := s.handoff(); err == nil {
+			start := from.In(loc)
+			day := time.Date(start.Year(), start.Month(), start.Day()-1, handoff/60, handoff%60, 0, 0, loc)
+			for ; day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, handoff/60, handoff%60, 0, 0, loc) {
+				if day.After(from) {
+					boundaries = append(boundaries, day)
+				}
+			}
+		}
+	}
+	for _, o := range s.Overrides {
+		for _, b := range []time.Time{o.StartsAt, o.EndsAt} {
+			if b.After(from) && b.Before(to) {
+				boundaries = append(boundaries, b)
+			}
+		}
+	}
+	slices.SortFunc(boundaries, time.Time.Compare)
+
+	var shifts []Shift
+	for i, start := range boundaries {
+		end := to
+		if i+1 < len(boundaries) {
+			end = boundaries[i+1]
+		}
+		if !start.Before(end) {
+			continue
+		}
+
+		member, ok := s.OnCallAt(start)
+		if !ok {
+			continue
+		}
+		override := s.overridden(start)
+
+		if n := len(shifts); n > 0 && shifts[n-1].UserID == member.UserID &&
+			shifts[n-1].Override == override && shifts[n-1].End.Equal(start) {
+			shifts[n-1].End = end
+			continue
+		}
+		shifts = append(shifts, Shift{
+			UserID:   member.UserID,
+			Email:    member.Email,
+			Start:    start,
+			End:      end,
+			Override: override,
+		})
+	}
+	return shifts
+}
+
+func (s *Schedule) overridden(t time.Time) bool {
+	for _, o := range s.Overrides {
+		if !t.Before(o.StartsAt) && t.Before(o.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
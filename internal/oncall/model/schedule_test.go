@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchedule() *Schedule {
+	return &Schedule{
+		ID:       1,
+		Name:     "Primary",
+		Timezone: "Europe/Berlin",
+		Rotation: RotationWeekly,
+		Handoff:  "09:00",
+		StartsOn: time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC), // a Monday
+		Members: []Member{
+			{UserID: 1, Email: "alice@example.com"},
+			{UserID: 2, Email: "bob@example.com"},
+		},
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	assert.NoError(t, testSchedule().Validate())
+
+	invalid := []func(s *Schedule){
+		func(s *Schedule) { s.Name = " " },
+		func(s *Schedule) { s.Timezone = "Mars/Olympus" },
+		func(s *Schedule) { s.Rotation = "monthly" },
+		func(s *Schedule) { s.Handoff = "9am" },
+		func(s *Schedule) { s.StartsOn = time.Time{} },
+		func(s *Schedule) { s.Members = nil },
+	}
+	for _, mutate := range invalid {
+		s := testSchedule()
+		mutate(s)
+		assert.Error(t, s.Validate())
+	}
+}
+
+func TestSchedule_OnCallAt(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	s := testSchedule()
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{"first handoff", time.Date(2025, 4, 21, 9, 0, 0, 0, berlin), 1},
+		{"before first handoff wraps around", time.Date(2025, 4, 21, 8, 59, 0, 0, berlin), 2},
+		{"end of first week", time.Date(2025, 4, 28, 8, 59, 0, 0, berlin), 1},
+		{"second week", time.Date(2025, 4, 28, 9, 0, 0, 0, berlin), 2},
+		{"third week", time.Date(2025, 5, 5, 12, 0, 0, 0, berlin), 1},
+		{"across DST change", time.Date(2025, 10, 27, 9, 0, 0, 0, berlin), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, ok := s.OnCallAt(tt.at)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, member.UserID)
+		})
+	}
+
+	t.Run("daily", func(t *testing.T) {
+		s := testSchedule()
+		s.Rotation = RotationDaily
+		member, _ := s.OnCallAt(time.Date(2025, 4, 22, 10, 0, 0, 0, berlin))
+		assert.Equal(t, 2, member.UserID)
+		member, _ = s.OnCallAt(time.Date(2025, 4, 23, 10, 0, 0, 0, berlin))
+		assert.Equal(t, 1, member.UserID)
+	})
+
+	t.Run("override wins", func(t *testing.T) {
+		s := testSchedule()
+		s.Overrides = []Override{{
+			UserID:   3,
+			Email:    "carol@example.com",
+			StartsAt: time.Date(2025, 4, 22, 18, 0, 0, 0, berlin),
+			EndsAt:   time.Date(2025, 4, 23, 8, 0, 0, 0, berlin),
+		}}
+		member, _ := s.OnCallAt(time.Date(2025, 4, 22, 20, 0, 0, 0, berlin))
+		assert.Equal(t, 3, member.UserID)
+		member, _ = s.OnCallAt(time.Date(2025, 4, 23, 8, 0, 0, 0, berlin))
+		assert.Equal(t, 1, member.UserID)
+	})
+
+	t.Run("no members", func(t *testing.T) {
+		s := testSchedule()
+		s.Members = nil
+		_, ok := s.OnCallAt(time.Now())
+		assert.False(t, ok)
+	})
+}
+
+func TestSchedule_Shifts(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	s := testSchedule()
+	s.Overrides = []Override{{
+		UserID:   3,
+		Email:    "carol@example.com",
+		StartsAt: time.Date(2025, 4, 23, 18, 0, 0, 0, berlin),
+		EndsAt:   time.Date(2025, 4, 24, 8, 0, 0, 0, berlin),
+	}}
+
+	from := time.Date(2025, 4, 22, 12, 0, 0, 0, berlin)
+	shifts := s.Shifts(from, from.Add(14*24*time.Hour))
+
+	assert.Len(t, shifts, 5)
+	assert.Equal(t, []int{1, 3, 1, 2, 1}, []int{shifts[0].UserID, shifts[1].UserID, shifts[2].UserID, shifts[3].UserID, shifts[4].UserID})
+	assert.True(t, shifts[1].Override)
+	assert.Equal(t, from, shifts[0].Start)
+	assert.True(t, shifts[3].Start.Equal(time.Date(2025, 4, 28, 9, 0, 0, 0, berlin)))
+	assert.True(t, shifts[4].End.Equal(from.Add(14*24*time.Hour)))
+
+	for i := 1; i < len(shifts); i++ {
+		assert.True(t, shifts[i-1].End.Equal(shifts[i].Start))
+	}
+}
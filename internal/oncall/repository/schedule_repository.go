@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+)
+
+var (
+	ErrScheduleNotFound   = errors.New("on-call schedule not found")
+	ErrOverrideNotFound   = errors.New("on-call override not found")
+	ErrInvitationNotFound = errors.New("on-call invitation not found")
+)
+
+type ScheduleRepositoryInterface interface {
+	Create(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error)
+	Get(ctx context.Context, id int) (*model.Schedule, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error)
+	Update(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error)
+	Delete(ctx context.Context, id int) error
+	AddOverride(ctx context.Context, scheduleID int, override *model.Override) (*model.Override, error)
+	DeleteOverride(ctx context.Context, scheduleID int, overrideID int) error
+	GetMembersByEmail(ctx context.Context, ownerID int, emails []string) ([]model.Member, error)
+	Invite(ctx context.Context, ownerID int, email string) error
+	GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error)
+	GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error)
+	AcceptInvitation(ctx context.Context, ownerID int, userID int) error
+	DeleteInvitation(ctx context.Context, ownerID int, userID int) error
+}
+
+var _ ScheduleRepositoryInterface = (*ScheduleRepository)(nil)
+
+// ScheduleRepository handles database operations for on-call schedules
+type ScheduleRepository struct {
+	db database.Querier
+}
+
+// NewScheduleRepository creates a new on-call schedule repository
+func NewScheduleRepository(db database.Querier) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Create inserts a schedule along with its members
+func (r *ScheduleRepository) Create(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO oncall_schedule (user_id, name, timezone, rotation, handoff, starts_on)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	newSchedule := *schedule
+	err := r.db.QueryRowContext(ctx,
+		query,
+		schedule.UserID,
+		schedule.Name,
+		schedule.Timezone,
+		schedule.Rotation,
+		schedule.Handoff,
+		schedule.StartsOn,
+	).Scan(&newSchedule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create on-call schedule: %w", err)
+	}
+
+	if err := r.insertMembers(ctx, newSchedule.ID, newSchedule.Members); err != nil {
+		return nil, err
+	}
+
+	return &newSchedule, nil
+}
+
+// insertMembers stores the rotation members in order
+func (r *ScheduleRepository) insertMembers(ctx context.Context, scheduleID int, members []model.Member) error {
+	query := `INSERT INTO oncall_member (schedule_id, user_id, position) VALUES ($1, $2, $3)`
+
+	for i, member := range members {
+		if _, err := r.db.ExecContext(ctx, query, scheduleID, member.UserID, i); err != nil {
+			return fmt.Errorf("failed to add on-call member: %w", err)
+		}
+	}
+	return nil
+}
+
+// onTeam keeps the rows of users other than the owner in $2 unless they
+// accepted the owner's invitation
+const onTeam = `(u.id = $2 OR EXISTS (
+	SELECT 1 FROM oncall_invitation i WHERE i.owner_id = $2 AND i.user_id = u.id AND i.accepted
+))`
+
+// load fills in the members and overrides of a schedule. People who left the
+// owner's on-call team are left out.
+func (r *ScheduleRepository) load(ctx context.Context, schedule *model.Schedule) error {
+	query := `
+		SELECT m.user_id, u.email
+		FROM oncall_member m
+		JOIN usr u ON u.id = m.user_id
+		WHERE m.schedule_id = $1 AND ` + onTeam + `
+		ORDER BY m.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, schedule.ID, schedule.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to query on-call members: %w", err)
+	}
+	defer rows.Close()
+
+	schedule.Members = nil
+	for rows.Next() {
+		var member model.Member
+		if err := rows.Scan(&member.UserID, &member.Email); err != nil {
+			return fmt.Errorf("failed to scan on-call member: %w", err)
+		}
+		schedule.Members = append(schedule.Members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating on-call members: %w", err)
+	}
+
+	query = `
+		SELECT o.id, o.user_id, u.email, o.starts_at, o.ends_at
+		FROM oncall_override o
+		JOIN usr u ON u.id = o.user_id
+		WHERE o.schedule_id = $1 AND ` + onTeam + `
+		ORDER BY o.starts_at
+	`
+
+	rows, err = r.db.QueryContext(ctx, query, schedule.ID, schedule.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to query on-call overrides: %w", err)
+	}
+	defer rows.Close()
+
+	schedule.Overrides = nil
+	for rows.Next() {
+		var override model.Override
+		if err := rows.Scan(&override.ID, &override.UserID, &override.Email, &override.StartsAt, &override.EndsAt); err != nil {
+			return fmt.Errorf("failed to scan on-call override: %w", err)
+		}
+		schedule.Overrides = append(schedule.Overrides, override)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating on-call overrides: %w", err)
+	}
+
+	return nil
+}
+
+const scheduleColumns = `id, user_id, name, timezone, rotation, handoff, starts_on`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchedule(row rowScanner) (*model.Schedule, error) {
+	schedule := &model.Schedule{}
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.UserID,
+		&schedule.Name,
+		&schedule.Timezone,
+		&schedule.Rotation,
+		&schedule.Handoff,
+		&schedule.StartsOn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Get retrieves a schedule with its members and overrides by ID
+func (r *ScheduleRepository) Get(ctx context.Context, id int) (*model.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM oncall_schedule WHERE id = $1`
+
+	schedule, err := scanSchedule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get on-call schedule: %w", err)
+	}
+
+	if err := r.load(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetByUserID retrieves all schedules owned by a user, with their members and overrides
+func (r *ScheduleRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM oncall_schedule WHERE user_id = $1 ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-call schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*model.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan on-call schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating on-call schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		if err := r.load(ctx, schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	return schedules, nil
+}
+
+// Update changes a schedule's settings and replaces its members; overrides are kept
+func (r *ScheduleRepository) Update(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE oncall_schedule
+		SET name = $1, timezone = $2, rotation = $3, handoff = $4, starts_on = $5
+		WHERE id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		schedule.Name,
+		schedule.Timezone,
+		schedule.Rotation,
+		schedule.Handoff,
+		schedule.StartsOn,
+		schedule.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update on-call schedule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil, ErrScheduleNotFound
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oncall_member WHERE schedule_id = $1`, schedule.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete on-call members: %w", err)
+	}
+	if err := r.insertMembers(ctx, schedule.ID, schedule.Members); err != nil {
+		return nil, err
+	}
+
+	updated := *schedule
+	return &updated, nil
+}
+
+// Delete removes a schedule along with its members and overrides
+func (r *ScheduleRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM oncall_schedule WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call schedule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// AddOverride puts a user on call for a one-off period
+func (r *ScheduleRepository) AddOverride(ctx context.Context, scheduleID int, override *model.Override) (*model.Override, error) {
+	query := `
+		INSERT INTO oncall_override (schedule_id, user_id, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	newOverride := *override
+	newOverride.StartsAt = override.StartsAt.UTC()
+	newOverride.EndsAt = override.EndsAt.UTC()
+	err := r.db.QueryRowContext(ctx, query, scheduleID, override.UserID, newOverride.StartsAt, newOverride.EndsAt).Scan(&newOverride.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create on-call override: %w", err)
+	}
+
+	return &newOverride, nil
+}
+
+// DeleteOverride removes an override of the schedule
+func (r *ScheduleRepository) DeleteOverride(ctx context.Context, scheduleID int, overrideID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM oncall_override WHERE id = $1 AND schedule_id = $2`, overrideID, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call override: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
+
+// GetMembersByEmail looks up the users with the given email addresses who can
+// go on the owner's rotations: the owner and whoever accepted their
+// invitation. Other addresses are left out.
+func (r *ScheduleRepository) GetMembersByEmail(ctx context.Context, ownerID int, emails []string) ([]model.Member, error) {
+	query := `SELECT u.id, u.email FROM usr u WHERE u.email = ANY($1) AND ` + onTeam
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(emails), ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var members []model.Member
+	for rows.Next() {
+		var member model.Member
+		if err := rows.Scan(&member.UserID, &member.Email); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		members = append(members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return members, nil
+}
+
+// Invite asks the user with the given email to join the owner's on-call team.
+// Nothing happens for an address without an account, the owner's own address
+// or a user already invited.
+func (r *ScheduleRepository) Invite(ctx context.Context, ownerID int, email string) error {
+	query := `
+		INSERT INTO oncall_invitation (owner_id, user_id)
+		SELECT $1, id FROM usr WHERE email = $2 AND id <> $1
+		ON CONFLICT (owner_id, user_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, ownerID, email); err != nil {
+		return fmt.Errorf("failed to create on-call invitation: %w", err)
+	}
+	return nil
+}
+
+const invitationQuery = `
+	SELECT i.owner_id, o.email, i.user_id, u.email, i.accepted
+	FROM oncall_invitation i
+	JOIN usr o ON o.id = i.owner_id
+	JOIN usr u ON u.id = i.user_id
+`
+
+// GetInvitations retrieves the invitations a user received
+func (r *ScheduleRepository) GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error) {
+	return r.queryInvitations(ctx, invitationQuery+` WHERE i.user_id = $1 ORDER BY o.email`, userID)
+}
+
+// GetInvitees retrieves the invitations an owner sent
+func (r *ScheduleRepository) GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error) {
+	return r.queryInvitations(ctx, invitationQuery+` WHERE i.owner_id = $1 ORDER BY u.email`, ownerID)
+}
+
+func (r *ScheduleRepository) queryInvitations(ctx context.Context, query string, id int) ([]model.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-call invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []model.Invitation
+	for rows.Next() {
+		var invitation model.Invitation
+		if err := rows.Scan(&invitation.OwnerID, &invitation.OwnerEmail, &invitation.UserID, &invitation.Email, &invitation.Accepted); err != nil {
+			return nil, fmt.Errorf("failed to scan on-call invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating on-call invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation lets the owner put the user on their rotations
+func (r *ScheduleRepository) AcceptInvitation(ctx context.Context, ownerID int, userID int) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE oncall_invitation SET accepted = TRUE WHERE owner_id = $1 AND user_id = $2`, ownerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to accept on-call invitation: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// DeleteInvitation takes the user off the owner's on-call team, dropping
+// them from the owner's rotations and overrides
+func (r *ScheduleRepository) DeleteInvitation(ctx context.Context, ownerID int, userID int) error {
+	return database.WithTx(ctx, r.db, func(tx database.Querier) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM oncall_invitation WHERE owner_id = $1 AND user_id = $2`, ownerID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to delete on-call invitation: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return ErrInvitationNotFound
+		}
+
+		owned := `schedule_id IN (SELECT id FROM oncall_schedule WHERE user_id = $1) AND user_id = $2`
+		if _, err := tx.ExecContext(ctx, `DELETE FROM oncall_member WHERE `+owned, ownerID, userID); err != nil {
+			return fmt.Errorf("failed to delete on-call members: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM oncall_override WHERE `+owned, ownerID, userID); err != nil {
+			return fmt.Errorf("failed to delete on-call overrides: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestUser(t *testing.T, tx *sql.Tx, email string) *authModel.User {
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{
+		Email:    email,
+		Password: "hashedpassword",
+	})
+	assert.NoError(t, err)
+	return user
+}
+
+func TestScheduleRepository_CRUD(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewScheduleRepository(tx)
+	alice := createTestUser(t, tx, "alice@example.com")
+	bob := createTestUser(t, tx, "bob@example.com")
+
+	assert.NoError(t, repo.Invite(context.Background(), alice.ID, bob.Email))
+	assert.NoError(t, repo.AcceptInvitation(context.Background(), alice.ID, bob.ID))
+
+	members, err := repo.GetMembersByEmail(context.Background(), alice.ID, []string{"bob@example.com", "alice@example.com", "nobody@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	schedule, err := repo.Create(context.Background(), &model.Schedule{
+		UserID:   alice.ID,
+		Name:     "Primary",
+		Timezone: "Europe/Berlin",
+		Rotation: model.RotationWeekly,
+		Handoff:  "09:00",
+		StartsOn: time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC),
+		Members:  []model.Member{{UserID: alice.ID}, {UserID: bob.ID}},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, schedule.ID)
+
+	override, err := repo.AddOverride(context.Background(), schedule.ID, &model.Override{
+		UserID:   bob.ID,
+		StartsAt: time.Date(2025, 4, 22, 18, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2025, 4, 23, 8, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.Get(context.Background(), schedule.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Primary", fetched.Name)
+	assert.Equal(t, []model.Member{{UserID: alice.ID, Email: "alice@example.com"}, {UserID: bob.ID, Email: "bob@example.com"}}, fetched.Members)
+	assert.Len(t, fetched.Overrides, 1)
+	assert.Equal(t, "bob@example.com", fetched.Overrides[0].Email)
+	assert.Equal(t, 2025, fetched.StartsOn.Year())
+
+	fetched.Rotation = model.RotationDaily
+	fetched.Members = fetched.Members[1:]
+	_, err = repo.Update(context.Background(), fetched)
+	assert.NoError(t, err)
+
+	schedules, err := repo.GetByUserID(context.Background(), alice.ID)
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	assert.Equal(t, model.RotationDaily, schedules[0].Rotation)
+	assert.Len(t, schedules[0].Members, 1)
+	assert.Len(t, schedules[0].Overrides, 1)
+
+	assert.ErrorIs(t, repo.DeleteOverride(context.Background(), schedule.ID+1, override.ID), ErrOverrideNotFound)
+	assert.NoError(t, repo.DeleteOverride(context.Background(), schedule.ID, override.ID))
+
+	assert.NoError(t, repo.Delete(context.Background(), schedule.ID))
+	_, err = repo.Get(context.Background(), schedule.ID)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+}
+
+func TestScheduleRepository_Invitations(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewScheduleRepository(tx)
+	alice := createTestUser(t, tx, "alice@example.com")
+	bob := createTestUser(t, tx, "bob@example.com")
+
+	// Inviting an unknown address or yourself does nothing
+	assert.NoError(t, repo.Invite(context.Background(), alice.ID, "nobody@example.com"))
+	assert.NoError(t, repo.Invite(context.Background(), alice.ID, alice.Email))
+	assert.NoError(t, repo.Invite(context.Background(), alice.ID, bob.Email))
+	assert.NoError(t, repo.Invite(context.Background(), alice.ID, bob.Email))
+
+	invitees, err := repo.GetInvitees(context.Background(), alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Invitation{{OwnerID: alice.ID, OwnerEmail: alice.Email, UserID: bob.ID, Email: bob.Email}}, invitees)
+
+	// Until bob accepts, alice cannot put bob on a rotation
+	members, err := repo.GetMembersByEmail(context.Background(), alice.ID, []string{bob.Email})
+	assert.NoError(t, err)
+	assert.Empty(t, members)
+
+	assert.ErrorIs(t, repo.AcceptInvitation(context.Background(), bob.ID, alice.ID), ErrInvitationNotFound)
+	assert.NoError(t, repo.AcceptInvitation(context.Background(), alice.ID, bob.ID))
+
+	invitations, err := repo.GetInvitations(context.Background(), bob.ID)
+	assert.NoError(t, err)
+	assert.Len(t, invitations, 1)
+	assert.True(t, invitations[0].Accepted)
+
+	schedule, err := repo.Create(context.Background(), &model.Schedule{
+		UserID:   alice.ID,
+		Name:     "Primary",
+		Rotation: model.RotationWeekly,
+		Handoff:  "09:00",
+		StartsOn: time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC),
+		Members:  []model.Member{{UserID: alice.ID}, {UserID: bob.ID}},
+	})
+	assert.NoError(t, err)
+
+	// Leaving the team takes bob off the rotation
+	assert.NoError(t, repo.DeleteInvitation(context.Background(), alice.ID, bob.ID))
+	assert.ErrorIs(t, repo.DeleteInvitation(context.Background(), alice.ID, bob.ID), ErrInvitationNotFound)
+
+	fetched, err := repo.Get(context.Background(), schedule.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Member{{UserID: alice.ID, Email: alice.Email}}, fetched.Members)
+}
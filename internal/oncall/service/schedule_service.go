@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/repository"
+)
+
+// Common errors returned by the on-call schedule service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a schedule they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to on-call schedule")
+	// ErrScheduleNotFound is returned when the requested schedule or override does not exist.
+	ErrScheduleNotFound = errors.New("on-call schedule not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+	// ErrInvitationNotFound is returned when the requested on-call invitation does not exist.
+	ErrInvitationNotFound = errors.New("on-call invitation not found")
+)
+
+// NotifierService is the part of the notifier service that on-call resolution relies on
+type NotifierService interface {
+	GetByUserID(ctx context.Context, userID int) ([]*notifModel.Notifier, error)
+}
+
+type ScheduleServiceInterface interface {
+	Create(ctx context.Context, schedule *model.Schedule, userID int, emails []string) error
+	Get(ctx context.Context, id int, userID int) (*model.Schedule, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error)
+	Update(ctx context.Context, schedule *model.Schedule, userID int, emails []string) (*model.Schedule, error)
+	Delete(ctx context.Context, id int, userID int) error
+	AddOverride(ctx context.Context, scheduleID int, userID int, email string, startsAt, endsAt time.Time) error
+	DeleteOverride(ctx context.Context, scheduleID int, overrideID int, userID int) error
+	ResolveNotifierIDs(ctx context.Context, scheduleIDs []int, at time.Time) ([]int, error)
+	Invite(ctx context.Context, ownerID int, email string) error
+	GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error)
+	GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error)
+	AcceptInvitation(ctx context.Context, ownerID int, userID int) error
+	DeleteInvitation(ctx context.Context, ownerID int, userID int) error
+}
+
+var _ ScheduleServiceInterface = (*ScheduleService)(nil)
+
+type ScheduleService struct {
+	repo            repository.ScheduleRepositoryInterface
+	notifierService NotifierService
+}
+
+func NewScheduleService(repo repository.ScheduleRepositoryInterface, notifierService NotifierService) *ScheduleService {
+	return &ScheduleService{
+		repo:            repo,
+		notifierService: notifierService,
+	}
+}
+
+// normalizeEmail trims and lowercases an email address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// members turns email addresses into rotation members, keeping their order.
+// Only the owner and users who accepted the owner's invitation can be members;
+// the error for anyone else does not tell whether the address has an account.
+func (s *ScheduleService) members(ctx context.Context, ownerID int, emails []string) ([]model.Member, error) {
+	var normalized []string
+	for _, email := range emails {
+		if email = normalizeEmail(email); email != "" {
+			normalized = append(normalized, email)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one member is required", ErrInvalidInput)
+	}
+
+	users, err := s.repo.GetMembersByEmail(ctx, ownerID, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up members: %w", err)
+	}
+	byEmail := make(map[string]model.Member, len(users))
+	for _, user := range users {
+		byEmail[strings.ToLower(user.Email)] = user
+	}
+
+	members := make([]model.Member, 0, len(normalized))
+	for _, email := range normalized {
+		member, ok := byEmail[email]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not on your on-call team, invite them first", ErrInvalidInput, email)
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (s *ScheduleService) Create(ctx context.Context, schedule *model.Schedule, userID int, emails []string) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	members, err := s.members(ctx, userID, emails)
+	if err != nil {
+		return err
+	}
+	schedule.UserID = userID
+	schedule.Members = members
+
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	newSchedule, err := s.repo.Create(ctx, schedule)
+	if err != nil {
+		return fmt.Errorf("failed to create on-call schedule: %w", err)
+	}
+	schedule.ID = newSchedule.ID
+	return nil
+}
+
+// Get retrieves a schedule after verifying the user owns it
+func (s *ScheduleService) Get(ctx context.Context, id int, userID int) (*model.Schedule, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	schedule, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduleNotFound) {
+			return nil, fmt.Errorf("%w: schedule with id %d not found", ErrScheduleNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get on-call schedule: %w", err)
+	}
+
+	if schedule.UserID != userID {
+		return nil, fmt.Errorf("%w: user %d does not own schedule %d", ErrUnauthorized, userID, id)
+	}
+	return schedule, nil
+}
+
+func (s *ScheduleService) GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	schedules, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get on-call schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Update changes the settings and members of a schedule the user owns
+func (s *ScheduleService) Update(ctx context.Context, schedule *model.Schedule, userID int, emails []string) (*model.Schedule, error) {
+	existing, err := s.Get(ctx, schedule.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.members(ctx, userID, emails)
+	if err != nil {
+		return nil, err
+	}
+	schedule.UserID = existing.UserID
+	schedule.Members = members
+
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	updated, err := s.repo.Update(ctx, schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update on-call schedule: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *ScheduleService) Delete(ctx context.Context, id int, userID int) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete on-call schedule: %w", err)
+	}
+	return nil
+}
+
+// AddOverride puts the user with the given email on call for a one-off period
+func (s *ScheduleService) AddOverride(ctx context.Context, scheduleID int, userID int, email string, startsAt, endsAt time.Time) error {
+	if _, err := s.Get(ctx, scheduleID, userID); err != nil {
+		return err
+	}
+	if !endsAt.After(startsAt) {
+		return fmt.Errorf("%w: override must end after it starts", ErrInvalidInput)
+	}
+
+	members, err := s.members(ctx, userID, []string{email})
+	if err != nil {
+		return err
+	}
+
+	override := &model.Override{UserID: members[0].UserID, StartsAt: startsAt, EndsAt: endsAt}
+	if _, err := s.repo.AddOverride(ctx, scheduleID, override); err != nil {
+		return fmt.Errorf("failed to add on-call override: %w", err)
+	}
+	return nil
+}
+
+func (s *ScheduleService) DeleteOverride(ctx context.Context, scheduleID int, overrideID int, userID int) error {
+	if _, err := s.Get(ctx, scheduleID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteOverride(ctx, scheduleID, overrideID); err != nil {
+		if errors.Is(err, repository.ErrOverrideNotFound) {
+			return fmt.Errorf("%w: override with id %d not found", ErrScheduleNotFound, overrideID)
+		}
+		return fmt.Errorf("failed to delete on-call override: %w", err)
+	}
+	return nil
+}
+
+// ResolveNotifierIDs returns the personal notifiers of whoever is on call at
+// the given time on each schedule. Schedules that no longer exist are skipped.
+func (s *ScheduleService) ResolveNotifierIDs(ctx context.Context, scheduleIDs []int, at time.Time) ([]int, error) {
+	var ids []int
+	var errs []error
+	for _, scheduleID := range scheduleIDs {
+		schedule, err := s.repo.Get(ctx, scheduleID)
+		if errors.Is(err, repository.ErrScheduleNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		member, ok := schedule.OnCallAt(at)
+		if !ok {
+			continue
+		}
+
+		notifiers, err := s.notifierService.GetByUserID(ctx, member.UserID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get notifiers of user %d: %w", member.UserID, err))
+			continue
+		}
+		for _, notifier := range notifiers {
+			ids = append(ids, notifier.ID)
+		}
+	}
+
+	return ids, errors.Join(errs...)
+}
+
+// Invite asks the user with the given email to join the owner's on-call team.
+// It succeeds whether or not the address has an account, so it cannot be used
+// to find out who has one.
+func (s *ScheduleService) Invite(ctx context.Context, ownerID int, email string) error {
+	if ownerID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+	if email = normalizeEmail(email); email == "" {
+		return fmt.Errorf("%w: email is required", ErrInvalidInput)
+	}
+
+	if err := s.repo.Invite(ctx, ownerID, email); err != nil {
+		return fmt.Errorf("failed to invite on-call member: %w", err)
+	}
+	return nil
+}
+
+// GetInvitations retrieves the invitations a user received
+func (s *ScheduleService) GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error) {
+	invitations, err := s.repo.GetInvitations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get on-call invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// GetInvitees retrieves the invitations an owner sent
+func (s *ScheduleService) GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error) {
+	invitations, err := s.repo.GetInvitees(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get on-call invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation lets the owner put the invited user on their rotations
+func (s *ScheduleService) AcceptInvitation(ctx context.Context, ownerID int, userID int) error {
+	if err := s.repo.AcceptInvitation(ctx, ownerID, userID); err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return fmt.Errorf("%w: no invitation from user %d", ErrInvitationNotFound, ownerID)
+		}
+		return fmt.Errorf("failed to accept on-call invitation: %w", err)
+	}
+	return nil
+}
+
+// DeleteInvitation takes the user off the owner's on-call team, whether the
+// owner removes them or they decline or leave
+func (s *ScheduleService) DeleteInvitation(ctx context.Context, ownerID int, userID int) error {
+	if err := s.repo.DeleteInvitation(ctx, ownerID, userID); err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return fmt.Errorf("%w: user %d is not invited by user %d", ErrInvitationNotFound, userID, ownerID)
+		}
+		return fmt.Errorf("failed to delete on-call invitation: %w", err)
+	}
+	return nil
+}
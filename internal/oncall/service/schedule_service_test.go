@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/model"
+	"github.com/shuvo-paul/uptimebot/internal/oncall/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockScheduleRepository struct {
+	createFunc            func(schedule *model.Schedule) (*model.Schedule, error)
+	getFunc               func(id int) (*model.Schedule, error)
+	getByUserIDFunc       func(userID int) ([]*model.Schedule, error)
+	updateFunc            func(schedule *model.Schedule) (*model.Schedule, error)
+	deleteFunc            func(id int) error
+	addOverrideFunc       func(scheduleID int, override *model.Override) (*model.Override, error)
+	deleteOverrideFunc    func(scheduleID int, overrideID int) error
+	getMembersByEmailFunc func(ownerID int, emails []string) ([]model.Member, error)
+	inviteFunc            func(ownerID int, email string) error
+	getInvitationsFunc    func(userID int) ([]model.Invitation, error)
+	getInviteesFunc       func(ownerID int) ([]model.Invitation, error)
+	acceptInvitationFunc  func(ownerID int, userID int) error
+	deleteInvitationFunc  func(ownerID int, userID int) error
+}
+
+func (m *mockScheduleRepository) Create(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error) {
+	return m.createFunc(schedule)
+}
+
+func (m *mockScheduleRepository) Get(ctx context.Context, id int) (*model.Schedule, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockScheduleRepository) GetByUserID(ctx context.Context, userID int) ([]*model.Schedule, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockScheduleRepository) Update(ctx context.Context, schedule *model.Schedule) (*model.Schedule, error) {
+	return m.updateFunc(schedule)
+}
+
+func (m *mockScheduleRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockScheduleRepository) AddOverride(ctx context.Context, scheduleID int, override *model.Override) (*model.Override, error) {
+	return m.addOverrideFunc(scheduleID, override)
+}
+
+func (m *mockScheduleRepository) DeleteOverride(ctx context.Context, scheduleID int, overrideID int) error {
+	return m.deleteOverrideFunc(scheduleID, overrideID)
+}
+
+func (m *mockScheduleRepository) GetMembersByEmail(ctx context.Context, ownerID int, emails []string) ([]model.Member, error) {
+	return m.getMembersByEmailFunc(ownerID, emails)
+}
+
+func (m *mockScheduleRepository) Invite(ctx context.Context, ownerID int, email string) error {
+	return m.inviteFunc(ownerID, email)
+}
+
+func (m *mockScheduleRepository) GetInvitations(ctx context.Context, userID int) ([]model.Invitation, error) {
+	return m.getInvitationsFunc(userID)
+}
+
+func (m *mockScheduleRepository) GetInvitees(ctx context.Context, ownerID int) ([]model.Invitation, error) {
+	return m.getInviteesFunc(ownerID)
+}
+
+func (m *mockScheduleRepository) AcceptInvitation(ctx context.Context, ownerID int, userID int) error {
+	return m.acceptInvitationFunc(ownerID, userID)
+}
+
+func (m *mockScheduleRepository) DeleteInvitation(ctx context.Context, ownerID int, userID int) error {
+	return m.deleteInvitationFunc(ownerID, userID)
+}
+
+type mockNotifierService struct {
+	getByUserIDFunc func(userID int) ([]*notifModel.Notifier, error)
+}
+
+func (m *mockNotifierService) GetByUserID(ctx context.Context, userID int) ([]*notifModel.Notifier, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+// teamMembers is the on-call team of alice (1): herself and bob (2), who
+// accepted her invitation. carol (3) has an account but is not on the team.
+func teamMembers(ownerID int, emails []string) ([]model.Member, error) {
+	users := map[string]int{"alice@example.com": 1, "bob@example.com": 2}
+	if ownerID != 1 {
+		users = map[string]int{}
+	}
+	var members []model.Member
+	for _, email := range emails {
+		if id, ok := users[email]; ok {
+			members = append(members, model.Member{UserID: id, Email: email})
+		}
+	}
+	return members, nil
+}
+
+func testSchedule() *model.Schedule {
+	return &model.Schedule{
+		ID:       1,
+		UserID:   1,
+		Name:     "Primary",
+		Rotation: model.RotationWeekly,
+		Handoff:  "09:00",
+		StartsOn: time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC),
+		Members:  []model.Member{{UserID: 1, Email: "alice@example.com"}, {UserID: 2, Email: "bob@example.com"}},
+	}
+}
+
+func TestScheduleService_Create(t *testing.T) {
+	var created *model.Schedule
+	mockRepo := &mockScheduleRepository{
+		getMembersByEmailFunc: teamMembers,
+		createFunc: func(schedule *model.Schedule) (*model.Schedule, error) {
+			created = schedule
+			result := *schedule
+			result.ID = 3
+			return &result, nil
+		},
+	}
+	service := NewScheduleService(mockRepo, &mockNotifierService{})
+
+	schedule := testSchedule()
+	schedule.ID = 0
+	schedule.Members = nil
+	assert.NoError(t, service.Create(context.Background(), schedule, 1, []string{" Bob@example.com", "alice@example.com", ""}))
+	assert.Equal(t, 3, schedule.ID)
+	assert.Equal(t, []int{2, 1}, []int{created.Members[0].UserID, created.Members[1].UserID})
+
+	// Someone off the team gets the same answer as an unknown address
+	err := service.Create(context.Background(), testSchedule(), 1, []string{"nobody@example.com"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.EqualError(t, err, "invalid input parameters: nobody@example.com is not on your on-call team, invite them first")
+	err = service.Create(context.Background(), testSchedule(), 1, []string{"carol@example.com"})
+	assert.EqualError(t, err, "invalid input parameters: carol@example.com is not on your on-call team, invite them first")
+
+	err = service.Create(context.Background(), testSchedule(), 1, nil)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestScheduleService_Ownership(t *testing.T) {
+	deleted := false
+	mockRepo := &mockScheduleRepository{
+		getFunc: func(id int) (*model.Schedule, error) {
+			if id != 1 {
+				return nil, repository.ErrScheduleNotFound
+			}
+			return testSchedule(), nil
+		},
+		deleteFunc: func(id int) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := NewScheduleService(mockRepo, &mockNotifierService{})
+
+	_, err := service.Get(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	_, err = service.Get(context.Background(), 2, 1)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+
+	assert.ErrorIs(t, service.Delete(context.Background(), 1, 2), ErrUnauthorized)
+	assert.False(t, deleted)
+	assert.NoError(t, service.Delete(context.Background(), 1, 1))
+	assert.True(t, deleted)
+}
+
+func TestScheduleService_Overrides(t *testing.T) {
+	var added *model.Override
+	mockRepo := &mockScheduleRepository{
+		getFunc: func(id int) (*model.Schedule, error) {
+			return testSchedule(), nil
+		},
+		getMembersByEmailFunc: teamMembers,
+		addOverrideFunc: func(scheduleID int, override *model.Override) (*model.Override, error) {
+			added = override
+			return override, nil
+		},
+		deleteOverrideFunc: func(scheduleID int, overrideID int) error {
+			return repository.ErrOverrideNotFound
+		},
+	}
+	service := NewScheduleService(mockRepo, &mockNotifierService{})
+	start := time.Date(2025, 4, 22, 18, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, service.AddOverride(context.Background(), 1, 1, "bob@example.com", start, start.Add(time.Hour)))
+	assert.Equal(t, 2, added.UserID)
+
+	assert.ErrorIs(t, service.AddOverride(context.Background(), 1, 1, "bob@example.com", start, start), ErrInvalidInput)
+	assert.ErrorIs(t, service.AddOverride(context.Background(), 1, 1, "carol@example.com", start, start.Add(time.Hour)), ErrInvalidInput)
+	assert.ErrorIs(t, service.AddOverride(context.Background(), 1, 2, "bob@example.com", start, start.Add(time.Hour)), ErrUnauthorized)
+	assert.ErrorIs(t, service.DeleteOverride(context.Background(), 1, 9, 1), ErrScheduleNotFound)
+}
+
+func TestScheduleService_ResolveNotifierIDs(t *testing.T) {
+	mockRepo := &mockScheduleRepository{
+		getFunc: func(id int) (*model.Schedule, error) {
+			if id != 1 {
+				return nil, repository.ErrScheduleNotFound
+			}
+			return testSchedule(), nil
+		},
+	}
+	mockNotifier := &mockNotifierService{
+		getByUserIDFunc: func(userID int) ([]*notifModel.Notifier, error) {
+			return []*notifModel.Notifier{{ID: userID * 10}, {ID: userID*10 + 1}}, nil
+		},
+	}
+	service := NewScheduleService(mockRepo, mockNotifier)
+
+	// Second week of the rotation: bob is on call
+	ids, err := service.ResolveNotifierIDs(context.Background(), []int{1, 5}, time.Date(2025, 4, 29, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20, 21}, ids)
+}
+
+func TestScheduleService_Invitations(t *testing.T) {
+	var invited []string
+	mockRepo := &mockScheduleRepository{
+		inviteFunc: func(ownerID int, email string) error {
+			invited = append(invited, email)
+			return nil
+		},
+		acceptInvitationFunc: func(ownerID int, userID int) error {
+			if ownerID != 1 || userID != 2 {
+				return repository.ErrInvitationNotFound
+			}
+			return nil
+		},
+		deleteInvitationFunc: func(ownerID int, userID int) error {
+			return repository.ErrInvitationNotFound
+		},
+	}
+	service := NewScheduleService(mockRepo, &mockNotifierService{})
+
+	assert.NoError(t, service.Invite(context.Background(), 1, " Bob@Example.com "))
+	assert.Equal(t, []string{"bob@example.com"}, invited)
+	assert.ErrorIs(t, service.Invite(context.Background(), 1, " "), ErrInvalidInput)
+
+	assert.NoError(t, service.AcceptInvitation(context.Background(), 1, 2))
+	assert.ErrorIs(t, service.AcceptInvitation(context.Background(), 1, 3), ErrInvitationNotFound)
+	assert.ErrorIs(t, service.DeleteInvitation(context.Background(), 1, 3), ErrInvitationNotFound)
+}
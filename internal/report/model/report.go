@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+)
+
+// Recipient is a user who receives the uptime digest
+type Recipient struct {
+	UserID    int                       `db:"id"`
+	Email     string                    `db:"email"`
+	Frequency authModel.DigestFrequency `db:"digest_frequency"`
+	SentAt    *time.Time                `db:"digest_sent_at"` // nil until the first digest is sent
+}
+
+// Due reports whether a whole period passed since the last digest
+func (r *Recipient) Due(now time.Time) bool {
+	period := r.Frequency.Period()
+	if period == 0 {
+		return false
+	}
+	return r.SentAt == nil || now.Sub(*r.SentAt) >= period
+}
+
+// TargetReport summarizes how a target did over a digest period
+type TargetReport struct {
+	TargetID  int
+	URL       string
+	Up        time.Duration // time the target was known to be up, from its checks
+	Down      time.Duration // time the target was known to be down, from its checks
+	Incidents int           // incidents started in the period
+	Downtime  time.Duration // time spent in incidents within the period
+	P50       time.Duration // median response time
+	P95       time.Duration // 95th percentile response time
+}
+
+// Uptime returns the share of the measured time the target was up, as a
+// percentage. A target without checks in the period reports 100.
+func (r *TargetReport) Uptime() float64 {
+	if r.Up+r.Down == 0 {
+		return 100
+	}
+	return float64(r.Up) * 100 / float64(r.Up+r.Down)
+}
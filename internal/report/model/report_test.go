@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecipient_Due(t *testing.T) {
+	now := time.Date(2025, 4, 27, 9, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+
+	tests := []struct {
+		name      string
+		recipient Recipient
+		expected  bool
+	}{
+		{"never sent", Recipient{Frequency: authModel.DigestWeekly}, true},
+		{"daily sent yesterday", Recipient{Frequency: authModel.DigestDaily, SentAt: ago(24 * time.Hour)}, true},
+		{"daily sent this morning", Recipient{Frequency: authModel.DigestDaily, SentAt: ago(2 * time.Hour)}, false},
+		{"weekly sent three days ago", Recipient{Frequency: authModel.DigestWeekly, SentAt: ago(72 * time.Hour)}, false},
+		{"opted out", Recipient{Frequency: authModel.DigestOff}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.recipient.Due(now))
+		})
+	}
+}
+
+func TestTargetReport_Uptime(t *testing.T) {
+	assert.Equal(t, 100.0, (&TargetReport{}).Uptime())
+	assert.Equal(t, 75.0, (&TargetReport{Up: 3 * time.Hour, Down: time.Hour}).Uptime())
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/report/model"
+)
+
+type ReportRepositoryInterface interface {
+	GetRecipients(ctx context.Context) ([]*model.Recipient, error)
+	GetTargetReports(ctx context.Context, userID int, from, to time.Time) ([]*model.TargetReport, error)
+	MarkSent(ctx context.Context, userID int, sentAt time.Time) error
+}
+
+var _ ReportRepositoryInterface = (*ReportRepository)(nil)
+
+// ReportRepository aggregates check results and incidents for digests
+type ReportRepository struct {
+	db database.Querier
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db database.Querier) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// GetRecipients returns the verified users who did not opt out of digests
+func (r *ReportRepository) GetRecipients(ctx context.Context) ([]*model.Recipient, error) {
+	query := `
+		SELECT id, email, digest_frequency, digest_sent_at
+		FROM usr
+		WHERE verified AND digest_frequency <> 'off'
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []*model.Recipient
+	for rows.Next() {
+		recipient := &model.Recipient{}
+		if err := rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Frequency, &recipient.SentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
+
+// GetTargetReports summarizes every target of the user between from and to.
+// Downtime only counts the part of each incident that falls in the period,
+// and checks made during maintenance windows are left out of response times.
+// Uptime is not part of it, as the SLA service works it out.
+func (r *ReportRepository) GetTargetReports(ctx context.Context, userID int, from, to time.Time) ([]*model.TargetReport, error) {
+	query := `
+		SELECT t.id, t.url,
+			COALESCE(c.p50, 0), COALESCE(c.p95, 0),
+			COALESCE(i.incidents, 0), COALESCE(i.downtime, 0)
+		FROM target t
+		LEFT JOIN (
+			SELECT target_id,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms) AS p50,
+				percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms) AS p95
+			FROM check_result
+			WHERE checked_at >= $2::timestamp AND checked_at < $3::timestamp AND status <> 'maintenance'
+			GROUP BY target_id
+		) c ON c.target_id = t.id
+		LEFT JOIN (
+			SELECT target_id,
+				COUNT(*) FILTER (WHERE started_at >= $2::timestamp) AS incidents,
+				SUM(EXTRACT(EPOCH FROM
+					LEAST(COALESCE(resolved_at, $3::timestamp), $3::timestamp) - GREATEST(started_at, $2::timestamp)
+				)) AS downtime
+			FROM incident
+			WHERE started_at < $3::timestamp AND (resolved_at IS NULL OR resolved_at > $2::timestamp)
+			GROUP BY target_id
+		) i ON i.target_id = t.id
+		WHERE t.user_id = $1
+		ORDER BY t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*model.TargetReport
+	for rows.Next() {
+		report := &model.TargetReport{}
+		var p50, p95, downtime float64
+		err := rows.Scan(
+			&report.TargetID,
+			&report.URL,
+			&p50,
+			&p95,
+			&report.Incidents,
+			&downtime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan target report: %w", err)
+		}
+		report.P50 = time.Duration(math.Round(p50)) * time.Millisecond
+		report.P95 = time.Duration(math.Round(p95)) * time.Millisecond
+		report.Downtime = time.Duration(math.Round(downtime)) * time.Second
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// MarkSent records when the user was last sent a digest
+func (r *ReportRepository) MarkSent(ctx context.Context, userID int, sentAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE usr SET digest_sent_at = $1 WHERE id = $2`, sentAt.UTC(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	incidentRepo "github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewReportRepository(tx)
+	users := authRepo.NewUserRepository(tx)
+	targets := monitorRepo.NewTargetRepository(tx)
+
+	user, err := users.SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	user.Verified = true
+	_, err = users.UpdateUser(context.Background(), user)
+	assert.NoError(t, err)
+
+	// Unverified users never receive digests
+	_, err = users.SaveUser(context.Background(), &authModel.User{Email: "unverified@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+
+	target, err := targets.Create(context.Background(), monitorModel.UserTarget{
+		UserID: user.ID,
+		Target: &core.Target{
+			URL:             "https://example.org",
+			Status:          "up",
+			Enabled:         true,
+			Interval:        30 * time.Second,
+			StatusChangedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+
+	to := time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+
+	for i, duration := range []time.Duration{100, 200, 300, 400} {
+		status := "up"
+		if i == 3 {
+			status = "down"
+		}
+		assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{
+			Status:    status,
+			Duration:  duration * time.Millisecond,
+			CheckedAt: from.Add(time.Duration(i+1) * time.Hour),
+		}))
+	}
+	// Outside of the period
+	assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{Status: "down", CheckedAt: from.Add(-time.Hour)}))
+	// Made during a maintenance window
+	assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{Status: "maintenance", Duration: 5 * time.Second, CheckedAt: from.Add(5 * time.Hour)}))
+
+	// Started before the period: only the last ten minutes count as downtime
+	incidents := incidentRepo.NewIncidentRepository(tx)
+	_, _, err = incidents.Open(context.Background(), target.ID, "down", from.Add(-time.Hour))
+	assert.NoError(t, err)
+	_, err = incidents.Resolve(context.Background(), target.ID, from.Add(10*time.Minute))
+	assert.NoError(t, err)
+	_, _, err = incidents.Open(context.Background(), target.ID, "down", from.Add(6*time.Hour))
+	assert.NoError(t, err)
+	_, err = incidents.Resolve(context.Background(), target.ID, from.Add(6*time.Hour+20*time.Minute))
+	assert.NoError(t, err)
+
+	t.Run("recipients", func(t *testing.T) {
+		recipients, err := repo.GetRecipients(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, recipients, 1)
+		assert.Equal(t, user.ID, recipients[0].UserID)
+		assert.Equal(t, authModel.DigestWeekly, recipients[0].Frequency)
+		assert.Nil(t, recipients[0].SentAt)
+	})
+
+	t.Run("target reports", func(t *testing.T) {
+		reports, err := repo.GetTargetReports(context.Background(), user.ID, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+
+		report := reports[0]
+		assert.Equal(t, "https://example.org", report.URL)
+		assert.Equal(t, 1, report.Incidents)
+		assert.Equal(t, 30*time.Minute, report.Downtime)
+		assert.Equal(t, 250*time.Millisecond, report.P50)
+		assert.Equal(t, 385*time.Millisecond, report.P95)
+	})
+
+	t.Run("mark sent", func(t *testing.T) {
+		assert.NoError(t, repo.MarkSent(context.Background(), user.ID, to))
+
+		recipients, err := repo.GetRecipients(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, to, recipients[0].SentAt.UTC())
+	})
+
+	t.Run("opted out", func(t *testing.T) {
+		assert.NoError(t, users.UpdateDigestFrequency(context.Background(), user.ID, authModel.DigestOff))
+
+		recipients, err := repo.GetRecipients(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, recipients)
+	})
+}
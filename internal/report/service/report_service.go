@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/report/model"
+	"github.com/shuvo-paul/uptimebot/internal/report/repository"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+)
+
+type ReportServiceInterface interface {
+	SendDue(ctx context.Context) error
+}
+
+var _ ReportServiceInterface = (*ReportService)(nil)
+
+// AvailabilityService is the part of the SLA service the uptime in digests
+// relies on
+type AvailabilityService interface {
+	Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (slaModel.Availability, error)
+}
+
+// ReportService emails users a periodic digest of how their targets did
+type ReportService struct {
+	repo                repository.ReportRepositoryInterface
+	availabilityService AvailabilityService
+	mailer              email.Mailer
+	template            *template.Template
+	baseURL             string
+	now                 func() time.Time
+}
+
+func NewReportService(
+	repo repository.ReportRepositoryInterface,
+	availabilityService AvailabilityService,
+	mailer email.Mailer,
+	template *template.Template,
+	baseURL string,
+) *ReportService {
+	return &ReportService{
+		repo:                repo,
+		availabilityService: availabilityService,
+		mailer:              mailer,
+		template:            template,
+		baseURL:             baseURL,
+		now:                 time.Now,
+	}
+}
+
+// digestRow is a target report formatted for the email
+type digestRow struct {
+	URL       string
+	Uptime    string
+	Incidents int
+	Downtime  string
+	P50       string
+	P95       string
+}
+
+type digestData struct {
+	Frequency   string
+	From        string
+	To          string
+	Targets     []digestRow
+	SettingsURL string
+}
+
+// SendDue emails a digest to every user whose period is over. A failed
+// digest is retried on the next run, the other users still get theirs.
+func (s *ReportService) SendDue(ctx context.Context) error {
+	recipients, err := s.repo.GetRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	var errs []error
+	for _, recipient := range recipients {
+		if !recipient.Due(now) {
+			continue
+		}
+		if err := s.send(ctx, recipient, now); err != nil {
+			errs = append(errs, fmt.Errorf("digest for user %d: %w", recipient.UserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *ReportService) send(ctx context.Context, recipient *model.Recipient, now time.Time) error {
+	from := now.Add(-recipient.Frequency.Period())
+	reports, err := s.repo.GetTargetReports(ctx, recipient.UserID, from, now)
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		availability, err := s.availabilityService.Availability(ctx, report.TargetID, recipient.UserID, from, now)
+		if err != nil {
+			return fmt.Errorf("failed to get availability of target %d: %w", report.TargetID, err)
+		}
+		report.Up, report.Down = availability.Up, availability.Down
+	}
+
+	data := digestData{
+		Frequency:   string(recipient.Frequency),
+		From:        from.UTC().Format("Jan 2, 2006 15:04 MST"),
+		To:          now.UTC().Format("Jan 2, 2006 15:04 MST"),
+		SettingsURL: s.baseURL + "/app/profile",
+	}
+	for _, report := range reports {
+		data.Targets = append(data.Targets, digestRow{
+			URL:       report.URL,
+			Uptime:    fmt.Sprintf("%.2f%%", report.Uptime()),
+			Incidents: report.Incidents,
+			Downtime:  report.Downtime.String(),
+			P50:       report.P50.String(),
+			P95:       report.P95.String(),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := s.template.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	if err := s.mailer.SetTo(recipient.Email); err != nil {
+		return fmt.Errorf("failed to set email recipient: %w", err)
+	}
+	if err := s.mailer.SetSubject(fmt.Sprintf("Your %s uptime digest", recipient.Frequency)); err != nil {
+		return fmt.Errorf("failed to set email subject: %w", err)
+	}
+	if err := s.mailer.SetBody(buf.String()); err != nil {
+		return fmt.Errorf("failed to set email body: %w", err)
+	}
+	if err := s.mailer.SendEmail(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return s.repo.MarkSent(ctx, recipient.UserID, now)
+}
+
+// Start sends due digests every interval in the background
+func (s *ReportService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.SendDue(context.Background()); err != nil {
+				slog.Error("Failed to send uptime digests", "error", err)
+			}
+		}
+	}()
+}
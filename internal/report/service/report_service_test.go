@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/report/model"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReportRepository struct {
+	getRecipientsFunc    func() ([]*model.Recipient, error)
+	getTargetReportsFunc func(userID int, from, to time.Time) ([]*model.TargetReport, error)
+	markSentFunc         func(userID int, sentAt time.Time) error
+}
+
+func (m *mockReportRepository) GetRecipients(ctx context.Context) ([]*model.Recipient, error) {
+	return m.getRecipientsFunc()
+}
+
+func (m *mockReportRepository) GetTargetReports(ctx context.Context, userID int, from, to time.Time) ([]*model.TargetReport, error) {
+	return m.getTargetReportsFunc(userID, from, to)
+}
+
+func (m *mockReportRepository) MarkSent(ctx context.Context, userID int, sentAt time.Time) error {
+	return m.markSentFunc(userID, sentAt)
+}
+
+type mockAvailabilityService struct {
+	availabilityFunc func(targetID int, userID int, from, to time.Time) (slaModel.Availability, error)
+}
+
+func (m *mockAvailabilityService) Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (slaModel.Availability, error) {
+	return m.availabilityFunc(targetID, userID, from, to)
+}
+
+func TestReportService_SendDue(t *testing.T) {
+	now := time.Date(2025, 4, 27, 9, 0, 0, 0, time.UTC)
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	var periods []time.Duration
+	sent := map[int]time.Time{}
+	mockRepo := &mockReportRepository{
+		getRecipientsFunc: func() ([]*model.Recipient, error) {
+			return []*model.Recipient{
+				{UserID: 1, Email: "weekly@example.com", Frequency: authModel.DigestWeekly, SentAt: &lastWeek},
+				{UserID: 2, Email: "daily@example.com", Frequency: authModel.DigestDaily, SentAt: &earlier},
+				{UserID: 3, Email: "new@example.com", Frequency: authModel.DigestDaily},
+			}, nil
+		},
+		getTargetReportsFunc: func(userID int, from, to time.Time) ([]*model.TargetReport, error) {
+			periods = append(periods, to.Sub(from))
+			if userID == 3 {
+				return nil, nil
+			}
+			return []*model.TargetReport{{
+				TargetID:  1,
+				URL:       "https://example.org",
+				Incidents: 2,
+				Downtime:  90 * time.Second,
+				P50:       120 * time.Millisecond,
+				P95:       450 * time.Millisecond,
+			}}, nil
+		},
+		markSentFunc: func(userID int, sentAt time.Time) error {
+			sent[userID] = sentAt
+			return nil
+		},
+	}
+	mailer := &email.MailServiceMock{}
+	tmpl := renderer.New(templates.TemplateFS, flash.NewFlashStore()).GetTemplate("emails:digest").Raw()
+
+	availability := &mockAvailabilityService{
+		availabilityFunc: func(targetID int, userID int, from, to time.Time) (slaModel.Availability, error) {
+			assert.Equal(t, 1, targetID)
+			assert.Equal(t, now, to)
+			return slaModel.Availability{Up: 399 * time.Minute, Down: time.Minute, Excluded: time.Hour}, nil
+		},
+	}
+
+	service := NewReportService(mockRepo, availability, mailer, tmpl, "https://uptimebot.example")
+	service.now = func() time.Time { return now }
+
+	assert.NoError(t, service.SendDue(context.Background()))
+
+	// The daily digest sent an hour ago is not due yet
+	assert.Equal(t, []string{"weekly@example.com", "new@example.com"}, mailer.GetSetToCalls())
+	assert.Equal(t, []string{"Your weekly uptime digest", "Your daily uptime digest"}, mailer.GetSetSubjectCalls())
+	assert.Equal(t, []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}, periods)
+	assert.Equal(t, map[int]time.Time{1: now, 3: now}, sent)
+
+	bodies := mailer.GetSetBodyCalls()
+	assert.Contains(t, bodies[0], "https://example.org")
+	assert.Contains(t, bodies[0], "99.75%")
+	assert.Contains(t, bodies[0], "1m30s")
+	assert.Contains(t, bodies[0], "450ms")
+	assert.Contains(t, bodies[0], "https://uptimebot.example/app/profile")
+	assert.Contains(t, bodies[1], "not monitoring any targets")
+
+	t.Run("failed send is retried next run", func(t *testing.T) {
+		sent = map[int]time.Time{}
+		mailer.SendEmailFunc = func() error {
+			return fmt.Errorf("smtp unavailable")
+		}
+
+		assert.ErrorContains(t, service.SendDue(context.Background()), "smtp unavailable")
+		assert.Empty(t, sent)
+	})
+}
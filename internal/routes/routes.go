@@ -3,11 +3,19 @@ package routes
 import (
 	"net/http"
 
+	"github.com/shuvo-paul/uptimebot/internal/api"
 	authHandler "github.com/shuvo-paul/uptimebot/internal/auth/handler"
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	badgeHandler "github.com/shuvo-paul/uptimebot/internal/badge/handler"
+	escalationHandler "github.com/shuvo-paul/uptimebot/internal/escalation/handler"
+	incidentHandler "github.com/shuvo-paul/uptimebot/internal/incident/handler"
+	maintenanceHandler "github.com/shuvo-paul/uptimebot/internal/maintenance/handler"
 	"github.com/shuvo-paul/uptimebot/internal/middleware"
 	uptimeHandler "github.com/shuvo-paul/uptimebot/internal/monitor/handler"
 	eventHandler "github.com/shuvo-paul/uptimebot/internal/notification/handler"
+	oncallHandler "github.com/shuvo-paul/uptimebot/internal/oncall/handler"
+	slaHandler "github.com/shuvo-paul/uptimebot/internal/sla/handler"
+	statusPageHandler "github.com/shuvo-paul/uptimebot/internal/statuspage/handler"
 	"github.com/shuvo-paul/uptimebot/pkg/csrf"
 	"github.com/shuvo-paul/uptimebot/pkg/flash"
 	"github.com/shuvo-paul/uptimebot/web/static"
@@ -17,8 +25,19 @@ func SetupRoutes(
 	userHandler *authHandler.AuthHandler,
 	sessionService authService.SessionService,
 	authService authService.AuthService,
+	apiTokenService authService.APITokenServiceInterface,
 	targetHandler *uptimeHandler.TargetHandler,
 	notifierHandler *eventHandler.NotifierHandler,
+	incidentHandler *incidentHandler.IncidentHandler,
+	policyHandler *escalationHandler.PolicyHandler,
+	scheduleHandler *oncallHandler.ScheduleHandler,
+	windowHandler *maintenanceHandler.WindowHandler,
+	statusPageHandler *statusPageHandler.StatusPageHandler,
+	badgeHandler *badgeHandler.BadgeHandler,
+	slaHandler *slaHandler.SLAHandler,
+	slackHandler *eventHandler.SlackHandler,
+	apiHandler *api.Handler,
+	metricsHandler http.Handler,
 ) http.Handler {
 	// Setup routes
 	mux := http.NewServeMux()
@@ -42,6 +61,15 @@ func SetupRoutes(
 	mux.HandleFunc("POST /send-reset-password-link", userHandler.SendResetLink)
 	mux.HandleFunc("GET /reset-password", userHandler.ShowResetPasswordForm)
 	mux.HandleFunc("POST /reset-password", userHandler.ResetPassword)
+	mux.HandleFunc("GET /status/{slug}", statusPageHandler.Show)
+	mux.HandleFunc("GET /status/{slug}/feed.rss", statusPageHandler.Feed)
+	mux.HandleFunc("GET /status/{slug}/feed.atom", statusPageHandler.Feed)
+	mux.HandleFunc("GET /status/{slug}/feed.json", statusPageHandler.Feed)
+	mux.HandleFunc("GET /status/{slug}/subscribe", statusPageHandler.Subscribe)
+	mux.HandleFunc("POST /status/{slug}/subscribe", statusPageHandler.Subscribe)
+	mux.HandleFunc("GET /status/{slug}/confirm", statusPageHandler.Confirm)
+	mux.HandleFunc("GET /status/{slug}/unsubscribe", statusPageHandler.Unsubscribe)
+	mux.HandleFunc("POST /status/{slug}/unsubscribe", statusPageHandler.Unsubscribe)
 
 	// Protected routes
 	protected := http.NewServeMux()
@@ -57,26 +85,140 @@ func SetupRoutes(
 	protected.HandleFunc("POST /targets/delete/{id}", targetHandler.Delete)
 	protected.HandleFunc("POST /targets/toggle-enable/{id}", targetHandler.ToggleEnabled)
 
+	protected.HandleFunc("GET /targets/badges/{id}", badgeHandler.Show)
+	protected.HandleFunc("POST /targets/badges/{id}", badgeHandler.Regenerate)
+	protected.HandleFunc("POST /targets/badges/{id}/disable", badgeHandler.Disable)
+	protected.HandleFunc("GET /targets/sla/{id}", slaHandler.Show)
+	protected.HandleFunc("POST /targets/sla/{id}", slaHandler.CreateSLO)
+	protected.HandleFunc("POST /targets/sla/{id}/delete/{sloId}", slaHandler.DeleteSLO)
+	protected.HandleFunc("GET /targets/notifiers/{targetId}", notifierHandler.Target)
+	protected.HandleFunc("POST /targets/notifiers/{targetId}/attach", notifierHandler.Attach)
+	protected.HandleFunc("POST /targets/notifiers/{targetId}/detach/{id}", notifierHandler.Detach)
+
+	// Account-level contact channels
+	protected.HandleFunc("GET /notifiers", notifierHandler.List)
+	protected.HandleFunc("GET /notifiers/create", notifierHandler.Create)
+	protected.HandleFunc("POST /notifiers/create", notifierHandler.Create)
+	protected.HandleFunc("GET /notifiers/edit/{id}", notifierHandler.Edit)
+	protected.HandleFunc("POST /notifiers/edit/{id}", notifierHandler.Edit)
+	protected.HandleFunc("POST /notifiers/delete/{id}", notifierHandler.Delete)
+	protected.HandleFunc("POST /notifiers/preview", notifierHandler.Preview)
+	protected.HandleFunc("POST /notifiers/attach-all/{id}", notifierHandler.AttachAll)
+	protected.HandleFunc("POST /notifiers/attach-tag/{id}", notifierHandler.AttachTag)
+
+	// Incidents and escalation
+	protected.HandleFunc("GET /incidents", incidentHandler.List)
+	protected.HandleFunc("GET /incidents/{id}", incidentHandler.Show)
+	protected.HandleFunc("POST /incidents/{id}/acknowledge", incidentHandler.Acknowledge)
+	protected.HandleFunc("POST /incidents/{id}/comment", incidentHandler.Comment)
+	protected.HandleFunc("GET /escalation-policies", policyHandler.List)
+	protected.HandleFunc("GET /escalation-policies/create", policyHandler.Create)
+	protected.HandleFunc("POST /escalation-policies/create", policyHandler.Create)
+	protected.HandleFunc("GET /escalation-policies/edit/{id}", policyHandler.Edit)
+	protected.HandleFunc("POST /escalation-policies/edit/{id}", policyHandler.Edit)
+	protected.HandleFunc("POST /escalation-policies/delete/{id}", policyHandler.Delete)
+
+	// On-call schedules
+	protected.HandleFunc("GET /oncall", scheduleHandler.List)
+	protected.HandleFunc("GET /oncall/create", scheduleHandler.Create)
+	protected.HandleFunc("POST /oncall/create", scheduleHandler.Create)
+	protected.HandleFunc("GET /oncall/edit/{id}", scheduleHandler.Edit)
+	protected.HandleFunc("POST /oncall/edit/{id}", scheduleHandler.Edit)
+	protected.HandleFunc("POST /oncall/delete/{id}", scheduleHandler.Delete)
+	protected.HandleFunc("GET /oncall/{id}", scheduleHandler.Show)
+	protected.HandleFunc("GET /oncall/calendar/{id}", scheduleHandler.ICal)
+	protected.HandleFunc("POST /oncall/overrides/{id}", scheduleHandler.AddOverride)
+	protected.HandleFunc("POST /oncall/overrides/{id}/delete/{overrideId}", scheduleHandler.DeleteOverride)
+	protected.HandleFunc("POST /oncall/invitations", scheduleHandler.Invite)
+	protected.HandleFunc("POST /oncall/invitations/{ownerId}/accept", scheduleHandler.AcceptInvitation)
+	protected.HandleFunc("POST /oncall/invitations/{ownerId}/decline", scheduleHandler.DeclineInvitation)
+	protected.HandleFunc("POST /oncall/team/{userId}/remove", scheduleHandler.RemoveInvitee)
+
+	// Maintenance windows
+	protected.HandleFunc("GET /maintenance", windowHandler.List)
+	protected.HandleFunc("GET /maintenance/create", windowHandler.Create)
+	protected.HandleFunc("POST /maintenance/create", windowHandler.Create)
+	protected.HandleFunc("GET /maintenance/edit/{id}", windowHandler.Edit)
+	protected.HandleFunc("POST /maintenance/edit/{id}", windowHandler.Edit)
+	protected.HandleFunc("POST /maintenance/delete/{id}", windowHandler.Delete)
+
+	protected.HandleFunc("GET /status-pages", statusPageHandler.List)
+	protected.HandleFunc("GET /status-pages/create", statusPageHandler.Create)
+	protected.HandleFunc("POST /status-pages/create", statusPageHandler.Create)
+	protected.HandleFunc("GET /status-pages/edit/{id}", statusPageHandler.Edit)
+	protected.HandleFunc("POST /status-pages/edit/{id}", statusPageHandler.Edit)
+	protected.HandleFunc("POST /status-pages/delete/{id}", statusPageHandler.Delete)
+	protected.HandleFunc("POST /status-pages/verify/{id}", statusPageHandler.VerifyDomain)
+	protected.HandleFunc("GET /status-pages/posts/{id}", statusPageHandler.Posts)
+	protected.HandleFunc("POST /status-pages/posts/{id}", statusPageHandler.Posts)
+	protected.HandleFunc("POST /status-pages/posts/{id}/update/{postId}", statusPageHandler.AddPostUpdate)
+	protected.HandleFunc("POST /status-pages/posts/{id}/delete/{postId}", statusPageHandler.DeletePost)
+	protected.HandleFunc("GET /status-pages/subscribers/{id}", statusPageHandler.Subscribers)
+	protected.HandleFunc("POST /status-pages/subscribers/{id}/delete/{subscriberId}", statusPageHandler.DeleteSubscriber)
+
 	protected.HandleFunc("GET /auth/slack/{targetId}", notifierHandler.AuthSlack)
 	protected.HandleFunc("POST /verify-email", userHandler.SendVerificationEmail)
+	protected.HandleFunc("GET /profile", userHandler.ShowProfileForm)
 	protected.HandleFunc("POST /profile", userHandler.ShowProfileForm)
 	protected.HandleFunc("POST /update-password", userHandler.UpdatePassword)
+	protected.HandleFunc("POST /update-digest", userHandler.UpdateDigestSettings)
+	protected.HandleFunc("POST /api-tokens", userHandler.CreateAPIToken)
+	protected.HandleFunc("POST /api-tokens/{id}/revoke", userHandler.RevokeAPIToken)
 
 	// Move Slack callback route to main mux to preserve query parameters
 	protected.HandleFunc("GET /auth/slack/callback", notifierHandler.AuthSlackCallback)
 
 	mux.Handle("/app/", middleware.RequireAuth(
-		http.StripPrefix("/app", protected),
+		middleware.Mount("/app", protected),
 		sessionService,
 		authService,
 	))
 
 	mws := middleware.CreateStack(
+		middleware.Tracing,
 		flash.Middleware,
 		csrf.Middleware,
 		middleware.ErrorHandler,
 		middleware.Logger,
 		middleware.RemoveTrailingSlash,
+		middleware.Metrics,
+		middleware.TraceRoute,
 	)
-	return mws(mux)
+
+	// JSON API, versioned so clients keep working as it evolves
+	v1 := apiHandler.Mux()
+
+	apiStack := middleware.CreateStack(
+		middleware.Tracing,
+		middleware.ErrorHandler,
+		middleware.Logger,
+		middleware.Metrics,
+		middleware.TraceRoute,
+	)
+
+	// Integration routes are called by other services, which sign their
+	// requests instead of carrying a session and a CSRF token. The API
+	// takes bearer tokens, and requires JSON bodies, which other sites
+	// cannot post with a session.
+	root := http.NewServeMux()
+	root.Handle("/api/v1/", apiStack(api.RequireAuth(
+		middleware.Mount("/api/v1", v1),
+		&sessionService,
+		&authService,
+		apiTokenService,
+	)))
+	root.Handle("GET /api/openapi.json", middleware.Logger(http.HandlerFunc(api.OpenAPI)))
+	root.Handle("GET /api/docs", middleware.Logger(http.HandlerFunc(api.Docs)))
+	root.Handle("POST /slack/interactions", middleware.Logger(http.HandlerFunc(slackHandler.Interact)))
+	root.Handle("POST /slack/commands", middleware.Logger(http.HandlerFunc(slackHandler.Command)))
+	root.Handle("GET /metrics", metricsHandler)
+	// Badges are embedded on other sites, so they carry neither sessions nor
+	// cookies, which would keep caches from storing them
+	root.Handle("GET /badge/{token}/status.svg", apiStack(http.HandlerFunc(badgeHandler.Status)))
+	root.Handle("GET /badge/{token}/uptime.svg", apiStack(http.HandlerFunc(badgeHandler.Uptime)))
+	root.Handle("GET /badge/{token}/response-time.svg", apiStack(http.HandlerFunc(badgeHandler.ResponseTime)))
+	// Status pages with a domain of their own are served at its root
+	root.Handle("/", mws(statusPageHandler.CustomDomain(mux)))
+
+	return root
 }
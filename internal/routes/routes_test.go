@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/api"
+	authHandler "github.com/shuvo-paul/uptimebot/internal/auth/handler"
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	badgeHandler "github.com/shuvo-paul/uptimebot/internal/badge/handler"
+	escalationHandler "github.com/shuvo-paul/uptimebot/internal/escalation/handler"
+	incidentHandler "github.com/shuvo-paul/uptimebot/internal/incident/handler"
+	maintenanceHandler "github.com/shuvo-paul/uptimebot/internal/maintenance/handler"
+	uptimeHandler "github.com/shuvo-paul/uptimebot/internal/monitor/handler"
+	eventHandler "github.com/shuvo-paul/uptimebot/internal/notification/handler"
+	oncallHandler "github.com/shuvo-paul/uptimebot/internal/oncall/handler"
+	slaHandler "github.com/shuvo-paul/uptimebot/internal/sla/handler"
+	statusPageHandler "github.com/shuvo-paul/uptimebot/internal/statuspage/handler"
+	"github.com/shuvo-paul/uptimebot/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type mockSessionRepository struct{}
+
+func (m *mockSessionRepository) Create(ctx context.Context, session *model.Session) error {
+	return nil
+}
+
+func (m *mockSessionRepository) GetByToken(ctx context.Context, token string) (*model.Session, error) {
+	return &model.Session{UserID: 1, Token: token, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (m *mockSessionRepository) Delete(ctx context.Context, token string) error {
+	return nil
+}
+
+type mockUserRepository struct{}
+
+func (m *mockUserRepository) SaveUser(ctx context.Context, user *model.User) (*model.User, error) {
+	return user, nil
+}
+
+func (m *mockUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockUserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return &model.User{ID: 1, Email: email}, nil
+}
+
+func (m *mockUserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	return &model.User{ID: id, Email: "test@example.com"}, nil
+}
+
+func (m *mockUserRepository) UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	return user, nil
+}
+
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID int, hashedPassword string) error {
+	return nil
+}
+
+func (m *mockUserRepository) UpdateDigestFrequency(ctx context.Context, userID int, frequency model.DigestFrequency) error {
+	return nil
+}
+
+func setupTestRoutes() http.Handler {
+	return SetupRoutes(
+		&authHandler.AuthHandler{},
+		*authService.NewSessionService(&mockSessionRepository{}),
+		*authService.NewAuthService(&mockUserRepository{}, nil),
+		nil,
+		&uptimeHandler.TargetHandler{},
+		&eventHandler.NotifierHandler{},
+		&incidentHandler.IncidentHandler{},
+		&escalationHandler.PolicyHandler{},
+		&oncallHandler.ScheduleHandler{},
+		&maintenanceHandler.WindowHandler{},
+		&statusPageHandler.StatusPageHandler{},
+		&badgeHandler.BadgeHandler{},
+		&slaHandler.SLAHandler{},
+		&eventHandler.SlackHandler{},
+		api.NewHandler(nil, nil, nil),
+		http.NotFoundHandler(),
+	)
+}
+
+var mountedRoutes = []struct {
+	name  string
+	path  string
+	route string
+}{
+	{
+		name:  "app route",
+		path:  "/app/targets/edit/abc",
+		route: "GET /app/targets/edit/{id}",
+	},
+	{
+		name:  "api route",
+		path:  "/api/v1/targets/abc",
+		route: "GET /api/v1/targets/{id}",
+	},
+}
+
+func serveAuthenticated(handler http.Handler, path string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = "localhost"
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: "token"})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestSetupRoutes_MetricsRoute(t *testing.T) {
+	handler := setupTestRoutes()
+
+	for _, tt := range mountedRoutes {
+		t.Run(tt.name, func(t *testing.T) {
+			serveAuthenticated(handler, tt.path)
+
+			var out bytes.Buffer
+			metrics.Default.Write(&out)
+			assert.Contains(t, out.String(), `route="`+tt.route+`"`)
+		})
+	}
+}
+
+func TestSetupRoutes_TraceRoute(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	handler := setupTestRoutes()
+
+	for _, tt := range mountedRoutes {
+		t.Run(tt.name, func(t *testing.T) {
+			serveAuthenticated(handler, tt.path)
+
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+			assert.Equal(t, tt.route, span.Name())
+			assert.Contains(t, span.Attributes(), semconv.HTTPRoute(tt.route))
+		})
+	}
+}
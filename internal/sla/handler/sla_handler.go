@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/service"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const dateFormat = "2006-01-02"
+
+// periods are the rolling periods the availability of a target is shown for
+var periods = []struct {
+	Label  string
+	Length time.Duration
+}{
+	{"Last 24 hours", model.Day},
+	{"Last 7 days", 7 * model.Day},
+	{"Last 30 days", 30 * model.Day},
+	{"Last 90 days", 90 * model.Day},
+}
+
+type SLAHandler struct {
+	slaService service.SLAServiceInterface
+	flash      flash.FlashStoreInterface
+	now        func() time.Time
+	Template   struct {
+		Show *renderer.Template
+	}
+}
+
+func NewSLAHandler(slaService service.SLAServiceInterface, flash flash.FlashStoreInterface) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+		flash:      flash,
+		now:        time.Now,
+	}
+}
+
+// availabilityRow is the availability of a target over a period, as shown
+type availabilityRow struct {
+	Label    string
+	Uptime   string
+	Downtime string
+	Excluded string
+}
+
+// sloRow is an SLO with its error budget, as shown
+type sloRow struct {
+	ID         int
+	Name       string
+	Uptime     string
+	Allowed    string
+	Spent      string
+	Remaining  string
+	Exhausted  bool
+	BurnRate   string
+	AlertLevel string
+}
+
+// errorStatus maps SLA service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTargetNotFound), errors.Is(err, service.ErrSLONotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseID reads a positive integer path value
+func parseID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// uptime formats the share of measured time a target was up
+func uptime(availability model.Availability) string {
+	ratio, ok := availability.Ratio()
+	if !ok {
+		return "no data"
+	}
+	return strconv.FormatFloat(ratio*100, 'f', 3, 64) + "%"
+}
+
+func newAvailabilityRow(label string, availability model.Availability) availabilityRow {
+	return availabilityRow{
+		Label:    label,
+		Uptime:   uptime(availability),
+		Downtime: availability.Down.Round(time.Second).String(),
+		Excluded: availability.Excluded.Round(time.Second).String(),
+	}
+}
+
+func newSLORow(status *model.SLOStatus) sloRow {
+	row := sloRow{
+		ID:         status.SLO.ID,
+		Name:       status.SLO.String(),
+		Uptime:     uptime(status.Availability),
+		Allowed:    status.Budget.Allowed.Round(time.Second).String(),
+		Spent:      status.Budget.Spent.Round(time.Second).String(),
+		Remaining:  fmt.Sprintf("%s (%.1f%%)", status.Budget.Remaining().Round(time.Second), status.Budget.RemainingRatio()*100),
+		Exhausted:  status.Budget.Remaining() < 0,
+		BurnRate:   "no data",
+		AlertLevel: status.SLO.AlertLevel,
+	}
+	if status.HasBurnRate {
+		row.BurnRate = strconv.FormatFloat(status.BurnRate, 'f', 1, 64) + "x"
+	}
+	return row
+}
+
+// Show shows the availability of a target over rolling periods and the
+// period in the query, with its SLOs and their error budgets
+func (h *SLAHandler) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	target, err := h.slaService.GetTarget(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	now := h.now()
+	rows := make([]availabilityRow, 0, len(periods))
+	for _, period := range periods {
+		availability, err := h.slaService.Availability(r.Context(), target.ID, user.ID, now.Add(-period.Length), now)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		rows = append(rows, newAvailabilityRow(period.Label, availability))
+	}
+
+	// The custom period runs from the start of its first day to the end of
+	// its last, in UTC
+	query := r.URL.Query()
+	data := map[string]any{
+		"title":        "uptime and SLOs",
+		"target":       target,
+		"availability": rows,
+		"from":         now.Add(-30 * model.Day).UTC().Format(dateFormat),
+		"to":           now.UTC().Format(dateFormat),
+	}
+	if query.Has("from") || query.Has("to") {
+		from, fromErr := time.Parse(dateFormat, query.Get("from"))
+		to, toErr := time.Parse(dateFormat, query.Get("to"))
+		if fromErr != nil || toErr != nil {
+			http.Error(w, "Invalid period", http.StatusBadRequest)
+			return
+		}
+		availability, err := h.slaService.Availability(r.Context(), target.ID, user.ID, from, to.Add(model.Day))
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data["from"], data["to"] = query.Get("from"), query.Get("to")
+		data["custom"] = newAvailabilityRow(fmt.Sprintf("%s to %s", query.Get("from"), query.Get("to")), availability)
+	}
+
+	statuses, err := h.slaService.GetSLOs(r.Context(), target.ID, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	slos := make([]sloRow, len(statuses))
+	for i, status := range statuses {
+		slos[i] = newSLORow(status)
+	}
+	data["slos"] = slos
+
+	h.Template.Show.Render(w, r, data)
+}
+
+// CreateSLO adds an SLO to a target
+func (h *SLAHandler) CreateSLO(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+	slaURL := fmt.Sprintf("/app/targets/sla/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	objective, err := strconv.ParseFloat(r.PostForm.Get("objective"), 64)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid SLO: objective must be a percentage"})
+		http.Redirect(w, r, slaURL, http.StatusSeeOther)
+		return
+	}
+	days, err := strconv.Atoi(r.PostForm.Get("window_days"))
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid SLO: window must be a number of days"})
+		http.Redirect(w, r, slaURL, http.StatusSeeOther)
+		return
+	}
+
+	slo := &model.SLO{TargetID: id, Objective: objective, Window: time.Duration(days) * model.Day}
+	if err := h.slaService.CreateSLO(r.Context(), slo, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to create SLO: " + err.Error()})
+		http.Redirect(w, r, slaURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"SLO created successfully"})
+	http.Redirect(w, r, slaURL, http.StatusSeeOther)
+}
+
+// DeleteSLO removes an SLO from a target
+func (h *SLAHandler) DeleteSLO(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return
+	}
+	sloID, err := parseID(r, "sloId")
+	if err != nil {
+		http.Error(w, "Invalid SLO ID", http.StatusBadRequest)
+		return
+	}
+	slaURL := fmt.Sprintf("/app/targets/sla/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.slaService.DeleteSLO(r.Context(), sloID, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to delete SLO: " + err.Error()})
+		http.Redirect(w, r, slaURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"SLO deleted successfully"})
+	http.Redirect(w, r, slaURL, http.StatusSeeOther)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/service"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockSLAService struct {
+	availabilityFunc func(targetID int, userID int, from, to time.Time) (model.Availability, error)
+	getSLOsFunc      func(targetID int, userID int) ([]*model.SLOStatus, error)
+	createSLOFunc    func(slo *model.SLO, userID int) error
+	deleteSLOFunc    func(id int, userID int) error
+}
+
+func (m *MockSLAService) GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error) {
+	if userID != 1 {
+		return nil, service.ErrUnauthorized
+	}
+	return &model.Target{ID: targetID, UserID: 1, URL: "https://example.org", Interval: time.Minute}, nil
+}
+
+func (m *MockSLAService) Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (model.Availability, error) {
+	return m.availabilityFunc(targetID, userID, from, to)
+}
+
+func (m *MockSLAService) GetSLOs(ctx context.Context, targetID int, userID int) ([]*model.SLOStatus, error) {
+	return m.getSLOsFunc(targetID, userID)
+}
+
+func (m *MockSLAService) CreateSLO(ctx context.Context, slo *model.SLO, userID int) error {
+	return m.createSLOFunc(slo, userID)
+}
+
+func (m *MockSLAService) DeleteSLO(ctx context.Context, id int, userID int) error {
+	return m.deleteSLOFunc(id, userID)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID, Email: "alice@example.com"})
+	return req.WithContext(ctx)
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+var testNow = time.Date(2025, 5, 7, 12, 0, 0, 0, time.UTC)
+
+func newTestSLAHandler(mockService *MockSLAService) *SLAHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewSLAHandler(mockService, mockFlashStore)
+	handler.now = func() time.Time { return testNow }
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.Show = templateRenderer.GetTemplate("pages:targets/sla")
+	return handler
+}
+
+func TestSLAHandler_Show(t *testing.T) {
+	var periods [][2]time.Time
+	mockService := &MockSLAService{
+		availabilityFunc: func(targetID int, userID int, from, to time.Time) (model.Availability, error) {
+			periods = append(periods, [2]time.Time{from, to})
+			if to.Sub(from) > 90*model.Day {
+				return model.Availability{}, service.ErrInvalidInput
+			}
+			if to.Sub(from) == model.Day {
+				return model.Availability{Unknown: model.Day}, nil
+			}
+			return model.Availability{Up: 999 * time.Minute, Down: time.Minute, Excluded: time.Hour}, nil
+		},
+		getSLOsFunc: func(targetID int, userID int) ([]*model.SLOStatus, error) {
+			return []*model.SLOStatus{{
+				SLO:          &model.SLO{ID: 3, TargetID: targetID, Objective: 99.9, Window: 30 * model.Day, AlertLevel: model.AlertSlow},
+				Availability: model.Availability{Up: 999 * time.Minute, Down: time.Minute},
+				Budget:       model.Budget{Allowed: time.Minute, Spent: 2 * time.Minute},
+				BurnRate:     7.5,
+				HasBurnRate:  true,
+			}}, nil
+		},
+	}
+	handler := newTestSLAHandler(mockService)
+
+	t.Run("rolling periods", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/sla/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Show(w, req)
+
+		body := w.Body.String()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, body, "Uptime of https://example.org")
+		assert.Contains(t, body, "no data", "the last 24 hours were not measured")
+		assert.Contains(t, body, "99.900%")
+		assert.Contains(t, body, "99.9% over 30 days")
+		assert.Contains(t, body, "exhausted, -1m0s (-100.0%)")
+		assert.Contains(t, body, "7.5x")
+		assert.Contains(t, body, "(slow burn)")
+		assert.Contains(t, body, `value="2025-04-07"`)
+		assert.Len(t, periods, 4)
+	})
+
+	t.Run("custom period", func(t *testing.T) {
+		periods = nil
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/sla/1?from=2025-04-01&to=2025-04-30", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Show(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "2025-04-01 to 2025-04-30")
+		if assert.Len(t, periods, 5) {
+			assert.Equal(t, [2]time.Time{
+				time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			}, periods[4], "the last day is included")
+		}
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		for _, query := range []string{"from=2025-04-01", "from=2024-01-01&to=2025-01-01"} {
+			req := httptest.NewRequest(http.MethodGet, "/app/targets/sla/1?"+query, nil)
+			req.SetPathValue("id", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			handler.Show(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("unauthorized user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/app/targets/sla/1", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Show(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestSLAHandler_CreateSLO(t *testing.T) {
+	var created *model.SLO
+	mockService := &MockSLAService{
+		createSLOFunc: func(slo *model.SLO, userID int) error {
+			created = slo
+			return nil
+		},
+	}
+	handler := newTestSLAHandler(mockService)
+
+	req := postForm("/app/targets/sla/1", url.Values{"objective": {"99.95"}, "window_days": {"7"}})
+	req.SetPathValue("id", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+	handler.CreateSLO(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/app/targets/sla/1", w.Header().Get("Location"))
+	assert.Equal(t, &model.SLO{TargetID: 1, Objective: 99.95, Window: 7 * model.Day}, created)
+
+	t.Run("invalid objective", func(t *testing.T) {
+		created = nil
+		req := postForm("/app/targets/sla/1", url.Values{"objective": {"three nines"}, "window_days": {"7"}})
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+		handler.CreateSLO(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Nil(t, created)
+	})
+}
+
+func TestSLAHandler_DeleteSLO(t *testing.T) {
+	deleted := 0
+	mockService := &MockSLAService{
+		deleteSLOFunc: func(id int, userID int) error {
+			deleted = id
+			return nil
+		},
+	}
+	handler := newTestSLAHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/targets/sla/1/delete/3", nil)
+	req.SetPathValue("id", "1")
+	req.SetPathValue("sloId", "3")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+	handler.DeleteSLO(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/app/targets/sla/1", w.Header().Get("Location"))
+	assert.Equal(t, 3, deleted)
+}
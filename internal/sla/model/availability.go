@@ -0,0 +1,122 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Statuses recorded by checks that availability tells apart. Every other
+// status, such as down or error, counts as downtime.
+const (
+	StatusUp          = "up"
+	StatusMaintenance = "maintenance"
+	// StatusUnreachableDependency is recorded while a parent of the target is
+	// failing. The outage is the parent's, so it is excluded like maintenance.
+	StatusUnreachableDependency = "unreachable-dependency"
+)
+
+// Target is a monitored target as seen by availability calculations
+type Target struct {
+	ID       int
+	UserID   int
+	URL      string
+	Interval time.Duration
+}
+
+// MaxGap is how long the status found by a check of the target holds when no
+// other check follows it. Allowing for a few missed checks keeps a slow or
+// timed out check from leaving a hole, while a paused or disabled target
+// stops counting soon after its last check.
+func (t *Target) MaxGap() time.Duration {
+	return 3 * t.Interval
+}
+
+// Sample is the status a check found at a point in time
+type Sample struct {
+	Status    string
+	CheckedAt time.Time
+}
+
+// Availability is how a target spent a period of time
+type Availability struct {
+	From     time.Time
+	To       time.Time
+	Up       time.Duration
+	Down     time.Duration
+	Excluded time.Duration // spent in maintenance windows or behind a failing parent
+	Unknown  time.Duration // not covered by any check
+}
+
+// Compute works out the availability of a target over [from, to) from its
+// checks, in the order they were made. The status found by a check holds
+// until the next check, or for at most maxGap. Checks made before from still
+// count for the part of their interval that falls within the period.
+func Compute(samples []Sample, from, to time.Time, maxGap time.Duration) Availability {
+	availability := Availability{From: from, To: to}
+	if !to.After(from) {
+		return availability
+	}
+
+	for i, sample := range samples {
+		start, end := sample.CheckedAt, sample.CheckedAt.Add(maxGap)
+		if i+1 < len(samples) && samples[i+1].CheckedAt.Before(end) {
+			end = samples[i+1].CheckedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		switch elapsed := end.Sub(start); sample.Status {
+		case StatusUp:
+			availability.Up += elapsed
+		case StatusMaintenance, StatusUnreachableDependency:
+			availability.Excluded += elapsed
+		default:
+			availability.Down += elapsed
+		}
+	}
+
+	availability.Unknown = to.Sub(from) - availability.Up - availability.Down - availability.Excluded
+	return availability
+}
+
+// ComputeDays works out the availability of a target over each of the given
+// number of days starting at from, as Compute would for every day on its own
+func ComputeDays(samples []Sample, from time.Time, days int, maxGap time.Duration) []Availability {
+	availabilities := make([]Availability, 0, days)
+	for day := range days {
+		start, end := from.Add(time.Duration(day)*Day), from.Add(time.Duration(day+1)*Day)
+		// Samples are in order, so only those that can reach into the day
+		// are passed on
+		first := sort.Search(len(samples), func(i int) bool {
+			return !samples[i].CheckedAt.Before(start.Add(-maxGap))
+		})
+		last := sort.Search(len(samples), func(i int) bool {
+			return !samples[i].CheckedAt.Before(end)
+		})
+		availabilities = append(availabilities, Compute(samples[first:last], start, end, maxGap))
+	}
+	return availabilities
+}
+
+// Measured is the time the target was known to be up or down, which
+// availability is a share of
+func (a Availability) Measured() time.Duration {
+	return a.Up + a.Down
+}
+
+// Ratio is the share of measured time the target was up, between 0 and 1. It
+// is false when nothing was measured.
+func (a Availability) Ratio() (float64, bool) {
+	measured := a.Measured()
+	if measured <= 0 {
+		return 0, false
+	}
+	return float64(a.Up) / float64(measured), true
+}
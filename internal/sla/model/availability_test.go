@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var base = time.Date(2025, 5, 7, 12, 0, 0, 0, time.UTC)
+
+// at is a sample taken the given number of minutes after base
+func at(minutes int, status string) Sample {
+	return Sample{Status: status, CheckedAt: base.Add(time.Duration(minutes) * time.Minute)}
+}
+
+func TestCompute(t *testing.T) {
+	samples := []Sample{
+		at(-1, "up"),
+		at(1, "up"),
+		at(2, "down"),
+		at(3, "error"),
+		at(4, "up"),
+		at(5, "maintenance"),
+		// Paused after this check, whose status then holds for three minutes
+		at(6, "up"),
+		at(20, "unreachable-dependency"),
+	}
+
+	availability := Compute(samples, base, base.Add(30*time.Minute), 3*time.Minute)
+	assert.Equal(t, 6*time.Minute, availability.Up)
+	assert.Equal(t, 2*time.Minute, availability.Down)
+	assert.Equal(t, 4*time.Minute, availability.Excluded, "maintenance and unreachable dependencies are excluded")
+	assert.Equal(t, 18*time.Minute, availability.Unknown)
+	assert.Equal(t, 8*time.Minute, availability.Measured())
+
+	ratio, ok := availability.Ratio()
+	assert.True(t, ok)
+	assert.InDelta(t, 6.0/8, ratio, 1e-9)
+
+	t.Run("checks before the period", func(t *testing.T) {
+		availability := Compute(samples, base.Add(90*time.Second), base.Add(150*time.Second), 3*time.Minute)
+		assert.Equal(t, 30*time.Second, availability.Up)
+		assert.Equal(t, 30*time.Second, availability.Down)
+		assert.Zero(t, availability.Unknown)
+	})
+
+	t.Run("no checks", func(t *testing.T) {
+		availability := Compute(nil, base, base.Add(time.Hour), time.Minute)
+		assert.Equal(t, time.Hour, availability.Unknown)
+		_, ok := availability.Ratio()
+		assert.False(t, ok)
+	})
+
+	t.Run("empty period", func(t *testing.T) {
+		availability := Compute(samples, base, base, time.Minute)
+		assert.Zero(t, availability.Unknown)
+		assert.Zero(t, availability.Measured())
+	})
+}
+
+func TestComputeDays(t *testing.T) {
+	from := base.Truncate(Day)
+	samples := []Sample{
+		{Status: "up", CheckedAt: from.Add(22 * time.Hour)},
+		// Holds for an hour into the second day
+		{Status: "down", CheckedAt: from.Add(Day - time.Hour)},
+		{Status: "up", CheckedAt: from.Add(Day + time.Hour)},
+		{Status: "up", CheckedAt: from.Add(3 * Day)},
+	}
+
+	days := ComputeDays(samples, from, 3, 2*time.Hour)
+	if assert.Len(t, days, 3) {
+		for i, day := range days {
+			assert.Equal(t, Compute(samples, from.Add(time.Duration(i)*Day), from.Add(time.Duration(i+1)*Day), 2*time.Hour), day)
+		}
+		assert.Equal(t, time.Hour, days[1].Down)
+		assert.Equal(t, 2*time.Hour, days[1].Up)
+		assert.Zero(t, days[2].Measured())
+	}
+}
+
+func TestTarget_MaxGap(t *testing.T) {
+	target := &Target{Interval: 30 * time.Second}
+	assert.Equal(t, 90*time.Second, target.MaxGap())
+}
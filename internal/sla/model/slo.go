@@ -0,0 +1,164 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Day is the unit SLO windows are given in
+const Day = 24 * time.Hour
+
+// MaxWindow bounds the rolling window of an SLO
+const MaxWindow = 90 * Day
+
+// Burn rate alert levels, from the most to the least urgent
+const (
+	AlertFast = "fast"
+	AlertSlow = "slow"
+)
+
+// SLO is a service level objective: the share of time a target should be up,
+// measured over a rolling window. The downtime the objective allows is its
+// error budget.
+type SLO struct {
+	ID         int
+	TargetID   int
+	Objective  float64       // percentage of measured time the target should be up, such as 99.9
+	Window     time.Duration // a whole number of days
+	AlertLevel string        // burn rate alert last sent, empty while the budget burns normally
+	AlertedAt  *time.Time
+}
+
+// Validate checks the objective and window
+func (o *SLO) Validate() error {
+	if o.TargetID <= 0 {
+		return fmt.Errorf("target is required")
+	}
+	if o.Objective <= 0 || o.Objective >= 100 {
+		return fmt.Errorf("objective must be above 0%% and below 100%%")
+	}
+	if o.Window < Day || o.Window > MaxWindow || o.Window%Day != 0 {
+		return fmt.Errorf("window must be between 1 and %d days", MaxWindow/Day)
+	}
+	return nil
+}
+
+// String describes the objective, such as "99.9% over 30 days"
+func (o *SLO) String() string {
+	days := int(o.Window / Day)
+	unit := "days"
+	if days == 1 {
+		unit = "day"
+	}
+	return fmt.Sprintf("%s%% over %d %s", strconv.FormatFloat(o.Objective, 'f', -1, 64), days, unit)
+}
+
+// ErrorRate is the share of measured time the objective allows the target to
+// be down
+func (o *SLO) ErrorRate() float64 {
+	return 1 - o.Objective/100
+}
+
+// BurnRate is how many times faster than the objective allows the target
+// spent its error budget over a period. It is false when nothing was measured.
+func (o *SLO) BurnRate(availability Availability) (float64, bool) {
+	ratio, ok := availability.Ratio()
+	if !ok {
+		return 0, false
+	}
+	return (1 - ratio) / o.ErrorRate(), true
+}
+
+// Budget is the error budget of an objective over its window, sized by the
+// time the target was measured
+func (o *SLO) Budget(availability Availability) Budget {
+	return Budget{
+		Allowed: time.Duration(float64(availability.Measured()) * o.ErrorRate()),
+		Spent:   availability.Down,
+	}
+}
+
+// Breaks reports whether the budget burns at least as fast as the rule
+// allows over both its long and its short lookback
+func (o *SLO) Breaks(rule BurnRule, long, short Availability) bool {
+	threshold := rule.Threshold(o.Window)
+	longRate, ok := o.BurnRate(long)
+	if !ok || longRate < threshold {
+		return false
+	}
+	shortRate, ok := o.BurnRate(short)
+	return ok && shortRate >= threshold
+}
+
+// Budget is the downtime an objective allows and how much of it is spent
+type Budget struct {
+	Allowed time.Duration
+	Spent   time.Duration
+}
+
+// Remaining is the downtime left, negative once the budget is exhausted
+func (b Budget) Remaining() time.Duration {
+	return b.Allowed - b.Spent
+}
+
+// RemainingRatio is the share of the budget left, negative once it is
+// exhausted. A budget that allows nothing is whole until something is spent.
+func (b Budget) RemainingRatio() float64 {
+	if b.Allowed <= 0 {
+		if b.Spent > 0 {
+			return -1
+		}
+		return 1
+	}
+	return float64(b.Remaining()) / float64(b.Allowed)
+}
+
+// BurnRule raises a burn rate alert when a share of the error budget is spent
+// within its long lookback. The budget must still burn that fast over the
+// short lookback, so that the alert stops soon after the target recovers.
+type BurnRule struct {
+	Level string
+	Spent float64 // share of the error budget of the window
+	Long  time.Duration
+	Short time.Duration
+}
+
+// BurnRules are checked in order, from the fast burn of an outage to the slow
+// burn of a target that keeps failing now and then
+var BurnRules = []BurnRule{
+	{Level: AlertFast, Spent: 0.02, Long: time.Hour, Short: 5 * time.Minute},
+	{Level: AlertSlow, Spent: 0.05, Long: 6 * time.Hour, Short: 30 * time.Minute},
+}
+
+// Threshold is the burn rate that spends the rule's share of the budget of a
+// window within the long lookback. It is never below 1, as burning at the
+// rate the objective allows is no cause for alarm.
+func (r BurnRule) Threshold(window time.Duration) float64 {
+	return max(1, r.Spent*float64(window)/float64(r.Long))
+}
+
+// Escalates reports whether an alert of the given level is more urgent than
+// the current one, which is empty when no alert was sent
+func Escalates(current, level string) bool {
+	index := func(level string) int {
+		return slices.IndexFunc(BurnRules, func(rule BurnRule) bool { return rule.Level == level })
+	}
+	next := index(level)
+	if next < 0 {
+		return false
+	}
+	previous := index(current)
+	return previous < 0 || next < previous
+}
+
+// SLOStatus is how an SLO stands: the availability of its target over the
+// window, the error budget left and how fast it burns over the last hour
+type SLOStatus struct {
+	SLO          *SLO
+	Availability Availability
+	Budget       Budget
+	BurnRate     float64
+	HasBurnRate  bool // false when nothing was measured over the last hour
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSLO() *SLO {
+	return &SLO{ID: 1, TargetID: 1, Objective: 99.9, Window: 30 * Day}
+}
+
+func TestSLO_Validate(t *testing.T) {
+	assert.NoError(t, testSLO().Validate())
+
+	invalid := []func(o *SLO){
+		func(o *SLO) { o.TargetID = 0 },
+		func(o *SLO) { o.Objective = 0 },
+		func(o *SLO) { o.Objective = 100 },
+		func(o *SLO) { o.Window = 12 * time.Hour },
+		func(o *SLO) { o.Window = 36 * time.Hour },
+		func(o *SLO) { o.Window = 91 * Day },
+	}
+	for _, mutate := range invalid {
+		slo := testSLO()
+		mutate(slo)
+		assert.Error(t, slo.Validate(), "%+v", slo)
+	}
+}
+
+func TestSLO_String(t *testing.T) {
+	assert.Equal(t, "99.9% over 30 days", testSLO().String())
+	assert.Equal(t, "95% over 1 day", (&SLO{Objective: 95, Window: Day}).String())
+}
+
+func TestSLO_Budget(t *testing.T) {
+	slo := testSLO()
+	availability := Availability{Up: 30*Day - 30*time.Minute, Down: 30 * time.Minute, Excluded: time.Hour}
+
+	budget := slo.Budget(availability)
+	assert.InDelta(t, float64(43*time.Minute+12*time.Second), float64(budget.Allowed), float64(time.Millisecond))
+	assert.Equal(t, 30*time.Minute, budget.Spent)
+	assert.InDelta(t, float64(13*time.Minute+12*time.Second), float64(budget.Remaining()), float64(time.Millisecond))
+	assert.InDelta(t, 0.3056, budget.RemainingRatio(), 1e-3)
+
+	exhausted := Budget{Allowed: time.Minute, Spent: 3 * time.Minute}
+	assert.Equal(t, -2.0, exhausted.RemainingRatio())
+	assert.Equal(t, 1.0, Budget{}.RemainingRatio())
+	assert.Equal(t, -1.0, Budget{Spent: time.Second}.RemainingRatio())
+}
+
+func TestSLO_BurnRate(t *testing.T) {
+	slo := testSLO()
+
+	rate, ok := slo.BurnRate(Availability{Up: 59 * time.Minute, Down: time.Minute})
+	assert.True(t, ok)
+	assert.InDelta(t, 16.67, rate, 0.01)
+
+	_, ok = slo.BurnRate(Availability{Excluded: time.Hour})
+	assert.False(t, ok)
+}
+
+func TestSLO_Breaks(t *testing.T) {
+	slo := testSLO()
+	fast := BurnRules[0]
+	assert.InDelta(t, 14.4, fast.Threshold(slo.Window), 1e-9)
+	assert.InDelta(t, 6.0, BurnRules[1].Threshold(slo.Window), 1e-9)
+	assert.Equal(t, 1.0, fast.Threshold(Day), "burning at the allowed rate never alerts")
+
+	outage := Availability{Up: 55 * time.Minute, Down: 5 * time.Minute}
+	down := Availability{Down: 5 * time.Minute}
+	recovered := Availability{Up: 5 * time.Minute}
+	assert.True(t, slo.Breaks(fast, outage, down))
+	assert.False(t, slo.Breaks(fast, outage, recovered), "the short lookback ends the alert")
+	assert.False(t, slo.Breaks(fast, Availability{Up: 59 * time.Minute, Down: 30 * time.Second}, down))
+	assert.False(t, slo.Breaks(fast, Availability{}, down))
+}
+
+func TestEscalates(t *testing.T) {
+	assert.True(t, Escalates("", AlertSlow))
+	assert.True(t, Escalates("", AlertFast))
+	assert.True(t, Escalates(AlertSlow, AlertFast))
+	assert.False(t, Escalates(AlertFast, AlertSlow))
+	assert.False(t, Escalates(AlertFast, AlertFast))
+	assert.False(t, Escalates(AlertSlow, ""))
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+)
+
+var (
+	ErrTargetNotFound = errors.New("target not found")
+	ErrSLONotFound    = errors.New("slo not found")
+)
+
+type SLARepositoryInterface interface {
+	GetTarget(ctx context.Context, id int) (*model.Target, error)
+	GetSamples(ctx context.Context, targetID int, from, to time.Time) ([]model.Sample, error)
+	Create(ctx context.Context, slo *model.SLO) (*model.SLO, error)
+	Get(ctx context.Context, id int) (*model.SLO, error)
+	GetByTargetID(ctx context.Context, targetID int) ([]*model.SLO, error)
+	GetAll(ctx context.Context) ([]*model.SLO, error)
+	Delete(ctx context.Context, id int) error
+	SetAlert(ctx context.Context, id int, level string, at *time.Time) error
+}
+
+var _ SLARepositoryInterface = (*SLARepository)(nil)
+
+// SLARepository handles database operations for SLOs, and reads the check
+// history availability is computed from
+type SLARepository struct {
+	db database.Querier
+}
+
+// NewSLARepository creates a new SLA repository
+func NewSLARepository(db database.Querier) *SLARepository {
+	return &SLARepository{db: db}
+}
+
+func (r *SLARepository) GetTarget(ctx context.Context, id int) (*model.Target, error) {
+	target := &model.Target{}
+	var intervalSeconds float64
+	err := r.db.QueryRowContext(ctx, `SELECT id, user_id, url, interval FROM target WHERE id = $1`, id).Scan(
+		&target.ID,
+		&target.UserID,
+		&target.URL,
+		&intervalSeconds,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+	target.Interval = time.Duration(intervalSeconds) * time.Second
+	return target, nil
+}
+
+// GetSamples lists the checks of a target made within [from, to), oldest first
+func (r *SLARepository) GetSamples(ctx context.Context, targetID int, from, to time.Time) ([]model.Sample, error) {
+	query := `
+		SELECT status, checked_at
+		FROM check_result
+		WHERE target_id = $1 AND checked_at >= $2::timestamp AND checked_at < $3::timestamp
+		ORDER BY checked_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, targetID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check results: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []model.Sample
+	for rows.Next() {
+		var sample model.Sample
+		if err := rows.Scan(&sample.Status, &sample.CheckedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan check result: %w", err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating check results: %w", err)
+	}
+	return samples, nil
+}
+
+const sloColumns = `id, target_id, objective, window_days, alert_level, alerted_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSLO(row rowScanner) (*model.SLO, error) {
+	slo := &model.SLO{}
+	var days int
+	var alertedAt sql.NullTime
+	err := row.Scan(&slo.ID, &slo.TargetID, &slo.Objective, &days, &slo.AlertLevel, &alertedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	slo.Window = time.Duration(days) * model.Day
+	if alertedAt.Valid {
+		slo.AlertedAt = &alertedAt.Time
+	}
+	return slo, nil
+}
+
+func (r *SLARepository) Create(ctx context.Context, slo *model.SLO) (*model.SLO, error) {
+	query := `
+		INSERT INTO slo (target_id, objective, window_days)
+		VALUES ($1, $2, $3)
+		RETURNING ` + sloColumns
+
+	created, err := scanSLO(r.db.QueryRowContext(ctx, query, slo.TargetID, slo.Objective, int(slo.Window/model.Day)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create slo: %w", err)
+	}
+	return created, nil
+}
+
+func (r *SLARepository) Get(ctx context.Context, id int) (*model.SLO, error) {
+	slo, err := scanSLO(r.db.QueryRowContext(ctx, `SELECT `+sloColumns+` FROM slo WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrSLONotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slo: %w", err)
+	}
+	return slo, nil
+}
+
+func (r *SLARepository) list(ctx context.Context, query string, args ...any) ([]*model.SLO, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slos: %w", err)
+	}
+	defer rows.Close()
+
+	var slos []*model.SLO
+	for rows.Next() {
+		slo, err := scanSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan slo: %w", err)
+		}
+		slos = append(slos, slo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating slos: %w", err)
+	}
+	return slos, nil
+}
+
+func (r *SLARepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.SLO, error) {
+	return r.list(ctx, `SELECT `+sloColumns+` FROM slo WHERE target_id = $1 ORDER BY objective DESC, window_days, id`, targetID)
+}
+
+// GetAll lists the SLOs of enabled targets, which burn rate alerts are
+// evaluated for
+func (r *SLARepository) GetAll(ctx context.Context) ([]*model.SLO, error) {
+	query := `
+		SELECT ` + sloColumns + `
+		FROM slo
+		WHERE target_id IN (SELECT id FROM target WHERE enabled)
+		ORDER BY target_id, id
+	`
+	return r.list(ctx, query)
+}
+
+func (r *SLARepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM slo WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete slo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSLONotFound
+	}
+	return nil
+}
+
+// SetAlert records the burn rate alert last sent for an SLO. An empty level
+// with a nil time clears it.
+func (r *SLARepository) SetAlert(ctx context.Context, id int, level string, at *time.Time) error {
+	var alertedAt *time.Time
+	if at != nil {
+		utc := at.UTC()
+		alertedAt = &utc
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE slo SET alert_level = $1, alerted_at = $2 WHERE id = $3`, level, alertedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update slo alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSLONotFound
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLARepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewSLARepository(tx)
+	targets := monitorRepo.NewTargetRepository(tx)
+
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	create := func(url string, enabled bool) monitorModel.UserTarget {
+		target, err := targets.Create(context.Background(), monitorModel.UserTarget{
+			UserID: user.ID,
+			Target: &core.Target{URL: url, Status: "up", Enabled: enabled, Interval: 30 * time.Second, StatusChangedAt: time.Now()},
+		})
+		assert.NoError(t, err)
+		return target
+	}
+	target := create("https://example.org", true)
+	disabled := create("https://old.example.org", false)
+
+	stored, err := repo.GetTarget(context.Background(), target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, 30*time.Second, stored.Interval)
+	_, err = repo.GetTarget(context.Background(), disabled.ID+1)
+	assert.ErrorIs(t, err, ErrTargetNotFound)
+
+	start := time.Date(2025, 5, 7, 12, 0, 0, 0, time.UTC)
+	for i, status := range []string{"up", "down", "maintenance", "up"} {
+		assert.NoError(t, targets.SaveResult(context.Background(), target.ID, core.Result{Status: status, CheckedAt: start.Add(time.Duration(i) * time.Minute)}))
+	}
+	samples, err := repo.GetSamples(context.Background(), target.ID, start.Add(time.Minute), start.Add(3*time.Minute))
+	assert.NoError(t, err)
+	if assert.Len(t, samples, 2) {
+		assert.Equal(t, "down", samples[0].Status)
+		assert.True(t, samples[1].CheckedAt.Equal(start.Add(2*time.Minute)))
+	}
+
+	slo, err := repo.Create(context.Background(), &model.SLO{TargetID: target.ID, Objective: 99.9, Window: 30 * model.Day})
+	assert.NoError(t, err)
+	assert.NotZero(t, slo.ID)
+	assert.Equal(t, 30*model.Day, slo.Window)
+	_, err = repo.Create(context.Background(), &model.SLO{TargetID: disabled.ID, Objective: 99, Window: 7 * model.Day})
+	assert.NoError(t, err)
+
+	slos, err := repo.GetByTargetID(context.Background(), target.ID)
+	assert.NoError(t, err)
+	assert.Len(t, slos, 1)
+	slos, err = repo.GetAll(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, slos, 1, "SLOs of disabled targets are not evaluated") {
+		assert.Equal(t, slo.ID, slos[0].ID)
+	}
+
+	alertedAt := start.Add(time.Hour)
+	assert.NoError(t, repo.SetAlert(context.Background(), slo.ID, model.AlertFast, &alertedAt))
+	alerted, err := repo.Get(context.Background(), slo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.AlertFast, alerted.AlertLevel)
+	assert.True(t, alertedAt.Equal(*alerted.AlertedAt))
+
+	assert.NoError(t, repo.SetAlert(context.Background(), slo.ID, "", nil))
+	alerted, err = repo.Get(context.Background(), slo.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, alerted.AlertLevel)
+	assert.Nil(t, alerted.AlertedAt)
+
+	assert.NoError(t, repo.Delete(context.Background(), slo.ID))
+	_, err = repo.Get(context.Background(), slo.ID)
+	assert.ErrorIs(t, err, ErrSLONotFound)
+	assert.ErrorIs(t, repo.Delete(context.Background(), slo.ID), ErrSLONotFound)
+	assert.ErrorIs(t, repo.SetAlert(context.Background(), slo.ID, "", nil), ErrSLONotFound)
+}
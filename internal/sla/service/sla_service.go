@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/repository"
+)
+
+// Common errors returned by the SLA service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a target they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to target")
+	// ErrTargetNotFound is returned when the requested target does not exist.
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrSLONotFound is returned when the requested SLO does not exist.
+	ErrSLONotFound = errors.New("slo not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+)
+
+// MaxRange bounds the periods availability can be asked for over
+const MaxRange = 366 * model.Day
+
+// NotifierService is the part of the notifier service that burn rate alerts rely on
+type NotifierService interface {
+	GetByTargetID(ctx context.Context, targetID int, userID int) ([]*notifModel.Notifier, error)
+	NotifyByIDs(ctx context.Context, ids []int, state notifCore.State) error
+}
+
+type SLAServiceInterface interface {
+	GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error)
+	Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (model.Availability, error)
+	GetSLOs(ctx context.Context, targetID int, userID int) ([]*model.SLOStatus, error)
+	CreateSLO(ctx context.Context, slo *model.SLO, userID int) error
+	DeleteSLO(ctx context.Context, id int, userID int) error
+}
+
+var _ SLAServiceInterface = (*SLAService)(nil)
+
+// SLAService computes the availability of targets from their check history,
+// and alerts when the error budget of an SLO burns too fast
+type SLAService struct {
+	repo            repository.SLARepositoryInterface
+	notifierService NotifierService
+	baseURL         string // prefixes the dashboard links added to notifications
+	now             func() time.Time
+}
+
+func NewSLAService(repo repository.SLARepositoryInterface, notifierService NotifierService, baseURL string) *SLAService {
+	return &SLAService{
+		repo:            repo,
+		notifierService: notifierService,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		now:             time.Now,
+	}
+}
+
+// GetTarget retrieves a target after verifying the user owns it
+func (s *SLAService) GetTarget(ctx context.Context, targetID int, userID int) (*model.Target, error) {
+	if targetID <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid targetID or userID", ErrInvalidInput)
+	}
+
+	target, err := s.repo.GetTarget(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			return nil, fmt.Errorf("%w: target with id %d not found", ErrTargetNotFound, targetID)
+		}
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	if target.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return target, nil
+}
+
+// samples reads the checks of a target that count towards [from, to),
+// including those made shortly before from
+func (s *SLAService) samples(ctx context.Context, target *model.Target, from, to time.Time) ([]model.Sample, error) {
+	samples, err := s.repo.GetSamples(ctx, target.ID, from.Add(-target.MaxGap()), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check results: %w", err)
+	}
+	return samples, nil
+}
+
+// Availability computes the availability of a target over [from, to). Time
+// spent in maintenance windows is excluded.
+func (s *SLAService) Availability(ctx context.Context, targetID int, userID int, from, to time.Time) (model.Availability, error) {
+	if !to.After(from) {
+		return model.Availability{}, fmt.Errorf("%w: the period must end after it starts", ErrInvalidInput)
+	}
+	if to.Sub(from) > MaxRange {
+		return model.Availability{}, fmt.Errorf("%w: the period must not be longer than %d days", ErrInvalidInput, MaxRange/model.Day)
+	}
+
+	target, err := s.GetTarget(ctx, targetID, userID)
+	if err != nil {
+		return model.Availability{}, err
+	}
+
+	samples, err := s.samples(ctx, target, from, to)
+	if err != nil {
+		return model.Availability{}, err
+	}
+	return model.Compute(samples, from, to, target.MaxGap()), nil
+}
+
+// DailyAvailability computes the availability of a target over each of the
+// given number of days starting at from, reading its checks once
+func (s *SLAService) DailyAvailability(ctx context.Context, targetID int, userID int, from time.Time, days int) ([]model.Availability, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("%w: the number of days must be positive", ErrInvalidInput)
+	}
+	to := from.Add(time.Duration(days) * model.Day)
+	if to.Sub(from) > MaxRange {
+		return nil, fmt.Errorf("%w: the period must not be longer than %d days", ErrInvalidInput, MaxRange/model.Day)
+	}
+
+	target, err := s.GetTarget(ctx, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := s.samples(ctx, target, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return model.ComputeDays(samples, from, days, target.MaxGap()), nil
+}
+
+// GetSLOs lists the SLOs of a target, each with its error budget over its
+// window and how fast it burns now
+func (s *SLAService) GetSLOs(ctx context.Context, targetID int, userID int) ([]*model.SLOStatus, error) {
+	target, err := s.GetTarget(ctx, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slos, err := s.repo.GetByTargetID(ctx, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch slos: %w", err)
+	}
+	if len(slos) == 0 {
+		return nil, nil
+	}
+
+	// One read covers the longest window, which the others fall within
+	now := s.now()
+	longest := time.Hour
+	for _, slo := range slos {
+		longest = max(longest, slo.Window)
+	}
+	samples, err := s.samples(ctx, target, now.Add(-longest), now)
+	if err != nil {
+		return nil, err
+	}
+
+	lastHour := model.Compute(samples, now.Add(-time.Hour), now, target.MaxGap())
+	statuses := make([]*model.SLOStatus, len(slos))
+	for i, slo := range slos {
+		availability := model.Compute(samples, now.Add(-slo.Window), now, target.MaxGap())
+		status := &model.SLOStatus{SLO: slo, Availability: availability, Budget: slo.Budget(availability)}
+		status.BurnRate, status.HasBurnRate = slo.BurnRate(lastHour)
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+func (s *SLAService) CreateSLO(ctx context.Context, slo *model.SLO, userID int) error {
+	if _, err := s.GetTarget(ctx, slo.TargetID, userID); err != nil {
+		return err
+	}
+
+	if err := slo.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	created, err := s.repo.Create(ctx, slo)
+	if err != nil {
+		return fmt.Errorf("failed to create slo: %w", err)
+	}
+	slo.ID = created.ID
+	return nil
+}
+
+func (s *SLAService) DeleteSLO(ctx context.Context, id int, userID int) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid id", ErrInvalidInput)
+	}
+
+	slo, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSLONotFound) {
+			return fmt.Errorf("%w: slo with id %d not found", ErrSLONotFound, id)
+		}
+		return fmt.Errorf("failed to fetch slo: %w", err)
+	}
+	if _, err := s.GetTarget(ctx, slo.TargetID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrSLONotFound) {
+			return fmt.Errorf("%w: slo with id %d not found", ErrSLONotFound, id)
+		}
+		return fmt.Errorf("failed to delete slo: %w", err)
+	}
+	return nil
+}
+
+// Evaluate checks the burn rate of every SLO against the burn rules, and
+// alerts the notifiers of its target when the budget starts burning faster
+// than a rule allows. An alert is sent once per level, and a faster burn
+// escalates a slow burn alert; the level is cleared once no rule is broken.
+func (s *SLAService) Evaluate(ctx context.Context) error {
+	slos, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get slos: %w", err)
+	}
+
+	var lookback time.Duration
+	for _, rule := range model.BurnRules {
+		lookback = max(lookback, rule.Long)
+	}
+
+	now := s.now()
+	type history struct {
+		target  *model.Target
+		samples []model.Sample
+	}
+	histories := make(map[int]*history)
+	var errs []error
+	for _, slo := range slos {
+		h, ok := histories[slo.TargetID]
+		if !ok {
+			target, err := s.repo.GetTarget(ctx, slo.TargetID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get target %d: %w", slo.TargetID, err))
+				continue
+			}
+			samples, err := s.samples(ctx, target, now.Add(-lookback), now)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			h = &history{target: target, samples: samples}
+			histories[slo.TargetID] = h
+		}
+
+		var broken *model.BurnRule
+		for _, rule := range model.BurnRules {
+			long := model.Compute(h.samples, now.Add(-rule.Long), now, h.target.MaxGap())
+			short := model.Compute(h.samples, now.Add(-rule.Short), now, h.target.MaxGap())
+			if slo.Breaks(rule, long, short) {
+				broken = &rule
+				break
+			}
+		}
+
+		switch {
+		case broken != nil && model.Escalates(slo.AlertLevel, broken.Level):
+			if err := s.alert(ctx, h.target, slo, *broken, now); err != nil {
+				errs = append(errs, err)
+			}
+			// Recorded even when a delivery failed, so a broken notifier
+			// doesn't alert everyone else again on the next evaluation
+			if err := s.repo.SetAlert(ctx, slo.ID, broken.Level, &now); err != nil {
+				errs = append(errs, err)
+			}
+		case broken == nil && slo.AlertLevel != "":
+			if err := s.repo.SetAlert(ctx, slo.ID, "", nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// alert tells the notifiers of a target that the error budget of an SLO
+// burns faster than a rule allows
+func (s *SLAService) alert(ctx context.Context, target *model.Target, slo *model.SLO, rule model.BurnRule, now time.Time) error {
+	samples, err := s.samples(ctx, target, now.Add(-slo.Window), now)
+	if err != nil {
+		return err
+	}
+	budget := slo.Budget(model.Compute(samples, now.Add(-slo.Window), now, target.MaxGap()))
+	rate, _ := slo.BurnRate(model.Compute(samples, now.Add(-rule.Long), now, target.MaxGap()))
+
+	notifiers, err := s.notifierService.GetByTargetID(ctx, target.ID, target.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get notifiers of target %d: %w", target.ID, err)
+	}
+	ids := make([]int, len(notifiers))
+	for i, notifier := range notifiers {
+		ids[i] = notifier.ID
+	}
+
+	state := notifCore.State{
+		Name:      target.URL,
+		URL:       target.URL,
+		TargetID:  target.ID,
+		Status:    notifModel.EventBurnRate,
+		UpdatedAt: now,
+		Link:      fmt.Sprintf("%s/app/targets/sla/%d", s.baseURL, target.ID),
+		Message: fmt.Sprintf("The error budget of %s for %s is burning %.1f times faster than allowed over the last %s (%s burn). %.0f%% of the budget is left.",
+			target.URL, slo, rate, strings.TrimSuffix(rule.Long.String(), "0m0s"), rule.Level, budget.RemainingRatio()*100),
+	}
+	if err := s.notifierService.NotifyByIDs(ctx, ids, state); err != nil {
+		return fmt.Errorf("failed to alert on slo %d: %w", slo.ID, err)
+	}
+	return nil
+}
+
+// Start evaluates burn rates in the background at the given interval
+func (s *SLAService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.Evaluate(context.Background()); err != nil {
+				slog.Error("Failed to evaluate SLO burn rates", "error", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	notifModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/sla/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSLARepository struct {
+	samples         []model.Sample
+	slos            map[int]*model.SLO
+	createFunc      func(slo *model.SLO) (*model.SLO, error)
+	deleteFunc      func(id int) error
+	setAlertFunc    func(id int, level string, at *time.Time) error
+	getSamplesCalls int
+}
+
+func (m *mockSLARepository) GetTarget(ctx context.Context, id int) (*model.Target, error) {
+	if id != 1 {
+		return nil, repository.ErrTargetNotFound
+	}
+	return &model.Target{ID: 1, UserID: 1, URL: "https://example.org", Interval: time.Minute}, nil
+}
+
+func (m *mockSLARepository) GetSamples(ctx context.Context, targetID int, from, to time.Time) ([]model.Sample, error) {
+	m.getSamplesCalls++
+	var samples []model.Sample
+	for _, sample := range m.samples {
+		if !sample.CheckedAt.Before(from) && sample.CheckedAt.Before(to) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+func (m *mockSLARepository) Create(ctx context.Context, slo *model.SLO) (*model.SLO, error) {
+	return m.createFunc(slo)
+}
+
+func (m *mockSLARepository) Get(ctx context.Context, id int) (*model.SLO, error) {
+	slo, ok := m.slos[id]
+	if !ok {
+		return nil, repository.ErrSLONotFound
+	}
+	return slo, nil
+}
+
+func (m *mockSLARepository) GetByTargetID(ctx context.Context, targetID int) ([]*model.SLO, error) {
+	var slos []*model.SLO
+	for _, slo := range m.slos {
+		if slo.TargetID == targetID {
+			slos = append(slos, slo)
+		}
+	}
+	return slos, nil
+}
+
+func (m *mockSLARepository) GetAll(ctx context.Context) ([]*model.SLO, error) {
+	return m.GetByTargetID(ctx, 1)
+}
+
+func (m *mockSLARepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockSLARepository) SetAlert(ctx context.Context, id int, level string, at *time.Time) error {
+	return m.setAlertFunc(id, level, at)
+}
+
+type mockNotifierService struct {
+	notified []notifCore.State
+	ids      [][]int
+}
+
+func (m *mockNotifierService) GetByTargetID(ctx context.Context, targetID int, userID int) ([]*notifModel.Notifier, error) {
+	return []*notifModel.Notifier{{ID: 4, UserID: userID}, {ID: 5, UserID: userID}}, nil
+}
+
+func (m *mockNotifierService) NotifyByIDs(ctx context.Context, ids []int, state notifCore.State) error {
+	m.ids = append(m.ids, ids)
+	m.notified = append(m.notified, state)
+	return nil
+}
+
+var testNow = time.Date(2025, 5, 7, 12, 0, 0, 0, time.UTC)
+
+// checks are checks made every minute over [from, to), all finding the status
+func checks(from, to time.Time, status string) []model.Sample {
+	var samples []model.Sample
+	for at := from; at.Before(to); at = at.Add(time.Minute) {
+		samples = append(samples, model.Sample{Status: status, CheckedAt: at})
+	}
+	return samples
+}
+
+func newTestService(repo *mockSLARepository) (*SLAService, *mockNotifierService) {
+	notifier := &mockNotifierService{}
+	service := NewSLAService(repo, notifier, "https://uptime.example.org/")
+	service.now = func() time.Time { return testNow }
+	return service, notifier
+}
+
+func TestSLAService_GetTarget(t *testing.T) {
+	service, _ := newTestService(&mockSLARepository{})
+
+	target, err := service.GetTarget(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.org", target.URL)
+
+	_, err = service.GetTarget(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.GetTarget(context.Background(), 2, 1)
+	assert.ErrorIs(t, err, ErrTargetNotFound)
+
+	_, err = service.GetTarget(context.Background(), 0, 1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestSLAService_Availability(t *testing.T) {
+	from := testNow.Add(-time.Hour)
+	repo := &mockSLARepository{
+		samples: append(append(
+			checks(from.Add(-2*time.Minute), testNow.Add(-30*time.Minute), "up"),
+			checks(testNow.Add(-30*time.Minute), testNow.Add(-20*time.Minute), "maintenance")...),
+			checks(testNow.Add(-20*time.Minute), testNow, "down")...),
+	}
+	service, _ := newTestService(repo)
+
+	availability, err := service.Availability(context.Background(), 1, 1, from, testNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, availability.Up, "checks made before the period count")
+	assert.Equal(t, 10*time.Minute, availability.Excluded)
+	assert.Equal(t, 20*time.Minute, availability.Down)
+	ratio, _ := availability.Ratio()
+	assert.InDelta(t, 0.6, ratio, 1e-9)
+
+	_, err = service.Availability(context.Background(), 1, 2, from, testNow)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.Availability(context.Background(), 1, 1, testNow, from)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = service.Availability(context.Background(), 1, 1, testNow.Add(-400*model.Day), testNow)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestSLAService_DailyAvailability(t *testing.T) {
+	from := testNow.Truncate(model.Day).Add(-model.Day)
+	repo := &mockSLARepository{
+		samples: append(
+			checks(from.Add(-2*time.Minute), from.Add(12*time.Hour), "up"),
+			checks(from.Add(12*time.Hour), from.Add(13*time.Hour), "down")...),
+	}
+	service, _ := newTestService(repo)
+
+	days, err := service.DailyAvailability(context.Background(), 1, 1, from, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, days, 2) {
+		assert.Equal(t, 12*time.Hour, days[0].Up, "checks made before the first day count")
+		assert.Equal(t, time.Hour+2*time.Minute, days[0].Down, "the last check holds for a few intervals")
+		assert.Zero(t, days[1].Measured())
+	}
+	assert.Equal(t, 1, repo.getSamplesCalls)
+
+	_, err = service.DailyAvailability(context.Background(), 1, 2, from, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.DailyAvailability(context.Background(), 1, 1, from, 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestSLAService_GetSLOs(t *testing.T) {
+	repo := &mockSLARepository{
+		samples: append(
+			checks(testNow.Add(-7*model.Day), testNow.Add(-10*time.Minute), "up"),
+			checks(testNow.Add(-10*time.Minute), testNow, "down")...),
+		slos: map[int]*model.SLO{
+			1: {ID: 1, TargetID: 1, Objective: 99.9, Window: 7 * model.Day},
+		},
+	}
+	service, _ := newTestService(repo)
+
+	statuses, err := service.GetSLOs(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, statuses, 1) {
+		status := statuses[0]
+		assert.Equal(t, 10*time.Minute, status.Budget.Spent)
+		assert.InDelta(t, float64(604800*time.Millisecond), float64(status.Budget.Allowed), float64(time.Millisecond))
+		assert.True(t, status.HasBurnRate)
+		assert.InDelta(t, 166.67, status.BurnRate, 0.01)
+	}
+	assert.Equal(t, 1, repo.getSamplesCalls, "one read covers every SLO")
+
+	_, err = service.GetSLOs(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestSLAService_CreateSLO(t *testing.T) {
+	var created *model.SLO
+	repo := &mockSLARepository{
+		createFunc: func(slo *model.SLO) (*model.SLO, error) {
+			created = slo
+			stored := *slo
+			stored.ID = 3
+			return &stored, nil
+		},
+	}
+	service, _ := newTestService(repo)
+
+	slo := &model.SLO{TargetID: 1, Objective: 99.5, Window: 30 * model.Day}
+	assert.NoError(t, service.CreateSLO(context.Background(), slo, 1))
+	assert.Equal(t, 3, slo.ID)
+	assert.Equal(t, 99.5, created.Objective)
+
+	assert.ErrorIs(t, service.CreateSLO(context.Background(), &model.SLO{TargetID: 1, Objective: 100, Window: model.Day}, 1), ErrInvalidInput)
+	assert.ErrorIs(t, service.CreateSLO(context.Background(), &model.SLO{TargetID: 1, Objective: 99, Window: model.Day}, 2), ErrUnauthorized)
+}
+
+func TestSLAService_DeleteSLO(t *testing.T) {
+	deleted := 0
+	repo := &mockSLARepository{
+		slos:       map[int]*model.SLO{2: {ID: 2, TargetID: 1, Objective: 99, Window: model.Day}},
+		deleteFunc: func(id int) error { deleted = id; return nil },
+	}
+	service, _ := newTestService(repo)
+
+	assert.ErrorIs(t, service.DeleteSLO(context.Background(), 2, 2), ErrUnauthorized)
+	assert.Zero(t, deleted)
+	assert.ErrorIs(t, service.DeleteSLO(context.Background(), 3, 1), ErrSLONotFound)
+
+	assert.NoError(t, service.DeleteSLO(context.Background(), 2, 1))
+	assert.Equal(t, 2, deleted)
+}
+
+func TestSLAService_Evaluate(t *testing.T) {
+	slo := &model.SLO{ID: 1, TargetID: 1, Objective: 99.9, Window: 30 * model.Day}
+	repo := &mockSLARepository{
+		samples: append(
+			checks(testNow.Add(-30*model.Day), testNow.Add(-10*time.Minute), "up"),
+			checks(testNow.Add(-10*time.Minute), testNow, "down")...),
+		slos: map[int]*model.SLO{1: slo},
+		setAlertFunc: func(id int, level string, at *time.Time) error {
+			slo.AlertLevel, slo.AlertedAt = level, at
+			return nil
+		},
+	}
+	service, notifier := newTestService(repo)
+
+	assert.NoError(t, service.Evaluate(context.Background()))
+	assert.Equal(t, model.AlertFast, slo.AlertLevel)
+	if assert.Len(t, notifier.notified, 1) {
+		state := notifier.notified[0]
+		assert.Equal(t, []int{4, 5}, notifier.ids[0])
+		assert.Equal(t, notifModel.EventBurnRate, state.Status)
+		assert.Equal(t, 1, state.TargetID)
+		assert.Equal(t, "https://uptime.example.org/app/targets/sla/1", state.Link)
+		assert.Contains(t, state.Message, "99.9% over 30 days is burning 166.7 times faster than allowed over the last 1h (fast burn)")
+		assert.Contains(t, state.Message, "77% of the budget is left")
+	}
+
+	t.Run("alerts once per level", func(t *testing.T) {
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Len(t, notifier.notified, 1)
+	})
+
+	t.Run("clears once the target recovers", func(t *testing.T) {
+		// The six hour lookback still burns too fast, but not the last
+		// thirty minutes
+		repo.samples = append(repo.samples, checks(testNow, testNow.Add(time.Hour), "up")...)
+		service.now = func() time.Time { return testNow.Add(time.Hour) }
+
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Empty(t, slo.AlertLevel)
+		assert.Nil(t, slo.AlertedAt)
+		assert.Len(t, notifier.notified, 1)
+	})
+
+	t.Run("a slow burn alerts", func(t *testing.T) {
+		// Down one minute in every hour, the last time twenty minutes ago:
+		// burning about 17 times faster than allowed is above the slow burn
+		// threshold of 6, and above the fast burn threshold of 14.4 over
+		// the last hour, but not over the last five minutes
+		now := testNow.Add(8 * time.Hour)
+		var samples []model.Sample
+		for at := testNow.Add(time.Hour); at.Before(now); at = at.Add(time.Minute) {
+			status := "up"
+			if at.Minute() == 40 {
+				status = "down"
+			}
+			samples = append(samples, model.Sample{Status: status, CheckedAt: at})
+		}
+		repo.samples = append(repo.samples, samples...)
+		service.now = func() time.Time { return now }
+
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Equal(t, model.AlertSlow, slo.AlertLevel)
+		if assert.Len(t, notifier.notified, 2) {
+			assert.Contains(t, notifier.notified[1].Message, "over the last 6h (slow burn)")
+		}
+
+		// A fast burn escalates the slow burn alert
+		repo.samples = append(repo.samples, checks(now, now.Add(5*time.Minute), "down")...)
+		service.now = func() time.Time { return now.Add(5 * time.Minute) }
+		assert.NoError(t, service.Evaluate(context.Background()))
+		assert.Equal(t, model.AlertFast, slo.AlertLevel)
+		assert.Len(t, notifier.notified, 3)
+	})
+}
+
+func TestSLAService_Evaluate_UnreachableDependency(t *testing.T) {
+	// The parent of the target is down, so its own checks cannot tell
+	slo := &model.SLO{ID: 1, TargetID: 1, Objective: 99.9, Window: 30 * model.Day}
+	repo := &mockSLARepository{
+		samples: append(
+			checks(testNow.Add(-30*model.Day), testNow.Add(-10*time.Minute), "up"),
+			checks(testNow.Add(-10*time.Minute), testNow, "unreachable-dependency")...),
+		slos: map[int]*model.SLO{1: slo},
+		setAlertFunc: func(id int, level string, at *time.Time) error {
+			slo.AlertLevel, slo.AlertedAt = level, at
+			return nil
+		},
+	}
+	service, notifier := newTestService(repo)
+
+	assert.NoError(t, service.Evaluate(context.Background()))
+	assert.Empty(t, slo.AlertLevel)
+	assert.Empty(t, notifier.notified)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+const (
+	// domainCacheTTL is how long the page found for a host, or the lack of
+	// one, is reused before the host is looked up again
+	domainCacheTTL = time.Minute
+	// domainCacheSize bounds how many hosts are kept. Hosts come from
+	// request headers, so unknown ones must not grow the cache for ever.
+	domainCacheSize = 1000
+)
+
+// domainCache remembers which page is served on a host, so that requests
+// for a custom domain do not each query the database
+type domainCache struct {
+	mu      sync.Mutex
+	entries map[string]domainEntry
+	now     func() time.Time
+}
+
+type domainEntry struct {
+	page      *model.StatusPage // nil when no page is served on the host
+	expiresAt time.Time
+}
+
+func newDomainCache() *domainCache {
+	return &domainCache{
+		entries: make(map[string]domainEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached page of a host, and whether the host was cached at all
+func (c *domainCache) get(host string) (*model.StatusPage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[host]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.page, true
+}
+
+// put caches the page of a host, nil for a host without a page
+func (c *domainCache) put(host string, page *model.StatusPage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= domainCacheSize {
+		for cached, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, cached)
+			}
+		}
+	}
+	if len(c.entries) >= domainCacheSize {
+		clear(c.entries)
+	}
+	c.entries[host] = domainEntry{page: page, expiresAt: now.Add(domainCacheTTL)}
+}
+
+// reset forgets every host, once a page changed
+func (c *domainCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
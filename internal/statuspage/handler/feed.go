@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+// Feed formats and the content types they are served with
+var feedTypes = map[string]string{
+	"rss":  "application/rss+xml; charset=utf-8",
+	"atom": "application/atom+xml; charset=utf-8",
+	"json": "application/feed+json; charset=utf-8",
+}
+
+// feed is the format independent content of a page's feed
+type feed struct {
+	Title   string
+	PageURL string
+	FeedURL string
+	Updated time.Time
+	Entries []feedEntry
+}
+
+// feedEntry is a single update of a post
+type feedEntry struct {
+	ID        string
+	Title     string
+	URL       string
+	Content   string
+	Published time.Time
+}
+
+// newFeed turns every update of the posts into an entry, newest first. The
+// feed keeps at most limit entries.
+func newFeed(page *model.StatusPage, posts []*model.Post, pageURL, feedURL string, limit int) *feed {
+	f := &feed{Title: page.Title, PageURL: pageURL, FeedURL: feedURL}
+	for _, post := range posts {
+		for _, update := range post.Updates {
+			f.Entries = append(f.Entries, feedEntry{
+				ID:        fmt.Sprintf("%s#update-%d", pageURL, update.ID),
+				Title:     fmt.Sprintf("%s: %s", update.Status.Label(), post.Title),
+				URL:       pageURL + "#" + post.Anchor(),
+				Content:   update.Message,
+				Published: update.CreatedAt.UTC(),
+			})
+		}
+	}
+	slices.SortStableFunc(f.Entries, func(a, b feedEntry) int {
+		return b.Published.Compare(a.Published)
+	})
+	if len(f.Entries) > limit {
+		f.Entries = f.Entries[:limit]
+	}
+	if len(f.Entries) > 0 {
+		f.Updated = f.Entries[0].Published
+	}
+	return f
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// writeRSS writes the feed as RSS 2.0
+func writeRSS(w io.Writer, f *feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.PageURL,
+			Description: "Incident and maintenance updates for " + f.Title,
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+	for _, entry := range f.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.URL,
+			Description: entry.Content,
+			GUID:        rssGUID{Value: entry.ID},
+			PubDate:     entry.Published.Format(time.RFC1123Z),
+		})
+	}
+	return writeXML(w, doc)
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Link      atomLink    `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeAtom writes the feed as Atom 1.0
+func writeAtom(w io.Writer, f *feed) error {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0).UTC()
+	}
+	doc := atomDocument{
+		Title:   f.Title,
+		ID:      f.PageURL,
+		Updated: updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.PageURL, Rel: "alternate"},
+			{Href: f.FeedURL, Rel: "self"},
+		},
+	}
+	for _, entry := range f.Entries {
+		published := entry.Published.Format(time.RFC3339)
+		doc.Entries = append(doc.Entries, atomEntry{
+			Title:     entry.Title,
+			ID:        entry.ID,
+			Published: published,
+			Updated:   published,
+			Link:      atomLink{Href: entry.URL, Rel: "alternate"},
+			Content:   atomContent{Type: "text", Value: entry.Content},
+		})
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+}
+
+// writeJSONFeed writes the feed as JSON Feed 1.1
+func writeJSONFeed(w io.Writer, f *feed) error {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.PageURL,
+		FeedURL:     f.FeedURL,
+		Items:       []jsonFeedItem{},
+	}
+	for _, entry := range f.Entries {
+		doc.Items = append(doc.Items, jsonFeedItem{
+			ID:            entry.ID,
+			URL:           entry.URL,
+			Title:         entry.Title,
+			ContentText:   entry.Content,
+			DatePublished: entry.Published.Format(time.RFC3339),
+		})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFeed() *feed {
+	now := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	posts := []*model.Post{
+		{ID: 1, Kind: model.PostIncident, Title: "Elevated API errors", Updates: []model.PostUpdate{
+			{ID: 3, Status: model.PostResolved, Message: "Fixed", CreatedAt: now},
+			{ID: 1, Status: model.PostInvestigating, Message: "Looking into it", CreatedAt: now.Add(-2 * time.Hour)},
+		}},
+		{ID: 2, Kind: model.PostMaintenance, Title: "Database upgrade", Updates: []model.PostUpdate{
+			{ID: 2, Status: model.PostScheduled, Message: "Writes pause <briefly>", CreatedAt: now.Add(-time.Hour)},
+		}},
+	}
+	page := &model.StatusPage{Title: "Acme Status"}
+	return newFeed(page, posts, "https://uptime.example.com/status/acme", "https://uptime.example.com/status/acme/feed.atom", 10)
+}
+
+func TestNewFeed(t *testing.T) {
+	f := testFeed()
+	assert.Len(t, f.Entries, 3)
+	assert.Equal(t, "Resolved: Elevated API errors", f.Entries[0].Title)
+	assert.Equal(t, "Scheduled: Database upgrade", f.Entries[1].Title)
+	assert.Equal(t, "https://uptime.example.com/status/acme#post-2", f.Entries[1].URL)
+	assert.Equal(t, f.Entries[0].Published, f.Updated)
+
+	page := &model.StatusPage{Title: "Acme Status"}
+	limited := newFeed(page, []*model.Post{{Updates: make([]model.PostUpdate, 5)}}, "", "", 2)
+	assert.Len(t, limited.Entries, 2)
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeRSS(&buf, testFeed()))
+
+	var doc rssDocument
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Acme Status", doc.Channel.Title)
+	assert.Len(t, doc.Channel.Items, 3)
+	assert.Equal(t, "Fri, 02 May 2025 09:00:00 +0000", doc.Channel.Items[0].PubDate)
+	assert.Equal(t, "Writes pause <briefly>", doc.Channel.Items[1].Description)
+	assert.False(t, doc.Channel.Items[0].GUID.IsPermaLink)
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeAtom(&buf, testFeed()))
+	assert.Contains(t, buf.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+
+	var doc atomDocument
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2025-05-02T09:00:00Z", doc.Updated)
+	assert.Equal(t, "https://uptime.example.com/status/acme/feed.atom", doc.Links[1].Href)
+	assert.Len(t, doc.Entries, 3)
+	assert.Equal(t, "https://uptime.example.com/status/acme#update-3", doc.Entries[0].ID)
+}
+
+func TestWriteJSONFeed(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeJSONFeed(&buf, testFeed()))
+
+	var doc jsonFeed
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc.Version)
+	assert.Len(t, doc.Items, 3)
+	assert.Equal(t, "Looking into it", doc.Items[2].ContentText)
+
+	// An empty feed still lists its items as an array
+	buf.Reset()
+	assert.NoError(t, writeJSONFeed(&buf, &feed{Title: "Acme Status"}))
+	assert.Contains(t, buf.String(), `"items":[]`)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+// parsePost reads a new post and its first update from the submitted form.
+// Maintenance windows are entered in UTC.
+func parsePost(r *http.Request, pageID int) (*model.Post, *model.PostUpdate, error) {
+	post := &model.Post{
+		PageID: pageID,
+		Kind:   model.PostKind(r.FormValue("kind")),
+		Title:  r.FormValue("title"),
+	}
+	update := &model.PostUpdate{
+		Status:  model.PostStatus(r.FormValue("status")),
+		Message: r.FormValue("message"),
+	}
+
+	if post.Kind == model.PostMaintenance {
+		startsAt, startErr := time.ParseInLocation(dateTimeFormat, r.FormValue("starts_at"), time.UTC)
+		endsAt, endErr := time.ParseInLocation(dateTimeFormat, r.FormValue("ends_at"), time.UTC)
+		if startErr != nil || endErr != nil {
+			return nil, nil, fmt.Errorf("start and end are required for maintenance")
+		}
+		post.StartsAt = &startsAt
+		post.EndsAt = &endsAt
+	}
+	return post, update, nil
+}
+
+// Posts lists the posts of a page and publishes new ones
+func (h *StatusPageHandler) Posts(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	postsURL := fmt.Sprintf("/app/status-pages/posts/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		page, err := h.statusPageService.Get(r.Context(), id, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		posts, err := h.postService.GetByPageID(r.Context(), id, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data := map[string]any{
+			"title":               "status page posts",
+			"page":                page,
+			"posts":               posts,
+			"incidentStatuses":    model.PostIncident.Statuses(),
+			"maintenanceStatuses": model.PostMaintenance.Statuses(),
+		}
+		h.Template.Posts.Render(w, r, data)
+		return
+	}
+
+	post, update, err := parsePost(r, id)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid post: " + err.Error()})
+		http.Redirect(w, r, postsURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := h.postService.Create(r.Context(), post, update, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to publish post: " + err.Error()})
+		http.Redirect(w, r, postsURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Post published successfully"})
+	http.Redirect(w, r, postsURL, http.StatusSeeOther)
+}
+
+// AddPostUpdate posts a new update to a post
+func (h *StatusPageHandler) AddPostUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	postID, err := parseID(r, "postId")
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+	postsURL := fmt.Sprintf("/app/status-pages/posts/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	update := &model.PostUpdate{
+		PostID:  postID,
+		Status:  model.PostStatus(r.FormValue("status")),
+		Message: r.FormValue("message"),
+	}
+	if err := h.postService.AddUpdate(r.Context(), update, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to post update: " + err.Error()})
+		http.Redirect(w, r, postsURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Update posted successfully"})
+	http.Redirect(w, r, postsURL, http.StatusSeeOther)
+}
+
+func (h *StatusPageHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	postID, err := parseID(r, "postId")
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+	postsURL := fmt.Sprintf("/app/status-pages/posts/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.postService.Delete(r.Context(), postID, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to delete post: " + err.Error()})
+		http.Redirect(w, r, postsURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Post deleted successfully"})
+	http.Redirect(w, r, postsURL, http.StatusSeeOther)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusPageHandler_Posts(t *testing.T) {
+	t.Run("lists the posts of the page", func(t *testing.T) {
+		mockService := &MockStatusPageService{
+			getFunc: func(id int, userID int) (*model.StatusPage, error) {
+				return testPage(), nil
+			},
+		}
+		mockPostService := &MockPostService{
+			getByPageIDFunc: func(pageID int, userID int) ([]*model.Post, error) {
+				return []*model.Post{{
+					ID: 5, PageID: pageID, Kind: model.PostIncident, Title: "Elevated API errors", Status: model.PostMonitoring,
+					Updates: []model.PostUpdate{{Status: model.PostMonitoring, Message: "Error rates are back to normal"}},
+				}}, nil
+			},
+		}
+		handler := newTestStatusPageHandler(mockService, mockPostService)
+
+		req := httptest.NewRequest(http.MethodGet, "/app/status-pages/posts/3", nil)
+		req.SetPathValue("id", "3")
+		rr := httptest.NewRecorder()
+		handler.Posts(rr, withUser(req, 1))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		body := rr.Body.String()
+		assert.Contains(t, body, "Elevated API errors")
+		assert.Contains(t, body, "Error rates are back to normal")
+		assert.Contains(t, body, `action="/app/status-pages/posts/3/update/5"`)
+		assert.Contains(t, body, `<option value="monitoring" selected>Monitoring</option>`)
+	})
+
+	t.Run("publishes maintenance", func(t *testing.T) {
+		var created *model.Post
+		var first *model.PostUpdate
+		mockPostService := &MockPostService{
+			createFunc: func(post *model.Post, update *model.PostUpdate, userID int) error {
+				created, first = post, update
+				return nil
+			},
+		}
+		handler := newTestStatusPageHandler(&MockStatusPageService{}, mockPostService)
+
+		form := url.Values{
+			"kind":      {"maintenance"},
+			"status":    {"scheduled"},
+			"title":     {"Database upgrade"},
+			"message":   {"Writes pause for a few minutes"},
+			"starts_at": {"2025-05-02T12:00"},
+			"ends_at":   {"2025-05-02T14:00"},
+		}
+		req := postForm("/app/status-pages/posts/3", form)
+		req.SetPathValue("id", "3")
+		rr := httptest.NewRecorder()
+		handler.Posts(rr, withUser(req, 1))
+
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+		assert.Equal(t, "/app/status-pages/posts/3", rr.Header().Get("Location"))
+		assert.Equal(t, 3, created.PageID)
+		assert.Equal(t, time.Date(2025, 5, 2, 12, 0, 0, 0, time.UTC), *created.StartsAt)
+		assert.Equal(t, model.PostScheduled, first.Status)
+	})
+
+	t.Run("rejects maintenance without a window", func(t *testing.T) {
+		handler := newTestStatusPageHandler(&MockStatusPageService{}, &MockPostService{})
+
+		form := url.Values{"kind": {"maintenance"}, "status": {"scheduled"}, "title": {"Upgrade"}, "message": {"Soon"}}
+		req := postForm("/app/status-pages/posts/3", form)
+		req.SetPathValue("id", "3")
+		rr := httptest.NewRecorder()
+		handler.Posts(rr, withUser(req, 1))
+
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+	})
+}
+
+func TestStatusPageHandler_AddPostUpdate(t *testing.T) {
+	var added *model.PostUpdate
+	mockPostService := &MockPostService{
+		addUpdateFunc: func(update *model.PostUpdate, userID int) error {
+			if userID != 1 {
+				return service.ErrUnauthorized
+			}
+			added = update
+			return nil
+		},
+	}
+	handler := newTestStatusPageHandler(&MockStatusPageService{}, mockPostService)
+
+	req := postForm("/app/status-pages/posts/3/update/5", url.Values{"status": {"resolved"}, "message": {"Fixed"}})
+	req.SetPathValue("id", "3")
+	req.SetPathValue("postId", "5")
+	rr := httptest.NewRecorder()
+	handler.AddPostUpdate(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/app/status-pages/posts/3", rr.Header().Get("Location"))
+	assert.Equal(t, &model.PostUpdate{PostID: 5, Status: model.PostResolved, Message: "Fixed"}, added)
+}
+
+func TestStatusPageHandler_Feed(t *testing.T) {
+	mockService := &MockStatusPageService{
+		getBySlugFunc: func(slug string) (*model.StatusPage, error) {
+			return testPage(), nil
+		},
+	}
+	mockPostService := &MockPostService{
+		feedFunc: func(page *model.StatusPage) ([]*model.Post, error) {
+			return []*model.Post{{
+				ID: 5, Kind: model.PostIncident, Title: "Elevated API errors",
+				Updates: []model.PostUpdate{{ID: 9, Status: model.PostResolved, Message: "Fixed", CreatedAt: time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)}},
+			}}, nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, mockPostService)
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{path: "/status/acme/feed.rss", status: http.StatusOK, contentType: "application/rss+xml; charset=utf-8", contains: "<title>Resolved: Elevated API errors</title>"},
+		{path: "/status/acme/feed.atom", status: http.StatusOK, contentType: "application/atom+xml; charset=utf-8", contains: `<link href="https://uptime.example.com/status/acme#post-5" rel="alternate"></link>`},
+		{path: "/status/acme/feed.json", status: http.StatusOK, contentType: "application/feed+json; charset=utf-8", contains: `"id":"https://uptime.example.com/status/acme#update-9"`},
+		{path: "/status/acme/feed.txt", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.SetPathValue("slug", "acme")
+			rr := httptest.NewRecorder()
+			handler.Feed(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, rr.Header().Get("Content-Type"))
+				assert.Contains(t, rr.Body.String(), tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/service"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const (
+	// maxComponents bounds how many component rows a form is read for
+	maxComponents = 50
+	// blankComponents is how many empty component rows forms offer
+	blankComponents = 2
+	dateTimeFormat  = "2006-01-02T15:04"
+)
+
+type StatusPageHandler struct {
+	statusPageService service.StatusPageServiceInterface
+	postService       service.PostServiceInterface
+	subscriberService service.SubscriberServiceInterface
+	flash             flash.FlashStoreInterface
+	baseURL           string
+	appHost           string // host of the app itself, never looked up as a page domain
+	domains           *domainCache
+	Template          struct {
+		List        *renderer.Template
+		Create      *renderer.Template
+		Edit        *renderer.Template
+		Posts       *renderer.Template
+		Subscribers *renderer.Template
+		Public      *renderer.Template
+		Subscribe   *renderer.Template
+		Notice      *renderer.Template
+	}
+}
+
+func NewStatusPageHandler(
+	statusPageService service.StatusPageServiceInterface,
+	postService service.PostServiceInterface,
+	subscriberService service.SubscriberServiceInterface,
+	flash flash.FlashStoreInterface,
+	baseURL string,
+) *StatusPageHandler {
+	var appHost string
+	if base, err := url.Parse(baseURL); err == nil {
+		appHost = model.NormalizeDomain(base.Host)
+	}
+	return &StatusPageHandler{
+		statusPageService: statusPageService,
+		postService:       postService,
+		subscriberService: subscriberService,
+		flash:             flash,
+		baseURL:           strings.TrimSuffix(baseURL, "/"),
+		appHost:           appHost,
+		domains:           newDomainCache(),
+	}
+}
+
+// targetOption is a target offered for a component
+type targetOption struct {
+	ID       int
+	URL      string
+	Selected bool
+}
+
+// componentRow is a component as edited in the form
+type componentRow struct {
+	Index   int
+	Name    string
+	Targets []targetOption
+}
+
+// errorStatus maps status page service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrStatusPageNotFound), errors.Is(err, service.ErrPostNotFound),
+		errors.Is(err, service.ErrSubscriberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseID reads a positive integer path value
+func parseID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// parsePage reads the page settings and components from the submitted form.
+// Components are numbered rows; rows left blank are skipped.
+func parsePage(r *http.Request) (*model.StatusPage, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("invalid form")
+	}
+
+	page := &model.StatusPage{
+		Slug:    r.PostForm.Get("slug"),
+		Title:   r.PostForm.Get("title"),
+		LogoURL: r.PostForm.Get("logo_url"),
+		Domain:  r.PostForm.Get("domain"),
+	}
+
+	for i := 0; i < maxComponents; i++ {
+		names, ok := r.PostForm[fmt.Sprintf("component_name_%d", i)]
+		if !ok {
+			break
+		}
+		component := model.Component{Name: strings.TrimSpace(names[0])}
+		for _, value := range r.PostForm[fmt.Sprintf("component_targets_%d", i)] {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid target")
+			}
+			component.TargetIDs = append(component.TargetIDs, id)
+		}
+		if component.Name == "" && len(component.TargetIDs) == 0 {
+			continue
+		}
+		page.Components = append(page.Components, component)
+	}
+	return page, nil
+}
+
+// componentRows lays out the components of a page for the form, followed by
+// blank rows to add more
+func (h *StatusPageHandler) componentRows(ctx context.Context, userID int, components []model.Component) ([]componentRow, error) {
+	targets, err := h.statusPageService.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]componentRow, 0, len(components)+blankComponents)
+	for i := 0; i < len(components)+blankComponents; i++ {
+		row := componentRow{Index: i}
+		selected := map[int]bool{}
+		if i < len(components) {
+			row.Name = components[i].Name
+			for _, id := range components[i].TargetIDs {
+				selected[id] = true
+			}
+		}
+		for _, target := range targets {
+			row.Targets = append(row.Targets, targetOption{ID: target.ID, URL: target.URL, Selected: selected[target.ID]})
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// List shows the user's status pages
+func (h *StatusPageHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	pages, err := h.statusPageService.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title": "status pages",
+		"pages": pages,
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+func (h *StatusPageHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		rows, err := h.componentRows(r.Context(), user.ID, nil)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data := map[string]any{
+			"title":      "add a status page",
+			"components": rows,
+		}
+		h.Template.Create.Render(w, r, data)
+		return
+	}
+
+	createURL := "/app/status-pages/create"
+	page, err := parsePage(r)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid status page: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	if err := h.statusPageService.Create(r.Context(), page, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to create status page: " + err.Error()})
+		http.Redirect(w, r, createURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Status page created successfully"})
+	http.Redirect(w, r, "/app/status-pages", http.StatusSeeOther)
+}
+
+func (h *StatusPageHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/status-pages/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	page, err := h.statusPageService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		rows, err := h.componentRows(r.Context(), user.ID, page.Components)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		data := map[string]any{
+			"title":      "edit status page",
+			"page":       page,
+			"components": rows,
+		}
+		h.Template.Edit.Render(w, r, data)
+		return
+	}
+
+	updated, err := parsePage(r)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Invalid status page: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	updated.ID = page.ID
+
+	if _, err := h.statusPageService.Update(r.Context(), updated, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to update status page: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	h.domains.reset()
+
+	h.flash.SetSuccesses(r.Context(), []string{"Status page updated successfully"})
+	http.Redirect(w, r, "/app/status-pages", http.StatusSeeOther)
+}
+
+func (h *StatusPageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.statusPageService.Delete(r.Context(), id, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to delete status page: " + err.Error()})
+		http.Redirect(w, r, "/app/status-pages", http.StatusSeeOther)
+		return
+	}
+	h.domains.reset()
+
+	h.flash.SetSuccesses(r.Context(), []string{"Status page deleted successfully"})
+	http.Redirect(w, r, "/app/status-pages", http.StatusSeeOther)
+}
+
+// VerifyDomain checks the challenge record of a page's domain, after which
+// the page is served on the domain
+func (h *StatusPageHandler) VerifyDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	editURL := fmt.Sprintf("/app/status-pages/edit/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	page, err := h.statusPageService.VerifyDomain(r.Context(), id, user.ID)
+	if err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to verify domain: " + err.Error()})
+		http.Redirect(w, r, editURL, http.StatusSeeOther)
+		return
+	}
+	h.domains.reset()
+
+	h.flash.SetSuccesses(r.Context(), []string{"Domain verified, the page is now served on " + page.Domain})
+	http.Redirect(w, r, editURL, http.StatusSeeOther)
+}
+
+// Show serves a public status page by its slug
+func (h *StatusPageHandler) Show(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+	h.show(w, r, page, page.Path())
+}
+
+// Feed serves the posts of a public status page as RSS, Atom or JSON Feed,
+// picked by the extension of the path
+func (h *StatusPageHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+	h.feed(w, r, page)
+}
+
+// publicError writes the error that kept a public page from being served
+func (h *StatusPageHandler) publicError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("Failed to build status page", "error", err)
+		http.Error(w, "Internal Server Error", status)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// show renders a public page. root is the path of the page on the host
+// serving it, which links to the other pages of the status page build on.
+func (h *StatusPageHandler) show(w http.ResponseWriter, r *http.Request, page *model.StatusPage, root string) {
+	view, err := h.statusPageService.View(r.Context(), page)
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+
+	data := map[string]any{
+		"title":        view.Page.Title,
+		"view":         view,
+		"feedBase":     h.baseURL + page.Path() + "/feed",
+		"subscribeURL": root + "/subscribe",
+	}
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if err := h.Template.Public.Render(w, r, data); err != nil {
+		slog.Error("Failed to render status page", "error", err)
+	}
+}
+
+func (h *StatusPageHandler) feed(w http.ResponseWriter, r *http.Request, page *model.StatusPage) {
+	format := strings.TrimPrefix(path.Ext(r.URL.Path), ".")
+	contentType, ok := feedTypes[format]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	posts, err := h.postService.Feed(r.Context(), page)
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+
+	pageURL := h.baseURL + page.Path()
+	f := newFeed(page, posts, pageURL, pageURL+"/feed."+format, service.FeedSize)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	switch format {
+	case "rss":
+		err = writeRSS(w, f)
+	case "atom":
+		err = writeAtom(w, f)
+	default:
+		err = writeJSONFeed(w, f)
+	}
+	if err != nil {
+		slog.Error("Failed to write status page feed", "error", err)
+	}
+}
+
+// CustomDomain serves status pages on their own domain. Requests whose Host
+// is the verified domain of a page get the page at the root, its feeds, its
+// subscribe form, static files, and a 404 for anything else. Requests for any
+// other host go to next. Lookups are cached, including hosts without a page.
+func (h *StatusPageHandler) CustomDomain(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := model.NormalizeDomain(r.Host)
+		if host == h.appHost || !strings.Contains(host, ".") || strings.HasPrefix(r.URL.Path, "/static/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		page, ok := h.domainPage(r.Context(), host)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.URL.Path == "/subscribe" {
+			h.subscribe(w, r, page, "")
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Path {
+		case "/":
+			h.show(w, r, page, "")
+		case "/feed.rss", "/feed.atom", "/feed.json":
+			h.feed(w, r, page)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// domainPage returns the page served on a host, looking the host up when it
+// is not cached. A failed lookup is not cached, so it is retried.
+func (h *StatusPageHandler) domainPage(ctx context.Context, host string) (*model.StatusPage, bool) {
+	if page, ok := h.domains.get(host); ok {
+		return page, page != nil
+	}
+
+	page, err := h.statusPageService.GetByDomain(ctx, host)
+	if err != nil {
+		if !errors.Is(err, service.ErrStatusPageNotFound) {
+			slog.Error("Failed to look up status page domain", "host", host, "error", err)
+			return nil, false
+		}
+		page = nil
+	}
+	h.domains.put(host, page)
+	return page, page != nil
+}
@@ -0,0 +1,452 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/service"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockStatusPageService struct {
+	createFunc       func(page *model.StatusPage, userID int) error
+	getFunc          func(id int, userID int) (*model.StatusPage, error)
+	getByUserIDFunc  func(userID int) ([]*model.StatusPage, error)
+	updateFunc       func(page *model.StatusPage, userID int) (*model.StatusPage, error)
+	deleteFunc       func(id int, userID int) error
+	verifyDomainFunc func(id int, userID int) (*model.StatusPage, error)
+	getTargetsFunc   func(userID int) ([]monitorModel.UserTarget, error)
+	getBySlugFunc    func(slug string) (*model.StatusPage, error)
+	getByDomainFunc  func(domain string) (*model.StatusPage, error)
+	viewFunc         func(page *model.StatusPage) (*model.PageView, error)
+}
+
+func (m *MockStatusPageService) Create(ctx context.Context, page *model.StatusPage, userID int) error {
+	return m.createFunc(page, userID)
+}
+
+func (m *MockStatusPageService) Get(ctx context.Context, id int, userID int) (*model.StatusPage, error) {
+	return m.getFunc(id, userID)
+}
+
+func (m *MockStatusPageService) GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *MockStatusPageService) Update(ctx context.Context, page *model.StatusPage, userID int) (*model.StatusPage, error) {
+	return m.updateFunc(page, userID)
+}
+
+func (m *MockStatusPageService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *MockStatusPageService) VerifyDomain(ctx context.Context, id int, userID int) (*model.StatusPage, error) {
+	return m.verifyDomainFunc(id, userID)
+}
+
+func (m *MockStatusPageService) GetTargets(ctx context.Context, userID int) ([]monitorModel.UserTarget, error) {
+	return m.getTargetsFunc(userID)
+}
+
+func (m *MockStatusPageService) GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error) {
+	return m.getBySlugFunc(slug)
+}
+
+func (m *MockStatusPageService) GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error) {
+	return m.getByDomainFunc(domain)
+}
+
+func (m *MockStatusPageService) View(ctx context.Context, page *model.StatusPage) (*model.PageView, error) {
+	return m.viewFunc(page)
+}
+
+type MockPostService struct {
+	getByPageIDFunc func(pageID int, userID int) ([]*model.Post, error)
+	createFunc      func(post *model.Post, update *model.PostUpdate, userID int) error
+	addUpdateFunc   func(update *model.PostUpdate, userID int) error
+	deleteFunc      func(id int, userID int) error
+	feedFunc        func(page *model.StatusPage) ([]*model.Post, error)
+}
+
+func (m *MockPostService) GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Post, error) {
+	return m.getByPageIDFunc(pageID, userID)
+}
+
+func (m *MockPostService) Create(ctx context.Context, post *model.Post, update *model.PostUpdate, userID int) error {
+	return m.createFunc(post, update, userID)
+}
+
+func (m *MockPostService) AddUpdate(ctx context.Context, update *model.PostUpdate, userID int) error {
+	return m.addUpdateFunc(update, userID)
+}
+
+func (m *MockPostService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func (m *MockPostService) Feed(ctx context.Context, page *model.StatusPage) ([]*model.Post, error) {
+	return m.feedFunc(page)
+}
+
+type MockSubscriberService struct {
+	subscribeFunc   func(page *model.StatusPage, subscriber *model.Subscriber) error
+	confirmFunc     func(page *model.StatusPage, token string) error
+	unsubscribeFunc func(page *model.StatusPage, token string) error
+	getByPageIDFunc func(pageID int, userID int) ([]*model.Subscriber, error)
+	deleteFunc      func(id int, userID int) error
+}
+
+func (m *MockSubscriberService) Subscribe(ctx context.Context, page *model.StatusPage, subscriber *model.Subscriber) error {
+	return m.subscribeFunc(page, subscriber)
+}
+
+func (m *MockSubscriberService) Confirm(ctx context.Context, page *model.StatusPage, token string) error {
+	return m.confirmFunc(page, token)
+}
+
+func (m *MockSubscriberService) Unsubscribe(ctx context.Context, page *model.StatusPage, token string) error {
+	return m.unsubscribeFunc(page, token)
+}
+
+func (m *MockSubscriberService) GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Subscriber, error) {
+	return m.getByPageIDFunc(pageID, userID)
+}
+
+func (m *MockSubscriberService) Delete(ctx context.Context, id int, userID int) error {
+	return m.deleteFunc(id, userID)
+}
+
+func withUser(req *http.Request, userID int) *http.Request {
+	ctx := authService.WithUser(req.Context(), &authModel.User{ID: userID, Email: "alice@example.com"})
+	return req.WithContext(ctx)
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func newTestStatusPageHandler(mockService *MockStatusPageService, mockPostService *MockPostService) *StatusPageHandler {
+	mockFlashStore := flash.NewMockFlashStore()
+	handler := NewStatusPageHandler(mockService, mockPostService, &MockSubscriberService{}, mockFlashStore, "https://uptime.example.com")
+	templateRenderer := renderer.New(templates.TemplateFS, mockFlashStore)
+	handler.Template.List = templateRenderer.GetTemplate("pages:status-pages/list")
+	handler.Template.Create = templateRenderer.GetTemplate("pages:status-pages/create")
+	handler.Template.Edit = templateRenderer.GetTemplate("pages:status-pages/edit")
+	handler.Template.Posts = templateRenderer.GetTemplate("pages:status-pages/posts")
+	handler.Template.Subscribers = templateRenderer.GetTemplate("pages:status-pages/subscribers")
+	handler.Template.Public = templateRenderer.GetTemplate("pages:status/show")
+	handler.Template.Subscribe = templateRenderer.GetTemplate("pages:status/subscribe")
+	handler.Template.Notice = templateRenderer.GetTemplate("pages:status/notice")
+	return handler
+}
+
+func testPage() *model.StatusPage {
+	return &model.StatusPage{
+		ID:          3,
+		UserID:      1,
+		Slug:        "acme",
+		Title:       "Acme Status",
+		LogoURL:     "https://acme.test/logo.png",
+		Domain:      "status.acme.test",
+		DomainToken: "challenge-token",
+		Components:  []model.Component{{Name: "API", TargetIDs: []int{7}}},
+	}
+}
+
+func testView() *model.PageView {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	resolved := now.Add(-time.Hour)
+	starts, ends := now.Add(24*time.Hour), now.Add(26*time.Hour)
+	return &model.PageView{
+		Page:  testPage(),
+		State: model.StateDegraded,
+		Components: []model.ComponentView{{
+			Name:    "API",
+			State:   model.StateDegraded,
+			History: model.History([]model.DailyUptime{{TargetID: 7, Day: now, Up: 9 * time.Hour, Down: time.Hour}}, []int{7}, 90, now),
+			Uptime:  90,
+		}},
+		Active: []model.PageIncident{{ID: 2, TargetID: 7, Status: "open", StartedAt: now, Components: []string{"API"}}},
+		Recent: []model.PageIncident{{ID: 1, TargetID: 7, Status: "resolved", StartedAt: now.Add(-2 * time.Hour), ResolvedAt: &resolved, Components: []string{"API"}}},
+		Announcements: []*model.Post{{
+			ID: 5, Kind: model.PostIncident, Title: "Elevated API errors", Status: model.PostIdentified,
+			Updates: []model.PostUpdate{{Status: model.PostIdentified, Message: "A bad deploy is being rolled back", CreatedAt: now}},
+		}},
+		Maintenance: []*model.Post{{
+			ID: 6, Kind: model.PostMaintenance, Title: "Database upgrade", Status: model.PostScheduled, StartsAt: &starts, EndsAt: &ends,
+			Updates: []model.PostUpdate{{Status: model.PostScheduled, Message: "Writes pause for a few minutes", CreatedAt: now}},
+		}},
+		UpdatedAt: now,
+	}
+}
+
+func TestStatusPageHandler_List(t *testing.T) {
+	mockService := &MockStatusPageService{
+		getByUserIDFunc: func(userID int) ([]*model.StatusPage, error) {
+			verified, pending := testPage(), testPage()
+			verifiedAt := time.Now()
+			verified.DomainVerifiedAt = &verifiedAt
+			pending.ID, pending.Domain = 4, "status.pending.test"
+			return []*model.StatusPage{verified, pending}, nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+	rr := httptest.NewRecorder()
+	handler.List(rr, withUser(httptest.NewRequest(http.MethodGet, "/app/status-pages", nil), 1))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Acme Status")
+	assert.Contains(t, rr.Body.String(), "/status/acme")
+	assert.Contains(t, rr.Body.String(), "https://status.acme.test")
+	assert.Contains(t, rr.Body.String(), "status.pending.test not verified")
+	assert.NotContains(t, rr.Body.String(), "https://status.pending.test")
+}
+
+func TestStatusPageHandler_Create(t *testing.T) {
+	t.Run("shows the targets for each component", func(t *testing.T) {
+		mockService := &MockStatusPageService{
+			getTargetsFunc: func(userID int) ([]monitorModel.UserTarget, error) {
+				return []monitorModel.UserTarget{{UserID: 1, Target: &monitor.Target{ID: 7, URL: "https://api.acme.test"}}}, nil
+			},
+		}
+		handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+		rr := httptest.NewRecorder()
+		handler.Create(rr, withUser(httptest.NewRequest(http.MethodGet, "/app/status-pages/create", nil), 1))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `name="component_targets_1" value="7"`)
+		assert.Contains(t, rr.Body.String(), "https://api.acme.test")
+	})
+
+	t.Run("reads the components", func(t *testing.T) {
+		var created *model.StatusPage
+		mockService := &MockStatusPageService{
+			createFunc: func(page *model.StatusPage, userID int) error {
+				created = page
+				return nil
+			},
+		}
+		handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+		form := url.Values{
+			"title":               {"Acme Status"},
+			"slug":                {"acme"},
+			"component_name_0":    {"API"},
+			"component_targets_0": {"7", "8"},
+			"component_name_1":    {""},
+			"component_name_2":    {"Website"},
+			"component_targets_2": {"9"},
+		}
+		rr := httptest.NewRecorder()
+		handler.Create(rr, withUser(postForm("/app/status-pages/create", form), 1))
+
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+		assert.Equal(t, "/app/status-pages", rr.Header().Get("Location"))
+		assert.Equal(t, []model.Component{
+			{Name: "API", TargetIDs: []int{7, 8}},
+			{Name: "Website", TargetIDs: []int{9}},
+		}, created.Components)
+	})
+
+	t.Run("redirects back on invalid input", func(t *testing.T) {
+		mockService := &MockStatusPageService{
+			createFunc: func(page *model.StatusPage, userID int) error {
+				return service.ErrInvalidInput
+			},
+		}
+		handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+		rr := httptest.NewRecorder()
+		handler.Create(rr, withUser(postForm("/app/status-pages/create", url.Values{"slug": {"acme"}}), 1))
+
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+		assert.Equal(t, "/app/status-pages/create", rr.Header().Get("Location"))
+	})
+}
+
+func TestStatusPageHandler_Edit(t *testing.T) {
+	mockService := &MockStatusPageService{
+		getFunc: func(id int, userID int) (*model.StatusPage, error) {
+			if userID != 1 {
+				return nil, service.ErrUnauthorized
+			}
+			return testPage(), nil
+		},
+		getTargetsFunc: func(userID int) ([]monitorModel.UserTarget, error) {
+			return []monitorModel.UserTarget{{UserID: 1, Target: &monitor.Target{ID: 7, URL: "https://api.acme.test"}}}, nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/app/status-pages/edit/3", nil)
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+	handler.Edit(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `value="status.acme.test"`)
+	assert.Contains(t, rr.Body.String(), `value="API"`)
+	assert.Contains(t, rr.Body.String(), `value="7" checked`)
+	assert.Contains(t, rr.Body.String(), "_uptimebot-challenge.status.acme.test")
+	assert.Contains(t, rr.Body.String(), "challenge-token")
+	assert.Contains(t, rr.Body.String(), `action="/app/status-pages/verify/3"`)
+
+	rr = httptest.NewRecorder()
+	handler.Edit(rr, withUser(req, 2))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestStatusPageHandler_VerifyDomain(t *testing.T) {
+	verified := false
+	mockService := &MockStatusPageService{
+		verifyDomainFunc: func(id int, userID int) (*model.StatusPage, error) {
+			if !verified {
+				return nil, fmt.Errorf("%w: record missing", service.ErrDomainNotVerified)
+			}
+			return testPage(), nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, &MockPostService{})
+	handler.domains.put("status.acme.test", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/status-pages/verify/3", nil)
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+	handler.VerifyDomain(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/app/status-pages/edit/3", rr.Header().Get("Location"))
+	_, cached := handler.domains.get("status.acme.test")
+	assert.True(t, cached)
+
+	// A verified domain is looked up again, so the page is served on it
+	verified = true
+	rr = httptest.NewRecorder()
+	handler.VerifyDomain(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/app/status-pages/edit/3", rr.Header().Get("Location"))
+	_, cached = handler.domains.get("status.acme.test")
+	assert.False(t, cached)
+}
+
+func TestStatusPageHandler_Show(t *testing.T) {
+	mockService := &MockStatusPageService{
+		getBySlugFunc: func(slug string) (*model.StatusPage, error) {
+			if slug != "acme" {
+				return nil, service.ErrStatusPageNotFound
+			}
+			return testPage(), nil
+		},
+		viewFunc: func(page *model.StatusPage) (*model.PageView, error) {
+			return testView(), nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, &MockPostService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/status/acme", nil)
+	req.SetPathValue("slug", "acme")
+	rr := httptest.NewRecorder()
+	handler.Show(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.Contains(t, body, "<title>Acme Status</title>")
+	assert.Contains(t, body, `src="https://acme.test/logo.png"`)
+	assert.Contains(t, body, "Degraded performance")
+	assert.Contains(t, body, "Ongoing incidents")
+	assert.Contains(t, body, `id="post-5"`)
+	assert.Contains(t, body, "A bad deploy is being rolled back")
+	assert.Contains(t, body, "May 2, 12:00 to May 2, 14:00 UTC")
+	assert.Contains(t, body, `href="https://uptime.example.com/status/acme/feed.atom"`)
+	assert.Contains(t, body, "May 1, 2025: 90.00% uptime")
+	assert.Contains(t, body, "Apr 30, 2025: no data")
+	assert.NotContains(t, body, "Logout")
+	assert.Contains(t, body, `href="/status/acme/subscribe"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/status/unknown", nil)
+	req.SetPathValue("slug", "unknown")
+	rr = httptest.NewRecorder()
+	handler.Show(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestStatusPageHandler_CustomDomain(t *testing.T) {
+	var lookups []string
+	mockService := &MockStatusPageService{
+		getByDomainFunc: func(domain string) (*model.StatusPage, error) {
+			lookups = append(lookups, domain)
+			if domain != "status.acme.test" {
+				return nil, service.ErrStatusPageNotFound
+			}
+			return testPage(), nil
+		},
+		viewFunc: func(page *model.StatusPage) (*model.PageView, error) {
+			return testView(), nil
+		},
+	}
+	mockPostService := &MockPostService{
+		feedFunc: func(page *model.StatusPage) ([]*model.Post, error) {
+			return nil, nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, mockPostService)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	wrapped := handler.CustomDomain(next)
+
+	tests := []struct {
+		name   string
+		host   string
+		path   string
+		status int
+	}{
+		{name: "page at the root of its domain", host: "status.acme.test", path: "/", status: http.StatusOK},
+		{name: "feed on the page domain", host: "status.acme.test", path: "/feed.atom", status: http.StatusOK},
+		{name: "subscribe form on the page domain", host: "status.acme.test", path: "/subscribe", status: http.StatusOK},
+		{name: "static files on the page domain", host: "status.acme.test", path: "/static/css/tailwind.css", status: http.StatusTeapot},
+		{name: "other paths on the page domain", host: "status.acme.test", path: "/login", status: http.StatusNotFound},
+		{name: "app host", host: "uptime.example.com:443", path: "/", status: http.StatusTeapot},
+		{name: "unknown domain", host: "other.example.com", path: "/", status: http.StatusTeapot},
+		{name: "local host", host: "localhost:8080", path: "/", status: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = tt.host
+			rr := httptest.NewRecorder()
+			wrapped.ServeHTTP(rr, req)
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+
+	// Each host is looked up once, including the one without a page
+	assert.Equal(t, []string{"status.acme.test", "other.example.com"}, lookups)
+
+	handler.domains.now = func() time.Time { return time.Now().Add(domainCacheTTL) }
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "other.example.com"
+	wrapped.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, []string{"status.acme.test", "other.example.com", "other.example.com"}, lookups)
+}
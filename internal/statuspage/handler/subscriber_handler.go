@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/service"
+)
+
+// Subscribe shows the subscribe form of a public status page and signs
+// visitors up
+func (h *StatusPageHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+	h.subscribe(w, r, page, page.Path())
+}
+
+func (h *StatusPageHandler) subscribe(w http.ResponseWriter, r *http.Request, page *model.StatusPage, root string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The form carries the visitor's CSRF token, so it must never be cached
+	w.Header().Set("Cache-Control", "no-store")
+	data := map[string]any{
+		"title":   "Subscribe to " + page.Title,
+		"page":    page,
+		"pageURL": root,
+		"action":  root + "/subscribe",
+		"kind":    model.SubscriberEmail,
+	}
+	if root == "" {
+		data["pageURL"] = "/"
+	}
+
+	if r.Method == http.MethodPost {
+		subscriber := &model.Subscriber{
+			Kind:    model.SubscriberKind(r.FormValue("kind")),
+			Address: r.FormValue("address"),
+		}
+		err := h.subscriberService.Subscribe(r.Context(), page, subscriber)
+		if err == nil {
+			heading, message := "Check your inbox", "We sent a confirmation link to "+subscriber.Address+". Follow it to start receiving updates."
+			if subscriber.Kind == model.SubscriberWebhook {
+				heading, message = "Webhook added", "Updates will be posted to "+subscriber.Address+"."
+			}
+			h.notice(w, r, page, http.StatusOK, heading, message)
+			return
+		}
+		if !errors.Is(err, service.ErrInvalidInput) {
+			h.publicError(w, r, err)
+			return
+		}
+		data["kind"] = subscriber.Kind
+		data["address"] = subscriber.Address
+		data["error"] = err.Error()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	if err := h.Template.Subscribe.Render(w, r, data); err != nil {
+		slog.Error("Failed to render subscribe form", "error", err)
+	}
+}
+
+// Confirm confirms the email subscriber the link in the query was sent to
+func (h *StatusPageHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+
+	if err := h.subscriberService.Confirm(r.Context(), page, r.URL.Query().Get("token")); err != nil {
+		h.tokenError(w, r, page, err)
+		return
+	}
+	h.notice(w, r, page, http.StatusOK, "Subscription confirmed", "You will receive incident and maintenance updates from "+page.Title+" by email.")
+}
+
+// Unsubscribe removes the subscriber holding the token. The link in emails
+// only shows a button that posts the token back, so mail scanners that open
+// every link do not unsubscribe anyone.
+func (h *StatusPageHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		h.publicError(w, r, err)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.renderNotice(w, r, page, http.StatusOK, map[string]any{
+			"heading": "Unsubscribe",
+			"message": "Stop receiving updates from " + page.Title + "?",
+			"form": map[string]any{
+				"action": page.Path() + "/unsubscribe",
+				"token":  r.URL.Query().Get("token"),
+				"submit": "Unsubscribe",
+			},
+		})
+		return
+	}
+
+	if err := h.subscriberService.Unsubscribe(r.Context(), page, r.FormValue("token")); err != nil {
+		h.tokenError(w, r, page, err)
+		return
+	}
+	h.notice(w, r, page, http.StatusOK, "Unsubscribed", "You will no longer receive updates from "+page.Title+".")
+}
+
+// tokenError explains why a confirm or unsubscribe link did not work
+func (h *StatusPageHandler) tokenError(w http.ResponseWriter, r *http.Request, page *model.StatusPage, err error) {
+	switch status := errorStatus(err); status {
+	case http.StatusNotFound:
+		h.notice(w, r, page, status, "Link not valid", "This link is not valid anymore. You may have used it already.")
+	case http.StatusBadRequest:
+		h.notice(w, r, page, status, "Link not valid", err.Error())
+	default:
+		h.publicError(w, r, err)
+	}
+}
+
+// notice renders a short message on a public status page
+func (h *StatusPageHandler) notice(w http.ResponseWriter, r *http.Request, page *model.StatusPage, status int, heading string, message string) {
+	h.renderNotice(w, r, page, status, map[string]any{
+		"heading": heading,
+		"message": message,
+	})
+}
+
+// renderNotice renders the notice template, with a form when data holds one
+func (h *StatusPageHandler) renderNotice(w http.ResponseWriter, r *http.Request, page *model.StatusPage, status int, data map[string]any) {
+	data["title"] = data["heading"]
+	data["page"] = page
+	// Forms carry the visitor's CSRF token, and links their own token, so
+	// notices are never cached
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := h.Template.Notice.Render(w, r, data); err != nil {
+		slog.Error("Failed to render status page notice", "error", err)
+	}
+}
+
+// Subscribers lists the subscribers of a page
+func (h *StatusPageHandler) Subscribers(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	page, err := h.statusPageService.Get(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	subscribers, err := h.subscriberService.GetByPageID(r.Context(), id, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	data := map[string]any{
+		"title":       "status page subscribers",
+		"page":        page,
+		"subscribers": subscribers,
+	}
+	h.Template.Subscribers.Render(w, r, data)
+}
+
+func (h *StatusPageHandler) DeleteSubscriber(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+	subscriberID, err := parseID(r, "subscriberId")
+	if err != nil {
+		http.Error(w, "Invalid subscriber ID", http.StatusBadRequest)
+		return
+	}
+	subscribersURL := fmt.Sprintf("/app/status-pages/subscribers/%d", id)
+
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.subscriberService.Delete(r.Context(), subscriberID, user.ID); err != nil {
+		h.flash.SetErrors(r.Context(), []string{"Failed to remove subscriber: " + err.Error()})
+		http.Redirect(w, r, subscribersURL, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetSuccesses(r.Context(), []string{"Subscriber removed successfully"})
+	http.Redirect(w, r, subscribersURL, http.StatusSeeOther)
+}
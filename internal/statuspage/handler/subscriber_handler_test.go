@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func bySlug(slug string) (*model.StatusPage, error) {
+	if slug != "acme" {
+		return nil, service.ErrStatusPageNotFound
+	}
+	return testPage(), nil
+}
+
+func TestStatusPageHandler_Subscribe(t *testing.T) {
+	t.Run("shows the form", func(t *testing.T) {
+		handler := newTestStatusPageHandler(&MockStatusPageService{getBySlugFunc: bySlug}, &MockPostService{})
+
+		req := httptest.NewRequest(http.MethodGet, "/status/acme/subscribe", nil)
+		req.SetPathValue("slug", "acme")
+		rr := httptest.NewRecorder()
+		handler.Subscribe(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+		assert.Contains(t, rr.Body.String(), `action="/status/acme/subscribe"`)
+	})
+
+	t.Run("signs the visitor up", func(t *testing.T) {
+		var subscribed *model.Subscriber
+		handler := newTestStatusPageHandler(&MockStatusPageService{getBySlugFunc: bySlug}, &MockPostService{})
+		handler.subscriberService = &MockSubscriberService{
+			subscribeFunc: func(page *model.StatusPage, subscriber *model.Subscriber) error {
+				subscribed = subscriber
+				return nil
+			},
+		}
+
+		req := postForm("/status/acme/subscribe", url.Values{"kind": {"email"}, "address": {"carol@example.com"}})
+		req.SetPathValue("slug", "acme")
+		rr := httptest.NewRecorder()
+		handler.Subscribe(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, &model.Subscriber{Kind: model.SubscriberEmail, Address: "carol@example.com"}, subscribed)
+		assert.Contains(t, rr.Body.String(), "Check your inbox")
+	})
+
+	t.Run("shows the form again for invalid addresses", func(t *testing.T) {
+		handler := newTestStatusPageHandler(&MockStatusPageService{getBySlugFunc: bySlug}, &MockPostService{})
+		handler.subscriberService = &MockSubscriberService{
+			subscribeFunc: func(page *model.StatusPage, subscriber *model.Subscriber) error {
+				return service.ErrInvalidInput
+			},
+		}
+
+		req := postForm("/status/acme/subscribe", url.Values{"kind": {"webhook"}, "address": {"http://hooks.example.com"}})
+		req.SetPathValue("slug", "acme")
+		rr := httptest.NewRecorder()
+		handler.Subscribe(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `value="http://hooks.example.com"`)
+		assert.Contains(t, rr.Body.String(), `value="webhook" checked`)
+	})
+}
+
+func TestStatusPageHandler_Confirm(t *testing.T) {
+	handler := newTestStatusPageHandler(&MockStatusPageService{getBySlugFunc: bySlug}, &MockPostService{})
+	handler.subscriberService = &MockSubscriberService{
+		confirmFunc: func(page *model.StatusPage, token string) error {
+			if token != "valid" {
+				return service.ErrSubscriberNotFound
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		body   string
+	}{
+		{name: "valid link", token: "valid", status: http.StatusOK, body: "Subscription confirmed"},
+		{name: "unknown link", token: "unknown", status: http.StatusNotFound, body: "not valid anymore"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/status/acme/confirm?token="+tt.token, nil)
+			req.SetPathValue("slug", "acme")
+			rr := httptest.NewRecorder()
+			handler.Confirm(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.body)
+		})
+	}
+}
+
+func TestStatusPageHandler_Unsubscribe(t *testing.T) {
+	var tokens []string
+	handler := newTestStatusPageHandler(&MockStatusPageService{getBySlugFunc: bySlug}, &MockPostService{})
+	handler.subscriberService = &MockSubscriberService{
+		unsubscribeFunc: func(page *model.StatusPage, token string) error {
+			tokens = append(tokens, token)
+			return nil
+		},
+	}
+
+	t.Run("asks before unsubscribing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status/acme/unsubscribe?token=abc", nil)
+		req.SetPathValue("slug", "acme")
+		rr := httptest.NewRecorder()
+		handler.Unsubscribe(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `action="/status/acme/unsubscribe"`)
+		assert.Contains(t, rr.Body.String(), `name="token" value="abc"`)
+		assert.Empty(t, tokens)
+	})
+
+	t.Run("unsubscribes on post", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/status/acme/unsubscribe", strings.NewReader("token=abc"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("slug", "acme")
+		rr := httptest.NewRecorder()
+		handler.Unsubscribe(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "no longer receive updates")
+		assert.Equal(t, []string{"abc"}, tokens)
+	})
+}
+
+func TestStatusPageHandler_Subscribers(t *testing.T) {
+	now := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+	mockService := &MockStatusPageService{
+		getFunc: func(id int, userID int) (*model.StatusPage, error) {
+			return testPage(), nil
+		},
+	}
+	handler := newTestStatusPageHandler(mockService, &MockPostService{})
+	handler.subscriberService = &MockSubscriberService{
+		getByPageIDFunc: func(pageID int, userID int) ([]*model.Subscriber, error) {
+			return []*model.Subscriber{
+				{ID: 1, Kind: model.SubscriberEmail, Address: "carol@example.com", CreatedAt: now},
+				{ID: 2, Kind: model.SubscriberWebhook, Address: "https://hooks.example.com/status", ConfirmedAt: &now, CreatedAt: now},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/app/status-pages/subscribers/3", nil)
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+	handler.Subscribers(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.Contains(t, body, "carol@example.com")
+	assert.Contains(t, body, "Awaiting confirmation")
+	assert.Contains(t, body, `action="/app/status-pages/subscribers/3/delete/2"`)
+}
+
+func TestStatusPageHandler_DeleteSubscriber(t *testing.T) {
+	var deleted []int
+	handler := newTestStatusPageHandler(&MockStatusPageService{}, &MockPostService{})
+	handler.subscriberService = &MockSubscriberService{
+		deleteFunc: func(id int, userID int) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+
+	req := postForm("/app/status-pages/subscribers/3/delete/2", url.Values{})
+	req.SetPathValue("id", "3")
+	req.SetPathValue("subscriberId", "2")
+	rr := httptest.NewRecorder()
+	handler.DeleteSubscriber(rr, withUser(req, 1))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/app/status-pages/subscribers/3", rr.Header().Get("Location"))
+	assert.Equal(t, []int{2}, deleted)
+}
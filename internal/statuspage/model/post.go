@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PostKind tells incident announcements from scheduled maintenance
+type PostKind string
+
+const (
+	PostIncident    PostKind = "incident"
+	PostMaintenance PostKind = "maintenance"
+)
+
+// PostStatus is the stage a post has reached, as set by its latest update
+type PostStatus string
+
+const (
+	PostInvestigating PostStatus = "investigating"
+	PostIdentified    PostStatus = "identified"
+	PostMonitoring    PostStatus = "monitoring"
+	PostResolved      PostStatus = "resolved"
+
+	PostScheduled  PostStatus = "scheduled"
+	PostInProgress PostStatus = "in_progress"
+	PostCompleted  PostStatus = "completed"
+)
+
+// Statuses lists the statuses updates of the kind may set, in lifecycle order
+func (k PostKind) Statuses() []PostStatus {
+	switch k {
+	case PostIncident:
+		return []PostStatus{PostInvestigating, PostIdentified, PostMonitoring, PostResolved}
+	case PostMaintenance:
+		return []PostStatus{PostScheduled, PostInProgress, PostCompleted}
+	default:
+		return nil
+	}
+}
+
+// Label describes the kind for visitors
+func (k PostKind) Label() string {
+	if k == PostMaintenance {
+		return "Scheduled maintenance"
+	}
+	return "Incident"
+}
+
+// Label describes the status for visitors
+func (s PostStatus) Label() string {
+	switch s {
+	case PostInProgress:
+		return "In progress"
+	case "":
+		return ""
+	default:
+		return strings.ToUpper(string(s[:1])) + string(s[1:])
+	}
+}
+
+// Closed reports whether the status ends the post
+func (s PostStatus) Closed() bool {
+	return s == PostResolved || s == PostCompleted
+}
+
+// Post is an announcement written by the owner of a status page: an incident
+// told in the operator's own words, or maintenance planned ahead
+type Post struct {
+	ID        int          `db:"id"`
+	PageID    int          `db:"page_id"`
+	Kind      PostKind     `db:"kind"`
+	Title     string       `db:"title"`
+	Status    PostStatus   `db:"status"`    // status of the latest update
+	StartsAt  *time.Time   `db:"starts_at"` // maintenance only
+	EndsAt    *time.Time   `db:"ends_at"`   // maintenance only
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"` // time of the latest update
+	Updates   []PostUpdate // newest first
+}
+
+// PostUpdate is a single message on a post
+type PostUpdate struct {
+	ID        int        `db:"id"`
+	PostID    int        `db:"post_id"`
+	Status    PostStatus `db:"status"`
+	Message   string     `db:"message"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// Validate checks the title and kind of the post, and that maintenance has a
+// window that ends after it starts
+func (p *Post) Validate() error {
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	switch p.Kind {
+	case PostIncident:
+		if p.StartsAt != nil || p.EndsAt != nil {
+			return fmt.Errorf("only maintenance has start and end times")
+		}
+	case PostMaintenance:
+		if p.StartsAt == nil || p.EndsAt == nil {
+			return fmt.Errorf("maintenance needs start and end times")
+		}
+		if !p.EndsAt.After(*p.StartsAt) {
+			return fmt.Errorf("maintenance must end after it starts")
+		}
+	default:
+		return fmt.Errorf("unknown post kind: %s", p.Kind)
+	}
+	return nil
+}
+
+// ValidateUpdate checks that the update has a message and a status the kind
+// of the post allows
+func (p *Post) ValidateUpdate(update *PostUpdate) error {
+	if !slices.Contains(p.Kind.Statuses(), update.Status) {
+		return fmt.Errorf("invalid status for %s: %s", p.Kind, update.Status)
+	}
+	if strings.TrimSpace(update.Message) == "" {
+		return fmt.Errorf("message is required")
+	}
+	return nil
+}
+
+// Upcoming reports whether the post is maintenance that has not been
+// completed and whose window has not passed
+func (p *Post) Upcoming(now time.Time) bool {
+	return p.Kind == PostMaintenance && !p.Status.Closed() && p.EndsAt != nil && p.EndsAt.After(now)
+}
+
+// Anchor is the fragment the post is found at on its page
+func (p *Post) Anchor() string {
+	return fmt.Sprintf("post-%d", p.ID)
+}
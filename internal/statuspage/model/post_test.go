@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPost_Validate(t *testing.T) {
+	starts := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	ends := starts.Add(time.Hour)
+
+	assert.NoError(t, (&Post{Kind: PostIncident, Title: "Slow API"}).Validate())
+	assert.NoError(t, (&Post{Kind: PostMaintenance, Title: "Upgrade", StartsAt: &starts, EndsAt: &ends}).Validate())
+
+	assert.ErrorContains(t, (&Post{Kind: PostIncident, Title: " "}).Validate(), "title")
+	assert.ErrorContains(t, (&Post{Kind: "outage", Title: "Down"}).Validate(), "kind")
+	assert.ErrorContains(t, (&Post{Kind: PostIncident, Title: "Slow API", StartsAt: &starts}).Validate(), "only maintenance")
+	assert.ErrorContains(t, (&Post{Kind: PostMaintenance, Title: "Upgrade", StartsAt: &starts}).Validate(), "start and end")
+	assert.ErrorContains(t, (&Post{Kind: PostMaintenance, Title: "Upgrade", StartsAt: &ends, EndsAt: &starts}).Validate(), "end after")
+}
+
+func TestPost_ValidateUpdate(t *testing.T) {
+	incident := &Post{Kind: PostIncident}
+	assert.NoError(t, incident.ValidateUpdate(&PostUpdate{Status: PostIdentified, Message: "Found it"}))
+	assert.ErrorContains(t, incident.ValidateUpdate(&PostUpdate{Status: PostCompleted, Message: "Done"}), "invalid status")
+	assert.ErrorContains(t, incident.ValidateUpdate(&PostUpdate{Status: PostResolved}), "message")
+}
+
+func TestPost_Upcoming(t *testing.T) {
+	now := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	assert.True(t, (&Post{Kind: PostMaintenance, Status: PostInProgress, StartsAt: &earlier, EndsAt: &later}).Upcoming(now))
+	assert.False(t, (&Post{Kind: PostMaintenance, Status: PostCompleted, StartsAt: &earlier, EndsAt: &later}).Upcoming(now))
+	assert.False(t, (&Post{Kind: PostMaintenance, Status: PostScheduled, StartsAt: &earlier, EndsAt: &earlier}).Upcoming(now))
+	assert.False(t, (&Post{Kind: PostIncident, Status: PostInvestigating}).Upcoming(now))
+}
+
+func TestPostStatus_Label(t *testing.T) {
+	assert.Equal(t, "Investigating", PostInvestigating.Label())
+	assert.Equal(t, "In progress", PostInProgress.Label())
+	assert.True(t, PostCompleted.Closed())
+	assert.False(t, PostMonitoring.Closed())
+}
@@ -0,0 +1,332 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slugPattern allows lowercase letters, digits and inner hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// domainPattern matches a host name with at least two labels
+var domainPattern = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,61}[a-z0-9]$`)
+
+// challengePrefix names the DNS TXT record proving control of a page's
+// domain, which is this label followed by the domain
+const challengePrefix = "_uptimebot-challenge."
+
+// StatusPage is a public page showing the state of a user's targets, grouped
+// into components. It is served at /status/{slug}, and at the root of its
+// domain once the owner proved they control it.
+type StatusPage struct {
+	ID               int         `db:"id"`
+	UserID           int         `db:"user_id"`
+	Slug             string      `db:"slug"`
+	Title            string      `db:"title"`
+	LogoURL          string      `db:"logo_url"`           // empty shows the title only
+	Domain           string      `db:"domain"`             // empty when the page has no domain of its own
+	DomainToken      string      `db:"domain_token"`       // value the domain's challenge record must hold
+	DomainVerifiedAt *time.Time  `db:"domain_verified_at"` // nil until the challenge record was found
+	Components       []Component // in display order
+}
+
+// Component is a named group of targets, shown as a single line on the page
+type Component struct {
+	ID        int    `db:"id"`
+	Name      string `db:"name"`
+	TargetIDs []int
+}
+
+// Path returns the address of the page on the app's own domain
+func (p *StatusPage) Path() string {
+	return "/status/" + p.Slug
+}
+
+// ChallengeRecord returns the name of the TXT record that has to hold the
+// page's DomainToken before the page is served on its domain
+func (p *StatusPage) ChallengeRecord() string {
+	return challengePrefix + p.Domain
+}
+
+// DomainVerified reports whether the page is served on its domain
+func (p *StatusPage) DomainVerified() bool {
+	return p.Domain != "" && p.DomainVerifiedAt != nil
+}
+
+// TargetIDs returns the targets of every component, each once
+func (p *StatusPage) TargetIDs() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, component := range p.Components {
+		for _, id := range component.TargetIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// Validate checks the page's slug, title, logo, domain and components
+func (p *StatusPage) Validate() error {
+	if !slugPattern.MatchString(p.Slug) {
+		return fmt.Errorf("slug must be lowercase letters, digits and hyphens")
+	}
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if p.LogoURL != "" {
+		logo, err := url.Parse(p.LogoURL)
+		if err != nil || (logo.Scheme != "https" && logo.Scheme != "http") || logo.Host == "" {
+			return fmt.Errorf("logo must be an http or https URL")
+		}
+	}
+	if p.Domain != "" && !domainPattern.MatchString(p.Domain) {
+		return fmt.Errorf("invalid domain: %s", p.Domain)
+	}
+	if len(p.Components) == 0 {
+		return fmt.Errorf("at least one component is required")
+	}
+	for _, component := range p.Components {
+		if strings.TrimSpace(component.Name) == "" {
+			return fmt.Errorf("component name is required")
+		}
+		if len(component.TargetIDs) == 0 {
+			return fmt.Errorf("component %s needs at least one target", component.Name)
+		}
+	}
+	return nil
+}
+
+// NormalizeDomain turns what a user typed, or a Host header, into a bare
+// lowercase host name
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	domain, _, _ = strings.Cut(domain, "/")
+	if host, _, found := strings.Cut(domain, ":"); found {
+		domain = host
+	}
+	return strings.TrimSuffix(domain, ".")
+}
+
+// State is how a component, or a whole page, is doing right now
+type State string
+
+const (
+	StateOperational State = "operational"
+	StateMaintenance State = "maintenance" // some of the targets are in a maintenance window, none failing
+	StateDegraded    State = "degraded"    // some of the targets are failing
+	StateOutage      State = "outage"      // every target is failing
+	StateUnknown     State = "unknown"     // no target has been checked yet
+)
+
+// severity orders states from best to worst
+func (s State) severity() int {
+	switch s {
+	case StateOperational:
+		return 1
+	case StateMaintenance:
+		return 2
+	case StateDegraded:
+		return 3
+	case StateOutage:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Label describes the state for visitors
+func (s State) Label() string {
+	switch s {
+	case StateOperational:
+		return "Operational"
+	case StateMaintenance:
+		return "Under maintenance"
+	case StateDegraded:
+		return "Degraded performance"
+	case StateOutage:
+		return "Major outage"
+	default:
+		return "No data"
+	}
+}
+
+// ComponentState derives the state of a component from the statuses of its
+// targets. Targets unreachable behind a failing parent are failing too, and
+// flapping targets degrade the component. Paused and not yet checked targets
+// do not count, and targets in a maintenance window only count while none of
+// the others is failing.
+func ComponentState(statuses []string) State {
+	var checked, failing, flapping, maintenance int
+	for _, status := range statuses {
+		switch status {
+		case "up":
+			checked++
+		case "down", "error", "unreachable-dependency":
+			checked++
+			failing++
+		case "flapping":
+			checked++
+			flapping++
+		case "maintenance":
+			maintenance++
+		}
+	}
+	switch {
+	case failing == 0 && flapping == 0 && maintenance > 0:
+		return StateMaintenance
+	case checked == 0:
+		return StateUnknown
+	case failing == 0 && flapping == 0:
+		return StateOperational
+	case failing == checked:
+		return StateOutage
+	default:
+		return StateDegraded
+	}
+}
+
+// PageState is the worst state of the components
+func PageState(components []ComponentView) State {
+	state := StateUnknown
+	for _, component := range components {
+		if component.State.severity() > state.severity() {
+			state = component.State
+		}
+	}
+	return state
+}
+
+// DailyUptime is how long a target was known to be up and down on a single
+// day, in UTC. Maintenance and time behind a failing parent count as neither.
+type DailyUptime struct {
+	TargetID int
+	Day      time.Time
+	Up       time.Duration
+	Down     time.Duration
+}
+
+// Day is a bar of the uptime history
+type Day struct {
+	Date time.Time
+	Up   time.Duration
+	Down time.Duration
+}
+
+// HasData reports whether checks found the targets up or down that day
+func (d Day) HasData() bool {
+	return d.Up+d.Down > 0
+}
+
+// Uptime returns the share of the measured time the targets were up, as a
+// percentage
+func (d Day) Uptime() float64 {
+	if !d.HasData() {
+		return 100
+	}
+	return float64(d.Up) * 100 / float64(d.Up+d.Down)
+}
+
+// Level grades the day for its bar: "none" without data, then "good",
+// "warn" or "bad" as uptime drops below 99% and 95%
+func (d Day) Level() string {
+	switch uptime := d.Uptime(); {
+	case !d.HasData():
+		return "none"
+	case uptime >= 99:
+		return "good"
+	case uptime >= 95:
+		return "warn"
+	default:
+		return "bad"
+	}
+}
+
+// History lays out the uptime of the given targets over the days ending on
+// the day of now, oldest first. Days without data are kept as gaps.
+func History(uptimes []DailyUptime, targetIDs []int, days int, now time.Time) []Day {
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(days - 1))
+
+	history := make([]Day, days)
+	for i := range history {
+		history[i].Date = first.AddDate(0, 0, i)
+	}
+
+	wanted := make(map[int]bool, len(targetIDs))
+	for _, id := range targetIDs {
+		wanted[id] = true
+	}
+	for _, uptime := range uptimes {
+		if !wanted[uptime.TargetID] {
+			continue
+		}
+		i := int(uptime.Day.UTC().Truncate(24*time.Hour).Sub(first) / (24 * time.Hour))
+		if i < 0 || i >= days {
+			continue
+		}
+		history[i].Up += uptime.Up
+		history[i].Down += uptime.Down
+	}
+	return history
+}
+
+// Uptime returns the share of the measured time over the whole history the
+// targets were up, as a percentage
+func Uptime(history []Day) float64 {
+	var total Day
+	for _, day := range history {
+		total.Up += day.Up
+		total.Down += day.Down
+	}
+	return total.Uptime()
+}
+
+// TargetState is the current status of a target on a page
+type TargetState struct {
+	TargetID int
+	Status   string
+}
+
+// PageIncident is an incident of one of the page's targets, as shown to visitors
+type PageIncident struct {
+	ID         int
+	TargetID   int
+	Status     string
+	StartedAt  time.Time
+	ResolvedAt *time.Time
+	Components []string // names of the components the target belongs to
+}
+
+// Active reports whether the incident is still going on
+func (i PageIncident) Active() bool {
+	return i.ResolvedAt == nil
+}
+
+// ComponentView is a component as shown on the public page
+type ComponentView struct {
+	Name    string
+	State   State
+	History []Day
+	Uptime  float64 // over the whole history
+}
+
+// PageView is everything the public page shows
+type PageView struct {
+	Page          *StatusPage
+	State         State
+	Components    []ComponentView
+	Active        []PageIncident // incidents still going on, newest first
+	Recent        []PageIncident // incidents resolved lately, newest first
+	Announcements []*Post        // incident posts not yet resolved, newest first
+	Maintenance   []*Post        // maintenance not yet over, soonest first
+	PastPosts     []*Post        // posts closed lately, newest first
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusPage_Validate(t *testing.T) {
+	valid := func() *StatusPage {
+		return &StatusPage{
+			Slug:       "acme",
+			Title:      "Acme Status",
+			Components: []Component{{Name: "API", TargetIDs: []int{1}}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		change  func(p *StatusPage)
+		wantErr string
+	}{
+		{name: "valid", change: func(p *StatusPage) {}},
+		{name: "with logo and domain", change: func(p *StatusPage) {
+			p.LogoURL = "https://acme.test/logo.png"
+			p.Domain = "status.acme.test"
+		}},
+		{name: "uppercase slug", change: func(p *StatusPage) { p.Slug = "Acme" }, wantErr: "slug"},
+		{name: "slug ending with a hyphen", change: func(p *StatusPage) { p.Slug = "acme-" }, wantErr: "slug"},
+		{name: "missing title", change: func(p *StatusPage) { p.Title = " " }, wantErr: "title"},
+		{name: "logo without scheme", change: func(p *StatusPage) { p.LogoURL = "acme.test/logo.png" }, wantErr: "logo"},
+		{name: "javascript logo", change: func(p *StatusPage) { p.LogoURL = "javascript:alert(1)" }, wantErr: "logo"},
+		{name: "domain with port", change: func(p *StatusPage) { p.Domain = "status.acme.test:8080" }, wantErr: "domain"},
+		{name: "single label domain", change: func(p *StatusPage) { p.Domain = "localhost" }, wantErr: "domain"},
+		{name: "no components", change: func(p *StatusPage) { p.Components = nil }, wantErr: "component"},
+		{name: "component without targets", change: func(p *StatusPage) {
+			p.Components = append(p.Components, Component{Name: "Web"})
+		}, wantErr: "Web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := valid()
+			tt.change(page)
+			err := page.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	assert.Equal(t, "status.acme.test", NormalizeDomain(" https://Status.Acme.test/ "))
+	assert.Equal(t, "status.acme.test", NormalizeDomain("status.acme.test:8080"))
+	assert.Equal(t, "status.acme.test", NormalizeDomain("status.acme.test."))
+	assert.Equal(t, "", NormalizeDomain(""))
+}
+
+func TestStatusPage_DomainVerified(t *testing.T) {
+	page := &StatusPage{Domain: "status.acme.test"}
+	assert.Equal(t, "_uptimebot-challenge.status.acme.test", page.ChallengeRecord())
+	assert.False(t, page.DomainVerified())
+
+	verifiedAt := time.Now()
+	page.DomainVerifiedAt = &verifiedAt
+	assert.True(t, page.DomainVerified())
+
+	page.Domain = ""
+	assert.False(t, page.DomainVerified())
+}
+
+func TestStatusPage_TargetIDs(t *testing.T) {
+	page := &StatusPage{Components: []Component{
+		{Name: "API", TargetIDs: []int{3, 1}},
+		{Name: "Web", TargetIDs: []int{1, 2}},
+	}}
+	assert.Equal(t, []int{3, 1, 2}, page.TargetIDs())
+}
+
+func TestComponentState(t *testing.T) {
+	assert.Equal(t, StateUnknown, ComponentState(nil))
+	assert.Equal(t, StateUnknown, ComponentState([]string{"paused", ""}))
+	assert.Equal(t, StateOperational, ComponentState([]string{"up", "paused"}))
+	assert.Equal(t, StateDegraded, ComponentState([]string{"up", "down"}))
+	assert.Equal(t, StateOutage, ComponentState([]string{"error", "down"}))
+	assert.Equal(t, StateMaintenance, ComponentState([]string{"up", "maintenance"}))
+	assert.Equal(t, StateDegraded, ComponentState([]string{"down", "up", "maintenance"}))
+	assert.Equal(t, StateOutage, ComponentState([]string{"down", "unreachable-dependency"}))
+	assert.Equal(t, StateDegraded, ComponentState([]string{"flapping"}))
+	assert.Equal(t, StateDegraded, ComponentState([]string{"flapping", "maintenance"}))
+}
+
+func TestPageState(t *testing.T) {
+	assert.Equal(t, StateUnknown, PageState(nil))
+	assert.Equal(t, StateOutage, PageState([]ComponentView{
+		{State: StateOperational}, {State: StateOutage}, {State: StateDegraded},
+	}))
+	assert.Equal(t, StateOperational, PageState([]ComponentView{{State: StateUnknown}, {State: StateOperational}}))
+	assert.Equal(t, StateMaintenance, PageState([]ComponentView{{State: StateMaintenance}, {State: StateOperational}}))
+}
+
+func TestHistory(t *testing.T) {
+	now := time.Date(2025, 5, 1, 15, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	uptimes := []DailyUptime{
+		{TargetID: 1, Day: day(1), Up: 9 * time.Hour, Down: time.Hour},
+		{TargetID: 2, Day: day(1), Up: 10 * time.Hour},
+		{TargetID: 3, Day: day(1), Down: 10 * time.Hour}, // not part of the component
+		{TargetID: 1, Day: time.Date(2025, 4, 29, 0, 0, 0, 0, time.UTC), Up: 4 * time.Hour},
+		{TargetID: 1, Day: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Down: 4 * time.Hour}, // too old
+	}
+
+	history := History(uptimes, []int{1, 2}, 3, now)
+	assert.Len(t, history, 3)
+	assert.Equal(t, time.Date(2025, 4, 29, 0, 0, 0, 0, time.UTC), history[0].Date)
+	assert.Equal(t, 4*time.Hour, history[0].Up)
+	assert.False(t, history[1].HasData())
+	assert.Equal(t, 95.0, history[2].Uptime())
+	assert.InDelta(t, 95.83, Uptime(history), 0.01)
+}
+
+func TestDay_Level(t *testing.T) {
+	assert.Equal(t, "none", Day{}.Level())
+	assert.Equal(t, "good", Day{Up: 99 * time.Minute, Down: time.Minute}.Level())
+	assert.Equal(t, "warn", Day{Up: 95 * time.Minute, Down: 5 * time.Minute}.Level())
+	assert.Equal(t, "bad", Day{Up: 94 * time.Minute, Down: 6 * time.Minute}.Level())
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/pkg/netguard"
+)
+
+// SubscriberKind is how a subscriber receives updates
+type SubscriberKind string
+
+const (
+	SubscriberEmail   SubscriberKind = "email"
+	SubscriberWebhook SubscriberKind = "webhook"
+)
+
+// Subscriber is a visitor who gets the posts of a status page as they are
+// published. Email subscribers confirm their address before they get
+// anything; webhooks are confirmed as soon as they are added, so they must
+// point at a public address.
+type Subscriber struct {
+	ID               int            `db:"id"`
+	PageID           int            `db:"page_id"`
+	Kind             SubscriberKind `db:"kind"`
+	Address          string         `db:"address"` // email address or webhook URL
+	ConfirmToken     string         `db:"confirm_token"`
+	ConfirmExpiresAt time.Time      `db:"confirm_expires_at"`
+	ConfirmedAt      *time.Time     `db:"confirmed_at"` // nil until confirmed
+	UnsubscribeToken string         `db:"unsubscribe_token"`
+	CreatedAt        time.Time      `db:"created_at"`
+}
+
+// NormalizeAddress trims the address, and lowercases email addresses
+func (s *Subscriber) NormalizeAddress() {
+	s.Address = strings.TrimSpace(s.Address)
+	if s.Kind == SubscriberEmail {
+		s.Address = strings.ToLower(s.Address)
+	}
+}
+
+// Validate checks the kind, and that the address is a bare email address or
+// an https URL that is not on a private, loopback or link-local address
+func (s *Subscriber) Validate() error {
+	switch s.Kind {
+	case SubscriberEmail:
+		parsed, err := mail.ParseAddress(s.Address)
+		if err != nil || parsed.Address != s.Address {
+			return fmt.Errorf("invalid email address: %s", s.Address)
+		}
+	case SubscriberWebhook:
+		parsed, err := url.Parse(s.Address)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("webhook must be an https URL")
+		}
+		if err := netguard.CheckHost(parsed.Hostname()); err != nil {
+			return fmt.Errorf("webhook must be a public address")
+		}
+	default:
+		return fmt.Errorf("unknown subscriber kind: %s", s.Kind)
+	}
+	return nil
+}
+
+// Confirmed reports whether the subscriber gets updates
+func (s *Subscriber) Confirmed() bool {
+	return s.ConfirmedAt != nil
+}
+
+// CanConfirm reports whether the confirmation link is still valid
+func (s *Subscriber) CanConfirm(now time.Time) bool {
+	return !s.Confirmed() && now.Before(s.ConfirmExpiresAt)
+}
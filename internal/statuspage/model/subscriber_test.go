@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriber_Validate(t *testing.T) {
+	email := &Subscriber{Kind: SubscriberEmail, Address: " Carol@Example.com "}
+	email.NormalizeAddress()
+	assert.Equal(t, "carol@example.com", email.Address)
+	assert.NoError(t, email.Validate())
+
+	assert.NoError(t, (&Subscriber{Kind: SubscriberWebhook, Address: "https://hooks.example.com/status"}).Validate())
+
+	assert.ErrorContains(t, (&Subscriber{Kind: SubscriberEmail, Address: "Carol <carol@example.com>"}).Validate(), "invalid email")
+	assert.ErrorContains(t, (&Subscriber{Kind: SubscriberWebhook, Address: "http://hooks.example.com"}).Validate(), "https")
+	assert.ErrorContains(t, (&Subscriber{Kind: SubscriberWebhook, Address: "https://169.254.169.254/latest/meta-data"}).Validate(), "public")
+	assert.ErrorContains(t, (&Subscriber{Kind: SubscriberWebhook, Address: "https://localhost:8443/hook"}).Validate(), "public")
+	assert.ErrorContains(t, (&Subscriber{Kind: SubscriberWebhook, Address: "https://[::1]/hook"}).Validate(), "public")
+	assert.ErrorContains(t, (&Subscriber{Kind: "sms", Address: "+15550100"}).Validate(), "kind")
+}
+
+func TestSubscriber_CanConfirm(t *testing.T) {
+	now := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+
+	assert.True(t, (&Subscriber{ConfirmExpiresAt: now.Add(time.Hour)}).CanConfirm(now))
+	assert.False(t, (&Subscriber{ConfirmExpiresAt: now.Add(-time.Hour)}).CanConfirm(now))
+	assert.False(t, (&Subscriber{ConfirmExpiresAt: now.Add(time.Hour), ConfirmedAt: &now}).CanConfirm(now))
+}
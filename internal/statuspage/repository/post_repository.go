@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+var ErrPostNotFound = errors.New("status page post not found")
+
+type PostRepositoryInterface interface {
+	Create(ctx context.Context, post *model.Post) (*model.Post, error)
+	Get(ctx context.Context, id int) (*model.Post, error)
+	GetByPageID(ctx context.Context, pageID int, limit int) ([]*model.Post, error)
+	GetCurrent(ctx context.Context, pageID int, since time.Time) ([]*model.Post, error)
+	AddUpdate(ctx context.Context, update *model.PostUpdate) error
+	Delete(ctx context.Context, id int) error
+}
+
+var _ PostRepositoryInterface = (*PostRepository)(nil)
+
+// PostRepository handles database operations for status page posts and their updates
+type PostRepository struct {
+	db database.Querier
+}
+
+// NewPostRepository creates a new post repository
+func NewPostRepository(db database.Querier) *PostRepository {
+	return &PostRepository{db: db}
+}
+
+const postColumns = `id, page_id, kind, title, status, starts_at, ends_at, created_at, updated_at`
+
+func scanPost(row rowScanner) (*model.Post, error) {
+	post := &model.Post{}
+	err := row.Scan(&post.ID, &post.PageID, &post.Kind, &post.Title, &post.Status,
+		&post.StartsAt, &post.EndsAt, &post.CreatedAt, &post.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// utcOrNil converts an optional time to UTC for storage
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// Create inserts a post along with its updates, which must hold at least the
+// first one
+func (r *PostRepository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
+	query := `
+		INSERT INTO status_page_post (page_id, kind, title, status, starts_at, ends_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	newPost := *post
+	err := r.db.QueryRowContext(ctx, query, post.PageID, post.Kind, post.Title, post.Status,
+		utcOrNil(post.StartsAt), utcOrNil(post.EndsAt), post.CreatedAt.UTC(), post.UpdatedAt.UTC()).Scan(&newPost.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status page post: %w", err)
+	}
+
+	newPost.Updates = make([]model.PostUpdate, 0, len(post.Updates))
+	for _, update := range post.Updates {
+		update.PostID = newPost.ID
+		if err := r.insertUpdate(ctx, &update); err != nil {
+			return nil, err
+		}
+		newPost.Updates = append(newPost.Updates, update)
+	}
+	return &newPost, nil
+}
+
+func (r *PostRepository) insertUpdate(ctx context.Context, update *model.PostUpdate) error {
+	query := `
+		INSERT INTO status_page_post_update (post_id, status, message, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query, update.PostID, update.Status, update.Message, update.CreatedAt.UTC()).Scan(&update.ID)
+	if err != nil {
+		return fmt.Errorf("failed to add status page post update: %w", err)
+	}
+	return nil
+}
+
+// AddUpdate appends an update to a post and makes its status the post's own
+func (r *PostRepository) AddUpdate(ctx context.Context, update *model.PostUpdate) error {
+	query := `UPDATE status_page_post SET status = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, update.Status, update.CreatedAt.UTC(), update.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to update status page post: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrPostNotFound
+	}
+
+	return r.insertUpdate(ctx, update)
+}
+
+// Get retrieves a post with its updates
+func (r *PostRepository) Get(ctx context.Context, id int) (*model.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM status_page_post WHERE id = $1`
+
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page post: %w", err)
+	}
+
+	if err := r.loadUpdates(ctx, []*model.Post{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// GetByPageID retrieves the latest posts of a page with their updates, most
+// recently updated first
+func (r *PostRepository) GetByPageID(ctx context.Context, pageID int, limit int) ([]*model.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM status_page_post
+		WHERE page_id = $1
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $2
+	`
+	return r.queryPosts(ctx, query, pageID, limit)
+}
+
+// GetCurrent retrieves the posts of a page that are still open or were
+// updated since the given time, most recently updated first
+func (r *PostRepository) GetCurrent(ctx context.Context, pageID int, since time.Time) ([]*model.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM status_page_post
+		WHERE page_id = $1
+			AND (status NOT IN ('resolved', 'completed') OR updated_at >= $2::timestamp)
+		ORDER BY updated_at DESC, id DESC
+	`
+	return r.queryPosts(ctx, query, pageID, since.UTC())
+}
+
+func (r *PostRepository) queryPosts(ctx context.Context, query string, args ...any) ([]*model.Post, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status page posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status page post: %w", err)
+		}
+		posts = append(posts, post)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status page posts: %w", err)
+	}
+
+	if err := r.loadUpdates(ctx, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// loadUpdates fills in the updates of the posts, newest first
+func (r *PostRepository) loadUpdates(ctx context.Context, posts []*model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(posts))
+	byID := make(map[int]*model.Post, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+		byID[post.ID] = post
+		post.Updates = nil
+	}
+
+	query := `
+		SELECT id, post_id, status, message, created_at
+		FROM status_page_post_update
+		WHERE post_id = ANY($1)
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query status page post updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var update model.PostUpdate
+		if err := rows.Scan(&update.ID, &update.PostID, &update.Status, &update.Message, &update.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan status page post update: %w", err)
+		}
+		post := byID[update.PostID]
+		post.Updates = append(post.Updates, update)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating status page post updates: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a post along with its updates
+func (r *PostRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM status_page_post WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status page post: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
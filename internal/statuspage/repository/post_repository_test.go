@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewPostRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	targetID := createTestTarget(t, tx, user.ID, "up")
+	page, err := NewStatusPageRepository(tx).Create(context.Background(), &model.StatusPage{
+		UserID:     user.ID,
+		Slug:       "acme",
+		Title:      "Acme Status",
+		Components: []model.Component{{Name: "API", TargetIDs: []int{targetID}}},
+	})
+	assert.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	incident, err := repo.Create(context.Background(), &model.Post{
+		PageID:    page.ID,
+		Kind:      model.PostIncident,
+		Title:     "Slow API",
+		Status:    model.PostInvestigating,
+		CreatedAt: now.Add(-2 * time.Hour),
+		UpdatedAt: now.Add(-2 * time.Hour),
+		Updates:   []model.PostUpdate{{Status: model.PostInvestigating, Message: "Looking into it", CreatedAt: now.Add(-2 * time.Hour)}},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, incident.ID)
+	assert.NotZero(t, incident.Updates[0].ID)
+
+	starts, ends := now.Add(24*time.Hour), now.Add(26*time.Hour)
+	maintenance, err := repo.Create(context.Background(), &model.Post{
+		PageID:    page.ID,
+		Kind:      model.PostMaintenance,
+		Title:     "Database upgrade",
+		Status:    model.PostScheduled,
+		StartsAt:  &starts,
+		EndsAt:    &ends,
+		CreatedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-time.Hour),
+		Updates:   []model.PostUpdate{{Status: model.PostScheduled, Message: "Expect a short outage", CreatedAt: now.Add(-time.Hour)}},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.AddUpdate(context.Background(), &model.PostUpdate{PostID: incident.ID, Status: model.PostResolved, Message: "Fixed", CreatedAt: now}))
+	assert.ErrorIs(t, repo.AddUpdate(context.Background(), &model.PostUpdate{PostID: -1, Status: model.PostResolved, Message: "Fixed", CreatedAt: now}), ErrPostNotFound)
+
+	got, err := repo.Get(context.Background(), incident.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PostResolved, got.Status)
+	assert.Equal(t, now, got.UpdatedAt)
+	assert.Len(t, got.Updates, 2)
+	assert.Equal(t, "Fixed", got.Updates[0].Message)
+
+	posts, err := repo.GetByPageID(context.Background(), page.ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, incident.ID, posts[0].ID)
+	assert.Equal(t, ends, posts[1].EndsAt.UTC())
+
+	// The resolved incident drops out once it is older than the cutoff
+	current, err := repo.GetCurrent(context.Background(), page.ID, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, current, 1)
+	assert.Equal(t, maintenance.ID, current[0].ID)
+
+	assert.NoError(t, repo.Delete(context.Background(), incident.ID))
+	_, err = repo.Get(context.Background(), incident.ID)
+	assert.ErrorIs(t, err, ErrPostNotFound)
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+var ErrStatusPageNotFound = errors.New("status page not found")
+
+type StatusPageRepositoryInterface interface {
+	Create(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error)
+	Get(ctx context.Context, id int) (*model.StatusPage, error)
+	GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error)
+	GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error)
+	Update(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error)
+	VerifyDomain(ctx context.Context, id int, at time.Time) error
+	Delete(ctx context.Context, id int) error
+	GetTargetStates(ctx context.Context, targetIDs []int) ([]model.TargetState, error)
+	GetIncidents(ctx context.Context, targetIDs []int, since time.Time) ([]model.PageIncident, error)
+}
+
+var _ StatusPageRepositoryInterface = (*StatusPageRepository)(nil)
+
+// StatusPageRepository handles database operations for status pages, and
+// reads the target states, check results and incidents they show
+type StatusPageRepository struct {
+	db database.Querier
+}
+
+// NewStatusPageRepository creates a new status page repository
+func NewStatusPageRepository(db database.Querier) *StatusPageRepository {
+	return &StatusPageRepository{db: db}
+}
+
+// Create inserts a page along with its components
+func (r *StatusPageRepository) Create(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error) {
+	query := `
+		INSERT INTO status_page (user_id, slug, title, logo_url, domain, domain_token, domain_verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	newPage := *page
+	err := r.db.QueryRowContext(ctx, query, page.UserID, page.Slug, page.Title, page.LogoURL, page.Domain,
+		page.DomainToken, page.DomainVerifiedAt).Scan(&newPage.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status page: %w", err)
+	}
+
+	components, err := r.insertComponents(ctx, newPage.ID, page.Components)
+	if err != nil {
+		return nil, err
+	}
+	newPage.Components = components
+
+	return &newPage, nil
+}
+
+// insertComponents stores the components of a page in order
+func (r *StatusPageRepository) insertComponents(ctx context.Context, pageID int, components []model.Component) ([]model.Component, error) {
+	query := `
+		INSERT INTO status_page_component (page_id, name, position, target_ids)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	inserted := make([]model.Component, 0, len(components))
+	for i, component := range components {
+		err := r.db.QueryRowContext(ctx, query, pageID, component.Name, i, pq.Array(component.TargetIDs)).Scan(&component.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add status page component: %w", err)
+		}
+		inserted = append(inserted, component)
+	}
+	return inserted, nil
+}
+
+// loadComponents fills in the components of a page
+func (r *StatusPageRepository) loadComponents(ctx context.Context, page *model.StatusPage) error {
+	query := `
+		SELECT id, name, target_ids
+		FROM status_page_component
+		WHERE page_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, page.ID)
+	if err != nil {
+		return fmt.Errorf("failed to query status page components: %w", err)
+	}
+	defer rows.Close()
+
+	page.Components = nil
+	for rows.Next() {
+		var component model.Component
+		var targetIDs pq.Int64Array
+		if err := rows.Scan(&component.ID, &component.Name, &targetIDs); err != nil {
+			return fmt.Errorf("failed to scan status page component: %w", err)
+		}
+		for _, id := range targetIDs {
+			component.TargetIDs = append(component.TargetIDs, int(id))
+		}
+		page.Components = append(page.Components, component)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating status page components: %w", err)
+	}
+	return nil
+}
+
+const pageColumns = `id, user_id, slug, title, logo_url, domain, domain_token, domain_verified_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPage(row rowScanner) (*model.StatusPage, error) {
+	page := &model.StatusPage{}
+	err := row.Scan(&page.ID, &page.UserID, &page.Slug, &page.Title, &page.LogoURL, &page.Domain,
+		&page.DomainToken, &page.DomainVerifiedAt)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// getOne retrieves the page matching a condition, with its components
+func (r *StatusPageRepository) getOne(ctx context.Context, condition string, value any) (*model.StatusPage, error) {
+	query := `SELECT ` + pageColumns + ` FROM status_page WHERE ` + condition
+
+	page, err := scanPage(r.db.QueryRowContext(ctx, query, value))
+	if err == sql.ErrNoRows {
+		return nil, ErrStatusPageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+
+	if err := r.loadComponents(ctx, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *StatusPageRepository) Get(ctx context.Context, id int) (*model.StatusPage, error) {
+	return r.getOne(ctx, "id = $1", id)
+}
+
+func (r *StatusPageRepository) GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error) {
+	return r.getOne(ctx, "slug = $1", slug)
+}
+
+// GetByDomain retrieves the page served on a domain. Pages without a domain,
+// or whose domain is not verified yet, never match.
+func (r *StatusPageRepository) GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error) {
+	if domain == "" {
+		return nil, ErrStatusPageNotFound
+	}
+	return r.getOne(ctx, "domain = $1 AND domain_verified_at IS NOT NULL", domain)
+}
+
+// GetByUserID retrieves all pages owned by a user, with their components
+func (r *StatusPageRepository) GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error) {
+	query := `SELECT ` + pageColumns + ` FROM status_page WHERE user_id = $1 ORDER BY title, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []*model.StatusPage
+	for rows.Next() {
+		page, err := scanPage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status pages: %w", err)
+	}
+
+	for _, page := range pages {
+		if err := r.loadComponents(ctx, page); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// Update changes a page's settings and replaces its components
+func (r *StatusPageRepository) Update(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error) {
+	query := `
+		UPDATE status_page
+		SET slug = $1, title = $2, logo_url = $3, domain = $4, domain_token = $5, domain_verified_at = $6
+		WHERE id = $7
+	`
+
+	result, err := r.db.ExecContext(ctx, query, page.Slug, page.Title, page.LogoURL, page.Domain,
+		page.DomainToken, page.DomainVerifiedAt, page.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status page: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil, ErrStatusPageNotFound
+	}
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM status_page_component WHERE page_id = $1`, page.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete status page components: %w", err)
+	}
+	components, err := r.insertComponents(ctx, page.ID, page.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *page
+	updated.Components = components
+	return &updated, nil
+}
+
+// VerifyDomain marks the domain of a page as proven, which starts serving the
+// page on it. It fails when another page already holds the domain.
+func (r *StatusPageRepository) VerifyDomain(ctx context.Context, id int, at time.Time) error {
+	query := `UPDATE status_page SET domain_verified_at = $1 WHERE id = $2 AND domain <> ''`
+
+	result, err := r.db.ExecContext(ctx, query, at.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to verify status page domain: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrStatusPageNotFound
+	}
+	return nil
+}
+
+// Delete removes a page along with its components
+func (r *StatusPageRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM status_page WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status page: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrStatusPageNotFound
+	}
+	return nil
+}
+
+// GetTargetStates returns the status of the targets that still exist.
+// Disabled targets are reported as paused.
+func (r *StatusPageRepository) GetTargetStates(ctx context.Context, targetIDs []int) ([]model.TargetState, error) {
+	query := `
+		SELECT id, CASE WHEN enabled THEN COALESCE(status, '') ELSE 'paused' END
+		FROM target
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(targetIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query target states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []model.TargetState
+	for rows.Next() {
+		var state model.TargetState
+		if err := rows.Scan(&state.TargetID, &state.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan target state: %w", err)
+		}
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+// GetIncidents returns the incidents of the targets that are unresolved or
+// were resolved since the given time, newest first
+func (r *StatusPageRepository) GetIncidents(ctx context.Context, targetIDs []int, since time.Time) ([]model.PageIncident, error) {
+	query := `
+		SELECT id, target_id, status, started_at, resolved_at
+		FROM incident
+		WHERE target_id = ANY($1) AND (resolved_at IS NULL OR resolved_at >= $2::timestamp)
+		ORDER BY started_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(targetIDs), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status page incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []model.PageIncident
+	for rows.Next() {
+		var incident model.PageIncident
+		err := rows.Scan(&incident.ID, &incident.TargetID, &incident.Status, &incident.StartedAt, &incident.ResolvedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status page incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, rows.Err()
+}
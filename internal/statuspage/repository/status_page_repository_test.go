@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	incidentRepo "github.com/shuvo-paul/uptimebot/internal/incident/repository"
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	monitorRepo "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestTarget(t *testing.T, tx *sql.Tx, userID int, status string) int {
+	target, err := monitorRepo.NewTargetRepository(tx).Create(context.Background(), monitorModel.UserTarget{
+		UserID: userID,
+		Target: &core.Target{
+			URL:             "https://example.org",
+			Status:          status,
+			Enabled:         true,
+			Interval:        30 * time.Second,
+			StatusChangedAt: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+	return target.ID
+}
+
+func TestStatusPageRepository_CRUD(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewStatusPageRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	api := createTestTarget(t, tx, user.ID, "up")
+	web := createTestTarget(t, tx, user.ID, "down")
+
+	page, err := repo.Create(context.Background(), &model.StatusPage{
+		UserID:      user.ID,
+		Slug:        "acme",
+		Title:       "Acme Status",
+		Domain:      "status.acme.test",
+		DomainToken: "challenge",
+		Components: []model.Component{
+			{Name: "API", TargetIDs: []int{api}},
+			{Name: "Website", TargetIDs: []int{web, api}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, page.ID)
+
+	bySlug, err := repo.GetBySlug(context.Background(), "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, page.ID, bySlug.ID)
+	assert.Len(t, bySlug.Components, 2)
+	assert.Equal(t, "Website", bySlug.Components[1].Name)
+	assert.Equal(t, []int{web, api}, bySlug.Components[1].TargetIDs)
+
+	assert.Equal(t, "challenge", bySlug.DomainToken)
+	assert.Nil(t, bySlug.DomainVerifiedAt)
+
+	// The page is only served on its domain once the domain is verified
+	_, err = repo.GetByDomain(context.Background(), "status.acme.test")
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+	assert.NoError(t, repo.VerifyDomain(context.Background(), page.ID, time.Now()))
+	byDomain, err := repo.GetByDomain(context.Background(), "status.acme.test")
+	assert.NoError(t, err)
+	assert.Equal(t, page.ID, byDomain.ID)
+	assert.NotNil(t, byDomain.DomainVerifiedAt)
+	_, err = repo.GetByDomain(context.Background(), "")
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+
+	bySlug.Title = "Acme"
+	bySlug.Components = bySlug.Components[:1]
+	_, err = repo.Update(context.Background(), bySlug)
+	assert.NoError(t, err)
+
+	pages, err := repo.GetByUserID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, "Acme", pages[0].Title)
+	assert.Len(t, pages[0].Components, 1)
+
+	assert.NoError(t, repo.Delete(context.Background(), page.ID))
+	_, err = repo.Get(context.Background(), page.ID)
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+}
+
+func TestStatusPageRepository_PageData(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewStatusPageRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	targetID := createTestTarget(t, tx, user.ID, "down")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	targetRepo := monitorRepo.NewTargetRepository(tx)
+	assert.NoError(t, targetRepo.SaveResult(context.Background(), targetID, core.Result{Status: "up", CheckedAt: now.Add(-time.Hour)}))
+	assert.NoError(t, targetRepo.SaveResult(context.Background(), targetID, core.Result{Status: "down", StatusCode: 503, CheckedAt: now}))
+
+	_, _, err = incidentRepo.NewIncidentRepository(tx).Open(context.Background(), targetID, "down", now)
+	assert.NoError(t, err)
+
+	states, err := repo.GetTargetStates(context.Background(), []int{targetID})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TargetState{{TargetID: targetID, Status: "down"}}, states)
+
+	incidents, err := repo.GetIncidents(context.Background(), []int{targetID}, now.AddDate(0, 0, -7))
+	assert.NoError(t, err)
+	assert.Len(t, incidents, 1)
+	assert.True(t, incidents[0].Active())
+}
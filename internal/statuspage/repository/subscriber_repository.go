@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/database"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+)
+
+var ErrSubscriberNotFound = errors.New("status page subscriber not found")
+
+type SubscriberRepositoryInterface interface {
+	Create(ctx context.Context, subscriber *model.Subscriber) (*model.Subscriber, error)
+	Get(ctx context.Context, id int) (*model.Subscriber, error)
+	GetByAddress(ctx context.Context, pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error)
+	GetByConfirmToken(ctx context.Context, token string) (*model.Subscriber, error)
+	GetByUnsubscribeToken(ctx context.Context, token string) (*model.Subscriber, error)
+	GetByPageID(ctx context.Context, pageID int) ([]*model.Subscriber, error)
+	GetConfirmed(ctx context.Context, pageID int) ([]*model.Subscriber, error)
+	RenewConfirmToken(ctx context.Context, id int, token string, expiresAt time.Time) error
+	Confirm(ctx context.Context, id int, confirmedAt time.Time) error
+	Delete(ctx context.Context, id int) error
+}
+
+var _ SubscriberRepositoryInterface = (*SubscriberRepository)(nil)
+
+// SubscriberRepository handles database operations for status page subscribers
+type SubscriberRepository struct {
+	db database.Querier
+}
+
+// NewSubscriberRepository creates a new subscriber repository
+func NewSubscriberRepository(db database.Querier) *SubscriberRepository {
+	return &SubscriberRepository{db: db}
+}
+
+const subscriberColumns = `id, page_id, kind, address, confirm_token, confirm_expires_at,
+	confirmed_at, unsubscribe_token, created_at`
+
+func scanSubscriber(row rowScanner) (*model.Subscriber, error) {
+	subscriber := &model.Subscriber{}
+	err := row.Scan(&subscriber.ID, &subscriber.PageID, &subscriber.Kind, &subscriber.Address,
+		&subscriber.ConfirmToken, &subscriber.ConfirmExpiresAt, &subscriber.ConfirmedAt,
+		&subscriber.UnsubscribeToken, &subscriber.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return subscriber, nil
+}
+
+func (r *SubscriberRepository) Create(ctx context.Context, subscriber *model.Subscriber) (*model.Subscriber, error) {
+	query := `
+		INSERT INTO status_page_subscriber (page_id, kind, address, confirm_token, confirm_expires_at,
+			confirmed_at, unsubscribe_token, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	newSubscriber := *subscriber
+	err := r.db.QueryRowContext(ctx, query, subscriber.PageID, subscriber.Kind, subscriber.Address,
+		subscriber.ConfirmToken, subscriber.ConfirmExpiresAt.UTC(), utcOrNil(subscriber.ConfirmedAt),
+		subscriber.UnsubscribeToken, subscriber.CreatedAt.UTC()).Scan(&newSubscriber.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status page subscriber: %w", err)
+	}
+	return &newSubscriber, nil
+}
+
+// getOne retrieves the subscriber matching the condition
+func (r *SubscriberRepository) getOne(ctx context.Context, condition string, args ...any) (*model.Subscriber, error) {
+	query := `SELECT ` + subscriberColumns + ` FROM status_page_subscriber WHERE ` + condition
+
+	subscriber, err := scanSubscriber(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrSubscriberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page subscriber: %w", err)
+	}
+	return subscriber, nil
+}
+
+func (r *SubscriberRepository) Get(ctx context.Context, id int) (*model.Subscriber, error) {
+	return r.getOne(ctx, `id = $1`, id)
+}
+
+func (r *SubscriberRepository) GetByAddress(ctx context.Context, pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+	return r.getOne(ctx, `page_id = $1 AND kind = $2 AND address = $3`, pageID, kind, address)
+}
+
+func (r *SubscriberRepository) GetByConfirmToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return r.getOne(ctx, `confirm_token = $1`, token)
+}
+
+func (r *SubscriberRepository) GetByUnsubscribeToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return r.getOne(ctx, `unsubscribe_token = $1`, token)
+}
+
+// GetByPageID retrieves every subscriber of a page, oldest first
+func (r *SubscriberRepository) GetByPageID(ctx context.Context, pageID int) ([]*model.Subscriber, error) {
+	query := `SELECT ` + subscriberColumns + ` FROM status_page_subscriber WHERE page_id = $1 ORDER BY created_at, id`
+	return r.querySubscribers(ctx, query, pageID)
+}
+
+// GetConfirmed retrieves the subscribers of a page who get its updates
+func (r *SubscriberRepository) GetConfirmed(ctx context.Context, pageID int) ([]*model.Subscriber, error) {
+	query := `
+		SELECT ` + subscriberColumns + `
+		FROM status_page_subscriber
+		WHERE page_id = $1 AND confirmed_at IS NOT NULL
+		ORDER BY id
+	`
+	return r.querySubscribers(ctx, query, pageID)
+}
+
+func (r *SubscriberRepository) querySubscribers(ctx context.Context, query string, args ...any) ([]*model.Subscriber, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status page subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []*model.Subscriber
+	for rows.Next() {
+		subscriber, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status page subscriber: %w", err)
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status page subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// RenewConfirmToken replaces the confirmation token of a subscriber who has
+// not confirmed yet
+func (r *SubscriberRepository) RenewConfirmToken(ctx context.Context, id int, token string, expiresAt time.Time) error {
+	query := `UPDATE status_page_subscriber SET confirm_token = $1, confirm_expires_at = $2 WHERE id = $3`
+	return r.exec(ctx, query, token, expiresAt.UTC(), id)
+}
+
+func (r *SubscriberRepository) Confirm(ctx context.Context, id int, confirmedAt time.Time) error {
+	query := `UPDATE status_page_subscriber SET confirmed_at = $1 WHERE id = $2`
+	return r.exec(ctx, query, confirmedAt.UTC(), id)
+}
+
+func (r *SubscriberRepository) Delete(ctx context.Context, id int) error {
+	return r.exec(ctx, `DELETE FROM status_page_subscriber WHERE id = $1`, id)
+}
+
+// exec runs a statement on a single subscriber
+func (r *SubscriberRepository) exec(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update status page subscriber: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrSubscriberNotFound
+	}
+	return nil
+}
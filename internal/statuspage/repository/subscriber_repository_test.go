@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authRepo "github.com/shuvo-paul/uptimebot/internal/auth/repository"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriberRepository(t *testing.T) {
+	tx := testutil.GetTestTx(t)
+	repo := NewSubscriberRepository(tx)
+	user, err := authRepo.NewUserRepository(tx).SaveUser(context.Background(), &authModel.User{Email: "test@example.com", Password: "hashedpassword"})
+	assert.NoError(t, err)
+	targetID := createTestTarget(t, tx, user.ID, "up")
+	page, err := NewStatusPageRepository(tx).Create(context.Background(), &model.StatusPage{
+		UserID:     user.ID,
+		Slug:       "acme",
+		Title:      "Acme Status",
+		Components: []model.Component{{Name: "API", TargetIDs: []int{targetID}}},
+	})
+	assert.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	email, err := repo.Create(context.Background(), &model.Subscriber{
+		PageID:           page.ID,
+		Kind:             model.SubscriberEmail,
+		Address:          "carol@example.com",
+		ConfirmToken:     "confirm-1",
+		ConfirmExpiresAt: now.Add(24 * time.Hour),
+		UnsubscribeToken: "unsubscribe-1",
+		CreatedAt:        now,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, email.ID)
+
+	_, err = repo.Create(context.Background(), &model.Subscriber{
+		PageID:           page.ID,
+		Kind:             model.SubscriberWebhook,
+		Address:          "https://hooks.example.com/status",
+		ConfirmToken:     "confirm-2",
+		ConfirmExpiresAt: now,
+		ConfirmedAt:      &now,
+		UnsubscribeToken: "unsubscribe-2",
+		CreatedAt:        now,
+	})
+	assert.NoError(t, err)
+
+	byAddress, err := repo.GetByAddress(context.Background(), page.ID, model.SubscriberEmail, "carol@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, email.ID, byAddress.ID)
+	assert.False(t, byAddress.Confirmed())
+
+	confirmed, err := repo.GetConfirmed(context.Background(), page.ID)
+	assert.NoError(t, err)
+	assert.Len(t, confirmed, 1)
+	assert.Equal(t, model.SubscriberWebhook, confirmed[0].Kind)
+
+	assert.NoError(t, repo.RenewConfirmToken(context.Background(), email.ID, "confirm-3", now.Add(48*time.Hour)))
+	_, err = repo.GetByConfirmToken(context.Background(), "confirm-1")
+	assert.ErrorIs(t, err, ErrSubscriberNotFound)
+	byToken, err := repo.GetByConfirmToken(context.Background(), "confirm-3")
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(48*time.Hour), byToken.ConfirmExpiresAt.UTC())
+
+	assert.NoError(t, repo.Confirm(context.Background(), email.ID, now))
+	subscribers, err := repo.GetByPageID(context.Background(), page.ID)
+	assert.NoError(t, err)
+	assert.Len(t, subscribers, 2)
+	assert.True(t, subscribers[0].Confirmed())
+
+	byUnsubscribe, err := repo.GetByUnsubscribeToken(context.Background(), "unsubscribe-1")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(context.Background(), byUnsubscribe.ID))
+	_, err = repo.Get(context.Background(), email.ID)
+	assert.ErrorIs(t, err, ErrSubscriberNotFound)
+	assert.ErrorIs(t, repo.Delete(context.Background(), email.ID), ErrSubscriberNotFound)
+}
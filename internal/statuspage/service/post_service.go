@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+)
+
+const (
+	// FeedSize is how many posts the feeds of a page carry
+	FeedSize = 50
+	// ownerPostLimit is how many posts the owner sees when managing a page
+	ownerPostLimit = 100
+)
+
+// ErrPostNotFound is returned when the requested post does not exist.
+var ErrPostNotFound = errors.New("status page post not found")
+
+// PageService is the part of the status page service posts rely on
+type PageService interface {
+	Get(ctx context.Context, id int, userID int) (*model.StatusPage, error)
+}
+
+// PostNotifier tells the subscribers of a page about a new update. It returns
+// right away, delivery happens in the background.
+type PostNotifier interface {
+	Notify(page *model.StatusPage, post *model.Post, update *model.PostUpdate)
+}
+
+type PostServiceInterface interface {
+	GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Post, error)
+	Create(ctx context.Context, post *model.Post, update *model.PostUpdate, userID int) error
+	AddUpdate(ctx context.Context, update *model.PostUpdate, userID int) error
+	Delete(ctx context.Context, id int, userID int) error
+	Feed(ctx context.Context, page *model.StatusPage) ([]*model.Post, error)
+}
+
+var _ PostServiceInterface = (*PostService)(nil)
+
+// PostService manages the announcements owners publish on their status pages
+type PostService struct {
+	repo        repository.PostRepositoryInterface
+	pageService PageService
+	notifier    PostNotifier
+	now         func() time.Time
+}
+
+func NewPostService(repo repository.PostRepositoryInterface, pageService PageService, notifier PostNotifier) *PostService {
+	return &PostService{
+		repo:        repo,
+		pageService: pageService,
+		notifier:    notifier,
+		now:         time.Now,
+	}
+}
+
+// get retrieves a post and its page after verifying the user owns the page
+func (s *PostService) get(ctx context.Context, id int, userID int) (*model.Post, *model.StatusPage, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	post, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPostNotFound) {
+			return nil, nil, fmt.Errorf("%w: post with id %d not found", ErrPostNotFound, id)
+		}
+		return nil, nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	page, err := s.pageService.Get(ctx, post.PageID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return post, page, nil
+}
+
+// GetByPageID lists the latest posts of a page the user owns
+func (s *PostService) GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Post, error) {
+	if _, err := s.pageService.Get(ctx, pageID, userID); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.repo.GetByPageID(ctx, pageID, ownerPostLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	return posts, nil
+}
+
+// Create publishes a post on a page the user owns, with its first update,
+// and tells the subscribers of the page
+func (s *PostService) Create(ctx context.Context, post *model.Post, update *model.PostUpdate, userID int) error {
+	page, err := s.pageService.Get(ctx, post.PageID, userID)
+	if err != nil {
+		return err
+	}
+
+	post.Title = strings.TrimSpace(post.Title)
+	update.Message = strings.TrimSpace(update.Message)
+	if err := post.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := post.ValidateUpdate(update); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	now := s.now()
+	update.CreatedAt = now
+	post.ID = 0
+	post.Status = update.Status
+	post.CreatedAt = now
+	post.UpdatedAt = now
+	post.Updates = []model.PostUpdate{*update}
+
+	newPost, err := s.repo.Create(ctx, post)
+	if err != nil {
+		return fmt.Errorf("failed to create post: %w", err)
+	}
+	post.ID = newPost.ID
+	post.Updates = newPost.Updates
+	*update = newPost.Updates[0]
+
+	s.notifier.Notify(page, post, update)
+	return nil
+}
+
+// AddUpdate posts a new update to a post on a page the user owns, and tells
+// the subscribers of the page. Its status becomes the status of the post.
+func (s *PostService) AddUpdate(ctx context.Context, update *model.PostUpdate, userID int) error {
+	post, page, err := s.get(ctx, update.PostID, userID)
+	if err != nil {
+		return err
+	}
+
+	update.Message = strings.TrimSpace(update.Message)
+	if err := post.ValidateUpdate(update); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	update.CreatedAt = s.now()
+	if err := s.repo.AddUpdate(ctx, update); err != nil {
+		return fmt.Errorf("failed to add post update: %w", err)
+	}
+	post.Status = update.Status
+	post.UpdatedAt = update.CreatedAt
+	post.Updates = append([]model.PostUpdate{*update}, post.Updates...)
+
+	s.notifier.Notify(page, post, update)
+	return nil
+}
+
+func (s *PostService) Delete(ctx context.Context, id int, userID int) error {
+	if _, _, err := s.get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	return nil
+}
+
+// Feed lists the latest posts of a public page, most recently updated first
+func (s *PostService) Feed(ctx context.Context, page *model.StatusPage) ([]*model.Post, error) {
+	posts, err := s.repo.GetByPageID(ctx, page.ID, FeedSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	return posts, nil
+}
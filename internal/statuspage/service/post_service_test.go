@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPostRepository struct {
+	createFunc      func(post *model.Post) (*model.Post, error)
+	getFunc         func(id int) (*model.Post, error)
+	getByPageIDFunc func(pageID int, limit int) ([]*model.Post, error)
+	getCurrentFunc  func(pageID int, since time.Time) ([]*model.Post, error)
+	addUpdateFunc   func(update *model.PostUpdate) error
+	deleteFunc      func(id int) error
+}
+
+func (m *mockPostRepository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
+	return m.createFunc(post)
+}
+
+func (m *mockPostRepository) Get(ctx context.Context, id int) (*model.Post, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockPostRepository) GetByPageID(ctx context.Context, pageID int, limit int) ([]*model.Post, error) {
+	return m.getByPageIDFunc(pageID, limit)
+}
+
+func (m *mockPostRepository) GetCurrent(ctx context.Context, pageID int, since time.Time) ([]*model.Post, error) {
+	return m.getCurrentFunc(pageID, since)
+}
+
+func (m *mockPostRepository) AddUpdate(ctx context.Context, update *model.PostUpdate) error {
+	return m.addUpdateFunc(update)
+}
+
+func (m *mockPostRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+type mockPostNotifier struct {
+	notified []*model.PostUpdate
+}
+
+func (m *mockPostNotifier) Notify(page *model.StatusPage, post *model.Post, update *model.PostUpdate) {
+	m.notified = append(m.notified, update)
+}
+
+type mockPageService struct{}
+
+func (m *mockPageService) Get(ctx context.Context, id int, userID int) (*model.StatusPage, error) {
+	if userID != 1 {
+		return nil, ErrUnauthorized
+	}
+	return &model.StatusPage{ID: id, UserID: userID, Slug: "acme"}, nil
+}
+
+func TestPostService_Create(t *testing.T) {
+	now := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("publishes the post with its first update", func(t *testing.T) {
+		var stored *model.Post
+		repo := &mockPostRepository{
+			createFunc: func(post *model.Post) (*model.Post, error) {
+				stored = post
+				created := *post
+				created.ID = 4
+				created.Updates = []model.PostUpdate{post.Updates[0]}
+				created.Updates[0].ID = 8
+				return &created, nil
+			},
+		}
+		notifier := &mockPostNotifier{}
+		service := NewPostService(repo, &mockPageService{}, notifier)
+		service.now = func() time.Time { return now }
+
+		post := &model.Post{PageID: 3, Kind: model.PostIncident, Title: " Slow API "}
+		update := &model.PostUpdate{Status: model.PostInvestigating, Message: "Looking into it"}
+		assert.NoError(t, service.Create(context.Background(), post, update, 1))
+		assert.Equal(t, 4, post.ID)
+		assert.Equal(t, 8, update.ID)
+		assert.Equal(t, "Slow API", stored.Title)
+		assert.Equal(t, model.PostInvestigating, stored.Status)
+		assert.Equal(t, now, stored.UpdatedAt)
+		assert.Equal(t, now, stored.Updates[0].CreatedAt)
+		assert.Equal(t, []*model.PostUpdate{update}, notifier.notified)
+	})
+
+	t.Run("rejects a status of the other kind", func(t *testing.T) {
+		service := NewPostService(&mockPostRepository{}, &mockPageService{}, &mockPostNotifier{})
+
+		post := &model.Post{PageID: 3, Kind: model.PostIncident, Title: "Slow API"}
+		err := service.Create(context.Background(), post, &model.PostUpdate{Status: model.PostScheduled, Message: "Soon"}, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("rejects maintenance without a window", func(t *testing.T) {
+		service := NewPostService(&mockPostRepository{}, &mockPageService{}, &mockPostNotifier{})
+
+		post := &model.Post{PageID: 3, Kind: model.PostMaintenance, Title: "Upgrade"}
+		err := service.Create(context.Background(), post, &model.PostUpdate{Status: model.PostScheduled, Message: "Soon"}, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("rejects pages of other users", func(t *testing.T) {
+		service := NewPostService(&mockPostRepository{}, &mockPageService{}, &mockPostNotifier{})
+
+		post := &model.Post{PageID: 3, Kind: model.PostIncident, Title: "Slow API"}
+		err := service.Create(context.Background(), post, &model.PostUpdate{Status: model.PostInvestigating, Message: "Looking"}, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestPostService_AddUpdate(t *testing.T) {
+	now := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	var added *model.PostUpdate
+	repo := &mockPostRepository{
+		getFunc: func(id int) (*model.Post, error) {
+			if id != 4 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &model.Post{ID: id, PageID: 3, Kind: model.PostIncident, Status: model.PostInvestigating}, nil
+		},
+		addUpdateFunc: func(update *model.PostUpdate) error {
+			added = update
+			return nil
+		},
+	}
+	notifier := &mockPostNotifier{}
+	service := NewPostService(repo, &mockPageService{}, notifier)
+	service.now = func() time.Time { return now }
+
+	assert.NoError(t, service.AddUpdate(context.Background(), &model.PostUpdate{PostID: 4, Status: model.PostResolved, Message: "Fixed"}, 1))
+	assert.Equal(t, now, added.CreatedAt)
+	assert.Equal(t, []*model.PostUpdate{added}, notifier.notified)
+
+	err := service.AddUpdate(context.Background(), &model.PostUpdate{PostID: 4, Status: model.PostResolved, Message: " "}, 1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	err = service.AddUpdate(context.Background(), &model.PostUpdate{PostID: 4, Status: model.PostResolved, Message: "Fixed"}, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	err = service.AddUpdate(context.Background(), &model.PostUpdate{PostID: 5, Status: model.PostResolved, Message: "Fixed"}, 1)
+	assert.ErrorIs(t, err, ErrPostNotFound)
+	assert.Len(t, notifier.notified, 1)
+}
+
+func TestPostService_Feed(t *testing.T) {
+	repo := &mockPostRepository{
+		getByPageIDFunc: func(pageID int, limit int) ([]*model.Post, error) {
+			assert.Equal(t, 3, pageID)
+			assert.Equal(t, FeedSize, limit)
+			return []*model.Post{{ID: 4}}, nil
+		},
+	}
+	service := NewPostService(repo, &mockPageService{}, &mockPostNotifier{})
+
+	posts, err := service.Feed(context.Background(), &model.StatusPage{ID: 3})
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+)
+
+const (
+	// HistoryDays is how many days of uptime a page shows
+	HistoryDays = 90
+	// recentIncidentPeriod is how long resolved incidents stay on a page
+	recentIncidentPeriod = 7 * 24 * time.Hour
+)
+
+// Common errors returned by the status page service.
+var (
+	// ErrUnauthorized is returned when a user attempts to access a page they don't own.
+	ErrUnauthorized = errors.New("unauthorized access to status page")
+	// ErrStatusPageNotFound is returned when the requested page does not exist.
+	ErrStatusPageNotFound = errors.New("status page not found")
+	// ErrInvalidInput is returned when the provided input parameters are invalid.
+	ErrInvalidInput = errors.New("invalid input parameters")
+	// ErrDomainNotVerified is returned when a page's challenge record is missing from its domain.
+	ErrDomainNotVerified = errors.New("domain not verified")
+)
+
+// TargetService is the part of the target service status pages rely on
+type TargetService interface {
+	GetAllByUserID(ctx context.Context, userID int) ([]monitorModel.UserTarget, error)
+}
+
+// AvailabilityService is the part of the SLA service the uptime history of
+// pages relies on
+type AvailabilityService interface {
+	DailyAvailability(ctx context.Context, targetID int, userID int, from time.Time, days int) ([]slaModel.Availability, error)
+}
+
+type StatusPageServiceInterface interface {
+	Create(ctx context.Context, page *model.StatusPage, userID int) error
+	Get(ctx context.Context, id int, userID int) (*model.StatusPage, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error)
+	Update(ctx context.Context, page *model.StatusPage, userID int) (*model.StatusPage, error)
+	Delete(ctx context.Context, id int, userID int) error
+	VerifyDomain(ctx context.Context, id int, userID int) (*model.StatusPage, error)
+	GetTargets(ctx context.Context, userID int) ([]monitorModel.UserTarget, error)
+	GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error)
+	GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error)
+	View(ctx context.Context, page *model.StatusPage) (*model.PageView, error)
+}
+
+var _ StatusPageServiceInterface = (*StatusPageService)(nil)
+
+type StatusPageService struct {
+	repo                repository.StatusPageRepositoryInterface
+	postRepo            repository.PostRepositoryInterface
+	targetService       TargetService
+	availabilityService AvailabilityService
+	now                 func() time.Time
+	lookupTXT           func(name string) ([]string, error)
+}
+
+func NewStatusPageService(
+	repo repository.StatusPageRepositoryInterface,
+	postRepo repository.PostRepositoryInterface,
+	targetService TargetService,
+	availabilityService AvailabilityService,
+) *StatusPageService {
+	return &StatusPageService{
+		repo:                repo,
+		postRepo:            postRepo,
+		targetService:       targetService,
+		availabilityService: availabilityService,
+		now:                 time.Now,
+		lookupTXT:           net.LookupTXT,
+	}
+}
+
+// prepare normalizes a page and checks it can be saved: it must be valid, its
+// components may only show the user's own targets, and its slug and domain
+// must not be used by another page. A domain is only in use once the page
+// claiming it verified it.
+func (s *StatusPageService) prepare(ctx context.Context, page *model.StatusPage, userID int) error {
+	page.Slug = strings.ToLower(strings.TrimSpace(page.Slug))
+	page.Title = strings.TrimSpace(page.Title)
+	page.LogoURL = strings.TrimSpace(page.LogoURL)
+	page.Domain = model.NormalizeDomain(page.Domain)
+	for i := range page.Components {
+		page.Components[i].Name = strings.TrimSpace(page.Components[i].Name)
+	}
+
+	if err := page.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	targets, err := s.targetService.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+	owned := make(map[int]bool, len(targets))
+	for _, target := range targets {
+		owned[target.ID] = true
+	}
+	for _, id := range page.TargetIDs() {
+		if !owned[id] {
+			return fmt.Errorf("%w: target %d not found", ErrInvalidInput, id)
+		}
+	}
+
+	if err := s.checkUnused(ctx, page.ID, page.Slug, s.repo.GetBySlug, "slug"); err != nil {
+		return err
+	}
+	if page.Domain != "" {
+		if err := s.checkUnused(ctx, page.ID, page.Domain, s.repo.GetByDomain, "domain"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimDomain starts a new claim on the page's domain. The page is not served
+// on it until the owner publishes the claim's token, see VerifyDomain.
+func claimDomain(page *model.StatusPage) error {
+	page.DomainToken = ""
+	page.DomainVerifiedAt = nil
+	if page.Domain == "" {
+		return nil
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate domain token: %w", err)
+	}
+	page.DomainToken = hex.EncodeToString(token)
+	return nil
+}
+
+// checkUnused fails when another page than the one with pageID already has the value
+func (s *StatusPageService) checkUnused(ctx context.Context, pageID int, value string, lookup func(context.Context, string) (*model.StatusPage, error), field string) error {
+	existing, err := lookup(ctx, value)
+	if errors.Is(err, repository.ErrStatusPageNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", field, err)
+	}
+	if existing.ID != pageID {
+		return fmt.Errorf("%w: %s %s is already in use", ErrInvalidInput, field, value)
+	}
+	return nil
+}
+
+func (s *StatusPageService) Create(ctx context.Context, page *model.StatusPage, userID int) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+	page.ID = 0
+	page.UserID = userID
+
+	if err := s.prepare(ctx, page, userID); err != nil {
+		return err
+	}
+	if err := claimDomain(page); err != nil {
+		return err
+	}
+
+	newPage, err := s.repo.Create(ctx, page)
+	if err != nil {
+		return fmt.Errorf("failed to create status page: %w", err)
+	}
+	page.ID = newPage.ID
+	return nil
+}
+
+// Get retrieves a page after verifying the user owns it
+func (s *StatusPageService) Get(ctx context.Context, id int, userID int) (*model.StatusPage, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	page, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusPageNotFound) {
+			return nil, fmt.Errorf("%w: page with id %d not found", ErrStatusPageNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+
+	if page.UserID != userID {
+		return nil, fmt.Errorf("%w: user %d does not own page %d", ErrUnauthorized, userID, id)
+	}
+	return page, nil
+}
+
+func (s *StatusPageService) GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+
+	pages, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status pages: %w", err)
+	}
+	return pages, nil
+}
+
+// Update changes the settings and components of a page the user owns
+func (s *StatusPageService) Update(ctx context.Context, page *model.StatusPage, userID int) (*model.StatusPage, error) {
+	existing, err := s.Get(ctx, page.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	page.UserID = existing.UserID
+
+	if err := s.prepare(ctx, page, userID); err != nil {
+		return nil, err
+	}
+	// A page keeps its claim for as long as it keeps its domain
+	if page.Domain == existing.Domain {
+		page.DomainToken = existing.DomainToken
+		page.DomainVerifiedAt = existing.DomainVerifiedAt
+	} else if err := claimDomain(page); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status page: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *StatusPageService) Delete(ctx context.Context, id int, userID int) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete status page: %w", err)
+	}
+	return nil
+}
+
+// VerifyDomain looks up the challenge record of a page's domain, and starts
+// serving the page on the domain when the record holds the page's token
+func (s *StatusPageService) VerifyDomain(ctx context.Context, id int, userID int) (*model.StatusPage, error) {
+	page, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if page.Domain == "" {
+		return nil, fmt.Errorf("%w: page %d has no domain", ErrInvalidInput, id)
+	}
+	if page.DomainVerified() {
+		return page, nil
+	}
+
+	records, err := s.lookupTXT(page.ChallengeRecord())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to look up %s: %v", ErrDomainNotVerified, page.ChallengeRecord(), err)
+	}
+	if page.DomainToken == "" || !slices.Contains(records, page.DomainToken) {
+		return nil, fmt.Errorf("%w: %s does not hold the page's token", ErrDomainNotVerified, page.ChallengeRecord())
+	}
+
+	if err := s.checkUnused(ctx, page.ID, page.Domain, s.repo.GetByDomain, "domain"); err != nil {
+		return nil, err
+	}
+	verifiedAt := s.now()
+	if err := s.repo.VerifyDomain(ctx, page.ID, verifiedAt); err != nil {
+		return nil, fmt.Errorf("failed to verify domain: %w", err)
+	}
+	page.DomainVerifiedAt = &verifiedAt
+	return page, nil
+}
+
+// GetTargets lists the targets the user can show on a page
+func (s *StatusPageService) GetTargets(ctx context.Context, userID int) ([]monitorModel.UserTarget, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid userID", ErrInvalidInput)
+	}
+	return s.targetService.GetAllByUserID(ctx, userID)
+}
+
+// GetBySlug retrieves the page published at the slug, for anyone to see
+func (s *StatusPageService) GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error) {
+	page, err := s.repo.GetBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusPageNotFound) {
+			return nil, fmt.Errorf("%w: no page at %s", ErrStatusPageNotFound, slug)
+		}
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+	return page, nil
+}
+
+// GetByDomain retrieves the page served on the domain, for anyone to see.
+// Only pages whose domain is verified are served on it.
+func (s *StatusPageService) GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error) {
+	page, err := s.repo.GetByDomain(ctx, model.NormalizeDomain(domain))
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusPageNotFound) {
+			return nil, fmt.Errorf("%w: no page on %s", ErrStatusPageNotFound, domain)
+		}
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+	return page, nil
+}
+
+// View gathers what the public page shows: the state and uptime history of
+// every component, which incidents to list, and the posts of the owner.
+// Targets deleted since the page was set up are left out.
+func (s *StatusPageService) View(ctx context.Context, page *model.StatusPage) (*model.PageView, error) {
+	now := s.now()
+	targetIDs := page.TargetIDs()
+
+	states, err := s.repo.GetTargetStates(ctx, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target states: %w", err)
+	}
+	statuses := make(map[int]string, len(states))
+	for _, state := range states {
+		statuses[state.TargetID] = state.Status
+	}
+
+	from := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(HistoryDays - 1))
+	var uptimes []model.DailyUptime
+	for _, state := range states {
+		days, err := s.availabilityService.DailyAvailability(ctx, state.TargetID, page.UserID, from, HistoryDays)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uptime history: %w", err)
+		}
+		for i, day := range days {
+			uptimes = append(uptimes, model.DailyUptime{
+				TargetID: state.TargetID,
+				Day:      from.AddDate(0, 0, i),
+				Up:       day.Up,
+				Down:     day.Down,
+			})
+		}
+	}
+
+	incidents, err := s.repo.GetIncidents(ctx, targetIDs, now.Add(-recentIncidentPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+
+	posts, err := s.postRepo.GetCurrent(ctx, page.ID, now.Add(-recentIncidentPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+
+	view := &model.PageView{Page: page, UpdatedAt: now}
+	for _, component := range page.Components {
+		var componentStatuses []string
+		for _, id := range component.TargetIDs {
+			if status, ok := statuses[id]; ok {
+				componentStatuses = append(componentStatuses, status)
+			}
+		}
+		history := model.History(uptimes, component.TargetIDs, HistoryDays, now)
+		view.Components = append(view.Components, model.ComponentView{
+			Name:    component.Name,
+			State:   model.ComponentState(componentStatuses),
+			History: history,
+			Uptime:  model.Uptime(history),
+		})
+	}
+	view.State = model.PageState(view.Components)
+
+	for _, incident := range incidents {
+		for _, component := range page.Components {
+			if slices.Contains(component.TargetIDs, incident.TargetID) {
+				incident.Components = append(incident.Components, component.Name)
+			}
+		}
+		if incident.Active() {
+			view.Active = append(view.Active, incident)
+		} else {
+			view.Recent = append(view.Recent, incident)
+		}
+	}
+
+	cutoff := now.Add(-recentIncidentPeriod)
+	for _, post := range posts {
+		switch {
+		case post.Kind == model.PostIncident && !post.Status.Closed():
+			view.Announcements = append(view.Announcements, post)
+		case post.Upcoming(now):
+			view.Maintenance = append(view.Maintenance, post)
+		case !post.UpdatedAt.Before(cutoff) || (post.EndsAt != nil && !post.EndsAt.Before(cutoff)):
+			// Maintenance left open after its window still shows for a while
+			view.PastPosts = append(view.PastPosts, post)
+		}
+	}
+	slices.SortStableFunc(view.Maintenance, func(a, b *model.Post) int {
+		return a.StartsAt.Compare(*b.StartsAt)
+	})
+
+	return view, nil
+}
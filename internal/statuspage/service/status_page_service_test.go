@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	monitorModel "github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	slaModel "github.com/shuvo-paul/uptimebot/internal/sla/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStatusPageRepository struct {
+	createFunc          func(page *model.StatusPage) (*model.StatusPage, error)
+	getFunc             func(id int) (*model.StatusPage, error)
+	getBySlugFunc       func(slug string) (*model.StatusPage, error)
+	getByDomainFunc     func(domain string) (*model.StatusPage, error)
+	getByUserIDFunc     func(userID int) ([]*model.StatusPage, error)
+	updateFunc          func(page *model.StatusPage) (*model.StatusPage, error)
+	verifyDomainFunc    func(id int, at time.Time) error
+	deleteFunc          func(id int) error
+	getTargetStatesFunc func(targetIDs []int) ([]model.TargetState, error)
+	getIncidentsFunc    func(targetIDs []int, since time.Time) ([]model.PageIncident, error)
+}
+
+func (m *mockStatusPageRepository) Create(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error) {
+	return m.createFunc(page)
+}
+
+func (m *mockStatusPageRepository) Get(ctx context.Context, id int) (*model.StatusPage, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockStatusPageRepository) GetBySlug(ctx context.Context, slug string) (*model.StatusPage, error) {
+	return m.getBySlugFunc(slug)
+}
+
+func (m *mockStatusPageRepository) GetByDomain(ctx context.Context, domain string) (*model.StatusPage, error) {
+	return m.getByDomainFunc(domain)
+}
+
+func (m *mockStatusPageRepository) GetByUserID(ctx context.Context, userID int) ([]*model.StatusPage, error) {
+	return m.getByUserIDFunc(userID)
+}
+
+func (m *mockStatusPageRepository) Update(ctx context.Context, page *model.StatusPage) (*model.StatusPage, error) {
+	return m.updateFunc(page)
+}
+
+func (m *mockStatusPageRepository) VerifyDomain(ctx context.Context, id int, at time.Time) error {
+	return m.verifyDomainFunc(id, at)
+}
+
+func (m *mockStatusPageRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockStatusPageRepository) GetTargetStates(ctx context.Context, targetIDs []int) ([]model.TargetState, error) {
+	return m.getTargetStatesFunc(targetIDs)
+}
+
+func (m *mockStatusPageRepository) GetIncidents(ctx context.Context, targetIDs []int, since time.Time) ([]model.PageIncident, error) {
+	return m.getIncidentsFunc(targetIDs, since)
+}
+
+type mockTargetService struct {
+	targets []monitorModel.UserTarget
+}
+
+func (m *mockTargetService) GetAllByUserID(ctx context.Context, userID int) ([]monitorModel.UserTarget, error) {
+	return m.targets, nil
+}
+
+func notFound(string) (*model.StatusPage, error) {
+	return nil, repository.ErrStatusPageNotFound
+}
+
+func userTargets(ids ...int) *mockTargetService {
+	targets := &mockTargetService{}
+	for _, id := range ids {
+		targets.targets = append(targets.targets, monitorModel.UserTarget{UserID: 1, Target: &monitor.Target{ID: id}})
+	}
+	return targets
+}
+
+type mockAvailabilityService struct {
+	dailyAvailabilityFunc func(targetID int, userID int, from time.Time, days int) ([]slaModel.Availability, error)
+}
+
+func (m *mockAvailabilityService) DailyAvailability(ctx context.Context, targetID int, userID int, from time.Time, days int) ([]slaModel.Availability, error) {
+	return m.dailyAvailabilityFunc(targetID, userID, from, days)
+}
+
+func testPage() *model.StatusPage {
+	return &model.StatusPage{
+		Slug:  " Acme ",
+		Title: "Acme Status",
+		Components: []model.Component{
+			{Name: "API", TargetIDs: []int{1}},
+			{Name: "Website", TargetIDs: []int{2, 1}},
+		},
+	}
+}
+
+func TestStatusPageService_Create(t *testing.T) {
+	t.Run("normalizes and stores the page", func(t *testing.T) {
+		var stored *model.StatusPage
+		repo := &mockStatusPageRepository{
+			getBySlugFunc:   notFound,
+			getByDomainFunc: notFound,
+			createFunc: func(page *model.StatusPage) (*model.StatusPage, error) {
+				stored = page
+				created := *page
+				created.ID = 5
+				return &created, nil
+			},
+		}
+		service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(1, 2), nil)
+
+		page := testPage()
+		page.Domain = "https://Status.Acme.test/"
+		assert.NoError(t, service.Create(context.Background(), page, 1))
+		assert.Equal(t, 5, page.ID)
+		assert.Equal(t, "acme", stored.Slug)
+		assert.Equal(t, "status.acme.test", stored.Domain)
+		assert.NotEmpty(t, stored.DomainToken)
+		assert.Nil(t, stored.DomainVerifiedAt)
+		assert.Equal(t, 1, stored.UserID)
+	})
+
+	t.Run("rejects targets of other users", func(t *testing.T) {
+		repo := &mockStatusPageRepository{getBySlugFunc: notFound}
+		service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(1), nil)
+
+		err := service.Create(context.Background(), testPage(), 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("rejects a slug in use", func(t *testing.T) {
+		repo := &mockStatusPageRepository{
+			getBySlugFunc: func(slug string) (*model.StatusPage, error) {
+				return &model.StatusPage{ID: 9, Slug: slug}, nil
+			},
+		}
+		service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(1, 2), nil)
+
+		err := service.Create(context.Background(), testPage(), 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.Contains(t, err.Error(), "already in use")
+	})
+
+	t.Run("rejects an invalid page", func(t *testing.T) {
+		service := NewStatusPageService(&mockStatusPageRepository{}, &mockPostRepository{}, userTargets(1, 2), nil)
+
+		page := testPage()
+		page.Components = nil
+		assert.ErrorIs(t, service.Create(context.Background(), page, 1), ErrInvalidInput)
+	})
+}
+
+func TestStatusPageService_Update(t *testing.T) {
+	verifiedAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	var stored *model.StatusPage
+	repo := &mockStatusPageRepository{
+		getFunc: func(id int) (*model.StatusPage, error) {
+			return &model.StatusPage{ID: id, UserID: 1, Slug: "acme", Domain: "status.acme.test",
+				DomainToken: "token", DomainVerifiedAt: &verifiedAt}, nil
+		},
+		getBySlugFunc: func(slug string) (*model.StatusPage, error) {
+			return &model.StatusPage{ID: 3, Slug: slug}, nil
+		},
+		getByDomainFunc: notFound,
+		updateFunc: func(page *model.StatusPage) (*model.StatusPage, error) {
+			stored = page
+			return page, nil
+		},
+	}
+	service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(1, 2), nil)
+
+	// Keeping its own slug and domain is fine, and keeps the domain verified
+	page := testPage()
+	page.ID = 3
+	page.Domain = "status.acme.test"
+	_, err := service.Update(context.Background(), page, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", stored.DomainToken)
+	assert.Equal(t, &verifiedAt, stored.DomainVerifiedAt)
+
+	// Another domain has to be verified again
+	page = testPage()
+	page.ID = 3
+	page.Domain = "status.other.test"
+	_, err = service.Update(context.Background(), page, 1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, stored.DomainToken)
+	assert.NotEqual(t, "token", stored.DomainToken)
+	assert.Nil(t, stored.DomainVerifiedAt)
+
+	// Another user cannot update the page
+	page.ID = 3
+	_, err = service.Update(context.Background(), page, 2)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestStatusPageService_VerifyDomain(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	newService := func(records []string, lookupErr error, verified *time.Time) (*StatusPageService, *mockStatusPageRepository) {
+		repo := &mockStatusPageRepository{
+			getFunc: func(id int) (*model.StatusPage, error) {
+				return &model.StatusPage{ID: id, UserID: 1, Domain: "status.acme.test", DomainToken: "token"}, nil
+			},
+			getByDomainFunc: notFound,
+			verifyDomainFunc: func(id int, at time.Time) error {
+				*verified = at
+				return nil
+			},
+		}
+		service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(), nil)
+		service.now = func() time.Time { return now }
+		service.lookupTXT = func(name string) ([]string, error) {
+			assert.Equal(t, "_uptimebot-challenge.status.acme.test", name)
+			return records, lookupErr
+		}
+		return service, repo
+	}
+
+	t.Run("verifies a domain holding the token", func(t *testing.T) {
+		var verified time.Time
+		service, _ := newService([]string{"v=spf1 -all", "token"}, nil, &verified)
+
+		page, err := service.VerifyDomain(context.Background(), 3, 1)
+		assert.NoError(t, err)
+		assert.True(t, page.DomainVerified())
+		assert.Equal(t, now, verified)
+	})
+
+	t.Run("rejects a domain without the token", func(t *testing.T) {
+		var verified time.Time
+		service, _ := newService([]string{"other"}, nil, &verified)
+
+		_, err := service.VerifyDomain(context.Background(), 3, 1)
+		assert.ErrorIs(t, err, ErrDomainNotVerified)
+		assert.True(t, verified.IsZero())
+	})
+
+	t.Run("rejects a domain without a challenge record", func(t *testing.T) {
+		var verified time.Time
+		service, _ := newService(nil, &net.DNSError{Err: "no such host", IsNotFound: true}, &verified)
+
+		_, err := service.VerifyDomain(context.Background(), 3, 1)
+		assert.ErrorIs(t, err, ErrDomainNotVerified)
+	})
+
+	t.Run("rejects a domain another page verified", func(t *testing.T) {
+		var verified time.Time
+		service, repo := newService([]string{"token"}, nil, &verified)
+		repo.getByDomainFunc = func(domain string) (*model.StatusPage, error) {
+			return &model.StatusPage{ID: 9, Domain: domain}, nil
+		}
+
+		_, err := service.VerifyDomain(context.Background(), 3, 1)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.True(t, verified.IsZero())
+	})
+
+	t.Run("only the owner can verify", func(t *testing.T) {
+		var verified time.Time
+		service, _ := newService([]string{"token"}, nil, &verified)
+
+		_, err := service.VerifyDomain(context.Background(), 3, 2)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestStatusPageService_View(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	resolved := now.Add(-48 * time.Hour)
+
+	repo := &mockStatusPageRepository{
+		getBySlugFunc: func(slug string) (*model.StatusPage, error) {
+			page := testPage()
+			page.Slug = slug
+			page.UserID = 1
+			return page, nil
+		},
+		getTargetStatesFunc: func(targetIDs []int) ([]model.TargetState, error) {
+			assert.Equal(t, []int{1, 2}, targetIDs)
+			return []model.TargetState{{TargetID: 1, Status: "up"}, {TargetID: 2, Status: "down"}}, nil
+		},
+		getIncidentsFunc: func(targetIDs []int, since time.Time) ([]model.PageIncident, error) {
+			assert.Equal(t, now.Add(-7*24*time.Hour), since)
+			return []model.PageIncident{
+				{ID: 2, TargetID: 2, Status: "open", StartedAt: now.Add(-time.Hour)},
+				{ID: 1, TargetID: 1, Status: "resolved", StartedAt: resolved.Add(-time.Hour), ResolvedAt: &resolved},
+			}, nil
+		},
+	}
+	starts, ends := now.Add(24*time.Hour), now.Add(26*time.Hour)
+	pastEnd := now.Add(-30 * 24 * time.Hour)
+	postRepo := &mockPostRepository{
+		getCurrentFunc: func(pageID int, since time.Time) ([]*model.Post, error) {
+			assert.Equal(t, now.Add(-7*24*time.Hour), since)
+			return []*model.Post{
+				{ID: 1, Kind: model.PostIncident, Status: model.PostMonitoring, UpdatedAt: now},
+				{ID: 2, Kind: model.PostMaintenance, Status: model.PostScheduled, StartsAt: &starts, EndsAt: &ends, UpdatedAt: now},
+				{ID: 3, Kind: model.PostIncident, Status: model.PostResolved, UpdatedAt: now.Add(-time.Hour)},
+				{ID: 4, Kind: model.PostMaintenance, Status: model.PostInProgress, StartsAt: &pastEnd, EndsAt: &pastEnd, UpdatedAt: pastEnd},
+			}, nil
+		},
+	}
+	availability := &mockAvailabilityService{
+		dailyAvailabilityFunc: func(targetID int, userID int, from time.Time, days int) ([]slaModel.Availability, error) {
+			assert.Equal(t, 1, userID)
+			assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), from)
+			history := make([]slaModel.Availability, days)
+			// Target 2 was down for half of today, and maintenance is left out
+			history[days-1] = slaModel.Availability{Up: 10 * time.Hour, Excluded: time.Hour}
+			if targetID == 2 {
+				history[days-1] = slaModel.Availability{Up: 5 * time.Hour, Down: 5 * time.Hour}
+			}
+			return history, nil
+		},
+	}
+	service := NewStatusPageService(repo, postRepo, userTargets(), availability)
+	service.now = func() time.Time { return now }
+
+	page, err := service.GetBySlug(context.Background(), "Acme")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", page.Slug)
+
+	view, err := service.View(context.Background(), page)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StateDegraded, view.State)
+
+	assert.Len(t, view.Components, 2)
+	assert.Equal(t, model.StateOperational, view.Components[0].State)
+	assert.Equal(t, model.StateDegraded, view.Components[1].State)
+	assert.Len(t, view.Components[0].History, HistoryDays)
+	assert.Equal(t, 10*time.Hour, view.Components[0].History[HistoryDays-1].Up)
+	assert.Equal(t, 75.0, view.Components[1].Uptime)
+
+	assert.Len(t, view.Active, 1)
+	assert.Equal(t, []string{"Website"}, view.Active[0].Components)
+	assert.Len(t, view.Recent, 1)
+	assert.Equal(t, []string{"API", "Website"}, view.Recent[0].Components)
+
+	// Maintenance left open long after its window is no longer shown
+	assert.Equal(t, 1, view.Announcements[0].ID)
+	assert.Equal(t, 2, view.Maintenance[0].ID)
+	assert.Len(t, view.PastPosts, 1)
+	assert.Equal(t, 3, view.PastPosts[0].ID)
+}
+
+func TestStatusPageService_GetByDomain(t *testing.T) {
+	repo := &mockStatusPageRepository{getByDomainFunc: notFound}
+	service := NewStatusPageService(repo, &mockPostRepository{}, userTargets(), nil)
+
+	_, err := service.GetByDomain(context.Background(), "status.example.com:443")
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+}
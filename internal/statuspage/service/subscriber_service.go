@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+	"github.com/shuvo-paul/uptimebot/pkg/netguard"
+)
+
+// confirmExpiry is how long the confirmation link of an email subscriber works
+const confirmExpiry = 24 * time.Hour
+
+// ErrSubscriberNotFound is returned when the requested subscriber does not exist.
+var ErrSubscriberNotFound = errors.New("status page subscriber not found")
+
+// HTTPClient sends the requests of webhook subscribers
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type SubscriberServiceInterface interface {
+	Subscribe(ctx context.Context, page *model.StatusPage, subscriber *model.Subscriber) error
+	Confirm(ctx context.Context, page *model.StatusPage, token string) error
+	Unsubscribe(ctx context.Context, page *model.StatusPage, token string) error
+	GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Subscriber, error)
+	Delete(ctx context.Context, id int, userID int) error
+}
+
+var (
+	_ SubscriberServiceInterface = (*SubscriberService)(nil)
+	_ PostNotifier               = (*SubscriberService)(nil)
+)
+
+// SubscriberService signs visitors up for the posts of status pages, and
+// delivers the posts to them by email or webhook
+type SubscriberService struct {
+	repo            repository.SubscriberRepositoryInterface
+	pageService     PageService
+	mailer          email.Mailer
+	mailerMu        sync.Mutex // the mailer builds one message at a time
+	confirmTemplate *template.Template
+	updateTemplate  *template.Template
+	client          HTTPClient
+	baseURL         string
+	now             func() time.Time
+	newToken        func() string
+}
+
+func NewSubscriberService(
+	repo repository.SubscriberRepositoryInterface,
+	pageService PageService,
+	mailer email.Mailer,
+	confirmTemplate *template.Template,
+	updateTemplate *template.Template,
+	client HTTPClient,
+	baseURL string,
+) *SubscriberService {
+	if client == nil {
+		client = netguard.NewClient(10 * time.Second)
+	}
+	return &SubscriberService{
+		repo:            repo,
+		pageService:     pageService,
+		mailer:          mailer,
+		confirmTemplate: confirmTemplate,
+		updateTemplate:  updateTemplate,
+		client:          client,
+		baseURL:         baseURL,
+		now:             time.Now,
+		newToken:        func() string { return uuid.New().String() },
+	}
+}
+
+// pageURL is the address of the page on the app's own domain
+func (s *SubscriberService) pageURL(page *model.StatusPage) string {
+	return s.baseURL + page.Path()
+}
+
+func (s *SubscriberService) tokenLink(page *model.StatusPage, action string, token string) template.URL {
+	return template.URL(s.pageURL(page) + "/" + action + "?token=" + url.QueryEscape(token))
+}
+
+// Subscribe signs a visitor up for the posts of a page. Email addresses get a
+// confirmation link and receive nothing until they follow it; signing up an
+// address again sends a new link. Webhooks receive posts right away. Whether
+// the address was already subscribed is not revealed.
+func (s *SubscriberService) Subscribe(ctx context.Context, page *model.StatusPage, subscriber *model.Subscriber) error {
+	subscriber.PageID = page.ID
+	subscriber.NormalizeAddress()
+	if err := subscriber.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	now := s.now()
+	existing, err := s.repo.GetByAddress(ctx, page.ID, subscriber.Kind, subscriber.Address)
+	switch {
+	case err == nil:
+		if existing.Confirmed() {
+			return nil
+		}
+		token := s.newToken()
+		if err := s.repo.RenewConfirmToken(ctx, existing.ID, token, now.Add(confirmExpiry)); err != nil {
+			return fmt.Errorf("failed to renew confirmation: %w", err)
+		}
+		return s.sendConfirmation(page, existing.Address, token)
+	case !errors.Is(err, repository.ErrSubscriberNotFound):
+		return fmt.Errorf("failed to check subscriber: %w", err)
+	}
+
+	subscriber.ConfirmToken = s.newToken()
+	subscriber.ConfirmExpiresAt = now.Add(confirmExpiry)
+	subscriber.UnsubscribeToken = s.newToken()
+	subscriber.CreatedAt = now
+	subscriber.ConfirmedAt = nil
+	if subscriber.Kind == model.SubscriberWebhook {
+		subscriber.ConfirmedAt = &now
+	}
+
+	created, err := s.repo.Create(ctx, subscriber)
+	if err != nil {
+		return fmt.Errorf("failed to create subscriber: %w", err)
+	}
+	subscriber.ID = created.ID
+
+	if subscriber.Kind == model.SubscriberEmail {
+		return s.sendConfirmation(page, subscriber.Address, subscriber.ConfirmToken)
+	}
+	return nil
+}
+
+// byToken retrieves the subscriber of the page holding a token
+func (s *SubscriberService) byToken(ctx context.Context, page *model.StatusPage, token string, lookup func(context.Context, string) (*model.Subscriber, error)) (*model.Subscriber, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: missing token", ErrInvalidInput)
+	}
+
+	subscriber, err := lookup(ctx, token)
+	if errors.Is(err, repository.ErrSubscriberNotFound) || (err == nil && subscriber.PageID != page.ID) {
+		return nil, fmt.Errorf("%w: unknown token", ErrSubscriberNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	return subscriber, nil
+}
+
+// Confirm starts sending posts to the email subscriber the token was sent to.
+// Following the link again does nothing.
+func (s *SubscriberService) Confirm(ctx context.Context, page *model.StatusPage, token string) error {
+	subscriber, err := s.byToken(ctx, page, token, s.repo.GetByConfirmToken)
+	if err != nil {
+		return err
+	}
+	if subscriber.Confirmed() {
+		return nil
+	}
+
+	now := s.now()
+	if !subscriber.CanConfirm(now) {
+		return fmt.Errorf("%w: the confirmation link has expired, please subscribe again", ErrInvalidInput)
+	}
+	if err := s.repo.Confirm(ctx, subscriber.ID, now); err != nil {
+		return fmt.Errorf("failed to confirm subscriber: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe removes the subscriber holding the token
+func (s *SubscriberService) Unsubscribe(ctx context.Context, page *model.StatusPage, token string) error {
+	subscriber, err := s.byToken(ctx, page, token, s.repo.GetByUnsubscribeToken)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, subscriber.ID); err != nil {
+		return fmt.Errorf("failed to remove subscriber: %w", err)
+	}
+	return nil
+}
+
+// GetByPageID lists the subscribers of a page the user owns
+func (s *SubscriberService) GetByPageID(ctx context.Context, pageID int, userID int) ([]*model.Subscriber, error) {
+	if _, err := s.pageService.Get(ctx, pageID, userID); err != nil {
+		return nil, err
+	}
+
+	subscribers, err := s.repo.GetByPageID(ctx, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// Delete removes a subscriber from a page the user owns
+func (s *SubscriberService) Delete(ctx context.Context, id int, userID int) error {
+	if id <= 0 || userID <= 0 {
+		return fmt.Errorf("%w: invalid id or userID", ErrInvalidInput)
+	}
+
+	subscriber, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSubscriberNotFound) {
+			return fmt.Errorf("%w: subscriber with id %d not found", ErrSubscriberNotFound, id)
+		}
+		return fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	if _, err := s.pageService.Get(ctx, subscriber.PageID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to remove subscriber: %w", err)
+	}
+	return nil
+}
+
+// Notify delivers an update to the subscribers of the page in the background
+func (s *SubscriberService) Notify(page *model.StatusPage, post *model.Post, update *model.PostUpdate) {
+	go func() {
+		if err := s.deliver(context.Background(), page, post, update); err != nil {
+			slog.Error("Failed to notify status page subscribers", "page", page.ID, "post", post.ID, "error", err)
+		}
+	}()
+}
+
+// deliver sends an update to every confirmed subscriber of the page. A
+// failed delivery does not keep the others from getting it.
+func (s *SubscriberService) deliver(ctx context.Context, page *model.StatusPage, post *model.Post, update *model.PostUpdate) error {
+	subscribers, err := s.repo.GetConfirmed(ctx, page.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscribers: %w", err)
+	}
+
+	var errs []error
+	for _, subscriber := range subscribers {
+		var err error
+		switch subscriber.Kind {
+		case model.SubscriberEmail:
+			err = s.sendUpdate(page, post, update, subscriber)
+		case model.SubscriberWebhook:
+			err = s.postWebhook(page, post, update, subscriber)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %d: %w", subscriber.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SubscriberService) sendConfirmation(page *model.StatusPage, address string, token string) error {
+	data := struct {
+		PageTitle   string
+		PageURL     string
+		ConfirmLink template.URL
+	}{
+		PageTitle:   page.Title,
+		PageURL:     s.pageURL(page),
+		ConfirmLink: s.tokenLink(page, "confirm", token),
+	}
+	return s.sendEmail(address, "Confirm your subscription to "+page.Title, s.confirmTemplate, data, "")
+}
+
+// window describes when maintenance takes place, empty for incidents
+func window(post *model.Post) string {
+	if post.StartsAt == nil || post.EndsAt == nil {
+		return ""
+	}
+	return post.StartsAt.UTC().Format("Jan 2, 15:04") + " to " + post.EndsAt.UTC().Format("Jan 2, 15:04 MST")
+}
+
+func (s *SubscriberService) sendUpdate(page *model.StatusPage, post *model.Post, update *model.PostUpdate, subscriber *model.Subscriber) error {
+	data := struct {
+		PageTitle       string
+		PostURL         string
+		Kind            string
+		Title           string
+		Status          string
+		Message         string
+		Window          string
+		UnsubscribeLink template.URL
+	}{
+		PageTitle:       page.Title,
+		PostURL:         s.pageURL(page) + "#" + post.Anchor(),
+		Kind:            post.Kind.Label(),
+		Title:           post.Title,
+		Status:          update.Status.Label(),
+		Message:         update.Message,
+		Window:          window(post),
+		UnsubscribeLink: s.tokenLink(page, "unsubscribe", subscriber.UnsubscribeToken),
+	}
+	subject := fmt.Sprintf("[%s] %s: %s", page.Title, update.Status.Label(), post.Title)
+	return s.sendEmail(subscriber.Address, subject, s.updateTemplate, data, data.UnsubscribeLink)
+}
+
+// sendEmail sends a message built from a template. A non-empty unsubscribe
+// link is also sent as the List-Unsubscribe header, for mail clients to offer.
+func (s *SubscriberService) sendEmail(to string, subject string, tmpl *template.Template, data any, unsubscribeLink template.URL) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	s.mailerMu.Lock()
+	defer s.mailerMu.Unlock()
+
+	if err := s.mailer.SetTo(to); err != nil {
+		return fmt.Errorf("failed to set email recipient: %w", err)
+	}
+	if err := s.mailer.SetSubject(subject); err != nil {
+		return fmt.Errorf("failed to set email subject: %w", err)
+	}
+	if err := s.mailer.SetBody(buf.String()); err != nil {
+		return fmt.Errorf("failed to set email body: %w", err)
+	}
+	if unsubscribeLink != "" {
+		if err := s.mailer.SetHeader("List-Unsubscribe", "<"+string(unsubscribeLink)+">"); err != nil {
+			return fmt.Errorf("failed to set email header: %w", err)
+		}
+	}
+	if err := s.mailer.SendEmail(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// webhookPayload is the JSON body posted to webhook subscribers
+type webhookPayload struct {
+	Page struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	} `json:"page"`
+	Post struct {
+		ID       int              `json:"id"`
+		Kind     model.PostKind   `json:"kind"`
+		Title    string           `json:"title"`
+		Status   model.PostStatus `json:"status"`
+		URL      string           `json:"url"`
+		StartsAt *time.Time       `json:"starts_at,omitempty"`
+		EndsAt   *time.Time       `json:"ends_at,omitempty"`
+	} `json:"post"`
+	Update struct {
+		ID        int              `json:"id"`
+		Status    model.PostStatus `json:"status"`
+		Message   string           `json:"message"`
+		CreatedAt time.Time        `json:"created_at"`
+	} `json:"update"`
+	UnsubscribeURL string `json:"unsubscribe_url"`
+}
+
+func (s *SubscriberService) postWebhook(page *model.StatusPage, post *model.Post, update *model.PostUpdate, subscriber *model.Subscriber) error {
+	var payload webhookPayload
+	payload.Page.Title = page.Title
+	payload.Page.URL = s.pageURL(page)
+	payload.Post.ID = post.ID
+	payload.Post.Kind = post.Kind
+	payload.Post.Title = post.Title
+	payload.Post.Status = update.Status
+	payload.Post.URL = s.pageURL(page) + "#" + post.Anchor()
+	payload.Post.StartsAt = post.StartsAt
+	payload.Post.EndsAt = post.EndsAt
+	payload.Update.ID = update.ID
+	payload.Update.Status = update.Status
+	payload.Update.Message = update.Message
+	payload.Update.CreatedAt = update.CreatedAt.UTC()
+	payload.UnsubscribeURL = string(s.tokenLink(page, "unsubscribe", subscriber.UnsubscribeToken))
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscriber.Address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
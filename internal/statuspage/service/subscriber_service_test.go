@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/model"
+	"github.com/shuvo-paul/uptimebot/internal/statuspage/repository"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSubscriberRepository struct {
+	createFunc                func(subscriber *model.Subscriber) (*model.Subscriber, error)
+	getFunc                   func(id int) (*model.Subscriber, error)
+	getByAddressFunc          func(pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error)
+	getByConfirmTokenFunc     func(token string) (*model.Subscriber, error)
+	getByUnsubscribeTokenFunc func(token string) (*model.Subscriber, error)
+	getByPageIDFunc           func(pageID int) ([]*model.Subscriber, error)
+	getConfirmedFunc          func(pageID int) ([]*model.Subscriber, error)
+	renewConfirmTokenFunc     func(id int, token string, expiresAt time.Time) error
+	confirmFunc               func(id int, confirmedAt time.Time) error
+	deleteFunc                func(id int) error
+}
+
+func (m *mockSubscriberRepository) Create(ctx context.Context, subscriber *model.Subscriber) (*model.Subscriber, error) {
+	return m.createFunc(subscriber)
+}
+
+func (m *mockSubscriberRepository) Get(ctx context.Context, id int) (*model.Subscriber, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockSubscriberRepository) GetByAddress(ctx context.Context, pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+	return m.getByAddressFunc(pageID, kind, address)
+}
+
+func (m *mockSubscriberRepository) GetByConfirmToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return m.getByConfirmTokenFunc(token)
+}
+
+func (m *mockSubscriberRepository) GetByUnsubscribeToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return m.getByUnsubscribeTokenFunc(token)
+}
+
+func (m *mockSubscriberRepository) GetByPageID(ctx context.Context, pageID int) ([]*model.Subscriber, error) {
+	return m.getByPageIDFunc(pageID)
+}
+
+func (m *mockSubscriberRepository) GetConfirmed(ctx context.Context, pageID int) ([]*model.Subscriber, error) {
+	return m.getConfirmedFunc(pageID)
+}
+
+func (m *mockSubscriberRepository) RenewConfirmToken(ctx context.Context, id int, token string, expiresAt time.Time) error {
+	return m.renewConfirmTokenFunc(id, token, expiresAt)
+}
+
+func (m *mockSubscriberRepository) Confirm(ctx context.Context, id int, confirmedAt time.Time) error {
+	return m.confirmFunc(id, confirmedAt)
+}
+
+func (m *mockSubscriberRepository) Delete(ctx context.Context, id int) error {
+	return m.deleteFunc(id)
+}
+
+func newTestSubscriberService(repo *mockSubscriberRepository, mailer email.Mailer, client HTTPClient) *SubscriberService {
+	templateRenderer := renderer.New(templates.TemplateFS, flash.NewFlashStore())
+	service := NewSubscriberService(
+		repo,
+		&mockPageService{},
+		mailer,
+		templateRenderer.GetTemplate("emails:confirm-subscription").Raw(),
+		templateRenderer.GetTemplate("emails:status-update").Raw(),
+		client,
+		"https://uptimebot.example",
+	)
+	service.newToken = func() string { return "token-1" }
+	return service
+}
+
+func TestSubscriberService_Subscribe(t *testing.T) {
+	now := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+	page := &model.StatusPage{ID: 3, Slug: "acme", Title: "Acme Status"}
+
+	t.Run("sends a confirmation link to new email subscribers", func(t *testing.T) {
+		var created *model.Subscriber
+		repo := &mockSubscriberRepository{
+			getByAddressFunc: func(pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+				return nil, repository.ErrSubscriberNotFound
+			},
+			createFunc: func(subscriber *model.Subscriber) (*model.Subscriber, error) {
+				created = subscriber
+				return &model.Subscriber{ID: 7}, nil
+			},
+		}
+		mailer := &email.MailServiceMock{}
+		service := newTestSubscriberService(repo, mailer, nil)
+		service.now = func() time.Time { return now }
+
+		subscriber := &model.Subscriber{Kind: model.SubscriberEmail, Address: " Carol@Example.com "}
+		assert.NoError(t, service.Subscribe(context.Background(), page, subscriber))
+		assert.Equal(t, 7, subscriber.ID)
+		assert.Equal(t, "carol@example.com", created.Address)
+		assert.Equal(t, now.Add(confirmExpiry), created.ConfirmExpiresAt)
+		assert.False(t, created.Confirmed())
+		assert.Equal(t, []string{"carol@example.com"}, mailer.GetSetToCalls())
+		assert.Contains(t, mailer.GetSetBodyCalls()[0], "https://uptimebot.example/status/acme/confirm?token=token-1")
+		assert.Empty(t, mailer.GetSetHeaderCalls())
+	})
+
+	t.Run("confirms webhooks right away", func(t *testing.T) {
+		var created *model.Subscriber
+		repo := &mockSubscriberRepository{
+			getByAddressFunc: func(pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+				return nil, repository.ErrSubscriberNotFound
+			},
+			createFunc: func(subscriber *model.Subscriber) (*model.Subscriber, error) {
+				created = subscriber
+				return &model.Subscriber{ID: 8}, nil
+			},
+		}
+		mailer := &email.MailServiceMock{}
+		service := newTestSubscriberService(repo, mailer, nil)
+
+		assert.NoError(t, service.Subscribe(context.Background(), page, &model.Subscriber{Kind: model.SubscriberWebhook, Address: "https://hooks.example.com/status"}))
+		assert.True(t, created.Confirmed())
+		assert.Equal(t, 0, mailer.GetSendEmailCallCount())
+	})
+
+	t.Run("sends a new link to unconfirmed subscribers", func(t *testing.T) {
+		var renewed string
+		repo := &mockSubscriberRepository{
+			getByAddressFunc: func(pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+				return &model.Subscriber{ID: 7, Kind: kind, Address: address}, nil
+			},
+			renewConfirmTokenFunc: func(id int, token string, expiresAt time.Time) error {
+				renewed = token
+				return nil
+			},
+		}
+		mailer := &email.MailServiceMock{}
+		service := newTestSubscriberService(repo, mailer, nil)
+
+		assert.NoError(t, service.Subscribe(context.Background(), page, &model.Subscriber{Kind: model.SubscriberEmail, Address: "carol@example.com"}))
+		assert.Equal(t, "token-1", renewed)
+		assert.Equal(t, 1, mailer.GetSendEmailCallCount())
+	})
+
+	t.Run("does nothing for confirmed subscribers", func(t *testing.T) {
+		repo := &mockSubscriberRepository{
+			getByAddressFunc: func(pageID int, kind model.SubscriberKind, address string) (*model.Subscriber, error) {
+				return &model.Subscriber{ID: 7, ConfirmedAt: &now}, nil
+			},
+		}
+		mailer := &email.MailServiceMock{}
+		service := newTestSubscriberService(repo, mailer, nil)
+
+		assert.NoError(t, service.Subscribe(context.Background(), page, &model.Subscriber{Kind: model.SubscriberEmail, Address: "carol@example.com"}))
+		assert.Equal(t, 0, mailer.GetSendEmailCallCount())
+	})
+
+	t.Run("rejects invalid addresses", func(t *testing.T) {
+		service := newTestSubscriberService(&mockSubscriberRepository{}, &email.MailServiceMock{}, nil)
+
+		err := service.Subscribe(context.Background(), page, &model.Subscriber{Kind: model.SubscriberWebhook, Address: "http://hooks.example.com"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestSubscriberService_Confirm(t *testing.T) {
+	now := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+	page := &model.StatusPage{ID: 3, Slug: "acme"}
+	var confirmed []int
+	repo := &mockSubscriberRepository{
+		getByConfirmTokenFunc: func(token string) (*model.Subscriber, error) {
+			switch token {
+			case "valid":
+				return &model.Subscriber{ID: 7, PageID: 3, ConfirmExpiresAt: now.Add(time.Hour)}, nil
+			case "expired":
+				return &model.Subscriber{ID: 8, PageID: 3, ConfirmExpiresAt: now.Add(-time.Hour)}, nil
+			case "other-page":
+				return &model.Subscriber{ID: 9, PageID: 4, ConfirmExpiresAt: now.Add(time.Hour)}, nil
+			}
+			return nil, repository.ErrSubscriberNotFound
+		},
+		confirmFunc: func(id int, confirmedAt time.Time) error {
+			confirmed = append(confirmed, id)
+			return nil
+		},
+	}
+	service := newTestSubscriberService(repo, &email.MailServiceMock{}, nil)
+	service.now = func() time.Time { return now }
+
+	assert.NoError(t, service.Confirm(context.Background(), page, "valid"))
+	assert.Equal(t, []int{7}, confirmed)
+	assert.ErrorIs(t, service.Confirm(context.Background(), page, "expired"), ErrInvalidInput)
+	assert.ErrorIs(t, service.Confirm(context.Background(), page, "other-page"), ErrSubscriberNotFound)
+	assert.ErrorIs(t, service.Confirm(context.Background(), page, "unknown"), ErrSubscriberNotFound)
+	assert.ErrorIs(t, service.Confirm(context.Background(), page, ""), ErrInvalidInput)
+}
+
+func TestSubscriberService_Unsubscribe(t *testing.T) {
+	page := &model.StatusPage{ID: 3, Slug: "acme"}
+	var deleted []int
+	repo := &mockSubscriberRepository{
+		getByUnsubscribeTokenFunc: func(token string) (*model.Subscriber, error) {
+			if token != "valid" {
+				return nil, repository.ErrSubscriberNotFound
+			}
+			return &model.Subscriber{ID: 7, PageID: 3}, nil
+		},
+		deleteFunc: func(id int) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	service := newTestSubscriberService(repo, &email.MailServiceMock{}, nil)
+
+	assert.NoError(t, service.Unsubscribe(context.Background(), page, "valid"))
+	assert.Equal(t, []int{7}, deleted)
+	assert.ErrorIs(t, service.Unsubscribe(context.Background(), page, "unknown"), ErrSubscriberNotFound)
+	assert.ErrorIs(t, service.Unsubscribe(context.Background(), &model.StatusPage{ID: 4}, "valid"), ErrSubscriberNotFound)
+}
+
+func TestSubscriberService_Delete(t *testing.T) {
+	repo := &mockSubscriberRepository{
+		getFunc: func(id int) (*model.Subscriber, error) {
+			if id != 7 {
+				return nil, repository.ErrSubscriberNotFound
+			}
+			return &model.Subscriber{ID: id, PageID: 3}, nil
+		},
+		deleteFunc: func(id int) error { return nil },
+	}
+	service := newTestSubscriberService(repo, &email.MailServiceMock{}, nil)
+
+	assert.NoError(t, service.Delete(context.Background(), 7, 1))
+	assert.ErrorIs(t, service.Delete(context.Background(), 7, 2), ErrUnauthorized)
+	assert.ErrorIs(t, service.Delete(context.Background(), 8, 1), ErrSubscriberNotFound)
+}
+
+func TestSubscriberService_Deliver(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	repo := &mockSubscriberRepository{
+		getConfirmedFunc: func(pageID int) ([]*model.Subscriber, error) {
+			return []*model.Subscriber{
+				{ID: 1, Kind: model.SubscriberEmail, Address: "carol@example.com", UnsubscribeToken: "unsubscribe-1"},
+				{ID: 2, Kind: model.SubscriberWebhook, Address: server.URL + "/broken", UnsubscribeToken: "unsubscribe-2"},
+				{ID: 3, Kind: model.SubscriberWebhook, Address: server.URL + "/hook", UnsubscribeToken: "unsubscribe-3"},
+			}, nil
+		},
+	}
+	mailer := &email.MailServiceMock{}
+	service := newTestSubscriberService(repo, mailer, server.Client())
+
+	page := &model.StatusPage{ID: 3, Slug: "acme", Title: "Acme Status"}
+	post := &model.Post{ID: 4, Kind: model.PostIncident, Title: "Slow API"}
+	update := &model.PostUpdate{ID: 8, Status: model.PostIdentified, Message: "Found the cause"}
+
+	err := service.deliver(context.Background(), page, post, update)
+	assert.ErrorContains(t, err, "subscriber 2")
+	assert.False(t, strings.Contains(err.Error(), "subscriber 3"))
+
+	assert.Equal(t, []string{"[Acme Status] Identified: Slow API"}, mailer.GetSetSubjectCalls())
+	assert.Contains(t, mailer.GetSetBodyCalls()[0], "https://uptimebot.example/status/acme/unsubscribe?token=unsubscribe-1")
+	assert.Equal(t, []string{"List-Unsubscribe: <https://uptimebot.example/status/acme/unsubscribe?token=unsubscribe-1>"}, mailer.GetSetHeaderCalls())
+
+	assert.Equal(t, "Slow API", payload.Post.Title)
+	assert.Equal(t, "Found the cause", payload.Update.Message)
+	assert.Equal(t, "https://uptimebot.example/status/acme#post-4", payload.Post.URL)
+	assert.Equal(t, "https://uptimebot.example/status/acme/unsubscribe?token=unsubscribe-3", payload.UnsubscribeURL)
+}
@@ -5,5 +5,12 @@ import "embed"
 //go:embed layouts/*.html
 //go:embed pages/*.html
 //go:embed pages/targets/*.html
+//go:embed pages/notifiers/*.html
+//go:embed pages/incidents/*.html
+//go:embed pages/escalation/*.html
+//go:embed pages/oncall/*.html
+//go:embed pages/maintenance/*.html
+//go:embed pages/status-pages/*.html
+//go:embed pages/status/*.html
 //go:embed emails/*.html
 var TemplateFS embed.FS
@@ -0,0 +1,12 @@
+// Package civil works with calendar dates regardless of the time of day and
+// the time zone they are in.
+package civil
+
+import "time"
+
+// Day returns the number of days between the Unix epoch and t's calendar
+// date, in t's own location
+func Day(t time.Time) int {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Unix() / 86400)
+}
@@ -0,0 +1,18 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDay(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	assert.Equal(t, 0, Day(time.Date(1970, 1, 1, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, -1, Day(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 20211, Day(time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)))
+	// The same instant falls on the next day in Tokyo
+	assert.Equal(t, 20212, Day(time.Date(2025, 5, 3, 20, 0, 0, 0, time.UTC).In(tokyo)))
+}
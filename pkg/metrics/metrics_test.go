@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	registry := NewRegistry()
+	checks := registry.NewCounter("checks_total", "Checks performed.", "result")
+	up := registry.NewGauge("target_up", "Whether the target is up.", "target_id")
+	duration := registry.NewHistogram("check_duration_seconds", "Duration of checks.", []float64{0.1, 1})
+
+	checks.Inc("up")
+	checks.Inc("up")
+	checks.Add(3, "down")
+	up.Set(1, "1")
+	up.Set(0, "2")
+	up.Delete("2")
+	duration.Observe(0.05)
+	duration.Observe(0.1)
+	duration.Observe(5)
+
+	var out strings.Builder
+	registry.Write(&out)
+
+	expected := `# HELP check_duration_seconds Duration of checks.
+# TYPE check_duration_seconds histogram
+check_duration_seconds_bucket{le="0.1"} 2
+check_duration_seconds_bucket{le="1"} 2
+check_duration_seconds_bucket{le="+Inf"} 3
+check_duration_seconds_sum 5.15
+check_duration_seconds_count 3
+# HELP checks_total Checks performed.
+# TYPE checks_total counter
+checks_total{result="down"} 3
+checks_total{result="up"} 2
+# HELP target_up Whether the target is up.
+# TYPE target_up gauge
+target_up{target_id="1"} 1
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out.String(), expected)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	got := escapeLabel("a\"b\\c\nd")
+	if got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel() = %s", got)
+	}
+}
+
+func TestRegistry_DuplicateName(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("requests_total", "Requests.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a name twice to panic")
+		}
+	}()
+	registry.NewGauge("requests_total", "Requests.")
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("requests_total", "Requests.").Inc()
+
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "no token configured", expectedStatus: http.StatusOK},
+		{name: "valid token", token: "secret", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "wrong token", token: "secret", authorization: "Bearer wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "missing token", token: "secret", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			registry.Handler(tt.token).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus == http.StatusOK && !strings.Contains(w.Body.String(), "requests_total 1\n") {
+				t.Errorf("expected the counter in the body; got %s", w.Body.String())
+			}
+		})
+	}
+}
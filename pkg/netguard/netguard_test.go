@@ -0,0 +1,56 @@
+package netguard
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{host: "example.com", allowed: true},
+		{host: "93.184.216.34", allowed: true},
+		{host: "2606:2800:220:1:248:1893:25c8:1946", allowed: true},
+		{host: "localhost", allowed: false},
+		{host: "api.localhost.", allowed: false},
+		{host: "127.0.0.1", allowed: false},
+		{host: "10.0.0.5", allowed: false},
+		{host: "172.16.0.1", allowed: false},
+		{host: "192.168.1.1", allowed: false},
+		{host: "169.254.169.254", allowed: false},
+		{host: "100.64.0.1", allowed: false},
+		{host: "0.0.0.0", allowed: false},
+		{host: "::1", allowed: false},
+		{host: "fc00::1", allowed: false},
+		{host: "fe80::1", allowed: false},
+		{host: "::ffff:127.0.0.1", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := CheckHost(tt.host)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrForbiddenAddress)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress), "got %v", err)
+}
@@ -0,0 +1,16 @@
+// Package textutil holds small helpers for text shown to people, such as
+// notification messages.
+package textutil
+
+// Truncate shortens text to at most limit characters, ending it with an
+// ellipsis when it was cut. A negative limit leaves the text as it is.
+func Truncate(text string, limit int) string {
+	runes := []rune(text)
+	if limit < 0 || len(runes) <= limit {
+		return text
+	}
+	if limit == 0 {
+		return ""
+	}
+	return string(runes[:limit-1]) + "…"
+}
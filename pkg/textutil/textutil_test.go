@@ -0,0 +1,29 @@
+package textutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected string
+	}{
+		{"short text", "down", 10, "down"},
+		{"exact length", "down", 4, "down"},
+		{"cut", "connection refused", 10, "connectio…"},
+		{"counts characters", "ÄÖÜäöü", 4, "ÄÖÜ…"},
+		{"zero limit", "down", 0, ""},
+		{"negative limit", "down", -1, "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Truncate(tt.text, tt.limit))
+		})
+	}
+}
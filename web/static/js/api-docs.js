@@ -0,0 +1,216 @@
+// Renders the OpenAPI document of the API and lets people try its
+// operations from the browser. Requests carry the API token entered on the
+// page, or the session cookie when no token is given.
+(function () {
+    var container = document.getElementById("api_operations");
+    var tokenInput = document.getElementById("api_token");
+    var methods = ["get", "post", "patch", "delete"];
+    var spec;
+
+    tokenInput.value = sessionStorage.getItem("api_token") || "";
+    tokenInput.addEventListener("input", function () {
+        sessionStorage.setItem("api_token", tokenInput.value);
+    });
+
+    function element(tag, className, text) {
+        var node = document.createElement(tag);
+        if (className) {
+            node.className = className;
+        }
+        if (text !== undefined) {
+            node.textContent = text;
+        }
+        return node;
+    }
+
+    // resolve follows a local reference such as #/components/schemas/Target
+    function resolve(node) {
+        while (node && node.$ref) {
+            node = node.$ref.replace(/^#\//, "").split("/").reduce(function (parent, part) {
+                return parent[part];
+            }, spec);
+        }
+        return node;
+    }
+
+    // example builds a sample value from a schema
+    function example(schema, depth) {
+        schema = resolve(schema) || {};
+        if (depth > 5) {
+            return null;
+        }
+        if (schema.oneOf) {
+            return example(schema.oneOf[0], depth + 1);
+        }
+        if (schema.enum) {
+            return schema.enum[0];
+        }
+        var type = Array.isArray(schema.type) ? schema.type[0] : schema.type;
+        switch (type) {
+            case "object":
+                var value = {};
+                Object.keys(schema.properties || {}).forEach(function (name) {
+                    value[name] = example(schema.properties[name], depth + 1);
+                });
+                return value;
+            case "array":
+                return [example(schema.items, depth + 1)];
+            case "integer":
+                return schema.default !== undefined ? schema.default : (schema.minimum || 0);
+            case "boolean":
+                return true;
+            case "string":
+                return schema.format === "date-time" ? new Date().toISOString() : "";
+            default:
+                return null;
+        }
+    }
+
+    function renderParameter(form, parameter) {
+        parameter = resolve(parameter);
+        var row = element("div", "mb-2");
+        var label = element("label", "block text-gray-700 text-sm font-bold mb-1",
+            parameter.name + " (" + parameter.in + (parameter.required ? ", required" : "") + ")");
+        var input = element("input", "shadow appearance-none border rounded w-full py-1 px-2 text-gray-700");
+        input.name = parameter.name;
+        input.dataset.in = parameter.in;
+        input.placeholder = parameter.description || "";
+        row.appendChild(label);
+        row.appendChild(input);
+        form.appendChild(row);
+    }
+
+    function send(path, method, form, output) {
+        var query = new URLSearchParams();
+        var missing = false;
+        form.querySelectorAll("input[data-in]").forEach(function (input) {
+            if (input.dataset.in === "path") {
+                missing = missing || input.value === "";
+                path = path.replace("{" + input.name + "}", encodeURIComponent(input.value));
+            } else if (input.value !== "") {
+                query.set(input.name, input.value);
+            }
+        });
+        if (missing) {
+            output.textContent = "Fill in the path parameters first";
+            return;
+        }
+
+        var headers = {};
+        if (tokenInput.value) {
+            headers["Authorization"] = "Bearer " + tokenInput.value;
+        }
+        var options = { method: method.toUpperCase(), headers: headers, credentials: "same-origin" };
+        var body = form.querySelector("textarea");
+        if (body) {
+            headers["Content-Type"] = "application/json";
+            options.body = body.value;
+        }
+
+        var url = spec.servers[0].url + path + (query.toString() ? "?" + query : "");
+        output.textContent = "Sending " + options.method + " " + url + " ...";
+        fetch(url, options)
+            .then(function (response) {
+                return response.text().then(function (text) {
+                    try {
+                        text = JSON.stringify(JSON.parse(text), null, 2);
+                    } catch (e) {
+                        // Not JSON, show as is
+                    }
+                    output.textContent = response.status + " " + response.statusText + "\n\n" + text;
+                });
+            })
+            .catch(function (err) {
+                output.textContent = "Request failed: " + err;
+            });
+    }
+
+    function renderOperation(path, method, operation, shared) {
+        var card = element("details", "bg-white shadow rounded-lg p-4 mb-3");
+        var summary = element("summary", "cursor-pointer");
+        summary.appendChild(element("span", "method method-" + method, method.toUpperCase()));
+        summary.appendChild(element("span", "font-mono", path + " "));
+        summary.appendChild(element("span", "text-gray-600", operation.summary || ""));
+        card.appendChild(summary);
+
+        var body = element("div", "mt-4");
+        if (operation.description) {
+            body.appendChild(element("p", "mb-2", operation.description));
+        }
+        (operation.security || []).forEach(function (requirement) {
+            if (requirement.apiToken && requirement.apiToken.length) {
+                body.appendChild(element("p", "text-sm text-gray-600 mb-2", "Token scope: " + requirement.apiToken.join(", ")));
+            }
+        });
+
+        var form = element("form");
+        shared.concat(operation.parameters || []).forEach(function (parameter) {
+            renderParameter(form, parameter);
+        });
+        if (operation.requestBody) {
+            var schema = operation.requestBody.content["application/json"].schema;
+            form.appendChild(element("label", "block text-gray-700 text-sm font-bold mb-1", "Body (" + (resolve(schema).description || "JSON") + ")"));
+            var textarea = element("textarea", "shadow border rounded w-full py-1 px-2 font-mono text-sm");
+            textarea.rows = 8;
+            textarea.value = JSON.stringify(example(schema, 0), null, 2);
+            form.appendChild(textarea);
+        }
+
+        var statuses = Object.keys(operation.responses || {}).join(", ");
+        form.appendChild(element("p", "text-sm text-gray-600 my-2", "Responses: " + statuses));
+
+        var button = element("button", "bg-blue-500 hover:bg-blue-700 text-white font-bold py-1 px-3 rounded", "Send");
+        button.type = "submit";
+        form.appendChild(button);
+
+        var output = element("pre", "bg-gray-100 rounded p-2 mt-2 text-sm");
+        form.addEventListener("submit", function (event) {
+            event.preventDefault();
+            send(path, method, form, output);
+        });
+
+        body.appendChild(form);
+        body.appendChild(output);
+        card.appendChild(body);
+        return card;
+    }
+
+    function render() {
+        document.getElementById("api_title").textContent = spec.info.title + " " + spec.info.version;
+        document.getElementById("api_description").textContent = spec.info.description || "";
+
+        var sections = {};
+        (spec.tags || []).forEach(function (tag) {
+            var section = element("section", "mb-6");
+            section.appendChild(element("h2", "text-xl font-semibold mb-2", tag.name));
+            if (tag.description) {
+                section.appendChild(element("p", "text-gray-600 mb-2", tag.description));
+            }
+            sections[tag.name] = section;
+            container.appendChild(section);
+        });
+
+        Object.keys(spec.paths).forEach(function (path) {
+            var item = spec.paths[path];
+            methods.forEach(function (method) {
+                var operation = item[method];
+                if (!operation) {
+                    return;
+                }
+                var tag = (operation.tags || [])[0];
+                var section = sections[tag] || container;
+                section.appendChild(renderOperation(path, method, operation, item.parameters || []));
+            });
+        });
+    }
+
+    fetch("/api/openapi.json")
+        .then(function (response) { return response.json(); })
+        .then(function (result) {
+            spec = result;
+            render();
+        })
+        .catch(function () {
+            document.getElementById("api_error").textContent = "The API description is unavailable";
+        });
+})();
@@ -0,0 +1,40 @@
+// Renders a live preview of a notifier's message template. The form posts
+// its own fields, CSRF token included, to the preview endpoint.
+(function () {
+    var preview = document.getElementById("message_preview");
+    if (!preview) {
+        return;
+    }
+
+    var form = preview.closest("form");
+    var title = document.getElementById("preview_title");
+    var body = document.getElementById("preview_body");
+    var error = document.getElementById("preview_error");
+    var timer;
+
+    function render() {
+        fetch(preview.dataset.url, {
+            method: "POST",
+            headers: { "Content-Type": "application/x-www-form-urlencoded" },
+            body: new URLSearchParams(new FormData(form)),
+        })
+            .then(function (response) { return response.json(); })
+            .then(function (result) {
+                title.textContent = result.title || "";
+                body.textContent = result.body || "";
+                error.textContent = result.error || "";
+            })
+            .catch(function () {
+                error.textContent = "Preview is unavailable";
+            });
+    }
+
+    ["title_template", "body_template"].forEach(function (id) {
+        document.getElementById(id).addEventListener("input", function () {
+            clearTimeout(timer);
+            timer = setTimeout(render, 300);
+        });
+    });
+
+    render();
+})();